@@ -1,8 +1,15 @@
 param(
     [string] $Version = (Get-Content "$PSScriptRoot/../version.txt"),
-    [string] $SourceVersion = (git rev-parse HEAD)
+    [string] $SourceVersion = (git rev-parse HEAD),
+    # Base64 encoded ed25519 public key verifying the signature of releases, `azd upgrade` is disabled without it
+    [string] $SigningPublicKey = $env:AZD_SIGNING_PUBLIC_KEY
 )
 
+if (!$SigningPublicKey) {
+    Write-Host "No release signing public key set, azd upgrade will be disabled in this build"
+}
+
+
 # On Windows, use the goversioninfo tool to embed the version information into the executable.
 if ($IsWindows) {
     Write-Host "Windows build, set verison info and run 'go generate'"
@@ -47,7 +54,7 @@ if ($IsWindows) {
         -trimpath `
         -gcflags="-trimpath" `
         -asmflags="-trimpath" `
-        -ldflags="-s -w -X 'github.com/azure/azure-dev/cli/azd/internal.Version=$Version (commit $SourceVersion)' -linkmode=auto -extldflags=-Wl,--high-entropy-va"
+        -ldflags="-s -w -X 'github.com/azure/azure-dev/cli/azd/internal.Version=$Version (commit $SourceVersion)' -X 'github.com/azure/azure-dev/cli/azd/pkg/update.SigningPublicKey=$SigningPublicKey' -linkmode=auto -extldflags=-Wl,--high-entropy-va"
 }
 elseif ($IsLinux) {
     Write-Host "go build (linux)"
@@ -56,7 +63,7 @@ elseif ($IsLinux) {
         -tags="cfi,cfg,cfgo,osusergo" `
         -gcflags="-trimpath" `
         -asmflags="-trimpath" `
-        -ldflags="-s -w -X 'github.com/azure/azure-dev/cli/azd/internal.Version=$Version (commit $SourceVersion)' -extldflags=-Wl,--high-entropy-va"
+        -ldflags="-s -w -X 'github.com/azure/azure-dev/cli/azd/internal.Version=$Version (commit $SourceVersion)' -X 'github.com/azure/azure-dev/cli/azd/pkg/update.SigningPublicKey=$SigningPublicKey' -extldflags=-Wl,--high-entropy-va"
 }
 elseif ($IsMacOS) {
     Write-Host "go build (macos)"
@@ -65,7 +72,7 @@ elseif ($IsMacOS) {
         -tags="cfi,cfg,cfgo,osusergo" `
         -gcflags="-trimpath" `
         -asmflags="-trimpath" `
-        -ldflags="-s -w -X 'github.com/azure/azure-dev/cli/azd/internal.Version=$Version (commit $SourceVersion)' -linkmode=auto"
+        -ldflags="-s -w -X 'github.com/azure/azure-dev/cli/azd/internal.Version=$Version (commit $SourceVersion)' -X 'github.com/azure/azure-dev/cli/azd/pkg/update.SigningPublicKey=$SigningPublicKey' -linkmode=auto"
 }
 
 if ($LASTEXITCODE) {
@@ -4,6 +4,7 @@ package actions
 import (
 	"context"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 )
 
@@ -37,9 +38,13 @@ type ActionInitializer[T Action] func() (T, error)
 
 func ToUxItem(actionResult *ActionResult, err error) ux.UxItem {
 	if err != nil {
-		return &ux.ActionResult{
+		result := &ux.ActionResult{
 			Err: err,
 		}
+		if azsdk.CorrelationIdSent() {
+			result.CorrelationId = azsdk.CorrelationId()
+		}
+		return result
 	}
 
 	if actionResult == nil {
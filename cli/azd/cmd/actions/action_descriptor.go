@@ -182,6 +182,10 @@ type ActionDescriptorOptions struct {
 	DefaultFormat output.Format
 	// Whether or not telemetry should be disabled for the current action
 	DisableTelemetry bool
+	// Whether or not the user must be logged in to run the current action
+	RequireLogin bool
+	// Whether or not the current action must run within a project
+	RequireProject bool
 	// The logic that produces the command help
 	HelpOptions ActionHelpOptions
 	// Defines grouping options for the command
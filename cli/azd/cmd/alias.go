@@ -0,0 +1,237 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/alias"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func aliasActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("alias", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "alias",
+			Short: "Manage your command aliases.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdAliasHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	group.Add("list", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:     "list",
+			Short:   "List your command aliases.",
+			Aliases: []string{"ls"},
+		},
+		ActionResolver: newAliasListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	group.Add("set", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "set <name> <command>",
+			Short: "Create or update a command alias.",
+			Args:  cobra.ExactArgs(2),
+			Example: `$ azd alias set deploy-all "deploy --all --environment prod"
+$ azd alias set ship "provision && deploy --all"`,
+		},
+		ActionResolver: newAliasSetAction,
+	})
+
+	group.Add("unset", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:     "unset <name>",
+			Short:   "Remove a command alias.",
+			Example: `$ azd alias unset deploy-all`,
+			Args:    cobra.ExactArgs(1),
+		},
+		ActionResolver: newAliasUnsetAction,
+	})
+
+	return group
+}
+
+// ExpandAlias returns the azd commands to run for the arguments of the command line: the commands of the alias named by
+// the first argument, or the arguments unchanged when it isn't an alias. The commands of azd take precedence over the
+// aliases, an alias named like a command added by a newer version of azd is ignored. Invalid aliases in the user
+// configuration are ignored too, so they don't break the commands of azd.
+func ExpandAlias(args []string) ([][]string, error) {
+	if len(args) == 0 || isBuiltInCommand(NewRootCmd(false, nil), args[0]) {
+		return [][]string{args}, nil
+	}
+
+	commands, err := alias.NewManager(config.NewUserConfigManager()).Expand(args)
+	if errors.Is(err, alias.ErrInvalidConfig) {
+		log.Printf("ignoring aliases: %v", err)
+		return [][]string{args}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if commands == nil {
+		return [][]string{args}, nil
+	}
+
+	return commands, nil
+}
+
+// isBuiltInCommand reports whether name is the name or an alias of a command of azd.
+func isBuiltInCommand(root *cobra.Command, name string) bool {
+	// The help command is only added by cobra when the command runs
+	if name == "help" {
+		return true
+	}
+
+	for _, command := range root.Commands() {
+		if command.Name() == name || command.HasAlias(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type aliasListAction struct {
+	aliasManager *alias.Manager
+	formatter    output.Formatter
+	writer       io.Writer
+}
+
+func newAliasListAction(
+	aliasManager *alias.Manager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &aliasListAction{
+		aliasManager: aliasManager,
+		formatter:    formatter,
+		writer:       writer,
+	}
+}
+
+func (a *aliasListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	aliases, err := a.aliasManager.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing aliases: %w", err)
+	}
+
+	results := make([]contracts.AliasListAlias, len(aliases))
+	for i, alias := range aliases {
+		results[i] = contracts.AliasListAlias{
+			Name:    alias.Name,
+			Command: alias.Command,
+		}
+	}
+
+	if a.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "NAME",
+				ValueTemplate: "{{.Name}}",
+			},
+			{
+				Heading:       "COMMAND",
+				ValueTemplate: "{{.Command}}",
+			},
+		}
+
+		err = a.formatter.Format(results, a.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = a.formatter.Format(results, a.writer, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type aliasSetAction struct {
+	aliasManager *alias.Manager
+	cmd          *cobra.Command
+	args         []string
+}
+
+func newAliasSetAction(aliasManager *alias.Manager, cmd *cobra.Command, args []string) actions.Action {
+	return &aliasSetAction{
+		aliasManager: aliasManager,
+		cmd:          cmd,
+		args:         args,
+	}
+}
+
+func (a *aliasSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name := a.args[0]
+	if isBuiltInCommand(a.cmd.Root(), name) {
+		return nil, fmt.Errorf("'%s' is a command of azd, choose another name for the alias", name)
+	}
+
+	created, err := a.aliasManager.Set(name, a.args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Alias '%s' has been set", created.Name),
+			FollowUp: fmt.Sprintf("Run %s to run: azd %s", output.WithHighLightFormat("azd %s", created.Name), created.Command),
+		},
+	}, nil
+}
+
+type aliasUnsetAction struct {
+	aliasManager *alias.Manager
+	args         []string
+}
+
+func newAliasUnsetAction(aliasManager *alias.Manager, args []string) actions.Action {
+	return &aliasUnsetAction{
+		aliasManager: aliasManager,
+		args:         args,
+	}
+}
+
+func (a *aliasUnsetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name := a.args[0]
+	if err := a.aliasManager.Unset(name); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Alias '%s' has been removed", name),
+		},
+	}, nil
+}
+
+func getCmdAliasHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage your command aliases.",
+		[]string{
+			formatHelpNote("An alias runs an azd command, typed in place of the command, like " +
+				output.WithHighLightFormat("azd deploy-all") + ". The arguments following the alias are appended " +
+				"to the command."),
+			formatHelpNote("Commands separated by && run in order until one of them fails, like " +
+				output.WithHighLightFormat("provision && deploy --all") + "."),
+			formatHelpNote("The aliases are stored in your user configuration, the commands of azd take precedence " +
+				"over them."),
+		})
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExpandAlias(t *testing.T) {
+	writeUserConfig := func(t *testing.T, content string) {
+		configDir := t.TempDir()
+		t.Setenv("AZD_CONFIG_DIR", configDir)
+		err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(content), osutil.PermissionFile)
+		require.NoError(t, err)
+	}
+
+	t.Run("Alias", func(t *testing.T) {
+		writeUserConfig(t, `{"alias": {"ship": "provision && deploy --all"}}`)
+
+		commands, err := ExpandAlias([]string{"ship", "--no-prompt"})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"provision"}, {"deploy", "--all", "--no-prompt"}}, commands)
+	})
+
+	t.Run("BuiltInCommand", func(t *testing.T) {
+		writeUserConfig(t, `{"alias": {"deploy": "up"}}`)
+
+		commands, err := ExpandAlias([]string{"deploy", "--all"})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"deploy", "--all"}}, commands)
+	})
+
+	t.Run("MalformedAliases", func(t *testing.T) {
+		writeUserConfig(t, `{"alias": "provision && deploy --all"}`)
+
+		commands, err := ExpandAlias([]string{"deploy", "--all"})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"deploy", "--all"}}, commands)
+
+		commands, err = ExpandAlias([]string{"ship"})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"ship"}}, commands)
+	})
+}
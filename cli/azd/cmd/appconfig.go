@@ -0,0 +1,298 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/appconfig"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The directory of the per environment App Configuration files when the project doesn't configure one
+const defaultAppConfigPath = "appconfig"
+
+func appConfigActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("appconfig", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "appconfig",
+			Short: "Manage the Azure App Configuration settings and feature flags of an environment.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupManage,
+		},
+	})
+
+	group.Add("sync", &actions.ActionDescriptorOptions{
+		Command:        newAppConfigSyncCmd(),
+		FlagsResolver:  newAppConfigSyncFlags,
+		ActionResolver: newAppConfigSyncAction,
+		RequireLogin:   true,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdAppConfigSyncHelpDescription,
+			Footer:      getCmdAppConfigSyncHelpFooter,
+		},
+	})
+
+	return group
+}
+
+type appConfigSyncFlags struct {
+	preview       bool
+	deleteOrphans bool
+	force         bool
+	global        *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *appConfigSyncFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.preview, "preview", false, "Shows the changes without applying them.")
+	local.BoolVar(
+		&f.deleteOrphans,
+		"delete-orphans",
+		false,
+		"Deletes the settings and feature flags of the store which aren't in the environment file.",
+	)
+	local.BoolVar(&f.force, "force", false, "Deletes the settings and feature flags without confirmation.")
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newAppConfigSyncFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *appConfigSyncFlags {
+	flags := &appConfigSyncFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newAppConfigSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Sync the App Configuration settings and feature flags of the environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type appConfigSyncAction struct {
+	flags         *appConfigSyncFlags
+	projectConfig *project.ProjectConfig
+	env           *environment.Environment
+	syncer        *appconfig.Syncer
+	console       input.Console
+}
+
+func newAppConfigSyncAction(
+	flags *appConfigSyncFlags,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	syncer *appconfig.Syncer,
+	console input.Console,
+) actions.Action {
+	return &appConfigSyncAction{
+		flags:         flags,
+		projectConfig: projectConfig,
+		env:           env,
+		syncer:        syncer,
+		console:       console,
+	}
+}
+
+func (a *appConfigSyncAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Syncing App Configuration (azd appconfig sync)",
+		TitleNote: fmt.Sprintf(
+			"Syncing the settings and feature flags of environment %s",
+			output.WithHighLightFormat(a.env.GetEnvName()),
+		),
+	})
+
+	deleteOrphans := a.flags.deleteOrphans
+	if a.projectConfig.AppConfig != nil {
+		deleteOrphans = deleteOrphans || a.projectConfig.AppConfig.DeleteOrphans
+	}
+
+	changes, err := syncAppConfig(ctx, a.syncer, a.projectConfig, a.env, a.console, appconfig.SyncOptions{
+		Preview:       a.flags.preview,
+		DeleteOrphans: deleteOrphans,
+	}, a.flags.force)
+	if err != nil {
+		return nil, err
+	}
+
+	writeAppConfigChanges(a.console.Handles().Stdout, changes)
+
+	header := fmt.Sprintf("Applied %d changes to the App Configuration store.", len(changes))
+	if a.flags.preview {
+		header = fmt.Sprintf("%d changes would be applied to the App Configuration store.", len(changes))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: header,
+		},
+	}, nil
+}
+
+// syncAppConfig syncs the App Configuration file of the environment, like appconfig/dev.yaml, to the store of the
+// environment. Unless force is set, the user confirms the deletion of the key-values which aren't in the file, which
+// are kept when the user declines or can't be prompted.
+func syncAppConfig(
+	ctx context.Context,
+	syncer *appconfig.Syncer,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	console input.Console,
+	options appconfig.SyncOptions,
+	force bool,
+) ([]appconfig.Change, error) {
+	documentPath := appConfigDocumentPath(projectConfig, env)
+	document, err := appconfig.Load(documentPath, env.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := env.Getenv(appconfig.EndpointEnvVarName)
+	if projectConfig.AppConfig != nil && projectConfig.AppConfig.Endpoint != (project.ExpandableString{}) {
+		endpoint, err = projectConfig.AppConfig.Endpoint.Envsubst(env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding app configuration endpoint: %w", err)
+		}
+	}
+
+	if strings.TrimSpace(endpoint) == "" {
+		return nil, fmt.Errorf(
+			"the App Configuration endpoint is not set, set appConfig.endpoint in azure.yaml or %s in the environment",
+			appconfig.EndpointEnvVarName,
+		)
+	}
+
+	if options.DeleteOrphans && !options.Preview && !force {
+		confirmed, err := confirmAppConfigDeletes(ctx, syncer, env, console, endpoint, document)
+		if err != nil {
+			return nil, err
+		}
+
+		options.DeleteOrphans = confirmed
+	}
+
+	return syncer.Sync(ctx, env.GetSubscriptionId(), endpoint, document, options)
+}
+
+// confirmAppConfigDeletes lists the key-values a sync would delete and asks the user to confirm their deletion.
+func confirmAppConfigDeletes(
+	ctx context.Context,
+	syncer *appconfig.Syncer,
+	env *environment.Environment,
+	console input.Console,
+	endpoint string,
+	document *appconfig.Document,
+) (bool, error) {
+	preview, err := syncer.Sync(ctx, env.GetSubscriptionId(), endpoint, document, appconfig.SyncOptions{
+		Preview:       true,
+		DeleteOrphans: true,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var deletes []appconfig.Change
+	for _, change := range preview {
+		if change.Kind == appconfig.ChangeDelete {
+			deletes = append(deletes, change)
+		}
+	}
+
+	if len(deletes) == 0 {
+		return true, nil
+	}
+
+	console.Message(ctx, "These settings and feature flags aren't in the environment file and will be deleted:")
+	writeAppConfigChanges(console.Handles().Stdout, deletes)
+
+	confirmed, err := console.Confirm(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Delete %d settings and feature flags from the App Configuration store?", len(deletes)),
+		DefaultValue: false,
+	})
+	if err != nil {
+		return false, fmt.Errorf("prompting to delete app configuration settings: %w", err)
+	}
+
+	if !confirmed {
+		console.Message(ctx, fmt.Sprintf(
+			"Keeping the settings and feature flags, run %s to delete them without confirmation.",
+			output.WithHighLightFormat("azd appconfig sync --delete-orphans --force")))
+	}
+
+	return confirmed, nil
+}
+
+// appConfigDocumentPath returns the path of the App Configuration file of the environment
+func appConfigDocumentPath(projectConfig *project.ProjectConfig, env *environment.Environment) string {
+	path := defaultAppConfigPath
+	if projectConfig.AppConfig != nil && projectConfig.AppConfig.Path != "" {
+		path = projectConfig.AppConfig.Path
+	}
+
+	return filepath.Join(projectConfig.Path, path, env.GetEnvName()+".yaml")
+}
+
+func writeAppConfigChanges(writer io.Writer, changes []appconfig.Change) {
+	for _, change := range changes {
+		line := change.String()
+		switch change.Kind {
+		case appconfig.ChangeAdd:
+			line = output.WithSuccessFormat(line)
+		case appconfig.ChangeDelete:
+			line = output.WithErrorFormat(line)
+		default:
+			line = output.WithWarningFormat(line)
+		}
+
+		fmt.Fprintf(writer, "  %s\n", line)
+	}
+
+	if len(changes) > 0 {
+		fmt.Fprintln(writer)
+	}
+}
+
+func getCmdAppConfigSyncHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Sync the Azure App Configuration settings and feature flags of the environment.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("The settings and feature flags are read from %s, relative to the project.",
+				output.WithHighLightFormat("appconfig/<environment>.yaml"))),
+			formatHelpNote(fmt.Sprintf("The store is %s, or the appConfig.endpoint of azure.yaml.",
+				output.WithHighLightFormat(appconfig.EndpointEnvVarName))),
+			formatHelpNote("When appConfig is set in azure.yaml, the environment is synced after provisioning."),
+			formatHelpNote(fmt.Sprintf("Deleting the settings which aren't in the environment file is confirmed "+
+				"first, unless %s is set. Values of settings are never displayed.",
+				output.WithHighLightFormat("--force"))),
+		},
+	)
+}
+
+func getCmdAppConfigSyncHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Preview the changes to the App Configuration store.": output.WithHighLightFormat(
+			"azd appconfig sync --preview",
+		),
+		"Sync the environment and delete the settings which aren't in the environment file.": output.WithHighLightFormat(
+			"azd appconfig sync --delete-orphans",
+		),
+	})
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -360,7 +361,8 @@ func (la *loginAction) login(ctx context.Context) error {
 		case la.flags.clientSecret.ptr != nil:
 			if *la.flags.clientSecret.ptr == "" {
 				v, err := la.console.Prompt(ctx, input.ConsoleOptions{
-					Message: "Enter your client secret",
+					Message:    "Enter your client secret",
+					IsPassword: true,
 				})
 				if err != nil {
 					return fmt.Errorf("prompting for client secret: %w", err)
@@ -434,13 +436,17 @@ func parseUseDeviceCode(ctx context.Context, flag boolPtr, commandRunner exec.Co
 
 	// Detect cases where the browser isn't available for interactive auth, and we instead want to set `useDeviceCode`
 	// to be true by default
-	inCodespacesEnv := os.Getenv("CODESPACES") == "true"
-	if inCodespacesEnv {
+	switch devcontainer.Detect(os.Getenv) {
+	case devcontainer.KindCodespaces:
 		// For VSCode online (in web Browser), like GitHub Codespaces or VSCode online attached to any server,
 		// interactive browser login will 404 when attempting to redirect to localhost
 		// (since azd launches a localhost server running remotely and the login response is accepted locally).
 		// Hence, we override login to device-code. See https://github.com/Azure/azure-dev/issues/1006
 		useDevCode = runningOnCodespacesBrowser(ctx, commandRunner)
+	case devcontainer.KindDevContainer:
+		// The localhost redirect of the browser login only works when the port azd listens on is forwarded from the
+		// container, which depends on the dev container tooling. Device code works in every dev container.
+		useDevCode = true
 	}
 
 	return useDevCode, nil
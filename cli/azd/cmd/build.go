@@ -9,10 +9,13 @@ import (
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -54,6 +57,7 @@ func newBuildCmd() *cobra.Command {
 		Hidden: true,
 	}
 	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.ValidArgsFunction = serviceNameCompletion
 	return cmd
 }
 
@@ -68,6 +72,8 @@ type buildAction struct {
 	writer                   io.Writer
 	middlewareRunner         middleware.MiddlewareContext
 	restoreActionInitializer actions.ActionInitializer[*restoreAction]
+	progressBus              *progress.Bus
+	env                      *environment.Environment
 }
 
 func newBuildAction(
@@ -81,7 +87,8 @@ func newBuildAction(
 	writer io.Writer,
 	middlewareRunner middleware.MiddlewareContext,
 	restoreActionInitializer actions.ActionInitializer[*restoreAction],
-
+	progressBus *progress.Bus,
+	env *environment.Environment,
 ) actions.Action {
 	return &buildAction{
 		flags:                    flags,
@@ -94,6 +101,8 @@ func newBuildAction(
 		writer:                   writer,
 		middlewareRunner:         middlewareRunner,
 		restoreActionInitializer: restoreActionInitializer,
+		progressBus:              progressBus,
+		env:                      env,
 	}
 }
 
@@ -120,7 +129,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	// Command title
 	ba.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title: "Building services (azd build)",
+		Title: locale.Sprintf(locale.BuildTitle),
 	})
 
 	targetServiceName := ""
@@ -141,7 +150,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}
 
 	if err := ba.projectManager.EnsureFrameworkTools(ctx, ba.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return (targetServiceName == "" || svc.Name == targetServiceName) && svc.EnabledIn(ba.env.GetEnvName())
 	}); err != nil {
 		return nil, err
 	}
@@ -153,32 +162,37 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	buildResults := map[string]*project.ServiceBuildResult{}
 
 	for _, svc := range ba.projectConfig.GetServicesStable() {
-		stepMessage := fmt.Sprintf("Building service %s", svc.Name)
-		ba.console.ShowSpinner(ctx, stepMessage, input.Step)
+		step := ba.progressBus.Step(
+			string(project.ServiceEventBuild), svc.Name, locale.Sprintf(locale.BuildStep, svc.Name))
+		step.Start(ctx)
 
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
 		// 2. This service is not the one the user specified
 		if targetServiceName != "" && targetServiceName != svc.Name {
-			ba.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			step.Skip(ctx)
+			continue
+		}
+
+		if !svc.EnabledIn(ba.env.GetEnvName()) {
+			step.SkipWithReason(ctx, locale.Sprintf(locale.ServiceDisabled, ba.env.GetEnvName()))
 			continue
 		}
 
 		buildTask := ba.serviceManager.Build(ctx, svc, nil)
 		go func() {
 			for buildProgress := range buildTask.Progress() {
-				progressMessage := fmt.Sprintf("Building service %s (%s)", svc.Name, buildProgress.Message)
-				ba.console.ShowSpinner(ctx, progressMessage, input.Step)
+				step.Progress(ctx, buildProgress.Message)
 			}
 		}()
 
 		buildResult, err := buildTask.Await()
 		if err != nil {
-			ba.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			step.Fail(ctx, err)
 			return nil, err
 		}
 
-		ba.console.StopSpinner(ctx, stepMessage, input.StepDone)
+		step.Complete(ctx)
 		buildResults[svc.Name] = buildResult
 
 		// report build outputs
@@ -198,7 +212,7 @@ func (ba *buildAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: "Your Azure app has been built!",
+			Header: locale.Sprintf(locale.BuildSuccess),
 		},
 	}, nil
 }
@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func cacheActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("cache", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "cache",
+			Short: "Manage the artifacts azd creates, like deployment packages and build outputs.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdCacheHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
+	group.Add("clean", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "clean",
+			Short: "Remove the expired artifacts, or all the artifacts with --all.",
+		},
+		FlagsResolver:  newCacheCleanFlags,
+		ActionResolver: newCacheCleanAction,
+	})
+
+	return group
+}
+
+type cacheCleanFlags struct {
+	all    bool
+	global *internal.GlobalCommandOptions
+}
+
+func (f *cacheCleanFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.all, "all", false, "Removes all the artifacts, instead of the expired artifacts.")
+	f.global = global
+}
+
+func newCacheCleanFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *cacheCleanFlags {
+	flags := &cacheCleanFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type cacheCleanAction struct {
+	flags             *cacheCleanFlags
+	userConfigManager config.UserConfigManager
+}
+
+func newCacheCleanAction(flags *cacheCleanFlags, userConfigManager config.UserConfigManager) actions.Action {
+	return &cacheCleanAction{
+		flags:             flags,
+		userConfigManager: userConfigManager,
+	}
+}
+
+func (c *cacheCleanAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	cleanConfig := artifacts.Config{}
+	if !c.flags.all {
+		userConfig, err := c.userConfigManager.Load()
+		if err != nil {
+			return nil, fmt.Errorf("loading user configuration: %w", err)
+		}
+
+		cleanConfig, err = artifacts.LoadConfig(userConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := artifacts.Dir()
+	if err != nil {
+		return nil, fmt.Errorf("getting artifacts directory: %w", err)
+	}
+
+	// With --all, the artifacts created up to now are all past the retention of the empty configuration
+	removed, err := artifacts.Clean(dir, cleanConfig, time.Now().Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	var size int64
+	for _, artifact := range removed {
+		size += artifact.Size
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Removed %d artifacts (%.1f MB) from %s", len(removed), float64(size)/(1024*1024), dir),
+		},
+	}, nil
+}
+
+func getCmdCacheHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage the artifacts azd creates, like deployment packages and build outputs.",
+		[]string{
+			formatHelpNote("Artifacts are kept in the artifacts folder of the azd configuration directory after the " +
+				"command which created them, so they can be inspected or reused."),
+			formatHelpNote(fmt.Sprintf("After each command, the artifacts older than %s (default: %d) are removed, "+
+				"then the oldest artifacts until they fit in %s (default: %d).",
+				output.WithHighLightFormat(artifacts.RetentionConfigPath),
+				int(artifacts.DefaultRetention.Hours()),
+				output.WithHighLightFormat(artifacts.MaxSizeConfigPath),
+				artifacts.DefaultMaxSize/(1024*1024))),
+		})
+}
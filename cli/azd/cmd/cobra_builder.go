@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -36,6 +38,14 @@ func (cb *CobraBuilder) BuildCommand(descriptor *actions.ActionDescriptor) (*cob
 		cmd.Use = descriptor.Name
 	}
 
+	// Invalid arguments are reported as validation failures
+	if cmd.Args != nil {
+		validateArgs := cmd.Args
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return exitcode.New(exitcode.CategoryValidation, validateArgs(cmd, args))
+		}
+	}
+
 	// Build the full command tree
 	for _, childDescriptor := range descriptor.Children() {
 		childCmd, err := cb.BuildCommand(childDescriptor)
@@ -107,18 +117,12 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 		}
 
 		actionName := createActionName(cmd)
-		var action actions.Action
-		if err := cb.container.ResolveNamed(actionName, &action); err != nil {
-			if errors.Is(err, ioc.ErrResolveInstance) {
-				return fmt.Errorf(
-					//nolint:lll
-					"failed resolving action '%s'. Ensure the ActionResolver is a valid go function that returns an `actions.Action` interface, %w",
-					actionName,
-					err,
-				)
-			}
 
-			return err
+		// The action is resolved when the middleware chain runs it, after the middleware guarding the action, like the
+		// login and project checks, so that the action dependencies are only resolved when the checks succeed.
+		action := &resolvingAction{
+			container:  cb.container,
+			actionName: actionName,
 		}
 
 		runOptions := &middleware.Options{
@@ -130,7 +134,7 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 		}
 
 		// Run the middleware chain with action
-		log.Printf("Resolved action '%s'\n", actionName)
+		log.Printf("Running action '%s'\n", actionName)
 		actionResult, err := cb.runner.RunAction(ctx, runOptions, action)
 
 		// At this point, we know that there might be an error, so we can silence cobra from showing it after us.
@@ -155,6 +159,30 @@ func (cb *CobraBuilder) configureActionResolver(cmd *cobra.Command, descriptor *
 	return nil
 }
 
+// resolvingAction resolves the named action from the container when it runs
+type resolvingAction struct {
+	container  *ioc.NestedContainer
+	actionName string
+}
+
+func (a *resolvingAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	var action actions.Action
+	if err := a.container.ResolveNamed(a.actionName, &action); err != nil {
+		if errors.Is(err, ioc.ErrResolveInstance) {
+			return nil, fmt.Errorf(
+				//nolint:lll
+				"failed resolving action '%s'. Ensure the ActionResolver is a valid go function that returns an `actions.Action` interface, %w",
+				a.actionName,
+				err,
+			)
+		}
+
+		return nil, err
+	}
+
+	return action.Run(ctx)
+}
+
 // Binds the intersection of cobra command options and action descriptor options
 func (cb *CobraBuilder) bindCommand(cmd *cobra.Command, descriptor *actions.ActionDescriptor) error {
 	actionName := createActionName(cmd)
@@ -162,6 +190,13 @@ func (cb *CobraBuilder) bindCommand(cmd *cobra.Command, descriptor *actions.Acti
 	// Automatically adds a consistent help flag
 	cmd.Flags().BoolP("help", "h", false, fmt.Sprintf("Gets help for %s.", cmd.Name()))
 
+	// Invalid flags are reported as validation failures, the flag error func is inherited by all nested commands
+	if descriptor.Parent() == nil {
+		cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+			return exitcode.New(exitcode.CategoryValidation, err)
+		})
+	}
+
 	// Consistently registers output formats for the descriptor
 	if len(descriptor.Options.OutputFormats) > 0 {
 		output.AddOutputParam(cmd, descriptor.Options.OutputFormats, descriptor.Options.DefaultFormat)
@@ -96,6 +96,35 @@ func Test_BuildAndRunSimpleActionWithMiddleware(t *testing.T) {
 	require.True(t, middlewareRan)
 }
 
+func Test_BuildAndRunActionResolvedAfterMiddleware(t *testing.T) {
+	container := ioc.NewNestedContainer(nil)
+	setup(container)
+
+	resolved := false
+	root := actions.NewActionDescriptor("root", &actions.ActionDescriptorOptions{
+		ActionResolver: func(flags *testFlags) actions.Action {
+			resolved = true
+			return newTestAction(flags)
+		},
+		FlagsResolver: newTestFlags,
+	}).UseMiddleware("guard", func() middleware.Middleware {
+		return &testGuardMiddleware{}
+	})
+
+	builder := NewCobraBuilder(container)
+	cmd, err := builder.BuildCommand(root)
+
+	require.NotNil(t, cmd)
+	require.NoError(t, err)
+
+	cmd.SetArgs([]string{"-r"})
+	err = cmd.ExecuteContext(context.Background())
+
+	// The action isn't resolved when a middleware fails before running it
+	require.ErrorIs(t, err, errGuard)
+	require.False(t, resolved)
+}
+
 func Test_BuildAndRunActionWithNestedMiddleware(t *testing.T) {
 	container := ioc.NewNestedContainer(nil)
 	setup(container)
@@ -291,3 +320,12 @@ func (m *testMiddlewareB) Run(ctx context.Context, nextFn middleware.NextFn) (*a
 
 	return nextFn(ctx)
 }
+
+var errGuard = errors.New("guard failed")
+
+type testGuardMiddleware struct {
+}
+
+func (m *testGuardMiddleware) Run(ctx context.Context, nextFn middleware.NextFn) (*actions.ActionResult, error) {
+	return nil, errGuard
+}
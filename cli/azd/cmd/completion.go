@@ -0,0 +1,223 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	completionShellBash       = "bash"
+	completionShellZsh        = "zsh"
+	completionShellFish       = "fish"
+	completionShellPowerShell = "powershell"
+)
+
+func completionActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("completion", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "completion",
+			Short: "Generate shell completion scripts.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdCompletionHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
+	shells := []struct {
+		name  string
+		short string
+	}{
+		{completionShellBash, "Generate bash completion script."},
+		{completionShellFish, "Generate fish completion script."},
+		{completionShellPowerShell, "Generate PowerShell completion script."},
+		{completionShellZsh, "Generate zsh completion script."},
+	}
+
+	for _, shell := range shells {
+		group.Add(shell.name, &actions.ActionDescriptorOptions{
+			Command: &cobra.Command{
+				Use:   shell.name,
+				Short: shell.short,
+				Args:  cobra.NoArgs,
+			},
+			ActionResolver:   newCompletionAction,
+			DisableTelemetry: true,
+		})
+	}
+
+	return group
+}
+
+type completionAction struct {
+	cmd *cobra.Command
+}
+
+func newCompletionAction(cmd *cobra.Command) actions.Action {
+	return &completionAction{
+		cmd: cmd,
+	}
+}
+
+// Run writes the completion script for the shell matching the name of the executing command.
+func (a *completionAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	root := a.cmd.Root()
+	writer := a.cmd.OutOrStdout()
+
+	var err error
+	switch a.cmd.Name() {
+	case completionShellBash:
+		err = root.GenBashCompletionV2(writer, true)
+	case completionShellZsh:
+		err = root.GenZshCompletion(writer)
+	case completionShellFish:
+		err = root.GenFishCompletion(writer, true)
+	case completionShellPowerShell:
+		err = root.GenPowerShellCompletionWithDesc(writer)
+	default:
+		return nil, fmt.Errorf("unsupported shell '%s'", a.cmd.Name())
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("generating %s completion script: %w", a.cmd.Name(), err)
+	}
+
+	return nil, nil
+}
+
+// registerDynamicCompletions walks the command tree and registers completion functions for flags that are shared
+// across many commands, such as the environment name flag.
+func registerDynamicCompletions(cmd *cobra.Command) {
+	if cmd.Flags().Lookup(environmentNameFlag) != nil {
+		// Commands may have already registered a more specific completion for the flag, which is left untouched.
+		if err := cmd.RegisterFlagCompletionFunc(environmentNameFlag, environmentNameCompletion); err != nil {
+			log.Printf("skipping environment completion for '%s': %v", cmd.CommandPath(), err)
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		registerDynamicCompletions(child)
+	}
+}
+
+// environmentNameCompletion completes the names of the environments available in the current project.
+func environmentNameCompletion(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	azdCtx, err := azdcontext.NewAzdContext()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	envs, err := azdCtx.ListEnvironments()
+	if err != nil {
+		cobra.CompError(fmt.Sprintf("Error listing environments: %s", err))
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	envNames := make([]string, 0, len(envs))
+	for _, env := range envs {
+		if env.IsDefault {
+			envNames = append(envNames, fmt.Sprintf("%s\tdefault", env.Name))
+		} else {
+			envNames = append(envNames, env.Name)
+		}
+	}
+
+	return envNames, cobra.ShellCompDirectiveNoFileComp
+}
+
+// environmentNameArgCompletion completes the first positional argument with an environment name.
+func environmentNameArgCompletion(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return environmentNameCompletion(cmd, args, toComplete)
+}
+
+// serviceNameCompletion completes the first positional argument with a service name from azure.yaml.
+func serviceNameCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	azdCtx, err := azdcontext.NewAzdContext()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	projectConfig, err := project.Load(cmd.Context(), azdCtx.ProjectPath())
+	if err != nil {
+		cobra.CompError(fmt.Sprintf("Error loading project: %s", err))
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	serviceNames := maps.Keys(projectConfig.Services)
+	slices.Sort(serviceNames)
+
+	return serviceNames, cobra.ShellCompDirectiveNoFileComp
+}
+
+// subscriptionCompletion completes subscription IDs from the locally cached subscriptions of the logged in account.
+// The cache is used instead of querying Azure to keep completion responsive.
+func subscriptionCompletion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cache, err := account.NewSubscriptionsCache()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	subscriptions, err := cache.Load()
+	if err != nil {
+		// The cache is not populated until the user logs in and lists subscriptions
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	slices.SortFunc(subscriptions, func(a, b account.Subscription) bool {
+		return a.Name < b.Name
+	})
+
+	completions := make([]string, len(subscriptions))
+	for i, sub := range subscriptions {
+		completions[i] = fmt.Sprintf("%s\t%s", sub.Id, sub.Name)
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func getCmdCompletionHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Generate shell completion scripts for azd. The generated script must be loaded by your shell to enable"+
+			" completion of commands, flags, environment names, service names, templates and subscriptions.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Bash: %s",
+				output.WithHighLightFormat("source <(azd completion bash)"))),
+			formatHelpNote(fmt.Sprintf("Zsh: %s",
+				output.WithHighLightFormat("source <(azd completion zsh)"))),
+			formatHelpNote(fmt.Sprintf("Fish: %s",
+				output.WithHighLightFormat("azd completion fish | source"))),
+			formatHelpNote(fmt.Sprintf("PowerShell: %s",
+				output.WithHighLightFormat("azd completion powershell | Out-String | Invoke-Expression"))),
+		})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
 
@@ -11,19 +12,25 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/repository"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/alias"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/appconfig"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/commands/pipeline"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	infraBicep "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/secrets"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
@@ -31,13 +38,19 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/trivy"
+	"github.com/azure/azure-dev/cli/azd/pkg/update"
+	"github.com/azure/azure-dev/cli/azd/pkg/workspace"
 	"github.com/benbjohnson/clock"
 	"github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
@@ -90,13 +103,27 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		rootOptions *internal.GlobalCommandOptions,
 		formatter output.Formatter,
 		cmd *cobra.Command) input.Console {
+		handles := input.ConsoleHandles{
+			Stdin:  cmd.InOrStdin(),
+			Stdout: cmd.OutOrStdout(),
+			Stderr: cmd.ErrOrStderr(),
+		}
+
+		if rootOptions.Machine {
+			// The messages are read by a client rendering them in its own UI
+			output.DisableColors()
+			return input.NewMachineConsole(rootOptions.NoPrompt, handles, formatter)
+		}
+
 		writer := cmd.OutOrStdout()
 		// When using JSON formatting, we want to ensure we always write messages from the console to stderr.
 		if formatter != nil && formatter.Kind() == output.JsonFormat {
 			writer = cmd.ErrOrStderr()
 		}
 
-		if os.Getenv("NO_COLOR") != "" {
+		plain := rootOptions.Plain || output.IsPlainRequested(os.Getenv)
+		if plain {
+			output.DisableColors()
 			writer = colorable.NewNonColorable(writer)
 		}
 
@@ -104,22 +131,55 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			cmd.InOrStdin() == os.Stdin && isatty.IsTerminal(os.Stdin.Fd()) &&
 			isatty.IsTerminal(os.Stdout.Fd())
 
-		return input.NewConsole(rootOptions.NoPrompt, isTerminal, writer, input.ConsoleHandles{
-			Stdin:  cmd.InOrStdin(),
-			Stdout: cmd.OutOrStdout(),
-			Stderr: cmd.ErrOrStderr(),
-		}, formatter)
+		return input.NewConsole(rootOptions.NoPrompt, isTerminal, plain, writer, handles, formatter)
 	})
 
-	container.RegisterSingleton(func(console input.Console) exec.CommandRunner {
-		return exec.NewCommandRunner(
+	container.RegisterSingleton(func(
+		console input.Console,
+		userConfigManager config.UserConfigManager,
+	) exec.CommandRunner {
+		// The shell set in the user configuration overrides the shells probed on systems without /bin/sh
+		var posixShell string
+		if userConfig, err := userConfigManager.Load(); err != nil {
+			log.Printf("failed loading user configuration, probing the POSIX shell: %v", err)
+		} else if value, has := userConfig.Get(exec.PosixShellConfigPath); has {
+			posixShell, _ = value.(string)
+		}
+
+		return exec.NewCommandRunnerWithShell(
 			console.Handles().Stdin,
 			console.Handles().Stdout,
 			console.Handles().Stderr,
+			posixShell,
 		)
 	})
 	container.RegisterSingleton(input.NewConsoleMessaging)
 
+	// Progress events published by long running operations are rendered by the sink selected with AZD_PROGRESS,
+	// defaulting to timestamped log lines in plain mode and to the interactive console spinner otherwise. With
+	// --machine, they are written as progress notifications.
+	container.RegisterSingleton(func(rootOptions *internal.GlobalCommandOptions, console input.Console) *progress.Bus {
+		// The --machine protocol includes the progress events, whatever the mode
+		if machineConsole, ok := console.(*input.MachineConsole); ok {
+			return progress.NewBus(progress.SinkFunc(func(ctx context.Context, event progress.Event) {
+				machineConsole.Notify(ctx, input.MachineMethodProgress, event)
+			}))
+		}
+
+		mode, err := progress.ParseMode(os.Getenv(progress.ModeEnvVarName))
+		if err != nil {
+			log.Printf("%v, defaulting to '%s'", err, progress.ModeInteractive)
+			mode = progress.ModeInteractive
+		}
+
+		if mode == progress.ModeInteractive && os.Getenv(progress.ModeEnvVarName) == "" &&
+			(rootOptions.Plain || output.IsPlainRequested(os.Getenv)) {
+			mode = progress.ModePlain
+		}
+
+		return progress.NewBus(progress.NewSink(mode, console))
+	})
+
 	container.RegisterSingleton(func() httputil.HttpClient { return &http.Client{} })
 
 	// Auth
@@ -152,13 +212,22 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		return cmd.Annotations
 	})
 
-	// Azd Context
-	container.RegisterSingleton(azdcontext.NewAzdContext)
+	// Azd Context, resolved from the current directory or the active project of the workspace
+	container.RegisterSingleton(newWorkspaceContextResolver)
+	container.RegisterSingleton(func(
+		ctx context.Context,
+		resolver *workspaceContextResolver,
+	) (*azdcontext.AzdContext, error) {
+		return resolver.Resolve(ctx)
+	})
 
 	// Lazy loads the Azd context after the azure.yaml file becomes available
-	container.RegisterSingleton(func() *lazy.Lazy[*azdcontext.AzdContext] {
+	container.RegisterSingleton(func(
+		ctx context.Context,
+		resolver *workspaceContextResolver,
+	) *lazy.Lazy[*azdcontext.AzdContext] {
 		return lazy.NewLazy(func() (*azdcontext.AzdContext, error) {
-			return azdcontext.NewAzdContext()
+			return resolver.Resolve(ctx)
 		})
 	})
 
@@ -171,6 +240,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			lazyEnv *lazy.Lazy[*environment.Environment],
 			envFlags envFlag,
 			console input.Console,
+			lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
+			secretsResolver *secrets.Resolver,
+			formatter output.Formatter,
 		) (*environment.Environment, error) {
 			if azdContext == nil {
 				return nil, azdcontext.ErrNoProject
@@ -184,6 +256,20 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 				return nil, fmt.Errorf("loading environment: %w", err)
 			}
 
+			if projectConfig, err := lazyProjectConfig.GetValue(); err == nil {
+				setEnvironmentSecrets(ctx, env, projectConfig, secretsResolver)
+
+				if err := projectConfig.ResolveEnvReferences(env); err != nil {
+					return nil, err
+				}
+			}
+
+			// Show which environment the command operates on, since it may come from the default environment,
+			// the --environment flag or AZURE_ENV_NAME
+			if formatter == nil || formatter.Kind() == output.NoneFormat {
+				console.Message(ctx, output.WithGrayFormat("Environment: %s", env.GetEnvName()))
+			}
+
 			// Reset lazy env value after loading or creating environment
 			// This allows any previous lazy instances (such as hooks) to now point to the same instance
 			lazyEnv.SetValue(env)
@@ -198,7 +284,13 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	// Lazy loads an existing environment, erroring out if not available
 	// One can repeatedly call GetValue to wait until the environment is available.
 	container.RegisterSingleton(
-		func(lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext], envFlags envFlag) *lazy.Lazy[*environment.Environment] {
+		func(
+			ctx context.Context,
+			lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext],
+			envFlags envFlag,
+			lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
+			secretsResolver *secrets.Resolver,
+		) *lazy.Lazy[*environment.Environment] {
 			return lazy.NewLazy(func() (*environment.Environment, error) {
 				azdCtx, err := lazyAzdContext.GetValue()
 				if err != nil {
@@ -218,6 +310,10 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 					return nil, err
 				}
 
+				if projectConfig, err := lazyProjectConfig.GetValue(); err == nil {
+					setEnvironmentSecrets(ctx, env, projectConfig, secretsResolver)
+				}
+
 				return env, err
 			})
 		},
@@ -254,11 +350,26 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		})
 	})
 
+	// The pipeline manager is configured by the flags of azd pipeline config
+	container.RegisterSingleton(func(flags *pipelineConfigFlags) *pipeline.PipelineManagerArgs {
+		return &flags.PipelineManagerArgs
+	})
+	container.RegisterSingleton(pipeline.NewPipelineManager)
+
 	container.RegisterSingleton(project.NewResourceManager)
+	container.RegisterSingleton(project.NewIdentityManager)
+	container.RegisterSingleton(project.NewServiceConnector)
 	container.RegisterSingleton(project.NewProjectManager)
 	container.RegisterSingleton(project.NewServiceManager)
 	container.RegisterSingleton(repository.NewInitializer)
 	container.RegisterSingleton(config.NewUserConfigManager)
+	container.RegisterSingleton(workspace.NewManager)
+	container.RegisterSingleton(alias.NewManager)
+	container.RegisterSingleton(update.NewManager)
+	container.RegisterSingleton(secrets.NewResolver)
+	container.RegisterSingleton(func(azCli azcli.AzCli) secrets.KeyVaultSecrets {
+		return azCli
+	})
 	container.RegisterSingleton(alpha.NewFeaturesManager)
 	container.RegisterSingleton(config.NewManager)
 	container.RegisterSingleton(templates.NewTemplateManager)
@@ -269,10 +380,16 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.RegisterSingleton(account.NewSubscriptionsManager)
 	container.RegisterSingleton(account.NewSubscriptionCredentialProvider)
 	container.RegisterSingleton(azcli.NewManagedClustersService)
+	container.RegisterSingleton(azcli.NewManagedIdentitiesService)
 	container.RegisterSingleton(azcli.NewContainerRegistryService)
 	container.RegisterSingleton(containerapps.NewContainerAppService)
 	container.RegisterSingleton(project.NewContainerHelper)
+	container.RegisterSingleton(project.NewDevRunner)
+	container.RegisterSingleton(project.NewTestRunner)
 	container.RegisterSingleton(azcli.NewSpringService)
+	container.RegisterSingleton(azcli.NewApiManagementService)
+	container.RegisterSingleton(azcli.NewIotHubService)
+	container.RegisterSingleton(azcli.NewDataPlatformService)
 	container.RegisterSingleton(func() ioc.ServiceLocator {
 		return ioc.NewServiceLocator(container)
 	})
@@ -293,17 +410,23 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		})
 	})
 	container.RegisterSingleton(bicep.NewBicepCli)
+	container.RegisterSingleton(infraBicep.NewParametersSync)
+	container.RegisterSingleton(appconfig.NewSyncer)
 	container.RegisterSingleton(docker.NewDocker)
 	container.RegisterSingleton(dotnet.NewDotNetCli)
 	container.RegisterSingleton(git.NewGitCli)
 	container.RegisterSingleton(github.NewGitHubCli)
+	container.RegisterSingleton(golang.NewGoCli)
+	container.RegisterSingleton(gradle.NewGradleCli)
 	container.RegisterSingleton(javac.NewCli)
 	container.RegisterSingleton(kubectl.NewKubectl)
 	container.RegisterSingleton(maven.NewMavenCli)
 	container.RegisterSingleton(npm.NewNpmCli)
+	container.RegisterSingleton(oras.NewOras)
 	container.RegisterSingleton(python.NewPythonCli)
 	container.RegisterSingleton(swa.NewSwaCli)
 	container.RegisterSingleton(terraform.NewTerraformCli)
+	container.RegisterSingleton(trivy.NewTrivy)
 
 	// Other
 	container.RegisterSingleton(clock.New)
@@ -317,8 +440,14 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.StaticWebAppTarget:  project.NewStaticWebAppTarget,
 		project.AksTarget:           project.NewAksTarget,
 		project.SpringAppTarget:     project.NewSpringAppTarget,
+		project.WebhookTarget:       project.NewWebhookTarget,
+		project.ApiManagementTarget: project.NewApimTarget,
+		project.IotEdgeTarget:       project.NewIotEdgeTarget,
+		project.DataPlatformTarget:  project.NewDataPlatformTarget,
 	}
 
+	container.RegisterSingleton(project.NewServiceTargetPluginManager)
+
 	for target, constructor := range serviceTargetMap {
 		if err := container.RegisterNamedSingleton(string(target), constructor); err != nil {
 			panic(fmt.Errorf("registering service target %s: %w", target, err))
@@ -334,7 +463,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.ServiceLanguagePython:     project.NewPythonProject,
 		project.ServiceLanguageJavaScript: project.NewNpmProject,
 		project.ServiceLanguageTypeScript: project.NewNpmProject,
-		project.ServiceLanguageJava:       project.NewMavenProject,
+		project.ServiceLanguageJava:       project.NewJavaProject,
+		project.ServiceLanguageGo:         project.NewGoProject,
 		project.ServiceLanguageDocker:     project.NewDockerProject,
 	}
 
@@ -355,3 +485,66 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	registerAction[*provisionAction](container, "azd-provision-action")
 	registerAction[*downAction](container, "azd-down-action")
 }
+
+// setEnvironmentSecrets sources the values mapped to external secret stores in azure.yaml, and in the config of the
+// environment, like the Key Vault secrets set by `azd env set-secret`. The secret stores are only called when a command
+// reads one of the values.
+func setEnvironmentSecrets(
+	ctx context.Context,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	secretsResolver *secrets.Resolver,
+) {
+	references := map[string]*secrets.Reference{}
+	for key, reference := range projectConfig.Secrets {
+		references[key] = reference
+	}
+
+	// The references of the environment take precedence over the references of the project
+	for key, reference := range environmentSecretReferences(env) {
+		references[key] = reference
+	}
+
+	if len(references) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(references))
+	for key := range references {
+		keys = append(keys, key)
+	}
+
+	env.SetSecrets(keys, func() (map[string]string, error) {
+		return secretsResolver.ForSubscription(env.GetSubscriptionId()).Resolve(ctx, references)
+	})
+}
+
+// environmentSecretReferences returns the references to secrets saved in the config of the environment.
+func environmentSecretReferences(env *environment.Environment) map[string]*secrets.Reference {
+	references := map[string]*secrets.Reference{}
+
+	value, has := env.Config.Get(environment.SecretsConfigPath)
+	if !has {
+		return references
+	}
+
+	entries, ok := value.(map[string]any)
+	if !ok {
+		log.Printf("ignoring %s of environment config, it isn't an object", environment.SecretsConfigPath)
+		return references
+	}
+
+	for key, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			log.Printf("ignoring secret '%s' of environment config, it isn't an object", key)
+			continue
+		}
+
+		provider, _ := fields["provider"].(string)
+		ref, _ := fields["ref"].(string)
+		references[key] = &secrets.Reference{Provider: secrets.ProviderKind(provider), Ref: ref}
+	}
+
+	return references
+}
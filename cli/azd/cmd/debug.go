@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/logging"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+)
+
+func debugActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("debug", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "debug",
+			Short: "Inspect the logs of previous azd commands.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
+	logGroup := group.Add("log", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "log",
+			Short: "Inspect the log files written by azd.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDebugLogHelpDescription,
+		},
+	})
+
+	logGroup.Add("show", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "show",
+			Short: "Print the log of the previous azd command.",
+		},
+		ActionResolver: newDebugLogShowAction,
+	})
+
+	logGroup.Add("path", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "path",
+			Short: "Print the path of the log file of the previous azd command.",
+		},
+		ActionResolver: newDebugLogPathAction,
+	})
+
+	return group
+}
+
+// latestLogFile returns the path of the log file of the previous azd command.
+func latestLogFile() (string, error) {
+	logsDir, err := logging.Dir()
+	if err != nil {
+		return "", fmt.Errorf("getting logs directory: %w", err)
+	}
+
+	path, err := logging.Latest(logsDir)
+	if errors.Is(err, logging.ErrNoLogs) {
+		return "", fmt.Errorf("%w in %s, logs are written by each azd command", err, logsDir)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+type debugLogShowAction struct {
+	writer io.Writer
+}
+
+func newDebugLogShowAction(writer io.Writer) actions.Action {
+	return &debugLogShowAction{
+		writer: writer,
+	}
+}
+
+func (d *debugLogShowAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	path, err := latestLogFile()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(d.writer, file); err != nil {
+		return nil, fmt.Errorf("reading log file: %w", err)
+	}
+
+	return nil, nil
+}
+
+type debugLogPathAction struct {
+	writer io.Writer
+}
+
+func newDebugLogPathAction(writer io.Writer) actions.Action {
+	return &debugLogPathAction{
+		writer: writer,
+	}
+}
+
+func (d *debugLogPathAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	path, err := latestLogFile()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintln(d.writer, path)
+	return nil, nil
+}
+
+func getCmdDebugLogHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Inspect the log files written by azd.",
+		[]string{
+			formatHelpNote("Every azd command writes a detailed log, the same output as " +
+				output.WithHighLightFormat("--debug") + ", to a log file in the logs folder of the azd " +
+				"configuration directory."),
+			formatHelpNote(fmt.Sprintf("The %d most recent log files are kept, share them when reporting an issue "+
+				"instead of running the failed command again.", logging.DefaultMaxFiles)),
+		})
+}
@@ -18,19 +18,25 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 )
 
 type deployFlags struct {
 	serviceName string
 	all         bool
 	fromPackage string
+	profile     string
 	global      *internal.GlobalCommandOptions
 	*envFlag
 }
@@ -71,6 +77,12 @@ func (d *deployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 		"",
 		"Deploys the application from an existing package.",
 	)
+	local.StringVar(
+		&d.profile,
+		"service-profile",
+		"",
+		"Deploys the services of a profile defined in "+azdcontext.ProjectFileName+".",
+	)
 }
 
 func (d *deployFlags) setCommon(envFlag *envFlag) {
@@ -90,6 +102,7 @@ func newDeployCmd() *cobra.Command {
 		Short: "Deploy the application's code to Azure.",
 	}
 	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.ValidArgsFunction = serviceNameCompletion
 
 	return cmd
 }
@@ -103,8 +116,10 @@ type deployAction struct {
 	projectManager           project.ProjectManager
 	serviceManager           project.ServiceManager
 	resourceManager          project.ResourceManager
+	serviceConnector         *project.ServiceConnector
 	accountManager           account.Manager
 	azCli                    azcli.AzCli
+	httpClient               httputil.HttpClient
 	formatter                output.Formatter
 	writer                   io.Writer
 	console                  input.Console
@@ -112,6 +127,7 @@ type deployAction struct {
 	middlewareRunner         middleware.MiddlewareContext
 	packageActionInitializer actions.ActionInitializer[*packageAction]
 	alphaFeatureManager      *alpha.FeatureManager
+	progressBus              *progress.Bus
 }
 
 func newDeployAction(
@@ -121,10 +137,12 @@ func newDeployAction(
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
 	resourceManager project.ResourceManager,
+	serviceConnector *project.ServiceConnector,
 	azdCtx *azdcontext.AzdContext,
 	environment *environment.Environment,
 	accountManager account.Manager,
 	azCli azcli.AzCli,
+	httpClient httputil.HttpClient,
 	commandRunner exec.CommandRunner,
 	console input.Console,
 	formatter output.Formatter,
@@ -132,6 +150,7 @@ func newDeployAction(
 	middlewareRunner middleware.MiddlewareContext,
 	packageActionInitializer actions.ActionInitializer[*packageAction],
 	alphaFeatureManager *alpha.FeatureManager,
+	progressBus *progress.Bus,
 ) actions.Action {
 	return &deployAction{
 		flags:                    flags,
@@ -142,8 +161,10 @@ func newDeployAction(
 		projectManager:           projectManager,
 		serviceManager:           serviceManager,
 		resourceManager:          resourceManager,
+		serviceConnector:         serviceConnector,
 		accountManager:           accountManager,
 		azCli:                    azCli,
+		httpClient:               httpClient,
 		formatter:                formatter,
 		writer:                   writer,
 		console:                  console,
@@ -151,12 +172,14 @@ func newDeployAction(
 		middlewareRunner:         middlewareRunner,
 		packageActionInitializer: packageActionInitializer,
 		alphaFeatureManager:      alphaFeatureManager,
+		progressBus:              progressBus,
 	}
 }
 
 type DeploymentResult struct {
 	Timestamp time.Time                               `json:"timestamp"`
 	Services  map[string]*project.ServiceDeployResult `json:"services"`
+	Summary   *project.DeploySummary                  `json:"summary"`
 }
 
 func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error) {
@@ -173,18 +196,37 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		)
 	}
 
-	targetServiceName, err := getTargetServiceName(
-		ctx,
-		da.projectManager,
-		da.projectConfig,
-		string(project.ServiceEventDeploy),
-		targetServiceName,
-		da.flags.all,
-	)
+	// The services of the profile are deployed when --service-profile is set, instead of the target service
+	var profileServices []string
+	var err error
+	if da.flags.profile != "" {
+		if targetServiceName != "" || da.flags.all {
+			return nil, errors.New("'--service-profile' cannot be specified with <service> or '--all'")
+		}
+
+		profileServices, err = da.projectConfig.ProfileServices(da.flags.profile)
+	} else {
+		targetServiceName, err = getTargetServiceName(
+			ctx,
+			da.projectManager,
+			da.projectConfig,
+			string(project.ServiceEventDeploy),
+			targetServiceName,
+			da.flags.all,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	isTargeted := func(svc *project.ServiceConfig) bool {
+		if profileServices != nil {
+			return slices.Contains(profileServices, svc.Name)
+		}
+
+		return targetServiceName == "" || svc.Name == targetServiceName
+	}
+
 	if da.flags.all && da.flags.fromPackage != "" {
 		return nil, errors.New(
 			"'--from-package' cannot be specified when '--all' is set. Specify a specific service by passing a <service>")
@@ -202,25 +244,30 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}
 
 	if err := da.projectManager.EnsureServiceTargetTools(ctx, da.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return isTargeted(svc) && svc.EnabledIn(da.env.GetEnvName())
 	}); err != nil {
 		return nil, err
 	}
 
 	// Command title
 	da.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title: "Deploying services (azd deploy)",
+		Title: locale.Sprintf(locale.DeployTitle),
 	})
 
 	deployResults := map[string]*project.ServiceDeployResult{}
+	summary := &project.DeploySummary{Services: []*project.ServiceDeploySummary{}}
 
 	for _, svc := range da.projectConfig.GetServicesStable() {
-		stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
+		// Skip this service if the user specified a service name or a profile, which doesn't include this service
+		if !isTargeted(svc) {
+			continue
+		}
 
-		// Skip this service if both cases are true:
-		// 1. The user specified a service name
-		// 2. This service is not the one the user specified
-		if targetServiceName != "" && targetServiceName != svc.Name {
+		if !svc.EnabledIn(da.env.GetEnvName()) {
+			step := da.progressBus.Step(
+				string(project.ServiceEventDeploy), svc.Name, locale.Sprintf(locale.DeployStep, svc.Name))
+			step.Start(ctx)
+			step.SkipWithReason(ctx, locale.Sprintf(locale.ServiceDisabled, da.env.GetEnvName()))
 			continue
 		}
 
@@ -230,7 +277,11 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			da.console.MessageUxItem(ctx, alpha.WarningMessage(alphaFeatureId))
 		}
 
-		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+		step := da.progressBus.Step(
+			string(project.ServiceEventDeploy), svc.Name, locale.Sprintf(locale.DeployStep, svc.Name))
+		step.Start(ctx)
+		start := time.Now()
+
 		var packageResult *project.ServicePackageResult
 		if da.flags.fromPackage != "" {
 			// --from-package set, skip packaging
@@ -242,34 +293,59 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			packageTask := da.serviceManager.Package(ctx, svc, nil)
 			go func() {
 				for packageProgress := range packageTask.Progress() {
-					progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, packageProgress.Message)
-					da.console.ShowSpinner(ctx, progressMessage, input.Step)
+					step.Progress(ctx, packageProgress.Message)
 				}
 			}()
 
 			packageResult, err = packageTask.Await()
 			if err != nil {
-				da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
-				return nil, err
+				step.Fail(ctx, err)
+				return nil, exitcode.New(exitcode.CategoryDeployment, err)
 			}
 		}
 
 		deployTask := da.serviceManager.Deploy(ctx, svc, packageResult)
 		go func() {
 			for deployProgress := range deployTask.Progress() {
-				progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, deployProgress.Message)
-				da.console.ShowSpinner(ctx, progressMessage, input.Step)
+				step.Progress(ctx, deployProgress.Message)
 			}
 		}()
 
 		deployResult, err := deployTask.Await()
 		if err != nil {
-			da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
-			return nil, err
+			step.Fail(ctx, err)
+			return nil, exitcode.New(exitcode.CategoryDeployment, err)
+		}
+
+		// Service Connector sets the configuration of the connections in the app settings of the service, the
+		// environment gets it too so it can be used by the services running locally.
+		if len(svc.Connections) > 0 {
+			step.Progress(ctx, "Creating connections")
+			configurations, err := da.serviceConnector.Connect(ctx, svc)
+			if err != nil {
+				step.Fail(ctx, err)
+				return nil, exitcode.New(exitcode.CategoryDeployment, err)
+			}
+
+			for _, configuration := range configurations {
+				da.env.SetServiceProperty(svc.Name, configuration.Name, configuration.Value)
+			}
 		}
 
-		da.console.StopSpinner(ctx, stepMessage, input.StepDone)
+		step.Complete(ctx)
 		deployResults[svc.Name] = deployResult
+		summary.Services = append(
+			summary.Services,
+			project.NewServiceDeploySummary(ctx, da.httpClient, svc.Name, deployResult, time.Since(start)),
+		)
+
+		if err := setLastDeployment(da.env, svc.Name, time.Now()); err != nil {
+			return nil, err
+		}
+
+		if err := da.env.Save(); err != nil {
+			return nil, fmt.Errorf("saving environment: %w", err)
+		}
 
 		// report deploy outputs
 		da.console.MessageUxItem(ctx, deployResult)
@@ -279,16 +355,19 @@ func (da *deployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		deployResult := DeploymentResult{
 			Timestamp: time.Now(),
 			Services:  deployResults,
+			Summary:   summary,
 		}
 
 		if fmtErr := da.formatter.Format(deployResult, da.writer, nil); fmtErr != nil {
 			return nil, fmt.Errorf("deploy result could not be displayed: %w", fmtErr)
 		}
+	} else if len(summary.Services) > 0 {
+		da.console.MessageUxItem(ctx, summary)
 	}
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header:   "Your Azure app has been deployed!",
+			Header:   locale.Sprintf(locale.DeploySuccess),
 			FollowUp: getResourceGroupFollowUp(ctx, da.formatter, da.projectConfig, da.resourceManager, da.env),
 		},
 	}, nil
@@ -301,6 +380,9 @@ func getCmdDeployHelpDescription(*cobra.Command) string {
 				" or the service described in the project that matches the current directory."),
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is deployed.", output.WithHighLightFormat("<service>"))),
+		formatHelpNote(fmt.Sprintf(
+			"When %s is set, only the services of the profile, listed in the 'profiles' section of 'azure.yaml', are"+
+				" deployed.", output.WithHighLightFormat("--service-profile"))),
 		formatHelpNote("After the deployment is complete, the endpoint is printed. To start the service, select" +
 			" the endpoint or paste it in a browser."),
 	})
@@ -320,5 +402,8 @@ func getCmdDeployHelpFooter(*cobra.Command) string {
 		"Deploy the service named 'api' to Azure from a previously generated package.": output.WithHighLightFormat(
 			"azd deploy api --from-package <package-path>",
 		),
+		"Deploy the services of the profile named 'backend' to Azure.": output.WithHighLightFormat(
+			"azd deploy --service-profile backend",
+		),
 	})
 }
@@ -0,0 +1,163 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
+)
+
+type devFlags struct {
+	profile string
+	global  *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *devFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.profile,
+		"service-profile",
+		"",
+		"Runs the services of a profile defined in "+azdcontext.ProjectFileName+".",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newDevFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *devFlags {
+	flags := &devFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newDevCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev [<service>...]",
+		Short: "Run the application's services locally.",
+	}
+	cmd.ValidArgsFunction = serviceNameCompletion
+
+	return cmd
+}
+
+type devAction struct {
+	flags         *devFlags
+	args          []string
+	projectConfig *project.ProjectConfig
+	env           *environment.Environment
+	devRunner     *project.DevRunner
+	console       input.Console
+}
+
+func newDevAction(
+	flags *devFlags,
+	args []string,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	devRunner *project.DevRunner,
+	console input.Console,
+) actions.Action {
+	return &devAction{
+		flags:         flags,
+		args:          args,
+		projectConfig: projectConfig,
+		env:           env,
+		devRunner:     devRunner,
+		console:       console,
+	}
+}
+
+func (d *devAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	for _, serviceName := range d.args {
+		if _, has := d.projectConfig.Services[serviceName]; !has {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+		}
+	}
+
+	targetServiceNames := d.args
+	if d.flags.profile != "" {
+		if len(d.args) > 0 {
+			return nil, errors.New("'--service-profile' cannot be specified with <service>")
+		}
+
+		profileServices, err := d.projectConfig.ProfileServices(d.flags.profile)
+		if err != nil {
+			return nil, err
+		}
+
+		targetServiceNames = profileServices
+	}
+
+	var services []*project.ServiceConfig
+	var serviceNames []string
+	for _, svc := range d.projectConfig.GetServicesStable() {
+		if len(targetServiceNames) > 0 && !slices.Contains(targetServiceNames, svc.Name) {
+			continue
+		}
+
+		services = append(services, svc)
+		serviceNames = append(serviceNames, svc.Name)
+	}
+
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services to run, add services to %s", azdcontext.ProjectFileName)
+	}
+
+	d.console.Message(ctx, fmt.Sprintf(
+		"Running %s locally with the values of environment %s. Press Ctrl+C to stop.\n",
+		output.WithHighLightFormat(strings.Join(serviceNames, ", ")),
+		output.WithHighLightFormat(d.env.GetEnvName()),
+	))
+
+	if devcontainer.Detect(os.Getenv) != devcontainer.KindNone {
+		d.console.Message(ctx, output.WithGrayFormat(
+			"The ports opened by the services are forwarded by your dev container, open them from the Ports view"+
+				" of your editor.\n"))
+	}
+
+	if err := d.devRunner.Run(ctx, services, d.env.Environ(), d.console.Handles().Stdout); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCmdDevHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription("Run the application's services locally.", []string{
+		formatHelpNote("By default, runs all services listed in 'azure.yaml'. When one or more " +
+			output.WithHighLightFormat("<service>") + " are set, only those services are run."),
+		formatHelpNote("Services that contain a compose file are started with docker compose, function apps with" +
+			" 'func start', and other services with the runner of their language, like 'dotnet run' or 'npm run dev'."),
+		formatHelpNote("The values of the environment, like the endpoints and connection strings of deployed" +
+			" resources, are available to the services as environment variables."),
+		formatHelpNote(fmt.Sprintf(
+			"When %s is set, only the services of the profile, listed in the 'profiles' section of 'azure.yaml', are"+
+				" run.", output.WithHighLightFormat("--service-profile"))),
+	})
+}
+
+func getCmdDevHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run all services in the current project locally.": output.WithHighLightFormat("azd dev"),
+		"Run the services named 'api' and 'web' locally.":  output.WithHighLightFormat("azd dev api web"),
+		"Run the services of the profile named 'frontend' locally.": output.WithHighLightFormat(
+			"azd dev --service-profile frontend"),
+	})
+}
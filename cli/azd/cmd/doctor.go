@@ -0,0 +1,501 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	osexec "os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	doctorCategoryTools   = "tools"
+	doctorCategoryAuth    = "auth"
+	doctorCategoryNetwork = "network"
+	doctorCategoryProject = "project"
+)
+
+// The endpoints azd must be able to reach in order to authenticate and manage Azure resources.
+var doctorNetworkEndpoints = []struct {
+	name     string
+	endpoint string
+}{
+	{"Azure Resource Manager", "https://management.azure.com/metadata/endpoints?api-version=2019-05-01"},
+	{"Microsoft Entra ID", "https://login.microsoftonline.com/common/v2.0/.well-known/openid-configuration"},
+}
+
+type doctorFlags struct {
+	reportPath string
+	global     *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *doctorFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.reportPath,
+		"report",
+		"",
+		"Writes a JSON report of the diagnostics to the specified file, to be attached to support requests.",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newDoctorFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *doctorFlags {
+	flags := &doctorFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newDoctorCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose common problems with your azd installation and project.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type doctorAction struct {
+	flags                *doctorFlags
+	console              input.Console
+	formatter            output.Formatter
+	writer               io.Writer
+	commandRunner        exec.CommandRunner
+	httpClient           httputil.HttpClient
+	authManager          *auth.Manager
+	accountManager       account.Manager
+	subscriptionsManager *account.SubscriptionsManager
+	lazyAzdCtx           *lazy.Lazy[*azdcontext.AzdContext]
+	lazyEnv              *lazy.Lazy[*environment.Environment]
+	tools                []doctorTool
+}
+
+// doctorTool is an external tool checked by `azd doctor`. Required tools are needed by every azd project, while the
+// remaining tools are only needed depending on the languages and hosts used by the project.
+type doctorTool struct {
+	tool     tools.ExternalTool
+	required bool
+}
+
+func newDoctorAction(
+	flags *doctorFlags,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	commandRunner exec.CommandRunner,
+	httpClient httputil.HttpClient,
+	authManager *auth.Manager,
+	accountManager account.Manager,
+	subscriptionsManager *account.SubscriptionsManager,
+	lazyAzdCtx *lazy.Lazy[*azdcontext.AzdContext],
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	gitCli git.GitCli,
+	dockerCli docker.Docker,
+	dotnetCli dotnet.DotNetCli,
+	npmCli npm.NpmCli,
+	pythonCli *python.PythonCli,
+	javacCli javac.JavacCli,
+	mavenCli maven.MavenCli,
+	gradleCli gradle.GradleCli,
+	goCli golang.GoCli,
+	kubectlCli kubectl.KubectlCli,
+	terraformCli terraform.TerraformCli,
+) actions.Action {
+	return &doctorAction{
+		flags:                flags,
+		console:              console,
+		formatter:            formatter,
+		writer:               writer,
+		commandRunner:        commandRunner,
+		httpClient:           httpClient,
+		authManager:          authManager,
+		accountManager:       accountManager,
+		subscriptionsManager: subscriptionsManager,
+		lazyAzdCtx:           lazyAzdCtx,
+		lazyEnv:              lazyEnv,
+		tools: []doctorTool{
+			{tool: gitCli, required: true},
+			{tool: dockerCli},
+			{tool: dotnetCli},
+			{tool: npmCli},
+			{tool: pythonCli},
+			{tool: javacCli},
+			{tool: mavenCli},
+			{tool: gradleCli},
+			{tool: goCli},
+			{tool: kubectlCli},
+			{tool: terraformCli},
+		},
+	}
+}
+
+func (d *doctorAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Command title
+	d.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     locale.Sprintf(locale.DoctorTitle),
+		TitleNote: locale.Sprintf(locale.DoctorTitleNote),
+	})
+
+	result := contracts.DoctorResult{
+		Timestamp: time.Now(),
+		Version:   internal.Version,
+		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+
+	spinnerMessage := locale.Sprintf(locale.DoctorRunning)
+	d.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	result.Checks = append(result.Checks, d.checkTools(ctx)...)
+	result.Checks = append(result.Checks, d.checkDockerDaemon(ctx))
+	result.Checks = append(result.Checks, d.checkNetwork(ctx)...)
+	result.Checks = append(result.Checks, d.checkAuth(ctx)...)
+	result.Checks = append(result.Checks, d.checkProject(ctx))
+
+	d.console.StopSpinner(ctx, "", input.StepDone)
+
+	if d.flags.reportPath != "" {
+		if err := writeDoctorReport(d.flags.reportPath, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.formatter.Kind() == output.JsonFormat {
+		if err := d.formatter.Format(result, d.writer, nil); err != nil {
+			return nil, fmt.Errorf("doctor result could not be displayed: %w", err)
+		}
+
+		return nil, nil
+	}
+
+	for _, check := range result.Checks {
+		fmt.Fprintln(d.writer, formatDoctorCheck(check))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   doctorSummary(result.Checks),
+			FollowUp: doctorFollowUp(d.flags.reportPath),
+		},
+	}, nil
+}
+
+func (d *doctorAction) checkTools(ctx context.Context) []contracts.DoctorCheck {
+	checks := make([]contracts.DoctorCheck, 0, len(d.tools))
+
+	for _, t := range d.tools {
+		check := contracts.DoctorCheck{
+			Category: doctorCategoryTools,
+			Name:     t.tool.Name(),
+		}
+
+		err := t.tool.CheckInstalled(ctx)
+		var errSemver *tools.ErrSemver
+
+		switch {
+		case err == nil:
+			check.Status = contracts.DoctorCheckPassed
+			check.Message = "installed"
+		case errors.As(err, &errSemver):
+			check.Status = contracts.DoctorCheckFailed
+			check.Message = err.Error()
+			check.Remediation = fmt.Sprintf("Update %s, see %s", t.tool.Name(), t.tool.InstallUrl())
+		case errors.Is(err, osexec.ErrNotFound):
+			check.Status = contracts.DoctorCheckWarning
+			if t.required {
+				check.Status = contracts.DoctorCheckFailed
+			}
+			check.Message = "not installed"
+			check.Remediation = fmt.Sprintf("Install %s, see %s", t.tool.Name(), t.tool.InstallUrl())
+			if !t.required {
+				check.Remediation += " (only required when your project uses it)"
+			}
+		default:
+			check.Status = contracts.DoctorCheckFailed
+			check.Message = err.Error()
+			check.Remediation = fmt.Sprintf("Reinstall %s, see %s", t.tool.Name(), t.tool.InstallUrl())
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+func (d *doctorAction) checkDockerDaemon(ctx context.Context) contracts.DoctorCheck {
+	check := contracts.DoctorCheck{
+		Category: doctorCategoryTools,
+		Name:     "Docker daemon",
+	}
+
+	if err := tools.ToolInPath("docker"); err != nil {
+		check.Status = contracts.DoctorCheckSkipped
+		check.Message = "docker is not installed"
+		return check
+	}
+
+	res, err := d.commandRunner.Run(ctx, exec.NewRunArgs("docker", "info", "--format", "{{.ServerVersion}}"))
+	if err != nil {
+		check.Status = contracts.DoctorCheckWarning
+		check.Message = "the docker daemon is not running or is not reachable"
+		check.Remediation = "Start Docker Desktop or the docker service, required to package container based services"
+		return check
+	}
+
+	check.Status = contracts.DoctorCheckPassed
+	check.Message = fmt.Sprintf("running (server version %s)", strings.TrimSpace(res.Stdout))
+	return check
+}
+
+func (d *doctorAction) checkNetwork(ctx context.Context) []contracts.DoctorCheck {
+	checks := []contracts.DoctorCheck{}
+
+	for _, e := range doctorNetworkEndpoints {
+		endpoint := e.endpoint
+		check := contracts.DoctorCheck{
+			Category: doctorCategoryNetwork,
+			Name:     e.name,
+		}
+
+		if err := d.probeEndpoint(ctx, endpoint); err != nil {
+			check.Status = contracts.DoctorCheckFailed
+			check.Message = fmt.Sprintf("unable to reach %s: %v", endpoint, err)
+			check.Remediation = "Check your network connection, firewall and proxy settings " +
+				"(see https://aka.ms/azd-proxy-configuration)"
+		} else {
+			check.Status = contracts.DoctorCheckPassed
+			check.Message = "reachable"
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// probeEndpoint sends a request to the specified endpoint. Any HTTP response, regardless of the status code, means
+// that the endpoint is reachable.
+func (d *doctorAction) probeEndpoint(ctx context.Context, endpoint string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (d *doctorAction) checkAuth(ctx context.Context) []contracts.DoctorCheck {
+	loginCheck := contracts.DoctorCheck{
+		Category: doctorCategoryAuth,
+		Name:     "Login",
+	}
+	subscriptionCheck := contracts.DoctorCheck{
+		Category: doctorCategoryAuth,
+		Name:     "Subscription access",
+	}
+
+	credential, err := d.authManager.CredentialForCurrentUser(ctx, nil)
+	if err == nil {
+		_, err = auth.EnsureLoggedInCredential(ctx, credential)
+	}
+
+	if err != nil {
+		loginCheck.Status = contracts.DoctorCheckFailed
+		loginCheck.Message = "not logged in"
+		loginCheck.Remediation = fmt.Sprintf("Run %s", output.WithHighLightFormat("azd auth login"))
+
+		subscriptionCheck.Status = contracts.DoctorCheckSkipped
+		subscriptionCheck.Message = "requires login"
+
+		return []contracts.DoctorCheck{loginCheck, subscriptionCheck}
+	}
+
+	loginCheck.Status = contracts.DoctorCheckPassed
+	loginCheck.Message = "logged in"
+
+	subscriptionId := ""
+	if env, err := d.lazyEnv.GetValue(); err == nil {
+		subscriptionId = env.GetSubscriptionId()
+	}
+
+	if subscriptionId != "" {
+		if subscription, err := d.subscriptionsManager.GetSubscription(ctx, subscriptionId); err != nil {
+			subscriptionCheck.Status = contracts.DoctorCheckFailed
+			subscriptionCheck.Message = fmt.Sprintf("unable to access subscription %s: %v", subscriptionId, err)
+			subscriptionCheck.Remediation = "Ensure your account has access to the subscription configured in " +
+				"the environment, or update AZURE_SUBSCRIPTION_ID with " +
+				output.WithHighLightFormat("azd env set")
+		} else {
+			subscriptionCheck.Status = contracts.DoctorCheckPassed
+			subscriptionCheck.Message = fmt.Sprintf("%s (%s)", subscription.Name, subscription.Id)
+		}
+
+		return []contracts.DoctorCheck{loginCheck, subscriptionCheck}
+	}
+
+	subscriptions, err := d.accountManager.GetSubscriptions(ctx)
+	switch {
+	case err != nil:
+		subscriptionCheck.Status = contracts.DoctorCheckFailed
+		subscriptionCheck.Message = fmt.Sprintf("unable to list subscriptions: %v", err)
+		subscriptionCheck.Remediation = fmt.Sprintf("Run %s and try again", output.WithHighLightFormat("azd auth login"))
+	case len(subscriptions) == 0:
+		subscriptionCheck.Status = contracts.DoctorCheckFailed
+		subscriptionCheck.Message = "no subscriptions are accessible by the current account"
+		subscriptionCheck.Remediation = "Ask the owner of a subscription to grant your account access"
+	default:
+		subscriptionCheck.Status = contracts.DoctorCheckPassed
+		subscriptionCheck.Message = fmt.Sprintf("%d subscription(s) available", len(subscriptions))
+	}
+
+	return []contracts.DoctorCheck{loginCheck, subscriptionCheck}
+}
+
+func (d *doctorAction) checkProject(ctx context.Context) contracts.DoctorCheck {
+	check := contracts.DoctorCheck{
+		Category: doctorCategoryProject,
+		Name:     azdcontext.ProjectFileName,
+	}
+
+	azdCtx, err := d.lazyAzdCtx.GetValue()
+	if err != nil {
+		check.Status = contracts.DoctorCheckSkipped
+		check.Message = fmt.Sprintf("no %s found in the current directory or its parents", azdcontext.ProjectFileName)
+		return check
+	}
+
+	projectConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		check.Status = contracts.DoctorCheckFailed
+		check.Message = err.Error()
+		check.Remediation = fmt.Sprintf("Fix the errors in %s", azdCtx.ProjectPath())
+		return check
+	}
+
+	check.Status = contracts.DoctorCheckPassed
+	check.Message = fmt.Sprintf("valid, %d service(s) defined", len(projectConfig.Services))
+	return check
+}
+
+func writeDoctorReport(path string, result contracts.DoctorResult) error {
+	report, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling doctor report: %w", err)
+	}
+
+	if err := os.WriteFile(path, report, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing doctor report: %w", err)
+	}
+
+	return nil
+}
+
+func formatDoctorCheck(check contracts.DoctorCheck) string {
+	var prefix string
+	switch check.Status {
+	case contracts.DoctorCheckPassed:
+		prefix = output.WithSuccessFormat("(✓) Passed:")
+	case contracts.DoctorCheckWarning:
+		prefix = output.WithWarningFormat("(!) Warning:")
+	case contracts.DoctorCheckFailed:
+		prefix = output.WithErrorFormat("(x) Failed:")
+	default:
+		prefix = output.WithGrayFormat("(-) Skipped:")
+	}
+
+	line := fmt.Sprintf("  %s %s", prefix, check.Name)
+	if check.Message != "" {
+		line += output.WithGrayFormat(" - %s", check.Message)
+	}
+	if check.Remediation != "" {
+		line += fmt.Sprintf("\n      %s", check.Remediation)
+	}
+
+	return line
+}
+
+func doctorSummary(checks []contracts.DoctorCheck) string {
+	failed, warnings := 0, 0
+	for _, check := range checks {
+		switch check.Status {
+		case contracts.DoctorCheckFailed:
+			failed++
+		case contracts.DoctorCheckWarning:
+			warnings++
+		}
+	}
+
+	if failed == 0 && warnings == 0 {
+		return locale.Sprintf(locale.DoctorNoIssues)
+	}
+
+	return locale.Sprintf(locale.DoctorIssues, failed, warnings)
+}
+
+func doctorFollowUp(reportPath string) string {
+	if reportPath != "" {
+		return fmt.Sprintf("The diagnostics report was written to %s", output.WithLinkFormat(reportPath))
+	}
+
+	return fmt.Sprintf("Run %s to export a report that can be attached to support requests.",
+		output.WithHighLightFormat("azd doctor --report <file>"))
+}
+
+func getCmdDoctorHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Diagnose common problems with your azd installation and project.",
+		[]string{
+			formatHelpNote("Checks the availability and version of the tools used by azd."),
+			formatHelpNote("Checks your login status, subscription access and network access to Azure."),
+			formatHelpNote(fmt.Sprintf("Checks that the Docker daemon is running and that %s is valid.",
+				azdcontext.ProjectFileName)),
+		})
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_doctorSummary(t *testing.T) {
+	t.Run("NoIssues", func(t *testing.T) {
+		summary := doctorSummary([]contracts.DoctorCheck{
+			{Name: "git", Status: contracts.DoctorCheckPassed},
+			{Name: "docker", Status: contracts.DoctorCheckSkipped},
+		})
+		require.Equal(t, "No issues found!", summary)
+	})
+
+	t.Run("WithIssues", func(t *testing.T) {
+		summary := doctorSummary([]contracts.DoctorCheck{
+			{Name: "git", Status: contracts.DoctorCheckFailed},
+			{Name: "docker", Status: contracts.DoctorCheckWarning},
+			{Name: "npm", Status: contracts.DoctorCheckWarning},
+		})
+		require.Equal(t, "Diagnostics completed with 1 failure(s) and 2 warning(s).", summary)
+	})
+}
+
+func Test_writeDoctorReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	result := contracts.DoctorResult{
+		Version:  "1.0.0",
+		Platform: "linux/amd64",
+		Checks: []contracts.DoctorCheck{
+			{
+				Category:    doctorCategoryAuth,
+				Name:        "Login",
+				Status:      contracts.DoctorCheckFailed,
+				Remediation: "Run azd auth login",
+			},
+		},
+	}
+
+	require.NoError(t, writeDoctorReport(path, result))
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var actual contracts.DoctorResult
+	require.NoError(t, json.Unmarshal(contents, &actual))
+	require.Equal(t, result.Checks, actual.Checks)
+	require.Equal(t, result.Version, actual.Version)
+}
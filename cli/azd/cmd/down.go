@@ -11,8 +11,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
@@ -22,9 +24,10 @@ import (
 )
 
 type downFlags struct {
-	forceDelete bool
-	purgeDelete bool
-	global      *internal.GlobalCommandOptions
+	forceDelete   bool
+	purgeDelete   bool
+	skipDataCheck bool
+	global        *internal.GlobalCommandOptions
 	envFlag
 }
 
@@ -37,6 +40,13 @@ func (i *downFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		//nolint:lll
 		"Does not require confirmation before it permanently deletes resources that are soft-deleted by default (for example, key vaults).",
 	)
+	local.BoolVar(
+		&i.skipDataCheck,
+		"skip-data-check",
+		false,
+		//nolint:lll
+		"Does not list the resources storing data (for example, databases and storage accounts) or offer to back them up before it deletes them.",
+	)
 	i.envFlag.Bind(local, global)
 	i.global = global
 }
@@ -106,15 +116,15 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	// Command title
 	a.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title:     "Deleting all resources and deployed code on Azure (azd down)",
-		TitleNote: "Local application code is not deleted when running 'azd down'.",
+		Title:     locale.Sprintf(locale.DownTitle),
+		TitleNote: locale.Sprintf(locale.DownTitleNote),
 	})
 
-	spinnerMsg := "Fetching resources groups."
-	a.console.ShowSpinner(ctx, spinnerMsg, input.Step)
-
-	deploymentPlan, err := infraManager.Plan(ctx)
-	a.console.StopSpinner(ctx, spinnerMsg, input.GetStepResultFormat(err))
+	var deploymentPlan *provisioning.DeploymentPlan
+	err = input.RunWithSpinner(ctx, a.console, locale.Sprintf(locale.DownFetching), func(ctx context.Context) error {
+		deploymentPlan, err = infraManager.Plan(ctx)
+		return err
+	})
 	a.console.Message(ctx, "")
 	if err != nil {
 		return nil, fmt.Errorf("planning destroy: %w", err)
@@ -126,7 +136,13 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("creating provisioning manager: %w", err)
 	}
 
-	destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete)
+	backup, err := a.backup()
+	if err != nil {
+		return nil, err
+	}
+
+	destroyOptions := provisioning.NewDestroyOptions(a.flags.forceDelete, a.flags.purgeDelete).
+		WithDataCheck(a.flags.skipDataCheck, backup)
 	destroyResult, err := infraManager.Destroy(ctx, &deploymentPlan.Deployment, destroyOptions)
 	if err != nil {
 		return nil, fmt.Errorf("deleting infrastructure: %w", err)
@@ -144,11 +160,65 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: "Your Azure resources have been deleted.",
+			Header: locale.Sprintf(locale.DownSuccess),
 		},
 	}, nil
 }
 
+// backupHookName is the name of the hooks backing up the data of the resources before azd down deletes them.
+const backupHookName = "backup"
+
+// backup returns the function running the backup hooks of the project and its services, or nil when no backup hook is
+// defined in azure.yaml. The hooks are found by the hooks manager, with the same rule used by the hooks runner to run
+// them.
+func (a *downAction) backup() (provisioning.BackupFn, error) {
+	type backupHook struct {
+		cwd   string
+		hooks map[string]*ext.HookConfig
+	}
+
+	var backupHooks []backupHook
+	addHooks := func(cwd string, hooks map[string]*ext.HookConfig) error {
+		matching, err := ext.NewHooksManager(cwd).GetByName(hooks, backupHookName)
+		if err != nil {
+			return fmt.Errorf("loading %s hooks: %w", backupHookName, err)
+		}
+
+		if len(matching) > 0 {
+			backupHooks = append(backupHooks, backupHook{cwd: cwd, hooks: hooks})
+		}
+
+		return nil
+	}
+
+	if err := addHooks(a.projectConfig.Path, a.projectConfig.Hooks); err != nil {
+		return nil, err
+	}
+
+	for _, svc := range a.projectConfig.GetServicesStable() {
+		if err := addHooks(svc.Path(), svc.Hooks); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(backupHooks) == 0 {
+		return nil, nil
+	}
+
+	return func(ctx context.Context) error {
+		for _, hook := range backupHooks {
+			hooksRunner := ext.NewHooksRunner(
+				ext.NewHooksManager(hook.cwd), a.commandRunner, a.console, a.azCli, hook.cwd, hook.hooks, a.env,
+			)
+			if err := hooksRunner.RunHook(ctx, backupHookName); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil
+}
+
 func createProvisioningManager(ctx context.Context, a *downAction, console input.Console) (*provisioning.Manager, error) {
 	infraManager, err := provisioning.NewManager(
 		ctx,
@@ -180,5 +250,7 @@ func getCmdDownHelpFooter(*cobra.Command) string {
 		"Forcibly delete all applications resources without confirmation.": output.WithHighLightFormat("azd down --force"),
 		"Permanently delete resources that are soft-deleted by default," +
 			" without confirmation.": output.WithHighLightFormat("azd down --purge"),
+		"Delete all resources without confirmation, listing or backing up resources storing data.": output.WithHighLightFormat(
+			"azd down --force --skip-data-check"),
 	})
 }
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_downAction_backup(t *testing.T) {
+	newAction := func(hooks map[string]*ext.HookConfig) *downAction {
+		return &downAction{
+			projectConfig: &project.ProjectConfig{
+				Path:  t.TempDir(),
+				Hooks: hooks,
+			},
+		}
+	}
+
+	t.Run("NoBackupHook", func(t *testing.T) {
+		backup, err := newAction(map[string]*ext.HookConfig{
+			"predown": {Shell: ext.ShellTypeBash, Run: "echo predown"},
+		}).backup()
+		require.NoError(t, err)
+		require.Nil(t, backup)
+	})
+
+	// The hook is found with the rule used to run it, the hook names are case insensitive
+	t.Run("BackupHook", func(t *testing.T) {
+		backup, err := newAction(map[string]*ext.HookConfig{
+			"Backup": {Shell: ext.ShellTypeBash, Run: "echo backup"},
+		}).backup()
+		require.NoError(t, err)
+		require.NotNil(t, backup)
+	})
+
+	t.Run("InvalidBackupHook", func(t *testing.T) {
+		_, err := newAction(map[string]*ext.HookConfig{
+			"backup": {Shell: ext.ShellTypeBash},
+		}).backup()
+		require.ErrorIs(t, err, ext.ErrRunRequired)
+	})
+}
@@ -5,24 +5,42 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/audit"
+	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
+	"github.com/azure/azure-dev/cli/azd/pkg/lock"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/secrets"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/sethvargo/go-retry"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 )
 
 func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
@@ -43,22 +61,41 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		Command:        newEnvSetCmd(),
 		FlagsResolver:  newEnvSetFlags,
 		ActionResolver: newEnvSetAction,
-	})
+		RequireProject: true,
+	}).UseMiddleware("audit", middleware.NewAuditMiddleware)
+
+	group.Add("set-secret", &actions.ActionDescriptorOptions{
+		Command:        newEnvSetSecretCmd(),
+		FlagsResolver:  newEnvSetSecretFlags,
+		ActionResolver: newEnvSetSecretAction,
+		RequireLogin:   true,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvSetSecretHelpDescription,
+			Footer:      getCmdEnvSetSecretHelpFooter,
+		},
+	}).UseMiddleware("audit", middleware.NewAuditMiddleware)
 
 	group.Add("select", &actions.ActionDescriptorOptions{
 		Command:        newEnvSelectCmd(),
 		ActionResolver: newEnvSelectAction,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvSelectHelpDescription,
+		},
 	})
 
 	group.Add("new", &actions.ActionDescriptorOptions{
 		Command:        newEnvNewCmd(),
 		FlagsResolver:  newEnvNewFlags,
 		ActionResolver: newEnvNewAction,
-	})
+		RequireProject: true,
+	}).AddFlagCompletion("subscription", subscriptionCompletion)
 
 	group.Add("list", &actions.ActionDescriptorOptions{
 		Command:        newEnvListCmd(),
 		ActionResolver: newEnvListAction,
+		RequireProject: true,
 		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
 		DefaultFormat:  output.TableFormat,
 	})
@@ -67,14 +104,57 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		Command:        newEnvRefreshCmd(),
 		FlagsResolver:  newEnvRefreshFlags,
 		ActionResolver: newEnvRefreshAction,
+		RequireLogin:   true,
+		RequireProject: true,
 		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvRefreshHelpDescription,
+			Footer:      getCmdEnvRefreshHelpFooter,
+		},
+	}).
+		AddFlagCompletion("subscription", subscriptionCompletion).
+		UseMiddleware("audit", middleware.NewAuditMiddleware)
+
+	group.Add("adopt", &actions.ActionDescriptorOptions{
+		Command:        newEnvAdoptCmd(),
+		FlagsResolver:  newEnvAdoptFlags,
+		ActionResolver: newEnvAdoptAction,
+		RequireLogin:   true,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvAdoptHelpDescription,
+			Footer:      getCmdEnvAdoptHelpFooter,
+		},
+	}).AddFlagCompletion("subscription", subscriptionCompletion)
+
+	group.Add("history", &actions.ActionDescriptorOptions{
+		Command:        newEnvHistoryCmd(),
+		FlagsResolver:  newEnvHistoryFlags,
+		ActionResolver: newEnvHistoryAction,
+		RequireProject: true,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvHistoryHelpDescription,
+		},
+	})
+
+	group.Add("unlock", &actions.ActionDescriptorOptions{
+		Command:        newEnvUnlockCmd(),
+		FlagsResolver:  newEnvUnlockFlags,
+		ActionResolver: newEnvUnlockAction,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvUnlockHelpDescription,
+		},
 	})
 
 	group.Add("get-values", &actions.ActionDescriptorOptions{
 		Command:        newEnvGetValuesCmd(),
 		FlagsResolver:  newEnvGetValuesFlags,
 		ActionResolver: newEnvGetValuesAction,
+		RequireProject: true,
 		OutputFormats:  []output.Format{output.JsonFormat, output.EnvVarsFormat},
 		DefaultFormat:  output.EnvVarsFormat,
 	})
@@ -132,6 +212,16 @@ func newEnvSetAction(
 }
 
 func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// The value would be saved but never used, the secret store taking precedence
+	if e.env.IsSecret(e.args[0]) {
+		return nil, fmt.Errorf(
+			"'%s' is sourced from a secret store declared in the secrets of azure.yaml, "+
+				"update the secret in the store or remove it from azure.yaml, or use `azd env set-secret` for the "+
+				"secrets of the environment kept in Key Vault",
+			e.args[0],
+		)
+	}
+
 	e.env.Values[e.args[0]] = e.args[1]
 
 	if err := e.env.Save(); err != nil {
@@ -141,32 +231,308 @@ func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	return nil, nil
 }
 
-func newEnvSelectCmd() *cobra.Command {
+func newEnvSetSecretFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSetSecretFlags {
+	flags := &envSetSecretFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvSetSecretCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "select <environment>",
-		Short: "Set the default environment.",
+		Use:   "set-secret <key>",
+		Short: "Store a secret of the environment in Azure Key Vault.",
 		Args:  cobra.ExactArgs(1),
 	}
 }
 
+type envSetSecretFlags struct {
+	envFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envSetSecretFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+// The role assigned to the signed-in account on the key vaults created by `azd env set-secret`, to write secrets
+const keyVaultSecretsOfficerRole = "Key Vault Secrets Officer"
+
+type envSetSecretAction struct {
+	console            input.Console
+	env                *environment.Environment
+	projectConfig      *project.ProjectConfig
+	azCli              azcli.AzCli
+	subResolver        account.SubscriptionTenantResolver
+	userProfileService *azcli.UserProfileService
+	flags              *envSetSecretFlags
+	args               []string
+}
+
+func newEnvSetSecretAction(
+	console input.Console,
+	env *environment.Environment,
+	projectConfig *project.ProjectConfig,
+	azCli azcli.AzCli,
+	subResolver account.SubscriptionTenantResolver,
+	userProfileService *azcli.UserProfileService,
+	flags *envSetSecretFlags,
+	args []string,
+) actions.Action {
+	return &envSetSecretAction{
+		console:            console,
+		env:                env,
+		projectConfig:      projectConfig,
+		azCli:              azCli,
+		subResolver:        subResolver,
+		userProfileService: userProfileService,
+		flags:              flags,
+		args:               args,
+	}
+}
+
+func (e *envSetSecretAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	key := e.args[0]
+	if _, has := e.projectConfig.Secrets[key]; has {
+		return nil, fmt.Errorf(
+			"'%s' is sourced from the secret store declared in the secrets of azure.yaml, update the secret in the store",
+			key,
+		)
+	}
+
+	secretName, err := secrets.KeyVaultSecretName(key)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionId := e.env.GetSubscriptionId()
+	if subscriptionId == "" {
+		return nil, fmt.Errorf(
+			"the environment has no subscription, run %s first", output.WithHighLightFormat("azd provision"))
+	}
+
+	value, err := e.console.Prompt(ctx, input.ConsoleOptions{
+		Message:    fmt.Sprintf("Enter the value of %s:", key),
+		IsPassword: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prompting for the value of %s: %w", key, err)
+	}
+
+	if value == "" {
+		return nil, fmt.Errorf("the value of %s can't be empty", key)
+	}
+
+	vaultName, created, err := e.ensureKeyVault(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The role assigned on a new vault takes a few moments to apply
+	attempts := uint64(1)
+	if created {
+		attempts = 12
+	}
+
+	err = retry.Do(ctx, retry.WithMaxRetries(attempts-1, retry.NewConstant(5*time.Second)), func(ctx context.Context) error {
+		_, err := e.azCli.SetKeyVaultSecret(ctx, subscriptionId, vaultName, secretName, value)
+
+		var responseErr *azcore.ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden {
+			return retry.RetryableError(err)
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storing %s in key vault %s: %w", key, vaultName, err)
+	}
+
+	// Only the reference to the secret is saved, the value is read from the vault when a command needs it
+	delete(e.env.Values, key)
+	reference := map[string]any{
+		"provider": string(secrets.ProviderKeyVault),
+		"ref":      secrets.KeyVaultReference(vaultName, secretName),
+	}
+	if err := e.env.Config.Set(environment.SecretsConfigPath+"."+key, reference); err != nil {
+		return nil, fmt.Errorf("saving the reference to %s: %w", key, err)
+	}
+
+	if err := e.env.Save(); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Stored %s in key vault %s", key, vaultName),
+			FollowUp: "The environment keeps a reference to the secret, its value is read from the vault " +
+				"when a command needs it.",
+		},
+	}, nil
+}
+
+// ensureKeyVault returns the name of the key vault of the environment. When the environment has no key vault, a vault
+// is created in the resource group of the environment after confirmation, and the signed-in account is allowed to
+// write its secrets.
+func (e *envSetSecretAction) ensureKeyVault(ctx context.Context, subscriptionId string) (string, bool, error) {
+	if vaultName := e.env.Getenv(environment.KeyVaultNameEnvVarName); vaultName != "" {
+		return vaultName, false, nil
+	}
+
+	resourceGroup := e.env.Getenv(environment.ResourceGroupEnvVarName)
+	if resourceGroup == "" {
+		return "", false, fmt.Errorf(
+			"the environment has no key vault, set %s to the name of a vault, or %s to create one in the resource group",
+			environment.KeyVaultNameEnvVarName,
+			environment.ResourceGroupEnvVarName,
+		)
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join([]string{subscriptionId, resourceGroup, e.env.GetEnvName()}, "/")))
+	vaultName := "kv-" + hex.EncodeToString(hash[:])[:13]
+
+	confirmed, err := e.console.Confirm(ctx, input.ConsoleOptions{
+		Message: fmt.Sprintf(
+			"The environment has no key vault, create key vault %s in resource group %s?", vaultName, resourceGroup),
+		DefaultValue: true,
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	if !confirmed {
+		return "", false, fmt.Errorf("the environment has no key vault, set %s to the name of a vault",
+			environment.KeyVaultNameEnvVarName)
+	}
+
+	tenantId, err := e.subResolver.LookupTenant(ctx, subscriptionId)
+	if err != nil {
+		return "", false, fmt.Errorf("getting the tenant of subscription %s: %w", subscriptionId, err)
+	}
+
+	spinnerMessage := fmt.Sprintf("Creating key vault %s", vaultName)
+	e.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	vault, err := e.azCli.CreateKeyVault(ctx, subscriptionId, resourceGroup, vaultName, e.env.GetLocation(), tenantId)
+	if err != nil {
+		e.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+		return "", false, err
+	}
+
+	principalId, err := azureutil.GetCurrentPrincipalId(ctx, e.userProfileService, tenantId)
+	if err == nil {
+		err = e.azCli.EnsureRoleAssignment(ctx, subscriptionId, vault.Id, keyVaultSecretsOfficerRole, principalId)
+	}
+	if err != nil {
+		e.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+		return "", false, fmt.Errorf("allowing the signed-in account to write the secrets of %s: %w", vaultName, err)
+	}
+	e.console.StopSpinner(ctx, spinnerMessage, input.StepDone)
+
+	// The vault is saved with the secret, it's reused by the next secrets
+	e.env.Values[environment.KeyVaultNameEnvVarName] = vault.Name
+	return vault.Name, true, nil
+}
+
+func getCmdEnvSetSecretHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Prompt for the value of a secret and store it in the key vault of the environment.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"The environment only keeps a reference to the secret, the value is read from the vault when a command"+
+					" needs it. Use %s for values which aren't secret.", output.WithHighLightFormat("azd env set"))),
+			formatHelpNote(fmt.Sprintf(
+				"The key vault is the vault named by %s. When it isn't set, a vault is created in the resource group of"+
+					" the environment after confirmation.", output.WithHighLightFormat(environment.KeyVaultNameEnvVarName))),
+		})
+}
+
+func getCmdEnvSetSecretHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Store the password of the database in the key vault of the environment.": output.WithHighLightFormat(
+			"azd env set-secret DATABASE_PASSWORD"),
+	})
+}
+
+func newEnvSelectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "select [<environment>]",
+		Short:             "Set the default environment.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: environmentNameArgCompletion,
+	}
+}
+
 type envSelectAction struct {
-	azdCtx *azdcontext.AzdContext
-	args   []string
+	azdCtx  *azdcontext.AzdContext
+	console input.Console
+	args    []string
 }
 
-func newEnvSelectAction(azdCtx *azdcontext.AzdContext, args []string) actions.Action {
+func newEnvSelectAction(azdCtx *azdcontext.AzdContext, console input.Console, args []string) actions.Action {
 	return &envSelectAction{
-		azdCtx: azdCtx,
-		args:   args,
+		azdCtx:  azdCtx,
+		console: console,
+		args:    args,
 	}
 }
 
 func (e *envSelectAction) Run(ctx context.Context) (*actions.ActionResult, error) {
-	if err := e.azdCtx.SetDefaultEnvironmentName(e.args[0]); err != nil {
+	envs, err := e.azdCtx.ListEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("listing environments: %w", err)
+	}
+
+	names := make([]string, 0, len(envs))
+	defaultName := ""
+	for _, env := range envs {
+		names = append(names, env.Name)
+		if env.IsDefault {
+			defaultName = env.Name
+		}
+	}
+
+	var name string
+	if len(e.args) > 0 {
+		name = e.args[0]
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf(
+				"environment '%s' does not exist, run `azd env list` to list the environments or `azd env new %s` "+
+					"to create it",
+				name,
+				name,
+			)
+		}
+	} else {
+		if len(names) == 0 {
+			return nil, errors.New("no environments found, run `azd env new` to create one")
+		}
+
+		options := input.ConsoleOptions{
+			Message: "Select an environment (type to filter)",
+			Options: names,
+		}
+		if defaultName != "" {
+			options.DefaultValue = defaultName
+		}
+
+		selected, err := e.console.Select(ctx, options)
+		if err != nil {
+			return nil, fmt.Errorf("selecting environment: %w", err)
+		}
+
+		name = names[selected]
+	}
+
+	if err := e.azdCtx.SetDefaultEnvironmentName(name); err != nil {
 		return nil, fmt.Errorf("setting default environment: %w", err)
 	}
 
-	return nil, nil
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("The default environment is now %s", output.WithHighLightFormat(name)),
+		},
+	}, nil
 }
 
 func newEnvListCmd() *cobra.Command {
@@ -304,11 +670,20 @@ func (en *envNewAction) Run(ctx context.Context) (*actions.ActionResult, error)
 }
 
 type envRefreshFlags struct {
-	global *internal.GlobalCommandOptions
+	subscription string
+	location     string
+	global       *internal.GlobalCommandOptions
 	envFlag
 }
 
 func (er *envRefreshFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&er.subscription,
+		"subscription",
+		"",
+		"ID of the Azure subscription the environment was provisioned to",
+	)
+	local.StringVarP(&er.location, "location", "l", "", "Azure location the environment was provisioned to")
 	er.envFlag.Bind(local, global)
 	er.global = global
 }
@@ -379,6 +754,28 @@ func newEnvRefreshAction(
 }
 
 func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Command title
+	ef.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     locale.Sprintf(locale.EnvRefreshTitle),
+		TitleNote: locale.Sprintf(locale.EnvRefreshTitleNote, output.WithHighLightFormat(ef.env.GetEnvName())),
+	})
+
+	// A freshly cloned project has no local environment values, the flags point the refresh at the existing
+	// deployment without prompting.
+	if ef.flags.subscription != "" || ef.flags.location != "" {
+		if ef.flags.subscription != "" {
+			ef.env.SetSubscriptionId(ef.flags.subscription)
+		}
+
+		if ef.flags.location != "" {
+			ef.env.SetLocation(ef.flags.location)
+		}
+
+		if err := ef.env.Save(); err != nil {
+			return nil, fmt.Errorf("saving environment: %w", err)
+		}
+	}
+
 	if err := ef.projectManager.Initialize(ctx, ef.projectConfig); err != nil {
 		return nil, err
 	}
@@ -401,9 +798,33 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		return nil, fmt.Errorf("creating provisioning manager: %w", err)
 	}
 
-	scope := infra.NewSubscriptionScope(ef.azCli, ef.env.GetLocation(), ef.env.GetSubscriptionId(), ef.env.GetEnvName())
+	// The outputs are the ones of the latest deployment of the environment, see provisioning.DeploymentOptions
+	deploymentName := ef.env.GetEnvName()
+	if ef.projectConfig.Infra.Provider != provisioning.Terraform {
+		latest, err := infra.NewAzureResourceManager(ef.azCli).FindLatestDeployment(
+			ctx, ef.env.GetSubscriptionId(), infra.DeploymentFilter{
+				EnvName:       ef.env.GetEnvName(),
+				ProjectName:   ef.projectConfig.Name,
+				ResourceGroup: ef.env.Getenv(environment.ResourceGroupEnvVarName),
+			})
+		if err == nil {
+			deploymentName = *latest.Name
+		} else if !errors.Is(err, azcli.ErrDeploymentNotFound) {
+			return nil, fmt.Errorf("finding the latest deployment: %w", err)
+		}
+	}
+
+	scope := infra.NewSubscriptionScope(ef.azCli, ef.env.GetLocation(), ef.env.GetSubscriptionId(), deploymentName)
 
 	getStateResult, err := infraManager.State(ctx, scope)
+	if errors.Is(err, azcli.ErrDeploymentNotFound) {
+		return nil, fmt.Errorf(
+			"no deployment found for environment '%s' in subscription '%s', run `azd provision` to provision it: %w",
+			ef.env.GetEnvName(),
+			ef.env.GetSubscriptionId(),
+			err,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("getting deployment: %w", err)
 	}
@@ -412,8 +833,6 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		return nil, err
 	}
 
-	ef.console.Message(ctx, "Environments setting refresh completed")
-
 	if ef.formatter.Kind() == output.JsonFormat {
 		err = ef.formatter.Format(provisioning.NewEnvRefreshResultFromState(getStateResult.State), ef.writer, nil)
 		if err != nil {
@@ -435,7 +854,165 @@ func (ef *envRefreshAction) Run(ctx context.Context) (*actions.ActionResult, err
 		}
 	}
 
-	return nil, nil
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Your environment has been refreshed with %d values from the latest deployment",
+				len(getStateResult.State.Outputs),
+			),
+			FollowUp: fmt.Sprintf(
+				"View the values of the environment with %s", output.WithHighLightFormat("azd env get-values"),
+			),
+		},
+	}, nil
+}
+
+func getCmdEnvRefreshHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Refresh environment settings by using information from a previous infrastructure provision.",
+		[]string{
+			formatHelpNote("The outputs of the latest deployment (ARM deployment or Terraform state) are written to " +
+				"the environment, nothing is provisioned."),
+			formatHelpNote(fmt.Sprintf("After cloning a project, run %s with the name of an existing environment to "+
+				"get working endpoints and connection settings.", output.WithHighLightFormat("azd env refresh"))),
+		})
+}
+
+func getCmdEnvRefreshHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Refresh the default environment.": output.WithHighLightFormat("azd env refresh"),
+		"Create the environment named 'dev' from its deployment, after cloning the project.": output.WithHighLightFormat(
+			"azd env refresh -e dev --subscription <subscription-id> --location <location>"),
+	})
+}
+
+type envAdoptFlags struct {
+	resourceGroup string
+	subscription  string
+	global        *internal.GlobalCommandOptions
+}
+
+func (f *envAdoptFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.resourceGroup,
+		"resource-group",
+		"",
+		"Name of the resource group provisioned for the environment",
+	)
+	local.StringVar(
+		&f.subscription,
+		"subscription",
+		"",
+		"ID of the Azure subscription of the resource group. Defaults to the default subscription",
+	)
+	f.global = global
+}
+
+func newEnvAdoptFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envAdoptFlags {
+	flags := &envAdoptFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvAdoptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "adopt",
+		Short: "Create an environment for resources provisioned by someone else, from the tags of their resource group.",
+	}
+}
+
+type envAdoptAction struct {
+	azdCtx         *azdcontext.AzdContext
+	azCli          azcli.AzCli
+	accountManager account.Manager
+	flags          *envAdoptFlags
+	console        input.Console
+}
+
+func newEnvAdoptAction(
+	azdCtx *azdcontext.AzdContext,
+	azCli azcli.AzCli,
+	accountManager account.Manager,
+	flags *envAdoptFlags,
+	console input.Console,
+) actions.Action {
+	return &envAdoptAction{
+		azdCtx:         azdCtx,
+		azCli:          azCli,
+		accountManager: accountManager,
+		flags:          flags,
+		console:        console,
+	}
+}
+
+func (ea *envAdoptAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if ea.flags.resourceGroup == "" {
+		return nil, errors.New("--resource-group must be set to the resource group provisioned for the environment")
+	}
+
+	subscriptionId := ea.flags.subscription
+	if subscriptionId == "" {
+		subscriptionId = ea.accountManager.GetDefaultSubscriptionID(ctx)
+	}
+
+	if subscriptionId == "" {
+		return nil, errors.New("no default subscription is set, set --subscription to the subscription of the resource group")
+	}
+
+	envName, resourceGroup, err := infra.NewAzureResourceManager(ea.azCli).FindEnvironmentOfResourceGroup(
+		ctx, subscriptionId, ea.flags.resourceGroup)
+	if err != nil {
+		return nil, fmt.Errorf("finding the environment of resource group '%s': %w", ea.flags.resourceGroup, err)
+	}
+
+	envSpec := environmentSpec{
+		environmentName: envName,
+		subscription:    subscriptionId,
+		location:        resourceGroup.Location,
+	}
+
+	env, err := createEnvironment(ctx, envSpec, ea.azdCtx, ea.console)
+	if err != nil {
+		return nil, fmt.Errorf("creating environment: %w", err)
+	}
+
+	env.Values[environment.ResourceGroupEnvVarName] = resourceGroup.Name
+	if err := env.Save(); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	if err := ea.azdCtx.SetDefaultEnvironmentName(env.GetEnvName()); err != nil {
+		return nil, fmt.Errorf("saving default environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Environment %s was created for resource group %s", env.GetEnvName(), resourceGroup.Name),
+			FollowUp: fmt.Sprintf(
+				"Load the outputs of its latest deployment with %s", output.WithHighLightFormat("azd env refresh")),
+		},
+	}, nil
+}
+
+func getCmdEnvAdoptHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Create an environment for resources provisioned by someone else, from the tags of their resource group.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("The name of the environment is read from the %s tag of the resource group, "+
+				"or of its resources when the resource group isn't tagged.",
+				output.WithHighLightFormat(infra.EnvNameTag))),
+			formatHelpNote("The environment becomes the default environment, its subscription, location and resource " +
+				"group are set, no .azure folder needs to be shared."),
+		})
+}
+
+func getCmdEnvAdoptHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Create the environment of the resources of resource group 'rg-dev', then load its outputs.": output.WithHighLightFormat(
+			"azd env adopt --resource-group rg-dev && azd env refresh"),
+	})
 }
 
 func newEnvGetValuesFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envGetValuesFlags {
@@ -498,6 +1075,226 @@ func (eg *envGetValuesAction) Run(ctx context.Context) (*actions.ActionResult, e
 	return nil, nil
 }
 
+func newEnvHistoryFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envHistoryFlags {
+	flags := &envHistoryFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "Show the operations which changed the environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envHistoryFlags struct {
+	envFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (eh *envHistoryFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	eh.envFlag.Bind(local, global)
+	eh.global = global
+}
+
+type envHistoryAction struct {
+	lazyEnv   *lazy.Lazy[*environment.Environment]
+	formatter output.Formatter
+	writer    io.Writer
+}
+
+func newEnvHistoryAction(
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envHistoryFlags,
+) actions.Action {
+	return &envHistoryAction{
+		lazyEnv:   lazyEnv,
+		formatter: formatter,
+		writer:    writer,
+	}
+}
+
+func (eh *envHistoryAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	env, err := eh.lazyEnv.GetValue()
+	if err != nil {
+		return nil, fmt.Errorf("loading environment: %w", err)
+	}
+
+	entries, err := audit.NewLog(env.Root).Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	// Most recent operations first
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	for i := range entries {
+		entries[i].Duration = entries[i].Duration.Round(time.Second)
+	}
+
+	if eh.formatter.Kind() == output.TableFormat {
+		if len(entries) == 0 {
+			fmt.Fprintf(eh.writer, "No operations recorded for environment %s.\n", env.GetEnvName())
+			return nil, nil
+		}
+
+		columns := []output.Column{
+			{
+				Heading:       "TIME",
+				ValueTemplate: `{{.Timestamp.Local.Format "2006-01-02 15:04:05"}}`,
+			},
+			{
+				Heading:       "COMMAND",
+				ValueTemplate: "{{.Command}}",
+			},
+			{
+				Heading:       "OUTCOME",
+				ValueTemplate: "{{.Outcome}}",
+			},
+			{
+				Heading:       "DURATION",
+				ValueTemplate: "{{.Duration}}",
+			},
+			{
+				Heading:       "PRINCIPAL",
+				ValueTemplate: "{{.Principal}}",
+			},
+			{
+				Heading:       "USER",
+				ValueTemplate: "{{.User}}",
+			},
+		}
+
+		err = eh.formatter.Format(entries, eh.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = eh.formatter.Format(entries, eh.writer, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func getCmdEnvHistoryHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Show the operations which changed the environment, most recent first.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Provision, deploy, up, down, pipeline config, env set and env refresh are "+
+				"recorded in the %s file of the environment, along with the user, the Azure principal and the outcome.",
+				output.WithLinkFormat(".azure/<environment-name>/"+audit.FileName))),
+			formatHelpNote("Only a digest of the arguments and flags of each command is recorded, since they may " +
+				"contain secrets. The digest is keyed per azd install, operations run on the same machine with the " +
+				"same inputs have the same digest."),
+		})
+}
+
+func newEnvUnlockFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envUnlockFlags {
+	flags := &envUnlockFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvUnlockCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock",
+		Short: "Remove the lock of the environment left by an operation which didn't complete.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envUnlockFlags struct {
+	envFlag
+	force  bool
+	global *internal.GlobalCommandOptions
+}
+
+func (eu *envUnlockFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	eu.envFlag.Bind(local, global)
+	local.BoolVar(
+		&eu.force,
+		"force",
+		false,
+		"Removes the lock, even though the operation holding it may still be running.",
+	)
+	eu.global = global
+}
+
+type envUnlockAction struct {
+	lazyEnv *lazy.Lazy[*environment.Environment]
+	flags   *envUnlockFlags
+}
+
+func newEnvUnlockAction(lazyEnv *lazy.Lazy[*environment.Environment], flags *envUnlockFlags) actions.Action {
+	return &envUnlockAction{
+		lazyEnv: lazyEnv,
+		flags:   flags,
+	}
+}
+
+func (eu *envUnlockAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	env, err := eu.lazyEnv.GetValue()
+	if err != nil {
+		return nil, fmt.Errorf("loading environment: %w", err)
+	}
+
+	envLock := lock.New(env.Root)
+	holder, err := envLock.Holder()
+	if err != nil {
+		return nil, err
+	}
+
+	if holder == nil {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("The environment %s isn't locked", output.WithHighLightFormat(env.GetEnvName())),
+			},
+		}, nil
+	}
+
+	if !eu.flags.force {
+		return nil, fmt.Errorf(
+			"the environment %s is locked by %s. Run 'azd env unlock --force' to remove the lock when the operation "+
+				"isn't running anymore",
+			env.GetEnvName(),
+			holder.String())
+	}
+
+	if err := envLock.Remove(); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Removed the lock of the environment %s held by %s",
+				output.WithHighLightFormat(env.GetEnvName()), holder.String()),
+		},
+	}, nil
+}
+
+func getCmdEnvUnlockHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Remove the lock of the environment left by an operation which didn't complete, like a process which was killed.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Provision, deploy, up and down lock the environment while they run, with the "+
+				"%s file of the environment, so two operations can't change the same environment concurrently.",
+				output.WithLinkFormat(".azure/<environment-name>/"+lock.FileName))),
+			formatHelpNote(fmt.Sprintf("The lock is removed only with %s, check the operation holding it isn't "+
+				"running anymore first.",
+				output.WithHighLightFormat("--force"))),
+		})
+}
+
 func getCmdEnvHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		"Manage your application environments. With this command group, you can create a new environment or get, set,"+
@@ -513,3 +1310,17 @@ func getCmdEnvHelpDescription(*cobra.Command) string {
 				output.WithLinkFormat(".azure/<environment-name>/.env"))),
 		})
 }
+
+func getCmdEnvSelectHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Set the default environment, used by commands when no environment is specified.",
+		[]string{
+			formatHelpNote("When no environment is specified, you select it from a list of the environments of " +
+				"the project. Type to filter the list, the characters only need to appear in order (ex: prd for " +
+				"contoso-prod)."),
+			formatHelpNote(fmt.Sprintf("The %s flag and the %s environment variable override the default "+
+				"environment for a single command.",
+				output.WithHighLightFormat("--environment"),
+				output.WithHighLightFormat(environment.EnvNameEnvVarName))),
+		})
+}
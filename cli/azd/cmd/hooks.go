@@ -0,0 +1,219 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func hooksActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("hooks", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "hooks",
+			Short: "Develop, test and run hooks for an application.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
+	group.Add("run", &actions.ActionDescriptorOptions{
+		Command:        newHooksRunCmd(),
+		FlagsResolver:  newHooksRunFlags,
+		ActionResolver: newHooksRunAction,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdHooksRunHelpDescription,
+			Footer:      getCmdHooksRunHelpFooter,
+		},
+	}).AddFlagCompletion("service", func(
+		cmd *cobra.Command,
+		args []string,
+		toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		return serviceNameCompletion(cmd, nil, toComplete)
+	})
+
+	return group
+}
+
+type hooksRunFlags struct {
+	global  *internal.GlobalCommandOptions
+	service string
+	envFlag
+}
+
+func (f *hooksRunFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.service, "service", "", "Only runs hooks for the specified service.")
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newHooksRunFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *hooksRunFlags {
+	flags := &hooksRunFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newHooksRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <name>",
+		Short: "Runs the specified hook for the project and services",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type hooksRunAction struct {
+	projectConfig *project.ProjectConfig
+	env           *environment.Environment
+	commandRunner exec.CommandRunner
+	console       input.Console
+	azCli         azcli.AzCli
+	flags         *hooksRunFlags
+	args          []string
+}
+
+func newHooksRunAction(
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	azCli azcli.AzCli,
+	flags *hooksRunFlags,
+	args []string,
+) actions.Action {
+	return &hooksRunAction{
+		projectConfig: projectConfig,
+		env:           env,
+		commandRunner: commandRunner,
+		console:       console,
+		azCli:         azCli,
+		flags:         flags,
+		args:          args,
+	}
+}
+
+func (hra *hooksRunAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	hookName := hra.args[0]
+
+	// Command title
+	hra.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Running hooks (azd hooks run)",
+		TitleNote: fmt.Sprintf(
+			"Finding and executing %s hooks for environment %s",
+			output.WithHighLightFormat(hookName),
+			output.WithHighLightFormat(hra.env.GetEnvName()),
+		),
+	})
+
+	if hra.flags.service != "" {
+		if _, has := hra.projectConfig.Services[hra.flags.service]; !has {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", hra.flags.service)
+		}
+	}
+
+	hooksFound := false
+
+	// Project level hooks run when no specific service is requested
+	if hra.flags.service == "" {
+		found, err := hra.runHook(ctx, hookName, "project", "", hra.projectConfig.Path, hra.projectConfig.Hooks)
+		if err != nil {
+			return nil, err
+		}
+
+		hooksFound = hooksFound || found
+	}
+
+	for _, service := range hra.projectConfig.GetServicesStable() {
+		if hra.flags.service != "" && service.Name != hra.flags.service {
+			continue
+		}
+
+		found, err := hra.runHook(ctx, hookName, service.Name, service.Name, service.Path(), service.Hooks)
+		if err != nil {
+			return nil, err
+		}
+
+		hooksFound = hooksFound || found
+	}
+
+	if !hooksFound {
+		return nil, fmt.Errorf("hook '%s' is not defined in %s", hookName, azdcontext.ProjectFileName)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "Your hooks have been run successfully",
+		},
+	}, nil
+}
+
+// runHook runs the hook with the specified name when it's defined in hooks, returning whether the hook was found.
+// serviceName is empty for the hooks of the project.
+func (hra *hooksRunAction) runHook(
+	ctx context.Context,
+	hookName string,
+	scope string,
+	serviceName string,
+	cwd string,
+	hooks map[string]*ext.HookConfig,
+) (bool, error) {
+	if len(hooks) == 0 {
+		return false, nil
+	}
+
+	hooksManager := ext.NewHooksManager(cwd)
+	hooksRunner := ext.NewHooksRunner(hooksManager, hra.commandRunner, hra.console, hra.azCli, cwd, hooks, hra.env)
+	if serviceName != "" {
+		hooksRunner = hooksRunner.ForService(serviceName, "")
+	}
+
+	err := hooksRunner.RunHook(ctx, hookName)
+	if errors.Is(err, ext.ErrHookNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return true, fmt.Errorf("running %s hook for %s: %w", hookName, scope, err)
+	}
+
+	return true, nil
+}
+
+func getCmdHooksRunHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Runs the specified hook for the project and services.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Hooks are defined in the %s file of the project, or of each service, and run "+
+				"with the values of the environment loaded.", azdcontext.ProjectFileName)),
+			formatHelpNote(fmt.Sprintf("When %s is set, only the hook of the specified service is run.",
+				output.WithHighLightFormat("--service"))),
+		})
+}
+
+func getCmdHooksRunHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run the 'seed-database' hook of the project and all services.": output.WithHighLightFormat(
+			"azd hooks run seed-database"),
+		"Run the 'predeploy' hook of the service named 'api'.": output.WithHighLightFormat(
+			"azd hooks run predeploy --service api"),
+	})
+}
@@ -11,11 +11,13 @@ import (
 )
 
 func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
-	//deprecate:cmd hide infra
+	// infra create and infra delete are deprecated and hidden, replaced by azd provision and azd down
 	group := root.Add("infra", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
-			Short:  "Manage your Azure infrastructure.",
-			Hidden: true,
+			Short: "Manage your Azure infrastructure.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupManage,
 		},
 	})
 
@@ -24,6 +26,8 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 			Command:        newInfraCreateCmd(),
 			FlagsResolver:  newInfraCreateFlags,
 			ActionResolver: newInfraCreateAction,
+			RequireLogin:   true,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 		}).
@@ -34,10 +38,32 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 			Command:        newInfraDeleteCmd(),
 			FlagsResolver:  newInfraDeleteFlags,
 			ActionResolver: newInfraDeleteAction,
+			RequireLogin:   true,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
+	group.Add("gen-params", &actions.ActionDescriptorOptions{
+		Command:        newInfraGenParamsCmd(),
+		FlagsResolver:  newInfraGenParamsFlags,
+		ActionResolver: newInfraGenParamsAction,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdInfraGenParamsHelpDescription,
+			Footer:      getCmdInfraGenParamsHelpFooter,
+		},
+	})
+
+	group.Add("synth", &actions.ActionDescriptorOptions{
+		Command:        newInfraSynthCmd(),
+		ActionResolver: newInfraSynthAction,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdInfraSynthHelpDescription,
+		},
+	})
+
 	return group
 }
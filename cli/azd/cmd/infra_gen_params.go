@@ -0,0 +1,163 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type infraGenParamsFlags struct {
+	importPath string
+	global     *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *infraGenParamsFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.importPath,
+		"import",
+		"",
+		"Imports the values of an existing parameters file into the environment.",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newInfraGenParamsFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraGenParamsFlags {
+	flags := &infraGenParamsFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newInfraGenParamsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "gen-params",
+		Short: "Generate the Bicep parameters file from the environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type infraGenParamsAction struct {
+	flags          *infraGenParamsFlags
+	azdCtx         *azdcontext.AzdContext
+	projectConfig  *project.ProjectConfig
+	env            *environment.Environment
+	parametersSync *bicep.ParametersSync
+	console        input.Console
+}
+
+func newInfraGenParamsAction(
+	flags *infraGenParamsFlags,
+	azdCtx *azdcontext.AzdContext,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	parametersSync *bicep.ParametersSync,
+	console input.Console,
+) actions.Action {
+	return &infraGenParamsAction{
+		flags:          flags,
+		azdCtx:         azdCtx,
+		projectConfig:  projectConfig,
+		env:            env,
+		parametersSync: parametersSync,
+		console:        console,
+	}
+}
+
+func (a *infraGenParamsAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	infraOptions := a.projectConfig.Infra
+	if infraOptions.Provider != "" && infraOptions.Provider != provisioning.Bicep {
+		return nil, fmt.Errorf("generating parameters is only supported for Bicep, the project uses '%s'",
+			infraOptions.Provider)
+	}
+
+	modulePath, parametersFilePath := bicep.ModuleFilePaths(a.azdCtx.ProjectDirectory(), infraOptions)
+	relativePath, err := filepath.Rel(a.azdCtx.ProjectDirectory(), parametersFilePath)
+	if err != nil {
+		relativePath = parametersFilePath
+	}
+
+	if a.flags.importPath != "" {
+		result, err := a.parametersSync.Import(ctx, a.flags.importPath, parametersFilePath, a.env)
+		if err != nil {
+			return nil, fmt.Errorf("importing parameters: %w", err)
+		}
+
+		if err := a.env.Save(); err != nil {
+			return nil, fmt.Errorf("saving environment: %w", err)
+		}
+
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("Imported %d parameter values into environment %s and updated %s.",
+					len(result.Imported), a.env.GetEnvName(), relativePath),
+				FollowUp: formatParameterList("Environment values", result.Imported),
+			},
+		}, nil
+	}
+
+	result, err := a.parametersSync.Generate(ctx, modulePath, parametersFilePath, a.env)
+	if err != nil {
+		return nil, fmt.Errorf("generating parameters: %w", err)
+	}
+
+	followUp := strings.TrimSpace(strings.Join([]string{
+		formatParameterList("Added", result.Added),
+		formatParameterList("Removed", result.Removed),
+	}, "\n"))
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header:   fmt.Sprintf("Generated %s from the parameters of %s.", relativePath, filepath.Base(modulePath)),
+			FollowUp: followUp,
+		},
+	}, nil
+}
+
+func formatParameterList(title string, names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s: %s", title, output.WithHighLightFormat(strings.Join(names, ", ")))
+}
+
+func getCmdInfraGenParamsHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Generate the Bicep parameters file of the project from the parameters of its template.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Parameters reference their environment value, like %s for location.",
+				output.WithHighLightFormat("${AZURE_LOCATION}"))),
+			formatHelpNote("Object and array parameters keep literal values, which can't be read from the environment."),
+			formatHelpNote("Parameters the template no longer declares are removed."),
+		},
+	)
+}
+
+func getCmdInfraGenParamsHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Generate the parameters file from the parameters of the template.": output.WithHighLightFormat(
+			"azd infra gen-params",
+		),
+		"Import the values of an existing parameters file into the environment.": output.WithHighLightFormat(
+			"azd infra gen-params --import <parameters-file>",
+		),
+	})
+}
@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/synth"
+	"github.com/spf13/cobra"
+)
+
+func newInfraSynthCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "synth",
+		Short: "Generate the Bicep infrastructure of the services of azure.yaml.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type infraSynthAction struct {
+	azdCtx        *azdcontext.AzdContext
+	projectConfig *project.ProjectConfig
+}
+
+func newInfraSynthAction(azdCtx *azdcontext.AzdContext, projectConfig *project.ProjectConfig) actions.Action {
+	return &infraSynthAction{
+		azdCtx:        azdCtx,
+		projectConfig: projectConfig,
+	}
+}
+
+func (a *infraSynthAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	infraOptions := a.projectConfig.Infra
+	if infraOptions.Provider != "" && infraOptions.Provider != provisioning.Bicep {
+		return nil, fmt.Errorf("generating infrastructure is only supported for Bicep, the project uses '%s'",
+			infraOptions.Provider)
+	}
+
+	modulePath, _ := bicep.ModuleFilePaths(a.azdCtx.ProjectDirectory(), infraOptions)
+	infraDir := filepath.Dir(modulePath)
+	relativePath, err := filepath.Rel(a.azdCtx.ProjectDirectory(), infraDir)
+	if err != nil {
+		relativePath = infraDir
+	}
+
+	if err := synth.Generate(infraDir, a.projectConfig); errors.Is(err, os.ErrExist) {
+		return nil, fmt.Errorf(
+			"the project already has infrastructure in %s, remove the directory to generate it again", relativePath)
+	} else if err != nil {
+		return nil, fmt.Errorf("generating infrastructure: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Generated the infrastructure of %d services in %s.",
+				len(a.projectConfig.Services), relativePath),
+			FollowUp: fmt.Sprintf("Review the generated files, then run %s to provision it.",
+				output.WithHighLightFormat("azd provision")),
+		},
+	}, nil
+}
+
+func getCmdInfraSynthHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Generate baseline Bicep infrastructure for the services of azure.yaml, for projects without infrastructure.",
+		[]string{
+			formatHelpNote("App Service, Function App, Container App and Static Web App services get the resources " +
+				"hosting them, with Application Insights monitoring and a Key Vault their identities can read."),
+			formatHelpNote("Services with other hosts are listed in a comment of resources.bicep, to be added by hand."),
+			formatHelpNote("The infrastructure directory must not exist."),
+		},
+	)
+}
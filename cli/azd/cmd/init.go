@@ -8,23 +8,35 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/repository"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/adopt"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/compose"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 )
 
 func newInitFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *initFlags {
@@ -42,11 +54,14 @@ func newInitCmd() *cobra.Command {
 }
 
 type initFlags struct {
-	template       templates.Template
-	templateBranch string
-	subscription   string
-	location       string
-	global         *internal.GlobalCommandOptions
+	template          templates.Template
+	templateBranch    string
+	templatePath      string
+	fromResourceGroup string
+	subscription      string
+	location          string
+	devContainer      bool
+	global            *internal.GlobalCommandOptions
 	envFlag
 }
 
@@ -60,6 +75,19 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		"The template to use when you initialize the project. You can use Full URI, <owner>/<repository>, or <repository> if it's part of the azure-samples organization.",
 	)
 	local.StringVarP(&i.templateBranch, "branch", "b", "", "The template branch to initialize from.")
+	local.StringVar(&i.templateBranch, "ref", "", "The template branch or tag to initialize from.")
+	local.StringVar(
+		&i.templatePath,
+		"path",
+		"",
+		"The directory of the template repository to initialize from, to use a template of a repository of templates.",
+	)
+	local.StringVar(
+		&i.fromResourceGroup,
+		"from-resource-group",
+		"",
+		"Initializes the project from the resources deployed to an existing resource group.",
+	)
 	local.StringVar(
 		&i.subscription,
 		"subscription",
@@ -67,6 +95,12 @@ func (i *initFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOpt
 		"Name or ID of an Azure subscription to use for the new environment",
 	)
 	local.StringVarP(&i.location, "location", "l", "", "Azure location for the new environment")
+	local.BoolVar(
+		&i.devContainer,
+		"devcontainer",
+		false,
+		"Adds a dev container configuration which preinstalls azd and the tools required by the project.",
+	)
 	i.envFlag.Bind(local, global)
 
 	i.global = global
@@ -76,6 +110,8 @@ type initAction struct {
 	console         input.Console
 	cmdRun          exec.CommandRunner
 	gitCli          git.GitCli
+	azCli           azcli.AzCli
+	accountManager  account.Manager
 	flags           *initFlags
 	repoInitializer *repository.Initializer
 }
@@ -84,12 +120,16 @@ func newInitAction(
 	cmdRun exec.CommandRunner,
 	console input.Console,
 	gitCli git.GitCli,
+	azCli azcli.AzCli,
+	accountManager account.Manager,
 	flags *initFlags,
 	repoInitializer *repository.Initializer) actions.Action {
 	return &initAction{
 		console:         console,
 		cmdRun:          cmdRun,
 		gitCli:          gitCli,
+		azCli:           azCli,
+		accountManager:  accountManager,
 		flags:           flags,
 		repoInitializer: repoInitializer,
 	}
@@ -103,8 +143,12 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	azdCtx := azdcontext.NewAzdContextWithDirectory(wd)
 
-	if i.flags.templateBranch != "" && i.flags.template.Name == "" {
-		return nil, errors.New("template name required when specifying a branch name")
+	if (i.flags.templateBranch != "" || i.flags.templatePath != "") && i.flags.template.Name == "" {
+		return nil, errors.New(locale.Sprintf(locale.InitBranchWithoutTemplate))
+	}
+
+	if i.flags.fromResourceGroup != "" && i.flags.template.Name != "" {
+		return nil, errors.New(locale.Sprintf(locale.InitTemplateAndResourceGroup))
 	}
 
 	// init now requires git all the time, even for empty template, azd initializes a local git project
@@ -114,14 +158,26 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	// Command title
 	i.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title: "Initializing a new project (azd init)",
+		Title: locale.Sprintf(locale.InitTitle),
 	})
 
+	if i.flags.fromResourceGroup != "" {
+		return i.initFromResourceGroup(ctx, azdCtx)
+	}
+
 	// Project not initialized and no template specified
 	// NOTE: Adding `azure.yaml` to a folder removes the option from selecting a template
+	var app *compose.Application
 	if _, err := os.Stat(azdCtx.ProjectPath()); err != nil && errors.Is(err, os.ErrNotExist) {
-
+		// Projects with a docker compose file or an Aspire manifest are initialized from their services
 		if i.flags.template.Name == "" {
+			app, err = i.promptApplication(ctx, azdCtx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if i.flags.template.Name == "" && app == nil {
 			i.flags.template, err = templates.PromptTemplate(ctx, "Select a project template:", i.console)
 
 			if err != nil {
@@ -154,10 +210,22 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			}
 		}
 
-		err = i.repoInitializer.Initialize(ctx, azdCtx, templateUrl, i.flags.templateBranch)
+		err = i.repoInitializer.Initialize(
+			ctx,
+			azdCtx,
+			templateUrl,
+			i.flags.templateBranch,
+			i.flags.templatePath,
+			// An input of the template named location takes the location of the new environment
+			map[string]string{"location": i.flags.location})
 		if err != nil {
 			return nil, fmt.Errorf("init from template repository: %w", err)
 		}
+	} else if app != nil {
+		err = i.repoInitializer.InitializeFromApplication(ctx, azdCtx, app)
+		if err != nil {
+			return nil, fmt.Errorf("init from %s: %w", filepath.Base(app.Source), err)
+		}
 	} else {
 		if _, err := os.Stat(azdCtx.ProjectPath()); errors.Is(err, os.ErrNotExist) {
 			err = i.repoInitializer.InitializeEmpty(ctx, azdCtx)
@@ -167,6 +235,12 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 	}
 
+	if i.flags.devContainer {
+		if err := i.scaffoldDevContainer(ctx, azdCtx); err != nil {
+			return nil, err
+		}
+	}
+
 	envName, err := azdCtx.GetDefaultEnvironmentName()
 	if err != nil {
 		return nil, fmt.Errorf("retrieving default environment name: %w", err)
@@ -196,7 +270,7 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: "New project initialized!",
+			Header: locale.Sprintf(locale.InitSuccess),
 			FollowUp: heredoc.Docf(`
 			You can view the template code in your directory: %s
 			Learn more about running 3rd party code on our DevHub: %s`,
@@ -206,6 +280,284 @@ func (i *initAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}, nil
 }
 
+// initFromResourceGroup initializes the project from the resources of an existing resource group, and tags the
+// resources so azd finds them when deploying the services.
+func (i *initAction) initFromResourceGroup(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+) (*actions.ActionResult, error) {
+	resourceGroup := i.flags.fromResourceGroup
+
+	if _, err := os.Stat(azdCtx.ProjectPath()); err == nil {
+		return nil, errors.New(locale.Sprintf(locale.InitAlreadyInitialized, azdcontext.ProjectFileName))
+	}
+
+	envName, err := azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return nil, fmt.Errorf("retrieving default environment name: %w", err)
+	}
+
+	if envName != "" {
+		return nil, environment.NewEnvironmentInitError(envName)
+	}
+
+	envSpec := environmentSpec{
+		environmentName: i.flags.environmentName,
+		subscription:    i.flags.subscription,
+		location:        i.flags.location,
+	}
+
+	env, err := createEnvironment(ctx, envSpec, azdCtx, i.console)
+	if err != nil {
+		return nil, fmt.Errorf("loading environment: %w", err)
+	}
+
+	if err := provisioning.EnsureSubscriptionAndLocation(ctx, i.console, env, i.accountManager); err != nil {
+		return nil, err
+	}
+
+	var resources []azcli.AzCliResourceExtended
+	spinnerMessage := fmt.Sprintf("Inspecting resource group %s", resourceGroup)
+	err = input.RunWithSpinner(ctx, i.console, spinnerMessage, func(ctx context.Context) error {
+		resources, err = adopt.Discover(ctx, i.azCli, env.GetSubscriptionId(), resourceGroup)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("resource group %s has no resources", resourceGroup)
+	}
+
+	services := adopt.Services(resources)
+	for _, service := range services {
+		language, err := i.promptServiceLanguage(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+
+		service.Language = language
+	}
+
+	err = i.repoInitializer.InitializeFromResources(ctx, azdCtx, resourceGroup, resources, services)
+	if err != nil {
+		return nil, fmt.Errorf("init from resource group %s: %w", resourceGroup, err)
+	}
+
+	if err := i.tagResources(ctx, env, resourceGroup, services); err != nil {
+		return nil, err
+	}
+
+	env.Values[environment.ResourceGroupEnvVarName] = resourceGroup
+	if err := env.Save(); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	if err := azdCtx.SetDefaultEnvironmentName(env.GetEnvName()); err != nil {
+		return nil, fmt.Errorf("saving default environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: locale.Sprintf(locale.InitResourceGroupSuccess, resourceGroup),
+			FollowUp: heredoc.Docf(`
+			Move the code of each service to its project directory in %s, then run %s to deploy it.
+			The existing resources are referenced in %s.`,
+				output.WithHighLightFormat(azdcontext.ProjectFileName),
+				output.WithHighLightFormat("azd deploy"),
+				output.WithLinkFormat("%s", filepath.Join(azdCtx.ProjectDirectory(), "infra"))),
+		},
+	}, nil
+}
+
+// promptServiceLanguage asks for the language of the code of a service, which azd can't tell from its resource.
+func (i *initAction) promptServiceLanguage(
+	ctx context.Context,
+	service *adopt.Service,
+) (project.ServiceLanguageKind, error) {
+	languages := []project.ServiceLanguageKind{
+		project.ServiceLanguageDotNet,
+		project.ServiceLanguageJavaScript,
+		project.ServiceLanguageTypeScript,
+		project.ServiceLanguagePython,
+		project.ServiceLanguageJava,
+		project.ServiceLanguageGo,
+	}
+
+	options := make([]string, len(languages))
+	for index, language := range languages {
+		options[index] = string(language)
+	}
+
+	selected, err := i.console.Select(ctx, input.ConsoleOptions{
+		Message: fmt.Sprintf(
+			"Select the language of service %s (%s %s):",
+			service.Name,
+			service.Host,
+			service.Resource.Name,
+		),
+		Options:      options,
+		DefaultValue: options[0],
+	})
+	if err != nil {
+		return "", fmt.Errorf("prompting for the language of service %s: %w", service.Name, err)
+	}
+
+	return languages[selected], nil
+}
+
+// tagResources tags the resource group with the environment, and the resources of the services with their service
+// name, after confirmation, so azd finds them without the resource names in azure.yaml.
+func (i *initAction) tagResources(
+	ctx context.Context,
+	env *environment.Environment,
+	resourceGroup string,
+	services []*adopt.Service,
+) error {
+	confirm, err := i.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Tag the resources of %s so azd can find them?", resourceGroup),
+		DefaultValue: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !confirm {
+		i.console.Message(ctx, fmt.Sprintf(
+			"Skipping tags, add the %s tag to the resources of the services for azd to find them.", adopt.ServiceTag))
+		return nil
+	}
+
+	subscriptionId := env.GetSubscriptionId()
+	err = i.azCli.UpdateResourceTags(
+		ctx,
+		subscriptionId,
+		azure.ResourceGroupRID(subscriptionId, resourceGroup),
+		map[string]string{infra.EnvNameTag: env.GetEnvName()},
+	)
+	if err != nil {
+		return fmt.Errorf("tagging resource group %s: %w", resourceGroup, err)
+	}
+
+	for _, service := range services {
+		if service.Resource.Tags[adopt.ServiceTag] == service.Name {
+			continue
+		}
+
+		err := i.azCli.UpdateResourceTags(
+			ctx, subscriptionId, service.Resource.Id, map[string]string{adopt.ServiceTag: service.Name})
+		if err != nil {
+			return fmt.Errorf("tagging resource %s: %w", service.Resource.Name, err)
+		}
+	}
+
+	i.console.MessageUxItem(ctx, &ux.DoneMessage{Message: "Tagged the resources for azd"})
+	return nil
+}
+
+// promptApplication offers to initialize the project from its docker compose file or Aspire manifest, and returns the
+// application read from it, or nil when the project has neither or the user declines.
+func (i *initAction) promptApplication(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+) (*compose.Application, error) {
+	filePath, err := compose.Detect(azdCtx.ProjectDirectory())
+	if err != nil {
+		return nil, fmt.Errorf("detecting compose file: %w", err)
+	}
+
+	if filePath == "" {
+		return nil, nil
+	}
+
+	app, err := compose.Load(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	confirm, err := i.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Initialize the project from the services of %s?", filepath.Base(filePath)),
+		DefaultValue: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !confirm {
+		return nil, nil
+	}
+
+	for _, warning := range app.Warnings {
+		i.console.Message(ctx, output.WithWarningFormat("WARNING: %s", warning))
+	}
+
+	return app, nil
+}
+
+// scaffoldDevContainer adds a devcontainer.json to the project which installs the tools needed by its services and
+// infrastructure provider. An existing dev container configuration is kept.
+func (i *initAction) scaffoldDevContainer(ctx context.Context, azdCtx *azdcontext.AzdContext) error {
+	projectConfig, err := project.Load(ctx, azdCtx.ProjectPath())
+	if err != nil {
+		return fmt.Errorf("loading project: %w", err)
+	}
+
+	config := devcontainer.NewConfig(projectConfig.Name, devContainerTools(projectConfig))
+	if err := config.Save(azdCtx.ProjectDirectory()); errors.Is(err, os.ErrExist) {
+		i.console.Message(ctx, fmt.Sprintf("Skipping dev container, %s already exists.", devcontainer.ConfigPath))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	i.console.Message(ctx, fmt.Sprintf("Created dev container configuration %s.", devcontainer.ConfigPath))
+	return nil
+}
+
+// devContainerTools returns the tools the project needs in its dev container.
+func devContainerTools(projectConfig *project.ProjectConfig) []devcontainer.Tool {
+	var required []devcontainer.Tool
+	add := func(tool devcontainer.Tool) {
+		if !slices.Contains(required, tool) {
+			required = append(required, tool)
+		}
+	}
+
+	if projectConfig.Infra.Provider == provisioning.Terraform {
+		add(devcontainer.ToolTerraform)
+	}
+
+	for _, svc := range projectConfig.GetServicesStable() {
+		switch svc.Language {
+		case project.ServiceLanguageDotNet, project.ServiceLanguageCsharp, project.ServiceLanguageFsharp:
+			add(devcontainer.ToolDotNet)
+		case project.ServiceLanguageJavaScript, project.ServiceLanguageTypeScript:
+			add(devcontainer.ToolNode)
+		case project.ServiceLanguagePython:
+			add(devcontainer.ToolPython)
+		case project.ServiceLanguageJava:
+			add(devcontainer.ToolJava)
+		case project.ServiceLanguageGo:
+			add(devcontainer.ToolGo)
+		case project.ServiceLanguageDocker:
+			add(devcontainer.ToolDocker)
+		}
+
+		switch svc.Host {
+		case project.ContainerAppTarget:
+			add(devcontainer.ToolDocker)
+		case project.AksTarget:
+			add(devcontainer.ToolDocker)
+			add(devcontainer.ToolKubectl)
+		case project.StaticWebAppTarget:
+			add(devcontainer.ToolNode)
+		}
+	}
+
+	return required
+}
+
 func getCmdInitHelpDescription(c *cobra.Command) string {
 	return generateCmdHelpDescription("Initialize a new application in your current directory.",
 		getCmdHelpDescriptionNoteForInit(c))
@@ -217,6 +569,23 @@ func getCmdInitHelpFooter(*cobra.Command) string {
 			output.WithHighLightFormat("azd init --template"),
 			output.WithWarningFormat("[GitHub repo URL]"),
 		),
+		"Initialize a project from the resources deployed to an existing resource group.": fmt.Sprintf("%s %s",
+			output.WithHighLightFormat("azd init --from-resource-group"),
+			output.WithWarningFormat("[Resource group name]"),
+		),
+		"Initialize a template and add a dev container configuration for it.": fmt.Sprintf("%s %s %s",
+			output.WithHighLightFormat("azd init --template"),
+			output.WithWarningFormat("[GitHub repo URL]"),
+			output.WithHighLightFormat("--devcontainer"),
+		),
+		"Initialize a template from a tag and a directory of a repository of templates.": fmt.Sprintf("%s %s %s %s %s %s",
+			output.WithHighLightFormat("azd init --template"),
+			output.WithWarningFormat("[GitHub repo URL]"),
+			output.WithHighLightFormat("--ref"),
+			output.WithWarningFormat("[Tag name]"),
+			output.WithHighLightFormat("--path"),
+			output.WithWarningFormat("[Template directory]"),
+		),
 		"Initialize a template to your current local directory from a branch other than main.": fmt.Sprintf("%s %s %s %s",
 			output.WithHighLightFormat("azd init --template"),
 			output.WithWarningFormat("[GitHub repo URL]"),
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os/user"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/audit"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/spf13/pflag"
+)
+
+// Records the commands changing an environment in the audit log of the environment
+type AuditMiddleware struct {
+	options     *Options
+	lazyEnv     *lazy.Lazy[*environment.Environment]
+	authManager *auth.Manager
+}
+
+// Creates a new instance of the Audit middleware
+func NewAuditMiddleware(
+	options *Options,
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	authManager *auth.Manager,
+) Middleware {
+	return &AuditMiddleware{
+		options:     options,
+		lazyEnv:     lazyEnv,
+		authManager: authManager,
+	}
+}
+
+// Runs the action and appends its outcome to the audit log. Child actions, like the provision and deploy actions run by
+// `azd up`, are recorded as part of the command which ran them.
+func (m *AuditMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	result, err := next(ctx)
+
+	// The environment is resolved after the action runs, since the action may create it
+	env, envErr := m.lazyEnv.GetValue()
+	if envErr != nil || env.Root == "" {
+		log.Printf("environment is not available, skipping audit of '%s'", m.options.CommandPath)
+		return result, err
+	}
+
+	entry := audit.Entry{
+		Timestamp: start.UTC(),
+		Command:   m.options.CommandPath,
+		Outcome:   audit.OutcomeSucceeded,
+		Duration:  time.Since(start),
+		Version:   internal.VersionInfo().Version.String(),
+	}
+
+	// Without the key, the digest is left out rather than recorded unkeyed, which would expose short secrets
+	if key, keyErr := audit.DigestKey(); keyErr == nil {
+		entry.InputsDigest = audit.InputsDigest(key, m.options.CommandPath, m.options.Args, m.changedFlags())
+	} else {
+		log.Printf("digest key is not available, skipping the inputs digest of '%s': %v", m.options.CommandPath, keyErr)
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		entry.Outcome = audit.OutcomeCancelled
+	case err != nil:
+		entry.Outcome = audit.OutcomeFailed
+	}
+
+	if currentUser, userErr := user.Current(); userErr == nil {
+		entry.User = currentUser.Username
+	}
+
+	// The command context may be cancelled at this point, the principal is read from the saved login.
+	if principal, principalErr := m.authManager.CurrentPrincipal(context.Background()); principalErr == nil {
+		entry.Principal = principal
+	}
+
+	if auditErr := audit.NewLog(env.Root).Append(entry); auditErr != nil {
+		log.Printf("failed recording '%s' in the audit log: %v", m.options.CommandPath, auditErr)
+	}
+
+	return result, err
+}
+
+// changedFlags returns the values of the flags specified by the user.
+func (m *AuditMiddleware) changedFlags() map[string]string {
+	flags := map[string]string{}
+	if m.options.Flags == nil {
+		return flags
+	}
+
+	m.options.Flags.VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			flags[f.Name] = f.Value.String()
+		}
+	})
+
+	return flags
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// validationErrors are the well known errors caused by the project or the arguments of the user
+var validationErrors = []error{
+	azdcontext.ErrNoProject,
+	azdcontext.ErrEnvironmentExists,
+	project.ErrNoDefaultService,
+}
+
+// ErrorMiddleware classifies the well known errors returned by actions which were not classified where they occurred,
+// so that all commands report them with the same exit code and telemetry
+type ErrorMiddleware struct {
+	options *Options
+}
+
+// Creates a new instance of the error middleware
+func NewErrorMiddleware(options *Options) Middleware {
+	return &ErrorMiddleware{
+		options: options,
+	}
+}
+
+// Invokes the error middleware and classifies the error of the action
+func (m *ErrorMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	result, err := next(ctx)
+	if err == nil || m.options.IsChildAction() {
+		return result, err
+	}
+
+	return result, classifyError(err)
+}
+
+// classifyError returns err classified with the category of the well known error it wraps, or err unchanged when it is
+// already classified or not a well known error.
+func classifyError(err error) error {
+	if exitcode.Classify(err) != exitcode.CategoryUnknown {
+		return err
+	}
+
+	for _, validationErr := range validationErrors {
+		if errors.Is(err, validationErr) {
+			return exitcode.New(exitcode.CategoryValidation, err)
+		}
+	}
+
+	return err
+}
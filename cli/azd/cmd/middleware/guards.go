@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/auth"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+)
+
+// LoginGuardMiddleware fails actions requiring the user to be logged in before they run, instead of each action
+// checking the login on its own
+type LoginGuardMiddleware struct {
+	options     *Options
+	authManager *auth.Manager
+}
+
+// Creates a new instance of the login guard middleware
+func NewLoginGuardMiddleware(options *Options, authManager *auth.Manager) Middleware {
+	return &LoginGuardMiddleware{
+		options:     options,
+		authManager: authManager,
+	}
+}
+
+// Invokes the login guard middleware. Returns an error when the user isn't logged in or the credentials can't be used.
+func (m *LoginGuardMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	// Child actions run after the parent action already checked the login
+	if m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	if _, err := auth.NewLoggedInGuard(m.authManager, ctx); err != nil {
+		return nil, exitcode.New(exitcode.CategoryAuth, err)
+	}
+
+	return next(ctx)
+}
+
+// ProjectGuardMiddleware fails actions requiring a project before they run, instead of each action checking for the
+// azure.yaml file on its own
+type ProjectGuardMiddleware struct {
+	options        *Options
+	lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext]
+}
+
+// Creates a new instance of the project guard middleware
+func NewProjectGuardMiddleware(options *Options, lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext]) Middleware {
+	return &ProjectGuardMiddleware{
+		options:        options,
+		lazyAzdContext: lazyAzdContext,
+	}
+}
+
+// Invokes the project guard middleware. Returns an error when the current directory isn't within a project.
+func (m *ProjectGuardMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	if _, err := m.lazyAzdContext.GetValue(); err != nil {
+		return nil, exitcode.New(exitcode.CategoryValidation, err)
+	}
+
+	return next(ctx)
+}
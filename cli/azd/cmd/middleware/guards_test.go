@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProjectGuardMiddleware(t *testing.T) {
+	t.Run("NoProject", func(t *testing.T) {
+		lazyAzdContext := lazy.NewLazy(func() (*azdcontext.AzdContext, error) {
+			return nil, azdcontext.ErrNoProject
+		})
+
+		ran := false
+		middleware := NewProjectGuardMiddleware(&Options{Name: "deploy"}, lazyAzdContext)
+		result, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			ran = true
+			return &actions.ActionResult{}, nil
+		})
+
+		require.Nil(t, result)
+		require.ErrorIs(t, err, azdcontext.ErrNoProject)
+		require.Equal(t, exitcode.CategoryValidation, exitcode.Classify(err))
+		require.False(t, ran)
+	})
+
+	t.Run("Project", func(t *testing.T) {
+		lazyAzdContext := lazy.NewLazy(func() (*azdcontext.AzdContext, error) {
+			return azdcontext.NewAzdContextWithDirectory(t.TempDir()), nil
+		})
+
+		ran := false
+		middleware := NewProjectGuardMiddleware(&Options{Name: "deploy"}, lazyAzdContext)
+		result, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			ran = true
+			return &actions.ActionResult{}, nil
+		})
+
+		require.NotNil(t, result)
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+}
+
+func Test_ErrorMiddleware(t *testing.T) {
+	run := func(actionErr error) error {
+		middleware := NewErrorMiddleware(&Options{Name: "deploy"})
+		_, err := middleware.Run(context.Background(), func(ctx context.Context) (*actions.ActionResult, error) {
+			return nil, actionErr
+		})
+
+		return err
+	}
+
+	require.NoError(t, run(nil))
+	require.Equal(t, exitcode.CategoryValidation, exitcode.Classify(run(azdcontext.ErrNoProject)))
+	require.Equal(t, exitcode.CategoryValidation, exitcode.Classify(run(fmt.Errorf("creating environment: %w",
+		azdcontext.ErrEnvironmentExists))))
+	require.Equal(t, exitcode.CategoryValidation, exitcode.Classify(run(project.ErrNoDefaultService)))
+	require.Equal(t, exitcode.CategoryUnknown, exitcode.Classify(run(errors.New("failed"))))
+
+	// Errors classified where they occurred keep their category
+	deploymentErr := exitcode.New(exitcode.CategoryDeployment, azdcontext.ErrNoProject)
+	require.Equal(t, exitcode.CategoryDeployment, exitcode.Classify(run(deploymentErr)))
+}
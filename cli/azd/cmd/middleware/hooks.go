@@ -13,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
 type contextKey string
@@ -24,6 +25,7 @@ type HooksMiddleware struct {
 	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig]
 	commandRunner     exec.CommandRunner
 	console           input.Console
+	azCli             azcli.AzCli
 	options           *Options
 }
 
@@ -33,6 +35,7 @@ func NewHooksMiddleware(
 	projectConfig *lazy.Lazy[*project.ProjectConfig],
 	commandRunner exec.CommandRunner,
 	console input.Console,
+	azCli azcli.AzCli,
 	options *Options,
 ) Middleware {
 	return &HooksMiddleware{
@@ -40,6 +43,7 @@ func NewHooksMiddleware(
 		lazyProjectConfig: projectConfig,
 		commandRunner:     commandRunner,
 		console:           console,
+		azCli:             azCli,
 		options:           options,
 	}
 }
@@ -88,6 +92,7 @@ func (m *HooksMiddleware) registerCommandHooks(
 		hooksManager,
 		m.commandRunner,
 		m.console,
+		m.azCli,
 		projectConfig.Path,
 		projectConfig.Hooks,
 		env,
@@ -140,6 +145,7 @@ func (m *HooksMiddleware) registerServiceHooks(
 			serviceHooksManager,
 			m.commandRunner,
 			m.console,
+			m.azCli,
 			service.Path(),
 			service.Hooks,
 			env,
@@ -184,7 +190,8 @@ func (m *HooksMiddleware) createServiceEventHandler(
 	hooksRunner *ext.HooksRunner,
 ) ext.EventHandlerFn[project.ServiceLifecycleEventArgs] {
 	return func(ctx context.Context, eventArgs project.ServiceLifecycleEventArgs) error {
-		return hooksRunner.RunHooks(ctx, hookType, hookName)
+		packagePath, _ := eventArgs.Args[project.PackagePathEventArg].(string)
+		return hooksRunner.ForService(eventArgs.Service.Name, packagePath).RunHooks(ctx, hookType, hookName)
 	}
 }
 
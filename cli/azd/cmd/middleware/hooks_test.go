@@ -334,6 +334,7 @@ func runMiddleware(
 		lazyProjectConfig,
 		mockContext.CommandRunner,
 		mockContext.Console,
+		nil,
 		runOptions,
 	)
 
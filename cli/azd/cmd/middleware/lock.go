@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/lock"
+)
+
+// Holds the lock of the environment while the commands changing it run, so two commands, like a developer and a CI
+// pipeline provisioning the same environment, can't run concurrently
+type LockMiddleware struct {
+	options *Options
+	lazyEnv *lazy.Lazy[*environment.Environment]
+}
+
+// Creates a new instance of the Lock middleware
+func NewLockMiddleware(
+	options *Options,
+	lazyEnv *lazy.Lazy[*environment.Environment],
+) Middleware {
+	return &LockMiddleware{
+		options: options,
+		lazyEnv: lazyEnv,
+	}
+}
+
+// Acquires the lock of the environment, runs the action and releases the lock. Child actions, like the provision and
+// deploy actions run by `azd up`, run under the lock of the command which ran them.
+func (m *LockMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	if m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	env, err := m.lazyEnv.GetValue()
+	if err != nil || env.Root == "" {
+		log.Printf("environment is not available, skipping the lock of '%s'", m.options.CommandPath)
+		return next(ctx)
+	}
+
+	release, err := lock.New(env.Root).Acquire(lock.NewHolder(m.options.CommandPath))
+	if errors.Is(err, lock.ErrLocked) {
+		return nil, fmt.Errorf(
+			"%w. Wait for the operation to complete, or run 'azd env unlock --force' when it isn't running anymore",
+			err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locking environment: %w", err)
+	}
+
+	defer func() {
+		if err := release(); err != nil {
+			log.Printf("failed releasing the lock of the environment: %v", err)
+		}
+	}()
+
+	return next(ctx)
+}
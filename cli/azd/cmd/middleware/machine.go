@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+)
+
+// MachineMiddleware writes the result of the command as the last notification of the --machine protocol, so clients
+// know when the command ended and whether it failed without parsing its output
+type MachineMiddleware struct {
+	options *Options
+	console input.Console
+}
+
+// Creates a new instance of the machine middleware
+func NewMachineMiddleware(options *Options, console input.Console) Middleware {
+	return &MachineMiddleware{
+		options: options,
+		console: console,
+	}
+}
+
+// Invokes the machine middleware. Writes the result notification when the console is a machine console.
+func (m *MachineMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	machineConsole, ok := m.console.(*input.MachineConsole)
+	if !ok || m.options.IsChildAction() {
+		return next(ctx)
+	}
+
+	result, err := next(ctx)
+	machineConsole.Notify(ctx, input.MachineMethodResult, machineResult(result, err))
+
+	return result, err
+}
+
+func machineResult(result *actions.ActionResult, err error) input.MachineResult {
+	machineResult := input.MachineResult{}
+	if result != nil && result.Message != nil {
+		machineResult.Message = result.Message.Header
+		machineResult.FollowUp = result.Message.FollowUp
+	}
+
+	if err != nil {
+		category := exitcode.Classify(err)
+		machineResult.Error = err.Error()
+		machineResult.Category = string(category)
+		machineResult.ExitCode = category.ExitCode()
+	}
+
+	return machineResult
+}
@@ -8,8 +8,6 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry/events"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry/fields"
 	"github.com/spf13/pflag"
-
-	"go.opentelemetry.io/otel/codes"
 )
 
 // Telemetry middleware tracks telemetry for the given action
@@ -59,7 +57,7 @@ func (m *TelemetryMiddleware) Run(ctx context.Context, next NextFn) (*actions.Ac
 
 	result, err := next(spanCtx)
 	if err != nil {
-		span.SetStatus(codes.Error, "UnknownError")
+		telemetry.SetErrorStatus(span, err)
 	}
 
 	return result, err
@@ -8,10 +8,13 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -54,6 +57,7 @@ func newPackageCmd() *cobra.Command {
 		),
 	}
 	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.ValidArgsFunction = serviceNameCompletion
 	return cmd
 }
 
@@ -66,6 +70,8 @@ type packageAction struct {
 	console        input.Console
 	formatter      output.Formatter
 	writer         io.Writer
+	progressBus    *progress.Bus
+	env            *environment.Environment
 }
 
 func newPackageAction(
@@ -77,6 +83,8 @@ func newPackageAction(
 	console input.Console,
 	formatter output.Formatter,
 	writer io.Writer,
+	progressBus *progress.Bus,
+	env *environment.Environment,
 ) actions.Action {
 	return &packageAction{
 		flags:          flags,
@@ -87,6 +95,8 @@ func newPackageAction(
 		console:        console,
 		formatter:      formatter,
 		writer:         writer,
+		progressBus:    progressBus,
+		env:            env,
 	}
 }
 
@@ -98,7 +108,7 @@ type PackageResult struct {
 func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	// Command title
 	pa.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title: "Packaging services (azd package)",
+		Title: locale.Sprintf(locale.PackageTitle),
 	})
 
 	targetServiceName := ""
@@ -123,7 +133,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}
 
 	if err := pa.projectManager.EnsureAllTools(ctx, pa.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return (targetServiceName == "" || svc.Name == targetServiceName) && svc.EnabledIn(pa.env.GetEnvName())
 	}); err != nil {
 		return nil, err
 	}
@@ -131,32 +141,37 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	packageResults := map[string]*project.ServicePackageResult{}
 
 	for _, svc := range pa.projectConfig.GetServicesStable() {
-		stepMessage := fmt.Sprintf("Packaging service %s", svc.Name)
-		pa.console.ShowSpinner(ctx, stepMessage, input.Step)
+		step := pa.progressBus.Step(
+			string(project.ServiceEventPackage), svc.Name, locale.Sprintf(locale.PackageStep, svc.Name))
+		step.Start(ctx)
 
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
 		// 2. This service is not the one the user specified
 		if targetServiceName != "" && targetServiceName != svc.Name {
-			pa.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			step.Skip(ctx)
+			continue
+		}
+
+		if !svc.EnabledIn(pa.env.GetEnvName()) {
+			step.SkipWithReason(ctx, locale.Sprintf(locale.ServiceDisabled, pa.env.GetEnvName()))
 			continue
 		}
 
 		packageTask := pa.serviceManager.Package(ctx, svc, nil)
 		go func() {
 			for packageProgress := range packageTask.Progress() {
-				progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
-				pa.console.ShowSpinner(ctx, progressMessage, input.Step)
+				step.Progress(ctx, packageProgress.Message)
 			}
 		}()
 
 		packageResult, err := packageTask.Await()
 		if err != nil {
-			pa.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			step.Fail(ctx, err)
 			return nil, err
 		}
 
-		pa.console.StopSpinner(ctx, stepMessage, input.StepDone)
+		step.Complete(ctx)
 		packageResults[svc.Name] = packageResult
 
 		// report package output
@@ -176,7 +191,7 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: "Your Azure app has been packaged!",
+			Header: locale.Sprintf(locale.PackageSuccess),
 		},
 	}, nil
 }
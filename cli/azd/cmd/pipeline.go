@@ -7,12 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/commands/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
@@ -55,6 +59,26 @@ func (pc *pipelineConfigFlags) Bind(local *pflag.FlagSet, global *internal.Globa
 	// there no customer input using --provider
 	local.StringVar(&pc.PipelineProvider, "provider", "",
 		"The pipeline provider to use (github for Github Actions and azdo for Azure Pipelines).")
+	//nolint:lll
+	local.StringVar(
+		&pc.GitHubHost,
+		"github-host",
+		"",
+		"The host of the GitHub Enterprise Server instance to use instead of github.com. Defaults to the pipeline.github.host config setting.",
+	)
+	local.BoolVar(
+		&pc.SkipSecretScan,
+		"skip-secret-scan",
+		false,
+		"Pushes the changes even when files which may contain secrets, like .env files, would be committed.",
+	)
+	//nolint:lll
+	local.StringVar(
+		&pc.PrValidation,
+		"pr-validation",
+		"",
+		"The workflow validating pull requests, generated for GitHub. Valid values: build (tests and packages, the default), ephemeral (also provisions a temporary environment), none.",
+	)
 	pc.envFlag.Bind(local, global)
 	pc.global = global
 }
@@ -78,6 +102,16 @@ func pipelineActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		Command:        newPipelineConfigCmd(),
 		FlagsResolver:  newPipelineConfigFlags,
 		ActionResolver: newPipelineConfigAction,
+		RequireLogin:   true,
+		RequireProject: true,
+	}).UseMiddleware("audit", middleware.NewAuditMiddleware)
+
+	group.Add("set-secrets", &actions.ActionDescriptorOptions{
+		Command:        newPipelineSetSecretsCmd(),
+		FlagsResolver:  newPipelineSetSecretsFlags,
+		ActionResolver: newPipelineSetSecretsAction,
+		RequireLogin:   true,
+		RequireProject: true,
 	})
 
 	return group
@@ -107,8 +141,12 @@ type pipelineConfigAction struct {
 	console            input.Console
 	commandRunner      exec.CommandRunner
 	credentialProvider account.SubscriptionCredentialProvider
+	userConfigManager  config.UserConfigManager
 }
 
+// The user config setting with the host of the GitHub Enterprise Server instance used by `azd pipeline config`
+const gitHubHostConfigPath = "pipeline.github.host"
+
 func newPipelineConfigAction(
 	azCli azcli.AzCli,
 	credentialProvider account.SubscriptionCredentialProvider,
@@ -116,19 +154,20 @@ func newPipelineConfigAction(
 	env *environment.Environment,
 	console input.Console,
 	flags *pipelineConfigFlags,
+	manager *pipeline.PipelineManager,
 	commandRunner exec.CommandRunner,
+	userConfigManager config.UserConfigManager,
 ) actions.Action {
 	pca := &pipelineConfigAction{
 		flags:              flags,
 		azCli:              azCli,
 		credentialProvider: credentialProvider,
-		manager: pipeline.NewPipelineManager(
-			azCli, azdCtx, env, flags.global, commandRunner, console, flags.PipelineManagerArgs,
-		),
-		azdCtx:        azdCtx,
-		env:           env,
-		console:       console,
-		commandRunner: commandRunner,
+		manager:            manager,
+		azdCtx:             azdCtx,
+		env:                env,
+		console:            console,
+		commandRunner:      commandRunner,
+		userConfigManager:  userConfigManager,
 	}
 
 	return pca
@@ -152,11 +191,16 @@ func (p *pipelineConfigAction) Run(ctx context.Context) (*actions.ActionResult,
 		return nil, err
 	}
 
+	gitHubHost, err := p.gitHubHost()
+	if err != nil {
+		return nil, err
+	}
+
 	// Detect the SCM and CI providers based on the project directory
 	p.manager.ScmProvider,
 		p.manager.CiProvider,
 		err = pipeline.DetectProviders(
-		ctx, p.azdCtx, p.env, p.manager.PipelineProvider, p.console, credential, p.commandRunner,
+		ctx, p.azdCtx, p.env, p.manager.PipelineProvider, gitHubHost, p.console, credential, p.commandRunner,
 	)
 	if err != nil {
 		return nil, err
@@ -179,6 +223,208 @@ func (p *pipelineConfigAction) Run(ctx context.Context) (*actions.ActionResult,
 	}, nil
 }
 
+func (p *pipelineConfigAction) gitHubHost() (string, error) {
+	return gitHubHost(p.flags.GitHubHost, p.userConfigManager)
+}
+
+// gitHubHost returns the host of the GitHub Enterprise Server instance from --github-host or the user config, empty
+// for github.com.
+func gitHubHost(flagValue string, userConfigManager config.UserConfigManager) (string, error) {
+	if flagValue != "" {
+		return normalizeGitHubHost(flagValue), nil
+	}
+
+	userConfig, err := userConfigManager.Load()
+	if err != nil {
+		return "", fmt.Errorf("loading user configuration: %w", err)
+	}
+
+	if host, has := userConfig.Get(gitHubHostConfigPath); has {
+		if hostName, ok := host.(string); ok {
+			return normalizeGitHubHost(hostName), nil
+		}
+	}
+
+	return "", nil
+}
+
+// normalizeGitHubHost accepts the URL of the instance, like https://github.contoso.com/, as host
+func normalizeGitHubHost(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(host), "https://"), "http://")
+	return strings.TrimSuffix(host, "/")
+}
+
+type pipelineSetSecretsFlags struct {
+	mappingFile string
+	dryRun      bool
+	provider    string
+	remoteName  string
+	gitHubHost  string
+	global      *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *pipelineSetSecretsFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.mappingFile,
+		"mapping-file",
+		"",
+		fmt.Sprintf("The file mapping the secrets and variables of the pipeline to environment values. Defaults to %s "+
+			"in the project directory.", pipeline.DefaultValuesMappingFile),
+	)
+	local.BoolVar(&f.dryRun, "dry-run", false, "Lists the secrets and variables, and checks the pipeline exists, without setting them.")
+	local.StringVar(&f.provider, "provider", "",
+		"The pipeline provider to use (github for Github Actions and azdo for Azure Pipelines).")
+	local.StringVar(
+		&f.remoteName,
+		"remote-name",
+		"origin",
+		"The name of the git remote of the pipeline.",
+	)
+	local.StringVar(
+		&f.gitHubHost,
+		"github-host",
+		"",
+		"The host of the GitHub Enterprise Server instance to use instead of github.com.",
+	)
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newPipelineSetSecretsFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *pipelineSetSecretsFlags {
+	flags := &pipelineSetSecretsFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newPipelineSetSecretsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-secrets",
+		Short: "Set the secrets and variables of your deployment pipeline from environment values.",
+	}
+}
+
+type pipelineSetSecretsAction struct {
+	flags              *pipelineSetSecretsFlags
+	azCli              azcli.AzCli
+	azdCtx             *azdcontext.AzdContext
+	env                *environment.Environment
+	console            input.Console
+	commandRunner      exec.CommandRunner
+	credentialProvider account.SubscriptionCredentialProvider
+	userConfigManager  config.UserConfigManager
+}
+
+func newPipelineSetSecretsAction(
+	azCli azcli.AzCli,
+	credentialProvider account.SubscriptionCredentialProvider,
+	azdCtx *azdcontext.AzdContext,
+	env *environment.Environment,
+	console input.Console,
+	flags *pipelineSetSecretsFlags,
+	commandRunner exec.CommandRunner,
+	userConfigManager config.UserConfigManager,
+) actions.Action {
+	return &pipelineSetSecretsAction{
+		flags:              flags,
+		azCli:              azCli,
+		azdCtx:             azdCtx,
+		env:                env,
+		console:            console,
+		commandRunner:      commandRunner,
+		credentialProvider: credentialProvider,
+		userConfigManager:  userConfigManager,
+	}
+}
+
+func (p *pipelineSetSecretsAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	mappingFile := p.flags.mappingFile
+	if mappingFile == "" {
+		mappingFile = filepath.Join(p.azdCtx.ProjectDirectory(), pipeline.DefaultValuesMappingFile)
+	}
+
+	mapping, err := pipeline.LoadValuesMapping(mappingFile)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := mapping.Values(p.env)
+	if err != nil {
+		return nil, fmt.Errorf("resolving the values of %s: %w", mappingFile, err)
+	}
+
+	if len(values) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("%s has no secrets or variables", mappingFile),
+			},
+		}, nil
+	}
+
+	p.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Set the secrets and variables of your azd pipeline",
+	})
+
+	for _, value := range values {
+		kind := "variable"
+		if value.Secret {
+			kind = "secret"
+		}
+		p.console.Message(ctx, fmt.Sprintf("  - %s %s from %s",
+			kind, output.WithHighLightFormat(value.Name), value.EnvName))
+	}
+	p.console.Message(ctx, "")
+
+	if p.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	credential, err := p.credentialProvider.CredentialForSubscription(ctx, p.env.GetSubscriptionId())
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := gitHubHost(p.flags.gitHubHost, p.userConfigManager)
+	if err != nil {
+		return nil, err
+	}
+
+	manager := pipeline.NewPipelineManager(p.azCli, p.azdCtx, p.env, p.flags.global, p.commandRunner, p.console,
+		&pipeline.PipelineManagerArgs{
+			PipelineRemoteName: p.flags.remoteName,
+			PipelineProvider:   p.flags.provider,
+			GitHubHost:         host,
+		})
+
+	manager.ScmProvider, manager.CiProvider, err = pipeline.DetectProviders(
+		ctx, p.azdCtx, p.env, p.flags.provider, host, p.console, credential, p.commandRunner,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := manager.SetValues(ctx, values, p.flags.dryRun); err != nil {
+		return nil, err
+	}
+
+	if p.flags.dryRun {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "The pipeline exists and wasn't changed (--dry-run)",
+			},
+		}, nil
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "Your pipeline secrets and variables are set!",
+		},
+	}, nil
+}
+
 func getCmdPipelineHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription(
 		"Manage integrating your application with build pipelines.",
@@ -186,6 +432,8 @@ func getCmdPipelineHelpDescription(*cobra.Command) string {
 			formatHelpNote(fmt.Sprintf("The Azure Developer CLI template includes a GitHub Actions pipeline"+
 				" configuration file (in the %s folder) that deploys your application whenever code is pushed"+
 				" to the main branch.", output.WithLinkFormat(".github/workflows"))),
+			formatHelpNote("For GitHub, azd pipeline config also writes a workflow validating the pull requests of the" +
+				" main branch without deploying them. Require it to pass with a branch protection rule."),
 			formatHelpNote(fmt.Sprintf("For more information, go to: %s.",
 				output.WithLinkFormat("https://aka.ms/azure-dev/pipeline"))),
 		})
@@ -195,5 +443,7 @@ func getCmdPipelineHelpFooter(c *cobra.Command) string {
 	return generateCmdHelpSamplesBlock(map[string]string{
 		"Walk through the steps required " +
 			"to set up your deployment pipeline.": output.WithHighLightFormat("azd pipeline config"),
+		"Set the secrets and variables of your deployment pipeline " +
+			"from environment values.": output.WithHighLightFormat("azd pipeline set-secrets"),
 	})
 }
@@ -4,12 +4,17 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/commands/pipeline"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPipelineCmd(t *testing.T) {
@@ -63,3 +68,23 @@ func TestSetupFlags(t *testing.T) {
 	principalRoleNameFlag = command.PersistentFlags().Lookup(flagName)
 	assert.Equal(t, (*pflag.Flag)(nil), principalRoleNameFlag)
 }
+
+func TestPipelineConfigActionNotProvisioned(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.EphemeralWithValues("test", nil)
+
+	action := newPipelineConfigAction(
+		nil,
+		nil,
+		nil,
+		env,
+		mockContext.Console,
+		&pipelineConfigFlags{},
+		&pipeline.PipelineManager{},
+		mockContext.CommandRunner,
+		nil,
+	)
+
+	_, err := action.Run(*mockContext.Context)
+	require.ErrorContains(t, err, "azd provision")
+}
@@ -0,0 +1,216 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The local port used when no port mapping is specified
+const defaultPortForwardLocalPort = 8080
+
+type portForwardFlags struct {
+	global *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *portForwardFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newPortForwardFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *portForwardFlags {
+	flags := &portForwardFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newPortForwardCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port-forward <service> [local:remote]",
+		Short: "Forward a local port to a deployed service.",
+	}
+	cmd.Args = cobra.RangeArgs(1, 2)
+	cmd.ValidArgsFunction = serviceNameCompletion
+
+	return cmd
+}
+
+type portForwardAction struct {
+	args            []string
+	projectConfig   *project.ProjectConfig
+	env             *environment.Environment
+	serviceManager  project.ServiceManager
+	resourceManager project.ResourceManager
+	console         input.Console
+}
+
+func newPortForwardAction(
+	args []string,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	serviceManager project.ServiceManager,
+	resourceManager project.ResourceManager,
+	console input.Console,
+) actions.Action {
+	return &portForwardAction{
+		args:            args,
+		projectConfig:   projectConfig,
+		env:             env,
+		serviceManager:  serviceManager,
+		resourceManager: resourceManager,
+		console:         console,
+	}
+}
+
+func (pf *portForwardAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	serviceName := pf.args[0]
+	serviceConfig, has := pf.projectConfig.Services[serviceName]
+	if !has {
+		return nil, fmt.Errorf("service name '%s' doesn't exist", serviceName)
+	}
+
+	ports := project.PortMapping{Local: defaultPortForwardLocalPort}
+	if len(pf.args) > 1 {
+		parsed, err := parsePortMapping(pf.args[1])
+		if err != nil {
+			return nil, err
+		}
+		ports = parsed
+	}
+
+	if pf.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	serviceTarget, err := pf.serviceManager.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	forwarder, ok := serviceTarget.(project.PortForwarder)
+	if !ok {
+		err := fmt.Errorf(
+			"service '%s' is hosted on '%s', which does not support port forwarding. Port forwarding supports "+
+				"services hosted on %s, %s and %s",
+			serviceName,
+			serviceConfig.Host,
+			project.AksTarget,
+			project.AppServiceTarget,
+			project.AzureFunctionTarget,
+		)
+
+		if serviceConfig.Host == project.ContainerAppTarget {
+			return nil, fmt.Errorf("%w. Run 'az containerapp exec' to open a shell in the container of the service", err)
+		}
+
+		return nil, err
+	}
+
+	targetResource, err := pf.resourceManager.GetTargetResource(ctx, pf.env.GetSubscriptionId(), serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource: %w", err)
+	}
+
+	if serviceConfig.Host == project.AppServiceTarget || serviceConfig.Host == project.AzureFunctionTarget {
+		// App Service tunnels only reach the SSH server of the container, not the port of the app
+		pf.console.Message(ctx, fmt.Sprintf(
+			"Forwarding %s to the SSH server of service %s, connect with %s. Press Ctrl+C to stop.",
+			output.WithHighLightFormat("localhost:%d", ports.Local),
+			output.WithHighLightFormat(serviceName),
+			output.WithHighLightFormat("ssh root@localhost -p %d", ports.Local),
+		))
+	} else {
+		pf.console.Message(ctx, fmt.Sprintf(
+			"Forwarding %s to service %s. Press Ctrl+C to stop.",
+			output.WithHighLightFormat("localhost:%d", ports.Local),
+			output.WithHighLightFormat(serviceName),
+		))
+	}
+
+	if err := forwarder.PortForward(ctx, serviceConfig, targetResource, ports); err != nil {
+		// Interrupting the tunnel is the expected way to stop forwarding
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// parsePortMapping parses a port mapping in the form 'local:remote' or 'port', where a single port is used for both
+// the local and the remote end.
+func parsePortMapping(value string) (project.PortMapping, error) {
+	local, remote, hasRemote := strings.Cut(value, ":")
+	if !hasRemote {
+		remote = local
+	}
+
+	localPort, err := parsePort(local)
+	if err != nil {
+		return project.PortMapping{}, fmt.Errorf("invalid port mapping '%s': %w", value, err)
+	}
+
+	remotePort, err := parsePort(remote)
+	if err != nil {
+		return project.PortMapping{}, fmt.Errorf("invalid port mapping '%s': %w", value, err)
+	}
+
+	return project.PortMapping{Local: localPort, Remote: remotePort}, nil
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("'%s' is not a valid port", value)
+	}
+
+	return port, nil
+}
+
+func getCmdPortForwardHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Forward a local port to a service deployed to AKS, or to the SSH server of an App Service or Function App"+
+			" container, so services that are only reachable from a private network can be reached from your machine.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("When %s is not set, local port %d is forwarded to the default port of the service.",
+				output.WithHighLightFormat("[local:remote]"), defaultPortForwardLocalPort)),
+			formatHelpNote("AKS services are forwarded with kubectl to any port of the Kubernetes service, port 80 by" +
+				" default."),
+			formatHelpNote(fmt.Sprintf("App Service and Function App tunnels only connect to the SSH server of the"+
+				" container on port %d, the port of the app can't be forwarded.", project.WebAppRemoteConnectionPort)),
+			formatHelpNote("Services hosted on Container Apps and the other hosts don't support port forwarding."),
+		})
+}
+
+func getCmdPortForwardHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Forward local port 8080 to the service named 'api'.": output.WithHighLightFormat(
+			"azd port-forward api",
+		),
+		"Forward local port 2222 to the SSH server of the App Service container of the service named 'web'.": output.
+			WithHighLightFormat("azd port-forward web 2222"),
+		"Forward local port 5000 to port 80 of the service named 'api'.": output.WithHighLightFormat(
+			"azd port-forward api 5000:80",
+		),
+	})
+}
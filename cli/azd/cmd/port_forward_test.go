@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parsePortMapping(t *testing.T) {
+	tests := map[string]struct {
+		value       string
+		expected    project.PortMapping
+		expectError bool
+	}{
+		"LocalAndRemote": {value: "5000:80", expected: project.PortMapping{Local: 5000, Remote: 80}},
+		"SinglePort":     {value: "3000", expected: project.PortMapping{Local: 3000, Remote: 3000}},
+		"NotANumber":     {value: "web:80", expectError: true},
+		"OutOfRange":     {value: "8080:70000", expectError: true},
+		"MissingRemote":  {value: "8080:", expectError: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ports, err := parsePortMapping(test.value)
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, ports)
+		})
+	}
+}
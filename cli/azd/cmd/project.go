@@ -0,0 +1,457 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/workspace"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func projectActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("project", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "project",
+			Short: "Manage the projects registered in your workspace.",
+		},
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdProjectHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupManage,
+		},
+	})
+
+	group.Add("list", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:     "list",
+			Short:   "List the projects registered in your workspace.",
+			Aliases: []string{"ls"},
+		},
+		ActionResolver: newProjectListAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+	})
+
+	group.Add("add", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "add [path]",
+			Short: "Register a project in your workspace.",
+			Args:  cobra.MaximumNArgs(1),
+		},
+		FlagsResolver:  newProjectAddFlags,
+		ActionResolver: newProjectAddAction,
+	})
+
+	group.Add("remove", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "remove <name>",
+			Short: "Remove a project from your workspace.",
+			Args:  cobra.ExactArgs(1),
+		},
+		ActionResolver: newProjectRemoveAction,
+	})
+
+	group.Add("switch", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "switch <name>",
+			Short: "Set the active project of your workspace.",
+			Args:  cobra.ExactArgs(1),
+		},
+		ActionResolver: newProjectSwitchAction,
+	})
+
+	group.Add("upgrade", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "upgrade",
+			Short: "Migrate azure.yaml to the latest schema version.",
+			Args:  cobra.NoArgs,
+		},
+		FlagsResolver:  newProjectUpgradeFlags,
+		ActionResolver: newProjectUpgradeAction,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdProjectUpgradeHelpDescription,
+		},
+	})
+
+	return group
+}
+
+type projectListAction struct {
+	workspaceManager *workspace.Manager
+	formatter        output.Formatter
+	writer           io.Writer
+}
+
+func newProjectListAction(
+	workspaceManager *workspace.Manager,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &projectListAction{
+		workspaceManager: workspaceManager,
+		formatter:        formatter,
+		writer:           writer,
+	}
+}
+
+func (p *projectListAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	projects, err := p.workspaceManager.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+
+	results := make([]contracts.ProjectListProject, len(projects))
+	for i, project := range projects {
+		results[i] = contracts.ProjectListProject{
+			Name:     project.Name,
+			Path:     project.Path,
+			IsActive: project.IsActive,
+		}
+	}
+
+	if p.formatter.Kind() == output.TableFormat {
+		columns := []output.Column{
+			{
+				Heading:       "NAME",
+				ValueTemplate: "{{.Name}}",
+			},
+			{
+				Heading:       "ACTIVE",
+				ValueTemplate: "{{.IsActive}}",
+			},
+			{
+				Heading:       "PATH",
+				ValueTemplate: "{{.Path}}",
+			},
+		}
+
+		err = p.formatter.Format(results, p.writer, output.TableFormatterOptions{
+			Columns: columns,
+		})
+	} else {
+		err = p.formatter.Format(results, p.writer, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+type projectAddFlags struct {
+	name   string
+	global *internal.GlobalCommandOptions
+}
+
+func (f *projectAddFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.name,
+		"name",
+		"",
+		"The name of the project in the workspace. Defaults to the name defined in azure.yaml.",
+	)
+	f.global = global
+}
+
+func newProjectAddFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *projectAddFlags {
+	flags := &projectAddFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type projectAddAction struct {
+	workspaceManager *workspace.Manager
+	flags            *projectAddFlags
+	args             []string
+}
+
+func newProjectAddAction(
+	workspaceManager *workspace.Manager,
+	flags *projectAddFlags,
+	args []string,
+) actions.Action {
+	return &projectAddAction{
+		workspaceManager: workspaceManager,
+		flags:            flags,
+		args:             args,
+	}
+}
+
+func (p *projectAddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	projectDir := "."
+	if len(p.args) > 0 {
+		projectDir = p.args[0]
+	}
+
+	name := p.flags.name
+	if name == "" {
+		projectConfig, err := project.Load(ctx, filepath.Join(projectDir, azdcontext.ProjectFileName))
+		if err != nil {
+			return nil, fmt.Errorf("loading project: %w", err)
+		}
+
+		name = projectConfig.Name
+	}
+
+	registered, err := p.workspaceManager.Add(name, projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Project '%s' has been added to your workspace", registered.Name),
+			FollowUp: fmt.Sprintf(
+				"Run %s to use it from anywhere on disk", output.WithHighLightFormat("azd project switch %s", registered.Name),
+			),
+		},
+	}, nil
+}
+
+type projectRemoveAction struct {
+	workspaceManager *workspace.Manager
+	args             []string
+}
+
+func newProjectRemoveAction(workspaceManager *workspace.Manager, args []string) actions.Action {
+	return &projectRemoveAction{
+		workspaceManager: workspaceManager,
+		args:             args,
+	}
+}
+
+func (p *projectRemoveAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name := p.args[0]
+	if err := p.workspaceManager.Remove(name); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Project '%s' has been removed from your workspace", name),
+		},
+	}, nil
+}
+
+type projectSwitchAction struct {
+	workspaceManager *workspace.Manager
+	args             []string
+}
+
+func newProjectSwitchAction(workspaceManager *workspace.Manager, args []string) actions.Action {
+	return &projectSwitchAction{
+		workspaceManager: workspaceManager,
+		args:             args,
+	}
+}
+
+func (p *projectSwitchAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	active, err := p.workspaceManager.Switch(p.args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Project '%s' is now the active project", active.Name),
+			FollowUp: fmt.Sprintf(
+				"Commands run outside of a project now target %s", output.WithHighLightFormat(active.Path),
+			),
+		},
+	}, nil
+}
+
+// workspaceConfirmedCommands are the commands changing Azure resources, which the user confirms before they run against
+// the active project of the workspace.
+var workspaceConfirmedCommands = map[string]bool{
+	"azd deploy":    true,
+	"azd down":      true,
+	"azd provision": true,
+	"azd up":        true,
+}
+
+// workspaceContextResolver resolves the context of the project containing the current directory, or else of the
+// active project of the workspace. The active project is never used silently: its name and path are displayed, and the
+// user confirms the commands changing Azure resources, which fail with --no-prompt.
+type workspaceContextResolver struct {
+	cmd              *cobra.Command
+	console          input.Console
+	workspaceManager *workspace.Manager
+	// Whether the user was already told about the active project, the context can be resolved more than once.
+	asked bool
+	// The error returned when the user didn't confirm the command for the active project.
+	declined error
+}
+
+func newWorkspaceContextResolver(
+	cmd *cobra.Command,
+	console input.Console,
+	workspaceManager *workspace.Manager,
+) *workspaceContextResolver {
+	return &workspaceContextResolver{
+		cmd:              cmd,
+		console:          console,
+		workspaceManager: workspaceManager,
+	}
+}
+
+func (r *workspaceContextResolver) Resolve(ctx context.Context) (*azdcontext.AzdContext, error) {
+	azdCtx, active, err := r.workspaceManager.ResolveAzdContext()
+	if err != nil || active == nil {
+		return azdCtx, err
+	}
+
+	if r.asked {
+		if r.declined != nil {
+			return nil, r.declined
+		}
+
+		return azdCtx, nil
+	}
+
+	r.asked = true
+	r.console.Message(ctx, output.WithWarningFormat(
+		"Running outside of a project, using the active project '%s' of the workspace (%s)", active.Name, active.Path))
+
+	commandPath := r.cmd.CommandPath()
+	if !workspaceConfirmedCommands[commandPath] {
+		return azdCtx, nil
+	}
+
+	confirmed, err := r.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Run '%s' against the project '%s'?", commandPath, active.Name),
+		DefaultValue: false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("confirming the active project: %w", err)
+	}
+
+	if !confirmed {
+		r.declined = fmt.Errorf(
+			"'%s' was not confirmed for the active project '%s', run it from the directory of the project",
+			commandPath, active.Name)
+		return nil, r.declined
+	}
+
+	return azdCtx, nil
+}
+
+type projectUpgradeFlags struct {
+	dryRun bool
+	global *internal.GlobalCommandOptions
+}
+
+func (f *projectUpgradeFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(&f.dryRun, "dry-run", false, "Lists the changes without writing azure.yaml.")
+	f.global = global
+}
+
+func newProjectUpgradeFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *projectUpgradeFlags {
+	flags := &projectUpgradeFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type projectUpgradeAction struct {
+	azdContext *azdcontext.AzdContext
+	flags      *projectUpgradeFlags
+	console    input.Console
+}
+
+func newProjectUpgradeAction(
+	azdContext *azdcontext.AzdContext,
+	flags *projectUpgradeFlags,
+	console input.Console,
+) actions.Action {
+	return &projectUpgradeAction{
+		azdContext: azdContext,
+		flags:      flags,
+		console:    console,
+	}
+}
+
+func (p *projectUpgradeAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	projectPath := p.azdContext.ProjectPath()
+	content, err := os.ReadFile(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading project file: %w", err)
+	}
+
+	result, err := project.UpgradeSchema(content)
+	if err != nil {
+		return nil, fmt.Errorf("upgrading %s: %w", projectPath, err)
+	}
+
+	if len(result.Changes) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("azure.yaml already uses the latest schema version %s", result.ToVersion),
+			},
+		}, nil
+	}
+
+	p.console.Message(ctx, fmt.Sprintf("Changes to migrate azure.yaml from schema version %s to %s:",
+		result.FromVersion, result.ToVersion))
+	for _, change := range result.Changes {
+		p.console.Message(ctx, fmt.Sprintf("  - %s", change))
+	}
+	p.console.Message(ctx, "")
+
+	if p.flags.dryRun {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "azure.yaml wasn't changed (--dry-run)",
+			},
+		}, nil
+	}
+
+	if err := os.WriteFile(projectPath, result.Content, osutil.PermissionFile); err != nil {
+		return nil, fmt.Errorf("writing project file: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("azure.yaml now uses schema version %s", result.ToVersion),
+		},
+	}, nil
+}
+
+func getCmdProjectUpgradeHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Migrate azure.yaml to the latest schema version.",
+		[]string{
+			formatHelpNote("Deprecated properties are replaced, and comments and the order of the properties are kept."),
+			formatHelpNote("Files declaring the latest schemaVersion are validated strictly against its JSON schema, " +
+				"misspelled properties and invalid values fail with the line they're defined at."),
+		})
+}
+
+func getCmdProjectHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage the projects registered in your workspace.",
+		[]string{
+			formatHelpNote("Registered projects can be listed and switched to from anywhere on disk."),
+			formatHelpNote("The project containing the current directory is always used. The active project is " +
+				"used when azd runs outside of a project, azd then names it and asks to confirm deploy, down, " +
+				"provision and up."),
+		})
+}
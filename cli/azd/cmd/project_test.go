@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/workspace"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_workspaceContextResolver(t *testing.T) {
+	newResolver := func(t *testing.T, command string) (*workspaceContextResolver, *mockinput.MockConsole, string) {
+		t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+		ostest.Chdir(t, t.TempDir())
+
+		projectDir := t.TempDir()
+		err := os.WriteFile(
+			filepath.Join(projectDir, azdcontext.ProjectFileName), []byte("name: api\n"), osutil.PermissionFile)
+		require.NoError(t, err)
+
+		workspaceManager := workspace.NewManager(config.NewUserConfigManager())
+		_, err = workspaceManager.Add("api", projectDir)
+		require.NoError(t, err)
+		_, err = workspaceManager.Switch("api")
+		require.NoError(t, err)
+
+		root := &cobra.Command{Use: "azd"}
+		cmd := &cobra.Command{Use: command}
+		root.AddCommand(cmd)
+
+		console := mockinput.NewMockConsole()
+		return newWorkspaceContextResolver(cmd, console, workspaceManager), console, projectDir
+	}
+
+	t.Run("NamesActiveProject", func(t *testing.T) {
+		resolver, console, projectDir := newResolver(t, "show")
+
+		azdCtx, err := resolver.Resolve(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, projectDir, azdCtx.ProjectDirectory())
+		require.Len(t, console.Output(), 1)
+		require.Contains(t, console.Output()[0], projectDir)
+	})
+
+	t.Run("Confirmed", func(t *testing.T) {
+		resolver, console, projectDir := newResolver(t, "down")
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return true
+		}).Respond(true)
+
+		azdCtx, err := resolver.Resolve(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, projectDir, azdCtx.ProjectDirectory())
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		resolver, console, _ := newResolver(t, "down")
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return true
+		}).Respond(false)
+
+		_, err := resolver.Resolve(context.Background())
+		require.Error(t, err)
+
+		// The context resolved again, like by the lazy context, is still refused without asking again
+		_, err = resolver.Resolve(context.Background())
+		require.Error(t, err)
+	})
+}
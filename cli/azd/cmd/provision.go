@@ -2,21 +2,29 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"strconv"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/appconfig"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/spf13/cobra"
@@ -25,8 +33,13 @@ import (
 )
 
 type provisionFlags struct {
-	noProgress bool
-	global     *internal.GlobalCommandOptions
+	noProgress        bool
+	force             bool
+	destroyOrphans    bool
+	registerProviders bool
+	parameters        []string
+	parametersFile    string
+	global            *internal.GlobalCommandOptions
 	*envFlag
 }
 
@@ -39,6 +52,36 @@ func (i *provisionFlags) bindNonCommon(local *pflag.FlagSet, global *internal.Gl
 	local.BoolVar(&i.noProgress, "no-progress", false, "Suppresses progress information.")
 	//deprecate:Flag hide --no-progress
 	_ = local.MarkHidden("no-progress")
+	local.StringArrayVar(
+		&i.parameters,
+		"parameter",
+		nil,
+		"Overrides a parameter of the infrastructure template for this run, formatted as key=value. Can be repeated.",
+	)
+	local.StringVar(
+		&i.parametersFile,
+		"parameters-file",
+		"",
+		"Overrides parameters of the infrastructure template for this run with the values of a JSON file.",
+	)
+	local.BoolVar(
+		&i.force,
+		"force",
+		false,
+		"Deploys the infrastructure even when the template and its parameters are unchanged since the last provisioning.",
+	)
+	local.BoolVar(
+		&i.destroyOrphans,
+		"destroy-orphans",
+		false,
+		"Deletes, after confirmation, the resources of the environment which the template doesn't provision anymore.",
+	)
+	local.BoolVar(
+		&i.registerProviders,
+		"register-providers",
+		false,
+		"Registers the resource providers of the template which aren't registered in the subscription without prompting.",
+	)
 	i.global = global
 }
 
@@ -90,6 +133,9 @@ type provisionAction struct {
 	userProfileService  *azcli.UserProfileService
 	subResolver         account.SubscriptionTenantResolver
 	alphaFeatureManager *alpha.FeatureManager
+	appConfigSyncer     *appconfig.Syncer
+	identityManager     *project.IdentityManager
+	progressBus         *progress.Bus
 }
 
 func newProvisionAction(
@@ -108,6 +154,9 @@ func newProvisionAction(
 	userProfileService *azcli.UserProfileService,
 	subResolver account.SubscriptionTenantResolver,
 	alphaFeatureManager *alpha.FeatureManager,
+	appConfigSyncer *appconfig.Syncer,
+	identityManager *project.IdentityManager,
+	progressBus *progress.Bus,
 ) actions.Action {
 	return &provisionAction{
 		flags:               flags,
@@ -125,6 +174,9 @@ func newProvisionAction(
 		userProfileService:  userProfileService,
 		subResolver:         subResolver,
 		alphaFeatureManager: alphaFeatureManager,
+		appConfigSyncer:     appConfigSyncer,
+		identityManager:     identityManager,
+		progressBus:         progressBus,
 	}
 }
 
@@ -141,19 +193,38 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 
 	// Command title
 	p.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title:     "Provisioning Azure resources (azd provision)",
-		TitleNote: "Provisioning Azure resources can take some time"},
+		Title:     locale.Sprintf(locale.ProvisionTitle),
+		TitleNote: locale.Sprintf(locale.ProvisionTitleNote)},
 	)
 
 	if err := p.projectManager.Initialize(ctx, p.projectConfig); err != nil {
 		return nil, err
 	}
 
+	parameters, err := provisioning.ParseParameterOverrides(p.flags.parameters, p.flags.parametersFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.setServicesEnabled(); err != nil {
+		return nil, err
+	}
+
+	infraOptions := p.projectConfig.Infra
+	if p.flags.destroyOrphans && infraOptions.Provider == provisioning.Terraform {
+		return nil, errors.New(
+			"--destroy-orphans is only supported with Bicep, Terraform deletes the resources removed from its configuration")
+	}
+
+	infraOptions.Parameters = parameters
+	infraOptions.Force = p.flags.force
+	infraOptions.RegisterProviders = p.flags.registerProviders
+
 	infraManager, err := provisioning.NewManager(
 		ctx,
 		p.env,
 		p.projectConfig.Path,
-		p.projectConfig.Infra,
+		infraOptions,
 		p.console.IsUnformatted(),
 		p.azCli,
 		p.console,
@@ -167,13 +238,24 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		return nil, fmt.Errorf("creating provisioning manager: %w", err)
 	}
 
+	infraManager.SetProgressBus(p.progressBus)
+
 	deploymentPlan, err := infraManager.Plan(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("planning deployment: %w", err)
+		return nil, exitcode.New(exitcode.CategoryProvisioning, fmt.Errorf("planning deployment: %w", err))
 	}
 
-	provisioningScope := infra.NewSubscriptionScope(
-		p.azCli, p.env.GetLocation(), p.env.GetSubscriptionId(), p.env.GetEnvName(),
+	azureResourceManager := infra.NewAzureResourceManager(p.azCli)
+	deploymentName := p.env.GetEnvName()
+	if p.uniqueDeploymentNames() {
+		deploymentName, err = azureResourceManager.NewDeploymentName(ctx, p.env.GetSubscriptionId(), p.env.GetEnvName())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	provisioningScope := infra.NewEnvironmentSubscriptionScope(
+		p.azCli, p.env.GetLocation(), p.env.GetSubscriptionId(), deploymentName, p.env.GetEnvName(), p.projectConfig.Name,
 	)
 	deployResult, err := infraManager.Deploy(ctx, deploymentPlan, provisioningScope)
 
@@ -196,10 +278,14 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 			}
 		}
 
-		return nil, fmt.Errorf("deployment failed: %w", err)
+		return nil, exitcode.New(exitcode.CategoryProvisioning, fmt.Errorf("deployment failed: %w", err))
 	}
 
 	for _, svc := range p.projectConfig.Services {
+		if !svc.EnabledIn(p.env.GetEnvName()) {
+			continue
+		}
+
 		eventArgs := project.ServiceLifecycleEventArgs{
 			Project: p.projectConfig,
 			Service: svc,
@@ -213,6 +299,20 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		}
 	}
 
+	if err := p.syncAppConfig(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := p.applyRoleAssignments(ctx); err != nil {
+		return nil, err
+	}
+
+	if p.flags.destroyOrphans {
+		if err := p.destroyOrphans(ctx, infraManager, provisioningScope); err != nil {
+			return nil, err
+		}
+	}
+
 	if p.formatter.Kind() == output.JsonFormat {
 		stateResult, err := infraManager.State(ctx, provisioningScope)
 		if err != nil {
@@ -231,15 +331,29 @@ func (p *provisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		}
 	}
 
+	followUp := getResourceGroupFollowUp(ctx, p.formatter, p.projectConfig, p.resourceManager, p.env)
+	if p.formatter.Kind() != output.JsonFormat && p.projectConfig.Infra.Provider != provisioning.Terraform {
+		if followUp != "" {
+			followUp += "\n\n"
+		}
+		followUp += fmt.Sprintf("You can view the deployment %s in Azure Portal:\n%s",
+			deploymentName, output.WithLinkFormat("%s", infra.DeploymentPortalUrl(provisioningScope)))
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: "Your project has been provisioned!",
-			FollowUp: getResourceGroupFollowUp(
-				ctx, p.formatter, p.projectConfig, p.resourceManager, p.env),
+			Header:   locale.Sprintf(locale.ProvisionSuccess),
+			FollowUp: followUp,
 		},
 	}, nil
 }
 
+// uniqueDeploymentNames returns whether each provisioning creates a new deployment, see provisioning.DeploymentOptions.
+func (p *provisionAction) uniqueDeploymentNames() bool {
+	return p.projectConfig.Infra.Provider != provisioning.Terraform &&
+		p.projectConfig.Infra.Deployment.Naming != provisioning.DeploymentNamingEnvironment
+}
+
 func getCmdProvisionHelpDescription(c *cobra.Command) string {
 	return generateCmdHelpDescription(fmt.Sprintf(
 		"Provision the Azure resources for an application."+
@@ -252,3 +366,134 @@ func getCmdProvisionHelpDescription(c *cobra.Command) string {
 		formatHelpNote("Azure subscription: The Azure subscription where your resources will be deployed."),
 	})
 }
+
+// syncAppConfig syncs the App Configuration settings and feature flags of the environment once the store is
+// provisioned, when the project configures appConfig and the environment has an App Configuration file.
+func (p *provisionAction) syncAppConfig(ctx context.Context) error {
+	if p.projectConfig.AppConfig == nil {
+		return nil
+	}
+
+	documentPath := appConfigDocumentPath(p.projectConfig, p.env)
+	if _, err := os.Stat(documentPath); errors.Is(err, os.ErrNotExist) {
+		log.Printf("skipping app configuration sync, '%s' does not exist", documentPath)
+		return nil
+	}
+
+	changes, err := syncAppConfig(ctx, p.appConfigSyncer, p.projectConfig, p.env, p.console, appconfig.SyncOptions{
+		DeleteOrphans: p.projectConfig.AppConfig.DeleteOrphans,
+	}, false)
+	if err != nil {
+		return fmt.Errorf("syncing app configuration: %w", err)
+	}
+
+	if p.formatter.Kind() != output.JsonFormat {
+		p.console.Message(ctx, fmt.Sprintf("Applied %d App Configuration changes", len(changes)))
+		writeAppConfigChanges(p.console.Handles().Stdout, changes)
+	}
+
+	return nil
+}
+
+// applyRoleAssignments assigns the roles declared by the uses section of the services to their managed identity, once
+// the services and the resources they use are provisioned.
+func (p *provisionAction) applyRoleAssignments(ctx context.Context) error {
+	assignments, err := p.identityManager.ApplyRoleAssignments(ctx, p.projectConfig)
+	if err != nil {
+		return fmt.Errorf("applying the role assignments of the services: %w", err)
+	}
+
+	if len(assignments) == 0 || p.formatter.Kind() == output.JsonFormat {
+		return nil
+	}
+
+	p.console.Message(
+		ctx, fmt.Sprintf("Applied %d role assignments to the managed identities of the services", len(assignments)))
+	for _, assignment := range assignments {
+		p.console.Message(ctx, fmt.Sprintf(
+			"  %s: %s on %s", assignment.Service, assignment.Role, output.WithHighLightFormat(assignment.ResourceId)))
+	}
+
+	return nil
+}
+
+// destroyOrphans deletes, after confirmation, the resources tagged with the environment which the latest deployment
+// doesn't include, typically because they were removed from the template.
+func (p *provisionAction) destroyOrphans(
+	ctx context.Context,
+	infraManager *provisioning.Manager,
+	scope infra.Scope,
+) error {
+	stateResult, err := infraManager.State(ctx, scope)
+	if err != nil {
+		return fmt.Errorf("getting the provisioned resources: %w", err)
+	}
+
+	// Without deployed resources, every resource of the environment would be an orphan
+	if len(stateResult.State.Resources) == 0 {
+		log.Println("skipping the deletion of orphaned resources, the deployment has no resources")
+		return nil
+	}
+
+	deployed := make([]string, 0, len(stateResult.State.Resources))
+	for _, resource := range stateResult.State.Resources {
+		deployed = append(deployed, resource.Id)
+	}
+
+	subscriptionId := p.env.GetSubscriptionId()
+	orphans, err := infra.NewAzureResourceManager(p.azCli).FindOrphanedResources(
+		ctx, subscriptionId, p.env.GetEnvName(), deployed)
+	if err != nil {
+		return fmt.Errorf("finding orphaned resources: %w", err)
+	}
+
+	if len(orphans) == 0 {
+		p.console.Message(ctx, "No orphaned resources found")
+		return nil
+	}
+
+	p.console.Message(ctx, fmt.Sprintf(
+		"\n%d resources of the environment aren't provisioned by the template anymore:", len(orphans)))
+	for _, orphan := range orphans {
+		p.console.Message(ctx, fmt.Sprintf("  %s (%s)", output.WithHighLightFormat(orphan.Name), orphan.Type))
+	}
+
+	confirm, err := p.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Delete these resources?",
+		DefaultValue: false,
+	})
+	if err != nil {
+		return fmt.Errorf("prompting to delete orphaned resources: %w", err)
+	}
+
+	if !confirm {
+		p.console.Message(ctx, "Skipped deleting the orphaned resources")
+		return nil
+	}
+
+	for _, orphan := range orphans {
+		title := fmt.Sprintf("Deleting %s", orphan.Name)
+		p.console.ShowSpinner(ctx, title, input.Step)
+		err := p.azCli.DeleteResource(ctx, subscriptionId, orphan.Id)
+		p.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+		if err != nil {
+			return fmt.Errorf("deleting orphaned resource %s: %w", orphan.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// setServicesEnabled sets SERVICE_<NAME>_ENABLED in the environment for each service, so that the infrastructure
+// templates can skip the resources of the services disabled in the environment by their condition.
+func (p *provisionAction) setServicesEnabled() error {
+	for _, svc := range p.projectConfig.GetServicesStable() {
+		p.env.SetServiceProperty(svc.Name, "ENABLED", strconv.FormatBool(svc.EnabledIn(p.env.GetEnvName())))
+	}
+
+	if err := p.env.Save(); err != nil {
+		return fmt.Errorf("saving environment: %w", err)
+	}
+
+	return nil
+}
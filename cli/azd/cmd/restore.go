@@ -15,8 +15,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -62,6 +64,7 @@ func newRestoreCmd() *cobra.Command {
 		Short: "Restores the application's dependencies.",
 	}
 	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.ValidArgsFunction = serviceNameCompletion
 	return cmd
 }
 
@@ -77,6 +80,7 @@ type restoreAction struct {
 	projectManager project.ProjectManager
 	serviceManager project.ServiceManager
 	commandRunner  exec.CommandRunner
+	progressBus    *progress.Bus
 }
 
 func newRestoreAction(
@@ -91,6 +95,7 @@ func newRestoreAction(
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
 	commandRunner exec.CommandRunner,
+	progressBus *progress.Bus,
 ) actions.Action {
 	return &restoreAction{
 		flags:          flags,
@@ -104,6 +109,7 @@ func newRestoreAction(
 		serviceManager: serviceManager,
 		env:            env,
 		commandRunner:  commandRunner,
+		progressBus:    progressBus,
 	}
 }
 
@@ -115,7 +121,7 @@ type RestoreResult struct {
 func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	// Command title
 	ra.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title: "Restoring services (azd restore)",
+		Title: locale.Sprintf(locale.RestoreTitle),
 	})
 
 	serviceNameWarningCheck(ra.console, ra.flags.serviceName, "restore")
@@ -142,7 +148,7 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}
 
 	if err := ra.projectManager.EnsureFrameworkTools(ctx, ra.projectConfig, func(svc *project.ServiceConfig) bool {
-		return targetServiceName == "" || svc.Name == targetServiceName
+		return (targetServiceName == "" || svc.Name == targetServiceName) && svc.EnabledIn(ra.env.GetEnvName())
 	}); err != nil {
 		return nil, err
 	}
@@ -150,32 +156,37 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	restoreResults := map[string]*project.ServiceRestoreResult{}
 
 	for _, svc := range ra.projectConfig.GetServicesStable() {
-		stepMessage := fmt.Sprintf("Restoring service %s", svc.Name)
-		ra.console.ShowSpinner(ctx, stepMessage, input.Step)
+		step := ra.progressBus.Step(
+			string(project.ServiceEventRestore), svc.Name, locale.Sprintf(locale.RestoreStep, svc.Name))
+		step.Start(ctx)
 
 		// Skip this service if both cases are true:
 		// 1. The user specified a service name
 		// 2. This service is not the one the user specified
 		if targetServiceName != "" && targetServiceName != svc.Name {
-			ra.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			step.Skip(ctx)
+			continue
+		}
+
+		if !svc.EnabledIn(ra.env.GetEnvName()) {
+			step.SkipWithReason(ctx, locale.Sprintf(locale.ServiceDisabled, ra.env.GetEnvName()))
 			continue
 		}
 
 		restoreTask := ra.serviceManager.Restore(ctx, svc)
 		go func() {
 			for restoreProgress := range restoreTask.Progress() {
-				progressMessage := fmt.Sprintf("Restoring service %s (%s)", svc.Name, restoreProgress.Message)
-				ra.console.ShowSpinner(ctx, progressMessage, input.Step)
+				step.Progress(ctx, restoreProgress.Message)
 			}
 		}()
 
 		restoreResult, err := restoreTask.Await()
 		if err != nil {
-			ra.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			step.Fail(ctx, err)
 			return nil, err
 		}
 
-		ra.console.StopSpinner(ctx, stepMessage, input.StepDone)
+		step.Complete(ctx)
 		restoreResults[svc.Name] = restoreResult
 	}
 
@@ -192,7 +203,7 @@ func (ra *restoreAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
-			Header: "Your Azure app has been restored!",
+			Header: locale.Sprintf(locale.RestoreSuccess),
 		},
 	}, nil
 }
@@ -70,7 +70,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		DisableAutoGenTag: true,
 	}
 
-	rootCmd.CompletionOptions.HiddenDefaultCmd = true
+	// azd registers its own completion command group, see completionActions
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	root := actions.NewActionDescriptor("azd", &actions.ActionDescriptorOptions{
 		Command: rootCmd,
@@ -84,6 +85,20 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 					"no-prompt",
 					false,
 					"Accepts the default value instead of prompting, or it fails if there is no default.")
+			rootCmd.PersistentFlags().
+				BoolVar(
+					&opts.Plain,
+					"plain",
+					false,
+					"Disables spinners and colors, and writes progress as timestamped log lines.")
+			rootCmd.PersistentFlags().
+				BoolVar(
+					&opts.Machine,
+					"machine",
+					false,
+					"Writes progress, prompts and results as JSON-RPC messages on stdout, and reads answers from stdin.")
+			// The protocol is meant for IDEs and other tools embedding azd, not for users
+			_ = rootCmd.PersistentFlags().MarkHidden("machine")
 
 			// The telemetry system is responsible for reading these flags value and using it to configure the telemetry
 			// system, but we still need to add it to our flag set so that when we parse the command line with Cobra we
@@ -98,17 +113,44 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				&traceLogEndpoint, "trace-log-url", "", "Send traces to an Open Telemetry compatible endpoint.")
 			_ = rootCmd.PersistentFlags().MarkHidden("trace-log-url")
 
+			// Profiling is started by main, before the command line is parsed by Cobra, these flags are only registered.
+			var profile string
+			var timings bool
+
+			rootCmd.PersistentFlags().StringVar(
+				&profile, "profile", "", "Writes a pprof profile of azd to the current directory: cpu or mem.")
+			_ = rootCmd.PersistentFlags().MarkHidden("profile")
+
+			rootCmd.PersistentFlags().BoolVar(
+				&timings, "timings", false, "Prints the duration of each stage of the command when it completes.")
+			_ = rootCmd.PersistentFlags().MarkHidden("timings")
+
+			// HTTP tracing is enabled by main, before any request is sent, this flag is only registered.
+			var traceHttp bool
+
+			rootCmd.PersistentFlags().BoolVar(
+				&traceHttp, "trace-http", false, "Writes a redacted summary of each HTTP request and response to stderr.")
+			_ = rootCmd.PersistentFlags().MarkHidden("trace-http")
+
 			return opts
 		},
 	})
 
 	configActions(root, opts)
+	aliasActions(root)
+	appConfigActions(root)
 	envActions(root)
+	projectActions(root)
 	infraActions(root)
 	pipelineActions(root)
 	telemetryActions(root)
 	templatesActions(root)
 	authActions(root)
+	hooksActions(root)
+	completionActions(root)
+	debugActions(root)
+	supportActions(root)
+	cacheActions(root)
 
 	root.Add("version", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
@@ -124,12 +166,47 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		},
 	})
 
+	root.Add("upgrade", &actions.ActionDescriptorOptions{
+		Command:        newUpgradeCmd(),
+		FlagsResolver:  newUpgradeFlags,
+		ActionResolver: newUpgradeAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdUpgradeHelpDescription,
+			Footer:      getCmdUpgradeHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
+	root.Add("doctor", &actions.ActionDescriptorOptions{
+		Command:        newDoctorCmd(),
+		FlagsResolver:  newDoctorFlags,
+		ActionResolver: newDoctorAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDoctorHelpDescription,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
 	root.Add("show", &actions.ActionDescriptorOptions{
 		Command:        newShowCmd(),
 		FlagsResolver:  newShowFlags,
 		ActionResolver: newShowAction,
-		OutputFormats:  []output.Format{output.JsonFormat},
+		RequireProject: true,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdShowHelpDescription,
+			Footer:      getCmdShowHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupManage,
+		},
 	})
 
 	//deprecate:cmd hide login
@@ -151,6 +228,26 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		ActionResolver: newLogoutAction,
 	})
 
+	root.Add("serve", &actions.ActionDescriptorOptions{
+		Command:          newServeCmd(),
+		FlagsResolver:    newServeFlags,
+		ActionResolver:   newServeAction,
+		DisableTelemetry: true,
+	})
+
+	root.Add("dev", &actions.ActionDescriptorOptions{
+		Command:        newDevCmd(),
+		FlagsResolver:  newDevFlags,
+		ActionResolver: newDevAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdDevHelpDescription,
+			Footer:      getCmdDevHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
 	root.Add("init", &actions.ActionDescriptorOptions{
 		Command:        newInitCmd(),
 		FlagsResolver:  newInitFlags,
@@ -162,13 +259,16 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		GroupingOptions: actions.CommandGroupOptions{
 			RootLevelHelp: actions.CmdGroupConfig,
 		},
-	}).AddFlagCompletion("template", templateNameCompletion)
+	}).
+		AddFlagCompletion("template", templateNameCompletion).
+		AddFlagCompletion("subscription", subscriptionCompletion)
 
 	root.
 		Add("restore", &actions.ActionDescriptorOptions{
 			Command:        newRestoreCmd(),
 			FlagsResolver:  newRestoreFlags,
 			ActionResolver: newRestoreAction,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
@@ -181,11 +281,28 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		}).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
+	root.Add("validate", &actions.ActionDescriptorOptions{
+		Command:        newValidateCmd(),
+		FlagsResolver:  newValidateFlags,
+		ActionResolver: newValidateAction,
+		RequireProject: true,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdValidateHelpDescription,
+			Footer:      getCmdValidateHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupConfig,
+		},
+	})
+
 	root.
 		Add("build", &actions.ActionDescriptorOptions{
 			Command:        newBuildCmd(),
 			FlagsResolver:  newBuildFlags,
 			ActionResolver: newBuildAction,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 		}).
@@ -196,6 +313,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			Command:        newProvisionCmd(),
 			FlagsResolver:  newProvisionFlags,
 			ActionResolver: newProvisionAction,
+			RequireLogin:   true,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
@@ -206,6 +325,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("audit", middleware.NewAuditMiddleware).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
@@ -213,6 +334,7 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			Command:        newPackageCmd(),
 			FlagsResolver:  newPackageFlags,
 			ActionResolver: newPackageAction,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
@@ -230,6 +352,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			Command:        newDeployCmd(),
 			FlagsResolver:  newDeployFlags,
 			ActionResolver: newDeployAction,
+			RequireLogin:   true,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
@@ -240,6 +364,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("audit", middleware.NewAuditMiddleware).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
@@ -247,6 +373,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 			Command:        newUpCmd(),
 			FlagsResolver:  newUpFlags,
 			ActionResolver: newUpAction,
+			RequireLogin:   true,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
@@ -256,12 +384,16 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("audit", middleware.NewAuditMiddleware).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.Add("monitor", &actions.ActionDescriptorOptions{
 		Command:        newMonitorCmd(),
 		FlagsResolver:  newMonitorFlags,
 		ActionResolver: newMonitorAction,
+		RequireLogin:   true,
+		RequireProject: true,
 		HelpOptions: actions.ActionHelpOptions{
 			Description: getCmdMonitorHelpDescription,
 			Footer:      getCmdMonitorHelpFooter,
@@ -271,11 +403,48 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		},
 	})
 
+	root.Add("port-forward", &actions.ActionDescriptorOptions{
+		Command:        newPortForwardCmd(),
+		FlagsResolver:  newPortForwardFlags,
+		ActionResolver: newPortForwardAction,
+		RequireLogin:   true,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdPortForwardHelpDescription,
+			Footer:      getCmdPortForwardHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	})
+
+	root.Add("test", &actions.ActionDescriptorOptions{
+		Command:        newTestCmd(),
+		FlagsResolver:  newServiceTestFlags,
+		ActionResolver: newServiceTestAction,
+		RequireProject: true,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdTestHelpDescription,
+			Footer:      getCmdTestHelpFooter,
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupMonitor,
+		},
+	}).AddFlagCompletion("service", func(
+		cmd *cobra.Command,
+		args []string,
+		toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		return serviceNameCompletion(cmd, nil, toComplete)
+	})
+
 	root.
 		Add("down", &actions.ActionDescriptorOptions{
 			Command:        newDownCmd(),
 			FlagsResolver:  newDownFlags,
 			ActionResolver: newDownAction,
+			RequireLogin:   true,
+			RequireProject: true,
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 			HelpOptions: actions.ActionHelpOptions{
@@ -286,6 +455,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("audit", middleware.NewAuditMiddleware).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	// Register any global middleware defined by the caller
@@ -298,8 +469,16 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 	// Global middleware registration
 	root.
 		UseMiddleware("debug", middleware.NewDebugMiddleware).
+		UseMiddleware("machine", middleware.NewMachineMiddleware).
 		UseMiddlewareWhen("telemetry", middleware.NewTelemetryMiddleware, func(descriptor *actions.ActionDescriptor) bool {
 			return !descriptor.Options.DisableTelemetry
+		}).
+		UseMiddleware("error", middleware.NewErrorMiddleware).
+		UseMiddlewareWhen("projectGuard", middleware.NewProjectGuardMiddleware, func(descriptor *actions.ActionDescriptor) bool {
+			return descriptor.Options.RequireProject
+		}).
+		UseMiddlewareWhen("loginGuard", middleware.NewLoginGuardMiddleware, func(descriptor *actions.ActionDescriptor) bool {
+			return descriptor.Options.RequireLogin
 		})
 
 	registerCommonDependencies(ioc.Global)
@@ -314,6 +493,8 @@ func NewRootCmd(staticHelp bool, middlewareChain []*actions.MiddlewareRegistrati
 		panic(err)
 	}
 
+	registerDynamicCompletions(cmd)
+
 	// The help template has to be set after calling `BuildCommand()` to ensure the command tree is built
 	cmd.SetHelpTemplate(generateCmdHelp(
 		cmd,
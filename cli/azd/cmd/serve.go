@@ -0,0 +1,132 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/serve"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The environment variable setting the token of the clients, instead of a generated token
+const serveTokenEnvVarName = "AZD_SERVE_TOKEN"
+
+type serveFlags struct {
+	port   int
+	global *internal.GlobalCommandOptions
+}
+
+func (f *serveFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.IntVar(&f.port, "port", 0, "The local port to listen on. Defaults to a free port.")
+	f.global = global
+}
+
+func newServeFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *serveFlags {
+	flags := &serveFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newServeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the operations of azd over a local HTTP endpoint.",
+		// The endpoint is meant for IDEs and other tools embedding azd, not for users
+		Hidden: true,
+	}
+}
+
+// serveEndpoint is written on stdout once the server listens, for the client which started it.
+type serveEndpoint struct {
+	Endpoint string `json:"endpoint"`
+	Token    string `json:"token"`
+}
+
+type serveAction struct {
+	flags  *serveFlags
+	writer io.Writer
+}
+
+func newServeAction(flags *serveFlags, writer io.Writer) actions.Action {
+	return &serveAction{
+		flags:  flags,
+		writer: writer,
+	}
+}
+
+func (s *serveAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	token := os.Getenv(serveTokenEnvVarName)
+	if token == "" {
+		generated, err := serve.NewToken()
+		if err != nil {
+			return nil, err
+		}
+		token = generated
+	}
+
+	// Only local clients can reach the server
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", s.flags.port))
+	if err != nil {
+		return nil, fmt.Errorf("listening on port %d: %w", s.flags.port, err)
+	}
+
+	server := &http.Server{
+		Handler:           serve.NewServer(token, runServedCommand),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	endpoint := serveEndpoint{Endpoint: "http://" + listener.Addr().String(), Token: token}
+	if err := json.NewEncoder(s.writer).Encode(endpoint); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// runServedCommand runs an azd command for a request of the server, in the process of the server, like main runs the
+// command of its arguments.
+func runServedCommand(ctx context.Context, args []string, stdout io.Writer) error {
+	log.Printf("serving: azd %s", strings.Join(args, " "))
+
+	rootCmd := NewRootCmd(false, nil)
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(io.Discard)
+	// The commands run without prompting, they never read stdin
+	rootCmd.SetIn(strings.NewReader(""))
+
+	err := rootCmd.ExecuteContext(ctx)
+	if err != nil {
+		log.Printf("served command failed: %v", err)
+	}
+
+	return err
+}
@@ -8,21 +8,26 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
-	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
-	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
+// lastDeploymentConfigKey is the key, within the environment configuration of a service, that records the time
+// the service was last deployed with azd.
+const lastDeploymentConfigKey = "lastDeployment"
+
 type showFlags struct {
 	global *internal.GlobalCommandOptions
 	envFlag
@@ -42,44 +47,48 @@ func newShowFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *sh
 
 func newShowCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:    "show --output json",
-		Short:  "Display information about your app and its resources.",
-		Hidden: true,
+		Use:   "show [service]",
+		Short: "Display information about your app and its resources.",
 	}
+	cmd.Args = cobra.MaximumNArgs(1)
+	cmd.ValidArgsFunction = serviceNameCompletion
 
 	return cmd
 }
 
 type showAction struct {
+	args            []string
 	projectConfig   *project.ProjectConfig
 	resourceManager project.ResourceManager
+	serviceManager  project.ServiceManager
 	console         input.Console
 	formatter       output.Formatter
 	writer          io.Writer
-	azCli           azcli.AzCli
 	azdCtx          *azdcontext.AzdContext
 	env             *environment.Environment
 	flags           *showFlags
 }
 
 func newShowAction(
+	args []string,
 	console input.Console,
 	formatter output.Formatter,
 	writer io.Writer,
-	azCli azcli.AzCli,
 	projectConfig *project.ProjectConfig,
 	resourceManager project.ResourceManager,
+	serviceManager project.ServiceManager,
 	azdCtx *azdcontext.AzdContext,
 	env *environment.Environment,
 	flags *showFlags,
 ) actions.Action {
 	return &showAction{
+		args:            args,
 		projectConfig:   projectConfig,
 		resourceManager: resourceManager,
+		serviceManager:  serviceManager,
 		console:         console,
 		formatter:       formatter,
 		writer:          writer,
-		azCli:           azCli,
 		azdCtx:          azdCtx,
 		env:             env,
 		flags:           flags,
@@ -87,12 +96,27 @@ func newShowAction(
 }
 
 func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	services := s.projectConfig.Services
+	if len(s.args) == 1 {
+		svc, has := s.projectConfig.Services[s.args[0]]
+		if !has {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", s.args[0])
+		}
+
+		services = map[string]*project.ServiceConfig{svc.Name: svc}
+	}
+
 	res := contracts.ShowResult{
 		Name:     s.projectConfig.Name,
-		Services: make(map[string]contracts.ShowService, len(s.projectConfig.Services)),
+		Services: make(map[string]contracts.ShowService, len(services)),
+		Environment: &contracts.ShowEnvironment{
+			Name:           s.env.GetEnvName(),
+			SubscriptionId: s.env.GetSubscriptionId(),
+			Location:       s.env.GetLocation(),
+		},
 	}
 
-	for name, svc := range s.projectConfig.Services {
+	for name, svc := range services {
 		path, err := getFullPathToProjectForService(svc)
 		if err != nil {
 			return nil, err
@@ -103,6 +127,21 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 				Path: path,
 				Type: showTypeFromLanguage(svc.Language),
 			},
+			Host: string(svc.Host),
+		}
+
+		if serviceTarget, err := s.serviceManager.GetServiceTarget(ctx, svc); err == nil {
+			for _, capability := range serviceTarget.Capabilities().List() {
+				showSvc.Capabilities = append(showSvc.Capabilities, string(capability))
+			}
+		} else {
+			log.Printf("ignoring error determining capabilities for service %s: %v", name, err)
+		}
+
+		if timestamp, has := getLastDeployment(s.env, name); has {
+			showSvc.LastDeployment = &contracts.ShowDeployment{
+				Timestamp: timestamp,
+			}
 		}
 
 		res.Services[name] = showSvc
@@ -111,26 +150,33 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	// Add information about the target of each service, if we can determine it (if the infrastructure has
 	// not been deployed, for example, we'll just not include target information)
 	if subId := s.env.GetSubscriptionId(); subId != "" {
-		resourceManager := infra.NewAzureResourceManager(s.azCli)
-		envName := s.env.GetEnvName()
-
-		rgName, err := resourceManager.FindResourceGroupForEnvironment(ctx, subId, envName)
+		rgName, err := s.resourceManager.GetResourceGroupName(ctx, subId, s.projectConfig)
 		if err == nil {
-			for svcName, serviceConfig := range s.projectConfig.Services {
+			res.Environment.ResourceGroup = rgName
+
+			for svcName, serviceConfig := range services {
+				resSvc := res.Services[svcName]
+
 				if resources, err := s.resourceManager.GetServiceResources(ctx, subId, rgName, serviceConfig); err == nil {
 					resourceIds := make([]string, len(resources))
 					for idx, res := range resources {
 						resourceIds[idx] = res.Id
 					}
 
-					resSvc := res.Services[svcName]
 					resSvc.Target = &contracts.ShowTargetArm{
 						ResourceIds: resourceIds,
 					}
-					res.Services[svcName] = resSvc
 				} else {
 					log.Printf("ignoring error determining resource id for service %s: %v", svcName, err)
 				}
+
+				if endpoints, err := s.serviceEndpoints(ctx, subId, serviceConfig); err == nil {
+					resSvc.Endpoints = endpoints
+				} else {
+					log.Printf("ignoring error determining endpoints for service %s: %v", svcName, err)
+				}
+
+				res.Services[svcName] = resSvc
 			}
 		} else {
 			log.Printf(
@@ -142,7 +188,126 @@ func (s *showAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		log.Printf("provision has not been run, resource ids will not be available")
 	}
 
-	return nil, s.formatter.Format(res, s.writer, nil)
+	if s.formatter.Kind() == output.JsonFormat {
+		return nil, s.formatter.Format(res, s.writer, nil)
+	}
+
+	s.displayShowResult(res)
+
+	return nil, nil
+}
+
+// serviceEndpoints returns the endpoints exposed by the deployed target resource of the specified service.
+func (s *showAction) serviceEndpoints(
+	ctx context.Context,
+	subscriptionId string,
+	serviceConfig *project.ServiceConfig,
+) ([]string, error) {
+	serviceTarget, err := s.serviceManager.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting service target: %w", err)
+	}
+
+	targetResource, err := s.resourceManager.GetTargetResource(ctx, subscriptionId, serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource: %w", err)
+	}
+
+	return serviceTarget.Endpoints(ctx, serviceConfig, targetResource)
+}
+
+func (s *showAction) displayShowResult(res contracts.ShowResult) {
+	fmt.Fprintf(s.writer, "\n%s\n", output.WithBold(res.Name))
+
+	if res.Environment != nil {
+		fmt.Fprintf(s.writer, "  Environment:    %s\n", output.WithHighLightFormat(res.Environment.Name))
+		if res.Environment.SubscriptionId != "" {
+			fmt.Fprintf(s.writer, "  Subscription:   %s\n", res.Environment.SubscriptionId)
+		}
+		if res.Environment.Location != "" {
+			fmt.Fprintf(s.writer, "  Location:       %s\n", res.Environment.Location)
+		}
+		if res.Environment.ResourceGroup != "" {
+			fmt.Fprintf(s.writer, "  Resource group: %s %s\n",
+				res.Environment.ResourceGroup,
+				output.WithLinkFormat(fmt.Sprintf(
+					"https://portal.azure.com/#@/resource/subscriptions/%s/resourceGroups/%s/overview",
+					res.Environment.SubscriptionId,
+					res.Environment.ResourceGroup)))
+		} else {
+			fmt.Fprintf(s.writer, "  %s\n", output.WithGrayFormat(
+				"The infrastructure has not been provisioned yet. Run `azd provision` to create it."))
+		}
+	}
+
+	serviceNames := maps.Keys(res.Services)
+	slices.Sort(serviceNames)
+
+	for _, name := range serviceNames {
+		svc := res.Services[name]
+
+		fmt.Fprintf(s.writer, "\n  %s\n", output.WithBold(name))
+		fmt.Fprintf(s.writer, "    Project:  %s (%s)\n", svc.Project.Path, svc.Project.Type)
+		fmt.Fprintf(s.writer, "    Host:     %s\n", svc.Host)
+
+		if len(svc.Capabilities) > 0 {
+			descriptions := make([]string, len(svc.Capabilities))
+			for idx, capability := range svc.Capabilities {
+				descriptions[idx] = project.ServiceTargetCapability(capability).Description()
+			}
+			fmt.Fprintf(s.writer, "    Supports: %s\n", strings.Join(descriptions, ", "))
+		}
+
+		for _, endpoint := range svc.Endpoints {
+			fmt.Fprintf(s.writer, "    Endpoint: %s\n", output.WithLinkFormat(endpoint))
+		}
+
+		if svc.Target != nil {
+			for _, resourceId := range svc.Target.ResourceIds {
+				fmt.Fprintf(s.writer, "    Resource: %s\n", output.WithLinkFormat(
+					fmt.Sprintf("https://portal.azure.com/#@/resource%s", resourceId)))
+			}
+		}
+
+		if svc.LastDeployment != nil {
+			fmt.Fprintf(s.writer, "    Deployed: %s\n", svc.LastDeployment.Timestamp.Local().Format(time.RFC1123))
+		} else {
+			fmt.Fprintf(s.writer, "    Deployed: %s\n", output.WithGrayFormat("never"))
+		}
+	}
+
+	fmt.Fprintln(s.writer)
+}
+
+// setLastDeployment records the time the specified service was deployed in the environment configuration.
+func setLastDeployment(env *environment.Environment, serviceName string, timestamp time.Time) error {
+	path := fmt.Sprintf("services.%s.%s", serviceName, lastDeploymentConfigKey)
+	if err := env.Config.Set(path, timestamp.UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("setting last deployment of service %s: %w", serviceName, err)
+	}
+
+	return nil
+}
+
+// getLastDeployment returns the time the specified service was last deployed, when it has been recorded.
+func getLastDeployment(env *environment.Environment, serviceName string) (time.Time, bool) {
+	value, has := env.Config.Get(fmt.Sprintf("services.%s.%s", serviceName, lastDeploymentConfigKey))
+	if !has {
+		return time.Time{}, false
+	}
+
+	timestampValue, ok := value.(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, timestampValue)
+	if err != nil {
+		log.Printf("ignoring invalid last deployment time for service %s: %v", serviceName, err)
+		return time.Time{}, false
+	}
+
+	return timestamp, true
 }
 
 func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowType {
@@ -155,6 +320,8 @@ func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowTy
 		return contracts.ShowTypeNode
 	case project.ServiceLanguageJava:
 		return contracts.ShowTypeJava
+	case project.ServiceLanguageGo:
+		return contracts.ShowTypeGo
 	default:
 		panic(fmt.Sprintf("unknown language %s", language))
 	}
@@ -214,3 +381,24 @@ func getFullPathToProjectForService(svc *project.ServiceConfig) (string, error)
 
 	return svc.Path(), nil
 }
+
+func getCmdShowHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Display information about your app and its resources.",
+		[]string{
+			formatHelpNote("Shows the services of the project, the active environment and the Azure resources" +
+				" each service is deployed to, with links to the Azure Portal."),
+			formatHelpNote(fmt.Sprintf("When %s is set, only the specific service is shown.",
+				output.WithHighLightFormat("<service>"))),
+		})
+}
+
+func getCmdShowHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Display information about all the services of the current project.": output.WithHighLightFormat(
+			"azd show"),
+		"Display information about the service named 'api'.": output.WithHighLightFormat("azd show api"),
+		"Display information about the current project as JSON.": output.WithHighLightFormat(
+			"azd show --output json"),
+	})
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lastDeployment(t *testing.T) {
+	env := environment.EphemeralWithValues("test", nil)
+
+	_, has := getLastDeployment(env, "api")
+	require.False(t, has)
+
+	deployedAt := time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC)
+	require.NoError(t, setLastDeployment(env, "api", deployedAt))
+
+	actual, has := getLastDeployment(env, "api")
+	require.True(t, has)
+	require.True(t, deployedAt.Equal(actual))
+
+	_, has = getLastDeployment(env, "web")
+	require.False(t, has)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/logging"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/support"
+	"github.com/spf13/cobra"
+)
+
+func supportActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("support", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "support",
+			Short: "Collect information to include when reporting an issue.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupAbout,
+		},
+	})
+
+	group.Add("bundle", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "bundle [path]",
+			Short: "Create a support bundle to attach to an issue.",
+			Args:  cobra.MaximumNArgs(1),
+		},
+		ActionResolver: newSupportBundleAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdSupportBundleHelpDescription,
+			Footer:      getCmdSupportBundleHelpFooter,
+		},
+	})
+
+	return group
+}
+
+type supportBundleAction struct {
+	args          []string
+	console       input.Console
+	commandRunner exec.CommandRunner
+}
+
+func newSupportBundleAction(
+	args []string,
+	console input.Console,
+	commandRunner exec.CommandRunner,
+) actions.Action {
+	return &supportBundleAction{
+		args:          args,
+		console:       console,
+		commandRunner: commandRunner,
+	}
+}
+
+func (a *supportBundleAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	var bundlePath string
+	if len(a.args) > 0 {
+		path, err := filepath.Abs(a.args[0])
+		if err != nil {
+			return nil, fmt.Errorf("resolving bundle path: %w", err)
+		}
+
+		bundlePath = path
+	} else {
+		path, err := support.DefaultPath(time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		bundlePath = path
+	}
+
+	spinnerMessage := "Collecting diagnostics"
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	bundle := support.Bundle{
+		ToolVersions: support.ToolVersions(ctx, a.commandRunner),
+	}
+
+	// The log of the previous command, the one the user is reporting an issue about
+	logPath, err := latestLogFile()
+	if err != nil && !errors.Is(err, logging.ErrNoLogs) {
+		a.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+		return nil, err
+	}
+	bundle.LogPath = logPath
+
+	if err := support.Write(bundlePath, bundle); err != nil {
+		a.console.StopSpinner(ctx, spinnerMessage, input.StepFailed)
+		return nil, err
+	}
+
+	a.console.StopSpinner(ctx, spinnerMessage, input.StepDone)
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Support bundle written to %s", output.WithHighLightFormat(bundlePath)),
+			FollowUp: fmt.Sprintf(
+				"Review its contents, then attach it to a new issue at %s",
+				output.WithLinkFormat(support.IssueUrl),
+			),
+		},
+	}, nil
+}
+
+func getCmdSupportBundleHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Create a support bundle to attach to an issue.",
+		[]string{
+			formatHelpNote("The bundle includes the version of azd and of the installed tools, the names of the " +
+				"environment variables used by azd, and the log of the previous azd command."),
+			formatHelpNote("Secrets found in the log, like access tokens and passwords, are redacted. Values of " +
+				"environment variables are never included."),
+			formatHelpNote("A bundle is also written when azd crashes, the path is printed along with the error."),
+		})
+}
+
+func getCmdSupportBundleHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Create a support bundle in the support folder of the azd configuration directory.": output.WithHighLightFormat(
+			"azd support bundle",
+		),
+		"Create a support bundle at the specified path.": output.WithHighLightFormat(
+			"azd support bundle ./azd-support.zip",
+		),
+	})
+}
@@ -5,13 +5,24 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"os"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
@@ -63,6 +74,27 @@ func templatesActions(root *actions.ActionDescriptor) *actions.ActionDescriptor
 		DefaultFormat:  output.TableFormat,
 	})
 
+	group.Add("validate", &actions.ActionDescriptorOptions{
+		Command:        newTemplateValidateCmd(),
+		FlagsResolver:  newTemplateValidateFlags,
+		ActionResolver: newTemplateValidateAction,
+		RequireProject: true,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Footer: getCmdTemplateValidateHelpFooter,
+		},
+	}).AddFlagCompletion("subscription", subscriptionCompletion)
+
+	group.Add("package", &actions.ActionDescriptorOptions{
+		Command:        newTemplatePackageCmd(),
+		FlagsResolver:  newTemplatePackageFlags,
+		ActionResolver: newTemplatePackageAction,
+		RequireProject: true,
+		OutputFormats:  []output.Format{output.JsonFormat},
+		DefaultFormat:  output.JsonFormat,
+	})
+
 	return group
 }
 
@@ -143,9 +175,240 @@ func newTemplateShowCmd() *cobra.Command {
 		Use:   "show <template>",
 		Short: "Show details for a given template.",
 		Args:  cobra.ExactArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return templateNameCompletion(cmd, args, toComplete)
+		},
+	}
+}
+
+type templateValidateFlags struct {
+	smokeTest    bool
+	subscription string
+	location     string
+	global       *internal.GlobalCommandOptions
+}
+
+func (f *templateValidateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&f.smokeTest,
+		"smoke-test",
+		false,
+		"Provisions the template in a new environment, and deletes the provisioned resources once it completes.",
+	)
+	local.StringVar(
+		&f.subscription,
+		"subscription",
+		"",
+		"ID of the sandbox Azure subscription the smoke test provisions the template to.",
+	)
+	local.StringVarP(&f.location, "location", "l", "", "Azure location the smoke test provisions the template to.")
+
+	f.global = global
+}
+
+func newTemplateValidateFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *templateValidateFlags {
+	flags := &templateValidateFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTemplateValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the current project follows the conventions of azd templates.",
+		Args:  cobra.NoArgs,
 	}
 }
 
+type templateValidateAction struct {
+	flags         *templateValidateFlags
+	azdCtx        *azdcontext.AzdContext
+	formatter     output.Formatter
+	writer        io.Writer
+	console       input.Console
+	commandRunner exec.CommandRunner
+}
+
+func newTemplateValidateAction(
+	flags *templateValidateFlags,
+	azdCtx *azdcontext.AzdContext,
+	formatter output.Formatter,
+	writer io.Writer,
+	console input.Console,
+	commandRunner exec.CommandRunner,
+) actions.Action {
+	return &templateValidateAction{
+		flags:         flags,
+		azdCtx:        azdCtx,
+		formatter:     formatter,
+		writer:        writer,
+		console:       console,
+		commandRunner: commandRunner,
+	}
+}
+
+func (a *templateValidateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.smokeTest && (a.flags.subscription == "" || a.flags.location == "") {
+		return nil, errors.New("--subscription and --location are required with --smoke-test")
+	}
+
+	result, err := templates.Validate(ctx, a.azdCtx.ProjectDirectory())
+	if err != nil {
+		return nil, fmt.Errorf("validating template: %w", err)
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(result, a.writer, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		displayValidationIssues(ctx, a.console, result.Issues)
+	}
+
+	if result.HasErrors() {
+		return nil, errors.New("the template doesn't follow the conventions of azd templates")
+	}
+
+	if !a.flags.smokeTest {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "The template follows the conventions of azd templates.",
+			},
+		}, nil
+	}
+
+	azdPath, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("finding the azd executable: %w", err)
+	}
+
+	environmentName := fmt.Sprintf("smoke-%s", time.Now().Format("20060102150405"))
+	a.console.Message(ctx, fmt.Sprintf("\nProvisioning the template in environment %s for the smoke test.",
+		output.WithHighLightFormat(environmentName)))
+
+	if err := templates.RunSmokeTest(ctx, a.commandRunner, azdPath, a.azdCtx.ProjectDirectory(),
+		templates.SmokeTestOptions{
+			EnvironmentName: environmentName,
+			SubscriptionId:  a.flags.subscription,
+			Location:        a.flags.location,
+		}); err != nil {
+		return nil, fmt.Errorf("smoke test failed: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "The template follows the conventions of azd templates, and provisions successfully.",
+		},
+	}, nil
+}
+
+// displayValidationIssues displays the errors and the warnings of a validation.
+func displayValidationIssues(ctx context.Context, console input.Console, issues []templates.ValidationIssue) {
+	for _, issue := range issues {
+		if issue.Severity == templates.ValidationError {
+			console.Message(ctx, output.WithErrorFormat("(x) %s: %s", issue.Check, issue.Message))
+		} else {
+			console.Message(ctx, output.WithWarningFormat("(!) %s: %s", issue.Check, issue.Message))
+		}
+	}
+}
+
+func getCmdTemplateValidateHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Check the current project follows the conventions of azd templates.": output.WithHighLightFormat(
+			"azd template validate"),
+		"Check the template, and provision it in a sandbox subscription.": output.WithHighLightFormat(
+			"azd template validate --smoke-test --subscription <subscription-id> --location <location>"),
+	})
+}
+
+type templatePackageFlags struct {
+	author string
+	global *internal.GlobalCommandOptions
+}
+
+func (f *templatePackageFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.author, "author", "", "The author of the template in the template gallery.")
+
+	f.global = global
+}
+
+func newTemplatePackageFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *templatePackageFlags {
+	flags := &templatePackageFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTemplatePackageCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "package",
+		Short: "Generate the template gallery metadata of the current project.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type templatePackageAction struct {
+	flags     *templatePackageFlags
+	azdCtx    *azdcontext.AzdContext
+	formatter output.Formatter
+	writer    io.Writer
+	gitCli    git.GitCli
+}
+
+func newTemplatePackageAction(
+	flags *templatePackageFlags,
+	azdCtx *azdcontext.AzdContext,
+	formatter output.Formatter,
+	writer io.Writer,
+	gitCli git.GitCli,
+) actions.Action {
+	return &templatePackageAction{
+		flags:     flags,
+		azdCtx:    azdCtx,
+		formatter: formatter,
+		writer:    writer,
+		gitCli:    gitCli,
+	}
+}
+
+func (a *templatePackageAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	result, err := templates.Validate(ctx, a.azdCtx.ProjectDirectory())
+	if err != nil {
+		return nil, fmt.Errorf("validating template: %w", err)
+	}
+
+	if result.HasErrors() {
+		return nil, fmt.Errorf(
+			"the template doesn't follow the conventions of azd templates, run '%s' for details",
+			output.WithHighLightFormat("azd template validate"))
+	}
+
+	projectConfig, err := project.Load(ctx, a.azdCtx.ProjectPath())
+	if err != nil {
+		return nil, fmt.Errorf("loading project: %w", err)
+	}
+
+	// The template is published from the repository of its origin remote, when it has one
+	source, err := a.gitCli.GetRemoteUrl(ctx, a.azdCtx.ProjectDirectory(), "origin")
+	if err != nil {
+		log.Printf("failed reading the origin remote of the template: %v", err)
+		source = ""
+	}
+
+	metadata, err := templates.NewGalleryMetadata(projectConfig, a.flags.author, source)
+	if err != nil {
+		return nil, fmt.Errorf("generating gallery metadata: %w", err)
+	}
+
+	return nil, a.formatter.Format(metadata, a.writer, nil)
+}
+
 func formatTemplates(
 	ctx context.Context,
 	formatter output.Formatter,
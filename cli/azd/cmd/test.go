@@ -0,0 +1,244 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type serviceTestFlags struct {
+	service string
+	e2e     bool
+	report  string
+	global  *internal.GlobalCommandOptions
+	envFlag
+}
+
+func (f *serviceTestFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.service, "service", "", "Only runs the tests of the specified service.")
+	local.BoolVar(&f.e2e, "e2e", false, "Runs the end to end tests against the deployed services.")
+	local.StringVar(&f.report, "report", "", "Writes a JUnit report of the results to the specified file.")
+	f.envFlag.Bind(local, global)
+	f.global = global
+}
+
+func newServiceTestFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *serviceTestFlags {
+	flags := &serviceTestFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newTestCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test",
+		Short: "Run the tests of the application's services.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type serviceTestAction struct {
+	flags           *serviceTestFlags
+	projectConfig   *project.ProjectConfig
+	env             *environment.Environment
+	testRunner      *project.TestRunner
+	serviceManager  project.ServiceManager
+	resourceManager project.ResourceManager
+	console         input.Console
+}
+
+func newServiceTestAction(
+	flags *serviceTestFlags,
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	testRunner *project.TestRunner,
+	serviceManager project.ServiceManager,
+	resourceManager project.ResourceManager,
+	console input.Console,
+) actions.Action {
+	return &serviceTestAction{
+		flags:           flags,
+		projectConfig:   projectConfig,
+		env:             env,
+		testRunner:      testRunner,
+		serviceManager:  serviceManager,
+		resourceManager: resourceManager,
+		console:         console,
+	}
+}
+
+func (t *serviceTestAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	suite := project.TestSuiteUnit
+	if t.flags.e2e {
+		suite = project.TestSuiteE2e
+	}
+
+	t.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Running tests (azd test)",
+		TitleNote: fmt.Sprintf(
+			"Running the %s tests of the services with the values of environment %s",
+			suite,
+			output.WithHighLightFormat(t.env.GetEnvName()),
+		),
+	})
+
+	if t.flags.service != "" {
+		if _, has := t.projectConfig.Services[t.flags.service]; !has {
+			return nil, fmt.Errorf("service name '%s' doesn't exist", t.flags.service)
+		}
+	}
+
+	var results []*project.TestResult
+	for _, svc := range t.projectConfig.GetServicesStable() {
+		if t.flags.service != "" && svc.Name != t.flags.service {
+			continue
+		}
+
+		if t.testRunner.Command(svc, suite) == "" {
+			continue
+		}
+
+		env := t.env.Environ()
+		if suite == project.TestSuiteE2e {
+			endpointEnv, err := t.serviceEndpointEnv(ctx, svc)
+			if err != nil {
+				return nil, err
+			}
+			env = append(env, endpointEnv...)
+		}
+
+		result, err := t.testRunner.Run(ctx, svc, suite, env, t.console.Handles().Stdout)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	// The test steps of the generated pipelines run for every project, so a project without tests isn't a failure
+	// unless the tests of a specific service were requested
+	if len(results) == 0 {
+		if t.flags.service != "" {
+			return nil, fmt.Errorf("service '%s' doesn't configure %s tests, add a test.%s command to it in azure.yaml",
+				t.flags.service, suite, suite)
+		}
+
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("No services configure %s tests.", suite),
+				FollowUp: fmt.Sprintf("Add a test.%s command to the services in azure.yaml to run their %s tests.",
+					suite, suite),
+			},
+		}, nil
+	}
+
+	if t.flags.report != "" {
+		if err := writeTestReport(t.flags.report, results); err != nil {
+			return nil, err
+		}
+	}
+
+	var failed []string
+	for _, result := range results {
+		if !result.Passed() {
+			failed = append(failed, result.Service)
+		}
+	}
+
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("the %s tests of %s failed", suite, strings.Join(failed, ", "))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("The %s tests of %d services passed.", suite, len(results)),
+		},
+	}, nil
+}
+
+// serviceEndpointEnv returns the endpoints of the deployed service as the SERVICE_ENDPOINT and SERVICE_ENDPOINTS
+// environment variables, so end to end tests can call the service.
+func (t *serviceTestAction) serviceEndpointEnv(ctx context.Context, serviceConfig *project.ServiceConfig) ([]string, error) {
+	if t.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Please run `azd provision`",
+		)
+	}
+
+	serviceTarget, err := t.serviceManager.GetServiceTarget(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	targetResource, err := t.resourceManager.GetTargetResource(ctx, t.env.GetSubscriptionId(), serviceConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting target resource: %w", err)
+	}
+
+	endpoints, err := serviceTarget.Endpoints(ctx, serviceConfig, targetResource)
+	if err != nil {
+		return nil, fmt.Errorf("getting endpoints of service '%s': %w", serviceConfig.Name, err)
+	}
+
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	return []string{
+		fmt.Sprintf("SERVICE_ENDPOINT=%s", endpoints[0]),
+		fmt.Sprintf("SERVICE_ENDPOINTS=%s", strings.Join(endpoints, ",")),
+	}, nil
+}
+
+func writeTestReport(path string, results []*project.TestResult) error {
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating test report directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating test report: %w", err)
+	}
+	defer file.Close()
+
+	return project.WriteJUnitReport(file, results)
+}
+
+func getCmdTestHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription("Run the tests of the application's services.", []string{
+		formatHelpNote("Runs the test.unit command of each service in 'azure.yaml' from the directory of the service," +
+			" or the test.e2e command when " + output.WithHighLightFormat("--e2e") + " is set."),
+		formatHelpNote("The values of the environment are available to the tests as environment variables. End to" +
+			" end tests also get the endpoint of the deployed service as SERVICE_ENDPOINT."),
+		formatHelpNote("When " + output.WithHighLightFormat("--report") + " is set, a JUnit report of the results" +
+			" is written for CI systems. The workflow generated by " + output.WithHighLightFormat("azd pipeline config") +
+			" runs the unit tests before provisioning and the end to end tests after deploying."),
+	})
+}
+
+func getCmdTestHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Run the unit tests of all services.":                  output.WithHighLightFormat("azd test"),
+		"Run the end to end tests of the service named 'api'.": output.WithHighLightFormat("azd test --service api --e2e"),
+		"Run the unit tests and write a JUnit report.": output.WithHighLightFormat(
+			"azd test --report test-results/azd.xml",
+		),
+	})
+}
@@ -3,15 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
-	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -20,7 +21,10 @@ import (
 type upFlags struct {
 	provisionFlags
 	deployFlags
-	global *internal.GlobalCommandOptions
+	resume        bool
+	skipProvision bool
+	skipDeploy    bool
+	global        *internal.GlobalCommandOptions
 	envFlag
 }
 
@@ -28,6 +32,15 @@ func (u *upFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptio
 	u.envFlag.Bind(local, global)
 	u.global = global
 
+	local.BoolVar(
+		&u.resume,
+		"resume",
+		false,
+		"Resumes a previously failed run, starting from the stage that failed.",
+	)
+	local.BoolVar(&u.skipProvision, "skip-provision", false, "Skips provisioning of Azure resources.")
+	local.BoolVar(&u.skipDeploy, "skip-deploy", false, "Skips deployment of the application's code.")
+
 	u.provisionFlags.bindNonCommon(local, global)
 	u.provisionFlags.setCommon(&u.envFlag)
 	u.deployFlags.bindNonCommon(local, global)
@@ -48,6 +61,34 @@ func newUpCmd() *cobra.Command {
 	}
 }
 
+// upStage is a stage of the 'azd up' workflow. Stages run in the order defined by upStages.
+type upStage string
+
+const (
+	// upStageInit selects the subscription and location of the environment and packages the services, so a service
+	// failing to build stops the workflow before any Azure resource is created.
+	upStageInit      upStage = "init"
+	upStageProvision upStage = "provision"
+	upStageDeploy    upStage = "deploy"
+)
+
+var upStages = []upStage{upStageInit, upStageProvision, upStageDeploy}
+
+// The environment config path used to store the last stage of 'azd up' that completed successfully.
+const upCheckpointConfigPath = "up.checkpoint"
+
+// remainingUpStages returns the stages that still need to run after the specified stage has completed.
+// When lastCompleted is empty or unknown, all stages are returned.
+func remainingUpStages(lastCompleted upStage) []upStage {
+	for i, stage := range upStages {
+		if stage == lastCompleted {
+			return upStages[i+1:]
+		}
+	}
+
+	return upStages
+}
+
 type upAction struct {
 	flags                      *upFlags
 	env                        *environment.Environment
@@ -62,7 +103,6 @@ type upAction struct {
 func newUpAction(
 	flags *upFlags,
 	env *environment.Environment,
-	_ auth.LoggedInGuard,
 	accountManager account.Manager,
 	packageActionInitializer actions.ActionInitializer[*packageAction],
 	provisionActionInitializer actions.ActionInitializer[*provisionAction],
@@ -102,58 +142,152 @@ func (u *upAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			output.WithWarningFormat("WARNING: The '--service' flag is deprecated and will be removed in a future release."))
 	}
 
-	err := provisioning.EnsureSubscriptionAndLocation(ctx, u.console, u.env, u.accountManager)
+	stages, err := u.stagesToRun(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	packageAction, err := u.packageActionInitializer()
-	if err != nil {
-		return nil, err
+	var result *actions.ActionResult
+	for _, stage := range stages {
+		// Skipped stages are checkpointed as well, so resuming doesn't run a stage the user asked to skip
+		if (stage == upStageProvision && u.flags.skipProvision) || (stage == upStageDeploy && u.flags.skipDeploy) {
+			log.Printf("skipping '%s' stage of 'azd up'", stage)
+		} else {
+			stageResult, err := u.runStage(ctx, stage)
+			if err != nil {
+				return nil, err
+			}
+
+			if stageResult != nil {
+				result = stageResult
+			}
+		}
+
+		if err := u.saveCheckpoint(stage); err != nil {
+			return nil, err
+		}
 	}
-	packageOptions := &middleware.Options{CommandPath: "package"}
-	_, err = u.runner.RunChildAction(ctx, packageOptions, packageAction)
-	if err != nil {
+
+	// The workflow completed, a subsequent '--resume' should start over
+	if err := u.clearCheckpoint(); err != nil {
 		return nil, err
 	}
 
-	provision, err := u.provisionActionInitializer()
-	if err != nil {
-		return nil, err
+	return result, nil
+}
+
+// stagesToRun determines which stages to execute. When resuming, stages that completed during the previous run are
+// omitted, otherwise any previous checkpoint is discarded and all stages run.
+func (u *upAction) stagesToRun(ctx context.Context) ([]upStage, error) {
+	if !u.flags.resume {
+		if err := u.clearCheckpoint(); err != nil {
+			return nil, err
+		}
+
+		return upStages, nil
 	}
 
-	provision.flags = &u.flags.provisionFlags
-	provisionOptions := &middleware.Options{CommandPath: "provision"}
-	_, err = u.runner.RunChildAction(ctx, provisionOptions, provision)
-	if err != nil {
-		return nil, err
+	lastCompleted, has := u.env.Config.Get(upCheckpointConfigPath)
+	stage, ok := lastCompleted.(string)
+	if !has || !ok || stage == "" {
+		u.console.Message(ctx, locale.Sprintf(locale.UpNoRun))
+		return upStages, nil
 	}
 
-	// Print an additional newline to separate provision from deploy
-	u.console.Message(ctx, "")
+	stages := remainingUpStages(upStage(stage))
+	if len(stages) > 0 {
+		u.console.Message(ctx, locale.Sprintf(locale.UpResuming, stages[0]))
+	}
 
-	deploy, err := u.deployActionInitializer()
-	if err != nil {
-		return nil, err
+	return stages, nil
+}
+
+func (u *upAction) runStage(ctx context.Context, stage upStage) (*actions.ActionResult, error) {
+	switch stage {
+	case upStageInit:
+		err := provisioning.EnsureSubscriptionAndLocation(ctx, u.console, u.env, u.accountManager)
+		if err != nil {
+			return nil, err
+		}
+
+		packageAction, err := u.packageActionInitializer()
+		if err != nil {
+			return nil, err
+		}
+		packageOptions := &middleware.Options{CommandPath: "package"}
+		return u.runner.RunChildAction(ctx, packageOptions, packageAction)
+	case upStageProvision:
+		provision, err := u.provisionActionInitializer()
+		if err != nil {
+			return nil, err
+		}
+
+		provision.flags = &u.flags.provisionFlags
+		provisionOptions := &middleware.Options{CommandPath: "provision"}
+		result, err := u.runner.RunChildAction(ctx, provisionOptions, provision)
+		if err != nil {
+			return nil, err
+		}
+
+		// Print an additional newline to separate provision from deploy
+		u.console.Message(ctx, "")
+		return result, nil
+	case upStageDeploy:
+		deploy, err := u.deployActionInitializer()
+		if err != nil {
+			return nil, err
+		}
+
+		deploy.flags = &u.flags.deployFlags
+		// move flag to args to avoid extra deprecation flag warning
+		if deploy.flags.serviceName != "" {
+			deploy.args = []string{deploy.flags.serviceName}
+			deploy.flags.serviceName = ""
+		}
+		deployOptions := &middleware.Options{CommandPath: "deploy"}
+		return u.runner.RunChildAction(ctx, deployOptions, deploy)
+	default:
+		return nil, fmt.Errorf("unknown stage '%s'", stage)
 	}
+}
 
-	deploy.flags = &u.flags.deployFlags
-	// move flag to args to avoid extra deprecation flag warning
-	if deploy.flags.serviceName != "" {
-		deploy.args = []string{deploy.flags.serviceName}
-		deploy.flags.serviceName = ""
+// saveCheckpoint records the specified stage as the last stage that completed successfully.
+func (u *upAction) saveCheckpoint(stage upStage) error {
+	if err := u.env.Config.Set(upCheckpointConfigPath, string(stage)); err != nil {
+		return fmt.Errorf("setting checkpoint for stage '%s': %w", stage, err)
 	}
-	deployOptions := &middleware.Options{CommandPath: "deploy"}
-	deployResult, err := u.runner.RunChildAction(ctx, deployOptions, deploy)
-	if err != nil {
-		return nil, err
+
+	if err := u.env.Save(); err != nil {
+		return fmt.Errorf("saving checkpoint for stage '%s': %w", stage, err)
+	}
+
+	return nil
+}
+
+func (u *upAction) clearCheckpoint() error {
+	if _, has := u.env.Config.Get(upCheckpointConfigPath); !has {
+		return nil
 	}
-	return deployResult, nil
+
+	if err := u.env.Config.Unset(upCheckpointConfigPath); err != nil {
+		return fmt.Errorf("clearing checkpoint: %w", err)
+	}
+
+	if err := u.env.Save(); err != nil {
+		return fmt.Errorf("saving environment: %w", err)
+	}
+
+	return nil
 }
 
 func getCmdUpHelpDescription(c *cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf("Executes the %s and %s commands in a single step.",
 			output.WithHighLightFormat("azd provision"),
-			output.WithHighLightFormat("azd deploy")), nil)
+			output.WithHighLightFormat("azd deploy")), []string{
+			formatHelpNote("The init, provision and deploy stages run in order. Init selects the subscription and " +
+				"location of the environment and packages the services."),
+			formatHelpNote(fmt.Sprintf("If a stage fails, run %s to continue from the failed stage.",
+				output.WithHighLightFormat("azd up --resume"))),
+		})
 }
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_remainingUpStages(t *testing.T) {
+	tests := []struct {
+		name          string
+		lastCompleted upStage
+		expected      []upStage
+	}{
+		{"NoCheckpoint", "", []upStage{upStageInit, upStageProvision, upStageDeploy}},
+		{"UnknownCheckpoint", "package", []upStage{upStageInit, upStageProvision, upStageDeploy}},
+		{"AfterInit", upStageInit, []upStage{upStageProvision, upStageDeploy}},
+		{"AfterProvision", upStageProvision, []upStage{upStageDeploy}},
+		{"AfterDeploy", upStageDeploy, []upStage{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, remainingUpStages(tt.lastCompleted))
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/update"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type upgradeFlags struct {
+	channel string
+	global  *internal.GlobalCommandOptions
+}
+
+func (f *upgradeFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(
+		&f.channel,
+		"channel",
+		"",
+		fmt.Sprintf(
+			"The release channel to upgrade from: %s, %s or %s. Defaults to the %s setting.",
+			update.ChannelStable,
+			update.ChannelBeta,
+			update.ChannelDaily,
+			update.ChannelConfigPath,
+		),
+	)
+	f.global = global
+}
+
+func newUpgradeFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *upgradeFlags {
+	flags := &upgradeFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newUpgradeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade azd to the latest version.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type upgradeAction struct {
+	flags             *upgradeFlags
+	console           input.Console
+	userConfigManager config.UserConfigManager
+	updateManager     *update.Manager
+}
+
+func newUpgradeAction(
+	flags *upgradeFlags,
+	console input.Console,
+	userConfigManager config.UserConfigManager,
+	updateManager *update.Manager,
+) actions.Action {
+	return &upgradeAction{
+		flags:             flags,
+		console:           console,
+		userConfigManager: userConfigManager,
+		updateManager:     updateManager,
+	}
+}
+
+func (a *upgradeAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Upgrading azd (azd upgrade)",
+	})
+
+	// Check before looking for a release, a build without a signing key or a package manager install can't be upgraded
+	if err := update.CanUpgrade(); err != nil {
+		return nil, err
+	}
+
+	userConfig, err := a.userConfigManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading user configuration: %w", err)
+	}
+
+	updateConfig, err := update.LoadConfig(userConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.flags.channel != "" {
+		channel, err := update.ParseChannel(a.flags.channel)
+		if err != nil {
+			return nil, err
+		}
+
+		updateConfig.Channel = channel
+	}
+
+	latest, err := a.updateManager.LatestVersion(ctx, updateConfig, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Switching channels may install an older version, for example when moving back from daily to stable
+	if !update.IsNewer(latest) && a.flags.channel == "" {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf(
+					"azd %s is the latest version on the %s channel.",
+					internal.VersionInfo().Version.String(),
+					updateConfig.Channel,
+				),
+			},
+		}, nil
+	}
+
+	confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+		Message: fmt.Sprintf(
+			"Upgrade azd from %s to %s (%s channel)?",
+			internal.VersionInfo().Version.String(),
+			latest.String(),
+			updateConfig.Channel,
+		),
+		DefaultValue: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !confirm {
+		return nil, nil
+	}
+
+	var step string
+	err = a.updateManager.Upgrade(ctx, updateConfig, func(message string) {
+		if step != "" {
+			a.console.StopSpinner(ctx, step, input.StepDone)
+		}
+
+		step = message
+		a.console.ShowSpinner(ctx, step, input.Step)
+	})
+	if err != nil {
+		if step != "" {
+			a.console.StopSpinner(ctx, step, input.StepFailed)
+		}
+		return nil, fmt.Errorf("upgrading azd: %w", err)
+	}
+
+	a.console.StopSpinner(ctx, step, input.StepDone)
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("azd was upgraded to %s.", latest.String()),
+			FollowUp: fmt.Sprintf(
+				"Run %s to confirm the installed version.",
+				output.WithHighLightFormat("azd version"),
+			),
+		},
+	}, nil
+}
+
+func getCmdUpgradeHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Upgrade azd to the latest version of the configured release channel.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"Select the release channel with %s. Supported channels are %s, %s and %s.",
+				output.WithHighLightFormat("azd config set %s <channel>", update.ChannelConfigPath),
+				update.ChannelStable,
+				update.ChannelBeta,
+				update.ChannelDaily,
+			)),
+			formatHelpNote("The release is verified against its signature before replacing the azd binary."),
+			formatHelpNote(
+				"When azd was installed by a package manager, like Homebrew or winget, upgrade it with the package manager."),
+			formatHelpNote(fmt.Sprintf(
+				"Set how often azd checks for a new version, in hours, with %s. Set it to 0 to disable the check.",
+				output.WithHighLightFormat("azd config set %s <hours>", update.CheckIntervalConfigPath),
+			)),
+		})
+}
+
+func getCmdUpgradeHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Upgrade azd to the latest version of the configured channel.": output.WithHighLightFormat("azd upgrade"),
+		"Upgrade azd to the latest daily build.": output.WithHighLightFormat(
+			"azd upgrade --channel daily",
+		),
+		"Check for a new version without upgrading.": output.WithHighLightFormat("azd version --check"),
+	})
+}
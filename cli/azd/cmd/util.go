@@ -8,12 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/spf13/pflag"
@@ -25,31 +27,31 @@ type CmdAnnotations map[string]string
 type Asker func(p survey.Prompt, response interface{}) error
 
 func invalidEnvironmentNameMsg(environmentName string) string {
-	return fmt.Sprintf(
-		"environment name '%s' is invalid (it should contain only alphanumeric characters and hyphens)\n",
-		environmentName,
-	)
+	return locale.Sprintf(locale.EnvironmentNameInvalid, environmentName) + "\n"
 }
 
-// ensureValidEnvironmentName ensures the environment name is valid, if it is not, an error is printed
-// and the user is prompted for a new name.
+// ensureValidEnvironmentName ensures the environment name is valid, if it is not, the user is prompted for a new name
+// until the name is valid.
 func ensureValidEnvironmentName(ctx context.Context, environmentName *string, console input.Console) error {
-	for !environment.IsValidEnvironmentName(*environmentName) {
-		userInput, err := console.Prompt(ctx, input.ConsoleOptions{
-			Message: "Please enter a new environment name:",
-		})
-
-		if err != nil {
-			return fmt.Errorf("reading environment name: %w", err)
-		}
+	if environment.IsValidEnvironmentName(*environmentName) {
+		return nil
+	}
 
-		*environmentName = userInput
+	userInput, err := console.Prompt(ctx, input.ConsoleOptions{
+		Message: locale.Sprintf(locale.EnvironmentNamePrompt),
+		Validate: func(value string) error {
+			if !environment.IsValidEnvironmentName(value) {
+				return errors.New(strings.TrimSuffix(invalidEnvironmentNameMsg(value), "\n"))
+			}
 
-		if !environment.IsValidEnvironmentName(*environmentName) {
-			fmt.Fprint(console.Handles().Stdout, invalidEnvironmentNameMsg(*environmentName))
-		}
+			return nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("reading environment name: %w", err)
 	}
 
+	*environmentName = userInput
 	return nil
 }
 
@@ -109,6 +111,11 @@ func loadEnvironmentIfAvailable() (*environment.Environment, error) {
 	if err != nil {
 		return nil, err
 	}
+	// AZURE_ENV_NAME overrides the default environment, the same way it sets the default of the --environment flag
+	if envName := os.Getenv(environment.EnvNameEnvVarName); envName != "" {
+		return environment.GetEnvironment(azdCtx, envName)
+	}
+
 	defaultEnv, err := azdCtx.GetDefaultEnvironmentName()
 	if err != nil {
 		return nil, err
@@ -0,0 +1,226 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type validateFlags struct {
+	checkTools bool
+	global     *internal.GlobalCommandOptions
+}
+
+func (f *validateFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&f.checkTools,
+		"check-tools",
+		false,
+		"Checks the tools required by the languages and the hosts of the services are installed.",
+	)
+
+	f.global = global
+}
+
+func newValidateFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *validateFlags {
+	flags := &validateFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newValidateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Check the project is valid, without connecting to Azure.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type validateAction struct {
+	flags        *validateFlags
+	azdCtx       *azdcontext.AzdContext
+	formatter    output.Formatter
+	writer       io.Writer
+	console      input.Console
+	dockerCli    docker.Docker
+	dotnetCli    dotnet.DotNetCli
+	npmCli       npm.NpmCli
+	pythonCli    *python.PythonCli
+	javacCli     javac.JavacCli
+	mavenCli     maven.MavenCli
+	gradleCli    gradle.GradleCli
+	goCli        golang.GoCli
+	kubectlCli   kubectl.KubectlCli
+	terraformCli terraform.TerraformCli
+}
+
+func newValidateAction(
+	flags *validateFlags,
+	azdCtx *azdcontext.AzdContext,
+	formatter output.Formatter,
+	writer io.Writer,
+	console input.Console,
+	dockerCli docker.Docker,
+	dotnetCli dotnet.DotNetCli,
+	npmCli npm.NpmCli,
+	pythonCli *python.PythonCli,
+	javacCli javac.JavacCli,
+	mavenCli maven.MavenCli,
+	gradleCli gradle.GradleCli,
+	goCli golang.GoCli,
+	kubectlCli kubectl.KubectlCli,
+	terraformCli terraform.TerraformCli,
+) actions.Action {
+	return &validateAction{
+		flags:        flags,
+		azdCtx:       azdCtx,
+		formatter:    formatter,
+		writer:       writer,
+		console:      console,
+		dockerCli:    dockerCli,
+		dotnetCli:    dotnetCli,
+		npmCli:       npmCli,
+		pythonCli:    pythonCli,
+		javacCli:     javacCli,
+		mavenCli:     mavenCli,
+		gradleCli:    gradleCli,
+		goCli:        goCli,
+		kubectlCli:   kubectlCli,
+		terraformCli: terraformCli,
+	}
+}
+
+func (a *validateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	result, projectConfig, err := templates.ValidateProject(ctx, a.azdCtx.ProjectDirectory())
+	if err != nil {
+		return nil, fmt.Errorf("validating project: %w", err)
+	}
+
+	if a.flags.checkTools && projectConfig != nil {
+		for _, tool := range tools.Unique(a.projectTools(projectConfig)) {
+			if err := tool.CheckInstalled(ctx); err != nil {
+				result.Issues = append(result.Issues, templates.ValidationIssue{
+					Severity: templates.ValidationError,
+					Check:    "tools",
+					Message:  fmt.Sprintf("%s: %s, see %s", tool.Name(), err.Error(), tool.InstallUrl()),
+				})
+			}
+		}
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(result, a.writer, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		displayValidationIssues(ctx, a.console, result.Issues)
+	}
+
+	if result.HasErrors() {
+		return nil, errors.New("the project isn't valid")
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "The project is valid.",
+		},
+	}, nil
+}
+
+// projectTools returns the tools required by the languages and the hosts of the services, and by the infrastructure
+// provider of the project.
+func (a *validateAction) projectTools(projectConfig *project.ProjectConfig) []tools.ExternalTool {
+	var projectTools []tools.ExternalTool
+
+	if projectConfig.Infra.Provider == provisioning.Terraform {
+		projectTools = append(projectTools, a.terraformCli)
+	}
+
+	for _, service := range projectConfig.Services {
+		switch service.Language {
+		case project.ServiceLanguageDotNet, project.ServiceLanguageCsharp, project.ServiceLanguageFsharp:
+			projectTools = append(projectTools, a.dotnetCli)
+		case project.ServiceLanguageJavaScript, project.ServiceLanguageTypeScript:
+			projectTools = append(projectTools, a.npmCli)
+		case project.ServiceLanguagePython:
+			projectTools = append(projectTools, a.pythonCli)
+		case project.ServiceLanguageGo:
+			projectTools = append(projectTools, a.goCli)
+		case project.ServiceLanguageJava:
+			projectTools = append(projectTools, a.javacCli)
+			if serviceHasFile(projectConfig, service, "build.gradle", "build.gradle.kts") {
+				projectTools = append(projectTools, a.gradleCli)
+			} else {
+				projectTools = append(projectTools, a.mavenCli)
+			}
+		}
+
+		switch service.Host {
+		case project.ContainerAppTarget:
+			projectTools = append(projectTools, a.dockerCli)
+		case project.AksTarget:
+			projectTools = append(projectTools, a.dockerCli, a.kubectlCli)
+		}
+	}
+
+	return projectTools
+}
+
+// serviceHasFile returns true when the project of the service has one of the files.
+func serviceHasFile(projectConfig *project.ProjectConfig, service *project.ServiceConfig, names ...string) bool {
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(projectConfig.Path, service.RelativePath, name)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getCmdValidateHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Check the project is valid, without connecting to Azure.",
+		[]string{
+			formatHelpNote(fmt.Sprintf("Checks %s matches its schema, and the project, the language and the host "+
+				"of each service work together.", azdcontext.ProjectFileName)),
+			formatHelpNote("Checks the hooks run existing scripts."),
+			formatHelpNote("Checks the infrastructure has the parameters and the outputs required by the services."),
+		})
+}
+
+func getCmdValidateHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Check the current project is valid.": output.WithHighLightFormat("azd validate"),
+		"Check the current project is valid, and the tools it requires are installed.": output.WithHighLightFormat(
+			"azd validate --check-tools"),
+	})
+}
@@ -10,18 +10,27 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/update"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 type versionFlags struct {
+	check  bool
 	global *internal.GlobalCommandOptions
 }
 
 func (v *versionFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.BoolVar(
+		&v.check,
+		"check",
+		false,
+		"Checks whether a newer version of azd is available on the configured release channel.",
+	)
 	v.global = global
 }
 
@@ -33,10 +42,12 @@ func newVersionFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions)
 }
 
 type versionAction struct {
-	flags     *versionFlags
-	formatter output.Formatter
-	writer    io.Writer
-	console   input.Console
+	flags             *versionFlags
+	formatter         output.Formatter
+	writer            io.Writer
+	console           input.Console
+	userConfigManager config.UserConfigManager
+	updateManager     *update.Manager
 }
 
 func newVersionAction(
@@ -44,25 +55,60 @@ func newVersionAction(
 	formatter output.Formatter,
 	writer io.Writer,
 	console input.Console,
+	userConfigManager config.UserConfigManager,
+	updateManager *update.Manager,
 ) actions.Action {
 	return &versionAction{
-		flags:     flags,
-		formatter: formatter,
-		writer:    writer,
-		console:   console,
+		flags:             flags,
+		formatter:         formatter,
+		writer:            writer,
+		console:           console,
+		userConfigManager: userConfigManager,
+		updateManager:     updateManager,
 	}
 }
 
 func (v *versionAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	var updateResult *contracts.VersionUpdateResult
+	if v.flags.check {
+		result, err := v.checkForUpdate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		updateResult = result
+	}
+
 	switch v.formatter.Kind() {
 	case output.NoneFormat:
-		fmt.Fprintf(v.console.Handles().Stdout, "azd version %s\n", internal.Version)
+		stdout := v.console.Handles().Stdout
+		fmt.Fprintf(stdout, "azd version %s\n", internal.Version)
+
+		if updateResult != nil {
+			if updateResult.UpdateAvailable {
+				fmt.Fprintf(
+					stdout,
+					"A new version of azd is available on the %s channel: %s. Run %s to upgrade.\n",
+					updateResult.Channel,
+					updateResult.LatestVersion,
+					output.WithHighLightFormat("azd upgrade"),
+				)
+			} else {
+				fmt.Fprintf(
+					stdout,
+					"azd is up to date, the latest version on the %s channel is %s.\n",
+					updateResult.Channel,
+					updateResult.LatestVersion,
+				)
+			}
+		}
 	case output.JsonFormat:
 		var result contracts.VersionResult
 		versionSpec := internal.VersionInfo()
 
 		result.Azd.Commit = versionSpec.Commit
 		result.Azd.Version = versionSpec.Version.String()
+		result.Update = updateResult
 
 		err := v.formatter.Format(result, v.writer, nil)
 		if err != nil {
@@ -72,3 +118,27 @@ func (v *versionAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	return nil, nil
 }
+
+// checkForUpdate always queries the release channel, bypassing the cache used by the new version notice.
+func (v *versionAction) checkForUpdate(ctx context.Context) (*contracts.VersionUpdateResult, error) {
+	userConfig, err := v.userConfigManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading user configuration: %w", err)
+	}
+
+	updateConfig, err := update.LoadConfig(userConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := v.updateManager.LatestVersion(ctx, updateConfig, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &contracts.VersionUpdateResult{
+		Channel:         string(updateConfig.Channel),
+		LatestVersion:   latest.String(),
+		UpdateAvailable: update.IsNewer(latest),
+	}, nil
+}
@@ -15,9 +15,17 @@ type GlobalCommandOptions struct {
 	// if there is no default value the prompt returns an error.
 	NoPrompt bool
 
+	// Plain disables spinners, colors and other ANSI control sequences, writing progress as timestamped log lines
+	// instead. It's enabled with `--plain`, or when NO_COLOR is set or TERM is 'dumb'.
+	Plain bool
+
+	// Machine replaces the human formatted output with the JSON-RPC protocol of input.MachineConsole, so IDEs can embed
+	// azd commands in their UI. It's enabled with `--machine`.
+	Machine bool
+
 	// EnableTelemetry indicates if telemetry should be sent.
 	// The rootCmd will disable this based if the environment variable
-	// AZURE_DEV_COLLECT_TELEMETRY is set to 'no'.
+	// AZURE_DEV_COLLECT_TELEMETRY is set to 'no', or telemetry.enabled is set to false in the user configuration.
 	// Defaults to true.
 	EnableTelemetry bool
 
@@ -11,40 +11,58 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/adopt"
+	"github.com/azure/azure-dev/cli/azd/pkg/compose"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/otiai10/copy"
 )
 
 // Initializer handles the initialization of a local repository.
 type Initializer struct {
-	console input.Console
-	gitCli  git.GitCli
+	console    input.Console
+	gitCli     git.GitCli
+	httpClient httputil.HttpClient
 }
 
 func NewInitializer(
 	console input.Console,
-	gitCli git.GitCli) *Initializer {
+	gitCli git.GitCli,
+	httpClient httputil.HttpClient) *Initializer {
 	return &Initializer{
-		console: console,
-		gitCli:  gitCli,
+		console:    console,
+		gitCli:     gitCli,
+		httpClient: httpClient,
 	}
 }
 
 // Initializes a local repository in the project directory from a remote repository.
 //
+// templateBranch is the branch or tag of the repository, the default branch when empty. templatePath is the directory
+// of the repository containing the template, the root of the repository when empty. Only the files of the directory are
+// checked out, which avoids downloading the whole repository of a collection of templates.
+//
+// The tokens declared by the manifest of the template are replaced in its files. templateValues are the values of the
+// inputs of the template known by the caller, keyed by token name, the other inputs are prompted for.
+//
 // A confirmation prompt is displayed for any existing files to be overwritten.
 func (i *Initializer) Initialize(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
 	templateUrl string,
-	templateBranch string) error {
+	templateBranch string,
+	templatePath string,
+	templateValues map[string]string) error {
 	var err error
 	stepMessage := fmt.Sprintf("Downloading template code to: %s", output.WithLinkFormat("%s", azdCtx.ProjectDirectory()))
 	i.console.ShowSpinner(ctx, stepMessage, input.Step)
@@ -64,7 +82,12 @@ func (i *Initializer) Initialize(
 
 	target := azdCtx.ProjectDirectory()
 
-	filesWithExecPerms, err := i.fetchCode(ctx, templateUrl, templateBranch, staging)
+	filesWithExecPerms, err := i.fetchCode(ctx, templateUrl, templateBranch, templatePath, staging)
+	if err != nil {
+		return err
+	}
+
+	err = i.applyTemplateManifest(ctx, azdCtx, staging, templateValues)
 	if err != nil {
 		return err
 	}
@@ -109,11 +132,75 @@ func (i *Initializer) Initialize(
 	return nil
 }
 
+// fetchCode fetches the files of the template to destination, returning the paths of its executable files.
+//
+// The template is cloned with git. When the clone fails, like on networks where git can't reach the repository, the
+// tarball of GitHub repositories is downloaded instead. When both fail, like on a machine which is offline, the copy of
+// the template cached by its last successful fetch is used.
 func (i *Initializer) fetchCode(
 	ctx context.Context,
 	templateUrl string,
 	templateBranch string,
+	templatePath string,
+	destination string) ([]string, error) {
+	executableFilePaths, err := i.cloneCode(ctx, templateUrl, templateBranch, templatePath, destination)
+	fetchErr := err
+
+	if archiveUrl, ok := codeloadArchiveUrl(templateUrl, templateBranch); ok && err != nil {
+		log.Printf("cloning template failed, downloading %s instead: %v", archiveUrl, err)
+		if err = emptyDir(destination); err != nil {
+			return nil, err
+		}
+
+		executableFilePaths, err = downloadTemplateArchive(ctx, i.httpClient, archiveUrl, templatePath, destination)
+	}
+
+	if err == nil {
+		metadata := templateCacheMetadata{
+			TemplateUrl:         templateUrl,
+			Branch:              templateBranch,
+			Path:                templatePath,
+			FetchedAt:           time.Now(),
+			ExecutableFilePaths: executableFilePaths,
+		}
+		if err := cacheTemplate(metadata, destination); err != nil {
+			log.Printf("failed caching template: %v", err)
+		}
+
+		return executableFilePaths, nil
+	}
+
+	log.Printf("fetching template failed, restoring the cached template: %v", err)
+	if err := emptyDir(destination); err != nil {
+		return nil, err
+	}
+
+	cached, err := restoreCachedTemplate(templateUrl, templateBranch, templatePath, destination)
+	if err != nil {
+		log.Printf("restoring cached template failed: %v", err)
+		return nil, fetchErr
+	}
+
+	i.console.StopSpinner(ctx, "", input.StepDone)
+	i.console.MessageUxItem(ctx, &ux.WarningMessage{
+		Description: fmt.Sprintf(
+			"The template couldn't be downloaded, using the copy cached on %s", cached.FetchedAt.Format(time.DateTime)),
+	})
+
+	return cached.ExecutableFilePaths, nil
+}
+
+// cloneCode clones the template to destination with git.
+func (i *Initializer) cloneCode(
+	ctx context.Context,
+	templateUrl string,
+	templateBranch string,
+	templatePath string,
 	destination string) (executableFilePaths []string, err error) {
+	if templatePath != "" {
+		return i.fetchCodeDirectory(ctx, templateUrl, templateBranch, templatePath, destination)
+	}
+
 	err = i.gitCli.ShallowClone(ctx, templateUrl, templateBranch, destination)
 	if err != nil {
 		return nil, fmt.Errorf("fetching template: %w", err)
@@ -136,6 +223,127 @@ func (i *Initializer) fetchCode(
 	return executableFilePaths, nil
 }
 
+// fetchCodeDirectory fetches the files of the directory templatePath of the repository to destination, with a sparse
+// checkout of the directory. The paths of the executable files returned are relative to the directory.
+func (i *Initializer) fetchCodeDirectory(
+	ctx context.Context,
+	templateUrl string,
+	templateBranch string,
+	templatePath string,
+	destination string) ([]string, error) {
+	directory := filepath.ToSlash(filepath.Clean(templatePath))
+	if filepath.IsAbs(templatePath) || directory == "." || directory == ".." || strings.HasPrefix(directory, "../") {
+		return nil, fmt.Errorf("template path '%s' must be a directory relative to the root of the repository", templatePath)
+	}
+
+	clone, err := os.MkdirTemp("", "az-dev-template-repo")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp folder: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(clone)
+	}()
+
+	err = i.gitCli.SparseClone(ctx, templateUrl, templateBranch, directory, clone)
+	if err != nil {
+		return nil, fmt.Errorf("fetching template: %w", err)
+	}
+
+	source := filepath.Join(clone, filepath.FromSlash(directory))
+	if info, err := os.Stat(source); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("fetching template: directory '%s' not found in %s", directory, templateUrl)
+	}
+
+	stagedFilesOutput, err := i.gitCli.ListStagedFiles(ctx, clone)
+	if err != nil {
+		return nil, fmt.Errorf("listing files with permissions: %w", err)
+	}
+
+	repositoryFilePaths, err := parseExecutableFiles(stagedFilesOutput)
+	if err != nil {
+		return nil, fmt.Errorf("parsing file permissions output: %w", err)
+	}
+
+	executableFilePaths := []string{}
+	for _, file := range repositoryFilePaths {
+		if rel, found := strings.CutPrefix(file, directory+"/"); found {
+			executableFilePaths = append(executableFilePaths, rel)
+		}
+	}
+
+	if err := copy.Copy(source, destination); err != nil {
+		return nil, fmt.Errorf("copying template directory '%s': %w", directory, err)
+	}
+
+	return executableFilePaths, nil
+}
+
+// applyTemplateManifest replaces the tokens declared by the manifest of the template in staging, prompting for the values
+// of the inputs of the template missing from values, and removes the manifest.
+func (i *Initializer) applyTemplateManifest(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	staging string,
+	values map[string]string) error {
+	manifest, err := templates.LoadManifest(staging)
+	if err != nil || manifest == nil {
+		return err
+	}
+
+	tokens := map[string]string{
+		templates.ProjectNameToken: azdCtx.GetDefaultProjectName(),
+	}
+
+	for _, templateInput := range manifest.Inputs {
+		if value := values[templateInput.Name]; value != "" {
+			tokens[templateInput.Name] = value
+			continue
+		}
+
+		message := templateInput.Prompt
+		if message == "" {
+			message = fmt.Sprintf("Enter a value for the template input '%s':", templateInput.Name)
+		}
+
+		i.console.StopSpinner(ctx, "", input.StepDone)
+		value, err := i.console.Prompt(ctx, input.ConsoleOptions{
+			Message:      message,
+			DefaultValue: templateInput.Default,
+		})
+		if err != nil {
+			return fmt.Errorf("prompting for template input '%s': %w", templateInput.Name, err)
+		}
+
+		tokens[templateInput.Name] = value
+	}
+
+	if err := manifest.ReplaceTokens(staging, tokens); err != nil {
+		return fmt.Errorf("applying template manifest: %w", err)
+	}
+
+	if err := os.Remove(filepath.Join(staging, templates.ManifestFileName)); err != nil {
+		return fmt.Errorf("removing template manifest: %w", err)
+	}
+
+	return nil
+}
+
+// emptyDir removes the files left in dir by a failed fetch.
+func emptyDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("removing files of failed fetch: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // promptForDuplicates prompts the user for any duplicate files detected.
 // The list of absolute source file paths to skip are returned.
 func (i *Initializer) promptForDuplicates(
@@ -293,6 +501,87 @@ func (i *Initializer) InitializeEmpty(ctx context.Context, azdCtx *azdcontext.Az
 	return nil
 }
 
+// Initializes a repository from the docker compose file or the Aspire manifest of the project, with an azd service
+// for each service built from a Dockerfile, and the infrastructure hosting the services in Azure Container Apps.
+func (i *Initializer) InitializeFromApplication(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	app *compose.Application,
+) error {
+	projectDir := azdCtx.ProjectDirectory()
+	sourceFormatted := output.WithLinkFormat("%s", app.Source)
+	var err error
+	i.console.ShowSpinner(ctx,
+		fmt.Sprintf("Creating project files from: %s", sourceFormatted),
+		input.Step)
+	defer i.console.StopSpinner(ctx,
+		fmt.Sprintf("Created project files from: %s", sourceFormatted)+"\n",
+		input.GetStepResultFormat(err))
+
+	isEmpty, err := isEmptyDir(projectDir)
+	if err != nil {
+		return err
+	}
+
+	err = compose.Generate(projectDir, azdCtx.GetDefaultProjectName(), app)
+	if err != nil {
+		return err
+	}
+
+	err = i.writeAzdAssets(ctx, azdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = i.gitInitialize(ctx, projectDir, []string{}, isEmpty)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Initializes a repository from the resources of an existing resource group, with an azd service for each resource
+// running application code, and the infrastructure referencing the existing resources.
+func (i *Initializer) InitializeFromResources(
+	ctx context.Context,
+	azdCtx *azdcontext.AzdContext,
+	resourceGroup string,
+	resources []azcli.AzCliResourceExtended,
+	services []*adopt.Service,
+) error {
+	projectDir := azdCtx.ProjectDirectory()
+	var err error
+	i.console.ShowSpinner(ctx,
+		fmt.Sprintf("Creating project files from resource group: %s", resourceGroup),
+		input.Step)
+	defer i.console.StopSpinner(ctx,
+		fmt.Sprintf("Created project files from resource group: %s", resourceGroup)+"\n",
+		input.GetStepResultFormat(err))
+
+	isEmpty, err := isEmptyDir(projectDir)
+	if err != nil {
+		return err
+	}
+
+	err = adopt.Generate(projectDir, azdCtx.GetDefaultProjectName(), resourceGroup, resources, services)
+	if err != nil {
+		return err
+	}
+
+	err = i.writeAzdAssets(ctx, azdCtx)
+	if err != nil {
+		return err
+	}
+
+	err = i.gitInitialize(ctx, projectDir, []string{}, isEmpty)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (i *Initializer) writeAzdAssets(ctx context.Context, azdCtx *azdcontext.AzdContext) error {
 	// Check to see if `azure.yaml` exists, and if it doesn't, create it.
 	if _, err := os.Stat(azdCtx.ProjectPath()); errors.Is(err, os.ErrNotExist) {
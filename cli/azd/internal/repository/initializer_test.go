@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -31,12 +33,16 @@ func Test_Initializer_Initialize(t *testing.T) {
 		// Files that will be mocked to be executable when fetched remotely.
 		// Equally, these files are asserted to be executable after init.
 		executableFiles []string
+		// The directory of the remote repository containing the template.
+		templatePath string
 	}{
-		{"RegularTemplate", "template", []string{"script/test.sh"}},
-		{"MinimalTemplate", "template-minimal", []string{}},
+		{"RegularTemplate", "template", []string{"script/test.sh"}, ""},
+		{"MinimalTemplate", "template-minimal", []string{}, ""},
+		{"TemplateDirectory", "template", []string{"script/test.sh"}, "templates/webapp"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AZD_CONFIG_DIR", t.TempDir())
 			projectDir := t.TempDir()
 			ctx := context.Background()
 			azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
@@ -45,10 +51,15 @@ func Test_Initializer_Initialize(t *testing.T) {
 			mockRunner := mockexec.NewMockCommandRunner()
 			mockRunner.When(func(args exec.RunArgs, command string) bool { return true }).
 				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
-					// Stub out git clone, otherwise run actual command
+					// Stub out git clone and sparse-checkout, otherwise run actual command
+					if slices.Contains(args.Args, "sparse-checkout") {
+						return exec.NewRunResult(0, "", ""), nil
+					}
+
 					if slices.Contains(args.Args, "clone") && slices.Contains(args.Args, "local") {
 						stagingDir := args.Args[len(args.Args)-1]
-						copyTemplate(t, testDataPath(tt.templateDir), stagingDir)
+						templateDir := filepath.Join(stagingDir, filepath.FromSlash(tt.templatePath))
+						copyTemplate(t, testDataPath(tt.templateDir), templateDir)
 
 						gitArgs := exec.NewRunArgs("git", "-C", stagingDir).WithEnrichError(true)
 
@@ -62,7 +73,7 @@ func Test_Initializer_Initialize(t *testing.T) {
 						for _, file := range tt.executableFiles {
 							_, err = realRunner.Run(
 								ctx,
-								gitArgs.AppendParams("update-index", "--chmod=+x", file))
+								gitArgs.AppendParams("update-index", "--chmod=+x", path.Join(tt.templatePath, file)))
 							require.NoError(t, err)
 
 							// Mocks the correct behavior in *nix when the file lands on the filesystem.
@@ -71,7 +82,7 @@ func Test_Initializer_Initialize(t *testing.T) {
 							// Note that `git update-index --chmod=+x` simply updates the tracked permissions in git,
 							// but does not update the files directly, hence this is needed.
 							if runtime.GOOS != "windows" {
-								err = os.Chmod(filepath.Join(stagingDir, file), 0755)
+								err = os.Chmod(filepath.Join(templateDir, file), 0755)
 								require.NoError(t, err)
 							}
 						}
@@ -82,8 +93,8 @@ func Test_Initializer_Initialize(t *testing.T) {
 					return realRunner.Run(ctx, args)
 				})
 
-			i := NewInitializer(console, git.NewGitCli(mockRunner))
-			err := i.Initialize(ctx, azdCtx, "local", "")
+			i := NewInitializer(console, git.NewGitCli(mockRunner), http.DefaultClient)
+			err := i.Initialize(ctx, azdCtx, "local", "", tt.templatePath, nil)
 			require.NoError(t, err)
 
 			verifyTemplateCopied(t, testDataPath(tt.templateDir), projectDir, verifyOptions{})
@@ -107,6 +118,7 @@ func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AZD_CONFIG_DIR", t.TempDir())
 			originalReadme := "ORIGINAL"
 			originalProgram := "Console.WriteLine(\"Hello, Original World!\");"
 			projectDir := t.TempDir()
@@ -145,8 +157,8 @@ func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 					return realRunner.Run(context.Background(), args)
 				})
 
-			i := NewInitializer(console, git.NewGitCli(mockRunner))
-			err = i.Initialize(context.Background(), azdCtx, "local", "")
+			i := NewInitializer(console, git.NewGitCli(mockRunner), http.DefaultClient)
+			err = i.Initialize(context.Background(), azdCtx, "local", "", "", nil)
 			require.NoError(t, err)
 
 			switch tt.selection {
@@ -342,7 +354,7 @@ func Test_Initializer_InitializeEmpty(t *testing.T) {
 
 			console := mockinput.NewMockConsole()
 			realRunner := exec.NewCommandRunner(os.Stdin, os.Stdout, os.Stderr)
-			i := NewInitializer(console, git.NewGitCli(realRunner))
+			i := NewInitializer(console, git.NewGitCli(realRunner), http.DefaultClient)
 			err := i.InitializeEmpty(context.Background(), azdCtx)
 			require.NoError(t, err)
 
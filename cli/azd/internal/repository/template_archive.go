@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// Matches the https and ssh URLs of GitHub repositories, capturing the owner and the name of the repository
+var gitHubRepositoryRegex = regexp.MustCompile(
+	`^(?:https://github\.com/|git@github\.com:|ssh://git@github\.com/)([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// codeloadArchiveUrl returns the URL of the tarball of the branch or tag of a GitHub repository, of its default branch
+// when branch is empty. false is returned when the repository isn't hosted on GitHub.
+func codeloadArchiveUrl(templateUrl string, branch string) (string, bool) {
+	matches := gitHubRepositoryRegex.FindStringSubmatch(templateUrl)
+	if matches == nil {
+		return "", false
+	}
+
+	ref := branch
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	return fmt.Sprintf("https://codeload.github.com/%s/%s/tar.gz/%s", matches[1], matches[2], ref), true
+}
+
+// downloadTemplateArchive downloads the tarball of a repository and extracts the files of the directory templatePath,
+// or of the whole repository when it's empty, to destination. The tarball is downloaded with the HTTP client of azd,
+// which goes through the proxy of the HTTPS_PROXY environment variable, for networks where git can't clone.
+//
+// The paths of the executable files returned are relative to destination, like the paths returned by git.
+func downloadTemplateArchive(
+	ctx context.Context,
+	httpClient httputil.HttpClient,
+	archiveUrl string,
+	templatePath string,
+	destination string,
+) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, archiveUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", archiveUrl, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: unexpected status code %d", archiveUrl, response.StatusCode)
+	}
+
+	gzipReader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", archiveUrl, err)
+	}
+	defer gzipReader.Close()
+
+	prefix := ""
+	if templatePath != "" {
+		prefix = path.Clean(filepath.ToSlash(templatePath)) + "/"
+	}
+
+	executableFilePaths := []string{}
+	found := false
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", archiveUrl, err)
+		}
+
+		// The entries are in a top level directory named after the repository and the commit, like 'repo-<sha>/'
+		_, name, hasParent := strings.Cut(header.Name, "/")
+		if !hasParent {
+			continue
+		}
+
+		name, inTemplate := strings.CutPrefix(path.Clean("/" + name)[1:], prefix)
+		if !inTemplate || name == "" {
+			continue
+		}
+		found = true
+
+		target := filepath.Join(destination, filepath.FromSlash(name))
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, osutil.PermissionDirectory); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			perm := osutil.PermissionFile
+			if header.FileInfo().Mode()&0111 != 0 {
+				perm = osutil.PermissionExecutableFile
+				executableFilePaths = append(executableFilePaths, name)
+			}
+
+			if err := extractArchiveFile(tarReader, target, perm); err != nil {
+				return nil, err
+			}
+		default:
+			// Links aren't extracted, templates don't rely on them and they could point outside of destination
+			continue
+		}
+	}
+
+	if !found && templatePath != "" {
+		return nil, fmt.Errorf("directory '%s' not found in %s", templatePath, archiveUrl)
+	} else if !found {
+		return nil, fmt.Errorf("no files found in %s", archiveUrl)
+	}
+
+	return executableFilePaths, nil
+}
+
+func extractArchiveFile(reader io.Reader, target string, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return fmt.Errorf("extracting %s: %w", target, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockhttp"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_codeloadArchiveUrl(t *testing.T) {
+	tests := []struct {
+		templateUrl string
+		branch      string
+		expected    string
+	}{
+		{"https://github.com/Azure-Samples/todo-python-mongo", "", "todo-python-mongo/tar.gz/HEAD"},
+		{"https://github.com/Azure-Samples/todo-python-mongo.git", "v1.0", "todo-python-mongo/tar.gz/v1.0"},
+		{"git@github.com:Azure-Samples/todo-python-mongo.git", "main", "todo-python-mongo/tar.gz/main"},
+		{"https://dev.azure.com/org/project/_git/repo", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.templateUrl, func(t *testing.T) {
+			archiveUrl, ok := codeloadArchiveUrl(tt.templateUrl, tt.branch)
+			if tt.expected == "" {
+				require.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			require.Equal(t, "https://codeload.github.com/Azure-Samples/"+tt.expected, archiveUrl)
+		})
+	}
+}
+
+func Test_downloadTemplateArchive(t *testing.T) {
+	httpClient := mockhttp.NewMockHttpUtil()
+	httpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "codeload.github.com"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return archiveResponse(t, request), nil
+	})
+
+	t.Run("Repository", func(t *testing.T) {
+		destination := t.TempDir()
+		executables, err := downloadTemplateArchive(
+			context.Background(), httpClient, "https://codeload.github.com/owner/repo/tar.gz/HEAD", "", destination)
+		require.NoError(t, err)
+		require.Equal(t, []string{"templates/web/run.sh"}, executables)
+
+		require.FileExists(t, filepath.Join(destination, "README.md"))
+		require.FileExists(t, filepath.Join(destination, "templates", "web", "app.py"))
+		require.NoFileExists(t, filepath.Join(filepath.Dir(destination), "escaped.txt"))
+		require.FileExists(t, filepath.Join(destination, "escaped.txt"))
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		destination := t.TempDir()
+		executables, err := downloadTemplateArchive(
+			context.Background(), httpClient, "https://codeload.github.com/owner/repo/tar.gz/HEAD", "templates/web",
+			destination)
+		require.NoError(t, err)
+		require.Equal(t, []string{"run.sh"}, executables)
+
+		require.FileExists(t, filepath.Join(destination, "app.py"))
+		require.NoFileExists(t, filepath.Join(destination, "README.md"))
+	})
+
+	t.Run("DirectoryNotFound", func(t *testing.T) {
+		_, err := downloadTemplateArchive(
+			context.Background(), httpClient, "https://codeload.github.com/owner/repo/tar.gz/HEAD", "templates/api",
+			t.TempDir())
+		require.ErrorContains(t, err, "directory 'templates/api' not found")
+	})
+}
+
+func Test_Initializer_fetchCodeFallback(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+	templateUrl := "https://github.com/owner/repo"
+
+	// git can't reach the repository
+	mockRunner := mockexec.NewMockCommandRunner()
+	mockRunner.When(func(args exec.RunArgs, command string) bool { return true }).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(128, "", "fatal: unable to access"), errors.New("exit code: 128")
+		})
+
+	httpClient := mockhttp.NewMockHttpUtil()
+	archiveAvailable := true
+	httpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "codeload.github.com"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if !archiveAvailable {
+			return nil, errors.New("no such host")
+		}
+
+		return archiveResponse(t, request), nil
+	})
+
+	console := mockinput.NewMockConsole()
+	i := NewInitializer(console, git.NewGitCli(mockRunner), httpClient)
+
+	t.Run("Archive", func(t *testing.T) {
+		destination := t.TempDir()
+		executables, err := i.fetchCode(context.Background(), templateUrl, "", "templates/web", destination)
+		require.NoError(t, err)
+		require.Equal(t, []string{"run.sh"}, executables)
+		require.FileExists(t, filepath.Join(destination, "app.py"))
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		archiveAvailable = false
+		destination := t.TempDir()
+		executables, err := i.fetchCode(context.Background(), templateUrl, "", "templates/web", destination)
+		require.NoError(t, err)
+		require.Equal(t, []string{"run.sh"}, executables)
+		require.FileExists(t, filepath.Join(destination, "app.py"))
+		require.Contains(t, strings.Join(console.Output(), "\n"), "using the copy cached on")
+	})
+
+	t.Run("NotCached", func(t *testing.T) {
+		archiveAvailable = false
+		_, err := i.fetchCode(context.Background(), templateUrl, "other-branch", "templates/web", t.TempDir())
+		require.ErrorContains(t, err, "fetching template")
+	})
+}
+
+// archiveResponse returns a tarball of a repository, as served by codeload.github.com.
+func archiveResponse(t *testing.T, request *http.Request) *http.Response {
+	files := []struct {
+		name string
+		mode int64
+		body string
+	}{
+		{"repo-sha/", 0755, ""},
+		{"repo-sha/README.md", 0644, "# repo"},
+		{"repo-sha/templates/web/app.py", 0644, "print('hello')"},
+		{"repo-sha/templates/web/run.sh", 0755, "python app.py"},
+		{"repo-sha/../escaped.txt", 0644, "escaped"},
+	}
+
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range files {
+		header := &tar.Header{Name: file.name, Mode: file.mode, Size: int64(len(file.body)), Typeflag: tar.TypeReg}
+		if file.body == "" {
+			header.Typeflag = tar.TypeDir
+		}
+
+		require.NoError(t, tarWriter.WriteHeader(header))
+		_, err := io.WriteString(tarWriter, file.body)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Request:    request,
+		Body:       io.NopCloser(buf),
+	}
+}
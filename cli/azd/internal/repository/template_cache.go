@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/otiai10/copy"
+)
+
+// The directory of the user config directory where the last fetched copy of each template is kept, to initialize
+// projects from templates when they can't be downloaded, like on a machine which is offline.
+const templateCacheDirName = "templates-cache"
+
+// The name of the metadata file of a cached template, next to the directory of its files
+const templateCacheMetadataFileName = "metadata.json"
+
+// The files of a cached template are in this directory of its cache entry
+const templateCacheFilesDirName = "files"
+
+// templateCacheMetadata describes a cached template.
+type templateCacheMetadata struct {
+	TemplateUrl         string    `json:"templateUrl"`
+	Branch              string    `json:"branch,omitempty"`
+	Path                string    `json:"path,omitempty"`
+	FetchedAt           time.Time `json:"fetchedAt"`
+	ExecutableFilePaths []string  `json:"executableFilePaths"`
+}
+
+// templateCacheDir returns the directory of the cache entry of the branch and directory of the template repository.
+func templateCacheDir(templateUrl string, branch string, templatePath string) (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	key := strings.Join([]string{strings.ToLower(templateUrl), branch, filepath.ToSlash(templatePath)}, "\n")
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(configDir, templateCacheDirName, hex.EncodeToString(hash[:])[:16]), nil
+}
+
+// cacheTemplate replaces the cached copy of the template with the files fetched in source.
+func cacheTemplate(metadata templateCacheMetadata, source string) error {
+	dir, err := templateCacheDir(metadata.TemplateUrl, metadata.Branch, metadata.Path)
+	if err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	if err := copy.Copy(source, filepath.Join(dir, templateCacheFilesDirName)); err != nil {
+		return fmt.Errorf("copying template to cache: %w", err)
+	}
+
+	content, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, templateCacheMetadataFileName), content, osutil.PermissionFile)
+}
+
+// restoreCachedTemplate copies the cached copy of the template to destination, returning its metadata. An error wrapping
+// os.ErrNotExist is returned when the template was never cached.
+func restoreCachedTemplate(
+	templateUrl string,
+	branch string,
+	templatePath string,
+	destination string,
+) (*templateCacheMetadata, error) {
+	dir, err := templateCacheDir(templateUrl, branch, templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, templateCacheMetadataFileName))
+	if err != nil {
+		return nil, fmt.Errorf("reading cached template: %w", err)
+	}
+
+	var metadata templateCacheMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, fmt.Errorf("reading cached template: %w", err)
+	}
+
+	if err := copy.Copy(filepath.Join(dir, templateCacheFilesDirName), destination); err != nil {
+		return nil, fmt.Errorf("copying cached template: %w", err)
+	}
+
+	return &metadata, nil
+}
@@ -21,3 +21,15 @@ const GitHubCliInstallEvent = "tools.gh.install"
 // AccountSubscriptionsListEvent is the name of the event which tracks listing of account subscriptions .
 // See fields.AccountSubscriptionsListTenantsFound for additional event fields.
 const AccountSubscriptionsListEvent = "account.subscriptions.list"
+
+// ExecRunEvent is the name of the event which tracks the execution of an external tool.
+// See fields.ExecCmdKey for additional event fields.
+const ExecRunEvent = "exec.run"
+
+// AzureRequestEvent is the name of the event which tracks a call to an Azure API.
+// See fields.HttpMethodKey for additional event fields.
+const AzureRequestEvent = "azure.request"
+
+// Service stage event names follow the convention service.<stage>, ex: service.package, service.deploy.
+// See fields.ServiceHostKey for additional event fields.
+const ServiceStageEventPrefix = "service."
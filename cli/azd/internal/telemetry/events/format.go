@@ -10,6 +10,11 @@ func GetCommandEventName(cmdPath string) string {
 	return CommandEventPrefix + formatCommandPath(cmdPath)
 }
 
+// GetServiceStageEventName returns the event name for a stage (restore, build, package, deploy) of a service.
+func GetServiceStageEventName(stage string) string {
+	return ServiceStageEventPrefix + stage
+}
+
 // formatCommandPath reformats the command path suitable for telemetry emission.
 //
 // It removes "azd" from command path and replaces spaces with dot.
@@ -94,6 +94,14 @@ const (
 	CmdEntry = attribute.Key("cmd.entry")
 )
 
+// Error related attributes
+const (
+	// The class of the failure of a failed operation. Only the category is recorded, the error message is not.
+	//
+	// See exitcode.Category for all possible values.
+	ErrorCategoryKey = attribute.Key("error.category")
+)
+
 // All possible enumerations of ExecutionEnvironmentKey
 const (
 	// Desktop environments
@@ -140,3 +148,29 @@ const (
 	// Number of tenants where listing of subscriptions failed
 	AccountSubscriptionsListTenantsFailed = attribute.Key("tenants.failed")
 )
+
+// Additional fields of events.ExecRunEvent
+const (
+	// The name of the executable run, without its path or arguments.
+	ExecCmdKey = attribute.Key("exec.cmd")
+	// The exit code of the process.
+	ExecExitCodeKey = attribute.Key("exec.exitCode")
+)
+
+// Additional fields of events.AzureRequestEvent
+const (
+	// The HTTP method of the request.
+	HttpMethodKey = semconv.HTTPMethodKey // http.method
+	// The HTTP status code of the response.
+	HttpStatusCodeKey = semconv.HTTPStatusCodeKey // http.status_code
+	// The host of the Azure endpoint. The path, which contains resource identifiers, is not recorded.
+	HttpHostKey = semconv.NetPeerNameKey // net.peer.name
+)
+
+// Additional fields of the service stage events, see events.GetServiceStageEventName
+const (
+	// The host of the service, ex: appservice, containerapp.
+	ServiceHostKey = attribute.Key("service.host")
+	// The language of the service, ex: python, dotnet.
+	ServiceLanguageKey = attribute.Key("service.language")
+)
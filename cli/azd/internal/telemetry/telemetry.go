@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -64,8 +65,48 @@ func getTelemetryDirectory() (string, error) {
 	return telemetryDir, nil
 }
 
+// TelemetryEnabledConfigPath is the path of the user configuration setting which opts out of telemetry when set to false,
+// ex: `azd config set telemetry.enabled false`.
+const TelemetryEnabledConfigPath = "telemetry.enabled"
+
+// IsTelemetryEnabled returns false when the user opted out of telemetry, either by setting AZURE_DEV_COLLECT_TELEMETRY
+// to 'no' or by setting telemetry.enabled to false in the user configuration.
 func IsTelemetryEnabled() bool {
-	return os.Getenv(collectTelemetryEnvVar) != "no"
+	if os.Getenv(collectTelemetryEnvVar) == "no" {
+		return false
+	}
+
+	userConfig, err := config.NewUserConfigManager().Load()
+	if err != nil {
+		log.Printf("failed loading user config to check telemetry opt-out: %v", err)
+		return true
+	}
+
+	return isEnabledInConfig(userConfig)
+}
+
+// isEnabledInConfig returns false when telemetry.enabled is set to false in the user configuration. Values set with
+// `azd config set` are stored as strings.
+func isEnabledInConfig(userConfig config.Config) bool {
+	value, has := userConfig.Get(TelemetryEnabledConfigPath)
+	if !has {
+		return true
+	}
+
+	switch enabled := value.(type) {
+	case bool:
+		return enabled
+	case string:
+		parsed, err := strconv.ParseBool(enabled)
+		if err != nil {
+			log.Printf("ignoring invalid value '%s' for '%s'", enabled, TelemetryEnabledConfigPath)
+			return true
+		}
+
+		return parsed
+	default:
+		return true
+	}
 }
 
 // Returns the singleton TelemetrySystem instance.
@@ -7,6 +7,7 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	appinsightsexporter "github.com/azure/azure-dev/cli/azd/internal/telemetry/appinsights-exporter"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/test/ostest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -112,3 +113,28 @@ func TestTelemetrySystem_RunBackgroundUpload(t *testing.T) {
 		})
 	}
 }
+
+func Test_isEnabledInConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected bool
+	}{
+		{"NotSet", nil, true},
+		{"False", "false", false},
+		{"True", "true", true},
+		{"Bool", false, false},
+		{"Invalid", "maybe", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userConfig := config.NewConfig(nil)
+			if tt.value != nil {
+				require.NoError(t, userConfig.Set(TelemetryEnabledConfigPath, tt.value))
+			}
+
+			require.Equal(t, tt.expected, isEnabledInConfig(userConfig))
+		})
+	}
+}
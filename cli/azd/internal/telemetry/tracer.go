@@ -8,6 +8,7 @@ import (
 
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry/baggage"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/codes"
 
@@ -107,7 +108,7 @@ func (s *wrapperSpan) End(options ...trace.SpanEndOption) {
 
 func (s *wrapperSpan) EndWithStatus(err error, options ...trace.SpanEndOption) {
 	if err != nil {
-		s.span.SetStatus(codes.Error, "UnknownError")
+		SetErrorStatus(s, err)
 	} else {
 		s.span.SetStatus(codes.Ok, "")
 	}
@@ -152,6 +153,14 @@ func (s *wrapperSpan) TracerProvider() trace.TracerProvider {
 	return s.span.TracerProvider()
 }
 
+// SetErrorStatus marks the span as failed with the class of the failure. The error message, which may contain user
+// data, is not recorded.
+func SetErrorStatus(span Span, err error) {
+	category := exitcode.Classify(err)
+	span.SetStatus(codes.Error, string(category))
+	span.SetAttributes(fields.ErrorCategoryKey.String(string(category)))
+}
+
 // GetTracer returns the application tracer for azd.
 func GetTracer() Tracer {
 	return &wrapperTracer{otel.Tracer(fields.ServiceNameAzd)}
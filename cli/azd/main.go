@@ -8,16 +8,13 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
-	"path/filepath"
-	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"time"
@@ -26,22 +23,52 @@ import (
 	"github.com/azure/azure-dev/cli/azd/cmd"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
-	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	azdexec "github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
+	"github.com/azure/azure-dev/cli/azd/pkg/logging"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/profiling"
+	"github.com/azure/azure-dev/cli/azd/pkg/shutdown"
+	"github.com/azure/azure-dev/cli/azd/pkg/support"
+	"github.com/azure/azure-dev/cli/azd/pkg/update"
 	"github.com/blang/semver/v4"
 	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 )
 
 func main() {
-	ctx := context.Background()
+	// Ctrl+C cancels the context of the running command and runs the cleanups registered by its operations
+	coordinator := shutdown.NewCoordinator(shutdown.DefaultGracePeriod)
+	ctx := coordinator.Context(context.Background())
+	stopSignals := coordinator.NotifySignals(func() {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, output.WithWarningFormat("Cancelling, press Ctrl+C again to exit immediately..."))
+	})
 
 	restoreColorMode := colorable.EnableColorsStdout(nil)
 	defer restoreColorMode()
 
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 
+	// Logs are always captured to the log file of the invocation, and written to stderr with --debug
+	var logWriters []io.Writer
+	logFile := openLogFile()
+	if logFile != nil {
+		logWriters = append(logWriters, logFile)
+	}
+
 	if isDebugEnabled() {
+		logWriters = append(logWriters, os.Stderr)
+	}
+
+	if len(logWriters) > 0 {
+		log.SetOutput(io.MultiWriter(logWriters...))
 		azcorelog.SetListener(func(event azcorelog.Event, msg string) {
 			log.Printf("%s: %s\n", event, msg)
 		})
@@ -50,13 +77,44 @@ func main() {
 	}
 
 	log.Printf("azd version: %s", internal.Version)
+	log.Printf("correlation id: %s", azsdk.CorrelationId())
+
+	if isHttpTraceEnabled() {
+		azsdk.EnableHttpTrace(os.Stderr)
+	}
+
+	// A crash writes a support bundle, with the stack trace and the log of the invocation, to attach to an issue
+	defer func() {
+		if value := recover(); value != nil {
+			handleCrash(value, debug.Stack(), logFile)
+		}
+	}()
 
 	ts := telemetry.GetTelemetrySystem()
 
 	latest := make(chan semver.Version)
 	go fetchLatestVersion(latest)
 
-	cmdErr := cmd.NewRootCmd(false, nil).ExecuteContext(ctx)
+	profile, timings := profilingOptions()
+	stopProfile := startProfile(profile)
+	if timings {
+		profiling.EnableTimings()
+	}
+
+	commandStart := time.Now()
+	cmdErr := executeCommands(ctx, os.Args[1:])
+	stopSignals()
+	coordinator.Wait()
+	cleanArtifacts()
+
+	stopProfile()
+	if timings {
+		fmt.Fprintln(os.Stderr)
+		if err := profiling.WriteTimingReport(os.Stderr, profiling.Stages(), time.Since(commandStart)); err != nil {
+			log.Printf("failed writing timing report: %v", err)
+		}
+	}
+
 	latestVersion, ok := <-latest
 
 	// If we were able to fetch a latest version, check to see if we are up to date and
@@ -71,34 +129,29 @@ func main() {
 			// This is a dev build (i.e. built using `go install without setting a version`) - don't print a warning in this
 			// case
 			log.Printf("eliding update message for dev build")
-		} else if latestVersion.GT(internal.VersionInfo().Version) {
-			var upgradeUrl string
-			if runtime.GOOS == "windows" {
-				upgradeUrl = "https://aka.ms/azd/upgrade/windows"
-			} else if runtime.GOOS == "linux" {
-				upgradeUrl = "https://aka.ms/azd/upgrade/linux"
-			} else if runtime.GOOS == "darwin" {
-				upgradeUrl = "https://aka.ms/azd/upgrade/mac"
-			} else {
-				// Platform is not recognized, use the generic install link
-				upgradeUrl = "https://aka.ms/azd/upgrade"
-			}
-
+		} else if update.IsNewer(latestVersion) {
 			fmt.Fprintln(
 				os.Stderr,
 				output.WithWarningFormat(
 					"warning: your version of azd is out of date, you have %s and the latest version is %s",
 					internal.VersionInfo().Version.String(), latestVersion.String()))
 			fmt.Fprintln(os.Stderr)
-			fmt.Fprintln(
-				os.Stderr,
-				output.WithWarningFormat(`To update to the latest version, follow instructions at: %s`,
-					upgradeUrl))
+			if command := update.UpgradeCommand(); command != "" {
+				fmt.Fprintln(
+					os.Stderr,
+					output.WithWarningFormat("To update to the latest version, run: %s", command))
+			} else {
+				fmt.Fprintln(
+					os.Stderr,
+					output.WithWarningFormat(
+						"To update to the latest version, follow the instructions at %s", update.InstallInstructionsUrl))
+			}
 		}
 	}
 
 	if ts != nil {
-		err := ts.Shutdown(ctx)
+		// The command context is cancelled when the user cancels the operation, telemetry is still flushed in that case
+		err := ts.Shutdown(context.Background())
 		if err != nil {
 			log.Printf("non-graceful telemetry shutdown: %v\n", err)
 		}
@@ -111,21 +164,167 @@ func main() {
 		}
 	}
 
+	if coordinator.Cancelled() {
+		if isJsonOutput() {
+			if err := writeJsonError(os.Stderr, exitcode.CategoryCancelled, context.Canceled); err != nil {
+				log.Printf("failed writing error output: %v", err)
+			}
+		} else {
+			fmt.Fprintln(os.Stderr, output.WithWarningFormat("The operation was cancelled."))
+		}
+		os.Exit(shutdown.ExitCodeCancelled)
+	}
+
 	if cmdErr != nil {
-		os.Exit(1)
+		category := exitcode.Classify(cmdErr)
+
+		// Scripts and IDE extensions branch on the failure type using the final error object
+		if isJsonOutput() {
+			if err := writeJsonError(os.Stderr, category, cmdErr); err != nil {
+				log.Printf("failed writing error output: %v", err)
+			}
+		}
+
+		os.Exit(category.ExitCode())
+	}
+}
+
+// executeCommands runs the azd command of the arguments, or the commands of the alias named by the first argument in
+// order, until one of them fails.
+func executeCommands(ctx context.Context, args []string) error {
+	commands, err := cmd.ExpandAlias(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, output.WithErrorFormat("ERROR: %v", err))
+		return err
+	}
+
+	for _, commandArgs := range commands {
+		if len(commands) > 1 {
+			fmt.Fprintln(os.Stderr, output.WithGrayFormat("Running: azd %s", strings.Join(commandArgs, " ")))
+		}
+
+		rootCmd := cmd.NewRootCmd(false, nil)
+		rootCmd.SetArgs(commandArgs)
+		if err := rootCmd.ExecuteContext(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeJsonError writes the error which caused the command to fail as an event envelope, along with the category of the
+// failure and the exit code of the process.
+func writeJsonError(w io.Writer, category exitcode.Category, cmdErr error) error {
+	return json.NewEncoder(w).Encode(contracts.EventEnvelope{
+		Type:      contracts.ErrorEventDataType,
+		Timestamp: time.Now(),
+		Data: contracts.ErrorMessage{
+			Category:      string(category),
+			ExitCode:      category.ExitCode(),
+			Message:       cmdErr.Error(),
+			CorrelationId: errorCorrelationId(),
+		},
+	})
+}
+
+// errorCorrelationId returns the correlation ID of the requests sent by the failed command, or an empty string when it
+// didn't send any.
+func errorCorrelationId() string {
+	if !azsdk.CorrelationIdSent() {
+		return ""
 	}
+
+	return azsdk.CorrelationId()
+}
+
+// unloggedCommands are the hidden commands run by tools rather than by the user: the shell completion requests made on
+// each Tab press, the background telemetry upload, and the access tokens requested by Azure SDK credentials. They don't
+// capture logs, so they don't rotate away the logs of the user's commands.
+var unloggedCommands = [][]string{
+	{cobra.ShellCompRequestCmd},
+	{cobra.ShellCompNoDescRequestCmd},
+	{cmd.TelemetryCommandFlag},
+	{"auth", "token"},
 }
 
-// azdConfigDir is the name of the folder where `azd` writes user wide configuration data.
-const azdConfigDir = ".azd"
+// isUnloggedCommand returns whether the command run with the given arguments is one of the unloggedCommands.
+func isUnloggedCommand(args []string) bool {
+	var names []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			names = append(names, arg)
+		}
+	}
+
+	for _, command := range unloggedCommands {
+		if len(names) >= len(command) && slices.Equal(names[:len(command)], command) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// openLogFile creates the log file of the current invocation, returning nil when logs can't be captured or aren't
+// captured for the command.
+func openLogFile() *logging.File {
+	if isUnloggedCommand(os.Args[1:]) {
+		return nil
+	}
+
+	logsDir, err := logging.Dir()
+	if err != nil {
+		return nil
+	}
+
+	logFile, err := logging.Open(logsDir, logging.DefaultMaxFiles, logging.DefaultMaxSize, time.Now())
+	if err != nil {
+		return nil
+	}
+
+	return logFile
+}
+
+// handleCrash writes a support bundle capturing the crash, then exits after printing instructions to report it.
+func handleCrash(value any, stack []byte, logFile *logging.File) {
+	log.Printf("panic: %v\n%s", value, stack)
+
+	fmt.Fprintln(os.Stderr, output.WithErrorFormat("azd encountered an unexpected error and had to exit: %v", value))
+
+	bundle := support.Bundle{
+		Crash:        &support.Crash{Value: value, Stack: stack},
+		ToolVersions: support.ToolVersions(context.Background(), azdexec.NewCommandRunner(nil, nil, nil)),
+	}
+
+	if logFile != nil {
+		bundle.LogPath = logFile.Path()
+	}
+
+	bundlePath, err := support.DefaultPath(time.Now())
+	if err == nil {
+		err = support.Write(bundlePath, bundle)
+	}
+
+	if err != nil {
+		log.Printf("failed to write support bundle: %v", err)
+		fmt.Fprintf(os.Stderr, "\n%s\n", stack)
+		fmt.Fprintf(os.Stderr, "Please report this issue at %s, including the stack trace above.\n", support.IssueUrl)
+	} else {
+		fmt.Fprintf(os.Stderr, "\nA support bundle was written to %s\n", bundlePath)
+		fmt.Fprintf(
+			os.Stderr,
+			"Please report this issue at %s and attach the support bundle, after reviewing its contents.\n",
+			support.IssueUrl,
+		)
+	}
 
-// updateCheckCacheFileName is the name of the file created in the azd configuration directory
-// which is used to cache version information for our up to date check.
-const updateCheckCacheFileName = "update-check.json"
+	os.Exit(exitcode.CategoryUnknown.ExitCode())
+}
 
-// fetchLatestVersion fetches the latest version of the CLI and sends the result
-// across the version channel, which it then closes. If the latest version can not
-// be determined, the channel is closed without writing a value.
+// fetchLatestVersion fetches the latest version of the CLI on the configured release channel and sends the result
+// across the version channel, which it then closes. If the latest version can not be determined, or the check is
+// disabled, the channel is closed without writing a value.
 func fetchLatestVersion(version chan<- semver.Version) {
 	defer close(version)
 
@@ -141,129 +340,108 @@ func fetchLatestVersion(version chan<- semver.Version) {
 		}
 	}
 
-	// To avoid fetching the latest version of the CLI on every invocation, we cache the result for a period
-	// of time, in the user's home directory.
-	homeDir, err := os.UserHomeDir()
+	userConfig, err := config.NewUserConfigManager().Load()
 	if err != nil {
-		log.Printf("could not determine current home directory: %v, skipping update check", err)
+		log.Printf("failed to load user configuration: %v, skipping update check", err)
 		return
 	}
 
-	cacheFilePath := filepath.Join(homeDir, azdConfigDir, updateCheckCacheFileName)
-	cacheFile, err := os.ReadFile(cacheFilePath)
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		log.Printf("error reading update cache file: %v, skipping update check", err)
+	updateConfig, err := update.LoadConfig(userConfig)
+	if err != nil {
+		log.Printf("%v, skipping update check", err)
 		return
 	}
 
-	// If we were able to read the update file, try to interpret it and use the cached
-	// value if it is still valid. Note the `err == nil` guard here ensures we don't run
-	// this logic when the cache file did not exist (since err will be a form of fs.ErrNotExist)
-	var cachedLatestVersion *semver.Version
-	if err == nil {
-		var cache updateCacheFile
-		if err := json.Unmarshal(cacheFile, &cache); err == nil {
-			parsedVersion, parseVersionErr := semver.Parse(cache.Version)
-			parsedExpiresOn, parseExpiresOnErr := time.Parse(time.RFC3339, cache.ExpiresOn)
-
-			if parseVersionErr == nil && parseExpiresOnErr == nil {
-				if time.Now().UTC().Before(parsedExpiresOn) {
-					log.Printf("using cached latest version: %s (expires on: %s)", cache.Version, cache.ExpiresOn)
-					cachedLatestVersion = &parsedVersion
-				} else {
-					log.Printf("ignoring cached latest version, it is out of date")
-				}
-			} else {
-				if parseVersionErr != nil {
-					log.Printf("failed to parse cached version '%s' as a semver: %v,"+
-						" ignoring cached value", cache.Version, parseVersionErr)
-				}
-				if parseExpiresOnErr != nil {
-					log.Printf(
-						"failed to parse cached version expiration time '%s' as a RFC3339"+
-							" timestamp: %v, ignoring cached value",
-						cache.ExpiresOn,
-						parseExpiresOnErr)
-				}
-			}
-		} else {
-			log.Printf("could not unmarshal cache file: %v, ignoring cache", err)
-		}
+	if updateConfig.CheckInterval == 0 {
+		log.Printf("skipping update check since %s is 0", update.CheckIntervalConfigPath)
+		return
 	}
 
-	// If we don't have a cached version we can use, fetch one (and cache it)
-	if cachedLatestVersion == nil {
-		log.Print("fetching latest version information for update check")
-		req, err := http.NewRequest(http.MethodGet, "https://aka.ms/azure-dev/versions/cli/latest", nil)
-		if err != nil {
-			log.Printf("failed to create request object: %v, skipping update check", err)
-		}
+	// To avoid fetching the latest version of the CLI on every invocation, the result is cached for the configured
+	// check interval.
+	latest, err := update.NewManager(http.DefaultClient).LatestVersion(context.Background(), updateConfig, true)
+	if err != nil {
+		log.Printf("%v, skipping update check", err)
+		return
+	}
 
-		req.Header.Set("User-Agent", internal.MakeUserAgentString(""))
+	// Publish our value, the defer above will close the channel.
+	version <- latest
+}
 
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			log.Printf("failed to fetch latest version: %v, skipping update check", err)
-			return
-		}
-		body, err := readToEndAndClose(res.Body)
-		if err != nil {
-			log.Printf("failed to read response body: %v, skipping update check", err)
-			return
-		}
+// cleanArtifacts removes the artifacts older than the configured retention, and the oldest artifacts past the configured
+// size, once the command completed so the artifacts of the command can't be removed while it uses them.
+func cleanArtifacts() {
+	userConfig, err := config.NewUserConfigManager().Load()
+	if err != nil {
+		log.Printf("failed to load user configuration: %v, skipping artifacts cleanup", err)
+		return
+	}
 
-		if res.StatusCode != http.StatusOK {
-			log.Printf(
-				"failed to refresh latest version, http status: %v, body: %v, skipping update check",
-				res.StatusCode,
-				body,
-			)
-			return
-		}
+	artifactsConfig, err := artifacts.LoadConfig(userConfig)
+	if err != nil {
+		log.Printf("%v, skipping artifacts cleanup", err)
+		return
+	}
 
-		// Parse the body of the response as a semver, and if it's valid, cache it.
-		fetchedVersionText := strings.TrimSpace(body)
-		fetchedVersion, err := semver.Parse(fetchedVersionText)
-		if err != nil {
-			log.Printf("failed to parse latest version '%s' as a semver: %v, skipping update check", fetchedVersionText, err)
-			return
-		}
+	dir, err := artifacts.Dir()
+	if err != nil {
+		log.Printf("%v, skipping artifacts cleanup", err)
+		return
+	}
 
-		cachedLatestVersion = &fetchedVersion
+	removed, err := artifacts.Clean(dir, artifactsConfig, time.Now())
+	if err != nil {
+		log.Printf("failed cleaning artifacts: %v", err)
+	}
 
-		// Write the value back to the cache. Note that on these logging paths for errors we do not return
-		// eagerly, since we have not yet sent the latest versions across the channel (and we don't want to do that until
-		// we've updated the cache since reader on the other end of the channel will exit the process after it receives this
-		// value and finishes
-		// the up to date check, possibly while this go-routine is still running)
-		if err := os.MkdirAll(filepath.Dir(cacheFilePath), osutil.PermissionFile); err != nil {
-			log.Printf("failed to create cache folder '%s': %v", filepath.Dir(cacheFilePath), err)
-		} else {
-			cacheObject := updateCacheFile{
-				Version:   fetchedVersionText,
-				ExpiresOn: time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339),
-			}
+	if len(removed) > 0 {
+		log.Printf("removed %d expired artifacts from %s", len(removed), dir)
+	}
+}
 
-			// The marshal call can not fail, so we ignore the error.
-			cacheContents, _ := json.Marshal(cacheObject)
+// startProfile starts the pprof profile requested with --profile, in the current directory. The returned function stops
+// the profile.
+func startProfile(kind string) (stop func()) {
+	if kind == "" {
+		return func() {}
+	}
 
-			if err := os.WriteFile(cacheFilePath, cacheContents, osutil.PermissionDirectory); err != nil {
-				log.Printf("failed to write update cache file: %v", err)
-			} else {
-				log.Printf("updated cache file to version %s (expires on: %s)", cacheObject.Version, cacheObject.ExpiresOn)
-			}
-		}
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, output.WithWarningFormat("warning: profiling is disabled: %v", err))
+		return func() {}
 	}
 
-	// Publish our value, the defer above will close the channel.
-	version <- *cachedLatestVersion
+	stopProfile, err := profiling.StartProfile(kind, dir, time.Now())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, output.WithWarningFormat("warning: profiling is disabled: %v", err))
+		return func() {}
+	}
+
+	return func() {
+		path, err := stopProfile()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, output.WithWarningFormat("warning: failed writing %s profile: %v", kind, err))
+			return
+		}
+
+		fmt.Fprintf(os.Stderr, "%s profile written to %s\n", kind, path)
+	}
 }
 
-type updateCacheFile struct {
-	// The semver of the  latest version the CLI
-	Version string `json:"version"`
-	// A time at which this cached value expires, stored as an RFC3339 timestamp
-	ExpiresOn string `json:"expiresOn"`
+// profilingOptions returns the values of the hidden `--profile` and `--timings` flags.
+func profilingOptions() (profile string, timings bool) {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+
+	// See isDebugEnabled, the flags of the command are not defined in this flag set.
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.StringVar(&profile, "profile", "", "")
+	flags.BoolVar(&timings, "timings", false, "")
+	flags.Usage = func() {}
+
+	_ = flags.Parse(os.Args[1:])
+	return profile, timings
 }
 
 // isDebugEnabled checks to see if `--debug` was passed with a truthy
@@ -288,6 +466,20 @@ func isDebugEnabled() bool {
 	return debug
 }
 
+// isHttpTraceEnabled checks to see if `--trace-http` was passed with a truthy value.
+func isHttpTraceEnabled() bool {
+	traceHttp := false
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+
+	// See isDebugEnabled, the flags of the command are not defined in this flag set.
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.BoolVar(&traceHttp, "trace-http", false, "")
+	flags.Usage = func() {}
+
+	_ = flags.Parse(os.Args[1:])
+	return traceHttp
+}
+
 // isJsonOutput checks to see if `--output` was passed with the value `json`
 func isJsonOutput() bool {
 	output := ""
@@ -310,13 +502,6 @@ func isJsonOutput() bool {
 	return output == "json"
 }
 
-func readToEndAndClose(r io.ReadCloser) (string, error) {
-	defer r.Close()
-	var buf strings.Builder
-	_, err := io.Copy(&buf, r)
-	return buf.String(), err
-}
-
 func startBackgroundUploadProcess() error {
 	// The background upload process executable is ourself
 	execPath, err := os.Executable()
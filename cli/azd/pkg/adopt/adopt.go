@@ -0,0 +1,144 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package adopt brings the resources of an existing resource group under azd management, by generating an azure.yaml
+// with a service for each resource running application code, and Bicep referencing the existing resources.
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// ServiceTag is the tag azd uses to find the resource hosting a service.
+const ServiceTag = "azd-service-name"
+
+// The API version used to read the kind of web sites, which tells function apps from app services
+const webSiteApiVersion = "2021-03-01"
+
+// Service is a resource of the resource group running application code, which becomes an azd service.
+type Service struct {
+	Name     string
+	Host     project.ServiceTargetKind
+	Language project.ServiceLanguageKind
+	// The path of the service code in azure.yaml, like ./src/api
+	Project  string
+	Resource azcli.AzCliResourceExtended
+}
+
+// Discover lists the resources of the resource group, with the kind of the web sites.
+func Discover(
+	ctx context.Context,
+	azCli azcli.AzCli,
+	subscriptionId string,
+	resourceGroup string,
+) ([]azcli.AzCliResourceExtended, error) {
+	resources, err := azCli.ListResourceGroupResources(ctx, subscriptionId, resourceGroup, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing resources of resource group %s: %w", resourceGroup, err)
+	}
+
+	var discovered []azcli.AzCliResourceExtended
+	for _, resource := range resources {
+		if !isResourceType(resource, infra.AzureResourceTypeWebSite) {
+			discovered = append(discovered, azcli.AzCliResourceExtended{AzCliResource: resource})
+			continue
+		}
+
+		extended, err := azCli.GetResource(ctx, subscriptionId, resource.Id, webSiteApiVersion)
+		if err != nil {
+			return nil, fmt.Errorf("reading web site %s: %w", resource.Name, err)
+		}
+
+		discovered = append(discovered, extended)
+	}
+
+	sort.Slice(discovered, func(i, j int) bool {
+		return discovered[i].Id < discovered[j].Id
+	})
+
+	return discovered, nil
+}
+
+// Services returns a service for each resource hosting application code, sorted by name. A resource already tagged
+// for azd keeps the name of its tag, the others are named after the resource.
+func Services(resources []azcli.AzCliResourceExtended) []*Service {
+	var services []*Service
+	names := map[string]bool{}
+
+	for _, resource := range resources {
+		host := serviceHost(resource)
+		if host == "" {
+			continue
+		}
+
+		name := resource.Tags[ServiceTag]
+		if name == "" {
+			name = serviceName(resource.Name)
+		}
+
+		uniqueName := name
+		for i := 2; names[uniqueName]; i++ {
+			uniqueName = fmt.Sprintf("%s-%d", name, i)
+		}
+		names[uniqueName] = true
+
+		services = append(services, &Service{
+			Name:     uniqueName,
+			Host:     host,
+			Project:  "./" + path.Join("src", uniqueName),
+			Resource: resource,
+		})
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+
+	return services
+}
+
+// serviceHost returns the azd host of a resource running application code, or an empty kind for other resources.
+func serviceHost(resource azcli.AzCliResourceExtended) project.ServiceTargetKind {
+	switch {
+	case isResourceType(resource.AzCliResource, infra.AzureResourceTypeWebSite):
+		if strings.Contains(resource.Kind, "functionapp") {
+			return project.AzureFunctionTarget
+		}
+		return project.AppServiceTarget
+	case isResourceType(resource.AzCliResource, infra.AzureResourceTypeContainerApp):
+		return project.ContainerAppTarget
+	case isResourceType(resource.AzCliResource, infra.AzureResourceTypeStaticWebSite):
+		return project.StaticWebAppTarget
+	case isResourceType(resource.AzCliResource, infra.AzureResourceTypeManagedCluster):
+		return project.AksTarget
+	}
+
+	return ""
+}
+
+// Azure Resource Manager does not preserve the casing of resource types
+func isResourceType(resource azcli.AzCliResource, resourceType infra.AzureResourceType) bool {
+	return strings.EqualFold(resource.Type, string(resourceType))
+}
+
+var invalidServiceNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// serviceName converts the name of a resource to a service name, with lowercase letters, numbers and dashes.
+func serviceName(resourceName string) string {
+	name := invalidServiceNameChars.ReplaceAllString(strings.ToLower(resourceName), "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "app"
+	}
+
+	return name
+}
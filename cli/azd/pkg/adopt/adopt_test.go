@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package adopt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/stretchr/testify/require"
+)
+
+func testResource(name string, resourceType string, kind string, tags map[string]string) azcli.AzCliResourceExtended {
+	return azcli.AzCliResourceExtended{
+		AzCliResource: azcli.AzCliResource{
+			Id:   "/subscriptions/SUB/resourceGroups/RG/providers/" + resourceType + "/" + name,
+			Name: name,
+			Type: resourceType,
+			Tags: tags,
+		},
+		Kind: kind,
+	}
+}
+
+var testResources = []azcli.AzCliResourceExtended{
+	testResource("app-web", "Microsoft.Web/sites", "app,linux", nil),
+	testResource("func-jobs", "microsoft.web/sites", "functionapp,linux", nil),
+	testResource("ca-orders", "Microsoft.App/containerApps", "", map[string]string{ServiceTag: "orders"}),
+	testResource("plan-web", "Microsoft.Web/serverfarms", "", nil),
+	testResource("crapp", "Microsoft.ContainerRegistry/registries", "", nil),
+	testResource("bus-app", "Microsoft.ServiceBus/namespaces", "", nil),
+}
+
+func TestServices(t *testing.T) {
+	services := Services(testResources)
+
+	var names []string
+	for _, service := range services {
+		names = append(names, service.Name)
+	}
+	require.Equal(t, []string{"app-web", "func-jobs", "orders"}, names)
+
+	require.Equal(t, project.AppServiceTarget, services[0].Host)
+	require.Equal(t, project.AzureFunctionTarget, services[1].Host)
+	require.Equal(t, project.ContainerAppTarget, services[2].Host)
+	require.Equal(t, "./src/orders", services[2].Project)
+	require.Equal(t, "ca-orders", services[2].Resource.Name)
+}
+
+func TestServicesUniqueNames(t *testing.T) {
+	services := Services([]azcli.AzCliResourceExtended{
+		testResource("Web_App", "Microsoft.Web/sites", "app", nil),
+		testResource("web-app", "Microsoft.App/containerApps", "", nil),
+	})
+
+	require.Len(t, services, 2)
+	require.Equal(t, "web-app", services[0].Name)
+	require.Equal(t, "web-app-2", services[1].Name)
+}
+
+func TestGenerate(t *testing.T) {
+	projectDir := t.TempDir()
+	services := Services(testResources)
+	for _, service := range services {
+		service.Language = project.ServiceLanguagePython
+	}
+
+	err := Generate(projectDir, "shop", "rg-shop", testResources, services)
+	require.NoError(t, err)
+
+	projectFile, err := os.ReadFile(filepath.Join(projectDir, "azure.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(projectFile), "name: shop")
+	require.Contains(t, string(projectFile), "    orders:\n        project: ./src/orders\n"+
+		"        language: python\n        host: containerapp\n")
+
+	resourcesBicep, err := os.ReadFile(filepath.Join(projectDir, resourcesBicepPath))
+	require.NoError(t, err)
+	require.Contains(t, string(resourcesBicep),
+		"resource appWeb 'Microsoft.Web/sites@2022-03-01' existing = {\n  name: 'app-web'\n}")
+	require.Contains(t, string(resourcesBicep), "resource funcJobs 'Microsoft.Web/sites@2022-03-01' existing")
+	require.Contains(t, string(resourcesBicep), "//   bus-app (Microsoft.ServiceBus/namespaces)")
+	require.Contains(t, string(resourcesBicep),
+		"output AZURE_CONTAINER_REGISTRY_ENDPOINT string = crapp.properties.loginServer")
+
+	mainBicep, err := os.ReadFile(filepath.Join(projectDir, mainBicepPath))
+	require.NoError(t, err)
+	require.Contains(t, string(mainBicep), "param resourceGroupName string = 'rg-shop'")
+	require.Contains(t, string(mainBicep), "output AZURE_CONTAINER_REGISTRY_ENDPOINT string")
+
+	err = Generate(projectDir, "shop", "rg-shop", testResources, services)
+	require.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestBicepIdentifier(t *testing.T) {
+	require.Equal(t, "myApi", bicepIdentifier("my-api"))
+	require.Equal(t, "stWebProd", bicepIdentifier("st_web.prod"))
+	require.Equal(t, "resource1app", bicepIdentifier("1app"))
+	require.Equal(t, "resource", bicepIdentifier("--"))
+}
@@ -0,0 +1,296 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package adopt
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"gopkg.in/yaml.v3"
+)
+
+// The infrastructure files written by Generate, relative to the project directory
+var (
+	mainBicepPath      = filepath.Join("infra", "main.bicep")
+	resourcesBicepPath = filepath.Join("infra", "resources.bicep")
+	parametersPath     = filepath.Join("infra", "main.parameters.json")
+)
+
+// The API versions of the resource types referenced in resources.bicep. Resources of other types are listed in a
+// comment, to be referenced by hand.
+var apiVersions = map[infra.AzureResourceType]string{
+	infra.AzureResourceTypeApim:                    "2022-08-01",
+	infra.AzureResourceTypeAppConfig:               "2023-03-01",
+	infra.AzureResourceTypeAppInsightComponent:     "2020-02-02",
+	infra.AzureResourceTypeCacheForRedis:           "2023-04-01",
+	infra.AzureResourceTypeCognitiveServiceAccount: "2023-05-01",
+	infra.AzureResourceTypeContainerApp:            "2023-05-01",
+	infra.AzureResourceTypeContainerAppEnvironment: "2023-05-01",
+	infra.AzureResourceTypeContainerRegistry:       "2023-07-01",
+	infra.AzureResourceTypeCosmosDb:                "2023-04-15",
+	infra.AzureResourceTypeKeyVault:                "2022-07-01",
+	infra.AzureResourceTypeLogAnalyticsWorkspace:   "2022-10-01",
+	infra.AzureResourceTypeManagedCluster:          "2023-08-01",
+	infra.AzureResourceTypeMySqlServer:             "2021-05-01",
+	infra.AzureResourceTypePostgreSqlServer:        "2022-12-01",
+	infra.AzureResourceTypeSearchService:           "2022-09-01",
+	infra.AzureResourceTypeServicePlan:             "2022-03-01",
+	infra.AzureResourceTypeSqlServer:               "2021-11-01",
+	infra.AzureResourceTypeStaticWebSite:           "2022-03-01",
+	infra.AzureResourceTypeStorageAccount:          "2022-09-01",
+	infra.AzureResourceTypeWebSite:                 "2022-03-01",
+}
+
+// projectFile is the subset of azure.yaml written for the services of a resource group.
+type projectFile struct {
+	Name     string                  `yaml:"name"`
+	Services map[string]*serviceFile `yaml:"services,omitempty"`
+}
+
+type serviceFile struct {
+	Project  string                      `yaml:"project"`
+	Language project.ServiceLanguageKind `yaml:"language"`
+	Host     project.ServiceTargetKind   `yaml:"host"`
+}
+
+// existingResource is a resource of resources.bicep.
+type existingResource struct {
+	// The symbolic name of the resource
+	Identifier string
+	Name       string
+	Type       string
+	ApiVersion string
+}
+
+// resourcesBicep is the content of resources.bicep.
+type resourcesBicep struct {
+	Resources []existingResource
+	// The resources of the resource group azd has no API version for
+	Unknown []azcli.AzCliResourceExtended
+	// The symbolic name of the container registry of the resource group, if any
+	Registry string
+}
+
+// Generate writes the azure.yaml of the services to the project directory, and the Bicep infrastructure referencing
+// the existing resources of the resource group. It returns os.ErrExist when one of the files already exists, without
+// writing any.
+func Generate(
+	projectDir string,
+	projectName string,
+	resourceGroup string,
+	resources []azcli.AzCliResourceExtended,
+	services []*Service,
+) error {
+	files := map[string][]byte{}
+
+	projectContent, err := generateProjectFile(projectName, services)
+	if err != nil {
+		return err
+	}
+	files[azdcontext.ProjectFileName] = projectContent
+
+	bicep := generateResourcesBicep(resources)
+	var resourcesContent bytes.Buffer
+	if err := resourcesTemplate.Execute(&resourcesContent, bicep); err != nil {
+		return fmt.Errorf("generating %s: %w", resourcesBicepPath, err)
+	}
+	files[resourcesBicepPath] = resourcesContent.Bytes()
+
+	var mainContent bytes.Buffer
+	if err := mainTemplate.Execute(&mainContent, struct {
+		ResourceGroup string
+		Registry      bool
+	}{
+		ResourceGroup: resourceGroup,
+		Registry:      bicep.Registry != "",
+	}); err != nil {
+		return fmt.Errorf("generating %s: %w", mainBicepPath, err)
+	}
+	files[mainBicepPath] = mainContent.Bytes()
+	files[parametersPath] = []byte(mainParameters)
+
+	for path := range files {
+		if _, err := os.Stat(filepath.Join(projectDir, path)); err == nil {
+			return fmt.Errorf("%s: %w", path, os.ErrExist)
+		}
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(projectDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), osutil.PermissionDirectory); err != nil {
+			return fmt.Errorf("creating directory for %s: %w", path, err)
+		}
+
+		if err := os.WriteFile(fullPath, content, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func generateProjectFile(projectName string, services []*Service) ([]byte, error) {
+	file := projectFile{
+		Name:     projectName,
+		Services: map[string]*serviceFile{},
+	}
+
+	for _, service := range services {
+		file.Services[service.Name] = &serviceFile{
+			Project:  service.Project,
+			Language: service.Language,
+			Host:     service.Host,
+		}
+	}
+
+	content, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling project file: %w", err)
+	}
+
+	return append([]byte(project.SchemaAnnotation+"\n\n"), content...), nil
+}
+
+func generateResourcesBicep(resources []azcli.AzCliResourceExtended) resourcesBicep {
+	var bicep resourcesBicep
+	identifiers := map[string]bool{}
+
+	for _, resource := range resources {
+		resourceType, apiVersion := knownResourceType(resource.Type)
+		if apiVersion == "" {
+			bicep.Unknown = append(bicep.Unknown, resource)
+			continue
+		}
+
+		name := bicepIdentifier(resource.Name)
+		identifier := name
+		for i := 2; identifiers[identifier]; i++ {
+			identifier = fmt.Sprintf("%s%d", name, i)
+		}
+		identifiers[identifier] = true
+
+		bicep.Resources = append(bicep.Resources, existingResource{
+			Identifier: identifier,
+			Name:       resource.Name,
+			Type:       string(resourceType),
+			ApiVersion: apiVersion,
+		})
+
+		if resourceType == infra.AzureResourceTypeContainerRegistry && bicep.Registry == "" {
+			bicep.Registry = identifier
+		}
+	}
+
+	return bicep
+}
+
+// knownResourceType returns the resource type, with the casing of azd, and its API version, or an empty API version
+// when the type is unknown.
+func knownResourceType(resourceType string) (infra.AzureResourceType, string) {
+	for known, apiVersion := range apiVersions {
+		if strings.EqualFold(resourceType, string(known)) {
+			return known, apiVersion
+		}
+	}
+
+	return infra.AzureResourceType(resourceType), ""
+}
+
+var invalidIdentifierChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// bicepIdentifier converts the name of a resource to a symbolic name, like my-api to myApi.
+func bicepIdentifier(name string) string {
+	parts := invalidIdentifierChars.Split(name, -1)
+
+	var identifier strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		if identifier.Len() == 0 {
+			identifier.WriteString(strings.ToLower(part[:1]) + part[1:])
+		} else {
+			identifier.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+
+	if identifier.Len() == 0 || (identifier.String()[0] >= '0' && identifier.String()[0] <= '9') {
+		return "resource" + identifier.String()
+	}
+
+	return identifier.String()
+}
+
+var resourcesTemplate = template.Must(template.New("resources").Parse(`// The existing resources of the resource group.
+// Replace 'existing' with the properties of a resource to have azd provision it.
+{{range .Resources}}
+resource {{.Identifier}} '{{.Type}}@{{.ApiVersion}}' existing = {
+  name: '{{.Name}}'
+}
+{{end}}
+{{- if .Unknown}}
+// The resources below have a type azd does not know the API version of, reference them as needed:
+{{- range .Unknown}}
+//   {{.Name}} ({{.Type}})
+{{- end}}
+{{end}}
+{{- if .Registry}}
+output AZURE_CONTAINER_REGISTRY_ENDPOINT string = {{.Registry}}.properties.loginServer
+{{- end}}
+`))
+
+var mainTemplate = template.Must(template.New("main").Parse(`targetScope = 'subscription'
+
+@minLength(1)
+@maxLength(64)
+@description('Name of the environment which is used to generate a short unique hash used in all resources.')
+param environmentName string
+
+@minLength(1)
+@description('Primary location for all resources')
+param location string
+
+@description('Name of the existing resource group holding the resources of the application')
+param resourceGroupName string = '{{.ResourceGroup}}'
+
+resource rg 'Microsoft.Resources/resourceGroups@2022-09-01' existing = {
+  name: resourceGroupName
+}
+
+module resources 'resources.bicep' = {
+  name: 'resources'
+  scope: rg
+}
+
+output AZURE_LOCATION string = location
+output AZURE_RESOURCE_GROUP string = rg.name
+{{- if .Registry}}
+output AZURE_CONTAINER_REGISTRY_ENDPOINT string = resources.outputs.AZURE_CONTAINER_REGISTRY_ENDPOINT
+{{- end}}
+`))
+
+const mainParameters = `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "environmentName": {
+      "value": "${AZURE_ENV_NAME}"
+    },
+    "location": {
+      "value": "${AZURE_LOCATION}"
+    }
+  }
+}
+`
@@ -0,0 +1,207 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package alias provides the command aliases of the user, stored in the user configuration.
+//
+// An alias expands to an azd command, like `deploy --all --environment prod`, or to a composite sequence of azd
+// commands separated by &&, like `provision && deploy --all`, which run in order until one of them fails. The
+// arguments following the alias on the command line are appended to the last command.
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/kballard/go-shellquote"
+	"golang.org/x/exp/slices"
+)
+
+const aliasesConfigPath = "alias"
+
+// Separator separates the commands of a composite alias.
+const Separator = "&&"
+
+var (
+	ErrAliasNotFound = errors.New("alias not found")
+	ErrInvalidConfig = errors.New("invalid aliases in user configuration")
+)
+
+// Alias is a command alias of the user.
+type Alias struct {
+	// The name of the alias, typed in place of a command, like deploy-all.
+	Name string
+	// The azd command the alias expands to, without the leading azd, like deploy --all --environment prod.
+	Command string
+}
+
+// Manager manages the command aliases of the user.
+type Manager struct {
+	configManager config.UserConfigManager
+}
+
+// NewManager creates an alias manager storing the aliases in the user configuration.
+func NewManager(configManager config.UserConfigManager) *Manager {
+	return &Manager{
+		configManager: configManager,
+	}
+}
+
+// List returns the aliases, sorted by name.
+func (m *Manager) List() ([]*Alias, error) {
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases []*Alias
+	if raw, has := userConfig.Get(aliasesConfigPath); has {
+		values, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("'%s' isn't an object: %w", aliasesConfigPath, ErrInvalidConfig)
+		}
+
+		for name, value := range values {
+			command, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("the command of alias '%s' isn't a string: %w", name, ErrInvalidConfig)
+			}
+
+			aliases = append(aliases, &Alias{
+				Name:    name,
+				Command: command,
+			})
+		}
+	}
+
+	slices.SortFunc(aliases, func(a, b *Alias) bool {
+		return a.Name < b.Name
+	})
+
+	return aliases, nil
+}
+
+// Get returns the alias with the specified name, or ErrAliasNotFound.
+func (m *Manager) Get(name string) (*Alias, error) {
+	aliases, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases {
+		if alias.Name == name {
+			return alias, nil
+		}
+	}
+
+	return nil, fmt.Errorf("'%s': %w", name, ErrAliasNotFound)
+}
+
+// Set creates or updates the alias with the specified name.
+func (m *Manager) Set(name string, command string) (*Alias, error) {
+	if name == "" || strings.ContainsAny(name, ". \t") || strings.HasPrefix(name, "-") {
+		return nil, fmt.Errorf(
+			"alias name '%s' is invalid, it must not be empty, start with '-' or contain '.' or spaces", name)
+	}
+
+	if _, err := Parse(command); err != nil {
+		return nil, err
+	}
+
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := userConfig.Set(aliasConfigPath(name), command); err != nil {
+		return nil, fmt.Errorf("setting alias '%s': %w", name, err)
+	}
+
+	if err := m.configManager.Save(userConfig); err != nil {
+		return nil, err
+	}
+
+	return &Alias{
+		Name:    name,
+		Command: command,
+	}, nil
+}
+
+// Unset removes the alias with the specified name, or returns ErrAliasNotFound.
+func (m *Manager) Unset(name string) error {
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := userConfig.Unset(aliasConfigPath(name)); err != nil {
+		return fmt.Errorf("removing alias '%s': %w", name, err)
+	}
+
+	return m.configManager.Save(userConfig)
+}
+
+// Expand returns the commands the alias named by the first argument expands to, each as the arguments of azd, with the
+// remaining arguments appended to the last command. It returns nil when the first argument isn't an alias.
+func (m *Manager) Expand(args []string) ([][]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	alias, err := m.Get(args[0])
+	if errors.Is(err, ErrAliasNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	commands, err := Parse(alias.Command)
+	if err != nil {
+		return nil, fmt.Errorf("expanding alias '%s': %w", alias.Name, err)
+	}
+
+	last := len(commands) - 1
+	commands[last] = append(commands[last], args[1:]...)
+
+	return commands, nil
+}
+
+// Parse splits the command of an alias into the arguments of each of its azd commands. Arguments are split like a POSIX
+// shell does, quotes included, and the commands may start with azd.
+func Parse(command string) ([][]string, error) {
+	words, err := shellquote.Split(command)
+	if err != nil {
+		return nil, fmt.Errorf("parsing command '%s': %w", command, err)
+	}
+
+	var commands [][]string
+	current := []string{}
+	for i := 0; i <= len(words); i++ {
+		if i < len(words) && words[i] != Separator {
+			current = append(current, words[i])
+			continue
+		}
+
+		if len(current) > 0 && current[0] == "azd" {
+			current = current[1:]
+		}
+
+		if len(current) == 0 {
+			return nil, fmt.Errorf("parsing command '%s': expected an azd command", command)
+		}
+
+		commands = append(commands, current)
+		current = []string{}
+	}
+
+	return commands, nil
+}
+
+func aliasConfigPath(name string) string {
+	return fmt.Sprintf("%s.%s", aliasesConfigPath, name)
+}
@@ -0,0 +1,109 @@
+package alias
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Manager(t *testing.T) {
+	manager := NewManager(newMemoryConfigManager())
+
+	aliases, err := manager.List()
+	require.NoError(t, err)
+	require.Empty(t, aliases)
+
+	_, err = manager.Set("deploy-all", "deploy --all --environment prod")
+	require.NoError(t, err)
+	_, err = manager.Set("ship", "azd provision && azd deploy --all")
+	require.NoError(t, err)
+
+	aliases, err = manager.List()
+	require.NoError(t, err)
+	require.Equal(t, []*Alias{
+		{Name: "deploy-all", Command: "deploy --all --environment prod"},
+		{Name: "ship", Command: "azd provision && azd deploy --all"},
+	}, aliases)
+
+	_, err = manager.Set("invalid.name", "deploy")
+	require.Error(t, err)
+	_, err = manager.Set("empty", "provision &&")
+	require.Error(t, err)
+
+	require.NoError(t, manager.Unset("deploy-all"))
+	require.True(t, errors.Is(manager.Unset("deploy-all"), ErrAliasNotFound))
+}
+
+func Test_Manager_Expand(t *testing.T) {
+	manager := NewManager(newMemoryConfigManager())
+	_, err := manager.Set("deploy-all", "deploy --all --environment prod")
+	require.NoError(t, err)
+	_, err = manager.Set("ship", "provision && deploy --all")
+	require.NoError(t, err)
+
+	commands, err := manager.Expand([]string{"deploy-all", "--debug"})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"deploy", "--all", "--environment", "prod", "--debug"}}, commands)
+
+	// The arguments are appended to the last command of a composite alias
+	commands, err = manager.Expand([]string{"ship", "--no-prompt"})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"provision"}, {"deploy", "--all", "--no-prompt"}}, commands)
+
+	commands, err = manager.Expand([]string{"deploy", "--all"})
+	require.NoError(t, err)
+	require.Nil(t, commands)
+}
+
+func Test_Manager_InvalidConfig(t *testing.T) {
+	configManager := newMemoryConfigManager()
+	manager := NewManager(configManager)
+
+	userConfig, err := configManager.Load()
+	require.NoError(t, err)
+	require.NoError(t, userConfig.Set("alias", "deploy --all"))
+
+	_, err = manager.List()
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = manager.Expand([]string{"deploy-all"})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	require.NoError(t, userConfig.Set("alias", map[string]any{"deploy-all": 1}))
+
+	_, err = manager.Expand([]string{"deploy-all"})
+	require.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func Test_Parse(t *testing.T) {
+	commands, err := Parse(`env set GREETING "hello world" && azd up`)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"env", "set", "GREETING", "hello world"}, {"up"}}, commands)
+
+	_, err = Parse(`deploy "unterminated`)
+	require.Error(t, err)
+
+	_, err = Parse("azd")
+	require.Error(t, err)
+}
+
+func newMemoryConfigManager() config.UserConfigManager {
+	return &memoryConfigManager{
+		config: config.NewConfig(nil),
+	}
+}
+
+type memoryConfigManager struct {
+	config config.Config
+}
+
+func (m *memoryConfigManager) Load() (config.Config, error) {
+	return m.config, nil
+}
+
+func (m *memoryConfigManager) Save(cfg config.Config) error {
+	m.config = cfg
+	return nil
+}
@@ -0,0 +1,291 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package appconfig syncs a declarative set of Azure App Configuration key-values and feature flags, kept alongside
+// the code of the project, to the App Configuration store of an environment.
+package appconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/drone/envsubst"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// EndpointEnvVarName is the environment value of the App Configuration store endpoint used when the project doesn't
+// configure one.
+const EndpointEnvVarName = "AZURE_APP_CONFIGURATION_ENDPOINT"
+
+const (
+	// Feature flags are key-values with this key prefix
+	featureFlagKeyPrefix = ".appconfig.featureflag/"
+	// The content type of feature flags
+	featureFlagContentType = "application/vnd.microsoft.appconfig.ff+json;charset=utf-8"
+)
+
+// Document is the desired configuration of an environment, like appconfig/dev.yaml:
+//
+//	label: dev
+//	settings:
+//	  Api:BaseUrl: https://${API_HOST}
+//	featureFlags:
+//	  Beta: false
+type Document struct {
+	// The label of the key-values, the key-values have no label when omitted
+	Label string `yaml:"label"`
+	// The key-values, values support environment variable substitution
+	Settings map[string]string `yaml:"settings"`
+	// The feature flags and whether they are enabled
+	FeatureFlags map[string]bool `yaml:"featureFlags"`
+}
+
+// Load reads the document at path, substituting the environment values referenced by the settings.
+func Load(path string, getenv func(string) string) (*Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading app configuration file: %w", err)
+	}
+
+	var document Document
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("parsing app configuration file %s: %w", path, err)
+	}
+
+	for key, value := range document.Settings {
+		expanded, err := envsubst.Eval(value, getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding setting '%s': %w", key, err)
+		}
+
+		document.Settings[key] = expanded
+	}
+
+	return &document, nil
+}
+
+// ChangeKind is the kind of change made to a key-value
+type ChangeKind string
+
+const (
+	ChangeAdd    ChangeKind = "add"
+	ChangeUpdate ChangeKind = "update"
+	ChangeDelete ChangeKind = "delete"
+)
+
+// Change is a change of a key-value of the store required to match the document
+type Change struct {
+	Kind        ChangeKind
+	Key         string
+	Value       string
+	ContentType string
+}
+
+// The value displayed in place of the values of settings, which may be secrets like connection strings
+const maskedValue = "********"
+
+// String renders the change as a line of a diff, like `+ Api:BaseUrl = ********`. The values of settings are masked,
+// only the state of feature flags is displayed.
+func (c Change) String() string {
+	name := c.Key
+	value := maskedValue
+	if strings.HasPrefix(c.Key, featureFlagKeyPrefix) {
+		name = fmt.Sprintf("%s (feature flag)", strings.TrimPrefix(c.Key, featureFlagKeyPrefix))
+		value = featureFlagState(c.Value)
+	}
+
+	switch c.Kind {
+	case ChangeAdd:
+		return fmt.Sprintf("+ %s = %s", name, value)
+	case ChangeUpdate:
+		return fmt.Sprintf("~ %s = %s", name, value)
+	default:
+		return fmt.Sprintf("- %s", name)
+	}
+}
+
+// featureFlagState returns whether the feature flag with the JSON value is enabled or disabled.
+func featureFlagState(value string) string {
+	var flag struct {
+		Enabled bool `json:"enabled"`
+	}
+
+	if err := json.Unmarshal([]byte(value), &flag); err != nil {
+		return maskedValue
+	}
+
+	if flag.Enabled {
+		return "enabled"
+	}
+
+	return "disabled"
+}
+
+// Diff returns the changes, sorted by key, which make the key-values of the store match the document. Key-values of
+// the store which aren't in the document are only deleted with deleteOrphans.
+func Diff(document *Document, current []*azsdk.AppConfigurationKeyValue, deleteOrphans bool) ([]Change, error) {
+	currentByKey := map[string]*azsdk.AppConfigurationKeyValue{}
+	for _, keyValue := range current {
+		currentByKey[keyValue.Key] = keyValue
+	}
+
+	desired := map[string]Change{}
+	for key, value := range document.Settings {
+		desired[key] = Change{Key: key, Value: value}
+	}
+
+	for name, enabled := range document.FeatureFlags {
+		key := featureFlagKeyPrefix + name
+		value, err := featureFlagValue(name, enabled, currentByKey[key])
+		if err != nil {
+			return nil, err
+		}
+
+		desired[key] = Change{Key: key, Value: value, ContentType: featureFlagContentType}
+	}
+
+	changes := []Change{}
+	for key, change := range desired {
+		existing, has := currentByKey[key]
+		switch {
+		case !has:
+			change.Kind = ChangeAdd
+		case existing.Value != change.Value || existing.ContentType != change.ContentType:
+			change.Kind = ChangeUpdate
+		default:
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	if deleteOrphans {
+		for key := range currentByKey {
+			if _, has := desired[key]; !has {
+				changes = append(changes, Change{Kind: ChangeDelete, Key: key})
+			}
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) bool {
+		return a.Key < b.Key
+	})
+
+	return changes, nil
+}
+
+// featureFlagValue returns the value of the feature flag. The existing value is kept, with only the enabled state
+// changed, so filters configured in the store aren't lost.
+func featureFlagValue(name string, enabled bool, existing *azsdk.AppConfigurationKeyValue) (string, error) {
+	flag := map[string]any{
+		"id":          name,
+		"description": "",
+		"enabled":     enabled,
+		"conditions": map[string]any{
+			"client_filters": []any{},
+		},
+	}
+
+	if existing != nil && existing.Value != "" {
+		flag = map[string]any{}
+		if err := json.Unmarshal([]byte(existing.Value), &flag); err != nil {
+			return "", fmt.Errorf("parsing feature flag '%s': %w", name, err)
+		}
+
+		if current, ok := flag["enabled"].(bool); ok && current == enabled {
+			return existing.Value, nil
+		}
+
+		flag["enabled"] = enabled
+	}
+
+	content, err := json.Marshal(flag)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// SyncOptions configures a sync of the store
+type SyncOptions struct {
+	// Returns the changes without applying them
+	Preview bool
+	// Deletes the key-values of the label which aren't in the document
+	DeleteOrphans bool
+}
+
+// Syncer syncs documents to App Configuration stores
+type Syncer struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
+}
+
+// NewSyncer creates a new instance of the Syncer
+func NewSyncer(
+	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
+) *Syncer {
+	return &Syncer{
+		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
+	}
+}
+
+// Sync makes the key-values of the store match the document and returns the changes made, or the changes which
+// would be made when previewing.
+func (s *Syncer) Sync(
+	ctx context.Context,
+	subscriptionId string,
+	endpoint string,
+	document *Document,
+	options SyncOptions,
+) ([]Change, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := azsdk.DefaultClientOptionsBuilder(s.httpClient, internal.MakeUserAgentString("")).
+		BuildCoreClientOptions()
+	client, err := azsdk.NewAppConfigurationClient(endpoint, credential, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := client.KeyValues(ctx, document.Label)
+	if err != nil {
+		return nil, fmt.Errorf("listing key-values: %w", err)
+	}
+
+	changes, err := Diff(document, current, options.DeleteOrphans)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Preview {
+		return changes, nil
+	}
+
+	for _, change := range changes {
+		if change.Kind == ChangeDelete {
+			err = client.DeleteKeyValue(ctx, change.Key, document.Label)
+		} else {
+			err = client.SetKeyValue(ctx, change.Key, document.Label, change.Value, change.ContentType)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("applying change to '%s': %w", change.Key, err)
+		}
+	}
+
+	return changes, nil
+}
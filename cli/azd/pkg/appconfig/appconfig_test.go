@@ -0,0 +1,118 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev.yaml")
+	content := `
+label: dev
+settings:
+  Api:BaseUrl: https://${API_HOST}
+featureFlags:
+  Beta: true
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+
+	document, err := Load(path, func(name string) string {
+		return map[string]string{"API_HOST": "api.contoso.com"}[name]
+	})
+	require.NoError(t, err)
+	require.Equal(t, &Document{
+		Label:        "dev",
+		Settings:     map[string]string{"Api:BaseUrl": "https://api.contoso.com"},
+		FeatureFlags: map[string]bool{"Beta": true},
+	}, document)
+}
+
+func TestDiff(t *testing.T) {
+	document := &Document{
+		Settings: map[string]string{
+			"Api:BaseUrl":  "https://api.contoso.com",
+			"Api:Timeout":  "30",
+			"Api:Retries":  "3",
+			"Web:Greeting": "Hello",
+		},
+		FeatureFlags: map[string]bool{
+			"Beta":    true,
+			"Preview": false,
+		},
+	}
+
+	current := []*azsdk.AppConfigurationKeyValue{
+		{Key: "Api:BaseUrl", Value: "https://api.contoso.com"},
+		{Key: "Api:Timeout", Value: "10"},
+		{Key: "Legacy:Setting", Value: "value"},
+		{
+			Key:         featureFlagKeyPrefix + "Preview",
+			Value:       `{"id":"Preview","enabled":false,"conditions":{"client_filters":[{"name":"Percentage"}]}}`,
+			ContentType: featureFlagContentType,
+		},
+	}
+
+	t.Run("KeepOrphans", func(t *testing.T) {
+		changes, err := Diff(document, current, false)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"+ .appconfig.featureflag/Beta",
+			"+ Api:Retries",
+			"~ Api:Timeout",
+			"+ Web:Greeting",
+		}, changeSummaries(changes))
+		require.Equal(t, ChangeAdd, changes[0].Kind)
+		require.Equal(t, featureFlagContentType, changes[0].ContentType)
+		require.JSONEq(t,
+			`{"id":"Beta","description":"","enabled":true,"conditions":{"client_filters":[]}}`,
+			changes[0].Value,
+		)
+	})
+
+	t.Run("DeleteOrphans", func(t *testing.T) {
+		changes, err := Diff(document, current, true)
+		require.NoError(t, err)
+		require.Contains(t, changes, Change{Kind: ChangeDelete, Key: "Legacy:Setting"})
+	})
+
+	t.Run("FeatureFlagKeepsFilters", func(t *testing.T) {
+		document := &Document{FeatureFlags: map[string]bool{"Preview": true}}
+
+		changes, err := Diff(document, current, false)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, ChangeUpdate, changes[0].Kind)
+		require.JSONEq(t,
+			`{"id":"Preview","enabled":true,"conditions":{"client_filters":[{"name":"Percentage"}]}}`,
+			changes[0].Value,
+		)
+	})
+}
+
+func TestChangeString(t *testing.T) {
+	// The values of settings may be secrets and are never displayed
+	require.Equal(t, "+ Api:Retries = ********", Change{Kind: ChangeAdd, Key: "Api:Retries", Value: "3"}.String())
+	require.Equal(t, "~ Beta (feature flag) = enabled", Change{
+		Kind: ChangeUpdate, Key: featureFlagKeyPrefix + "Beta", Value: `{"id": "Beta", "enabled": true}`,
+	}.String())
+	require.Equal(t, "- Beta (feature flag)", Change{Kind: ChangeDelete, Key: featureFlagKeyPrefix + "Beta"}.String())
+}
+
+// changeSummaries returns the kind and key of the changes, rendered like the lines of the preview.
+func changeSummaries(changes []Change) []string {
+	summaries := []string{}
+	for _, change := range changes {
+		symbol := map[ChangeKind]string{ChangeAdd: "+", ChangeUpdate: "~", ChangeDelete: "-"}[change.Kind]
+		summaries = append(summaries, symbol+" "+change.Key)
+	}
+
+	return summaries
+}
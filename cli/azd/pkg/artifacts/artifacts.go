@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package artifacts manages the temporary artifacts azd creates, like the deployment packages and the build outputs of
+// the services, in the artifacts directory of the azd user configuration directory.
+//
+// Artifacts aren't removed once they're used, so they can be inspected or reused after a command. The artifacts older
+// than the retention, and the oldest artifacts past the maximum size of the directory, are removed by Clean.
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+const (
+	// RetentionConfigPath is the user configuration setting, in hours, of how long artifacts are kept, ex:
+	// `azd config set artifacts.retentionHours 72`.
+	RetentionConfigPath = "artifacts.retentionHours"
+
+	// MaxSizeConfigPath is the user configuration setting, in megabytes, of the maximum size of the artifacts directory.
+	MaxSizeConfigPath = "artifacts.maxSizeMB"
+
+	// DefaultRetention is how long artifacts are kept when not configured.
+	DefaultRetention = 24 * time.Hour
+
+	// DefaultMaxSize is the maximum size, in bytes, of the artifacts directory when not configured.
+	DefaultMaxSize = 2 * 1024 * 1024 * 1024
+
+	artifactsDir = "artifacts"
+)
+
+// Config is the artifact retention configuration of the user.
+type Config struct {
+	Retention time.Duration
+	MaxSize   int64
+}
+
+// LoadConfig reads the artifact retention configuration from the user configuration. Values set with `azd config set`
+// are stored as strings.
+func LoadConfig(userConfig config.Config) (Config, error) {
+	result := Config{
+		Retention: DefaultRetention,
+		MaxSize:   DefaultMaxSize,
+	}
+
+	if value, has := userConfig.Get(RetentionConfigPath); has {
+		hours, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+		if err != nil || hours < 0 {
+			return result, fmt.Errorf("invalid value '%v' for '%s', expected a number of hours", value, RetentionConfigPath)
+		}
+
+		result.Retention = time.Duration(hours * float64(time.Hour))
+	}
+
+	if value, has := userConfig.Get(MaxSizeConfigPath); has {
+		megabytes, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+		if err != nil || megabytes < 0 {
+			return result, fmt.Errorf("invalid value '%v' for '%s', expected a number of megabytes", value, MaxSizeConfigPath)
+		}
+
+		result.MaxSize = int64(megabytes * 1024 * 1024)
+	}
+
+	return result, nil
+}
+
+// Dir returns the directory where artifacts are created.
+func Dir() (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, artifactsDir), nil
+}
+
+// CreateTemp creates a new artifact file, named after pattern like os.CreateTemp.
+func CreateTemp(pattern string) (*os.File, error) {
+	dir, err := ensureDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return os.CreateTemp(dir, pattern)
+}
+
+// MkdirTemp creates a new artifact directory, named after pattern like os.MkdirTemp.
+func MkdirTemp(pattern string) (string, error) {
+	dir, err := ensureDir()
+	if err != nil {
+		return "", err
+	}
+
+	return os.MkdirTemp(dir, pattern)
+}
+
+func ensureDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, osutil.PermissionDirectoryOwnerOnly); err != nil {
+		return "", fmt.Errorf("creating artifacts directory: %w", err)
+	}
+
+	return dir, nil
+}
+
+// Artifact is a file or a directory of the artifacts directory.
+type Artifact struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// List returns the artifacts of dir, oldest first. The size of a directory is the size of its files.
+func List(dir string) ([]Artifact, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading artifacts directory: %w", err)
+	}
+
+	artifacts := []Artifact{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		artifact := Artifact{
+			Path:    filepath.Join(dir, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}
+
+		if entry.IsDir() {
+			artifact.Size = dirSize(artifact.Path)
+		}
+
+		artifacts = append(artifacts, artifact)
+	}
+
+	sort.SliceStable(artifacts, func(i, j int) bool {
+		return artifacts[i].ModTime.Before(artifacts[j].ModTime)
+	})
+
+	return artifacts, nil
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size
+}
+
+// Clean removes the artifacts of dir older than the retention of the configuration, then the oldest artifacts until the
+// artifacts fit in the maximum size, and returns the removed artifacts.
+func Clean(dir string, config Config, now time.Time) ([]Artifact, error) {
+	artifacts, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, artifact := range artifacts {
+		total += artifact.Size
+	}
+
+	removed := []Artifact{}
+	for _, artifact := range artifacts {
+		if now.Sub(artifact.ModTime) <= config.Retention && total <= config.MaxSize {
+			break
+		}
+
+		if err := os.RemoveAll(artifact.Path); err != nil {
+			return removed, fmt.Errorf("removing artifact: %w", err)
+		}
+
+		total -= artifact.Size
+		removed = append(removed, artifact)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	result, err := LoadConfig(config.NewConfig(nil))
+	require.NoError(t, err)
+	require.Equal(t, Config{Retention: DefaultRetention, MaxSize: DefaultMaxSize}, result)
+
+	userConfig := config.NewConfig(nil)
+	require.NoError(t, userConfig.Set(RetentionConfigPath, "72"))
+	require.NoError(t, userConfig.Set(MaxSizeConfigPath, "512"))
+
+	result, err = LoadConfig(userConfig)
+	require.NoError(t, err)
+	require.Equal(t, Config{Retention: 72 * time.Hour, MaxSize: 512 * 1024 * 1024}, result)
+
+	require.NoError(t, userConfig.Set(RetentionConfigPath, "forever"))
+	_, err = LoadConfig(userConfig)
+	require.Error(t, err)
+}
+
+func TestCreateTemp(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	file, err := CreateTemp("azddeploy*.zip")
+	require.NoError(t, err)
+	defer file.Close()
+
+	dir, err := Dir()
+	require.NoError(t, err)
+	require.Equal(t, dir, filepath.Dir(file.Name()))
+}
+
+func TestClean(t *testing.T) {
+	now := time.Now()
+
+	createArtifacts := func(t *testing.T) string {
+		dir := t.TempDir()
+		for name, age := range map[string]time.Duration{"old.zip": 48 * time.Hour, "recent.zip": time.Hour} {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, make([]byte, 1024), 0600))
+			require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+		}
+
+		build := filepath.Join(dir, "azd123")
+		require.NoError(t, os.Mkdir(build, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(build, "app.js"), make([]byte, 1024), 0600))
+		require.NoError(t, os.Chtimes(build, now, now))
+
+		return dir
+	}
+
+	t.Run("Retention", func(t *testing.T) {
+		dir := createArtifacts(t)
+
+		removed, err := Clean(dir, Config{Retention: 24 * time.Hour, MaxSize: DefaultMaxSize}, now)
+		require.NoError(t, err)
+		require.Len(t, removed, 1)
+		require.Equal(t, filepath.Join(dir, "old.zip"), removed[0].Path)
+		require.NoFileExists(t, filepath.Join(dir, "old.zip"))
+		require.FileExists(t, filepath.Join(dir, "recent.zip"))
+	})
+
+	t.Run("MaxSize", func(t *testing.T) {
+		dir := createArtifacts(t)
+
+		removed, err := Clean(dir, Config{Retention: 72 * time.Hour, MaxSize: 1024}, now)
+		require.NoError(t, err)
+		require.Len(t, removed, 2)
+		require.NoFileExists(t, filepath.Join(dir, "recent.zip"))
+		require.DirExists(t, filepath.Join(dir, "azd123"))
+	})
+
+	t.Run("All", func(t *testing.T) {
+		dir := createArtifacts(t)
+
+		removed, err := Clean(dir, Config{}, now.Add(time.Second))
+		require.NoError(t, err)
+		require.Len(t, removed, 3)
+
+		artifacts, err := List(dir)
+		require.NoError(t, err)
+		require.Empty(t, artifacts)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package audit records the operations changing an environment, like provisioning and deploying, in an append-only log
+// stored with the environment. The log answers "who deployed what when".
+package audit
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// FileName is the name of the audit log, in the directory of the environment.
+const FileName = "history.jsonl"
+
+const (
+	// The name of the file storing the key of the inputs digests, in the user configuration directory.
+	digestKeyFileName = "audit.key"
+	digestKeySize     = 32
+)
+
+// Outcome is the result of an audited operation.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "succeeded"
+	OutcomeFailed    Outcome = "failed"
+	OutcomeCancelled Outcome = "cancelled"
+)
+
+// Entry is a single operation recorded in the audit log.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	// The command which ran the operation, ex) azd provision
+	Command string `json:"command"`
+	// The user of the machine azd ran on.
+	User string `json:"user,omitempty"`
+	// The Azure principal azd was logged in with, empty when it can't be determined.
+	Principal string `json:"principal,omitempty"`
+	// A digest of the arguments and flags of the command, keyed with the DigestKey of the azd install. Inputs may contain
+	// secrets, so only the digest is recorded, which allows comparing the inputs of two operations run on the same
+	// machine without allowing to guess the inputs from the log.
+	InputsDigest string        `json:"inputsDigest,omitempty"`
+	Outcome      Outcome       `json:"outcome"`
+	Duration     time.Duration `json:"duration"`
+	// The version of azd which ran the operation.
+	Version string `json:"version"`
+}
+
+// Log is the audit log of an environment.
+type Log struct {
+	path string
+}
+
+// NewLog returns the audit log of the environment stored in envRoot.
+func NewLog(envRoot string) *Log {
+	return &Log{
+		path: filepath.Join(envRoot, FileName),
+	}
+}
+
+// Append records an entry at the end of the log. Existing entries are never modified.
+func (l *Log) Append(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshalling audit entry: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, osutil.PermissionFile)
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing audit log: %w", err)
+	}
+
+	return file.Close()
+}
+
+// Entries returns the entries of the log, oldest first. Lines which can't be parsed, for example when the log was edited
+// by hand, are skipped.
+func (l *Log) Entries() ([]Entry, error) {
+	file, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer file.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DigestKey returns the key of the inputs digests of this azd install, generating it on first use. The key is stored in
+// the user configuration directory, readable only by the user, and never in the environment with the audit log.
+func DigestKey() ([]byte, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return loadOrCreateKey(filepath.Join(configDir, digestKeyFileName))
+}
+
+func loadOrCreateKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil && len(key) == digestKeySize {
+		return key, nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading digest key: %w", err)
+	}
+
+	key = make([]byte, digestKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating digest key: %w", err)
+	}
+
+	if err := os.WriteFile(path, key, osutil.PermissionFileOwnerOnly); err != nil {
+		return nil, fmt.Errorf("writing digest key: %w", err)
+	}
+
+	return key, nil
+}
+
+// InputsDigest returns the HMAC-SHA256 of the command, its arguments and the values of its flags, keyed with key. The
+// digest doesn't depend on the order the flags are specified in.
+func InputsDigest(key []byte, command string, args []string, flags map[string]string) string {
+	hash := hmac.New(sha256.New, key)
+	fmt.Fprintf(hash, "%s\n", command)
+
+	for _, arg := range args {
+		fmt.Fprintf(hash, "arg:%s\n", arg)
+	}
+
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(hash, "flag:%s=%s\n", name, flags[name])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
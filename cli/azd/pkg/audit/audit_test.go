@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Log(t *testing.T) {
+	log := NewLog(t.TempDir())
+
+	entries, err := log.Entries()
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	provision := Entry{
+		Timestamp:    time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+		Command:      "azd provision",
+		User:         "alice",
+		Principal:    "alice@contoso.com",
+		InputsDigest: InputsDigest(testKey, "azd provision", nil, nil),
+		Outcome:      OutcomeSucceeded,
+		Duration:     2 * time.Minute,
+		Version:      "1.0.0",
+	}
+	deploy := Entry{
+		Timestamp:    time.Date(2023, 5, 1, 10, 5, 0, 0, time.UTC),
+		Command:      "azd deploy",
+		InputsDigest: InputsDigest(testKey, "azd deploy", []string{"api"}, nil),
+		Outcome:      OutcomeFailed,
+		Version:      "1.0.0",
+	}
+
+	require.NoError(t, log.Append(provision))
+	require.NoError(t, log.Append(deploy))
+
+	entries, err = log.Entries()
+	require.NoError(t, err)
+	require.Equal(t, []Entry{provision, deploy}, entries)
+}
+
+func Test_Log_SkipsInvalidLines(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(root, FileName),
+		[]byte("not json\n\n{\"command\":\"azd down\",\"outcome\":\"succeeded\"}\n"),
+		0600,
+	))
+
+	entries, err := NewLog(root).Entries()
+	require.NoError(t, err)
+	require.Equal(t, []Entry{{Command: "azd down", Outcome: OutcomeSucceeded}}, entries)
+}
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func Test_InputsDigest(t *testing.T) {
+	args := []string{"KEY", "value"}
+	digest := InputsDigest(testKey, "azd env set", args, map[string]string{"environment": "dev", "debug": "true"})
+
+	// The order of the flags doesn't matter
+	require.Equal(t, digest, InputsDigest(
+		testKey, "azd env set", args, map[string]string{"debug": "true", "environment": "dev"}))
+
+	require.NotEqual(t, digest, InputsDigest(
+		testKey, "azd env set", []string{"KEY", "other"}, map[string]string{"environment": "dev", "debug": "true"}))
+	require.NotEqual(t, digest, InputsDigest(
+		testKey, "azd env set", args, map[string]string{"environment": "prod", "debug": "true"}))
+
+	// The same inputs digested with the key of another install can't be compared
+	require.NotEqual(t, digest, InputsDigest(
+		[]byte("another key"), "azd env set", args, map[string]string{"environment": "dev", "debug": "true"}))
+}
+
+func Test_DigestKey(t *testing.T) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	key, err := DigestKey()
+	require.NoError(t, err)
+	require.Len(t, key, digestKeySize)
+
+	// The key is generated once per install
+	again, err := DigestKey()
+	require.NoError(t, err)
+	require.Equal(t, key, again)
+}
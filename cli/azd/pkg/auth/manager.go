@@ -23,6 +23,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
 	"github.com/azure/azure-dev/cli/azd/pkg/github"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -114,7 +115,8 @@ func NewManager(configManager config.UserConfigManager, httpClient httputil.Http
 	}, nil
 }
 
-var ErrNoCurrentUser = errors.New("not logged in, run `azd auth login` to login")
+var ErrNoCurrentUser = exitcode.New(
+	exitcode.CategoryAuth, errors.New("not logged in, run `azd auth login` to login"))
 
 // EnsureLoggedInCredential uses the credential's GetToken method to ensure an access token can be fetched. If this fails,
 // nil, ErrNoCurrentUser is returned. On success, the token we fetched is returned.
@@ -304,6 +306,36 @@ func (m *Manager) GetLoggedInServicePrincipalTenantID(ctx context.Context) (*str
 	return currentUser.TenantID, nil
 }
 
+// CurrentPrincipal returns the name of the logged in principal: the user name for users, and the client ID for service
+// principals. It doesn't acquire a token, so an empty string is returned when the principal can't be determined from the
+// saved login, for example when delegating authentication to az.
+func (m *Manager) CurrentPrincipal(ctx context.Context) (string, error) {
+	cfg, err := m.configManager.Load()
+	if err != nil {
+		return "", fmt.Errorf("fetching current user: %w", err)
+	}
+
+	if shouldUseLegacyAuth(cfg) {
+		return "", nil
+	}
+
+	currentUser, err := readUserProperties(cfg)
+	if err != nil {
+		return "", ErrNoCurrentUser
+	}
+
+	if currentUser.ClientID != nil {
+		return fmt.Sprintf("serviceprincipal:%s", *currentUser.ClientID), nil
+	}
+
+	account, err := m.getSignedInAccount(ctx)
+	if err != nil || account == nil {
+		return "", err
+	}
+
+	return account.PreferredUsername, nil
+}
+
 func newCredentialFromClientSecret(tenantID string, clientID string, clientSecret string) (azcore.TokenCredential, error) {
 	cred, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
 	if err != nil {
@@ -42,6 +42,9 @@ var (
 	AzDoProjectDescription = "Azure Developer CLI Project"
 	// name of the service connection that will be used in the AzDo project. This will store the Azure service principal
 	ServiceConnectionName = "azconnection"
+	// prefix of the name of the variable group holding the values of an azd environment, followed by the name of the
+	// environment
+	VariableGroupNamePrefix = "azd-"
 )
 
 type AzureServicePrincipalCredentials struct {
@@ -91,7 +91,8 @@ func CreatePipeline(
 	credentials AzureServicePrincipalCredentials,
 	env *environment.Environment,
 	console input.Console,
-	provisioningProvider provisioning.Options) (*build.BuildDefinition, error) {
+	provisioningProvider provisioning.Options,
+	variableGroup *taskagent.VariableGroup) (*build.BuildDefinition, error) {
 
 	client, err := build.NewClient(ctx, connection)
 	if err != nil {
@@ -112,7 +113,17 @@ func CreatePipeline(
 		if err != nil {
 			return nil, err
 		}
+		// The variables set by `azd pipeline set-secrets` are kept
+		if definition.Variables != nil {
+			for name, variable := range *definition.Variables {
+				if _, has := (*buildDefinitionVariables)[name]; !has {
+					(*buildDefinitionVariables)[name] = variable
+				}
+			}
+		}
 		definition.Variables = buildDefinitionVariables
+		// The variable group of the azd environment replaces the one of the previous environment
+		linkVariableGroup(definition, variableGroup)
 		definition, err := client.UpdateDefinition(ctx, build.UpdateDefinitionArgs{
 			Definition:   definition,
 			Project:      &projectId,
@@ -130,7 +141,7 @@ func CreatePipeline(
 	}
 
 	createDefinitionArgs, err := createAzureDevPipelineArgs(
-		ctx, projectId, name, repoName, credentials, env, queue, provisioningProvider)
+		ctx, projectId, name, repoName, credentials, env, queue, provisioningProvider, variableGroup)
 	if err != nil {
 		return nil, err
 	}
@@ -147,11 +158,9 @@ func getDefinitionVariables(
 	env *environment.Environment,
 	credentials AzureServicePrincipalCredentials,
 	provisioningProvider provisioning.Options) (*map[string]build.BuildDefinitionVariable, error) {
+	// The name, the location and the subscription of the azd environment are held by its variable group
 	variables := map[string]build.BuildDefinitionVariable{
-		"AZURE_LOCATION":           createBuildDefinitionVariable(env.GetLocation(), false, false),
-		"AZURE_ENV_NAME":           createBuildDefinitionVariable(env.GetEnvName(), false, false),
 		"AZURE_SERVICE_CONNECTION": createBuildDefinitionVariable(ServiceConnectionName, false, false),
-		"AZURE_SUBSCRIPTION_ID":    createBuildDefinitionVariable(credentials.SubscriptionId, false, false),
 	}
 
 	if provisioningProvider.Provider == provisioning.Terraform {
@@ -184,6 +193,7 @@ func createAzureDevPipelineArgs(
 	env *environment.Environment,
 	queue *taskagent.TaskAgentQueue,
 	provisioningProvider provisioning.Options,
+	variableGroup *taskagent.VariableGroup,
 ) (*build.CreateDefinitionArgs, error) {
 
 	repoType := "tfsgit"
@@ -234,6 +244,7 @@ func createAzureDevPipelineArgs(
 		Variables:   buildDefinitionVariables,
 		Triggers:    &triggers,
 	}
+	linkVariableGroup(buildDefinition, variableGroup)
 
 	createDefinitionArgs := &build.CreateDefinitionArgs{
 		Project:    &projectId,
@@ -271,3 +282,86 @@ func QueueBuild(
 
 	return nil
 }
+
+// PipelineVariable is a variable of a pipeline, hidden from the logs of the runs when it is a secret.
+type PipelineVariable struct {
+	Value  string
+	Secret bool
+}
+
+// SetPipelineVariables adds or updates the variables of the pipeline created by `azd pipeline config` for the
+// repository. The other variables of the pipeline are left unchanged.
+func SetPipelineVariables(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	repoName string,
+	variables map[string]PipelineVariable,
+) error {
+	client, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	definition, err := findAzdPipeline(ctx, client, projectId, repoName)
+	if err != nil {
+		return err
+	}
+
+	definitionVariables := map[string]build.BuildDefinitionVariable{}
+	if definition.Variables != nil {
+		definitionVariables = *definition.Variables
+	}
+	for variableName, variable := range variables {
+		definitionVariables[variableName] = createBuildDefinitionVariable(variable.Value, variable.Secret, false)
+	}
+	definition.Variables = &definitionVariables
+
+	_, err = client.UpdateDefinition(ctx, build.UpdateDefinitionArgs{
+		Definition:   definition,
+		Project:      &projectId,
+		DefinitionId: definition.Id,
+	})
+	if err != nil {
+		return fmt.Errorf("updating the variables of pipeline %s: %w", *definition.Name, err)
+	}
+
+	return nil
+}
+
+// EnsurePipelineExists returns an error when the pipeline created by `azd pipeline config` for the repository doesn't
+// exist.
+func EnsurePipelineExists(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	repoName string,
+) error {
+	client, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	_, err = findAzdPipeline(ctx, client, projectId, repoName)
+	return err
+}
+
+// findAzdPipeline returns the pipeline created by `azd pipeline config` for the repository.
+func findAzdPipeline(
+	ctx context.Context,
+	client build.Client,
+	projectId string,
+	repoName string,
+) (*build.BuildDefinition, error) {
+	name := fmt.Sprintf("%s (%s)", AzurePipelineName, repoName)
+	definition, err := getPipelineDefinition(ctx, client, &projectId, &name)
+	if err != nil {
+		return nil, fmt.Errorf("finding pipeline %s: %w", name, err)
+	}
+	if definition == nil {
+		return nil, fmt.Errorf(
+			"pipeline %s not found, run %s to create it", name, output.WithHighLightFormat("azd pipeline config"))
+	}
+
+	return definition, nil
+}
@@ -43,6 +43,7 @@ func EnsurePatExists(ctx context.Context, env *environment.Environment, console
 		pat, err := console.Prompt(ctx, input.ConsoleOptions{
 			Message:      "Personal Access Token (PAT):",
 			DefaultValue: "",
+			IsPassword:   true,
 		})
 		if err != nil {
 			return "", false, fmt.Errorf("asking for pat: %w", err)
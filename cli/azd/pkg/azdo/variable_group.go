@@ -0,0 +1,223 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azdo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/microsoft/azure-devops-go-api/azuredevops"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/build"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/taskagent"
+)
+
+// the values of the azd environment held by the variable group, instead of the variables of the pipeline
+var variableGroupVariableNames = []string{
+	environment.EnvNameEnvVarName,
+	environment.LocationEnvVarName,
+	environment.SubscriptionIdEnvVarName,
+}
+
+// the description of the variable groups created by azd, which tells them apart from the groups of the user named
+// like them
+const variableGroupDescription = "Values of the azd environment %s, managed by azd pipeline config"
+
+// VariableGroupName returns the name of the variable group holding the values of the azd environment.
+func VariableGroupName(envName string) string {
+	return VariableGroupNamePrefix + envName
+}
+
+// EnsureVariableGroup creates the variable group holding the name, the location and the subscription of the azd
+// environment, or updates its values when it exists. The other variables of the group are left unchanged.
+func EnsureVariableGroup(
+	ctx context.Context,
+	connection *azuredevops.Connection,
+	projectId string,
+	env *environment.Environment,
+	subscriptionId string,
+	console input.Console,
+) (*taskagent.VariableGroup, error) {
+	client, err := taskagent.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	name := VariableGroupName(env.GetEnvName())
+	existing, err := findVariableGroup(ctx, client, projectId, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *map[string]interface{}
+	if existing != nil {
+		current = existing.Variables
+	}
+	variables := variableGroupVariables(current, map[string]string{
+		environment.EnvNameEnvVarName:        env.GetEnvName(),
+		environment.LocationEnvVarName:       env.GetLocation(),
+		environment.SubscriptionIdEnvVarName: subscriptionId,
+	})
+
+	groupType := "Vsts"
+	description := fmt.Sprintf(variableGroupDescription, env.GetEnvName())
+	parameters := &taskagent.VariableGroupParameters{
+		Name:        &name,
+		Description: &description,
+		Type:        &groupType,
+		Variables:   &variables,
+	}
+
+	var group *taskagent.VariableGroup
+	if existing != nil {
+		group, err = client.UpdateVariableGroup(ctx, taskagent.UpdateVariableGroupArgs{
+			Group:   parameters,
+			Project: &projectId,
+			GroupId: existing.Id,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("updating variable group %s: %w", name, err)
+		}
+		console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: "Azure DevOps",
+			Name: "Updated variable group",
+		})
+	} else {
+		group, err = client.AddVariableGroup(ctx, taskagent.AddVariableGroupArgs{
+			Group:   parameters,
+			Project: &projectId,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating variable group %s: %w", name, err)
+		}
+		console.MessageUxItem(ctx, &ux.DisplayedResource{
+			Type: "Azure DevOps",
+			Name: "Variable group",
+		})
+	}
+
+	// The group is authorized on updates too, as the pipelines may have lost the authorization since its creation
+	err = authorizeVariableGroupToAllPipelines(ctx, projectId, group, connection)
+	if err != nil {
+		return nil, fmt.Errorf("authorizing variable group: %w", err)
+	}
+
+	return group, nil
+}
+
+// find variable group by name, nil when the project doesn't have it
+func findVariableGroup(
+	ctx context.Context,
+	client taskagent.Client,
+	projectId string,
+	name string,
+) (*taskagent.VariableGroup, error) {
+	groups, err := client.GetVariableGroups(ctx, taskagent.GetVariableGroupsArgs{
+		Project:   &projectId,
+		GroupName: &name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding variable group %s: %w", name, err)
+	}
+	if groups == nil {
+		return nil, nil
+	}
+
+	for _, group := range *groups {
+		// the names of the variable groups are case insensitive
+		if group.Name != nil && strings.EqualFold(*group.Name, name) {
+			return &group, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// variableGroupVariables returns the variables of the group with the values set, keeping the other variables.
+func variableGroupVariables(current *map[string]interface{}, values map[string]string) map[string]interface{} {
+	variables := map[string]interface{}{}
+	if current != nil {
+		for name, variable := range *current {
+			variables[name] = variable
+		}
+	}
+	for name, value := range values {
+		variables[name] = map[string]interface{}{
+			"value":    value,
+			"isSecret": false,
+		}
+	}
+
+	return variables
+}
+
+// authorize a variable group to be used in all pipelines
+func authorizeVariableGroupToAllPipelines(
+	ctx context.Context,
+	projectId string,
+	group *taskagent.VariableGroup,
+	connection *azuredevops.Connection) error {
+	buildClient, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	groupResource := "variablegroup"
+	groupAuthorized := true
+	groupId := strconv.Itoa(*group.Id)
+	resources := []build.DefinitionResourceReference{
+		{
+			Type:       &groupResource,
+			Authorized: &groupAuthorized,
+			Id:         &groupId,
+		}}
+
+	_, err = buildClient.AuthorizeProjectResources(ctx, build.AuthorizeProjectResourcesArgs{
+		Project:   &projectId,
+		Resources: &resources,
+	})
+	return err
+}
+
+// linkVariableGroup links the variable group to the pipeline, in place of the variable groups azd created for the other
+// azd environments, and removes the variables of the pipeline which would override the values of the group. The other
+// variable groups of the pipeline are kept, even when their names start with the prefix of the azd groups.
+func linkVariableGroup(definition *build.BuildDefinition, group *taskagent.VariableGroup) {
+	groups := []build.VariableGroup{
+		{
+			Id:   group.Id,
+			Name: group.Name,
+		},
+	}
+	if definition.VariableGroups != nil {
+		for _, linked := range *definition.VariableGroups {
+			if isAzdVariableGroup(linked) {
+				continue
+			}
+			groups = append(groups, linked)
+		}
+	}
+	definition.VariableGroups = &groups
+
+	if definition.Variables != nil {
+		for _, name := range variableGroupVariableNames {
+			delete(*definition.Variables, name)
+		}
+	}
+}
+
+// isAzdVariableGroup returns true when azd created the variable group for an azd environment, which is named after the
+// environment and has the description azd sets.
+func isAzdVariableGroup(group build.VariableGroup) bool {
+	if group.Name == nil || group.Description == nil || !strings.HasPrefix(*group.Name, VariableGroupNamePrefix) {
+		return false
+	}
+
+	envName := strings.TrimPrefix(*group.Name, VariableGroupNamePrefix)
+	return *group.Description == fmt.Sprintf(variableGroupDescription, envName)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azdo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/build"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/taskagent"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_variableGroupVariables(t *testing.T) {
+	t.Run("new group", func(t *testing.T) {
+		variables := variableGroupVariables(nil, map[string]string{"AZURE_ENV_NAME": "dev"})
+
+		require.Equal(t, map[string]interface{}{
+			"AZURE_ENV_NAME": map[string]interface{}{"value": "dev", "isSecret": false},
+		}, variables)
+	})
+
+	t.Run("keeps the other variables", func(t *testing.T) {
+		current := map[string]interface{}{
+			"AZURE_ENV_NAME": map[string]interface{}{"value": "dev", "isSecret": false},
+			"CUSTOM":         map[string]interface{}{"value": "custom", "isSecret": true},
+		}
+
+		variables := variableGroupVariables(&current, map[string]string{"AZURE_ENV_NAME": "prod"})
+
+		require.Equal(t, map[string]interface{}{
+			"AZURE_ENV_NAME": map[string]interface{}{"value": "prod", "isSecret": false},
+			"CUSTOM":         map[string]interface{}{"value": "custom", "isSecret": true},
+		}, variables)
+	})
+}
+
+func Test_linkVariableGroup(t *testing.T) {
+	groupId := 2
+	groupName := VariableGroupName("prod")
+	group := &taskagent.VariableGroup{Id: &groupId, Name: &groupName}
+
+	t.Run("new pipeline", func(t *testing.T) {
+		definition := &build.BuildDefinition{}
+
+		linkVariableGroup(definition, group)
+
+		require.Len(t, *definition.VariableGroups, 1)
+		require.Equal(t, groupId, *(*definition.VariableGroups)[0].Id)
+	})
+
+	t.Run("replaces the group of the previous environment", func(t *testing.T) {
+		previousId, previousName := 1, VariableGroupName("dev")
+		previousDescription := fmt.Sprintf(variableGroupDescription, "dev")
+		otherId, otherName := 3, "shared"
+		// A group of the user named like the groups of azd
+		userId, userName, userDescription := 4, VariableGroupName("secrets"), "Secrets of the team"
+		definition := &build.BuildDefinition{
+			VariableGroups: &[]build.VariableGroup{
+				{Id: &previousId, Name: &previousName, Description: &previousDescription},
+				{Id: &otherId, Name: &otherName},
+				{Id: &userId, Name: &userName, Description: &userDescription},
+			},
+			Variables: &map[string]build.BuildDefinitionVariable{
+				"AZURE_ENV_NAME":           createBuildDefinitionVariable("dev", false, false),
+				"AZURE_LOCATION":           createBuildDefinitionVariable("westus", false, false),
+				"AZURE_SUBSCRIPTION_ID":    createBuildDefinitionVariable("sub", false, false),
+				"AZURE_SERVICE_CONNECTION": createBuildDefinitionVariable(ServiceConnectionName, false, false),
+			},
+		}
+
+		linkVariableGroup(definition, group)
+
+		var linked []int
+		for _, variableGroup := range *definition.VariableGroups {
+			linked = append(linked, *variableGroup.Id)
+		}
+		require.Equal(t, []int{groupId, otherId, userId}, linked)
+		require.Len(t, *definition.Variables, 1)
+		require.Contains(t, *definition.Variables, "AZURE_SERVICE_CONNECTION")
+	})
+}
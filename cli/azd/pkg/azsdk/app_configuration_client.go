@@ -0,0 +1,190 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const appConfigurationApiVersion = "1.0"
+
+// AppConfigurationClient manages the key-values of an Azure App Configuration store through its data plane.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/azure/azure-app-configuration/rest-api-key-value
+type AppConfigurationClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// AppConfigurationKeyValue is a key-value of an App Configuration store. Feature flags are key-values with the
+// .appconfig.featureflag/ key prefix and the feature flag content type.
+type AppConfigurationKeyValue struct {
+	Key         string  `json:"key"`
+	Label       *string `json:"label"`
+	Value       string  `json:"value"`
+	ContentType string  `json:"content_type"`
+	Locked      bool    `json:"locked"`
+}
+
+type appConfigurationKeyValueRequest struct {
+	Value       string `json:"value"`
+	ContentType string `json:"content_type"`
+}
+
+type appConfigurationKeyValuesResponse struct {
+	Items    []*AppConfigurationKeyValue `json:"items"`
+	NextLink string                      `json:"@nextLink"`
+}
+
+// Creates a new AppConfigurationClient instance for the store endpoint, like https://contoso.azconfig.io
+func NewAppConfigurationClient(
+	endpoint string,
+	credential azcore.TokenCredential,
+	options *azcore.ClientOptions,
+) (*AppConfigurationClient, error) {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid app configuration endpoint '%s': %w", endpoint, err)
+	}
+
+	// The data plane authenticates with tokens issued for the store
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{endpoint + "/.default"}, nil)
+	pipeline := runtime.NewPipeline(
+		"app-configuration",
+		"1.0.0",
+		runtime.PipelineOptions{PerRetry: []policy.Policy{authPolicy}},
+		options,
+	)
+
+	return &AppConfigurationClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// KeyValues lists the key-values with the label, following the pages of the results. An empty label lists the
+// key-values without a label.
+func (c *AppConfigurationClient) KeyValues(ctx context.Context, label string) ([]*AppConfigurationKeyValue, error) {
+	keyValues := []*AppConfigurationKeyValue{}
+
+	requestUrl := c.endpoint + "/kv?" + c.query(label).Encode()
+	for requestUrl != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+		if err != nil {
+			return nil, fmt.Errorf("creating app configuration request: %w", err)
+		}
+
+		req.Raw().Header.Set("Accept", "application/vnd.microsoft.appconfig.kvset+json")
+
+		response, err := c.pipeline.Do(req)
+		if err != nil {
+			return nil, httputil.HandleRequestError(response, err)
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		page, err := httputil.ReadRawResponse[appConfigurationKeyValuesResponse](response)
+		if err != nil {
+			return nil, err
+		}
+
+		keyValues = append(keyValues, page.Items...)
+
+		requestUrl = ""
+		if page.NextLink != "" {
+			requestUrl = c.endpoint + page.NextLink
+		}
+	}
+
+	return keyValues, nil
+}
+
+// SetKeyValue creates or updates the key-value
+func (c *AppConfigurationClient) SetKeyValue(
+	ctx context.Context,
+	key string,
+	label string,
+	value string,
+	contentType string,
+) error {
+	req, err := c.keyValueRequest(ctx, http.MethodPut, key, label)
+	if err != nil {
+		return err
+	}
+
+	req.Raw().Header.Set("Accept", "application/vnd.microsoft.appconfig.kv+json")
+	body := appConfigurationKeyValueRequest{Value: value, ContentType: contentType}
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return fmt.Errorf("creating app configuration request: %w", err)
+	}
+	req.Raw().Header.Set("Content-Type", "application/vnd.microsoft.appconfig.kv+json")
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// DeleteKeyValue deletes the key-value, deleting a key-value which doesn't exist succeeds.
+func (c *AppConfigurationClient) DeleteKeyValue(ctx context.Context, key string, label string) error {
+	req, err := c.keyValueRequest(ctx, http.MethodDelete, key, label)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusNoContent) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (c *AppConfigurationClient) keyValueRequest(
+	ctx context.Context,
+	method string,
+	key string,
+	label string,
+) (*policy.Request, error) {
+	requestUrl := fmt.Sprintf("%s/kv/%s?%s", c.endpoint, url.PathEscape(key), c.query(label).Encode())
+	req, err := runtime.NewRequest(ctx, method, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating app configuration request: %w", err)
+	}
+
+	return req, nil
+}
+
+// query returns the query of key-value requests. The null label is selected with \0.
+func (c *AppConfigurationClient) query(label string) url.Values {
+	query := url.Values{}
+	query.Set("api-version", appConfigurationApiVersion)
+	if label == "" {
+		query.Set("label", "\x00")
+	} else {
+		query.Set("label", label)
+	}
+
+	return query
+}
@@ -2,8 +2,12 @@ package azsdk
 
 import "github.com/azure/azure-dev/cli/azd/pkg/httputil"
 
+// DefaultClientOptionsBuilder returns a builder for the options of the clients sending requests on behalf of azd: each
+// request is stamped with the azd user agent and the correlation ID of the invocation, and traced with `--trace-http`.
 func DefaultClientOptionsBuilder(httpClient httputil.HttpClient, userAgent string) *ClientOptionsBuilder {
 	return NewClientOptionsBuilder().
 		WithTransport(httpClient).
-		WithPerCallPolicy(NewUserAgentPolicy(userAgent))
+		WithPerCallPolicy(NewUserAgentPolicy(userAgent)).
+		WithPerCallPolicy(NewCorrelationPolicy()).
+		WithPerRetryPolicy(NewHttpTracePolicy())
 }
@@ -0,0 +1,349 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const containerInstanceApiVersion = "2023-05-01"
+
+// The states of a container
+const (
+	ContainerStateWaiting    = "Waiting"
+	ContainerStateRunning    = "Running"
+	ContainerStateTerminated = "Terminated"
+)
+
+// ContainerInstanceClient manages container groups of Azure Container Instances, running a single container until it
+// exits. More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/container-instances/container-groups
+type ContainerInstanceClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// ContainerGroup is a container group running a single container, which isn't restarted once it exits.
+type ContainerGroup struct {
+	Location string
+	Image    string
+	Command  []string
+	// The environment variables of the container, passed as secure values which aren't returned by the API
+	Environment map[string]string
+	Cpu         float64
+	MemoryInGB  float64
+	// The id of the user-assigned managed identity of the container group, if any
+	IdentityId string
+	// The id of the subnet the container group is deployed to, if any
+	SubnetId string
+}
+
+// ContainerState is the state of the container of a container group.
+type ContainerState struct {
+	// The provisioning state of the container group, like Succeeded or Failed
+	ProvisioningState string
+	// The state of the container, like Waiting, Running or Terminated
+	State    string
+	ExitCode int
+	// The reason of the last event of the container, like the failure to pull its image
+	Detail string
+}
+
+type containerGroupRequest struct {
+	Location   string                        `json:"location"`
+	Identity   *containerGroupIdentity       `json:"identity,omitempty"`
+	Properties containerGroupRequestProperty `json:"properties"`
+}
+
+type containerGroupIdentity struct {
+	Type                   string              `json:"type"`
+	UserAssignedIdentities map[string]struct{} `json:"userAssignedIdentities"`
+}
+
+type containerGroupRequestProperty struct {
+	OsType        string              `json:"osType"`
+	RestartPolicy string              `json:"restartPolicy"`
+	Containers    []containerRequest  `json:"containers"`
+	SubnetIds     []containerSubnetId `json:"subnetIds,omitempty"`
+}
+
+type containerSubnetId struct {
+	Id string `json:"id"`
+}
+
+type containerRequest struct {
+	Name       string                     `json:"name"`
+	Properties containerRequestProperties `json:"properties"`
+}
+
+type containerRequestProperties struct {
+	Image                string                `json:"image"`
+	Command              []string              `json:"command"`
+	EnvironmentVariables []containerEnvVar     `json:"environmentVariables"`
+	Resources            containerResourceSpec `json:"resources"`
+}
+
+type containerEnvVar struct {
+	Name        string `json:"name"`
+	SecureValue string `json:"secureValue"`
+}
+
+type containerResourceSpec struct {
+	Requests struct {
+		Cpu        float64 `json:"cpu"`
+		MemoryInGB float64 `json:"memoryInGB"`
+	} `json:"requests"`
+}
+
+type containerGroupResponse struct {
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+		Containers        []struct {
+			Properties struct {
+				InstanceView *struct {
+					CurrentState struct {
+						State        string `json:"state"`
+						ExitCode     *int   `json:"exitCode"`
+						DetailStatus string `json:"detailStatus"`
+					} `json:"currentState"`
+					Events []struct {
+						Message string `json:"message"`
+						Type    string `json:"type"`
+					} `json:"events"`
+				} `json:"instanceView"`
+			} `json:"properties"`
+		} `json:"containers"`
+	} `json:"properties"`
+}
+
+type containerLogsResponse struct {
+	Content string `json:"content"`
+}
+
+// Creates a new ContainerInstanceClient instance
+func NewContainerInstanceClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*ContainerInstanceClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	pipeline, err := armruntime.NewPipeline("container-instance", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if config, has := options.Cloud.Services[cloud.ResourceManager]; has && config.Endpoint != "" {
+		endpoint = config.Endpoint
+	}
+
+	return &ContainerInstanceClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// CreateOrUpdate creates the container group, named after its container, and starts its container. The container
+// group is created asynchronously, its state is read with GetState.
+func (c *ContainerInstanceClient) CreateOrUpdate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+	group ContainerGroup,
+) error {
+	requestUrl := c.containerGroupUrl(subscriptionId, resourceGroupName, containerGroupName) +
+		"?api-version=" + containerInstanceApiVersion
+	req, err := runtime.NewRequest(ctx, http.MethodPut, requestUrl)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	container := containerRequest{Name: containerGroupName}
+	container.Properties.Image = group.Image
+	container.Properties.Command = group.Command
+	container.Properties.EnvironmentVariables = []containerEnvVar{}
+	for name, value := range group.Environment {
+		container.Properties.EnvironmentVariables = append(
+			container.Properties.EnvironmentVariables, containerEnvVar{Name: name, SecureValue: value})
+	}
+	container.Properties.Resources.Requests.Cpu = group.Cpu
+	container.Properties.Resources.Requests.MemoryInGB = group.MemoryInGB
+
+	body := containerGroupRequest{
+		Location: group.Location,
+		Properties: containerGroupRequestProperty{
+			OsType:        "Linux",
+			RestartPolicy: "Never",
+			Containers:    []containerRequest{container},
+		},
+	}
+
+	if group.IdentityId != "" {
+		body.Identity = &containerGroupIdentity{
+			Type:                   "UserAssigned",
+			UserAssignedIdentities: map[string]struct{}{group.IdentityId: {}},
+		}
+	}
+
+	if group.SubnetId != "" {
+		body.Properties.SubnetIds = []containerSubnetId{{Id: group.SubnetId}}
+	}
+
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// GetState reads the state of the container of the container group.
+func (c *ContainerInstanceClient) GetState(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+) (*ContainerState, error) {
+	requestUrl := c.containerGroupUrl(subscriptionId, resourceGroupName, containerGroupName) +
+		"?api-version=" + containerInstanceApiVersion
+	req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var group containerGroupResponse
+	if err := runtime.UnmarshalAsJSON(response, &group); err != nil {
+		return nil, fmt.Errorf("reading container group '%s': %w", containerGroupName, err)
+	}
+
+	state := &ContainerState{
+		ProvisioningState: group.Properties.ProvisioningState,
+		State:             ContainerStateWaiting,
+	}
+
+	if len(group.Properties.Containers) == 0 || group.Properties.Containers[0].Properties.InstanceView == nil {
+		return state, nil
+	}
+
+	instanceView := group.Properties.Containers[0].Properties.InstanceView
+	if instanceView.CurrentState.State != "" {
+		state.State = instanceView.CurrentState.State
+	}
+
+	if instanceView.CurrentState.ExitCode != nil {
+		state.ExitCode = *instanceView.CurrentState.ExitCode
+	}
+
+	state.Detail = instanceView.CurrentState.DetailStatus
+	if len(instanceView.Events) > 0 {
+		state.Detail = instanceView.Events[len(instanceView.Events)-1].Message
+	}
+
+	return state, nil
+}
+
+// GetLogs reads the logs of the container of the container group, from the start of the container.
+func (c *ContainerInstanceClient) GetLogs(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+) (string, error) {
+	requestUrl := runtime.JoinPaths(
+		c.containerGroupUrl(subscriptionId, resourceGroupName, containerGroupName),
+		"containers",
+		url.PathEscape(containerGroupName),
+		"logs",
+	) + "?api-version=" + containerInstanceApiVersion
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return "", httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return "", runtime.NewResponseError(response)
+	}
+
+	var logs containerLogsResponse
+	if err := runtime.UnmarshalAsJSON(response, &logs); err != nil {
+		return "", fmt.Errorf("reading logs of container group '%s': %w", containerGroupName, err)
+	}
+
+	return logs.Content, nil
+}
+
+// Delete deletes the container group, the container group is removed asynchronously.
+func (c *ContainerInstanceClient) Delete(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+) error {
+	requestUrl := c.containerGroupUrl(subscriptionId, resourceGroupName, containerGroupName) +
+		"?api-version=" + containerInstanceApiVersion
+	req, err := runtime.NewRequest(ctx, http.MethodDelete, requestUrl)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusAccepted, http.StatusNoContent) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (c *ContainerInstanceClient) containerGroupUrl(
+	subscriptionId string,
+	resourceGroupName string,
+	containerGroupName string,
+) string {
+	return runtime.JoinPaths(
+		c.endpoint,
+		fmt.Sprintf(
+			"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerInstance/containerGroups/%s",
+			url.PathEscape(subscriptionId),
+			url.PathEscape(resourceGroupName),
+			url.PathEscape(containerGroupName),
+		),
+	)
+}
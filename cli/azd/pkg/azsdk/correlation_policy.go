@@ -0,0 +1,48 @@
+package azsdk
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/google/uuid"
+)
+
+// The header used by Azure services to correlate the requests of a client operation in their logs
+const CorrelationIdHeaderName = "x-ms-correlation-request-id"
+
+// The correlation ID of the azd invocation, shared by all the requests it sends
+var correlationId = uuid.NewString()
+
+// Whether a request was sent with the correlation ID, which is only worth reporting in that case
+var correlationIdSent atomic.Bool
+
+// CorrelationId returns the correlation ID stamped on the requests of the azd invocation. Support can find the requests
+// of a failed command in the logs of the Azure services with it.
+func CorrelationId() string {
+	return correlationId
+}
+
+// CorrelationIdSent returns whether a request was sent with the correlation ID during the azd invocation.
+func CorrelationIdSent() bool {
+	return correlationIdSent.Load()
+}
+
+type correlationPolicy struct {
+}
+
+// Policy to stamp all HTTP requests with the correlation ID of the azd invocation.
+func NewCorrelationPolicy() policy.Policy {
+	return &correlationPolicy{}
+}
+
+// Sets the correlation ID on the underlying request, unless the caller already set one
+func (p *correlationPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	if rawRequest.Header.Get(CorrelationIdHeaderName) == "" {
+		rawRequest.Header.Set(CorrelationIdHeaderName, correlationId)
+		correlationIdSent.Store(true)
+	}
+
+	return req.Next()
+}
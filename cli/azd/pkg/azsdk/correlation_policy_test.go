@@ -0,0 +1,52 @@
+package azsdk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelationPolicy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return true
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	clientOptions := DefaultClientOptionsBuilder(mockContext.HttpClient, "custom/agent (5.0.0)").BuildArmClientOptions()
+	client, err := armresources.NewClient("SUBSCRIPTION_ID", &mocks.MockCredentials{}, clientOptions)
+	require.NoError(t, err)
+
+	t.Run("InvocationId", func(t *testing.T) {
+		var response *http.Response
+		ctx := runtime.WithCaptureResponse(*mockContext.Context, &response)
+
+		_, _ = client.GetByID(ctx, "RESOURCE_ID", "", nil)
+
+		require.Equal(t, CorrelationId(), response.Request.Header.Get(CorrelationIdHeaderName))
+		require.Contains(t, response.Request.Header.Get("User-Agent"), "custom/agent (5.0.0)")
+		require.True(t, CorrelationIdSent())
+	})
+
+	t.Run("CallerId", func(t *testing.T) {
+		var response *http.Response
+		ctx := runtime.WithCaptureResponse(*mockContext.Context, &response)
+		ctx = policy.WithHTTPHeader(ctx, http.Header{CorrelationIdHeaderName: []string{"CALLER_ID"}})
+
+		_, _ = client.GetByID(ctx, "RESOURCE_ID", "", nil)
+
+		require.Equal(t, "CALLER_ID", response.Request.Header.Get(CorrelationIdHeaderName))
+	})
+
+	t.Run("SameIdForAllRequests", func(t *testing.T) {
+		require.NotEmpty(t, CorrelationId())
+		require.Equal(t, CorrelationId(), CorrelationId())
+	})
+}
@@ -0,0 +1,201 @@
+package azsdk
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// The scope of the tokens accepted by Azure Databricks, issued for the AzureDatabricks application
+const databricksScope = "2ff814a6-3304-4ab8-85cb-cd0e6f879c1d/.default"
+
+// DatabricksClient manages the workspace objects and the jobs of an Azure Databricks workspace through its REST API.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/azure/databricks/dev-tools/api/latest/workspace
+// https://learn.microsoft.com/en-us/azure/databricks/dev-tools/api/latest/jobs
+type DatabricksClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// DatabricksNotebook is a notebook imported into a Databricks workspace
+type DatabricksNotebook struct {
+	// The path of the notebook in the workspace, like /Shared/etl/ingest
+	Path string
+	// The format of the content, SOURCE or JUPYTER
+	Format string
+	// The language of SOURCE notebooks, PYTHON, SCALA, SQL or R
+	Language string
+	Content  []byte
+}
+
+type databricksImportRequest struct {
+	Path      string `json:"path"`
+	Format    string `json:"format"`
+	Language  string `json:"language,omitempty"`
+	Content   string `json:"content"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+type databricksJob struct {
+	JobId    int64 `json:"job_id"`
+	Settings struct {
+		Name string `json:"name"`
+	} `json:"settings"`
+}
+
+type databricksJobsResponse struct {
+	Jobs    []databricksJob `json:"jobs"`
+	HasMore bool            `json:"has_more"`
+}
+
+// Creates a new DatabricksClient instance for the url of the workspace, like adb-1234.5.azuredatabricks.net
+func NewDatabricksClient(
+	workspaceUrl string,
+	credential azcore.TokenCredential,
+	options *azcore.ClientOptions,
+) (*DatabricksClient, error) {
+	endpoint := strings.TrimSuffix(workspaceUrl, "/")
+	if !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + endpoint
+	}
+
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid Databricks workspace url '%s': %w", workspaceUrl, err)
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{databricksScope}, nil)
+	pipeline := runtime.NewPipeline(
+		"databricks",
+		"1.0.0",
+		runtime.PipelineOptions{PerRetry: []policy.Policy{authPolicy}},
+		options,
+	)
+
+	return &DatabricksClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// ImportNotebook imports the notebook, creating its folder and overwriting the notebook when it exists.
+func (c *DatabricksClient) ImportNotebook(ctx context.Context, notebook *DatabricksNotebook) error {
+	if err := c.post(ctx, "/api/2.0/workspace/mkdirs", map[string]string{"path": path.Dir(notebook.Path)}, nil); err != nil {
+		return fmt.Errorf("creating folder of notebook '%s': %w", notebook.Path, err)
+	}
+
+	body := databricksImportRequest{
+		Path:      notebook.Path,
+		Format:    notebook.Format,
+		Language:  notebook.Language,
+		Content:   base64.StdEncoding.EncodeToString(notebook.Content),
+		Overwrite: true,
+	}
+
+	if err := c.post(ctx, "/api/2.0/workspace/import", body, nil); err != nil {
+		return fmt.Errorf("importing notebook '%s': %w", notebook.Path, err)
+	}
+
+	return nil
+}
+
+// UpsertJob creates the job, or resets the settings of the job with the same name, and returns the id of the job.
+func (c *DatabricksClient) UpsertJob(ctx context.Context, settings map[string]any) (int64, error) {
+	name, _ := settings["name"].(string)
+	if name == "" {
+		return 0, fmt.Errorf("the job has no name")
+	}
+
+	jobId, err := c.findJob(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	if jobId != 0 {
+		body := map[string]any{"job_id": jobId, "new_settings": settings}
+		if err := c.post(ctx, "/api/2.1/jobs/reset", body, nil); err != nil {
+			return 0, fmt.Errorf("updating job '%s': %w", name, err)
+		}
+
+		return jobId, nil
+	}
+
+	var created struct {
+		JobId int64 `json:"job_id"`
+	}
+	if err := c.post(ctx, "/api/2.1/jobs/create", settings, &created); err != nil {
+		return 0, fmt.Errorf("creating job '%s': %w", name, err)
+	}
+
+	return created.JobId, nil
+}
+
+// findJob returns the id of the job with the name, or 0 when there is no such job.
+func (c *DatabricksClient) findJob(ctx context.Context, name string) (int64, error) {
+	query := url.Values{}
+	query.Set("name", name)
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, c.endpoint+"/api/2.1/jobs/list?"+query.Encode())
+	if err != nil {
+		return 0, fmt.Errorf("creating Databricks request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return 0, httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return 0, runtime.NewResponseError(response)
+	}
+
+	jobs, err := httputil.ReadRawResponse[databricksJobsResponse](response)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs.Jobs {
+		if job.Settings.Name == name {
+			return job.JobId, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// post posts the body to the API and reads the response into result, when not nil
+func (c *DatabricksClient) post(ctx context.Context, apiPath string, body any, result any) error {
+	req, err := runtime.NewRequest(ctx, http.MethodPost, c.endpoint+apiPath)
+	if err != nil {
+		return fmt.Errorf("creating Databricks request: %w", err)
+	}
+
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return fmt.Errorf("creating Databricks request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	if result != nil {
+		return runtime.UnmarshalAsJSON(response, result)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package azsdk
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// The value written in place of the secrets of a request
+const redactedValue = "REDACTED"
+
+// The query parameters written as is in the trace, the values of the other parameters may be secrets like SAS signatures
+// or function keys
+var tracedQueryParameters = map[string]bool{
+	"api-version": true,
+}
+
+// The headers written in the trace. Authorization is written redacted, to show that the request was authenticated.
+var tracedRequestHeaders = []string{
+	"Authorization",
+	userAgentHeaderName,
+	CorrelationIdHeaderName,
+	"Content-Type",
+	"Content-Length",
+}
+
+var tracedResponseHeaders = []string{
+	"x-ms-request-id",
+	CorrelationIdHeaderName,
+	"x-ms-routing-request-id",
+	"x-ms-failure-cause",
+	"Retry-After",
+	"Content-Type",
+	"Content-Length",
+}
+
+var (
+	httpTraceWriter io.Writer
+	httpTraceMutex  sync.Mutex
+)
+
+// EnableHttpTrace writes a summary of every HTTP request sent by azd, and of its response, to w. The summaries are meant
+// to be shared with support: the secrets in headers and query parameters are redacted, and bodies are never written.
+// It's enabled with `--trace-http`.
+func EnableHttpTrace(w io.Writer) {
+	httpTraceMutex.Lock()
+	defer httpTraceMutex.Unlock()
+
+	httpTraceWriter = w
+}
+
+type httpTracePolicy struct {
+}
+
+// Policy to trace each attempt of an HTTP request when tracing is enabled with EnableHttpTrace.
+func NewHttpTracePolicy() policy.Policy {
+	return &httpTracePolicy{}
+}
+
+// Writes the summary of the request, then of its response, once it completes
+func (p *httpTracePolicy) Do(req *policy.Request) (*http.Response, error) {
+	httpTraceMutex.Lock()
+	w := httpTraceWriter
+	httpTraceMutex.Unlock()
+
+	if w == nil {
+		return req.Next()
+	}
+
+	rawRequest := req.Raw()
+	requestUrl := redactUrl(rawRequest.URL)
+	writeHttpTrace(w, fmt.Sprintf("--> %s %s", rawRequest.Method, requestUrl), rawRequest.Header, tracedRequestHeaders)
+
+	start := time.Now()
+	response, err := req.Next()
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		writeHttpTrace(w, fmt.Sprintf("<-- %s %s (%s) failed: %v", rawRequest.Method, requestUrl, elapsed, err), nil, nil)
+	} else {
+		writeHttpTrace(
+			w,
+			fmt.Sprintf("<-- %d %s %s (%s)", response.StatusCode, rawRequest.Method, requestUrl, elapsed),
+			response.Header,
+			tracedResponseHeaders,
+		)
+	}
+
+	return response, err
+}
+
+// writeHttpTrace writes the summary line, followed by the traced headers, as a single write so the traces of concurrent
+// requests don't interleave.
+func writeHttpTrace(w io.Writer, summary string, header http.Header, headerNames []string) {
+	var sb strings.Builder
+	sb.WriteString(summary + "\n")
+	for _, name := range headerNames {
+		value := header.Get(name)
+		if value == "" {
+			continue
+		}
+
+		if strings.EqualFold(name, "Authorization") {
+			value = redactedValue
+		}
+
+		sb.WriteString(fmt.Sprintf("    %s: %s\n", name, value))
+	}
+
+	httpTraceMutex.Lock()
+	defer httpTraceMutex.Unlock()
+
+	_, _ = io.WriteString(w, sb.String())
+}
+
+// redactUrl returns the URL without its user info, and with the values of its query parameters redacted, apart from
+// tracedQueryParameters.
+func redactUrl(requestUrl *url.URL) string {
+	redacted := *requestUrl
+	redacted.User = nil
+
+	query := redacted.Query()
+	for key, values := range query {
+		if tracedQueryParameters[strings.ToLower(key)] {
+			continue
+		}
+
+		for i := range values {
+			values[i] = redactedValue
+		}
+	}
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
@@ -0,0 +1,156 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const (
+	iotHubApiVersion = "2021-04-12"
+	// The scope of the tokens accepted by the data plane of IoT hubs
+	iotHubScope = "https://iothubs.azure.net/.default"
+	// The maximum number of configurations of an IoT hub
+	iotHubMaxConfigurations = 100
+)
+
+// IotHubClient manages the configurations of an IoT hub through its data plane. IoT Edge deployments are
+// configurations with modules content, applied to the devices matching their target condition.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/iothub/service/configuration
+type IotHubClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// IotHubConfiguration is a configuration of an IoT hub, like an IoT Edge deployment.
+type IotHubConfiguration struct {
+	Id              string                     `json:"id"`
+	Content         IotHubConfigurationContent `json:"content"`
+	TargetCondition string                     `json:"targetCondition"`
+	Priority        int                        `json:"priority"`
+	Labels          map[string]string          `json:"labels,omitempty"`
+	ETag            string                     `json:"etag,omitempty"`
+}
+
+// IotHubConfigurationContent is the content applied by a configuration. IoT Edge deployments set the desired
+// properties of the modules of the devices, by module.
+type IotHubConfigurationContent struct {
+	ModulesContent map[string]any `json:"modulesContent,omitempty"`
+}
+
+// Creates a new IotHubClient instance for the host name of the hub, like contoso.azure-devices.net
+func NewIotHubClient(
+	hostName string,
+	credential azcore.TokenCredential,
+	options *azcore.ClientOptions,
+) (*IotHubClient, error) {
+	endpoint := "https://" + strings.TrimSuffix(hostName, "/")
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid IoT hub host name '%s': %w", hostName, err)
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{iotHubScope}, nil)
+	pipeline := runtime.NewPipeline(
+		"iot-hub",
+		"1.0.0",
+		runtime.PipelineOptions{PerRetry: []policy.Policy{authPolicy}},
+		options,
+	)
+
+	return &IotHubClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Configurations lists the configurations of the hub
+func (c *IotHubClient) Configurations(ctx context.Context) ([]*IotHubConfiguration, error) {
+	req, err := runtime.NewRequest(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s/configurations?top=%d&api-version=%s", c.endpoint, iotHubMaxConfigurations, iotHubApiVersion),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating IoT hub request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	configurations, err := httputil.ReadRawResponse[[]*IotHubConfiguration](response)
+	if err != nil {
+		return nil, err
+	}
+
+	return *configurations, nil
+}
+
+// CreateConfiguration creates the configuration. The content of configurations can't be updated once created.
+func (c *IotHubClient) CreateConfiguration(ctx context.Context, configuration *IotHubConfiguration) error {
+	req, err := c.configurationRequest(ctx, http.MethodPut, configuration.Id)
+	if err != nil {
+		return err
+	}
+
+	if err := runtime.MarshalAsJSON(req, configuration); err != nil {
+		return fmt.Errorf("creating IoT hub request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+// DeleteConfiguration deletes the configuration, deleting a configuration which doesn't exist succeeds.
+func (c *IotHubClient) DeleteConfiguration(ctx context.Context, id string) error {
+	req, err := c.configurationRequest(ctx, http.MethodDelete, id)
+	if err != nil {
+		return err
+	}
+
+	req.Raw().Header.Set("If-Match", "*")
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusNoContent, http.StatusNotFound) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (c *IotHubClient) configurationRequest(ctx context.Context, method string, id string) (*policy.Request, error) {
+	requestUrl := fmt.Sprintf("%s/configurations/%s?api-version=%s", c.endpoint, url.PathEscape(id), iotHubApiVersion)
+	req, err := runtime.NewRequest(ctx, method, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating IoT hub request: %w", err)
+	}
+
+	return req, nil
+}
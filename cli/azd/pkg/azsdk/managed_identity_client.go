@@ -0,0 +1,180 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const managedIdentityApiVersion = "2023-01-31"
+
+// The audience of the tokens exchanged for Azure AD tokens by workload identity federation
+const FederatedCredentialAudience = "api://AzureADTokenExchange"
+
+// ManagedIdentityClient reads user-assigned managed identities, and manages the federated identity credentials
+// trusting external identity providers, like the OIDC issuer of a Kubernetes cluster, to act as the identities.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/managedidentity/federated-identity-credentials
+type ManagedIdentityClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// ManagedIdentity is a user-assigned managed identity
+type ManagedIdentity struct {
+	Id          string
+	Name        string
+	ClientId    string
+	PrincipalId string
+	TenantId    string
+}
+
+// FederatedCredential is a federated identity credential of a user-assigned managed identity
+type FederatedCredential struct {
+	// The URL of the issuer of the tokens of the external identity, like the OIDC issuer URL of an AKS cluster
+	Issuer string
+	// The identifier of the external identity, like system:serviceaccount:<namespace>:<service-account>
+	Subject   string
+	Audiences []string
+}
+
+type managedIdentityResponse struct {
+	Id         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		ClientId    string `json:"clientId"`
+		PrincipalId string `json:"principalId"`
+		TenantId    string `json:"tenantId"`
+	} `json:"properties"`
+}
+
+type federatedCredentialRequest struct {
+	Properties struct {
+		Issuer    string   `json:"issuer"`
+		Subject   string   `json:"subject"`
+		Audiences []string `json:"audiences"`
+	} `json:"properties"`
+}
+
+// Creates a new ManagedIdentityClient instance
+func NewManagedIdentityClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*ManagedIdentityClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	pipeline, err := armruntime.NewPipeline("managed-identity", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if config, has := options.Cloud.Services[cloud.ResourceManager]; has && config.Endpoint != "" {
+		endpoint = config.Endpoint
+	}
+
+	return &ManagedIdentityClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Get reads the user-assigned managed identity of the resource group
+func (c *ManagedIdentityClient) Get(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+) (*ManagedIdentity, error) {
+	requestUrl := c.identityUrl(subscriptionId, resourceGroupName, identityName) +
+		"?api-version=" + managedIdentityApiVersion
+	req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var identity managedIdentityResponse
+	if err := runtime.UnmarshalAsJSON(response, &identity); err != nil {
+		return nil, fmt.Errorf("reading managed identity '%s': %w", identityName, err)
+	}
+
+	return &ManagedIdentity{
+		Id:          identity.Id,
+		Name:        identity.Name,
+		ClientId:    identity.Properties.ClientId,
+		PrincipalId: identity.Properties.PrincipalId,
+		TenantId:    identity.Properties.TenantId,
+	}, nil
+}
+
+// CreateOrUpdateFederatedCredential creates the federated identity credential of the user-assigned managed identity,
+// or updates it when it already exists.
+func (c *ManagedIdentityClient) CreateOrUpdateFederatedCredential(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+	credentialName string,
+	credential FederatedCredential,
+) error {
+	requestUrl := runtime.JoinPaths(
+		c.identityUrl(subscriptionId, resourceGroupName, identityName),
+		"federatedIdentityCredentials",
+		url.PathEscape(credentialName),
+	) + "?api-version=" + managedIdentityApiVersion
+
+	req, err := runtime.NewRequest(ctx, http.MethodPut, requestUrl)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	var body federatedCredentialRequest
+	body.Properties.Issuer = credential.Issuer
+	body.Properties.Subject = credential.Subject
+	body.Properties.Audiences = credential.Audiences
+	if err := runtime.MarshalAsJSON(req, body); err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated) {
+		return runtime.NewResponseError(response)
+	}
+
+	return nil
+}
+
+func (c *ManagedIdentityClient) identityUrl(subscriptionId string, resourceGroupName string, identityName string) string {
+	return runtime.JoinPaths(
+		c.endpoint,
+		fmt.Sprintf(
+			"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ManagedIdentity/userAssignedIdentities/%s",
+			url.PathEscape(subscriptionId),
+			url.PathEscape(resourceGroupName),
+			url.PathEscape(identityName),
+		),
+	)
+}
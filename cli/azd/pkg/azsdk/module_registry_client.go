@@ -0,0 +1,208 @@
+package azsdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// The scope of the tokens exchanged for registry tokens by Azure Container Registry
+const containerRegistryScope = "https://containerregistry.azure.net/.default"
+
+const (
+	ociManifestMediaType      = "application/vnd.oci.image.manifest.v1+json"
+	bicepModuleLayerMediaType = "application/vnd.ms.bicep.module.layer.v1+json"
+)
+
+// ModuleRegistryClient pulls Bicep modules from an Azure Container Registry, with a registry token exchanged for the
+// token of the current Azure principal.
+// More info can be found at the following:
+// https://github.com/Azure/acr/blob/main/docs/AAD-OAuth.md
+type ModuleRegistryClient struct {
+	endpoint   string
+	service    string
+	credential azcore.TokenCredential
+	pipeline   runtime.Pipeline
+}
+
+// BicepModule is a Bicep module pulled from a registry
+type BicepModule struct {
+	// The digest of the manifest of the module, like sha256:0123...
+	ManifestDigest string
+	Manifest       []byte
+	// The ARM template the module was compiled to
+	Template []byte
+}
+
+type ociManifest struct {
+	Layers []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"layers"`
+}
+
+// Creates a new ModuleRegistryClient instance for the login server of the registry, like contoso.azurecr.io
+func NewModuleRegistryClient(
+	loginServer string,
+	credential azcore.TokenCredential,
+	options *azcore.ClientOptions,
+) (*ModuleRegistryClient, error) {
+	service := strings.TrimSuffix(strings.TrimPrefix(loginServer, "https://"), "/")
+	endpoint := "https://" + service
+
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid registry login server '%s': %w", loginServer, err)
+	}
+
+	return &ModuleRegistryClient{
+		endpoint:   endpoint,
+		service:    service,
+		credential: credential,
+		pipeline:   runtime.NewPipeline("module-registry", "1.0.0", runtime.PipelineOptions{}, options),
+	}, nil
+}
+
+// Pull downloads the module of the repository at a tag, like v1, or at a digest, like sha256:0123...
+func (c *ModuleRegistryClient) Pull(ctx context.Context, repository string, reference string) (*BicepModule, error) {
+	token, err := c.accessToken(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("authenticating to registry '%s': %w", c.service, err)
+	}
+
+	manifest, digest, err := c.get(ctx, fmt.Sprintf("/v2/%s/manifests/%s", repository, reference), token)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest of module '%s:%s': %w", repository, reference, err)
+	}
+
+	var parsed ociManifest
+	if err := json.Unmarshal(manifest, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing manifest of module '%s:%s': %w", repository, reference, err)
+	}
+
+	layerDigest := ""
+	for _, layer := range parsed.Layers {
+		if layer.MediaType == bicepModuleLayerMediaType {
+			layerDigest = layer.Digest
+			break
+		}
+	}
+
+	if layerDigest == "" {
+		return nil, fmt.Errorf("'%s:%s' is not a Bicep module", repository, reference)
+	}
+
+	template, _, err := c.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", repository, layerDigest), token)
+	if err != nil {
+		return nil, fmt.Errorf("reading module '%s:%s': %w", repository, reference, err)
+	}
+
+	return &BicepModule{
+		ManifestDigest: digest,
+		Manifest:       manifest,
+		Template:       template,
+	}, nil
+}
+
+// accessToken exchanges the token of the principal for a registry token allowed to pull from the repository.
+func (c *ModuleRegistryClient) accessToken(ctx context.Context, repository string) (string, error) {
+	aadToken, err := c.credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{containerRegistryScope}})
+	if err != nil {
+		return "", err
+	}
+
+	var exchanged struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := c.postForm(ctx, "/oauth2/exchange", url.Values{
+		"grant_type":   {"access_token"},
+		"service":      {c.service},
+		"access_token": {aadToken.Token},
+	}, &exchanged); err != nil {
+		return "", err
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := c.postForm(ctx, "/oauth2/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"service":       {c.service},
+		"scope":         {fmt.Sprintf("repository:%s:pull", repository)},
+		"refresh_token": {exchanged.RefreshToken},
+	}, &token); err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
+
+// postForm posts the form to the registry and reads the response into result
+func (c *ModuleRegistryClient) postForm(ctx context.Context, apiPath string, form url.Values, result any) error {
+	req, err := runtime.NewRequest(ctx, http.MethodPost, c.endpoint+apiPath)
+	if err != nil {
+		return fmt.Errorf("creating registry request: %w", err)
+	}
+
+	body := streaming.NopCloser(strings.NewReader(form.Encode()))
+	if err := req.SetBody(body, "application/x-www-form-urlencoded"); err != nil {
+		return fmt.Errorf("creating registry request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return runtime.NewResponseError(response)
+	}
+
+	return runtime.UnmarshalAsJSON(response, result)
+}
+
+// get reads the content of a manifest or of a blob, and returns it with its digest.
+func (c *ModuleRegistryClient) get(ctx context.Context, apiPath string, token string) ([]byte, string, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, c.endpoint+apiPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating registry request: %w", err)
+	}
+
+	req.Raw().Header.Set("Authorization", "Bearer "+token)
+	req.Raw().Header.Set("Accept", ociManifestMediaType)
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, "", httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, "", runtime.NewResponseError(response)
+	}
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading registry response: %w", err)
+	}
+
+	digest := response.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		sum := sha256.Sum256(content)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	return content, digest, nil
+}
@@ -0,0 +1,99 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const permissionsApiVersion = "2022-04-01"
+
+// PermissionsClient reads the permissions the signed-in principal has at a scope, granted by all its role
+// assignments at the scope or above. More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/authorization/permissions
+type PermissionsClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// Permission is the set of actions allowed by a role assignment, minus the actions it excludes.
+// Actions may contain wildcards, like Microsoft.Web/* or */read.
+type Permission struct {
+	Actions    []string `json:"actions"`
+	NotActions []string `json:"notActions"`
+}
+
+type permissionListResult struct {
+	Value    []Permission `json:"value"`
+	NextLink string       `json:"nextLink"`
+}
+
+// Creates a new PermissionsClient instance
+func NewPermissionsClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*PermissionsClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	pipeline, err := armruntime.NewPipeline("permissions", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if config, has := options.Cloud.Services[cloud.ResourceManager]; has && config.Endpoint != "" {
+		endpoint = config.Endpoint
+	}
+
+	return &PermissionsClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// List returns the permissions of the signed-in principal at the scope, like /subscriptions/<id> or the id of a
+// resource.
+func (c *PermissionsClient) List(ctx context.Context, scope string) ([]Permission, error) {
+	requestUrl := runtime.JoinPaths(
+		c.endpoint,
+		strings.TrimSuffix(scope, "/"),
+		"providers/Microsoft.Authorization/permissions",
+	) + "?api-version=" + permissionsApiVersion
+
+	permissions := []Permission{}
+	for requestUrl != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		response, err := c.pipeline.Do(req)
+		if err != nil {
+			return nil, httputil.HandleRequestError(response, err)
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		var result permissionListResult
+		if err := runtime.UnmarshalAsJSON(response, &result); err != nil {
+			return nil, fmt.Errorf("reading permissions at scope '%s': %w", scope, err)
+		}
+
+		permissions = append(permissions, result.Value...)
+		requestUrl = result.NextLink
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,290 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// The API versions of the usages of the resource providers which report regional quotas
+var usagesApiVersions = map[string]string{
+	"Microsoft.Compute": "2023-03-01",
+	"Microsoft.App":     "2023-05-01",
+	"Microsoft.Network": "2022-07-01",
+}
+
+const computeSkusApiVersion = "2021-07-01"
+const webGeoRegionsApiVersion = "2022-03-01"
+
+// QuotaClient reads the regional quotas of a subscription and the regional availability of SKUs, which tell before
+// a deployment whether the resources of a template fit in a region.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/compute/usage/list
+// https://learn.microsoft.com/en-us/rest/api/compute/resource-skus/list
+type QuotaClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// QuotaUsage is the current usage and the limit of a quota of a region, like the cores of a VM family.
+type QuotaUsage struct {
+	// The name of the quota, like cores or standardDSv3Family
+	Name string
+	// The localized name of the quota, like Standard DSv3 Family vCPUs
+	DisplayName  string
+	CurrentValue int64
+	Limit        int64
+	Unit         string
+}
+
+// ComputeSku is a size of virtual machine offered in a region.
+type ComputeSku struct {
+	// The name of the size, like Standard_D2s_v3
+	Name string
+	// The family of the size, which is the name of the quota of its cores, like standardDSv3Family
+	Family string
+	// The number of cores of the size
+	VCpus int64
+	// Whether the size can't be deployed by the subscription in the region
+	Restricted bool
+}
+
+type usageListResponse struct {
+	Value []struct {
+		Name struct {
+			Value          string `json:"value"`
+			LocalizedValue string `json:"localizedValue"`
+		} `json:"name"`
+		CurrentValue int64  `json:"currentValue"`
+		Limit        int64  `json:"limit"`
+		Unit         string `json:"unit"`
+	} `json:"value"`
+	NextLink *string `json:"nextLink"`
+}
+
+type computeSkuListResponse struct {
+	Value []struct {
+		ResourceType string `json:"resourceType"`
+		Name         string `json:"name"`
+		Family       string `json:"family"`
+		Capabilities []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"capabilities"`
+		Restrictions []struct {
+			Type       string `json:"type"`
+			ReasonCode string `json:"reasonCode"`
+		} `json:"restrictions"`
+	} `json:"value"`
+	NextLink *string `json:"nextLink"`
+}
+
+type geoRegionListResponse struct {
+	Value []struct {
+		Name string `json:"name"`
+	} `json:"value"`
+	NextLink *string `json:"nextLink"`
+}
+
+// Creates a new QuotaClient instance
+func NewQuotaClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*QuotaClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	pipeline, err := armruntime.NewPipeline("quota", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if config, has := options.Cloud.Services[cloud.ResourceManager]; has && config.Endpoint != "" {
+		endpoint = config.Endpoint
+	}
+
+	return &QuotaClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Usages lists the quotas of a resource provider in a region, like Microsoft.Compute or Microsoft.App.
+func (c *QuotaClient) Usages(
+	ctx context.Context,
+	subscriptionId string,
+	providerNamespace string,
+	location string,
+) ([]*QuotaUsage, error) {
+	apiVersion, has := usagesApiVersions[providerNamespace]
+	if !has {
+		return nil, fmt.Errorf("reading the usages of %s isn't supported", providerNamespace)
+	}
+
+	usages := []*QuotaUsage{}
+	err := c.listPages(
+		ctx,
+		fmt.Sprintf(
+			"/subscriptions/%s/providers/%s/locations/%s/usages",
+			url.PathEscape(subscriptionId),
+			providerNamespace,
+			url.PathEscape(location),
+		),
+		url.Values{"api-version": []string{apiVersion}},
+		func(response *http.Response) (*string, error) {
+			var page usageListResponse
+			if err := runtime.UnmarshalAsJSON(response, &page); err != nil {
+				return nil, err
+			}
+
+			for _, usage := range page.Value {
+				usages = append(usages, &QuotaUsage{
+					Name:         usage.Name.Value,
+					DisplayName:  usage.Name.LocalizedValue,
+					CurrentValue: usage.CurrentValue,
+					Limit:        usage.Limit,
+					Unit:         usage.Unit,
+				})
+			}
+
+			return page.NextLink, nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing usages of %s in %s: %w", providerNamespace, location, err)
+	}
+
+	return usages, nil
+}
+
+// ComputeSkus lists the sizes of virtual machines offered in a region.
+func (c *QuotaClient) ComputeSkus(ctx context.Context, subscriptionId string, location string) ([]*ComputeSku, error) {
+	skus := []*ComputeSku{}
+	err := c.listPages(
+		ctx,
+		fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Compute/skus", url.PathEscape(subscriptionId)),
+		url.Values{
+			"api-version": []string{computeSkusApiVersion},
+			"$filter":     []string{fmt.Sprintf("location eq '%s'", location)},
+		},
+		func(response *http.Response) (*string, error) {
+			var page computeSkuListResponse
+			if err := runtime.UnmarshalAsJSON(response, &page); err != nil {
+				return nil, err
+			}
+
+			for _, sku := range page.Value {
+				if sku.ResourceType != "virtualMachines" {
+					continue
+				}
+
+				computeSku := &ComputeSku{
+					Name:   sku.Name,
+					Family: sku.Family,
+				}
+
+				for _, capability := range sku.Capabilities {
+					if capability.Name == "vCPUs" {
+						computeSku.VCpus, _ = strconv.ParseInt(capability.Value, 10, 64)
+					}
+				}
+
+				for _, restriction := range sku.Restrictions {
+					if restriction.Type == "Location" {
+						computeSku.Restricted = true
+					}
+				}
+
+				skus = append(skus, computeSku)
+			}
+
+			return page.NextLink, nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing compute skus in %s: %w", location, err)
+	}
+
+	return skus, nil
+}
+
+// AppServiceRegions lists the display names of the regions offering a pricing tier of App Service plans, like
+// PremiumV3.
+func (c *QuotaClient) AppServiceRegions(ctx context.Context, subscriptionId string, tier string) ([]string, error) {
+	regions := []string{}
+	err := c.listPages(
+		ctx,
+		fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Web/geoRegions", url.PathEscape(subscriptionId)),
+		url.Values{
+			"api-version": []string{webGeoRegionsApiVersion},
+			"sku":         []string{tier},
+		},
+		func(response *http.Response) (*string, error) {
+			var page geoRegionListResponse
+			if err := runtime.UnmarshalAsJSON(response, &page); err != nil {
+				return nil, err
+			}
+
+			for _, region := range page.Value {
+				regions = append(regions, region.Name)
+			}
+
+			return page.NextLink, nil
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("listing regions of the %s App Service tier: %w", tier, err)
+	}
+
+	return regions, nil
+}
+
+// listPages gets the pages of a list operation, following the next links returned by readPage.
+func (c *QuotaClient) listPages(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	readPage func(response *http.Response) (*string, error),
+) error {
+	requestUrl := runtime.JoinPaths(c.endpoint, path) + "?" + query.Encode()
+
+	for {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+
+		req.Raw().Header.Set("Accept", "application/json")
+
+		response, err := c.pipeline.Do(req)
+		if err != nil {
+			return httputil.HandleRequestError(response, err)
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return runtime.NewResponseError(response)
+		}
+
+		nextLink, err := readPage(response)
+		if err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+
+		if nextLink == nil || *nextLink == "" {
+			return nil
+		}
+
+		requestUrl = *nextLink
+	}
+}
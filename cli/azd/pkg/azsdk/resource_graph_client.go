@@ -0,0 +1,136 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const resourceGraphApiVersion = "2021-03-01"
+
+// ResourceGraphClient queries Azure Resource Graph, which finds resources across the resource groups of a
+// subscription with a single request.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/azureresourcegraph/resourcegraph/resources/resources
+type ResourceGraphClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// ResourceGraphResource is a row of a Resource Graph query projecting the properties of a resource.
+type ResourceGraphResource struct {
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Location string            `json:"location"`
+	Tags     map[string]string `json:"tags"`
+}
+
+type resourceGraphRequest struct {
+	Subscriptions []string                    `json:"subscriptions"`
+	Query         string                      `json:"query"`
+	Options       resourceGraphRequestOptions `json:"options"`
+}
+
+type resourceGraphRequestOptions struct {
+	ResultFormat string  `json:"resultFormat"`
+	SkipToken    *string `json:"$skipToken,omitempty"`
+}
+
+type resourceGraphResponse struct {
+	Data      []*ResourceGraphResource `json:"data"`
+	SkipToken *string                  `json:"$skipToken"`
+}
+
+// Creates a new ResourceGraphClient instance
+func NewResourceGraphClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*ResourceGraphClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	// We do not have a Resource provider to register
+	options.DisableRPRegistration = true
+
+	pipeline, err := armruntime.NewPipeline("resource-graph", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if config, has := options.Cloud.Services[cloud.ResourceManager]; has && config.Endpoint != "" {
+		endpoint = config.Endpoint
+	}
+
+	return &ResourceGraphClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// Resources runs the query against the resources of the subscription, following the pages of the results.
+func (c *ResourceGraphClient) Resources(
+	ctx context.Context,
+	subscriptionId string,
+	query string,
+) ([]*ResourceGraphResource, error) {
+	resources := []*ResourceGraphResource{}
+	body := resourceGraphRequest{
+		Subscriptions: []string{subscriptionId},
+		Query:         query,
+		Options: resourceGraphRequestOptions{
+			ResultFormat: "objectArray",
+		},
+	}
+
+	for {
+		req, err := runtime.NewRequest(
+			ctx,
+			http.MethodPost,
+			runtime.JoinPaths(c.endpoint, "/providers/Microsoft.ResourceGraph/resources"),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating resource graph request: %w", err)
+		}
+
+		reqQuery := req.Raw().URL.Query()
+		reqQuery.Set("api-version", resourceGraphApiVersion)
+		req.Raw().URL.RawQuery = reqQuery.Encode()
+		req.Raw().Header.Set("Accept", "application/json")
+
+		if err := runtime.MarshalAsJSON(req, body); err != nil {
+			return nil, fmt.Errorf("creating resource graph request: %w", err)
+		}
+
+		response, err := c.pipeline.Do(req)
+		if err != nil {
+			return nil, httputil.HandleRequestError(response, err)
+		}
+
+		if !runtime.HasStatusCode(response, http.StatusOK) {
+			return nil, runtime.NewResponseError(response)
+		}
+
+		var page resourceGraphResponse
+		if err := runtime.UnmarshalAsJSON(response, &page); err != nil {
+			return nil, fmt.Errorf("reading resource graph response: %w", err)
+		}
+
+		resources = append(resources, page.Data...)
+
+		if page.SkipToken == nil || *page.SkipToken == "" {
+			return resources, nil
+		}
+
+		body.Options.SkipToken = page.SkipToken
+	}
+}
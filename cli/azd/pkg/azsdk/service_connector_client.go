@@ -0,0 +1,187 @@
+package azsdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const serviceLinkerApiVersion = "2022-05-01"
+
+// The authentication types of the connections
+const (
+	ServiceConnectionAuthSystemIdentity = "systemAssignedIdentity"
+	ServiceConnectionAuthSecret         = "secret"
+)
+
+// ServiceConnectorClient manages the connections of Service Connector between a compute resource, like an App Service
+// or a Container App, and the resources it uses, like a storage account or a database. Service Connector configures
+// the authentication of the connection, and sets the configuration of the connection in the app settings of the
+// compute resource.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/servicelinker/linker
+type ServiceConnectorClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+// ServiceConnection is a connection from a compute resource to a target resource.
+type ServiceConnection struct {
+	// The id of the target resource, like the blob service of a storage account
+	TargetId string
+	// The authentication of the connection, like systemAssignedIdentity or secret
+	AuthType string
+	// The client library connecting, like python or dotnet, which determines the names of the configuration values
+	ClientType string
+	// The container connecting, for Container Apps
+	Scope string
+}
+
+// ServiceConnectionConfiguration is a configuration value of a connection, like the endpoint of the target resource.
+type ServiceConnectionConfiguration struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type linkerResource struct {
+	Properties linkerProperties `json:"properties"`
+}
+
+type linkerProperties struct {
+	TargetService linkerTargetService `json:"targetService"`
+	AuthInfo      linkerAuthInfo      `json:"authInfo"`
+	ClientType    string              `json:"clientType,omitempty"`
+	Scope         string              `json:"scope,omitempty"`
+}
+
+type linkerTargetService struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+type linkerAuthInfo struct {
+	AuthType string `json:"authType"`
+}
+
+type linkerConfigurationsResponse struct {
+	Configurations []ServiceConnectionConfiguration `json:"configurations"`
+}
+
+// Creates a new ServiceConnectorClient instance
+func NewServiceConnectorClient(
+	credential azcore.TokenCredential,
+	options *arm.ClientOptions,
+) (*ServiceConnectorClient, error) {
+	if options == nil {
+		options = &arm.ClientOptions{}
+	}
+
+	pipeline, err := armruntime.NewPipeline("servicelinker", "1.0.0", credential, runtime.PipelineOptions{}, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating HTTP pipeline: %w", err)
+	}
+
+	endpoint := cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint
+	if config, has := options.Cloud.Services[cloud.ResourceManager]; has && config.Endpoint != "" {
+		endpoint = config.Endpoint
+	}
+
+	return &ServiceConnectorClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// CreateOrUpdate creates or updates the connection of the source resource with the name, and waits for Service
+// Connector to configure it.
+func (c *ServiceConnectorClient) CreateOrUpdate(
+	ctx context.Context,
+	sourceResourceId string,
+	name string,
+	connection ServiceConnection,
+) error {
+	req, err := runtime.NewRequest(ctx, http.MethodPut, c.linkerUrl(sourceResourceId, name, ""))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	err = runtime.MarshalAsJSON(req, linkerResource{
+		Properties: linkerProperties{
+			TargetService: linkerTargetService{Type: "AzureResource", Id: connection.TargetId},
+			AuthInfo:      linkerAuthInfo{AuthType: connection.AuthType},
+			ClientType:    connection.ClientType,
+			Scope:         connection.Scope,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	poller, err := runtime.NewPoller[any](response, c.pipeline, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("waiting for connection %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ListConfigurations lists the configuration values Service Connector sets for the connection, like
+// AZURE_STORAGEBLOB_RESOURCEENDPOINT.
+func (c *ServiceConnectorClient) ListConfigurations(
+	ctx context.Context,
+	sourceResourceId string,
+	name string,
+) ([]ServiceConnectionConfiguration, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodPost, c.linkerUrl(sourceResourceId, name, "listConfigurations"))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Raw().Header.Set("Accept", "application/json")
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	var configurations linkerConfigurationsResponse
+	if err := runtime.UnmarshalAsJSON(response, &configurations); err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	return configurations.Configurations, nil
+}
+
+func (c *ServiceConnectorClient) linkerUrl(sourceResourceId string, name string, action string) string {
+	path := runtime.JoinPaths(c.endpoint, sourceResourceId, "providers/Microsoft.ServiceLinker/linkers", url.PathEscape(name))
+	if action != "" {
+		path = runtime.JoinPaths(path, action)
+	}
+
+	return path + "?" + url.Values{"api-version": []string{serviceLinkerApiVersion}}.Encode()
+}
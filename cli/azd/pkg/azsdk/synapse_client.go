@@ -0,0 +1,107 @@
+package azsdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const (
+	synapseApiVersion = "2020-12-01"
+	// The scope of the tokens accepted by the development endpoint of Synapse workspaces
+	synapseScope = "https://dev.azuresynapse.net/.default"
+)
+
+// The kinds of the artifacts of Synapse workspaces
+const (
+	SynapseNotebooks           = "notebooks"
+	SynapsePipelines           = "pipelines"
+	SynapseSparkJobDefinitions = "sparkJobDefinitions"
+)
+
+// SynapseClient manages the artifacts of a Synapse workspace, like notebooks and pipelines, through its development
+// endpoint.
+// More info can be found at the following:
+// https://learn.microsoft.com/en-us/rest/api/synapse/data-plane/notebook
+// https://learn.microsoft.com/en-us/rest/api/synapse/data-plane/pipeline
+type SynapseClient struct {
+	endpoint string
+	pipeline runtime.Pipeline
+}
+
+type synapseArtifact struct {
+	Name       string          `json:"name"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+// Creates a new SynapseClient instance for the development endpoint of the workspace, like
+// https://contoso.dev.azuresynapse.net
+func NewSynapseClient(
+	endpoint string,
+	credential azcore.TokenCredential,
+	options *azcore.ClientOptions,
+) (*SynapseClient, error) {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if _, err := url.ParseRequestURI(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid Synapse development endpoint '%s': %w", endpoint, err)
+	}
+
+	authPolicy := runtime.NewBearerTokenPolicy(credential, []string{synapseScope}, nil)
+	pipeline := runtime.NewPipeline(
+		"synapse",
+		"1.0.0",
+		runtime.PipelineOptions{PerRetry: []policy.Policy{authPolicy}},
+		options,
+	)
+
+	return &SynapseClient{
+		endpoint: endpoint,
+		pipeline: pipeline,
+	}, nil
+}
+
+// PutArtifact creates or updates an artifact of the kind, like notebooks, and waits for the operation to complete.
+func (c *SynapseClient) PutArtifact(ctx context.Context, kind string, name string, properties json.RawMessage) error {
+	requestUrl := fmt.Sprintf("%s/%s/%s?api-version=%s", c.endpoint, kind, url.PathEscape(name), synapseApiVersion)
+	req, err := runtime.NewRequest(ctx, http.MethodPut, requestUrl)
+	if err != nil {
+		return fmt.Errorf("creating Synapse request: %w", err)
+	}
+
+	if err := runtime.MarshalAsJSON(req, synapseArtifact{Name: name, Properties: properties}); err != nil {
+		return fmt.Errorf("creating Synapse request: %w", err)
+	}
+
+	response, err := c.pipeline.Do(req)
+	if err != nil {
+		return httputil.HandleRequestError(response, err)
+	}
+
+	if !runtime.HasStatusCode(response, http.StatusOK, http.StatusCreated, http.StatusAccepted) {
+		return runtime.NewResponseError(response)
+	}
+
+	if response.StatusCode != http.StatusAccepted {
+		response.Body.Close()
+		return nil
+	}
+
+	poller, err := runtime.NewPoller[any](response, c.pipeline, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package azsdk
+
+import (
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry/events"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry/fields"
+)
+
+type telemetryPolicy struct {
+}
+
+// Policy to track each call to an Azure API with a telemetry span, retries are part of the same span.
+func NewTelemetryPolicy() policy.Policy {
+	return &telemetryPolicy{}
+}
+
+// Wraps the remaining pipeline in a span. Only the method, host and status code are recorded, the path of the request
+// contains resource identifiers and is not recorded.
+func (p *telemetryPolicy) Do(req *policy.Request) (*http.Response, error) {
+	rawRequest := req.Raw()
+	_, span := telemetry.GetTracer().Start(rawRequest.Context(), events.AzureRequestEvent)
+	span.SetAttributes(
+		fields.HttpMethodKey.String(rawRequest.Method),
+		fields.HttpHostKey.String(rawRequest.URL.Hostname()),
+	)
+
+	response, err := req.Next()
+	if response != nil {
+		span.SetAttributes(fields.HttpStatusCodeKey.Int(response.StatusCode))
+	}
+	span.EndWithStatus(err)
+
+	return response, err
+}
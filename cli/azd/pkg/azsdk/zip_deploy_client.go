@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,11 +15,23 @@ import (
 	armruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 )
 
 const (
 	deployStatusInterval = 10 * time.Second
+	// The maximum number of lines of the deployment log included in the error of a failed deployment
+	deployLogExcerptLines = 20
+)
+
+// The statuses of a Kudu deployment
+const (
+	DeployStatusPending   = 0
+	DeployStatusBuilding  = 1
+	DeployStatusDeploying = 2
+	DeployStatusFailed    = 3
+	DeployStatusSuccess   = 4
 )
 
 // ZipDeployClient wraps usage of app service zip deploy used for application deployments
@@ -52,6 +66,40 @@ type DeployStatus struct {
 	SiteName     string     `json:"site_name"`
 }
 
+// DeployLogEntry is an entry of the log of a Kudu deployment
+type DeployLogEntry struct {
+	LogTime    *time.Time `json:"log_time"`
+	Id         string     `json:"id"`
+	Message    string     `json:"message"`
+	Type       int        `json:"type"`
+	DetailsUrl string     `json:"details_url"`
+}
+
+// DeployFailedError is returned when the remote build or deployment of the package failed after the upload was
+// accepted.
+type DeployFailedError struct {
+	Status DeployStatus
+	// The last lines of the deployment log, empty when the log couldn't be fetched
+	Log []string
+}
+
+func (e *DeployFailedError) Error() string {
+	message := fmt.Sprintf("deployment %s failed", e.Status.Id)
+	if e.Status.StatusText != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Status.StatusText)
+	}
+
+	if len(e.Log) == 0 {
+		if e.Status.LogUrl != "" {
+			message = fmt.Sprintf("%s, the deployment log is available at %s", message, e.Status.LogUrl)
+		}
+
+		return message
+	}
+
+	return fmt.Sprintf("%s\n\nDeployment log:\n  %s", message, strings.Join(e.Log, "\n  "))
+}
+
 // Creates a new ZipDeployClient instance
 func NewZipDeployClient(
 	subscriptionId string,
@@ -88,11 +136,50 @@ func (c *ZipDeployClient) BeginDeploy(
 	appName string,
 	zipFile io.Reader,
 ) (*runtime.Poller[*DeployResponse], error) {
-	request, err := c.createDeployRequest(ctx, appName, zipFile)
+	endpoint := fmt.Sprintf("https://%s.scm.azurewebsites.net/api/zipdeploy", appName)
+	request, err := c.createDeployRequest(ctx, endpoint, zipFile, "application/octet-stream")
 	if err != nil {
 		return nil, err
 	}
 
+	query := request.Raw().URL.Query()
+	query.Set("isAsync", "true")
+	request.Raw().URL.RawQuery = query.Encode()
+
+	return c.beginDeployment(request, "")
+}
+
+// Begins a deployment with the one deploy API of the apps hosted on the Flex Consumption plan of Azure Functions, and
+// returns a poller to check for status. Unlike zip deploy, which reads the SCM_DO_BUILD_DURING_DEPLOYMENT app setting,
+// remoteBuild selects whether the package is built by the platform.
+func (c *ZipDeployClient) BeginPublish(
+	ctx context.Context,
+	appName string,
+	zipFile io.Reader,
+	remoteBuild bool,
+) (*runtime.Poller[*DeployResponse], error) {
+	endpoint := fmt.Sprintf("https://%s.scm.azurewebsites.net/api/publish", appName)
+	request, err := c.createDeployRequest(ctx, endpoint, zipFile, "application/zip")
+	if err != nil {
+		return nil, err
+	}
+
+	query := request.Raw().URL.Query()
+	query.Set("RemoteBuild", strconv.FormatBool(remoteBuild))
+	query.Set("Deployer", "azd")
+	request.Raw().URL.RawQuery = query.Encode()
+
+	// The status of the latest deployment is polled when the response doesn't locate the deployment
+	latest := fmt.Sprintf("https://%s.scm.azurewebsites.net/api/deployments/latest", appName)
+	return c.beginDeployment(request, latest)
+}
+
+// beginDeployment sends the request starting a deployment and returns a poller polling the location of the response,
+// or defaultLocation when the response has no location.
+func (c *ZipDeployClient) beginDeployment(
+	request *policy.Request,
+	defaultLocation string,
+) (*runtime.Poller[*DeployResponse], error) {
 	response, err := c.pipeline.Do(request)
 	if err != nil {
 		return nil, httputil.HandleRequestError(response, err)
@@ -104,11 +191,16 @@ func (c *ZipDeployClient) BeginDeploy(
 		return nil, runtime.NewResponseError(response)
 	}
 
+	location := response.Header.Get("Location")
+	if strings.TrimSpace(location) == "" {
+		location = defaultLocation
+	}
+
 	var finalResponse *DeployResponse
 
 	pollerOptions := &runtime.NewPollerOptions[*DeployResponse]{
 		Response: &finalResponse,
-		Handler:  newDeployPollingHandler(c.pipeline, response),
+		Handler:  newDeployPollingHandler(c.pipeline, location),
 	}
 
 	return runtime.NewPoller(response, c.pipeline, pollerOptions)
@@ -131,25 +223,53 @@ func (c *ZipDeployClient) Deploy(ctx context.Context, appName string, zipFile io
 	return response, nil
 }
 
-// Creates the HTTP request for the zip deployment operation
-func (c *ZipDeployClient) createDeployRequest(
+// Deploys the specified application zip with the one deploy API and waits for completion
+func (c *ZipDeployClient) Publish(
 	ctx context.Context,
 	appName string,
 	zipFile io.Reader,
+	remoteBuild bool,
+) (*DeployResponse, error) {
+	poller, err := c.BeginPublish(ctx, appName, zipFile, remoteBuild)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := poller.PollUntilDone(ctx, &runtime.PollUntilDoneOptions{
+		Frequency: deployStatusInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Creates the HTTP request uploading the package of a deployment operation
+func (c *ZipDeployClient) createDeployRequest(
+	ctx context.Context,
+	endpoint string,
+	zipFile io.Reader,
+	contentType string,
 ) (*policy.Request, error) {
-	endpoint := fmt.Sprintf("https://%s.scm.azurewebsites.net/api/zipdeploy", appName)
 	req, err := runtime.NewRequest(ctx, http.MethodPost, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("creating deploy request: %w", err)
 	}
 
+	// A seekable package is rewound when the request is retried, so an interrupted upload is sent again in full
+	if seeker, ok := zipFile.(io.ReadSeeker); ok {
+		if err := req.SetBody(streaming.NopCloser(seeker), contentType); err != nil {
+			return nil, fmt.Errorf("setting deploy request body: %w", err)
+		}
+	}
+
 	rawRequest := req.Raw()
-	rawRequest.Body = io.NopCloser(zipFile)
-	query := rawRequest.URL.Query()
-	query.Set("isAsync", "true")
-	rawRequest.Header.Set("Content-Type", "application/octet-stream")
+	if rawRequest.Body == nil {
+		rawRequest.Body = io.NopCloser(zipFile)
+	}
+	rawRequest.Header.Set("Content-Type", contentType)
 	rawRequest.Header.Set("Accept", "application/json")
-	rawRequest.URL.RawQuery = query.Encode()
 
 	return req, nil
 }
@@ -157,14 +277,14 @@ func (c *ZipDeployClient) createDeployRequest(
 // Implementation of a Go SDK polling handler for async zip deploy operations
 type deployPollingHandler struct {
 	pipeline runtime.Pipeline
-	response *http.Response
+	location string
 	result   *DeployStatusResponse
 }
 
-func newDeployPollingHandler(pipeline runtime.Pipeline, response *http.Response) *deployPollingHandler {
+func newDeployPollingHandler(pipeline runtime.Pipeline, location string) *deployPollingHandler {
 	return &deployPollingHandler{
 		pipeline: pipeline,
-		response: response,
+		location: location,
 	}
 }
 
@@ -175,12 +295,11 @@ func (h *deployPollingHandler) Done() bool {
 
 // Executing the polling logic to check the status of the deploy operation
 func (h *deployPollingHandler) Poll(ctx context.Context) (*http.Response, error) {
-	location := h.response.Header.Get("Location")
-	if strings.TrimSpace(location) == "" {
+	if h.location == "" {
 		return nil, fmt.Errorf("missing polling location header")
 	}
 
-	req, err := runtime.NewRequest(ctx, http.MethodGet, location)
+	req, err := runtime.NewRequest(ctx, http.MethodGet, h.location)
 	if err != nil {
 		return nil, err
 	}
@@ -212,9 +331,78 @@ func (h *deployPollingHandler) Poll(ctx context.Context) (*http.Response, error)
 
 // Gets the result of the deploy operation
 func (h *deployPollingHandler) Result(ctx context.Context, out **DeployResponse) error {
+	if h.result.Status == DeployStatusFailed {
+		return &DeployFailedError{
+			Status: h.result.DeployStatus,
+			Log:    h.logExcerpt(ctx, h.result.LogUrl),
+		}
+	}
+
 	*out = &DeployResponse{
 		DeployStatus: h.result.DeployStatus,
 	}
 
 	return nil
 }
+
+// logExcerpt returns the last lines of the deployment log, including the details of the entries, like the output of the
+// remote build. Failing to fetch the log isn't an error, the failure of the deployment is still reported.
+func (h *deployPollingHandler) logExcerpt(ctx context.Context, logUrl string) []string {
+	if logUrl == "" {
+		return nil
+	}
+
+	entries, err := h.logEntries(ctx, logUrl)
+	if err != nil {
+		log.Printf("failed fetching deployment log: %v", err)
+		return nil
+	}
+
+	lines := []string{}
+	for _, entry := range entries {
+		lines = append(lines, entry.Message)
+		if entry.DetailsUrl == "" {
+			continue
+		}
+
+		details, err := h.logEntries(ctx, entry.DetailsUrl)
+		if err != nil {
+			log.Printf("failed fetching deployment log details: %v", err)
+			continue
+		}
+
+		for _, detail := range details {
+			lines = append(lines, detail.Message)
+		}
+	}
+
+	if len(lines) > deployLogExcerptLines {
+		lines = lines[len(lines)-deployLogExcerptLines:]
+	}
+
+	return lines
+}
+
+func (h *deployPollingHandler) logEntries(ctx context.Context, url string) ([]DeployLogEntry, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, url)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.pipeline.Do(req)
+	if err != nil {
+		return nil, httputil.HandleRequestError(response, err)
+	}
+	defer response.Body.Close()
+
+	if !runtime.HasStatusCode(response, http.StatusOK) {
+		return nil, runtime.NewResponseError(response)
+	}
+
+	entries, err := httputil.ReadRawResponse[[]DeployLogEntry](response)
+	if err != nil {
+		return nil, err
+	}
+
+	return *entries, nil
+}
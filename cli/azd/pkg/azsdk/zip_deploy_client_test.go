@@ -3,6 +3,8 @@ package azsdk
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"testing"
@@ -65,6 +67,65 @@ func TestZipDeploy(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("WithFailedDeployment", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerDeployMocks(mockContext)
+		registerFailedDeploymentMocks(mockContext)
+
+		options := NewClientOptionsBuilder().
+			WithTransport(mockContext.HttpClient).
+			BuildArmClientOptions()
+
+		client, err := NewZipDeployClient("SUBSCRIPTION_ID", &mocks.MockCredentials{}, options)
+		require.NoError(t, err)
+
+		poller, err := client.BeginDeploy(*mockContext.Context, "APP_NAME", bytes.NewBuffer([]byte{}))
+		require.NoError(t, err)
+
+		response, err := poller.PollUntilDone(*mockContext.Context, &runtime.PollUntilDoneOptions{
+			Frequency: 250 * time.Millisecond,
+		})
+		require.Nil(t, response)
+
+		var deployErr *DeployFailedError
+		require.True(t, errors.As(err, &deployErr))
+		require.Equal(t, []string{"Updating submodules.", "Running oryx build...", "npm ERR! Missing script: build"},
+			deployErr.Log)
+		require.ErrorContains(t, err, "npm ERR! Missing script: build")
+	})
+
+	t.Run("WithSeekableZipFile", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "/api/zipdeploy")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			// Seekable packages can be rewound by the retry policy
+			require.NotNil(t, request.GetBody)
+			require.Equal(t, int64(7), request.ContentLength)
+
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.Equal(t, "PACKAGE", string(body))
+
+			response, _ := mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+			response.Header.Set("Location", "http://myapp.scm.azurewebsites.net/deployments/latest")
+
+			return response, nil
+		})
+		registerPollingMocks(mockContext)
+
+		options := NewClientOptionsBuilder().
+			WithTransport(mockContext.HttpClient).
+			BuildArmClientOptions()
+
+		client, err := NewZipDeployClient("SUBSCRIPTION_ID", &mocks.MockCredentials{}, options)
+		require.NoError(t, err)
+
+		poller, err := client.BeginDeploy(*mockContext.Context, "APP_NAME", bytes.NewReader([]byte("PACKAGE")))
+		require.NotNil(t, poller)
+		require.NoError(t, err)
+	})
+
 	t.Run("WithInitialError", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		registerConflictMocks(mockContext)
@@ -83,6 +144,37 @@ func TestZipDeploy(t *testing.T) {
 	})
 }
 
+func TestPublish(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.Contains(request.URL.Path, "/api/publish")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		require.Equal(t, "APP_NAME.scm.azurewebsites.net", request.URL.Host)
+		require.Equal(t, "true", request.URL.Query().Get("RemoteBuild"))
+		require.Equal(t, "application/zip", request.Header.Get("Content-Type"))
+
+		// The one deploy API doesn't locate the deployment, the latest deployment is polled
+		return mocks.CreateEmptyHttpResponse(request, http.StatusAccepted)
+	})
+	registerPollingMocks(mockContext)
+
+	options := NewClientOptionsBuilder().
+		WithTransport(mockContext.HttpClient).
+		BuildArmClientOptions()
+
+	client, err := NewZipDeployClient("SUBSCRIPTION_ID", &mocks.MockCredentials{}, options)
+	require.NoError(t, err)
+
+	poller, err := client.BeginPublish(*mockContext.Context, "APP_NAME", bytes.NewReader([]byte("PACKAGE")), true)
+	require.NoError(t, err)
+
+	response, err := poller.PollUntilDone(*mockContext.Context, &runtime.PollUntilDoneOptions{
+		Frequency: 250 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.True(t, response.Complete)
+}
+
 func registerConflictMocks(mockContext *mocks.MockContext) {
 	// Original call to start the deployment operation
 	mockContext.HttpClient.When(func(request *http.Request) bool {
@@ -154,6 +246,44 @@ func registerPollingMocks(mockContext *mocks.MockContext) {
 
 }
 
+func registerFailedDeploymentMocks(mockContext *mocks.MockContext) {
+	logUrl := "https://myapp.scm.azurewebsites.net/api/deployments/ID/log"
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.Contains(request.URL.Path, "/deployments/latest")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		failedStatus := DeployStatusResponse{
+			DeployStatus: DeployStatus{
+				Id:         "ID",
+				Status:     DeployStatusFailed,
+				StatusText: "Build failed",
+				Complete:   true,
+				SiteName:   "APP_NAME",
+				LogUrl:     logUrl,
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, failedStatus)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/deployments/ID/log")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, []DeployLogEntry{
+			{Id: "1", Message: "Updating submodules."},
+			{Id: "2", Message: "Running oryx build...", DetailsUrl: logUrl + "/2"},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/deployments/ID/log/2")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, []DeployLogEntry{
+			{Id: "3", Message: "npm ERR! Missing script: build"},
+		})
+	})
+}
+
 func registerPollingErrorMocks(mockContext *mocks.MockContext) {
 	// Polling call to check on the deployment status
 	mockContext.HttpClient.When(func(request *http.Request) bool {
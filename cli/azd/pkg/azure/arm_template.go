@@ -5,6 +5,7 @@ package azure
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 // RawArmTemplate is a JSON encoded ARM template.
@@ -36,7 +37,7 @@ type ArmTemplateParameterDefinition struct {
 }
 
 func (d *ArmTemplateParameterDefinition) Secure() bool {
-	return d.Type == "secureObject" || d.Type == "secureString"
+	return strings.EqualFold(d.Type, "secureObject") || strings.EqualFold(d.Type, "secureString")
 }
 
 type AzdMetadata struct {
@@ -100,6 +100,39 @@ func StaticWebAppRID(subscriptionId, resourceGroupName, staticSiteName string) s
 	return returnValue
 }
 
+func ApimApiRID(subscriptionId, resourceGroupName, serviceName, apiId string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.ApiManagement/service/%s/apis/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		serviceName,
+		apiId,
+	)
+}
+
+func IotHubRID(subscriptionId, resourceGroupName, hubName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.Devices/IotHubs/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		hubName,
+	)
+}
+
+func DatabricksWorkspaceRID(subscriptionId, resourceGroupName, workspaceName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.Databricks/workspaces/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		workspaceName,
+	)
+}
+
+func SynapseWorkspaceRID(subscriptionId, resourceGroupName, workspaceName string) string {
+	return fmt.Sprintf(
+		"%s/providers/Microsoft.Synapse/workspaces/%s",
+		ResourceGroupRID(subscriptionId, resourceGroupName),
+		workspaceName,
+	)
+}
+
 var resourceIdRegex = regexp.MustCompile("/.+/(?i)resourceGroups/(.+?)/.+")
 
 // Find the resource group name from the resource id
@@ -589,6 +589,14 @@ func (p *AzdoCiProvider) preConfigureCheck(
 		)
 	}
 
+	prValidation := PrValidationMode(pipelineManagerArgs.PrValidation)
+	if prValidation != "" && prValidation != PrValidationNone {
+		return false, fmt.Errorf(
+			"the pull request validation pipeline is only generated for GitHub, remove the %s flag",
+			output.WithBackticks("--pr-validation"),
+		)
+	}
+
 	_, updatedPat, err := azdo.EnsurePatExists(ctx, p.Env, p.console)
 	if err != nil {
 		return updatedPat, err
@@ -677,6 +685,11 @@ func (p *AzdoCiProvider) configurePipeline(
 	if err != nil {
 		return nil, err
 	}
+	variableGroup, err := azdo.EnsureVariableGroup(
+		ctx, connection, details.projectId, p.Env, p.credentials.SubscriptionId, p.console)
+	if err != nil {
+		return nil, err
+	}
 	buildDefinition, err := azdo.CreatePipeline(
 		ctx,
 		details.projectId,
@@ -687,6 +700,7 @@ func (p *AzdoCiProvider) configurePipeline(
 		p.Env,
 		p.console,
 		provisioningProvider,
+		variableGroup,
 	)
 	if err != nil {
 		return nil, err
@@ -701,3 +715,37 @@ func (p *AzdoCiProvider) configurePipeline(
 		remote: pipelineUrl,
 	}, nil
 }
+
+// setValues sets the secrets and the variables of the pipeline created by `azd pipeline config`, which must exist.
+func (p *AzdoCiProvider) setValues(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	values []PipelineValue,
+	dryRun bool,
+) error {
+	details := repoDetails.details.(*AzdoRepositoryDetails)
+
+	org, _, err := azdo.EnsureOrgNameExists(ctx, p.Env, p.console)
+	if err != nil {
+		return err
+	}
+	pat, _, err := azdo.EnsurePatExists(ctx, p.Env, p.console)
+	if err != nil {
+		return err
+	}
+	connection, err := azdo.GetConnection(ctx, org, pat)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		return azdo.EnsurePipelineExists(ctx, connection, details.projectId, details.repoName)
+	}
+
+	variables := map[string]azdo.PipelineVariable{}
+	for _, value := range values {
+		variables[value.Name] = azdo.PipelineVariable{Value: value.Value, Secret: value.Secret}
+	}
+
+	return azdo.SetPipelineVariables(ctx, connection, details.projectId, details.repoName, variables)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// The repository of the official azd container image, built from cli/Dockerfile and tagged with the version of each
+// azd release.
+const azdContainerImageRepository = "mcr.microsoft.com/azure-dev-cli-apps"
+
+var imageDigestRegexp = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// azdContainerImage returns the image the generated workflow runs azd in, empty when the pipeline doesn't run in a
+// container. It is the configured image, or else the official image of the running version of azd, so the runs of the
+// pipeline use the azd the pipeline was configured with.
+func azdContainerImage(options *project.PipelineContainerOptions) (string, error) {
+	if options == nil {
+		return "", nil
+	}
+
+	image := options.Image
+	if image == "" {
+		if internal.IsDevVersion() {
+			return "", errors.New(
+				"the azd container image is only published for released versions of azd, set pipeline.container.image " +
+					"in azure.yaml to an image pinned with a version tag or a digest")
+		}
+
+		image = fmt.Sprintf("%s:%s", azdContainerImageRepository, internal.VersionInfo().Version.String())
+	}
+
+	if err := verifyPinnedImage(image); err != nil {
+		return "", err
+	}
+
+	return image, nil
+}
+
+// verifyPinnedImage returns an error when the image reference doesn't pin a single version of the image, with a digest
+// like name@sha256:<digest>, or a tag other than latest like name:1.2.0.
+func verifyPinnedImage(image string) error {
+	name, digest, hasDigest := strings.Cut(image, "@")
+	if name == "" || strings.ContainsAny(image, " \t") {
+		return fmt.Errorf("'%s' isn't a valid container image reference", image)
+	}
+
+	if hasDigest {
+		if !imageDigestRegexp.MatchString(digest) {
+			return fmt.Errorf("the digest of the container image '%s' must be sha256: followed by 64 hex characters", image)
+		}
+
+		return nil
+	}
+
+	// The tag follows the last colon after the last slash, a colon before it separates the port of the registry
+	tag := ""
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		tag = name[colon+1:]
+	}
+
+	if tag == "" || tag == "latest" {
+		return fmt.Errorf(
+			"the container image '%s' isn't pinned, use a version tag or a digest so every run of the pipeline uses the "+
+				"same version of azd and of its tools", image)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_verifyPinnedImage(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a1", 32)
+
+	tests := []struct {
+		image  string
+		pinned bool
+	}{
+		{"mcr.microsoft.com/azure-dev-cli-apps:1.5.0", true},
+		{"mcr.microsoft.com/azure-dev-cli-apps@" + digest, true},
+		{"localhost:5000/azd:1.5.0", true},
+		{"mcr.microsoft.com/azure-dev-cli-apps", false},
+		{"mcr.microsoft.com/azure-dev-cli-apps:latest", false},
+		{"localhost:5000/azd", false},
+		{"mcr.microsoft.com/azure-dev-cli-apps@sha256:abc", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			err := verifyPinnedImage(test.image)
+			if test.pinned {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func Test_azdContainerImage(t *testing.T) {
+	image, err := azdContainerImage(nil)
+	require.NoError(t, err)
+	require.Empty(t, image)
+
+	image, err = azdContainerImage(&project.PipelineContainerOptions{Image: "contoso.azurecr.io/azd:1.5.0"})
+	require.NoError(t, err)
+	require.Equal(t, "contoso.azurecr.io/azd:1.5.0", image)
+
+	_, err = azdContainerImage(&project.PipelineContainerOptions{Image: "contoso.azurecr.io/azd:latest"})
+	require.Error(t, err)
+
+	// Tests run a dev build of azd, which has no published image
+	_, err = azdContainerImage(&project.PipelineContainerOptions{})
+	require.Error(t, err)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// workflowFile is the subset of a GitHub Actions workflow read by azd.
+type workflowFile struct {
+	Jobs map[string]struct {
+		Environment workflowEnvironment `yaml:"environment"`
+	} `yaml:"jobs"`
+}
+
+// workflowEnvironment is the environment a job deploys to, set either as a name or as a name and a url.
+type workflowEnvironment struct {
+	Name string
+}
+
+func (e *workflowEnvironment) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		e.Name = value.Value
+		return nil
+	}
+
+	var env struct {
+		Name string `yaml:"name"`
+	}
+	if err := value.Decode(&env); err != nil {
+		return err
+	}
+
+	e.Name = env.Name
+	return nil
+}
+
+// workflowEnvironments returns the names of the environments the jobs of the workflows of the project deploy to, like
+// the stages of a workflow deploying to a dev environment, and then to a prod environment. Environments named by
+// expressions are only known when the workflows run, and are ignored.
+func workflowEnvironments(projectDir string) ([]string, error) {
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(projectDir, githubFolder, "workflows", pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	var environments []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading workflow %s: %w", file, err)
+		}
+
+		var workflow workflowFile
+		if err := yaml.Unmarshal(content, &workflow); err != nil {
+			return nil, fmt.Errorf("parsing workflow %s: %w", file, err)
+		}
+
+		for _, job := range workflow.Jobs {
+			name := job.Environment.Name
+			if name != "" && !strings.Contains(name, "${{") && !slices.Contains(environments, name) {
+				environments = append(environments, name)
+			}
+		}
+	}
+
+	return environments, nil
+}
+
+// gitHubEnvironments returns the sorted names of the GitHub environments of the project, the environments of its
+// workflows and the stages and environments configured in azure.yaml, with the protection rules configured in
+// azure.yaml.
+func (p *GitHubCiProvider) gitHubEnvironments(
+	ctx context.Context,
+) ([]string, map[string]*project.PipelineEnvironmentOptions, error) {
+	if p.azdContext == nil {
+		return nil, nil, nil
+	}
+
+	names, err := workflowEnvironments(p.azdContext.ProjectDirectory())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prj, err := project.Load(ctx, p.azdContext.ProjectPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading project: %w", err)
+	}
+
+	for _, stage := range prj.Pipeline.Stages {
+		if !slices.Contains(names, stage) {
+			names = append(names, stage)
+		}
+	}
+
+	for name := range prj.Pipeline.Environments {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	return names, prj.Pipeline.Environments, nil
+}
+
+// configureEnvironments creates the GitHub environments of the project with their protection rules, and scopes the
+// values of the azd environments with the same names to them, so that each stage of a workflow deploys to its own azd
+// environment.
+func (p *GitHubCiProvider) configureEnvironments(ctx context.Context, repoSlug string) error {
+	names, options, err := p.gitHubEnvironments(ctx)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		protection, err := environmentProtection(ctx, ghCli, options[name])
+		if err != nil {
+			return fmt.Errorf("resolving the protection rules of environment %s: %w", name, err)
+		}
+
+		if err := ghCli.CreateOrUpdateEnvironment(ctx, repoSlug, name, protection); err != nil {
+			return err
+		}
+		p.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: fmt.Sprintf("Creating %s environment", name),
+		})
+
+		azdEnvironment, err := environment.GetEnvironment(p.azdContext, name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("loading environment %s: %w", name, err)
+		}
+
+		for _, key := range []string{
+			environment.EnvNameEnvVarName,
+			environment.LocationEnvVarName,
+			environment.SubscriptionIdEnvVarName} {
+
+			value := azdEnvironment.Values[key]
+			if value == "" {
+				p.console.MessageUxItem(ctx, &ux.WarningMessage{
+					Description: fmt.Sprintf(
+						"Skipping the %s secret of %s environment, the azd environment %s has no value for it",
+						key, name, name),
+				})
+				continue
+			}
+
+			if err := ghCli.SetEnvironmentSecret(ctx, repoSlug, name, key, value); err != nil {
+				return fmt.Errorf("failed setting %s secret of environment %s: %w", key, name, err)
+			}
+			p.console.MessageUxItem(ctx, &ux.DoneMessage{
+				Message: fmt.Sprintf("Setting %s secret of %s environment", key, name),
+			})
+		}
+	}
+
+	return nil
+}
+
+// environmentProtection resolves the reviewers of the options, users or teams as org/team, to their ids.
+func environmentProtection(
+	ctx context.Context,
+	ghCli github.GitHubCli,
+	options *project.PipelineEnvironmentOptions,
+) (github.EnvironmentProtection, error) {
+	if options == nil {
+		return github.EnvironmentProtection{}, nil
+	}
+
+	protection := github.EnvironmentProtection{
+		WaitTimer: options.WaitTimer,
+	}
+
+	for _, reviewer := range options.Reviewers {
+		if org, team, isTeam := strings.Cut(reviewer, "/"); isTeam {
+			id, err := ghCli.GetTeamId(ctx, org, team)
+			if err != nil {
+				return github.EnvironmentProtection{}, err
+			}
+			protection.Reviewers = append(protection.Reviewers, github.EnvironmentReviewer{Type: "Team", Id: id})
+		} else {
+			id, err := ghCli.GetUserId(ctx, reviewer)
+			if err != nil {
+				return github.EnvironmentProtection{}, err
+			}
+			protection.Reviewers = append(protection.Reviewers, github.EnvironmentReviewer{Type: "User", Id: id})
+		}
+	}
+
+	return protection, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_workflowEnvironments(t *testing.T) {
+	projectDir := t.TempDir()
+	workflowsDir := filepath.Join(projectDir, githubFolder, "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, osutil.PermissionDirectory))
+
+	workflows := map[string]string{
+		"azure-dev.yml": `
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+  deploy-dev:
+    runs-on: ubuntu-latest
+    environment: dev
+  deploy-prod:
+    runs-on: ubuntu-latest
+    environment:
+      name: prod
+      url: https://contoso.com
+`,
+		"preview.yaml": `
+on: pull_request
+jobs:
+  deploy:
+    environment: ${{ github.head_ref }}
+  smoke-test:
+    environment: dev
+`,
+	}
+	for name, content := range workflows {
+		require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, name), []byte(content), osutil.PermissionFile))
+	}
+
+	environments, err := workflowEnvironments(projectDir)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"dev", "prod"}, environments)
+}
+
+func Test_workflowEnvironments_NoWorkflows(t *testing.T) {
+	environments, err := workflowEnvironments(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, environments)
+}
+
+func Test_federatedCredentialSafeName(t *testing.T) {
+	require.Equal(t, "prod-west_2", federatedCredentialSafeName("prod west_2"))
+}
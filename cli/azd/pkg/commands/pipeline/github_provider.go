@@ -10,14 +10,17 @@ import (
 	"fmt"
 	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	githubRemote "github.com/azure/azure-dev/cli/azd/pkg/github"
 	"github.com/azure/azure-dev/cli/azd/pkg/graphsdk"
@@ -26,6 +29,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
@@ -38,21 +42,33 @@ type GitHubScmProvider struct {
 	newGitHubRepoCreated bool
 	commandRunner        exec.CommandRunner
 	console              input.Console
+	// The GitHub host of the repository, github.com or a GitHub Enterprise Server instance
+	hostname string
 }
 
-func NewGitHubScmProvider(commandRunner exec.CommandRunner, console input.Console) *GitHubScmProvider {
+func NewGitHubScmProvider(
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	hostname string,
+) *GitHubScmProvider {
 	return &GitHubScmProvider{
 		commandRunner: commandRunner,
 		console:       console,
+		hostname:      hostname,
 	}
 }
 
+// host returns the GitHub host of the repository, github.com when it isn't set
+func (p *GitHubScmProvider) host() string {
+	return gitHubHostOrDefault(p.hostname)
+}
+
 // ***  subareaProvider implementation ******
 
 // requiredTools return the list of external tools required by
 // GitHub provider during its execution.
 func (p *GitHubScmProvider) requiredTools(ctx context.Context) ([]tools.ExternalTool, error) {
-	ghCli, err := github.NewGitHubCli(ctx, p.console, p.commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
 	if err != nil {
 		return nil, err
 	}
@@ -67,12 +83,12 @@ func (p *GitHubScmProvider) preConfigureCheck(
 	infraOptions provisioning.Options,
 	projectPath string,
 ) (bool, error) {
-	ghCli, err := github.NewGitHubCli(ctx, p.console, p.commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
 	gitCli := git.NewGitCli(p.commandRunner)
 	if err != nil {
 		return false, err
 	}
-	return ensureGitHubLogin(ctx, projectPath, ghCli, gitCli, github.GitHubHostName, p.console)
+	return ensureGitHubLogin(ctx, projectPath, ghCli, gitCli, p.host(), p.console)
 }
 
 // name returns the name of the provider
@@ -108,7 +124,7 @@ func (p *GitHubScmProvider) configureGitRemote(
 	}
 
 	var remoteUrl string
-	ghCli, err := github.NewGitHubCli(ctx, p.console, p.commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
 	if err != nil {
 		return "", err
 	}
@@ -129,7 +145,7 @@ func (p *GitHubScmProvider) configureGitRemote(
 		p.newGitHubRepoCreated = true
 	// Enter a URL directly.
 	case 2:
-		remoteUrl, err = getRemoteUrlFromPrompt(ctx, remoteName, p.console)
+		remoteUrl, err = getRemoteUrlFromPrompt(ctx, remoteName, p.host(), p.console)
 		if err != nil {
 			return "", fmt.Errorf("getting remote from prompt: %w", err)
 		}
@@ -140,26 +156,14 @@ func (p *GitHubScmProvider) configureGitRemote(
 	return remoteUrl, nil
 }
 
-// defines the structure of an ssl git remote
-var gitHubRemoteGitUrlRegex = regexp.MustCompile(`^git@github\.com:(.*?)(?:\.git)?$`)
-
-// defines the structure of an HTTPS git remote
-var gitHubRemoteHttpsUrlRegex = regexp.MustCompile(`^https://(?:www\.)?github\.com/(.*?)(?:\.git)?$`)
-
 // ErrRemoteHostIsNotGitHub the error used when a non GitHub remote is found
 var ErrRemoteHostIsNotGitHub = errors.New("not a github host")
 
 // gitRepoDetails extracts the information from a GitHub remote url into general scm concepts
 // like owner, name and path
 func (p *GitHubScmProvider) gitRepoDetails(ctx context.Context, remoteUrl string) (*gitRepositoryDetails, error) {
-	slug := ""
-	for _, r := range []*regexp.Regexp{gitHubRemoteGitUrlRegex, gitHubRemoteHttpsUrlRegex} {
-		captures := r.FindStringSubmatch(remoteUrl)
-		if captures != nil {
-			slug = captures[1]
-		}
-	}
-	if slug == "" {
+	slug, err := githubRemote.GetSlugForRemoteOnHost(remoteUrl, p.host())
+	if err != nil {
 		return nil, ErrRemoteHostIsNotGitHub
 	}
 	slugParts := strings.Split(slug, "/")
@@ -182,7 +186,7 @@ func (p *GitHubScmProvider) preventGitPush(
 	if !p.newGitHubRepoCreated {
 		slug := gitRepo.owner + "/" + gitRepo.repoName
 		return notifyWhenGitHubActionsAreDisabled(
-			ctx, gitRepo.gitProjectPath, slug, remoteName, branchName, p.console, p.commandRunner,
+			ctx, gitRepo.gitProjectPath, slug, remoteName, branchName, p.host(), p.console, p.commandRunner,
 		)
 	}
 	return false, nil
@@ -225,10 +229,11 @@ func notifyWhenGitHubActionsAreDisabled(
 	repoSlug string,
 	origin string,
 	branch string,
+	hostname string,
 	console input.Console,
 	commandRunner exec.CommandRunner,
 ) (bool, error) {
-	ghCli, err := github.NewGitHubCli(ctx, console, commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, console, commandRunner, hostname)
 	if err != nil {
 		return false, err
 	}
@@ -285,8 +290,8 @@ func notifyWhenGitHubActionsAreDisabled(
 			" - If you forked and cloned a template, please enable actions here: %s.\n"+
 			" - Otherwise, check the GitHub Actions permissions here: %s.\n",
 			output.WithHighLightFormat("GitHub actions are currently disabled for your repository."),
-			output.WithHighLightFormat("https://github.com/%s/actions", repoSlug),
-			output.WithHighLightFormat("https://github.com/%s/settings/actions", repoSlug))
+			output.WithHighLightFormat("https://%s/%s/actions", hostname, repoSlug),
+			output.WithHighLightFormat("https://%s/%s/settings/actions", hostname, repoSlug))
 
 		console.Message(ctx, message)
 
@@ -322,22 +327,39 @@ type GitHubCiProvider struct {
 	credential    azcore.TokenCredential
 	commandRunner exec.CommandRunner
 	console       input.Console
+	// The GitHub host running the actions, github.com or a GitHub Enterprise Server instance
+	hostname   string
+	azdContext *azdcontext.AzdContext
+	// The pull request workflow generated along the azd workflow, set by preConfigureCheck. Defaults to build.
+	prValidation PrValidationMode
 }
 
 func NewGitHubCiProvider(
-	credential azcore.TokenCredential, commandRunner exec.CommandRunner, console input.Console) *GitHubCiProvider {
+	credential azcore.TokenCredential,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	hostname string,
+	azdContext *azdcontext.AzdContext,
+) *GitHubCiProvider {
 	return &GitHubCiProvider{
 		credential:    credential,
 		commandRunner: commandRunner,
 		console:       console,
+		hostname:      hostname,
+		azdContext:    azdContext,
 	}
 }
 
+// host returns the GitHub host running the actions, github.com when it isn't set
+func (p *GitHubCiProvider) host() string {
+	return gitHubHostOrDefault(p.hostname)
+}
+
 // ***  subareaProvider implementation ******
 
 // requiredTools defines the requires tools for GitHub to be used as CI manager
 func (p *GitHubCiProvider) requiredTools(ctx context.Context) ([]tools.ExternalTool, error) {
-	ghCli, err := github.NewGitHubCli(ctx, p.console, p.commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
 	if err != nil {
 		return nil, err
 	}
@@ -352,16 +374,18 @@ func (p *GitHubCiProvider) preConfigureCheck(
 	infraOptions provisioning.Options,
 	projectPath string,
 ) (bool, error) {
-	ghCli, err := github.NewGitHubCli(ctx, p.console, p.commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
 	if err != nil {
 		return false, err
 	}
 	gitCli := git.NewGitCli(p.commandRunner)
-	updated, err := ensureGitHubLogin(ctx, projectPath, ghCli, gitCli, github.GitHubHostName, p.console)
+	updated, err := ensureGitHubLogin(ctx, projectPath, ghCli, gitCli, p.host(), p.console)
 	if err != nil {
 		return updated, err
 	}
 
+	p.prValidation = PrValidationMode(pipelineManagerArgs.PrValidation)
+
 	authType := PipelineAuthType(pipelineManagerArgs.PipelineAuthTypeName)
 
 	// Federated Auth + Terraform is not a supported combination
@@ -434,7 +458,7 @@ func (p *GitHubCiProvider) configureConnection(
 		Lines: []string{
 			"",
 			"GitHub Action secrets are now configured. You can view GitHub action secrets that were created at this link:",
-			output.WithLinkFormat("https://github.com/%s/settings/secrets/actions", repoSlug),
+			output.WithLinkFormat("https://%s/%s/settings/secrets/actions", p.host(), repoSlug),
 			""},
 	})
 
@@ -449,7 +473,7 @@ func (p *GitHubCiProvider) configureClientCredentialsAuth(
 	repoSlug string,
 	credentials json.RawMessage,
 ) error {
-	ghCli, err := github.NewGitHubCli(ctx, p.console, p.commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
 	if err != nil {
 		return err
 	}
@@ -557,7 +581,7 @@ func (p *GitHubCiProvider) configureFederatedAuth(
 	credentials json.RawMessage,
 	credential azcore.TokenCredential,
 ) error {
-	ghCli, err := github.NewGitHubCli(ctx, p.console, p.commandRunner)
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
 	if err != nil {
 		return err
 	}
@@ -567,7 +591,15 @@ func (p *GitHubCiProvider) configureFederatedAuth(
 		return fmt.Errorf("failed unmarshalling azure credentials: %w", err)
 	}
 
-	err = applyFederatedCredentials(ctx, repoSlug, &azureCredentials, p.console, credential)
+	// The jobs deploying to an environment authenticate with a token for the environment instead of the branch
+	environments, _, err := p.gitHubEnvironments(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = applyFederatedCredentials(
+		ctx, repoSlug, p.host(), environments, &azureCredentials, p.console, credential,
+	)
 	if err != nil {
 		return err
 	}
@@ -593,13 +625,34 @@ func (p *GitHubCiProvider) configureFederatedAuth(
 }
 
 const (
-	federatedIdentityIssuer   = "https://token.actions.githubusercontent.com"
-	federatedIdentityAudience = "api://AzureADTokenExchange"
+	gitHubFederatedIdentityIssuer = "https://token.actions.githubusercontent.com"
+	federatedIdentityAudience     = "api://AzureADTokenExchange"
 )
 
+// federatedIdentityIssuer returns the issuer of the OIDC tokens of GitHub Actions, GitHub Enterprise Server instances
+// issue their own tokens.
+func federatedIdentityIssuer(hostname string) string {
+	if hostname == github.GitHubHostName {
+		return gitHubFederatedIdentityIssuer
+	}
+
+	return fmt.Sprintf("https://%s/_services/token", hostname)
+}
+
+// gitHubHostOrDefault returns hostname, or github.com when it isn't set
+func gitHubHostOrDefault(hostname string) string {
+	if hostname == "" {
+		return github.GitHubHostName
+	}
+
+	return hostname
+}
+
 func applyFederatedCredentials(
 	ctx context.Context,
 	repoSlug string,
+	hostname string,
+	environments []string,
 	azureCredentials *azcli.AzureCredentials,
 	console input.Console,
 	credential azcore.TokenCredential,
@@ -634,20 +687,30 @@ func applyFederatedCredentials(
 	federatedCredentials := []graphsdk.FederatedIdentityCredential{
 		{
 			Name:        fmt.Sprintf("%s-main", credentialSafeName),
-			Issuer:      federatedIdentityIssuer,
+			Issuer:      federatedIdentityIssuer(hostname),
 			Subject:     fmt.Sprintf("repo:%s:ref:refs/heads/main", repoSlug),
 			Description: convert.RefOf("Created by Azure Developer CLI"),
 			Audiences:   []string{federatedIdentityAudience},
 		},
 		{
 			Name:        fmt.Sprintf("%s-pull_request", credentialSafeName),
-			Issuer:      federatedIdentityIssuer,
+			Issuer:      federatedIdentityIssuer(hostname),
 			Subject:     fmt.Sprintf("repo:%s:pull_request", repoSlug),
 			Description: convert.RefOf("Created by Azure Developer CLI"),
 			Audiences:   []string{federatedIdentityAudience},
 		},
 	}
 
+	for _, name := range environments {
+		federatedCredentials = append(federatedCredentials, graphsdk.FederatedIdentityCredential{
+			Name:        fmt.Sprintf("%s-environment-%s", credentialSafeName, federatedCredentialSafeName(name)),
+			Issuer:      federatedIdentityIssuer(hostname),
+			Subject:     fmt.Sprintf("repo:%s:environment:%s", repoSlug, name),
+			Description: convert.RefOf("Created by Azure Developer CLI"),
+			Audiences:   []string{federatedIdentityAudience},
+		})
+	}
+
 	// Ensure the credential exists otherwise create a new one.
 	for i := range federatedCredentials {
 		err := ensureFederatedCredential(
@@ -660,21 +723,101 @@ func applyFederatedCredentials(
 	return nil
 }
 
-// configurePipeline is a no-op for GitHub, as the pipeline is automatically
-// created by creating the workflow files in .github folder.
+// federatedCredentialSafeName replaces the characters of name which aren't allowed in the name of a federated
+// identity credential.
+func federatedCredentialSafeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '-'
+	}, name)
+}
+
+// configurePipeline writes or updates the azd workflows and creates the GitHub environments deployed to by the
+// workflows, the pipeline itself is automatically created by pushing the workflow files in .github folder.
 func (p *GitHubCiProvider) configurePipeline(
 	ctx context.Context,
 	repoDetails *gitRepositoryDetails,
 	provisioningProvider provisioning.Options,
 ) (*CiPipeline, error) {
+	var stages []string
+	var containerImage string
+	if p.azdContext != nil {
+		prj, err := project.Load(ctx, p.azdContext.ProjectPath())
+		if err != nil {
+			return nil, fmt.Errorf("loading project: %w", err)
+		}
+
+		stages = prj.Pipeline.Stages
+		if containerImage, err = azdContainerImage(prj.Pipeline.Container); err != nil {
+			return nil, err
+		}
+	}
+
+	err := ensureGitHubWorkflows(ctx, repoDetails.gitProjectPath, stages, containerImage, p.prValidation, p.console)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.configureEnvironments(ctx, repoDetails.owner+"/"+repoDetails.repoName); err != nil {
+		return nil, fmt.Errorf("configuring environments: %w", err)
+	}
+
 	return &CiPipeline{
 		name:   "actions",
 		remote: fmt.Sprintf("%s/actions", repoDetails.remote),
 	}, nil
 }
 
+// setValues sets the secrets and the variables of the repository used by the workflows, which must exist.
+func (p *GitHubCiProvider) setValues(
+	ctx context.Context,
+	repoDetails *gitRepositoryDetails,
+	values []PipelineValue,
+	dryRun bool,
+) error {
+	ghCli, err := github.NewGitHubCliForHost(ctx, p.console, p.commandRunner, p.host())
+	if err != nil {
+		return err
+	}
+	gitCli := git.NewGitCli(p.commandRunner)
+	if _, err := ensureGitHubLogin(ctx, repoDetails.gitProjectPath, ghCli, gitCli, p.host(), p.console); err != nil {
+		return err
+	}
+
+	repoSlug := repoDetails.owner + "/" + repoDetails.repoName
+	hasWorkflows, err := ghCli.GitHubActionsExists(ctx, repoSlug)
+	if err != nil {
+		return err
+	}
+	if !hasWorkflows {
+		return fmt.Errorf(
+			"repository %s has no GitHub Actions workflows, run %s to configure them",
+			repoSlug, output.WithHighLightFormat("azd pipeline config"))
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	for _, value := range values {
+		if value.Secret {
+			err = ghCli.SetSecret(ctx, repoSlug, value.Name, value.Value)
+		} else {
+			err = ghCli.SetVariable(ctx, repoSlug, value.Name, value.Value)
+		}
+		if err != nil {
+			return fmt.Errorf("setting %s: %w", value.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // ensureGitHubLogin ensures the user is logged into the GitHub CLI. If not, it prompt the user
 // if they would like to log in and if so runs `gh auth login` interactively.
+// In a dev container, the GitHub credential forwarded from the host is reused before prompting.
 func ensureGitHubLogin(
 	ctx context.Context,
 	projectPath string,
@@ -691,6 +834,18 @@ func ensureGitHubLogin(
 		return false, nil
 	}
 
+	if devcontainer.Detect(os.Getenv) == devcontainer.KindDevContainer {
+		loggedIn, err := reuseDevContainerCredential(ctx, ghCli, gitCli, hostname, console)
+		if err != nil {
+			return false, err
+		}
+
+		if loggedIn {
+			console.Message(ctx, "Logged in to GitHub with the credential forwarded to your dev container.")
+			return true, nil
+		}
+	}
+
 	for {
 		var accept bool
 		accept, err := console.Confirm(ctx, input.ConsoleOptions{
@@ -725,6 +880,43 @@ func ensureGitHubLogin(
 	}
 }
 
+// reuseDevContainerCredential logs the GitHub CLI in with the GitHub credential that dev containers forward from the
+// host through the git credential helper, once the user agrees to it. It returns false when there is no credential to
+// reuse or the user declined, so the user can log in interactively instead.
+func reuseDevContainerCredential(
+	ctx context.Context,
+	ghCli github.GitHubCli,
+	gitCli git.GitCli,
+	hostname string,
+	console input.Console,
+) (bool, error) {
+	token, err := gitCli.GetCredential(ctx, hostname)
+	if err != nil || token == "" {
+		return false, nil
+	}
+
+	accept, err := console.Confirm(ctx, input.ConsoleOptions{
+		Message: "This command requires you to be logged into GitHub. " +
+			"Log in using the GitHub credential forwarded to your dev container?",
+		DefaultValue: true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("prompting to log in to github: %w", err)
+	}
+
+	if !accept {
+		return false, nil
+	}
+
+	if err := ghCli.LoginWithToken(ctx, hostname, token); err != nil {
+		log.Printf("reusing the dev container GitHub credential: %v", err)
+		return false, nil
+	}
+
+	authResult, err := ghCli.GetAuthStatus(ctx, hostname)
+	return err == nil && authResult.LoggedIn, nil
+}
+
 // getRemoteUrlFromExisting let user to select an existing repository from his/her account and
 // returns the remote url for that repository.
 func getRemoteUrlFromExisting(ctx context.Context, ghCli github.GitHubCli, console input.Console) (string, error) {
@@ -813,7 +1005,12 @@ func getRemoteUrlFromNewRepository(
 
 // getRemoteUrlFromPrompt interactively prompts the user for a URL for a GitHub repository. It validates
 // that the URL is well formed and is in the correct format for a GitHub repository.
-func getRemoteUrlFromPrompt(ctx context.Context, remoteName string, console input.Console) (string, error) {
+func getRemoteUrlFromPrompt(
+	ctx context.Context,
+	remoteName string,
+	hostname string,
+	console input.Console,
+) (string, error) {
 	remoteUrl := ""
 
 	for remoteUrl == "" {
@@ -827,7 +1024,8 @@ func getRemoteUrlFromPrompt(ctx context.Context, remoteName string, console inpu
 
 		remoteUrl = promptValue
 
-		if _, err := githubRemote.GetSlugForRemote(remoteUrl); errors.Is(err, githubRemote.ErrRemoteHostIsNotGitHub) {
+		_, err = githubRemote.GetSlugForRemoteOnHost(remoteUrl, hostname)
+		if errors.Is(err, githubRemote.ErrRemoteHostIsNotGitHub) {
 			fmt.Fprintf(console.Handles().Stdout, "error: \"%s\" is not a valid GitHub URL.\n", remoteUrl)
 
 			// So we retry from the loop.
@@ -882,8 +1080,7 @@ func ensureFederatedCredential(
 
 func createGraphClient(ctx context.Context, credential azcore.TokenCredential) (*graphsdk.GraphClient, error) {
 	graphOptions := azsdk.
-		NewClientOptionsBuilder().
-		WithTransport(httputil.GetHttpClient(ctx)).
+		DefaultClientOptionsBuilder(httputil.GetHttpClient(ctx), internal.MakeUserAgentString("")).
 		BuildCoreClientOptions()
 
 	return graphsdk.NewGraphClient(credential, graphOptions)
@@ -34,6 +34,17 @@ func Test_gitHub_provider_getRepoDetails(t *testing.T) {
 		require.EqualValues(t, "Azure", details.owner)
 		require.EqualValues(t, "azure-dev", details.repoName)
 	})
+	t.Run("enterprise server", func(t *testing.T) {
+		provider := &GitHubScmProvider{hostname: "github.contoso.com"}
+		ctx := context.Background()
+		details, e := provider.gitRepoDetails(ctx, "https://github.contoso.com/Azure/azure-dev.git")
+		require.NoError(t, e)
+		require.Equal(t, "Azure", details.owner)
+		require.Equal(t, "azure-dev", details.repoName)
+
+		_, e = provider.gitRepoDetails(ctx, "https://github.com/Azure/azure-dev.git")
+		require.ErrorIs(t, e, ErrRemoteHostIsNotGitHub)
+	})
 	t.Run("error", func(t *testing.T) {
 		provider := &GitHubScmProvider{}
 		ctx := context.Background()
@@ -48,7 +59,8 @@ func Test_gitHub_provider_preConfigure_check(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		setupGithubCliMocks(mockContext)
 
-		provider := NewGitHubCiProvider(mockContext.Credentials, mockContext.CommandRunner, mockContext.Console)
+		provider := NewGitHubCiProvider(
+			mockContext.Credentials, mockContext.CommandRunner, mockContext.Console, github.GitHubHostName, nil)
 		updatedConfig, err := provider.preConfigureCheck(
 			*mockContext.Context,
 			PipelineManagerArgs{},
@@ -75,7 +87,8 @@ func Test_gitHub_provider_preConfigure_check(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		setupGithubCliMocks(mockContext)
 
-		provider := NewGitHubCiProvider(mockContext.Credentials, mockContext.CommandRunner, mockContext.Console)
+		provider := NewGitHubCiProvider(
+			mockContext.Credentials, mockContext.CommandRunner, mockContext.Console, github.GitHubHostName, nil)
 		updatedConfig, err := provider.preConfigureCheck(
 			*mockContext.Context, pipelineManagerArgs, infraOptions, "")
 		require.Error(t, err)
@@ -95,7 +108,8 @@ func Test_gitHub_provider_preConfigure_check(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		setupGithubCliMocks(mockContext)
 
-		provider := NewGitHubCiProvider(mockContext.Credentials, mockContext.CommandRunner, mockContext.Console)
+		provider := NewGitHubCiProvider(
+			mockContext.Credentials, mockContext.CommandRunner, mockContext.Console, github.GitHubHostName, nil)
 		updatedConfig, err := provider.preConfigureCheck(
 			*mockContext.Context, pipelineManagerArgs, infraOptions, "")
 		require.NoError(t, err)
@@ -107,6 +121,11 @@ func Test_gitHub_provider_preConfigure_check(t *testing.T) {
 	})
 }
 
+func Test_federatedIdentityIssuer(t *testing.T) {
+	require.Equal(t, "https://token.actions.githubusercontent.com", federatedIdentityIssuer(github.GitHubHostName))
+	require.Equal(t, "https://github.contoso.com/_services/token", federatedIdentityIssuer("github.contoso.com"))
+}
+
 func setupGithubCliMocks(mockContext *mocks.MockContext) {
 	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
 		return strings.Contains(command, "auth status")
@@ -0,0 +1,588 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/resources"
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// The workflows written by `azd pipeline config`, relative to the project directory
+var (
+	gitHubWorkflowPath   = filepath.Join(githubFolder, "workflows", "azure-dev.yml")
+	gitHubPrWorkflowPath = filepath.Join(githubFolder, "workflows", "azure-dev-pr.yml")
+)
+
+// The action installing azd on the runner, see stepAction
+const setupAzdAction = "azure/setup-azd"
+
+// ensureGitHubWorkflows writes or updates the azd workflow, and the workflow validating the pull requests unless
+// prValidation is PrValidationNone, see ensureGitHubWorkflow. With stages, the azd workflow deploys to each stage in
+// order, and with a container image, the jobs run azd in the image, see gitHubWorkflow.
+func ensureGitHubWorkflows(
+	ctx context.Context,
+	projectDir string,
+	stages []string,
+	containerImage string,
+	prValidation PrValidationMode,
+	console input.Console,
+) error {
+	generated, err := gitHubWorkflow(stages, containerImage)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureGitHubWorkflow(ctx, projectDir, gitHubWorkflowPath, generated, console); err != nil {
+		return err
+	}
+
+	if prValidation == PrValidationNone {
+		return nil
+	}
+
+	generated, err = gitHubPrWorkflow(prValidation, containerImage)
+	if err != nil {
+		return err
+	}
+
+	return ensureGitHubWorkflow(ctx, projectDir, gitHubPrWorkflowPath, generated, console)
+}
+
+// ensureGitHubWorkflow writes the generated workflow to the project when it doesn't have it. Otherwise, the parts of
+// the workflow owned by azd are updated after previewing the changes, and the triggers, steps and jobs added by the user
+// are kept.
+func ensureGitHubWorkflow(
+	ctx context.Context,
+	projectDir string,
+	relativePath string,
+	generated []byte,
+	console input.Console,
+) error {
+	workflowPath := filepath.Join(projectDir, relativePath)
+
+	existing, err := os.ReadFile(workflowPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(workflowPath), osutil.PermissionDirectory); err != nil {
+			return fmt.Errorf("creating workflow directory: %w", err)
+		}
+
+		if err := os.WriteFile(workflowPath, generated, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing workflow: %w", err)
+		}
+
+		console.Message(ctx, fmt.Sprintf("Created workflow %s", output.WithHighLightFormat(relativePath)))
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading workflow: %w", err)
+	}
+
+	merged, changed, err := mergeWorkflow(existing, generated)
+	if err != nil {
+		return fmt.Errorf("updating workflow %s: %w", relativePath, err)
+	}
+
+	if !changed {
+		return nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existing)),
+		B:        difflib.SplitLines(string(merged)),
+		FromFile: relativePath,
+		ToFile:   relativePath,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("comparing workflow: %w", err)
+	}
+
+	console.Message(ctx, fmt.Sprintf("\nThe steps of %s run by azd have changed:\n",
+		output.WithHighLightFormat(relativePath)))
+	console.Message(ctx, formatDiff(diff))
+
+	update, err := console.Confirm(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Update %s with these changes?", relativePath),
+		DefaultValue: true,
+	})
+	if err != nil {
+		return fmt.Errorf("prompting to update workflow: %w", err)
+	}
+
+	if !update {
+		return nil
+	}
+
+	if err := os.WriteFile(workflowPath, merged, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing workflow: %w", err)
+	}
+
+	console.Message(ctx, fmt.Sprintf("Updated workflow %s", output.WithHighLightFormat(relativePath)))
+	return nil
+}
+
+// gitHubWorkflow returns the workflow generated by azd. Without stages, it is the embedded workflow, running azd in its
+// build job. With stages, the build job is replaced by a job per stage, like deploy-dev and then deploy-prod. Each job
+// runs in the GitHub environment of its stage, which scopes the secrets of the job to the azd environment of the stage,
+// after the job of the previous stage succeeds. With a container image, the jobs run in the image, which provides azd,
+// instead of installing azd on the runner.
+func gitHubWorkflow(stages []string, containerImage string) ([]byte, error) {
+	if len(stages) == 0 && containerImage == "" {
+		return resources.GitHubWorkflow, nil
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(resources.GitHubWorkflow, &document); err != nil {
+		return nil, fmt.Errorf("parsing generated workflow: %w", err)
+	}
+
+	root := workflowRoot(&document)
+	build := mappingValue(mappingValue(root, "jobs"), "build")
+	if build == nil || build.Kind != yaml.MappingNode {
+		return nil, errors.New("the generated workflow has no build job")
+	}
+
+	if containerImage != "" {
+		runInContainer(build, containerImage)
+	}
+
+	if len(stages) > 0 {
+		setMappingValue(root, "jobs", stageJobs(build, stages))
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("marshalling generated workflow: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("marshalling generated workflow: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// gitHubPrWorkflow returns the workflow validating the pull requests of the mainline branch, running the unit tests and
+// packaging the services without deploying them. With PrValidationEphemeral, the workflow also provisions the
+// infrastructure in an environment of the pull request, named after the environment of the azd workflow, and deletes
+// it at the end of the run, even when a step fails or the run is cancelled.
+func gitHubPrWorkflow(prValidation PrValidationMode, containerImage string) ([]byte, error) {
+	if prValidation != PrValidationEphemeral && containerImage == "" {
+		return resources.GitHubPrWorkflow, nil
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(resources.GitHubPrWorkflow, &document); err != nil {
+		return nil, fmt.Errorf("parsing generated pull request workflow: %w", err)
+	}
+
+	validate := mappingValue(mappingValue(workflowRoot(&document), "jobs"), "validate")
+	if validate == nil || validate.Kind != yaml.MappingNode {
+		return nil, errors.New("the generated pull request workflow has no validate job")
+	}
+
+	if containerImage != "" {
+		runInContainer(validate, containerImage)
+	}
+
+	if prValidation == PrValidationEphemeral {
+		mergeMapping(validate, "env", mappingNode(
+			"AZURE_ENV_NAME", "${{ secrets.AZURE_ENV_NAME }}-pr${{ github.event.pull_request.number }}"))
+
+		steps := mappingValue(validate, "steps")
+		steps.Content = append(steps.Content,
+			mappingNode(
+				"name", "Provision Ephemeral Environment",
+				"run", "azd provision --no-prompt"),
+			mappingNode(
+				"name", "Delete Ephemeral Environment",
+				"if", "${{ always() }}",
+				"run", "azd down --force --purge --no-prompt"),
+		)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("marshalling generated pull request workflow: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("marshalling generated pull request workflow: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// runInContainer sets the container of the job after its runner, and removes the step installing azd.
+func runInContainer(job *yaml.Node, image string) {
+	container := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(container, "image", scalarNode(image))
+
+	content := make([]*yaml.Node, 0, len(job.Content)+2)
+	for i := 0; i+1 < len(job.Content); i += 2 {
+		content = append(content, job.Content[i], job.Content[i+1])
+		if job.Content[i].Value == "runs-on" {
+			content = append(content, scalarNode("container"), container)
+		}
+	}
+	job.Content = content
+
+	removeSetupAzd(mappingValue(job, "steps"))
+}
+
+// removeSetupAzd removes the steps installing azd, which would replace the azd of the container image.
+func removeSetupAzd(steps *yaml.Node) {
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return
+	}
+
+	kept := steps.Content[:0]
+	for _, step := range steps.Content {
+		if stepAction(step) != setupAzdAction {
+			kept = append(kept, step)
+		}
+	}
+	steps.Content = kept
+}
+
+// stageJobs returns the jobs deploying to each stage in order, copies of the build job.
+func stageJobs(build *yaml.Node, stages []string) *yaml.Node {
+	jobs := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	previous := ""
+	for _, stage := range stages {
+		job := &yaml.Node{Kind: yaml.MappingNode, Tag: build.Tag}
+		for i := 0; i+1 < len(build.Content); i += 2 {
+			job.Content = append(job.Content, build.Content[i], build.Content[i+1])
+
+			if build.Content[i].Value == "runs-on" {
+				job.Content = append(job.Content, scalarNode("environment"), scalarNode(stage))
+				if previous != "" {
+					job.Content = append(job.Content, scalarNode("needs"), scalarNode(previous))
+				}
+			}
+		}
+
+		previous = "deploy-" + federatedCredentialSafeName(stage)
+		setMappingValue(jobs, previous, job)
+	}
+
+	return jobs
+}
+
+// mergeWorkflow merges the generated workflow into the existing workflow:
+//   - the permissions and the job env values of the generated workflow are set, the other ones are kept
+//   - the steps of the generated workflow replace the matching steps of the existing job, the same action or the same
+//     name, and the missing ones are inserted after the previous generated step
+//   - the triggers, the jobs and the steps only in the existing workflow are kept
+//
+// It returns false when the merge doesn't change the existing workflow.
+func mergeWorkflow(existing []byte, generated []byte) ([]byte, bool, error) {
+	var existingDoc, generatedDoc yaml.Node
+	if err := yaml.Unmarshal(existing, &existingDoc); err != nil {
+		return nil, false, fmt.Errorf("parsing workflow: %w", err)
+	}
+
+	if err := yaml.Unmarshal(generated, &generatedDoc); err != nil {
+		return nil, false, fmt.Errorf("parsing generated workflow: %w", err)
+	}
+
+	existingRoot := workflowRoot(&existingDoc)
+	generatedRoot := workflowRoot(&generatedDoc)
+	if existingRoot == nil || generatedRoot == nil {
+		return nil, false, errors.New("the workflow is empty or isn't a mapping")
+	}
+
+	for i := 0; i+1 < len(generatedRoot.Content); i += 2 {
+		key, value := generatedRoot.Content[i].Value, generatedRoot.Content[i+1]
+
+		switch key {
+		case "permissions":
+			mergeMapping(existingRoot, key, value)
+		case "jobs":
+			jobs := mappingValue(existingRoot, key)
+			if jobs == nil || jobs.Kind != yaml.MappingNode {
+				setMappingValue(existingRoot, key, value)
+				continue
+			}
+
+			mergeJobs(jobs, value)
+		default:
+			if mappingValue(existingRoot, key) == nil {
+				setMappingValue(existingRoot, key, value)
+			}
+		}
+	}
+
+	var existingValue, mergedValue any
+	if err := existingDoc.Decode(&mergedValue); err != nil {
+		return nil, false, fmt.Errorf("decoding merged workflow: %w", err)
+	}
+
+	if err := yaml.Unmarshal(existing, &existingValue); err != nil {
+		return nil, false, fmt.Errorf("parsing workflow: %w", err)
+	}
+
+	// Re-encoding the workflow drops its blank lines, leave it as is when the merge changes nothing
+	if reflect.DeepEqual(existingValue, mergedValue) {
+		return existing, false, nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&existingDoc); err != nil {
+		return nil, false, fmt.Errorf("marshalling workflow: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, false, fmt.Errorf("marshalling workflow: %w", err)
+	}
+
+	return buf.Bytes(), true, nil
+}
+
+// mergeJobs merges each generated job into the existing job running the same steps, or the job with the same name.
+// An existing job is merged with one generated job at most, the generated jobs without a match are added. The needs of
+// the generated jobs are renamed to the existing jobs they were merged with.
+func mergeJobs(existingJobs *yaml.Node, generatedJobs *yaml.Node) {
+	matched := map[*yaml.Node]bool{}
+	names := map[string]string{}
+
+	for i := 0; i+1 < len(generatedJobs.Content); i += 2 {
+		name, generatedJob := generatedJobs.Content[i].Value, generatedJobs.Content[i+1]
+
+		if needs := mappingValue(generatedJob, "needs"); needs != nil {
+			if existingName, has := names[needs.Value]; has {
+				setMappingValue(generatedJob, "needs", scalarNode(existingName))
+			}
+		}
+
+		existingName, existingJob := findJob(existingJobs, name, generatedJob, matched)
+		if existingJob == nil {
+			setMappingValue(existingJobs, name, generatedJob)
+			continue
+		}
+
+		matched[existingJob] = true
+		names[name] = existingName
+
+		for j := 0; j+1 < len(generatedJob.Content); j += 2 {
+			key, value := generatedJob.Content[j].Value, generatedJob.Content[j+1]
+
+			switch key {
+			case "env":
+				mergeMapping(existingJob, key, value)
+			case "container":
+				// The image is pinned to the version of azd configuring the pipeline, it is updated with azd
+				setMappingValue(existingJob, key, value)
+			case "steps":
+				steps := mappingValue(existingJob, key)
+				if steps == nil || steps.Kind != yaml.SequenceNode {
+					setMappingValue(existingJob, key, value)
+					continue
+				}
+
+				mergeSteps(steps, value)
+			default:
+				if mappingValue(existingJob, key) == nil {
+					setMappingValue(existingJob, key, value)
+				}
+			}
+		}
+
+		if mappingValue(generatedJob, "container") != nil {
+			removeSetupAzd(mappingValue(existingJob, "steps"))
+		}
+	}
+}
+
+// findJob returns the name and the existing job running one of the steps of the generated job, or else the job with the
+// same name. The jobs already matched are skipped.
+func findJob(
+	existingJobs *yaml.Node,
+	name string,
+	generatedJob *yaml.Node,
+	matched map[*yaml.Node]bool,
+) (string, *yaml.Node) {
+	generatedSteps := mappingValue(generatedJob, "steps")
+	if generatedSteps != nil {
+		for i := 0; i+1 < len(existingJobs.Content); i += 2 {
+			job := existingJobs.Content[i+1]
+			if matched[job] {
+				continue
+			}
+
+			steps := mappingValue(job, "steps")
+			if steps == nil || steps.Kind != yaml.SequenceNode {
+				continue
+			}
+
+			for _, step := range generatedSteps.Content {
+				if findStep(steps, step) >= 0 {
+					return existingJobs.Content[i].Value, job
+				}
+			}
+		}
+	}
+
+	if job := mappingValue(existingJobs, name); job != nil && job.Kind == yaml.MappingNode && !matched[job] {
+		return name, job
+	}
+
+	return "", nil
+}
+
+// mergeSteps replaces the existing steps matching the generated steps, and inserts the other generated steps after
+// the previous generated step.
+func mergeSteps(existingSteps *yaml.Node, generatedSteps *yaml.Node) {
+	position := 0
+	for _, step := range generatedSteps.Content {
+		if index := findStep(existingSteps, step); index >= 0 {
+			if step.HeadComment == "" {
+				step.HeadComment = existingSteps.Content[index].HeadComment
+			}
+
+			existingSteps.Content[index] = step
+			position = index + 1
+			continue
+		}
+
+		existingSteps.Content = append(existingSteps.Content[:position],
+			append([]*yaml.Node{step}, existingSteps.Content[position:]...)...)
+		position++
+	}
+}
+
+// findStep returns the index of the existing step using the same action, with the same name or running the same
+// script as step, -1 when there is none.
+func findStep(existingSteps *yaml.Node, step *yaml.Node) int {
+	uses := stepAction(step)
+	name := scalarValue(mappingValue(step, "name"))
+	run := strings.TrimSpace(scalarValue(mappingValue(step, "run")))
+
+	for i, existing := range existingSteps.Content {
+		switch {
+		case uses != "" && stepAction(existing) == uses,
+			name != "" && scalarValue(mappingValue(existing, "name")) == name,
+			run != "" && strings.TrimSpace(scalarValue(mappingValue(existing, "run"))) == run:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// stepAction returns the action used by the step without its version, like azure/setup-azd
+func stepAction(step *yaml.Node) string {
+	uses := scalarValue(mappingValue(step, "uses"))
+	if at := strings.Index(uses, "@"); at >= 0 {
+		uses = uses[:at]
+	}
+
+	return strings.ToLower(uses)
+}
+
+// mergeMapping sets the values of the generated mapping in the existing mapping at key, keeping the other values.
+func mergeMapping(node *yaml.Node, key string, generated *yaml.Node) {
+	existing := mappingValue(node, key)
+	if existing == nil || existing.Kind != yaml.MappingNode || generated.Kind != yaml.MappingNode {
+		setMappingValue(node, key, generated)
+		return
+	}
+
+	for i := 0; i+1 < len(generated.Content); i += 2 {
+		setMappingValue(existing, generated.Content[i].Value, generated.Content[i+1])
+	}
+}
+
+func workflowRoot(document *yaml.Node) *yaml.Node {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 ||
+		document.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	return document.Content[0]
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+
+	node.Content = append(node.Content, scalarNode(key), value)
+}
+
+// mappingNode returns a mapping of the keys and values, given in pairs.
+func mappingNode(keyValues ...string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		node.Content = append(node.Content, scalarNode(keyValues[i]), scalarNode(keyValues[i+1]))
+	}
+
+	return node
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+
+	return node.Value
+}
+
+// formatDiff colors the added and the removed lines of a unified diff.
+func formatDiff(diff string) string {
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = output.WithBold(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = output.WithSuccessFormat(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = output.WithErrorFormat(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = output.WithGrayFormat(line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,320 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/resources"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const generatedWorkflow = `on: push
+permissions:
+  id-token: write
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    env:
+      AZURE_CLIENT_ID: ${{ secrets.AZURE_CLIENT_ID }}
+    steps:
+      - name: Checkout
+        uses: actions/checkout@v4
+      - name: Install azd
+        uses: Azure/setup-azd@v1.0.0
+      - name: Deploy Application
+        run: azd deploy --no-prompt
+`
+
+func Test_mergeWorkflow(t *testing.T) {
+	t.Run("KeepsUserChanges", func(t *testing.T) {
+		existing := `on:
+  pull_request:
+permissions:
+  packages: write
+jobs:
+  deploy:
+    runs-on: windows-latest
+    env:
+      AZURE_CLIENT_ID: old
+      NODE_ENV: production
+    steps:
+      - uses: actions/checkout@v2
+      - run: npm test
+      - name: Deploy Application
+        run: azd deploy
+  lint:
+    steps:
+      - run: npm run lint
+`
+		merged, changed, err := mergeWorkflow([]byte(existing), []byte(generatedWorkflow))
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		var workflow map[string]any
+		require.NoError(t, yaml.Unmarshal(merged, &workflow))
+
+		require.Equal(t, map[string]any{"pull_request": nil}, workflow["on"])
+		require.Equal(t, map[string]any{"packages": "write", "id-token": "write"}, workflow["permissions"])
+
+		jobs := workflow["jobs"].(map[string]any)
+		require.Len(t, jobs, 2)
+		require.Equal(t, map[string]any{"steps": []any{map[string]any{"run": "npm run lint"}}}, jobs["lint"])
+
+		deploy := jobs["deploy"].(map[string]any)
+		require.Equal(t, "windows-latest", deploy["runs-on"])
+		require.Equal(t, map[string]any{
+			"AZURE_CLIENT_ID": "${{ secrets.AZURE_CLIENT_ID }}",
+			"NODE_ENV":        "production",
+		}, deploy["env"])
+		require.Equal(t, []any{
+			map[string]any{"name": "Checkout", "uses": "actions/checkout@v4"},
+			map[string]any{"name": "Install azd", "uses": "Azure/setup-azd@v1.0.0"},
+			map[string]any{"run": "npm test"},
+			map[string]any{"name": "Deploy Application", "run": "azd deploy --no-prompt"},
+		}, deploy["steps"])
+	})
+
+	t.Run("AddsMissingJob", func(t *testing.T) {
+		existing := `on: push
+jobs:
+  lint:
+    steps:
+      - run: npm run lint
+`
+		merged, changed, err := mergeWorkflow([]byte(existing), []byte(generatedWorkflow))
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		var workflow map[string]any
+		require.NoError(t, yaml.Unmarshal(merged, &workflow))
+
+		jobs := workflow["jobs"].(map[string]any)
+		require.Contains(t, jobs, "lint")
+		require.Contains(t, jobs, "build")
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		merged, changed, err := mergeWorkflow([]byte(generatedWorkflow), []byte(generatedWorkflow))
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Equal(t, generatedWorkflow, string(merged))
+	})
+
+	t.Run("NotAMapping", func(t *testing.T) {
+		_, _, err := mergeWorkflow([]byte("- step"), []byte(generatedWorkflow))
+		require.Error(t, err)
+	})
+}
+
+func Test_mergeWorkflow_EmbeddedWorkflowIsUnchanged(t *testing.T) {
+	merged, changed, err := mergeWorkflow(resources.GitHubWorkflow, resources.GitHubWorkflow)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, resources.GitHubWorkflow, merged)
+}
+
+func Test_gitHubWorkflow(t *testing.T) {
+	t.Run("NoStages", func(t *testing.T) {
+		generated, err := gitHubWorkflow(nil, "")
+		require.NoError(t, err)
+		require.Equal(t, resources.GitHubWorkflow, generated)
+	})
+
+	t.Run("Stages", func(t *testing.T) {
+		generated, err := gitHubWorkflow([]string{"dev", "prod west"}, "")
+		require.NoError(t, err)
+
+		var workflow map[string]any
+		require.NoError(t, yaml.Unmarshal(generated, &workflow))
+
+		jobs := workflow["jobs"].(map[string]any)
+		require.Len(t, jobs, 2)
+
+		dev := jobs["deploy-dev"].(map[string]any)
+		require.Equal(t, "dev", dev["environment"])
+		require.NotContains(t, dev, "needs")
+
+		prod := jobs["deploy-prod-west"].(map[string]any)
+		require.Equal(t, "prod west", prod["environment"])
+		require.Equal(t, "deploy-dev", prod["needs"])
+		require.Equal(t, dev["steps"], prod["steps"])
+	})
+
+	t.Run("MergesStagesIntoSingleJob", func(t *testing.T) {
+		generated, err := gitHubWorkflow([]string{"dev", "prod"}, "")
+		require.NoError(t, err)
+
+		merged, changed, err := mergeWorkflow(resources.GitHubWorkflow, generated)
+		require.NoError(t, err)
+		require.True(t, changed)
+
+		var workflow map[string]any
+		require.NoError(t, yaml.Unmarshal(merged, &workflow))
+
+		// The existing job becomes the first stage, the next stages need it
+		jobs := workflow["jobs"].(map[string]any)
+		require.Len(t, jobs, 2)
+		require.Equal(t, "dev", jobs["build"].(map[string]any)["environment"])
+		require.Equal(t, "build", jobs["deploy-prod"].(map[string]any)["needs"])
+
+		remerged, changed, err := mergeWorkflow(merged, generated)
+		require.NoError(t, err)
+		require.False(t, changed)
+		require.Equal(t, merged, remerged)
+	})
+
+	t.Run("Container", func(t *testing.T) {
+		image := "mcr.microsoft.com/azure-dev-cli-apps:1.5.0"
+		generated, err := gitHubWorkflow([]string{"dev"}, image)
+		require.NoError(t, err)
+		require.NotContains(t, string(generated), "setup-azd")
+
+		var workflow map[string]any
+		require.NoError(t, yaml.Unmarshal(generated, &workflow))
+
+		dev := workflow["jobs"].(map[string]any)["deploy-dev"].(map[string]any)
+		require.Equal(t, map[string]any{"image": image}, dev["container"])
+
+		// The existing workflow runs in the container, with the image updated along with azd
+		merged, changed, err := mergeWorkflow(resources.GitHubWorkflow, generated)
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.NotContains(t, string(merged), "setup-azd")
+
+		updated, err := gitHubWorkflow([]string{"dev"}, "mcr.microsoft.com/azure-dev-cli-apps:1.6.0")
+		require.NoError(t, err)
+
+		merged, changed, err = mergeWorkflow(merged, updated)
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Contains(t, string(merged), "image: mcr.microsoft.com/azure-dev-cli-apps:1.6.0")
+		require.NotContains(t, string(merged), "1.5.0")
+	})
+}
+
+func Test_ensureGitHubWorkflow(t *testing.T) {
+	existing := strings.Replace(
+		string(resources.GitHubWorkflow), "azd deploy --no-prompt", "azd deploy", 1) + `
+      - name: Smoke Test
+        run: npm run smoke-test
+`
+
+	writeWorkflow := func(t *testing.T) string {
+		projectDir := t.TempDir()
+		workflowPath := filepath.Join(projectDir, gitHubWorkflowPath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(workflowPath), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(workflowPath, []byte(existing), osutil.PermissionFile))
+		return projectDir
+	}
+
+	t.Run("Accepted", func(t *testing.T) {
+		projectDir := writeWorkflow(t)
+		console := mockinput.NewMockConsole()
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, gitHubWorkflowPath)
+		}).Respond(true)
+
+		require.NoError(t, ensureGitHubWorkflows(context.Background(), projectDir, nil, "", PrValidationNone, console))
+
+		updated, err := os.ReadFile(filepath.Join(projectDir, gitHubWorkflowPath))
+		require.NoError(t, err)
+		require.Contains(t, string(updated), "run: azd deploy --no-prompt")
+		require.Contains(t, string(updated), "run: npm run smoke-test")
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		projectDir := writeWorkflow(t)
+		console := mockinput.NewMockConsole()
+		console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return true
+		}).Respond(false)
+
+		require.NoError(t, ensureGitHubWorkflows(context.Background(), projectDir, nil, "", PrValidationNone, console))
+
+		unchanged, err := os.ReadFile(filepath.Join(projectDir, gitHubWorkflowPath))
+		require.NoError(t, err)
+		require.Equal(t, existing, string(unchanged))
+	})
+
+	t.Run("NoWorkflow", func(t *testing.T) {
+		projectDir := t.TempDir()
+
+		require.NoError(t, ensureGitHubWorkflows(context.Background(), projectDir, nil, "", "", mockinput.NewMockConsole()))
+
+		created, err := os.ReadFile(filepath.Join(projectDir, gitHubWorkflowPath))
+		require.NoError(t, err)
+		require.Equal(t, resources.GitHubWorkflow, created)
+
+		created, err = os.ReadFile(filepath.Join(projectDir, gitHubPrWorkflowPath))
+		require.NoError(t, err)
+		require.Equal(t, resources.GitHubPrWorkflow, created)
+	})
+
+	t.Run("NoPrValidation", func(t *testing.T) {
+		projectDir := t.TempDir()
+
+		require.NoError(t, ensureGitHubWorkflows(
+			context.Background(), projectDir, nil, "", PrValidationNone, mockinput.NewMockConsole()))
+
+		require.FileExists(t, filepath.Join(projectDir, gitHubWorkflowPath))
+		require.NoFileExists(t, filepath.Join(projectDir, gitHubPrWorkflowPath))
+	})
+}
+
+func Test_gitHubPrWorkflow(t *testing.T) {
+	t.Run("Build", func(t *testing.T) {
+		generated, err := gitHubPrWorkflow(PrValidationBuild, "")
+		require.NoError(t, err)
+		require.Equal(t, resources.GitHubPrWorkflow, generated)
+		require.NotContains(t, string(generated), "azd deploy")
+		require.NotContains(t, string(generated), "azd provision")
+	})
+
+	t.Run("Ephemeral", func(t *testing.T) {
+		generated, err := gitHubPrWorkflow(PrValidationEphemeral, "")
+		require.NoError(t, err)
+
+		var workflow map[string]any
+		require.NoError(t, yaml.Unmarshal(generated, &workflow))
+
+		validate := workflow["jobs"].(map[string]any)["validate"].(map[string]any)
+		require.Equal(t,
+			"${{ secrets.AZURE_ENV_NAME }}-pr${{ github.event.pull_request.number }}",
+			validate["env"].(map[string]any)["AZURE_ENV_NAME"])
+
+		steps := validate["steps"].([]any)
+		require.Equal(t, map[string]any{
+			"name": "Provision Ephemeral Environment",
+			"run":  "azd provision --no-prompt",
+		}, steps[len(steps)-2])
+		require.Equal(t, map[string]any{
+			"name": "Delete Ephemeral Environment",
+			"if":   "${{ always() }}",
+			"run":  "azd down --force --purge --no-prompt",
+		}, steps[len(steps)-1])
+		require.NotContains(t, string(generated), "azd deploy")
+
+		// The pull request workflow of the build mode is updated with the steps of the ephemeral environment
+		merged, changed, err := mergeWorkflow(resources.GitHubPrWorkflow, generated)
+		require.NoError(t, err)
+		require.True(t, changed)
+		require.Contains(t, string(merged), "azd down --force --purge --no-prompt")
+	})
+
+	t.Run("Container", func(t *testing.T) {
+		generated, err := gitHubPrWorkflow(PrValidationBuild, "mcr.microsoft.com/azure-dev-cli-apps:1.5.0")
+		require.NoError(t, err)
+		require.NotContains(t, string(generated), "setup-azd")
+		require.Contains(t, string(generated), "image: mcr.microsoft.com/azure-dev-cli-apps:1.5.0")
+	})
+}
@@ -108,6 +108,9 @@ type CiProvider interface {
 		credential json.RawMessage,
 		authType PipelineAuthType,
 	) error
+	// setValues adds or updates the secrets and the variables of the pipeline. With dryRun, it only checks that the
+	// pipeline exists.
+	setValues(ctx context.Context, repoDetails *gitRepositoryDetails, values []PipelineValue, dryRun bool) error
 }
 
 func folderExists(folderPath string) bool {
@@ -162,6 +165,7 @@ func resolveProvider(
 //   - none of the folders found: return error
 //   - no azd context in the ctx: return error
 //   - overrideProvider set to neither github or azdo: return error
+//   - gitHubHost is the host of the GitHub providers, github.com when empty or a GitHub Enterprise Server instance
 //   - Note: The provider is persisted in the environment so the next time the function is run
 //     the same provider is used directly, unless the overrideProvider is used to change
 //     the last used configuration
@@ -170,6 +174,7 @@ func DetectProviders(
 	azdContext *azdcontext.AzdContext,
 	env *environment.Environment,
 	overrideProvider string,
+	gitHubHost string,
 	console input.Console,
 	credential azcore.TokenCredential,
 	commandRunner exec.CommandRunner,
@@ -233,8 +238,8 @@ func DetectProviders(
 	// Or override value is github and the folder is available
 	_ = savePipelineProviderToEnv(gitHubLabel, env)
 	log.Printf("Using pipeline provider: %s", output.WithHighLightFormat("GitHub"))
-	scmProvider := NewGitHubScmProvider(commandRunner, console)
-	ciProvider := NewGitHubCiProvider(credential, commandRunner, console)
+	scmProvider := NewGitHubScmProvider(commandRunner, console, gitHubHost)
+	ciProvider := NewGitHubCiProvider(credential, commandRunner, console, gitHubHost, azdContext)
 	return scmProvider, ciProvider, nil
 }
 
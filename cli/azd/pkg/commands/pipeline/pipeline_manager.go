@@ -33,6 +33,18 @@ const (
 	AuthTypeClientCredentials PipelineAuthType = "client-credentials"
 )
 
+// PrValidationMode selects the workflow generated to validate the pull requests of the mainline branch.
+type PrValidationMode string
+
+const (
+	// PrValidationBuild runs the unit tests and packages the services, the default
+	PrValidationBuild PrValidationMode = "build"
+	// PrValidationEphemeral also provisions the infrastructure in an environment of the pull request, deleted after
+	PrValidationEphemeral PrValidationMode = "ephemeral"
+	// PrValidationNone doesn't generate the pull request workflow
+	PrValidationNone PrValidationMode = "none"
+)
+
 var ErrAuthNotSupported = errors.New("pipeline authentication configuration is not supported")
 
 type PipelineManagerArgs struct {
@@ -41,6 +53,12 @@ type PipelineManagerArgs struct {
 	PipelineRoleName             string
 	PipelineProvider             string
 	PipelineAuthTypeName         string
+	// SkipSecretScan pushes the changes even when files likely containing secrets would be committed.
+	SkipSecretScan bool
+	// GitHubHost is the host of the GitHub Enterprise Server instance to use instead of github.com.
+	GitHubHost string
+	// PrValidation is the PrValidationMode of the pull request workflow, PrValidationBuild when empty.
+	PrValidation string
 }
 
 type PipelineConfigResult struct {
@@ -69,13 +87,13 @@ func NewPipelineManager(
 	global *internal.GlobalCommandOptions,
 	commandRunner exec.CommandRunner,
 	console input.Console,
-	args PipelineManagerArgs,
+	args *PipelineManagerArgs,
 ) *PipelineManager {
 	return &PipelineManager{
 		AzdCtx:              azdCtx,
 		RootOptions:         global,
 		Environment:         env,
-		PipelineManagerArgs: args,
+		PipelineManagerArgs: *args,
 		azCli:               azCli,
 		commandRunner:       commandRunner,
 		console:             console,
@@ -114,6 +132,17 @@ func (i *PipelineManager) preConfigureCheck(ctx context.Context, infraOptions pr
 		)
 	}
 
+	validPrValidationModes := []string{
+		string(PrValidationBuild), string(PrValidationEphemeral), string(PrValidationNone),
+	}
+	if i.PrValidation != "" && !slices.Contains(validPrValidationModes, i.PrValidation) {
+		return configurationWasUpdated, fmt.Errorf(
+			"pull request validation '%s' is not valid. Valid values are '%s'",
+			i.PrValidation,
+			strings.Join(validPrValidationModes, ", "),
+		)
+	}
+
 	ciConfigurationWasUpdated, err := i.CiProvider.preConfigureCheck(
 		ctx, i.PipelineManagerArgs, infraOptions, projectPath)
 	if err != nil {
@@ -247,9 +276,49 @@ func validateDependencyInjection(ctx context.Context, manager *PipelineManager)
 }
 
 // pushGitRepo commit all changes in the git project and push it to upstream.
+// scanChangesForSecrets blocks the push when the changes about to be committed, the lines added to the tracked files
+// and the untracked files, likely contain secrets. The files already committed aren't scanned.
+func (i *PipelineManager) scanChangesForSecrets(ctx context.Context, gitCli git.GitCli) error {
+	projectDir := i.AzdCtx.ProjectDirectory()
+
+	diff, err := gitCli.DiffHead(ctx, projectDir)
+	if err != nil {
+		return fmt.Errorf("listing changes to scan for secrets: %w", err)
+	}
+
+	untracked, err := gitCli.ListUntrackedFiles(ctx, projectDir)
+	if err != nil {
+		return fmt.Errorf("listing files to scan for secrets: %w", err)
+	}
+
+	findings, err := scanForSecrets(projectDir, untracked)
+	if err != nil {
+		return fmt.Errorf("scanning for secrets: %w", err)
+	}
+
+	findings = append(scanDiffForSecrets(diff), findings...)
+
+	ignored, err := loadSecretScanIgnore(projectDir)
+	if err != nil {
+		return err
+	}
+
+	if findings = filterIgnoredFindings(findings, ignored); len(findings) > 0 {
+		return secretScanError(findings)
+	}
+
+	return nil
+}
+
 func (i *PipelineManager) pushGitRepo(ctx context.Context, currentBranch string) error {
 	gitCli := git.NewGitCli(i.commandRunner)
 
+	if !i.SkipSecretScan {
+		if err := i.scanChangesForSecrets(ctx, gitCli); err != nil {
+			return err
+		}
+	}
+
 	if err := gitCli.AddFile(ctx, i.AzdCtx.ProjectDirectory(), "."); err != nil {
 		return fmt.Errorf("adding files: %w", err)
 	}
@@ -421,3 +490,28 @@ func (manager *PipelineManager) Configure(ctx context.Context) (
 		PipelineLink:   ciPipeline.remote,
 	}, nil
 }
+
+// SetValues adds or updates the secrets and the variables of the pipeline of the git remote, without changing the
+// rest of its configuration. With dryRun, it only checks that the pipeline exists.
+func (manager *PipelineManager) SetValues(ctx context.Context, values []PipelineValue, dryRun bool) error {
+	validateDependencyInjection(ctx, manager)
+
+	gitRepoInfo, err := manager.ensureRemote(ctx, manager.AzdCtx.ProjectDirectory(), manager.PipelineRemoteName)
+	if err != nil {
+		return err
+	}
+
+	repoSlug := gitRepoInfo.owner + "/" + gitRepoInfo.repoName
+	displayMsg := fmt.Sprintf("Setting %d secrets and variables of %s", len(values), repoSlug)
+	if dryRun {
+		displayMsg = fmt.Sprintf("Checking the pipeline of %s", repoSlug)
+	}
+	err = input.RunWithSpinner(ctx, manager.console, displayMsg, func(ctx context.Context) error {
+		return manager.CiProvider.setValues(ctx, gitRepoInfo, values, dryRun)
+	})
+	if err != nil {
+		return fmt.Errorf("setting pipeline values with %s: %w", manager.CiProvider.name(), err)
+	}
+
+	return nil
+}
@@ -30,6 +30,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			environment.Ephemeral(),
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -53,6 +54,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			environment.Ephemeral(),
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -85,6 +87,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -111,6 +114,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -135,6 +139,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -160,6 +165,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -181,6 +187,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			&environment.Environment{Values: map[string]string{}},
 			"other",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -207,6 +214,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -230,7 +238,7 @@ func Test_detectProviders(t *testing.T) {
 			ctx,
 			azdContext,
 			environment.Ephemeral(),
-			"", mockContext.Console,
+			"", "", mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
 		)
@@ -263,6 +271,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -297,6 +306,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"arg",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -322,6 +332,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			environment.Ephemeral(),
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -342,6 +353,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			environment.Ephemeral(),
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -365,6 +377,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			environment.Ephemeral(),
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -388,6 +401,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			azdoLabel,
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -405,6 +419,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -430,6 +445,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			azdoLabel,
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -443,6 +459,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -461,6 +478,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -479,6 +497,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -493,6 +512,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			azdoLabel,
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
@@ -512,6 +532,7 @@ func Test_detectProviders(t *testing.T) {
 			azdContext,
 			env,
 			"",
+			"",
 			mockContext.Console,
 			mockContext.Credentials,
 			mockContext.CommandRunner,
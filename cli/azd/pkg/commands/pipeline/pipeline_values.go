@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultValuesMappingFile is the mapping file used by `azd pipeline set-secrets`, relative to the project directory
+const DefaultValuesMappingFile = "pipeline-secrets.yaml"
+
+// ValuesMapping maps the names of the secrets and the variables of the pipeline to the names of the azd environment
+// values they are set from, like:
+//
+//	secrets:
+//	  SQL_PASSWORD: AZURE_SQL_PASSWORD
+//	variables:
+//	  API_URL: SERVICE_API_URI
+type ValuesMapping struct {
+	Secrets   map[string]string `yaml:"secrets"`
+	Variables map[string]string `yaml:"variables"`
+}
+
+// PipelineValue is a secret or a variable of the pipeline set from an azd environment value.
+type PipelineValue struct {
+	// The name of the secret or the variable in the pipeline
+	Name string
+	// The name of the azd environment value
+	EnvName string
+	Value   string
+	Secret  bool
+}
+
+// LoadValuesMapping reads the mapping file at path.
+func LoadValuesMapping(path string) (*ValuesMapping, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading mapping file: %w", err)
+	}
+
+	var mapping ValuesMapping
+	if err := yaml.Unmarshal(content, &mapping); err != nil {
+		return nil, fmt.Errorf("parsing mapping file %s: %w", path, err)
+	}
+
+	for name := range mapping.Secrets {
+		if _, has := mapping.Variables[name]; has {
+			return nil, fmt.Errorf("'%s' is both a secret and a variable in mapping file %s", name, path)
+		}
+	}
+
+	return &mapping, nil
+}
+
+// Values resolves the secrets and the variables of the mapping from the azd environment, sorted by name. All the
+// environment values of the mapping must be set.
+func (m *ValuesMapping) Values(env *environment.Environment) ([]PipelineValue, error) {
+	var values []PipelineValue
+	var missing []string
+
+	add := func(mapping map[string]string, secret bool) {
+		for name, envName := range mapping {
+			value, has := env.LookupEnv(envName)
+			if !has {
+				missing = append(missing, envName)
+				continue
+			}
+
+			values = append(values, PipelineValue{Name: name, EnvName: envName, Value: value, Secret: secret})
+		}
+	}
+	add(m.Secrets, true)
+	add(m.Variables, false)
+
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, errors.New(
+			"the environment doesn't have the values: " + strings.Join(slices.Compact(missing), ", "))
+	}
+
+	slices.SortFunc(values, func(a, b PipelineValue) bool {
+		return a.Name < b.Name
+	})
+
+	return values, nil
+}
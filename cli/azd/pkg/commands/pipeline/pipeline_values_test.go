@@ -0,0 +1,75 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writeValuesMapping(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), DefaultValuesMappingFile)
+	require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	return path
+}
+
+func Test_ValuesMapping(t *testing.T) {
+	t.Run("Values", func(t *testing.T) {
+		mapping, err := LoadValuesMapping(writeValuesMapping(t, `
+secrets:
+  SQL_PASSWORD: AZURE_SQL_PASSWORD
+variables:
+  API_URL: SERVICE_API_URI
+  APP_NAME: SERVICE_API_URI
+`))
+		require.NoError(t, err)
+
+		env := environment.EphemeralWithValues("dev", map[string]string{
+			"AZURE_SQL_PASSWORD": "p@ss",
+			"SERVICE_API_URI":    "https://api.contoso.com",
+		})
+
+		values, err := mapping.Values(env)
+		require.NoError(t, err)
+		require.Equal(t, []PipelineValue{
+			{Name: "API_URL", EnvName: "SERVICE_API_URI", Value: "https://api.contoso.com"},
+			{Name: "APP_NAME", EnvName: "SERVICE_API_URI", Value: "https://api.contoso.com"},
+			{Name: "SQL_PASSWORD", EnvName: "AZURE_SQL_PASSWORD", Value: "p@ss", Secret: true},
+		}, values)
+	})
+
+	t.Run("MissingValues", func(t *testing.T) {
+		mapping, err := LoadValuesMapping(writeValuesMapping(t, `
+secrets:
+  SQL_PASSWORD: AZD_TEST_MISSING_PASSWORD
+  SQL_ADMIN_PASSWORD: AZD_TEST_MISSING_PASSWORD
+variables:
+  API_URL: AZD_TEST_MISSING_URI
+`))
+		require.NoError(t, err)
+
+		_, err = mapping.Values(environment.EphemeralWithValues("dev", nil))
+		require.ErrorContains(t, err, "AZD_TEST_MISSING_PASSWORD, AZD_TEST_MISSING_URI")
+	})
+
+	t.Run("SecretAndVariable", func(t *testing.T) {
+		_, err := LoadValuesMapping(writeValuesMapping(t, `
+secrets:
+  API_KEY: AZURE_API_KEY
+variables:
+  API_KEY: AZURE_API_KEY
+`))
+		require.ErrorContains(t, err, "'API_KEY' is both a secret and a variable")
+	})
+
+	t.Run("MissingFile", func(t *testing.T) {
+		_, err := LoadValuesMapping(filepath.Join(t.TempDir(), DefaultValuesMappingFile))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
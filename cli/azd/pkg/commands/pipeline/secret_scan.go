@@ -0,0 +1,330 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pipeline
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Files larger than this are not scanned, secrets are found in configuration files, not in large assets.
+const maxSecretScanFileSize = 1024 * 1024
+
+// The maximum number of findings listed in the error blocking the push.
+const maxReportedSecretFindings = 10
+
+// SecretScanIgnoreFile lists the files, relative to the project directory, which are never reported by the secret scan.
+// Each line is a path or a glob pattern, like test/fixtures/*.pem, a pattern without a slash matches the name of the
+// file in any directory and a pattern ending with a slash matches a directory. Lines starting with # are comments.
+const SecretScanIgnoreFile = ".azdsecretignore"
+
+// secretFinding is a file, or a line of a file, which likely contains a secret.
+type secretFinding struct {
+	// The path of the file, relative to the root of the repository, using forward slashes.
+	path string
+	// The line of the secret, 0 when the whole file is a secret.
+	line   int
+	reason string
+}
+
+func (f secretFinding) String() string {
+	if f.line == 0 {
+		return fmt.Sprintf("%s: %s", f.path, f.reason)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", f.path, f.line, f.reason)
+}
+
+// secretFileDetectors find files which hold secrets by their name.
+var secretFileDetectors = []struct {
+	reason string
+	match  func(filePath string) bool
+}{
+	{
+		reason: "azd environment files contain the outputs of provisioning, which may include secrets",
+		match: func(filePath string) bool {
+			return strings.HasPrefix(filePath, ".azure/") || strings.Contains(filePath, "/.azure/")
+		},
+	},
+	{
+		reason: ".env files usually contain secrets",
+		match: func(filePath string) bool {
+			name := path.Base(filePath)
+			if name != ".env" && !strings.HasPrefix(name, ".env.") {
+				return false
+			}
+
+			switch path.Ext(name) {
+			case ".example", ".sample", ".template", ".dist":
+				return false
+			}
+
+			return true
+		},
+	},
+	{
+		reason: "local settings files contain the connection strings of local development",
+		match: func(filePath string) bool {
+			return path.Base(filePath) == "local.settings.json"
+		},
+	},
+	{
+		reason: "private key file",
+		match: func(filePath string) bool {
+			name := path.Base(filePath)
+			switch path.Ext(name) {
+			case ".pem", ".key", ".pfx", ".p12":
+				return true
+			}
+
+			return name == "id_rsa" || name == "id_ecdsa" || name == "id_ed25519"
+		},
+	},
+}
+
+// secretContentDetectors find secrets in the lines of a file.
+var secretContentDetectors = []struct {
+	reason string
+	match  *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED )?PRIVATE KEY-----`)},
+	{"storage account key", regexp.MustCompile(`(?i)AccountKey=[A-Za-z0-9+/=]{20,}`)},
+	{"shared access key", regexp.MustCompile(`(?i)SharedAccessKey=[A-Za-z0-9+/=]{20,}`)},
+	{"connection string password", regexp.MustCompile(`(?i)(?:Server|Data Source)=[^;]+;.*Password=[^;\s]+`)},
+	{"shared access signature", regexp.MustCompile(`[?&]sig=[A-Za-z0-9%+/=]{20,}`)},
+}
+
+// scanForSecrets scans the files of the repository at root, specified relative to the root, for obvious secrets.
+// Files which don't exist, like deleted tracked files, are skipped.
+func scanForSecrets(root string, files []string) ([]secretFinding, error) {
+	findings := []secretFinding{}
+
+	for _, file := range files {
+		filePath := filepath.ToSlash(file)
+
+		if finding, found := detectSecretFile(filePath); found {
+			findings = append(findings, finding)
+			continue
+		}
+
+		fileFindings, err := scanFileForSecrets(filepath.Join(root, file), filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, fileFindings...)
+	}
+
+	return findings, nil
+}
+
+// scanDiffForSecrets scans the lines added by a unified diff, like the output of git diff, for obvious secrets. The
+// files added or changed by the diff are also checked by name, the lines removed or already committed aren't scanned.
+func scanDiffForSecrets(diff string) []secretFinding {
+	findings := []secretFinding{}
+
+	var filePath string
+	inHeader := false
+	skipFile := false
+	line := 0
+
+	for _, text := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			inHeader = true
+			filePath = ""
+		case inHeader && strings.HasPrefix(text, "+++ "):
+			filePath = diffPath(strings.TrimPrefix(text, "+++ "))
+			skipFile = filePath == ""
+
+			if finding, found := detectSecretFile(filePath); found && !skipFile {
+				findings = append(findings, finding)
+				skipFile = true
+			}
+		case strings.HasPrefix(text, "@@ "):
+			inHeader = false
+			line = hunkStartLine(text)
+		case !inHeader && strings.HasPrefix(text, "+"):
+			if !skipFile {
+				if reason, found := detectSecretLine(text[1:]); found {
+					findings = append(findings, secretFinding{path: filePath, line: line, reason: reason})
+				}
+			}
+
+			line++
+		}
+	}
+
+	return findings
+}
+
+// diffPath returns the path of the file of a +++ line of a diff, empty for a deleted file.
+func diffPath(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+	}
+
+	if value == "/dev/null" {
+		return ""
+	}
+
+	return strings.TrimPrefix(value, "b/")
+}
+
+// hunkStartLine returns the first line of the new file in a hunk header, like @@ -1,2 +3,4 @@.
+func hunkStartLine(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0
+	}
+
+	start, _, _ := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+	line, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+
+	return line
+}
+
+func detectSecretFile(filePath string) (secretFinding, bool) {
+	for _, detector := range secretFileDetectors {
+		if detector.match(filePath) {
+			return secretFinding{path: filePath, reason: detector.reason}, true
+		}
+	}
+
+	return secretFinding{}, false
+}
+
+func detectSecretLine(text string) (string, bool) {
+	for _, detector := range secretContentDetectors {
+		if detector.match.MatchString(text) {
+			return detector.reason, true
+		}
+	}
+
+	return "", false
+}
+
+func scanFileForSecrets(fullPath string, filePath string) ([]secretFinding, error) {
+	info, err := os.Stat(fullPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", filePath, err)
+	}
+
+	if info.IsDir() || info.Size() > maxSecretScanFileSize {
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", filePath, err)
+	}
+
+	// Binary files are skipped
+	if bytes.IndexByte(contents, 0) != -1 {
+		return nil, nil
+	}
+
+	findings := []secretFinding{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSecretScanFileSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		if reason, found := detectSecretLine(scanner.Text()); found {
+			findings = append(findings, secretFinding{path: filePath, line: line, reason: reason})
+		}
+	}
+
+	return findings, nil
+}
+
+// loadSecretScanIgnore reads the patterns of the SecretScanIgnoreFile of the project, none when the file doesn't exist.
+func loadSecretScanIgnore(projectDir string) ([]string, error) {
+	contents, err := os.ReadFile(filepath.Join(projectDir, SecretScanIgnoreFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", SecretScanIgnoreFile, err)
+	}
+
+	patterns := []string{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, strings.TrimPrefix(filepath.ToSlash(line), "/"))
+		}
+	}
+
+	return patterns, nil
+}
+
+// filterIgnoredFindings removes the findings of the files matching the ignore patterns.
+func filterIgnoredFindings(findings []secretFinding, patterns []string) []secretFinding {
+	kept := []secretFinding{}
+	for _, finding := range findings {
+		if !isIgnoredFile(finding.path, patterns) {
+			kept = append(kept, finding)
+		}
+	}
+
+	return kept
+}
+
+func isIgnoredFile(filePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		switch {
+		case strings.HasSuffix(pattern, "/"):
+			if strings.HasPrefix(filePath, pattern) || strings.Contains(filePath, "/"+pattern) {
+				return true
+			}
+		case !strings.Contains(pattern, "/"):
+			if matched, _ := path.Match(pattern, path.Base(filePath)); matched {
+				return true
+			}
+		default:
+			if matched, _ := path.Match(pattern, filePath); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// secretScanError is returned when the push is blocked because files likely containing secrets would be committed.
+func secretScanError(findings []secretFinding) error {
+	var message strings.Builder
+	message.WriteString("the push was blocked because these files may contain secrets:\n")
+
+	for i, finding := range findings {
+		if i == maxReportedSecretFindings {
+			fmt.Fprintf(&message, "  ... and %d more\n", len(findings)-maxReportedSecretFindings)
+			break
+		}
+
+		fmt.Fprintf(&message, "  %s\n", finding)
+	}
+
+	message.WriteString(fmt.Sprintf("Add the files to .gitignore and remove the secrets. If they don't contain secrets, "+
+		"list them in %s or run again with --skip-secret-scan", SecretScanIgnoreFile))
+
+	return errors.New(message.String())
+}
@@ -0,0 +1,161 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package compose
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The port ASP.NET Core listens on in containers, used for the projects of Aspire manifests.
+const aspNetCorePort = 8080
+
+// aspireManifest is the manifest published by an Aspire app host, with `dotnet run --publisher manifest`.
+// https://learn.microsoft.com/dotnet/aspire/deployment/manifest-format
+type aspireManifest struct {
+	Resources map[string]aspireResource `json:"resources"`
+}
+
+type aspireResource struct {
+	Type string `json:"type"`
+	// The .csproj of project.v0 resources, or the Dockerfile of dockerfile.v0 resources
+	Path string `json:"path"`
+	// The build context of dockerfile.v0 resources
+	Context  string                   `json:"context"`
+	Image    string                   `json:"image"`
+	Env      map[string]string        `json:"env"`
+	Bindings map[string]aspireBinding `json:"bindings"`
+}
+
+type aspireBinding struct {
+	Scheme        string `json:"scheme"`
+	ContainerPort int    `json:"containerPort"`
+	TargetPort    int    `json:"targetPort"`
+	External      bool   `json:"external"`
+}
+
+// isAspireManifest returns true when the JSON file is an Aspire manifest, as manifest.json is a common file name.
+func isAspireManifest(filePath string) bool {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+
+	var manifest aspireManifest
+	return json.Unmarshal(content, &manifest) == nil && len(manifest.Resources) > 0
+}
+
+func loadAspireManifest(filePath string) (*Application, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading Aspire manifest: %w", err)
+	}
+
+	var manifest aspireManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing Aspire manifest '%s': %w", filePath, err)
+	}
+
+	app := &Application{Source: filePath}
+	for name, resource := range manifest.Resources {
+		service := &Service{
+			Name: name,
+			Env:  map[string]string{},
+		}
+
+		switch resource.Type {
+		case "project.v0":
+			service.Context = filepath.ToSlash(filepath.Dir(filepath.Clean(resource.Path)))
+			service.DotNetProject = filepath.ToSlash(filepath.Clean(resource.Path))
+		case "dockerfile.v0":
+			service.Context = filepath.ToSlash(filepath.Clean(resource.Context))
+			dockerfile, err := filepath.Rel(resource.Context, resource.Path)
+			if err != nil {
+				return nil, fmt.Errorf("resource '%s' of '%s': %w", name, filePath, err)
+			}
+			service.Dockerfile = filepath.ToSlash(dockerfile)
+		case "container.v0":
+			service.Image = resource.Image
+		default:
+			// Parameters, connection strings and the resources of hosting integrations, like Redis or Postgres,
+			// are provisioned by their own Azure services
+			app.Warnings = append(app.Warnings, fmt.Sprintf(
+				"resource '%s' of type '%s' isn't translated, add the Azure service it needs to the infrastructure",
+				name, resource.Type))
+			continue
+		}
+
+		for envName, value := range resource.Env {
+			// Expressions reference the other resources of the manifest, like {cache.connectionString}
+			if strings.Contains(value, "{") {
+				app.Warnings = append(app.Warnings, fmt.Sprintf(
+					"the value of %s of resource '%s' references other resources and isn't translated: %s",
+					envName, name, value))
+				continue
+			}
+
+			service.Env[envName] = value
+		}
+
+		for _, binding := range sortedBindings(resource.Bindings) {
+			port := binding.ContainerPort
+			if port == 0 {
+				port = binding.TargetPort
+			}
+			if port == 0 && resource.Type == "project.v0" {
+				port = aspNetCorePort
+			}
+
+			if port != 0 {
+				service.Port = port
+				service.External = binding.External
+				break
+			}
+		}
+
+		app.Services = append(app.Services, service)
+	}
+
+	if len(app.Services) == 0 {
+		return nil, fmt.Errorf("aspire manifest '%s' has no projects or containers", filePath)
+	}
+
+	sortServices(app.Services)
+	return app, nil
+}
+
+// sortedBindings returns the bindings with the http bindings first, as they are the ones served by the ingress of
+// container apps, and the others by name.
+func sortedBindings(bindings map[string]aspireBinding) []aspireBinding {
+	var names []string
+	for name := range bindings {
+		names = append(names, name)
+	}
+
+	rank := func(name string) string {
+		switch bindings[name].Scheme {
+		case "https":
+			return "0" + name
+		case "http":
+			return "1" + name
+		default:
+			return "2" + name
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return rank(names[i]) < rank(names[j])
+	})
+
+	result := make([]aspireBinding, 0, len(names))
+	for _, name := range names {
+		result = append(result, bindings[name])
+	}
+
+	return result
+}
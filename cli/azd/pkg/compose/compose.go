@@ -0,0 +1,324 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package compose translates the services of a docker compose file, or of a .NET Aspire manifest, into azd services
+// hosted on Azure Container Apps, with the infrastructure to provision them.
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// The files an application is read from, in order of precedence
+var (
+	composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+	aspireFileNames  = []string{"aspire-manifest.json", "manifest.json"}
+)
+
+// Application is the set of services read from a compose file or an Aspire manifest.
+type Application struct {
+	// The file the application was read from
+	Source string
+	// The services, sorted by name
+	Services []*Service
+	// The settings which couldn't be translated, like volumes, reported to the user
+	Warnings []string
+}
+
+// Service is a service of an application. Services are either built from a Dockerfile, and become azd services, or
+// run from an existing image.
+type Service struct {
+	Name string
+	// The build context of built services, relative to the project directory
+	Context string
+	// The Dockerfile of built services, relative to the build context
+	Dockerfile string
+	// The language of built services, detected from the files of the build context
+	Language project.ServiceLanguageKind
+	// The image of services which aren't built
+	Image string
+	// The environment variables, by name
+	Env map[string]string
+	// The port the service listens on, 0 when the service isn't reachable
+	Port int
+	// True when the port is published outside of the application
+	External bool
+	// The .NET project of the projects of Aspire manifests, relative to the project directory
+	DotNetProject string
+	// True when the service has no Dockerfile and Generate writes one, only for the projects of Aspire manifests
+	GenerateDockerfile bool
+}
+
+// Built returns true when the service is built from a Dockerfile.
+func (s *Service) Built() bool {
+	return s.Context != ""
+}
+
+// Detect returns the path of the compose file or the Aspire manifest of the project directory, or an empty string
+// when the project has neither.
+func Detect(projectDir string) (string, error) {
+	for _, fileName := range append(slices.Clone(composeFileNames), aspireFileNames...) {
+		filePath := filepath.Join(projectDir, fileName)
+		if _, err := os.Stat(filePath); err == nil {
+			if slices.Contains(aspireFileNames, fileName) && !isAspireManifest(filePath) {
+				continue
+			}
+
+			return filePath, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// Load reads the application from a compose file or an Aspire manifest. The paths of the services are relative to the
+// directory of the file.
+func Load(filePath string) (*Application, error) {
+	var app *Application
+	var err error
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		app, err = loadAspireManifest(filePath)
+	} else {
+		app, err = loadComposeFile(filePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range app.Services {
+		if !service.Built() {
+			continue
+		}
+
+		contextDir := filepath.Join(filepath.Dir(filePath), service.Context)
+		dockerfile := service.Dockerfile
+		if dockerfile == "" {
+			dockerfile = "Dockerfile"
+		}
+		if _, err := os.Stat(filepath.Join(contextDir, dockerfile)); err != nil {
+			if service.DotNetProject != "" && service.Dockerfile == "" {
+				// The projects of Aspire manifests are built by the .NET SDK, without a Dockerfile
+				service.GenerateDockerfile = true
+				app.Warnings = append(app.Warnings, fmt.Sprintf(
+					"service '%s' has no Dockerfile in %s, one publishing %s is generated",
+					service.Name, contextDir, service.DotNetProject))
+			} else {
+				app.Warnings = append(app.Warnings, fmt.Sprintf(
+					"service '%s' has no %s in %s, add one to build its container image", service.Name, dockerfile, contextDir))
+			}
+		}
+
+		service.Language = detectLanguage(contextDir)
+		if service.Language == "" {
+			service.Language = project.ServiceLanguagePython
+			app.Warnings = append(app.Warnings, fmt.Sprintf(
+				"the language of service '%s' couldn't be detected, update its language in azure.yaml", service.Name))
+		}
+	}
+
+	return app, nil
+}
+
+// detectLanguage returns the language of the project in the directory from its manifest, like package.json, or an
+// empty string when the language is unknown.
+func detectLanguage(dir string) project.ServiceLanguageKind {
+	has := func(pattern string) bool {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		return len(matches) > 0
+	}
+
+	switch {
+	case has("*.csproj"), has("*.fsproj"):
+		return project.ServiceLanguageDotNet
+	case has("package.json") && has("tsconfig.json"):
+		return project.ServiceLanguageTypeScript
+	case has("package.json"):
+		return project.ServiceLanguageJavaScript
+	case has("requirements.txt"), has("pyproject.toml"):
+		return project.ServiceLanguagePython
+	case has("pom.xml"), has("build.gradle"), has("build.gradle.kts"):
+		return project.ServiceLanguageJava
+	case has("go.mod"):
+		return project.ServiceLanguageGo
+	}
+
+	return ""
+}
+
+// composeFile is the subset of the compose specification translated to Container Apps.
+// https://github.com/compose-spec/compose-spec/blob/master/spec.md
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Build       composeBuild       `yaml:"build"`
+	Image       string             `yaml:"image"`
+	Ports       []composePort      `yaml:"ports"`
+	Expose      []composePort      `yaml:"expose"`
+	Environment composeEnvironment `yaml:"environment"`
+	Volumes     []yaml.Node        `yaml:"volumes"`
+}
+
+// composeBuild is either the build context, or the build options.
+type composeBuild struct {
+	Context    string `yaml:"context"`
+	Dockerfile string `yaml:"dockerfile"`
+}
+
+func (b *composeBuild) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		b.Context = node.Value
+		return nil
+	}
+
+	type options composeBuild
+	return node.Decode((*options)(b))
+}
+
+// composePort is a port, like 80, "8080:80", "127.0.0.1:8080:80/tcp" or {target: 80, published: 8080}. Only the port
+// of the container is kept.
+type composePort struct {
+	Target    int
+	Published bool
+}
+
+func (p *composePort) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.MappingNode {
+		var long struct {
+			Target    int    `yaml:"target"`
+			Published string `yaml:"published"`
+		}
+		if err := node.Decode(&long); err != nil {
+			return err
+		}
+
+		p.Target = long.Target
+		p.Published = long.Published != ""
+		return nil
+	}
+
+	value := strings.TrimSuffix(strings.TrimSuffix(node.Value, "/tcp"), "/udp")
+	parts := strings.Split(value, ":")
+	target := parts[len(parts)-1]
+	// Ranges, like 3000-3005, are reduced to their first port
+	target, _, _ = strings.Cut(target, "-")
+
+	port, err := strconv.Atoi(target)
+	if err != nil {
+		return fmt.Errorf("invalid port '%s'", node.Value)
+	}
+
+	p.Target = port
+	p.Published = len(parts) > 1
+	return nil
+}
+
+// composeEnvironment is either a map of variables, or a list of KEY=value variables.
+type composeEnvironment map[string]string
+
+func (e *composeEnvironment) UnmarshalYAML(node *yaml.Node) error {
+	env := composeEnvironment{}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		var values map[string]*string
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+
+		for name, value := range values {
+			if value != nil {
+				env[name] = *value
+			}
+		}
+	case yaml.SequenceNode:
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+
+		for _, value := range values {
+			if name, value, has := strings.Cut(value, "="); has {
+				env[name] = value
+			}
+		}
+	}
+
+	*e = env
+	return nil
+}
+
+func loadComposeFile(filePath string) (*Application, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading compose file: %w", err)
+	}
+
+	var file composeFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("parsing compose file '%s': %w", filePath, err)
+	}
+
+	app := &Application{Source: filePath}
+	for name, composeService := range file.Services {
+		service := &Service{
+			Name:  name,
+			Image: composeService.Image,
+			Env:   composeService.Environment,
+		}
+
+		if composeService.Build.Context != "" {
+			service.Context = filepath.ToSlash(filepath.Clean(composeService.Build.Context))
+			service.Dockerfile = composeService.Build.Dockerfile
+			service.Image = ""
+		}
+
+		if !service.Built() && service.Image == "" {
+			return nil, fmt.Errorf("service '%s' of '%s' has neither a build nor an image", name, filePath)
+		}
+
+		if len(composeService.Ports) > 0 {
+			service.Port = composeService.Ports[0].Target
+			service.External = composeService.Ports[0].Published
+		} else if len(composeService.Expose) > 0 {
+			service.Port = composeService.Expose[0].Target
+		}
+
+		if len(composeService.Volumes) > 0 {
+			app.Warnings = append(app.Warnings, fmt.Sprintf(
+				"the volumes of service '%s' aren't translated, use Azure Files or a managed database instead", name))
+		}
+
+		if service.Env == nil {
+			service.Env = map[string]string{}
+		}
+
+		app.Services = append(app.Services, service)
+	}
+
+	if len(app.Services) == 0 {
+		return nil, fmt.Errorf("compose file '%s' has no services", filePath)
+	}
+
+	sortServices(app.Services)
+	return app, nil
+}
+
+func sortServices(services []*Service) {
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Name < services[j].Name
+	})
+}
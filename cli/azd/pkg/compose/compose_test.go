@@ -0,0 +1,335 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+const testComposeFile = `
+services:
+  web:
+    build: ./web
+    ports:
+      - "3000:3000"
+    environment:
+      - API_URL=http://api:8080
+  api:
+    build:
+      context: api
+      dockerfile: Dockerfile.prod
+    expose:
+      - "8080"
+    environment:
+      LOG_LEVEL: debug
+      UNSET:
+  cache:
+    image: redis:7
+    ports:
+      - target: 6379
+    volumes:
+      - cache-data:/data
+`
+
+func TestLoadComposeFile(t *testing.T) {
+	projectDir := t.TempDir()
+	writeFiles(t, projectDir, map[string]string{
+		"compose.yaml":         testComposeFile,
+		"web/package.json":     "{}",
+		"web/Dockerfile":       "FROM node:18",
+		"api/requirements.txt": "flask",
+		"api/Dockerfile.prod":  "FROM python:3.11",
+	})
+
+	filePath, err := Detect(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(projectDir, "compose.yaml"), filePath)
+
+	app, err := Load(filePath)
+	require.NoError(t, err)
+
+	require.Equal(t, []*Service{
+		{
+			Name:       "api",
+			Context:    "api",
+			Dockerfile: "Dockerfile.prod",
+			Language:   project.ServiceLanguagePython,
+			Env:        map[string]string{"LOG_LEVEL": "debug"},
+			Port:       8080,
+		},
+		{
+			Name:  "cache",
+			Image: "redis:7",
+			Env:   map[string]string{},
+			Port:  6379,
+		},
+		{
+			Name:     "web",
+			Context:  "web",
+			Language: project.ServiceLanguageJavaScript,
+			Env:      map[string]string{"API_URL": "http://api:8080"},
+			Port:     3000,
+			External: true,
+		},
+	}, app.Services)
+	require.Equal(t, []string{
+		"the volumes of service 'cache' aren't translated, use Azure Files or a managed database instead",
+	}, app.Warnings)
+}
+
+func TestLoadComposeFileInvalid(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		err     string
+	}{
+		"NoServices":     {content: "services: {}", err: "has no services"},
+		"NoBuildOrImage": {content: "services:\n  web:\n    ports: ['80']", err: "has neither a build nor an image"},
+		"InvalidPort":    {content: "services:\n  web:\n    image: nginx\n    ports: ['http']", err: "invalid port 'http'"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "docker-compose.yml")
+			require.NoError(t, os.WriteFile(filePath, []byte(test.content), osutil.PermissionFile))
+
+			_, err := Load(filePath)
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func TestLoadAspireManifest(t *testing.T) {
+	projectDir := t.TempDir()
+	writeFiles(t, projectDir, map[string]string{
+		"manifest.json": `{
+  "resources": {
+    "cache": {
+      "type": "redis.v0"
+    },
+    "apiservice": {
+      "type": "project.v0",
+      "path": "ApiService/ApiService.csproj",
+      "env": {
+        "OTEL_DOTNET_EXPERIMENTAL_OTLP_EMIT_EXCEPTION_LOG_ATTRIBUTES": "true"
+      },
+      "bindings": {
+        "http": {"scheme": "http", "protocol": "tcp", "transport": "http"},
+        "https": {"scheme": "https", "protocol": "tcp", "transport": "http"}
+      }
+    },
+    "worker": {
+      "type": "project.v0",
+      "path": "Worker/Worker.csproj"
+    },
+    "frontend": {
+      "type": "dockerfile.v0",
+      "path": "frontend/docker/Dockerfile",
+      "context": "frontend",
+      "env": {
+        "API_URL": "{apiservice.bindings.http.url}"
+      },
+      "bindings": {
+        "http": {"scheme": "http", "protocol": "tcp", "transport": "http", "containerPort": 3000, "external": true}
+      }
+    }
+  }
+}`,
+		"ApiService/ApiService.csproj":  "<Project />",
+		"ApiService/Dockerfile":         "FROM mcr.microsoft.com/dotnet/aspnet:8.0",
+		"frontend/package.json":         "{}",
+		"frontend/tsconfig.json":        "{}",
+		"frontend/docker/Dockerfile":    "FROM node:18",
+		"frontend/docker/.dockerignore": "node_modules",
+		"Worker/Worker.csproj":          "<Project />",
+	})
+
+	filePath, err := Detect(projectDir)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(projectDir, "manifest.json"), filePath)
+
+	app, err := Load(filePath)
+	require.NoError(t, err)
+
+	require.Equal(t, []*Service{
+		{
+			Name:     "apiservice",
+			Context:  "ApiService",
+			Language: project.ServiceLanguageDotNet,
+			Env:      map[string]string{"OTEL_DOTNET_EXPERIMENTAL_OTLP_EMIT_EXCEPTION_LOG_ATTRIBUTES": "true"},
+			Port:     aspNetCorePort,
+
+			DotNetProject: "ApiService/ApiService.csproj",
+		},
+		{
+			Name:       "frontend",
+			Context:    "frontend",
+			Dockerfile: "docker/Dockerfile",
+			Language:   project.ServiceLanguageTypeScript,
+			Env:        map[string]string{},
+			Port:       3000,
+			External:   true,
+		},
+		{
+			Name:     "worker",
+			Context:  "Worker",
+			Language: project.ServiceLanguageDotNet,
+			Env:      map[string]string{},
+
+			DotNetProject:      "Worker/Worker.csproj",
+			GenerateDockerfile: true,
+		},
+	}, app.Services)
+	require.Len(t, app.Warnings, 3)
+}
+
+func TestGenerateDotNetDockerfile(t *testing.T) {
+	projectDir := t.TempDir()
+	app := &Application{
+		Services: []*Service{
+			{
+				Name:               "worker",
+				Context:            "src/Worker",
+				Language:           project.ServiceLanguageDotNet,
+				DotNetProject:      "src/Worker/Worker.csproj",
+				GenerateDockerfile: true,
+			},
+		},
+	}
+
+	require.NoError(t, Generate(projectDir, "shop", app))
+
+	projectFile, err := os.ReadFile(filepath.Join(projectDir, "azure.yaml"))
+	require.NoError(t, err)
+	require.Contains(t, string(projectFile), `        docker:
+            path: ./Dockerfile
+            context: ../..
+`)
+
+	dockerfile, err := os.ReadFile(filepath.Join(projectDir, "src", "Worker", "Dockerfile"))
+	require.NoError(t, err)
+	require.Contains(t, string(dockerfile), `RUN dotnet publish "src/Worker/Worker.csproj"`)
+	require.Contains(t, string(dockerfile), `ENTRYPOINT ["dotnet", "Worker.dll"]`)
+}
+
+func TestDetectNone(t *testing.T) {
+	projectDir := t.TempDir()
+	// manifest.json files which aren't Aspire manifests are ignored
+	writeFiles(t, projectDir, map[string]string{"manifest.json": `{"name": "app"}`})
+
+	filePath, err := Detect(projectDir)
+	require.NoError(t, err)
+	require.Empty(t, filePath)
+}
+
+func TestGenerate(t *testing.T) {
+	projectDir := t.TempDir()
+	app := &Application{
+		Services: []*Service{
+			{
+				Name:       "api",
+				Context:    "api",
+				Dockerfile: "Dockerfile.prod",
+				Language:   project.ServiceLanguagePython,
+				Env:        map[string]string{"GREETING": "it's ${name}"},
+				Port:       8080,
+			},
+			{
+				Name:  "Cache_1",
+				Image: "redis:7",
+				Port:  6379,
+			},
+			{
+				Name:     "web",
+				Context:  ".",
+				Language: project.ServiceLanguageJavaScript,
+				Port:     3000,
+				External: true,
+			},
+		},
+	}
+
+	require.NoError(t, Generate(projectDir, "shop", app))
+
+	projectFile, err := os.ReadFile(filepath.Join(projectDir, "azure.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, project.SchemaAnnotation+`
+
+name: shop
+services:
+    api:
+        project: ./api
+        language: python
+        host: containerapp
+        docker:
+            path: ./Dockerfile.prod
+    web:
+        project: ./
+        language: js
+        host: containerapp
+`, string(projectFile))
+
+	resources, err := os.ReadFile(filepath.Join(projectDir, "infra", "resources.bicep"))
+	require.NoError(t, err)
+	require.Contains(t, string(resources), `resource apiApp 'Microsoft.App/containerApps@2023-05-01' = {
+  name: 'api'
+  location: location
+  tags: union(tags, { 'azd-service-name': 'api' })
+  properties: {
+    managedEnvironmentId: containerAppsEnvironment.id
+    configuration: {
+      ingress: {
+        external: false
+        targetPort: 8080
+      }
+      registries: [`)
+	require.Contains(t, string(resources), `          name: 'api'
+          image: '`+placeholderImage+`'
+          env: [
+            {
+              name: 'GREETING'
+              value: 'it\'s \${name}'
+            }
+          ]`)
+	require.Contains(t, string(resources), `resource cache_1App 'Microsoft.App/containerApps@2023-05-01' = {
+  name: 'cache-1'`)
+	require.Contains(t, string(resources), `          name: 'cache-1'
+          image: 'redis:7'
+        }`)
+
+	for _, path := range []string{mainBicepPath, parametersPath} {
+		require.FileExists(t, filepath.Join(projectDir, path))
+	}
+
+	// Existing files are never overwritten
+	err = Generate(projectDir, "shop", app)
+	require.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestContainerAppName(t *testing.T) {
+	tests := map[string]string{
+		"web":     "web",
+		"Web_API": "web-api",
+		"1st":     "app-1st",
+		"--":      "app",
+		"a-very-long-service-name-for-testing-limits": "a-very-long-service-name-for-tes",
+	}
+
+	for name, expected := range tests {
+		require.Equal(t, expected, containerAppName(name))
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	}
+}
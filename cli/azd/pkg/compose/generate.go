@@ -0,0 +1,398 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package compose
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"gopkg.in/yaml.v3"
+)
+
+// The image the container apps of built services run until azd deploys their own image
+const placeholderImage = "mcr.microsoft.com/azuredocs/containerapps-helloworld:latest"
+
+// The infrastructure files written by Generate, relative to the project directory
+var (
+	mainBicepPath      = filepath.Join("infra", "main.bicep")
+	resourcesBicepPath = filepath.Join("infra", "resources.bicep")
+	parametersPath     = filepath.Join("infra", "main.parameters.json")
+)
+
+// projectFile is the subset of azure.yaml written for applications, without the defaults of ProjectConfig.
+type projectFile struct {
+	Name     string                  `yaml:"name"`
+	Services map[string]*serviceFile `yaml:"services,omitempty"`
+}
+
+type serviceFile struct {
+	Project  string                      `yaml:"project"`
+	Language project.ServiceLanguageKind `yaml:"language"`
+	Host     project.ServiceTargetKind   `yaml:"host"`
+	Docker   *dockerFile                 `yaml:"docker,omitempty"`
+}
+
+type dockerFile struct {
+	Path    string `yaml:"path"`
+	Context string `yaml:"context,omitempty"`
+}
+
+// Generate writes the azure.yaml of the application to the project directory, with an azd service for each built
+// service, and the Bicep infrastructure hosting every service in a container app. It returns os.ErrExist when one of
+// the files already exists, without writing any.
+func Generate(projectDir string, projectName string, app *Application) error {
+	files := map[string][]byte{}
+
+	projectContent, err := generateProjectFile(projectName, app)
+	if err != nil {
+		return err
+	}
+	files[azdcontext.ProjectFileName] = projectContent
+
+	resourcesContent, err := generateResources(app)
+	if err != nil {
+		return err
+	}
+	files[resourcesBicepPath] = resourcesContent
+	files[mainBicepPath] = []byte(mainBicep)
+	files[parametersPath] = []byte(mainParameters)
+
+	for _, service := range app.Services {
+		if service.GenerateDockerfile {
+			files[filepath.Join(filepath.FromSlash(service.Context), "Dockerfile")] = generateDotNetDockerfile(service)
+		}
+	}
+
+	for path := range files {
+		if _, err := os.Stat(filepath.Join(projectDir, path)); err == nil {
+			return fmt.Errorf("%s: %w", path, os.ErrExist)
+		}
+	}
+
+	for path, content := range files {
+		fullPath := filepath.Join(projectDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), osutil.PermissionDirectory); err != nil {
+			return fmt.Errorf("creating directory for %s: %w", path, err)
+		}
+
+		if err := os.WriteFile(fullPath, content, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func generateProjectFile(projectName string, app *Application) ([]byte, error) {
+	file := projectFile{
+		Name:     projectName,
+		Services: map[string]*serviceFile{},
+	}
+
+	for _, service := range app.Services {
+		if !service.Built() {
+			continue
+		}
+
+		serviceFile := &serviceFile{
+			Project:  projectPath(service.Context),
+			Language: service.Language,
+			Host:     project.ContainerAppTarget,
+		}
+
+		if service.GenerateDockerfile {
+			// The generated Dockerfile is built from the project directory, with the projects it references
+			context, err := filepath.Rel(filepath.FromSlash(service.Context), ".")
+			if err != nil {
+				return nil, fmt.Errorf("service '%s': %w", service.Name, err)
+			}
+			serviceFile.Docker = &dockerFile{Path: "./Dockerfile", Context: filepath.ToSlash(context)}
+		} else if service.Dockerfile != "" && service.Dockerfile != "Dockerfile" {
+			serviceFile.Docker = &dockerFile{Path: "./" + service.Dockerfile}
+		}
+
+		file.Services[service.Name] = serviceFile
+	}
+
+	content, err := yaml.Marshal(file)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling project file: %w", err)
+	}
+
+	return append([]byte(project.SchemaAnnotation+"\n\n"), content...), nil
+}
+
+// projectPath returns the path of a service in azure.yaml, like ./api.
+func projectPath(context string) string {
+	if context == "." {
+		return "./"
+	}
+
+	if strings.HasPrefix(context, "../") {
+		return context
+	}
+
+	return "./" + context
+}
+
+// generateDotNetDockerfile returns the Dockerfile publishing the .NET project of the service, built from the project
+// directory.
+func generateDotNetDockerfile(service *Service) []byte {
+	assembly := strings.TrimSuffix(path.Base(service.DotNetProject), path.Ext(service.DotNetProject))
+	return []byte(fmt.Sprintf(dotNetDockerfile, service.DotNetProject, assembly, aspNetCorePort))
+}
+
+// containerApp is a container app of resources.bicep.
+type containerApp struct {
+	// The symbolic name of the resource
+	Identifier string
+	// The name of the container app, and of its container
+	AppName     string
+	ServiceName string
+	Image       string
+	Built       bool
+	Port        int
+	External    bool
+	Env         []envVar
+}
+
+type envVar struct {
+	Name  string
+	Value string
+}
+
+var invalidAppNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func generateResources(app *Application) ([]byte, error) {
+	var apps []containerApp
+	appNames := map[string]bool{}
+
+	for _, service := range app.Services {
+		appName := containerAppName(service.Name)
+		if appNames[appName] {
+			return nil, fmt.Errorf(
+				"the name of service '%s' conflicts with another service once converted to a container app name", service.Name)
+		}
+		appNames[appName] = true
+
+		image := service.Image
+		if service.Built() {
+			image = placeholderImage
+		}
+
+		var env []envVar
+		for name, value := range service.Env {
+			env = append(env, envVar{Name: bicepString(name), Value: bicepString(value)})
+		}
+		sort.Slice(env, func(i, j int) bool {
+			return env[i].Name < env[j].Name
+		})
+
+		apps = append(apps, containerApp{
+			Identifier:  strings.ReplaceAll(appName, "-", "_") + "App",
+			AppName:     appName,
+			ServiceName: bicepString(service.Name),
+			Image:       bicepString(image),
+			Built:       service.Built(),
+			Port:        service.Port,
+			External:    service.External,
+			Env:         env,
+		})
+	}
+
+	var content bytes.Buffer
+	if err := resourcesTemplate.Execute(&content, apps); err != nil {
+		return nil, fmt.Errorf("generating %s: %w", resourcesBicepPath, err)
+	}
+
+	return content.Bytes(), nil
+}
+
+// containerAppName converts the name of a service to the name of a container app, which has lowercase letters,
+// numbers and dashes, starts with a letter and has at most 32 characters.
+func containerAppName(name string) string {
+	appName := invalidAppNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	appName = strings.Trim(appName, "-")
+	if appName == "" {
+		appName = "app"
+	} else if appName[0] < 'a' || appName[0] > 'z' {
+		appName = "app-" + appName
+	}
+
+	if len(appName) > 32 {
+		appName = strings.TrimRight(appName[:32], "-")
+	}
+
+	return appName
+}
+
+// bicepString escapes a value for a single quoted Bicep string.
+func bicepString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, "${", `\${`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
+
+var resourcesTemplate = template.Must(template.New("resources").Parse(`param location string
+param tags object
+
+var resourceToken = toLower(uniqueString(resourceGroup().id))
+
+resource logAnalytics 'Microsoft.OperationalInsights/workspaces@2022-10-01' = {
+  name: 'log-${resourceToken}'
+  location: location
+  tags: tags
+  properties: {
+    sku: {
+      name: 'PerGB2018'
+    }
+  }
+}
+
+resource containerAppsEnvironment 'Microsoft.App/managedEnvironments@2023-05-01' = {
+  name: 'cae-${resourceToken}'
+  location: location
+  tags: tags
+  properties: {
+    appLogsConfiguration: {
+      destination: 'log-analytics'
+      logAnalyticsConfiguration: {
+        customerId: logAnalytics.properties.customerId
+        sharedKey: logAnalytics.listKeys().primarySharedKey
+      }
+    }
+  }
+}
+
+resource containerRegistry 'Microsoft.ContainerRegistry/registries@2023-07-01' = {
+  name: 'cr${resourceToken}'
+  location: location
+  tags: tags
+  sku: {
+    name: 'Basic'
+  }
+  properties: {
+    adminUserEnabled: true
+  }
+}
+{{range .}}
+resource {{.Identifier}} 'Microsoft.App/containerApps@2023-05-01' = {
+  name: '{{.AppName}}'
+  location: location
+  tags: union(tags, { 'azd-service-name': '{{.ServiceName}}' })
+  properties: {
+    managedEnvironmentId: containerAppsEnvironment.id
+    configuration: {
+{{- if .Port}}
+      ingress: {
+        external: {{.External}}
+        targetPort: {{.Port}}
+      }
+{{- end}}
+{{- if .Built}}
+      registries: [
+        {
+          server: containerRegistry.properties.loginServer
+          username: containerRegistry.listCredentials().username
+          passwordSecretRef: 'registry-password'
+        }
+      ]
+      secrets: [
+        {
+          name: 'registry-password'
+          value: containerRegistry.listCredentials().passwords[0].value
+        }
+      ]
+{{- end}}
+    }
+    template: {
+      containers: [
+        {
+          name: '{{.AppName}}'
+          image: '{{.Image}}'
+{{- if .Env}}
+          env: [
+{{- range .Env}}
+            {
+              name: '{{.Name}}'
+              value: '{{.Value}}'
+            }
+{{- end}}
+          ]
+{{- end}}
+        }
+      ]
+    }
+  }
+}
+{{end}}
+output AZURE_CONTAINER_REGISTRY_ENDPOINT string = containerRegistry.properties.loginServer
+`))
+
+const mainBicep = `targetScope = 'subscription'
+
+@minLength(1)
+@maxLength(64)
+@description('Name of the environment which is used to generate a short unique hash used in all resources.')
+param environmentName string
+
+@minLength(1)
+@description('Primary location for all resources')
+param location string
+
+var tags = { 'azd-env-name': environmentName }
+
+resource rg 'Microsoft.Resources/resourceGroups@2022-09-01' = {
+  name: 'rg-${environmentName}'
+  location: location
+  tags: tags
+}
+
+module resources 'resources.bicep' = {
+  name: 'resources'
+  scope: rg
+  params: {
+    location: location
+    tags: tags
+  }
+}
+
+output AZURE_CONTAINER_REGISTRY_ENDPOINT string = resources.outputs.AZURE_CONTAINER_REGISTRY_ENDPOINT
+`
+
+const mainParameters = `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "environmentName": {
+      "value": "${AZURE_ENV_NAME}"
+    },
+    "location": {
+      "value": "${AZURE_LOCATION}"
+    }
+  }
+}
+`
+
+const dotNetDockerfile = `# Generated by azd, publishes the project with the projects it references
+FROM mcr.microsoft.com/dotnet/sdk:8.0 AS build
+WORKDIR /src
+COPY . .
+RUN dotnet publish "%[1]s" -c Release -o /app/publish /p:UseAppHost=false
+
+FROM mcr.microsoft.com/dotnet/aspnet:8.0
+WORKDIR /app
+COPY --from=build /app/publish .
+ENV ASPNETCORE_HTTP_PORTS=%[3]d
+EXPOSE %[3]d
+ENTRYPOINT ["dotnet", "%[2]s.dll"]
+`
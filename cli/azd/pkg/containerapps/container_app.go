@@ -3,6 +3,8 @@ package containerapps
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
 	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
@@ -11,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/benbjohnson/clock"
+	"golang.org/x/exp/slices"
 )
 
 // ContainerAppService exposes operations for managing Azure Container Apps
@@ -22,16 +25,27 @@ type ContainerAppService interface {
 		resourceGroup,
 		appName string,
 	) (*ContainerAppIngressConfiguration, error)
-	// Adds and activates a new revision to the specified container app
+	// Adds and activates a new revision to the specified container app. When registry is set, the container app pulls
+	// the image with its credentials.
 	AddRevision(
 		ctx context.Context,
 		subscriptionId string,
 		resourceGroupName string,
 		appName string,
 		imageName string,
+		registry *RegistryCredentials,
 	) error
 }
 
+// RegistryCredentials are the credentials a container app pulls its image with from a registry other than Azure
+// Container Registry, which container apps pull from with their managed identity.
+type RegistryCredentials struct {
+	// The login server of the registry, like ghcr.io
+	Server   string
+	Username string
+	Password string
+}
+
 // NewContainerAppService creates a new ContainerAppService
 func NewContainerAppService(
 	credentialProvider account.SubscriptionCredentialProvider,
@@ -84,13 +98,15 @@ func (cas *containerAppService) GetIngressConfiguration(
 	}, nil
 }
 
-// Adds and activates a new revision to the specified container app
+// Adds and activates a new revision to the specified container app. When registry is set, the container app pulls
+// the image with its credentials.
 func (cas *containerAppService) AddRevision(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroupName string,
 	appName string,
 	imageName string,
+	registry *RegistryCredentials,
 ) error {
 	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
 	if err != nil {
@@ -121,6 +137,10 @@ func (cas *containerAppService) AddRevision(
 		return fmt.Errorf("syncing secrets: %w", err)
 	}
 
+	if registry != nil {
+		setRegistryCredentials(containerApp, registry)
+	}
+
 	// Update the container app
 	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp)
 	if err != nil {
@@ -177,6 +197,50 @@ func (cas *containerAppService) syncSecrets(
 	return containerApp, nil
 }
 
+// setRegistryCredentials configures the container app to pull from the registry with its credentials. The password is
+// stored in a secret of the container app, replacing the password of a previous deployment.
+func setRegistryCredentials(containerApp *armappcontainers.ContainerApp, registry *RegistryCredentials) {
+	configuration := containerApp.Properties.Configuration
+	secretName := registrySecretName(registry.Server)
+
+	secret := &armappcontainers.Secret{
+		Name:  convert.RefOf(secretName),
+		Value: convert.RefOf(registry.Password),
+	}
+
+	secretIndex := slices.IndexFunc(configuration.Secrets, func(s *armappcontainers.Secret) bool {
+		return s.Name != nil && *s.Name == secretName
+	})
+	if secretIndex >= 0 {
+		configuration.Secrets[secretIndex] = secret
+	} else {
+		configuration.Secrets = append(configuration.Secrets, secret)
+	}
+
+	credentials := &armappcontainers.RegistryCredentials{
+		Server:            convert.RefOf(registry.Server),
+		Username:          convert.RefOf(registry.Username),
+		PasswordSecretRef: convert.RefOf(secretName),
+	}
+
+	registryIndex := slices.IndexFunc(configuration.Registries, func(r *armappcontainers.RegistryCredentials) bool {
+		return r.Server != nil && strings.EqualFold(*r.Server, registry.Server)
+	})
+	if registryIndex >= 0 {
+		configuration.Registries[registryIndex] = credentials
+	} else {
+		configuration.Registries = append(configuration.Registries, credentials)
+	}
+}
+
+// Secret names of container apps are lower case alphanumeric characters and dashes
+var invalidSecretNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// registrySecretName returns the name of the secret holding the password of a registry, like azd-registry-ghcr-io.
+func registrySecretName(server string) string {
+	return "azd-registry-" + invalidSecretNameChars.ReplaceAllString(strings.ToLower(server), "-")
+}
+
 func (cas *containerAppService) setTrafficWeights(
 	ctx context.Context,
 	subscriptionId string,
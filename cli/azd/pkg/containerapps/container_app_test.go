@@ -128,7 +128,7 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	)
 
 	cas := NewContainerAppService(mockContext.SubscriptionCredentialProvider, mockContext.HttpClient, clock.NewMock())
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName)
+	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, nil)
 	require.NoError(t, err)
 
 	// Verify lastest revision is read
@@ -150,3 +150,52 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 	require.Equal(t, updatedImageName, *updatedContainerApp.Properties.Template.Containers[0].Image)
 	require.Equal(t, "azd-deploy-0", *updatedContainerApp.Properties.Template.RevisionSuffix)
 }
+
+func Test_setRegistryCredentials(t *testing.T) {
+	containerApp := &armappcontainers.ContainerApp{
+		Properties: &armappcontainers.ContainerAppProperties{
+			Configuration: &armappcontainers.Configuration{
+				Secrets: []*armappcontainers.Secret{
+					{Name: convert.RefOf("secret"), Value: convert.RefOf("value")},
+					{Name: convert.RefOf("azd-registry-ghcr-io"), Value: convert.RefOf("old-token")},
+				},
+				Registries: []*armappcontainers.RegistryCredentials{
+					{
+						Server:            convert.RefOf("contoso.azurecr.io"),
+						Username:          convert.RefOf("contoso"),
+						PasswordSecretRef: convert.RefOf("acr-password"),
+					},
+					{
+						Server:            convert.RefOf("GHCR.io"),
+						Username:          convert.RefOf("old-bot"),
+						PasswordSecretRef: convert.RefOf("azd-registry-ghcr-io"),
+					},
+				},
+			},
+		},
+	}
+
+	setRegistryCredentials(containerApp, &RegistryCredentials{
+		Server:   "ghcr.io",
+		Username: "contoso-bot",
+		Password: "token",
+	})
+
+	require.Equal(t, []*armappcontainers.Secret{
+		{Name: convert.RefOf("secret"), Value: convert.RefOf("value")},
+		{Name: convert.RefOf("azd-registry-ghcr-io"), Value: convert.RefOf("token")},
+	}, containerApp.Properties.Configuration.Secrets)
+
+	require.Equal(t, []*armappcontainers.RegistryCredentials{
+		{
+			Server:            convert.RefOf("contoso.azurecr.io"),
+			Username:          convert.RefOf("contoso"),
+			PasswordSecretRef: convert.RefOf("acr-password"),
+		},
+		{
+			Server:            convert.RefOf("ghcr.io"),
+			Username:          convert.RefOf("contoso-bot"),
+			PasswordSecretRef: convert.RefOf("azd-registry-ghcr-io"),
+		},
+	}, containerApp.Properties.Configuration.Registries)
+}
@@ -0,0 +1,10 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// AliasListAlias is a command alias of the user, as returned by `azd alias list`.
+type AliasListAlias struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+}
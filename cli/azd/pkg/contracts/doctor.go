@@ -0,0 +1,37 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+import "time"
+
+// DoctorCheckStatus is the outcome of a single `azd doctor` check.
+type DoctorCheckStatus string
+
+const (
+	DoctorCheckPassed  DoctorCheckStatus = "passed"
+	DoctorCheckWarning DoctorCheckStatus = "warning"
+	DoctorCheckFailed  DoctorCheckStatus = "failed"
+	DoctorCheckSkipped DoctorCheckStatus = "skipped"
+)
+
+// DoctorResult is the contract for the output of `azd doctor`
+type DoctorResult struct {
+	Timestamp time.Time `json:"timestamp"`
+	// The version of azd that produced the report
+	Version string `json:"version"`
+	// The operating system and architecture azd is running on, ex) linux/amd64
+	Platform string        `json:"platform"`
+	Checks   []DoctorCheck `json:"checks"`
+}
+
+// DoctorCheck is the result of a single diagnostic performed by `azd doctor`
+type DoctorCheck struct {
+	// The category of the check, ex) tools, auth, network
+	Category string            `json:"category"`
+	Name     string            `json:"name"`
+	Status   DoctorCheckStatus `json:"status"`
+	Message  string            `json:"message,omitempty"`
+	// Actionable steps to resolve a failed or warning check
+	Remediation string `json:"remediation,omitempty"`
+}
@@ -9,6 +9,7 @@ type EventDataType string
 
 const (
 	ConsoleMessageEventDataType EventDataType = "consoleMessage"
+	ErrorEventDataType          EventDataType = "error"
 )
 
 type EventEnvelope struct {
@@ -0,0 +1,17 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// ErrorMessage is the final event written to stderr when a command fails and `--output json` is used.
+type ErrorMessage struct {
+	// The class of the failure, ex: auth, validation, provisioning, deployment, externalTool or cancelled.
+	Category string `json:"category"`
+	// The exit code of the process, which is specific to the category.
+	ExitCode int `json:"exitCode"`
+	// The error message.
+	Message string `json:"message"`
+	// The correlation ID of the requests sent to Azure by the command, to share with support. Empty when the command
+	// didn't send any request.
+	CorrelationId string `json:"correlationId,omitempty"`
+}
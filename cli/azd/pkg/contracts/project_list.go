@@ -0,0 +1,11 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package contracts
+
+// ProjectListProject is a project registered in the workspace, as returned by `azd project list`.
+type ProjectListProject struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	IsActive bool   `json:"isActive"`
+}
@@ -2,6 +2,8 @@
 // Licensed under the MIT License.
 package contracts
 
+import "time"
+
 // ShowType are the values for the language property of a ShowServiceProject
 type ShowType string
 
@@ -10,12 +12,25 @@ const (
 	ShowTypePython ShowType = "python"
 	ShowTypeNode   ShowType = "node"
 	ShowTypeJava   ShowType = "java"
+	ShowTypeGo     ShowType = "go"
 )
 
 // ShowResult is the contract for the output of `azd show`
 type ShowResult struct {
 	Name     string                 `json:"name"`
 	Services map[string]ShowService `json:"services"`
+	// Environment contains information about the active environment, when one is selected.
+	Environment *ShowEnvironment `json:"environment,omitempty"`
+}
+
+// ShowEnvironment is the contract for the active environment as returned by `azd show`
+type ShowEnvironment struct {
+	Name           string `json:"name"`
+	SubscriptionId string `json:"subscriptionId,omitempty"`
+	Location       string `json:"location,omitempty"`
+	// ResourceGroup is the name of the resource group the application is provisioned in. It is empty when the
+	// infrastructure has not been provisioned yet.
+	ResourceGroup string `json:"resourceGroup,omitempty"`
 }
 
 // ShowService is the contract for a service returned by `azd show`
@@ -25,6 +40,19 @@ type ShowService struct {
 	// Target contains information about the resource that the service is deployed
 	// to.
 	Target *ShowTargetArm `json:"target,omitempty"`
+	// Host is the kind of Azure service that hosts this service, ex) containerapp, appservice.
+	Host string `json:"host"`
+	// Endpoints contains the public endpoints exposed by the deployed service.
+	Endpoints []string `json:"endpoints,omitempty"`
+	// LastDeployment contains information about the last time the service was deployed with azd.
+	LastDeployment *ShowDeployment `json:"lastDeployment,omitempty"`
+	// Capabilities contains the optional operations supported by the host, ex) slots, logStreaming.
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// ShowDeployment is the contract for information about the last deployment of a service
+type ShowDeployment struct {
+	Timestamp time.Time `json:"timestamp"`
 }
 
 // ShowServiceProject is the contract for a service's project as returned by `azd show`
@@ -8,4 +8,13 @@ type VersionResult struct {
 		Version string `json:"version"`
 		Commit  string `json:"commit"`
 	} `json:"azd"`
+	// Update is only set by `azd version --check`
+	Update *VersionUpdateResult `json:"update,omitempty"`
+}
+
+// VersionUpdateResult is the latest version of azd published on the configured release channel.
+type VersionUpdateResult struct {
+	Channel         string `json:"channel"`
+	LatestVersion   string `json:"latestVersion"`
+	UpdateAvailable bool   `json:"updateAvailable"`
 }
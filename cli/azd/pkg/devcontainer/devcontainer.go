@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package devcontainer detects when azd runs inside a dev container or a GitHub Codespace, and scaffolds the
+// devcontainer.json of a project so it can be developed in one.
+package devcontainer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// Kind is the kind of dev container azd is running in.
+type Kind string
+
+const (
+	// KindNone is used when azd is not running in a dev container.
+	KindNone Kind = ""
+	// KindCodespaces is used when azd is running in a GitHub Codespace.
+	KindCodespaces Kind = "codespaces"
+	// KindDevContainer is used when azd is running in a local dev container, like the ones started by the
+	// VS Code Dev Containers extension.
+	KindDevContainer Kind = "devcontainer"
+)
+
+// Detect returns the kind of dev container azd is running in, from the environment variables set by GitHub Codespaces
+// and the dev container tooling.
+func Detect(getenv func(string) string) Kind {
+	if getenv("CODESPACES") == "true" {
+		return KindCodespaces
+	}
+
+	if getenv("REMOTE_CONTAINERS") == "true" || getenv("REMOTE_CONTAINERS_IPC") != "" || getenv("DEVCONTAINER") == "true" {
+		return KindDevContainer
+	}
+
+	return KindNone
+}
+
+// The path of the dev container configuration, relative to the project directory.
+var ConfigPath = filepath.Join(".devcontainer", "devcontainer.json")
+
+// The image used by the scaffolded dev containers. The tools are installed as dev container features.
+const baseImage = "mcr.microsoft.com/devcontainers/base:bullseye"
+
+// Tool is a tool preinstalled in the dev container.
+type Tool string
+
+const (
+	ToolNode      Tool = "node"
+	ToolPython    Tool = "python"
+	ToolDotNet    Tool = "dotnet"
+	ToolJava      Tool = "java"
+	ToolGo        Tool = "go"
+	ToolDocker    Tool = "docker"
+	ToolKubectl   Tool = "kubectl"
+	ToolTerraform Tool = "terraform"
+)
+
+// The dev container features installed for every project: azd itself, the Azure CLI and the GitHub CLI used by
+// `azd pipeline config`.
+var defaultFeatures = map[string]map[string]any{
+	"ghcr.io/azure/azure-dev/azd:latest":          {},
+	"ghcr.io/devcontainers/features/azure-cli:1":  {},
+	"ghcr.io/devcontainers/features/github-cli:1": {},
+}
+
+var toolFeatures = map[Tool]struct {
+	id      string
+	options map[string]any
+}{
+	ToolNode:      {"ghcr.io/devcontainers/features/node:1", map[string]any{"version": "18"}},
+	ToolPython:    {"ghcr.io/devcontainers/features/python:1", map[string]any{"version": "3.10"}},
+	ToolDotNet:    {"ghcr.io/devcontainers/features/dotnet:1", map[string]any{"version": "6.0"}},
+	ToolJava:      {"ghcr.io/devcontainers/features/java:1", map[string]any{"version": "17", "installMaven": "true"}},
+	ToolGo:        {"ghcr.io/devcontainers/features/go:1", map[string]any{"version": "1.21"}},
+	ToolDocker:    {"ghcr.io/devcontainers/features/docker-in-docker:2", map[string]any{}},
+	ToolKubectl:   {"ghcr.io/devcontainers/features/kubectl-helm-minikube:1", map[string]any{"minikube": "none"}},
+	ToolTerraform: {"ghcr.io/devcontainers/features/terraform:1", map[string]any{}},
+}
+
+// Config is the subset of the devcontainer.json schema scaffolded by azd.
+// See https://containers.dev/implementors/json_reference/
+type Config struct {
+	Name           string                    `json:"name"`
+	Image          string                    `json:"image"`
+	Features       map[string]map[string]any `json:"features"`
+	Customizations Customizations            `json:"customizations"`
+}
+
+type Customizations struct {
+	VSCode VSCodeCustomizations `json:"vscode"`
+}
+
+type VSCodeCustomizations struct {
+	Extensions []string `json:"extensions"`
+}
+
+// NewConfig creates the dev container configuration of a project which preinstalls azd and the tools.
+func NewConfig(name string, tools []Tool) *Config {
+	features := map[string]map[string]any{}
+	for id, options := range defaultFeatures {
+		features[id] = options
+	}
+
+	extensions := []string{"ms-azuretools.azure-dev", "ms-azuretools.vscode-bicep"}
+
+	for _, tool := range tools {
+		feature, has := toolFeatures[tool]
+		if !has {
+			continue
+		}
+
+		features[feature.id] = feature.options
+
+		if tool == ToolDocker {
+			extensions = append(extensions, "ms-azuretools.vscode-docker")
+		}
+	}
+
+	return &Config{
+		Name:     name,
+		Image:    baseImage,
+		Features: features,
+		Customizations: Customizations{
+			VSCode: VSCodeCustomizations{Extensions: extensions},
+		},
+	}
+}
+
+// Save writes the configuration to the devcontainer.json of the project in projectDir. It returns os.ErrExist when the
+// project already has a dev container configuration.
+func (c *Config) Save(projectDir string) error {
+	configPath := filepath.Join(projectDir, ConfigPath)
+	if _, err := os.Stat(configPath); err == nil {
+		return fmt.Errorf("%s: %w", configPath, os.ErrExist)
+	}
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling dev container configuration: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating dev container directory: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, append(content, '\n'), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing dev container configuration: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package devcontainer
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	tests := map[string]struct {
+		env      map[string]string
+		expected Kind
+	}{
+		"None":              {env: map[string]string{}, expected: KindNone},
+		"Codespaces":        {env: map[string]string{"CODESPACES": "true"}, expected: KindCodespaces},
+		"RemoteContainers":  {env: map[string]string{"REMOTE_CONTAINERS": "true"}, expected: KindDevContainer},
+		"DevContainersCli":  {env: map[string]string{"DEVCONTAINER": "true"}, expected: KindDevContainer},
+		"CodespacesPrecede": {env: map[string]string{"CODESPACES": "true", "REMOTE_CONTAINERS": "true"}, expected: KindCodespaces},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kind := Detect(func(key string) string { return test.env[key] })
+			require.Equal(t, test.expected, kind)
+		})
+	}
+}
+
+func TestConfigSave(t *testing.T) {
+	projectDir := t.TempDir()
+
+	config := NewConfig("todo-nodejs", []Tool{ToolNode, ToolDocker})
+	require.NoError(t, config.Save(projectDir))
+
+	content, err := os.ReadFile(filepath.Join(projectDir, ".devcontainer", "devcontainer.json"))
+	require.NoError(t, err)
+
+	var saved Config
+	require.NoError(t, json.Unmarshal(content, &saved))
+	require.Equal(t, "todo-nodejs", saved.Name)
+	require.Contains(t, saved.Features, "ghcr.io/azure/azure-dev/azd:latest")
+	require.Contains(t, saved.Features, "ghcr.io/devcontainers/features/node:1")
+	require.Contains(t, saved.Features, "ghcr.io/devcontainers/features/docker-in-docker:2")
+	require.NotContains(t, saved.Features, "ghcr.io/devcontainers/features/python:1")
+	require.Contains(t, saved.Customizations.VSCode.Extensions, "ms-azuretools.vscode-docker")
+
+	// An existing configuration is never overwritten
+	err = NewConfig("todo-nodejs", nil).Save(projectDir)
+	require.True(t, errors.Is(err, os.ErrExist))
+}
@@ -6,10 +6,12 @@ package environment
 import (
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry/fields"
@@ -44,6 +46,18 @@ const AksClusterEnvVarName = "AZURE_AKS_CLUSTER_NAME"
 // ResourceGroupEnvVarName is the name of the azure resource group that should be used for deployments
 const ResourceGroupEnvVarName = "AZURE_RESOURCE_GROUP"
 
+// KeyVaultNameEnvVarName is the name of the key vault of the environment, storing the secrets set by
+// `azd env set-secret`.
+const KeyVaultNameEnvVarName = "AZURE_KEY_VAULT_NAME"
+
+// ProvisionOutputsConfigPath is the path of the environment config storing the outputs of the last provisioning of the
+// environment, keyed by output name. The outputs are also environment values, but only the config keeps their types.
+const ProvisionOutputsConfigPath = "provision.outputs"
+
+// SecretsConfigPath is the path of the environment config storing references to the secrets of the environment kept in
+// external secret stores, keyed by the name of the value, like the Key Vault secrets set by `azd env set-secret`.
+const SecretsConfigPath = "secrets"
+
 type Environment struct {
 	// Values is a map of setting names to values.
 	Values map[string]string
@@ -55,6 +69,56 @@ type Environment struct {
 	// will not be persisted when `Save` is called. This allows the zero value to be used
 	// for testing.
 	Root string
+
+	// secrets are values sourced at runtime from external secret stores. They take precedence over Values and are
+	// never persisted.
+	secrets *lazySecrets
+}
+
+// lazySecrets resolves the secrets of the environment once, the first time one of them is read, so commands which
+// don't read them don't call the secret stores.
+type lazySecrets struct {
+	keys   map[string]struct{}
+	load   func() (map[string]string, error)
+	once   sync.Once
+	values map[string]string
+}
+
+func (s *lazySecrets) has(key string) bool {
+	if s == nil {
+		return false
+	}
+
+	_, has := s.keys[key]
+	return has
+}
+
+func (s *lazySecrets) resolve() map[string]string {
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		values, err := s.load()
+		if err != nil {
+			// The values of the environment, if any, are used instead
+			log.Printf("failed to resolve the environment secrets: %v", err)
+			return
+		}
+
+		s.values = values
+	})
+
+	return s.values
+}
+
+func (s *lazySecrets) lookup(key string) (string, bool) {
+	if !s.has(key) {
+		return "", false
+	}
+
+	v, has := s.resolve()[key]
+	return v, has
 }
 
 type EnvironmentResolver func() (*Environment, error)
@@ -123,8 +187,12 @@ func EphemeralWithValues(name string, values map[string]string) *Environment {
 	return env
 }
 
-// Getenv fetches a key from e.Values, falling back to os.Getenv if it is not present.
+// Getenv fetches a key from the secrets and e.Values, falling back to os.Getenv if it is not present.
 func (e *Environment) Getenv(key string) string {
+	if v, has := e.secrets.lookup(key); has {
+		return v
+	}
+
 	if v, has := e.Values[key]; has {
 		return v
 	}
@@ -132,6 +200,19 @@ func (e *Environment) Getenv(key string) string {
 	return os.Getenv(key)
 }
 
+// LookupEnv is like Getenv, and also reports whether the key is set.
+func (e *Environment) LookupEnv(key string) (string, bool) {
+	if v, has := e.secrets.lookup(key); has {
+		return v, true
+	}
+
+	if v, has := e.Values[key]; has {
+		return v, true
+	}
+
+	return os.LookupEnv(key)
+}
+
 // Reloads environment variables and configuration
 func (e *Environment) Reload() error {
 	// Reload env values
@@ -249,10 +330,41 @@ func (e *Environment) SetServiceProperty(serviceName string, propertyName string
 // Creates a slice of key value pairs like `KEY=VALUE` that
 // can be used to pass into command runner or similar constructs
 func (e *Environment) Environ() []string {
+	secrets := e.secrets.resolve()
+
 	envVars := []string{}
 	for k, v := range e.Values {
+		if _, has := secrets[k]; has {
+			continue
+		}
+
+		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	for k, v := range secrets {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
 	}
 
 	return envVars
 }
+
+// SetSecrets sets the keys of the values sourced from external secret stores, resolved by load the first time one of
+// them is read, or Environ is called. Secrets are returned by Getenv and Environ, but are not part of Values and are
+// not persisted when `Save` is called.
+func (e *Environment) SetSecrets(keys []string, load func() (map[string]string, error)) {
+	secrets := &lazySecrets{
+		keys: map[string]struct{}{},
+		load: load,
+	}
+
+	for _, key := range keys {
+		secrets.keys[key] = struct{}{}
+	}
+
+	e.secrets = secrets
+}
+
+// IsSecret reports whether the value of key is sourced from an external secret store.
+func (e *Environment) IsSecret(key string) bool {
+	return e.secrets.has(key)
+}
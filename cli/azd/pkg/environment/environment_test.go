@@ -149,3 +149,29 @@ func Test_SaveAndReload(t *testing.T) {
 	require.Equal(t, "SUBSCRIPTION_ID", env.GetSubscriptionId())
 	require.Equal(t, "eastus2", env.GetLocation())
 }
+
+func Test_SecretsAreNotSaved(t *testing.T) {
+	tempDir := t.TempDir()
+
+	env := EmptyWithRoot(tempDir)
+	env.Values["DATABASE_PASSWORD"] = "placeholder"
+	resolved := 0
+	env.SetSecrets([]string{"DATABASE_PASSWORD"}, func() (map[string]string, error) {
+		resolved++
+		return map[string]string{"DATABASE_PASSWORD": "secret"}, nil
+	})
+
+	// The secrets are resolved the first time they're read, once
+	require.Equal(t, 0, resolved)
+	require.Equal(t, "secret", env.Getenv("DATABASE_PASSWORD"))
+	require.True(t, env.IsSecret("DATABASE_PASSWORD"))
+	require.Equal(t, []string{"DATABASE_PASSWORD=secret"}, env.Environ())
+
+	err := env.Save()
+	require.NoError(t, err)
+
+	envMap, err := godotenv.Read(filepath.Join(tempDir, azdcontext.DotEnvFileName))
+	require.NoError(t, err)
+	require.Equal(t, "placeholder", envMap["DATABASE_PASSWORD"])
+	require.Equal(t, 1, resolved)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/drone/envsubst"
+	"github.com/drone/envsubst/parse"
+	"golang.org/x/exp/slices"
+)
+
+// envReferenceRegex matches the ${env:KEY} references to environment values, and their $${env:KEY} escaped form.
+var envReferenceRegex = regexp.MustCompile(`\$?\$\{env:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// The operators of the references which have a value when the key isn't set, like ${KEY=default} or ${KEY:+alternate}.
+var optionalReferenceOperators = []string{"=", ":=", "-", ":-", "+", ":+"}
+
+// UnresolvedReferencesError is returned when a value references keys which aren't set in the environment.
+type UnresolvedReferencesError struct {
+	Names []string
+}
+
+func (e *UnresolvedReferencesError) Error() string {
+	references := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		references[i] = fmt.Sprintf("${%s}", name)
+	}
+
+	return fmt.Sprintf(
+		"%s not set in the environment. Set the value with 'azd env set %s <value>', "+
+			"provide a default like ${%s=default} or escape the reference like $${%s}",
+		strings.Join(references, ", "),
+		e.Names[0],
+		e.Names[0],
+		e.Names[0],
+	)
+}
+
+// Interpolate replaces the ${KEY} and ${env:KEY} references of template with the values returned by lookup. Defaults
+// are supported, like ${KEY=default}, and a reference is escaped with a second $, $${KEY} is kept as ${KEY}.
+// An *UnresolvedReferencesError is returned when lookup doesn't find keys referenced without a default.
+func Interpolate(template string, lookup func(string) (string, bool)) (string, error) {
+	// ${env:KEY} is a synonym of ${KEY}, the escaped $${env:KEY} is kept as is for envsubst to unescape it
+	template = envReferenceRegex.ReplaceAllStringFunc(template, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return reference
+		}
+
+		return "${" + strings.TrimPrefix(reference, "${env:")
+	})
+
+	tree, err := parse.Parse(template)
+	if err != nil {
+		return "", fmt.Errorf("parsing '%s': %w", template, err)
+	}
+
+	missing := []string{}
+	for _, name := range requiredReferences(tree.Root) {
+		if _, has := lookup(name); !has && !slices.Contains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", &UnresolvedReferencesError{Names: missing}
+	}
+
+	return envsubst.Eval(template, func(name string) string {
+		value, _ := lookup(name)
+		return value
+	})
+}
+
+// requiredReferences returns the keys referenced by a node without a default value.
+func requiredReferences(node parse.Node) []string {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		names := []string{}
+		for _, child := range node.Nodes {
+			names = append(names, requiredReferences(child)...)
+		}
+
+		return names
+	case *parse.FuncNode:
+		if slices.Contains(optionalReferenceOperators, node.Name) {
+			return nil
+		}
+
+		return []string{node.Param}
+	default:
+		return nil
+	}
+}
+
+// EnvReferences returns the keys referenced with ${env:KEY} by value, ignoring the escaped $${env:KEY} references.
+func EnvReferences(value string) []string {
+	names := []string{}
+	for _, match := range envReferenceRegex.FindAllStringSubmatch(value, -1) {
+		if !strings.HasPrefix(match[0], "$$") && !slices.Contains(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// ReplaceEnvReferences replaces the ${env:KEY} references of value with replace(KEY), and unescapes the $${env:KEY}
+// references. Other references, like ${KEY}, are kept, which is what scripts run by a shell need.
+func ReplaceEnvReferences(value string, replace func(string) string) string {
+	return envReferenceRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		if strings.HasPrefix(reference, "$$") {
+			return strings.TrimPrefix(reference, "$")
+		}
+
+		return replace(envReferenceRegex.FindStringSubmatch(reference)[1])
+	})
+}
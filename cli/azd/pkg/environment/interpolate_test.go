@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolate(t *testing.T) {
+	values := map[string]string{
+		"APP_NAME": "todo",
+		"EMPTY":    "",
+	}
+	lookup := func(key string) (string, bool) {
+		value, has := values[key]
+		return value, has
+	}
+
+	tests := map[string]struct {
+		template string
+		expected string
+	}{
+		"Reference":           {template: "app-${APP_NAME}", expected: "app-todo"},
+		"EnvReference":        {template: "app-${env:APP_NAME}", expected: "app-todo"},
+		"Empty":               {template: "app-${EMPTY}", expected: "app-"},
+		"Default":             {template: "${MISSING=default}-${MISSING:-other}", expected: "default-other"},
+		"EscapedReference":    {template: "$${APP_NAME}", expected: "${APP_NAME}"},
+		"EscapedEnvReference": {template: "$${env:APP_NAME}", expected: "${env:APP_NAME}"},
+		"NoReference":         {template: "app", expected: "app"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			value, err := Interpolate(test.template, lookup)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, value)
+		})
+	}
+
+	t.Run("Unresolved", func(t *testing.T) {
+		_, err := Interpolate("${MISSING}-${env:OTHER}-${MISSING}-${APP_NAME}", lookup)
+
+		var unresolvedErr *UnresolvedReferencesError
+		require.True(t, errors.As(err, &unresolvedErr))
+		require.Equal(t, []string{"MISSING", "OTHER"}, unresolvedErr.Names)
+		require.Contains(t, err.Error(), "${MISSING}, ${OTHER} not set in the environment")
+	})
+}
+
+func TestReplaceEnvReferences(t *testing.T) {
+	script := "echo ${env:APP_NAME} ${LOCAL} $${env:APP_NAME} ${env:APP_NAME}"
+
+	require.Equal(t, []string{"APP_NAME"}, EnvReferences(script))
+	require.Equal(
+		t,
+		"echo ${APP_NAME} ${LOCAL} ${env:APP_NAME} ${APP_NAME}",
+		ReplaceEnvReferences(script, func(name string) string {
+			return "${" + name + "}"
+		}),
+	)
+}
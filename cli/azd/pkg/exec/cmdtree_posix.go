@@ -35,5 +35,11 @@ func (o *CmdTree) Start() error {
 }
 
 func (o *CmdTree) Kill() {
+	// Interactive commands aren't the leader of a process group, only the command itself is killed
+	if o.Interactive {
+		_ = o.Cmd.Process.Kill()
+		return
+	}
+
 	_ = syscall.Kill(-o.Cmd.Process.Pid, syscall.SIGKILL)
 }
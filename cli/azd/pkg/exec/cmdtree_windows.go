@@ -31,8 +31,12 @@ type winProcess struct {
 }
 
 func (o *CmdTree) Start() error {
-	o.SysProcAttr = &syscall.SysProcAttr{
-		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
+	// Interactive commands stay in the process group of azd so the console sends them Ctrl+C, which is disabled for
+	// the processes of a new process group
+	if !o.Interactive {
+		o.SysProcAttr = &syscall.SysProcAttr{
+			CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
+		}
 	}
 
 	err := o.Cmd.Start()
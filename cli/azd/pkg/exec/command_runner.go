@@ -13,6 +13,11 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry/events"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry/fields"
 )
 
 // Settings to modify the way CmdTree is executed
@@ -30,10 +35,17 @@ type CommandRunner interface {
 // stdin, stdout & stderr will be used by default during interactive commands
 // unless specifically overridden within the command run arguments.
 func NewCommandRunner(stdin io.Reader, stdout io.Writer, stderr io.Writer) CommandRunner {
+	return NewCommandRunnerWithShell(stdin, stdout, stderr, "")
+}
+
+// NewCommandRunnerWithShell creates a command runner running shell commands with posixShell, the name or the path of a
+// POSIX shell, on Linux and macOS. When posixShell is empty, the first of /bin/sh, sh, bash and dash found is used.
+func NewCommandRunnerWithShell(stdin io.Reader, stdout io.Writer, stderr io.Writer, posixShell string) CommandRunner {
 	return &commandRunner{
-		stdin:  stdin,
-		stdout: stdout,
-		stderr: stderr,
+		stdin:      stdin,
+		stdout:     stdout,
+		stderr:     stderr,
+		posixShell: posixShell,
 	}
 }
 
@@ -43,6 +55,8 @@ type commandRunner struct {
 	stdin  io.Reader
 	stdout io.Writer
 	stderr io.Writer
+	// the POSIX shell configured by the user, empty to probe posixShells
+	posixShell string
 }
 
 // Run runs the command specified in 'args'.
@@ -55,10 +69,23 @@ type commandRunner struct {
 // NOTE: on Windows the command will automatically be run within a shell. This means .bat/.cmd
 // file based commands should just work.
 func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error) {
+	ctx, span := telemetry.GetTracer().Start(ctx, events.ExecRunEvent)
+	// Only the name of the executable is recorded, arguments may contain user data.
+	span.SetAttributes(fields.ExecCmdKey.String(filepath.Base(args.Cmd)))
+
+	result, err := r.run(ctx, args)
+	span.SetAttributes(fields.ExecExitCodeKey.Int(result.ExitCode))
+	span.EndWithStatus(err)
+
+	return result, err
+}
+
+func (r *commandRunner) run(ctx context.Context, args RunArgs) (RunResult, error) {
 	// use the shell on Windows since most commands are actually just batch files wrapping
 	// real commands. And even if they're not, this will work fine without having to do any
 	// probing or checking.
-	cmd, err := newCmdTree(ctx, args.Cmd, args.Args, args.UseShell || runtime.GOOS == "windows", args.Interactive)
+	cmd, err := newCmdTree(
+		ctx, args.Cmd, args.Args, args.UseShell || runtime.GOOS == "windows", args.Interactive, r.posixShell)
 
 	if err != nil {
 		return RunResult{}, err
@@ -73,7 +100,7 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		stdin = new(bytes.Buffer)
 	}
 
-	var stdout, stderr bytes.Buffer
+	stdout, stderr := captureOutput(args.Stdout), captureOutput(args.Stderr)
 
 	cmd.Env = appendEnv(args.Env)
 
@@ -83,11 +110,15 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		cmd.Stderr = r.stderr
 	} else {
 		cmd.Stdin = stdin
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+
+		if args.Stdout != nil {
+			cmd.Stdout = io.MultiWriter(args.Stdout, stdout)
+		}
 
 		if args.Stderr != nil {
-			cmd.Stderr = io.MultiWriter(args.Stderr, &stderr)
+			cmd.Stderr = io.MultiWriter(args.Stderr, stderr)
 		}
 	}
 
@@ -100,15 +131,42 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 		}
 	}
 
+	if args.Interactive {
+		// Restores the modes of the terminal changed by the command, even when it's killed or azd panics
+		restoreTerminal := saveTerminalState(r.stdin)
+		defer restoreTerminal()
+	}
+
 	if err := cmd.Start(); err != nil {
 		return RunResult{}, err
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
+	if args.Interactive {
+		stopForwarding := forwardSignals(&cmd, r.stdin)
+		defer stopForwarding()
+	}
+
+	exited := make(chan struct{})
+	defer close(exited)
 
 	go func() {
-		<-ctx.Done()
+		select {
+		case <-ctx.Done():
+		case <-exited:
+			return
+		}
+
+		// Interactive commands receive the interrupt cancelling the context too, they're given time to handle it,
+		// like restoring the modes of the terminal, before they're killed
+		if args.Interactive {
+			select {
+			case <-exited:
+				return
+			case <-time.After(interactiveExitGracePeriod):
+				log.Printf("'%s' did not exit within %s of the cancellation, killing it", args.Cmd, interactiveExitGracePeriod)
+			}
+		}
+
 		cmd.Kill()
 	}()
 
@@ -146,7 +204,31 @@ func (r *commandRunner) Run(ctx context.Context, args RunArgs) (RunResult, error
 }
 
 func (r *commandRunner) RunList(ctx context.Context, commands []string, args RunArgs) (RunResult, error) {
-	process, err := newCmdTree(ctx, "", commands, true, false)
+	ctx, span := telemetry.GetTracer().Start(ctx, events.ExecRunEvent)
+	span.SetAttributes(fields.ExecCmdKey.String(commandNames(commands)))
+
+	result, err := r.runList(ctx, commands, args)
+	span.SetAttributes(fields.ExecExitCodeKey.Int(result.ExitCode))
+	span.EndWithStatus(err)
+
+	return result, err
+}
+
+// commandNames returns the names of the executables run by a list of shell commands, separated by commas. Like for
+// Run, the arguments of the commands aren't included since they may contain user data.
+func commandNames(commands []string) string {
+	names := make([]string, 0, len(commands))
+	for _, command := range commands {
+		if parts := strings.Fields(command); len(parts) > 0 {
+			names = append(names, filepath.Base(parts[0]))
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (r *commandRunner) runList(ctx context.Context, commands []string, args RunArgs) (RunResult, error) {
+	process, err := newCmdTree(ctx, "", commands, true, false, r.posixShell)
 	if err != nil {
 		return NewRunResult(-1, "", ""), err
 	}
@@ -187,16 +269,58 @@ func appendEnv(env []string) []string {
 	return nil
 }
 
+// PosixShellConfigPath is the path of the user configuration setting the POSIX shell running shell commands, for
+// systems where none of posixShells is found.
+const PosixShellConfigPath = "shell.posix"
+
+// posixShells are the shells probed, in order, to run shell commands on Linux and macOS. /bin/sh doesn't exist on
+// systems which don't follow the Filesystem Hierarchy Standard, like NixOS, where the shells are only found on the PATH.
+var posixShells = []string{"/bin/sh", "sh", "bash", "dash"}
+
+// findPosixShell returns the path of the POSIX shell running shell commands: the configured shell when it's set, or
+// else the first of posixShells found.
+func findPosixShell(configured string) (string, error) {
+	if configured != "" {
+		path, err := exec.LookPath(configured)
+		if err != nil {
+			return "", fmt.Errorf("finding the shell '%s' set in %s: %w", configured, PosixShellConfigPath, err)
+		}
+
+		return path, nil
+	}
+
+	for _, shell := range posixShells {
+		if path, err := exec.LookPath(shell); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"no POSIX shell found, looked for %s. Set the path of a shell with `azd config set %s <path>`",
+		strings.Join(posixShells, ", "),
+		PosixShellConfigPath,
+	)
+}
+
 // newCmdTree creates a `CmdTree`, optionally using a shell appropriate for windows
-// or POSIX environments.
+// or POSIX environments. On POSIX environments, posixShell is the configured shell, see findPosixShell.
 // An empty cmd parameter indicates "command list mode", which means that args are combined into a single command list,
 // joined with && operator.
-func newCmdTree(ctx context.Context, cmd string, args []string, useShell bool, interactive bool) (CmdTree, error) {
+func newCmdTree(
+	ctx context.Context, cmd string, args []string, useShell bool, interactive bool, posixShell string,
+) (CmdTree, error) {
 	options := CmdTreeOptions{Interactive: interactive}
 
 	if !useShell {
 		if cmd == "" {
 			return CmdTree{}, errors.New("command must be provided if shell is not used")
+		} else if interactive {
+			// The cancellation of interactive commands is handled by run, they're not killed as soon as the context
+			// is cancelled
+			return CmdTree{
+				CmdTreeOptions: options,
+				Cmd:            exec.Command(cmd, args...),
+			}, nil
 		} else {
 			return CmdTree{
 				CmdTreeOptions: options,
@@ -223,7 +347,12 @@ func newCmdTree(ctx context.Context, cmd string, args []string, useShell bool, i
 			args = append([]string{cmd}, args...)
 		}
 	} else {
-		shellName = filepath.Join("/", "bin", "sh")
+		shell, err := findPosixShell(posixShell)
+		if err != nil {
+			return CmdTree{}, err
+		}
+
+		shellName = shell
 		shellCommandPrefix = "-c"
 
 		if cmd == "" {
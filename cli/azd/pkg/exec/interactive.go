@@ -0,0 +1,48 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/shutdown"
+	"golang.org/x/term"
+)
+
+// interactiveExitGracePeriod is the time an interactive command has to exit after the context of the command is
+// cancelled, before it's killed. Interactive commands, like `gh auth login`, receive the interrupt from the terminal
+// and restore the modes of the terminal they changed before exiting.
+var interactiveExitGracePeriod = 5 * time.Second
+
+// saveTerminalState saves the modes of the terminal of stdin, like the echo disabled by the password prompts of
+// interactive commands. The returned function restores the saved modes, they're restored too when azd exits before the
+// command completes. It does nothing when stdin isn't a terminal.
+func saveTerminalState(stdin io.Reader) (restore func()) {
+	file, ok := stdin.(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		return func() {}
+	}
+
+	fd := int(file.Fd())
+	state, err := term.GetState(fd)
+	if err != nil {
+		log.Printf("saving the state of the terminal: %v", err)
+		return func() {}
+	}
+
+	restore = func() {
+		if err := term.Restore(fd, state); err != nil {
+			log.Printf("restoring the state of the terminal: %v", err)
+		}
+	}
+
+	unregister := shutdown.OnExit(restore)
+	return func() {
+		unregister()
+		restore()
+	}
+}
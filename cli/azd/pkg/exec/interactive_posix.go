@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build !windows
+// +build !windows
+
+package exec
+
+import (
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// forwardSignals forwards the interrupts and the window size changes received by azd to an interactive command, until
+// stop is called. Interactive commands run in the process group of azd: when azd is in the foreground of the terminal,
+// the terminal sends the signals to the command itself, so they're only forwarded when azd isn't, or when they're sent
+// to azd alone, like SIGTERM.
+func forwardSignals(cmd *CmdTree, stdin io.Reader) (stop func()) {
+	signals := make(chan os.Signal, 4)
+	stopped := make(chan struct{})
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGWINCH, syscall.SIGTERM)
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig != syscall.SIGTERM && inTerminalForeground(stdin) {
+					continue
+				}
+
+				if err := cmd.Process.Signal(sig); err != nil {
+					log.Printf("forwarding %v to the interactive command: %v", sig, err)
+				}
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(stopped)
+	}
+}
+
+// inTerminalForeground returns true when the process group of azd is the foreground process group of the terminal of
+// stdin, which receives the signals generated by the terminal, like SIGINT for Ctrl+C.
+func inTerminalForeground(stdin io.Reader) bool {
+	file, ok := stdin.(*os.File)
+	if !ok {
+		return false
+	}
+
+	foreground, err := unix.IoctlGetInt(int(file.Fd()), unix.TIOCGPGRP)
+	if err != nil {
+		return false
+	}
+
+	return foreground == syscall.Getpgrp()
+}
@@ -0,0 +1,17 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build windows
+// +build windows
+
+package exec
+
+import (
+	"io"
+)
+
+// forwardSignals does nothing on Windows: interactive commands are attached to the console of azd, in its process
+// group, so the console sends them Ctrl+C itself, and the console window size is read by the commands.
+func forwardSignals(cmd *CmdTree, stdin io.Reader) (stop func()) {
+	return func() {}
+}
@@ -11,9 +11,15 @@ type RunArgs struct {
 	Cwd  string
 	Env  []string
 
+	// Stdout will receive a copy of the text written to Stdout by
+	// the command.
+	// NOTE: RunResult.Stdout will still contain the end of the stdout output, up to 64 KiB, so commands running for
+	// a long time, like dev servers, don't keep their whole output in memory.
+	Stdout io.Writer
+
 	// Stderr will receive a copy of the text written to Stderr by
 	// the command.
-	// NOTE: RunResult.Stderr will still contain stderr output.
+	// NOTE: RunResult.Stderr will still contain the end of the stderr output, up to 64 KiB.
 	Stderr io.Writer
 
 	// Debug will `log.Printf` the command and it's results after it completes.
@@ -85,6 +91,18 @@ func (b RunArgs) WithDebug(debug bool) RunArgs {
 	return b
 }
 
+// Updates the writer that receives a copy of the command stdout
+func (b RunArgs) WithStdOut(stdOut io.Writer) RunArgs {
+	b.Stdout = stdOut
+	return b
+}
+
+// Updates the writer that receives a copy of the command stderr
+func (b RunArgs) WithStdErr(stdErr io.Writer) RunArgs {
+	b.Stderr = stdErr
+	return b
+}
+
 // Updates the stdin reader that will be used while invoking the command
 func (b RunArgs) WithStdIn(stdIn io.Reader) RunArgs {
 	b.StdIn = stdIn
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"bytes"
+	"io"
+)
+
+// streamedOutputLimit is the size of the output kept in RunResult for the commands streaming their output to
+// RunArgs.Stdout or RunArgs.Stderr, which may run for as long as the user wants, like dev servers.
+const streamedOutputLimit = 64 * 1024
+
+// capturedOutput is the copy of the output of a command returned in RunResult.
+type capturedOutput interface {
+	io.Writer
+	String() string
+}
+
+// captureOutput returns the buffer capturing an output of a command. The whole output is kept unless it's streamed
+// to the writer of the caller, only its end is kept then.
+func captureOutput(stream io.Writer) capturedOutput {
+	if stream == nil {
+		return &bytes.Buffer{}
+	}
+
+	return &tailBuffer{size: streamedOutputLimit}
+}
+
+// tailBuffer is a writer keeping the last bytes written to it, up to its size.
+type tailBuffer struct {
+	size int
+	buf  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	if len(p) >= b.size {
+		b.buf = append(b.buf[:0], p[len(p)-b.size:]...)
+		return len(p), nil
+	}
+
+	if overflow := len(b.buf) + len(p) - b.size; overflow > 0 {
+		b.buf = b.buf[:copy(b.buf, b.buf[overflow:])]
+	}
+
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.buf)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exec
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailBuffer(t *testing.T) {
+	t.Run("KeepsTheEnd", func(t *testing.T) {
+		buffer := &tailBuffer{size: 8}
+		for _, line := range []string{"one\n", "two\n", "three\n"} {
+			n, err := buffer.Write([]byte(line))
+			require.NoError(t, err)
+			require.Equal(t, len(line), n)
+		}
+
+		require.Equal(t, "o\nthree\n", buffer.String())
+	})
+
+	t.Run("LargeWrite", func(t *testing.T) {
+		buffer := &tailBuffer{size: 4}
+		_, err := buffer.Write([]byte("ab"))
+		require.NoError(t, err)
+		_, err = buffer.Write([]byte("0123456789"))
+		require.NoError(t, err)
+
+		require.Equal(t, "6789", buffer.String())
+	})
+
+	t.Run("StreamedOutputIsBounded", func(t *testing.T) {
+		require.IsType(t, &bytes.Buffer{}, captureOutput(nil))
+
+		output := captureOutput(&bytes.Buffer{})
+		for i := 0; i < 3; i++ {
+			_, err := output.Write(bytes.Repeat([]byte{'x'}, streamedOutputLimit))
+			require.NoError(t, err)
+		}
+
+		require.Len(t, output.String(), streamedOutputLimit)
+	})
+}
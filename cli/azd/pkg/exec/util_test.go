@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"sort"
@@ -110,6 +111,38 @@ func TestRunList(t *testing.T) {
 	}
 }
 
+func TestFindPosixShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shells are only used on Linux and macOS")
+	}
+
+	// Without /bin/sh, the shells are found on the PATH
+	shellsDir := t.TempDir()
+	bashPath := filepath.Join(shellsDir, "bash")
+	require.NoError(t, os.WriteFile(bashPath, []byte("#!/bin/sh"), 0755))
+	t.Setenv("PATH", shellsDir)
+
+	original := posixShells
+	posixShells = []string{filepath.Join(shellsDir, "sh"), "sh", "bash", "dash"}
+	t.Cleanup(func() { posixShells = original })
+
+	shell, err := findPosixShell("")
+	require.NoError(t, err)
+	require.Equal(t, bashPath, shell)
+
+	// The configured shell is used instead of probing
+	_, err = findPosixShell("zsh")
+	require.ErrorContains(t, err, PosixShellConfigPath)
+
+	require.NoError(t, os.Remove(bashPath))
+	_, err = findPosixShell("")
+	require.ErrorContains(t, err, "no POSIX shell found")
+}
+
+func TestCommandNames(t *testing.T) {
+	require.Equal(t, "git,npm", commandNames([]string{"/usr/bin/git --version", "  ", "npm run build --token=secret"}))
+}
+
 func TestRunCapturingStderr(t *testing.T) {
 	myStderr := &bytes.Buffer{}
 
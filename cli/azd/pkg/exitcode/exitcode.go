@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package exitcode classifies the failures of azd, so the process exits with a code specific to the class of failure.
+//
+// Wrapping scripts and IDE extensions branch on the exit code (or on the category of the error object written to
+// stderr when --output json is used) instead of parsing error messages.
+package exitcode
+
+import (
+	"context"
+	"errors"
+	osexec "os/exec"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/shutdown"
+)
+
+// Category is the class of a failure.
+type Category string
+
+const (
+	// CategoryUnknown is used for failures which have not been classified.
+	CategoryUnknown Category = "unknown"
+	// CategoryValidation is used for invalid flags, arguments or configuration provided by the user.
+	CategoryValidation Category = "validation"
+	// CategoryAuth is used when the user is not logged in, or the credentials can't be used.
+	CategoryAuth Category = "auth"
+	// CategoryProvisioning is used when the infrastructure of the application can't be provisioned.
+	CategoryProvisioning Category = "provisioning"
+	// CategoryDeployment is used when a service of the application can't be packaged or deployed.
+	CategoryDeployment Category = "deployment"
+	// CategoryExternalTool is used when an external tool (az, docker, dotnet, ...) is missing or fails.
+	CategoryExternalTool Category = "externalTool"
+	// CategoryCancelled is used when the user cancels the operation.
+	CategoryCancelled Category = "cancelled"
+)
+
+var exitCodes = map[Category]int{
+	CategoryUnknown:      1,
+	CategoryValidation:   2,
+	CategoryAuth:         3,
+	CategoryProvisioning: 4,
+	CategoryDeployment:   5,
+	CategoryExternalTool: 6,
+	CategoryCancelled:    shutdown.ExitCodeCancelled,
+}
+
+// ExitCode returns the process exit code for the category. Unknown categories exit with 1.
+func (c Category) ExitCode() int {
+	if code, has := exitCodes[c]; has {
+		return code
+	}
+
+	return exitCodes[CategoryUnknown]
+}
+
+// Error associates an error with the category of the failure.
+type Error struct {
+	Category Category
+	Err      error
+}
+
+// New returns an error classified with the specified category.
+func New(category Category, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{
+		Category: category,
+		Err:      err,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Classify returns the category of err. The innermost classification explicitly set with New wins, otherwise the
+// category is inferred from well known errors.
+func Classify(err error) Category {
+	if err == nil {
+		return CategoryUnknown
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return CategoryCancelled
+	}
+
+	// errors.As stops on the first (outermost) match, walk the chain to find the most specific classification.
+	category := CategoryUnknown
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		if classified, ok := current.(*Error); ok {
+			category = classified.Category
+		}
+	}
+
+	if category != CategoryUnknown {
+		return category
+	}
+
+	if errors.Is(err, osexec.ErrNotFound) {
+		return CategoryExternalTool
+	}
+
+	return CategoryUnknown
+}
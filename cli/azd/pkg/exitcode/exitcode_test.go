@@ -0,0 +1,65 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	osexec "os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Classify(t *testing.T) {
+	authErr := New(CategoryAuth, errors.New("not logged in"))
+
+	tests := []struct {
+		name     string
+		err      error
+		expected Category
+	}{
+		{"Nil", nil, CategoryUnknown},
+		{"Unclassified", errors.New("boom"), CategoryUnknown},
+		{"Classified", New(CategoryValidation, errors.New("invalid flag")), CategoryValidation},
+		{"Wrapped", fmt.Errorf("running command: %w", authErr), CategoryAuth},
+		{"InnermostWins", New(CategoryDeployment, fmt.Errorf("deploying service: %w", authErr)), CategoryAuth},
+		{"Cancelled", New(CategoryDeployment, fmt.Errorf("deploying: %w", context.Canceled)), CategoryCancelled},
+		{"ToolNotFound", fmt.Errorf("running docker: %w", osexec.ErrNotFound), CategoryExternalTool},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Classify(tt.err))
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	require.Nil(t, New(CategoryAuth, nil))
+
+	inner := errors.New("not logged in")
+	err := New(CategoryAuth, inner)
+	require.ErrorIs(t, err, inner)
+	require.Equal(t, inner.Error(), err.Error())
+}
+
+func Test_ExitCode(t *testing.T) {
+	codes := map[int]Category{}
+	for _, category := range []Category{
+		CategoryUnknown,
+		CategoryValidation,
+		CategoryAuth,
+		CategoryProvisioning,
+		CategoryDeployment,
+		CategoryExternalTool,
+		CategoryCancelled,
+	} {
+		code := category.ExitCode()
+		require.NotContains(t, codes, code, "exit code %d is used by %s and %s", code, codes[code], category)
+		codes[code] = category
+	}
+
+	require.Equal(t, 1, CategoryUnknown.ExitCode())
+	require.Equal(t, 130, CategoryCancelled.ExitCode())
+	require.Equal(t, 1, Category("other").ExitCode())
+}
@@ -0,0 +1,144 @@
+package ext
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// The default images of the containers running hooks, per shell
+var defaultContainerImages = map[ShellType]string{
+	ShellTypeBash:       "mcr.microsoft.com/azure-cli",
+	ShellTypePowershell: "mcr.microsoft.com/azure-powershell",
+}
+
+const (
+	defaultContainerCpu    = 1.0
+	defaultContainerMemory = 1.5
+)
+
+// The characters not allowed in the names of container groups
+var containerGroupNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// execContainerHook runs the script of the hook in a transient container of Azure Container Instances, streaming the
+// logs of the container to the console when stream is true, and returns the exit code of the script.
+func (h *HooksRunner) execContainerHook(ctx context.Context, hookConfig *HookConfig, stream bool) (int, error) {
+	if h.azCli == nil {
+		return -1, fmt.Errorf("'%s' hook can't run in a container in this context", hookConfig.Name)
+	}
+
+	subscriptionId := h.env.GetSubscriptionId()
+	if subscriptionId == "" {
+		return -1, fmt.Errorf(
+			"'%s' hook can't run in a container: %s isn't set", hookConfig.Name, environment.SubscriptionIdEnvVarName)
+	}
+
+	resourceGroup, group, err := h.containerGroup(hookConfig)
+	if err != nil {
+		return -1, fmt.Errorf("'%s' hook can't run in a container: %w", hookConfig.Name, err)
+	}
+
+	name, err := containerGroupName(hookConfig.Name)
+	if err != nil {
+		return -1, err
+	}
+
+	var logs io.Writer = io.Discard
+	if stream {
+		logs = h.console.GetWriter()
+		h.console.Message(ctx, output.WithGrayFormat("Running in container group %s of resource group %s",
+			name, resourceGroup))
+	}
+
+	return h.azCli.RunContainer(ctx, subscriptionId, resourceGroup, name, group, logs)
+}
+
+// containerGroup returns the resource group, and the container group, running the script of the hook.
+// The values of the environment are passed to the container as secure environment variables.
+func (h *HooksRunner) containerGroup(hookConfig *HookConfig) (string, azsdk.ContainerGroup, error) {
+	config := *hookConfig.Container
+	if config.ResourceGroup == "" {
+		config.ResourceGroup = fmt.Sprintf("${%s}", environment.ResourceGroupEnvVarName)
+	}
+
+	if config.Location == "" {
+		config.Location = fmt.Sprintf("${%s}", environment.LocationEnvVarName)
+	}
+
+	if config.Image == "" {
+		config.Image = defaultContainerImages[hookConfig.Shell]
+	}
+
+	for _, value := range []*string{
+		&config.Image, &config.Identity, &config.SubnetId, &config.ResourceGroup, &config.Location,
+	} {
+		resolved, err := environment.Interpolate(*value, h.env.LookupEnv)
+		if err != nil {
+			return "", azsdk.ContainerGroup{}, err
+		}
+
+		*value = resolved
+	}
+
+	script, err := os.ReadFile(hookConfig.path)
+	if err != nil {
+		return "", azsdk.ContainerGroup{}, fmt.Errorf("reading script '%s': %w", hookConfig.path, err)
+	}
+
+	command := []string{"bash", "-c", string(script)}
+	if hookConfig.Shell == ShellTypePowershell {
+		command = []string{"pwsh", "-Command", string(script)}
+	}
+
+	variables := map[string]string{}
+	for _, variable := range h.environ() {
+		name, value, _ := strings.Cut(variable, "=")
+		variables[name] = value
+	}
+
+	group := azsdk.ContainerGroup{
+		Location:    config.Location,
+		Image:       config.Image,
+		Command:     command,
+		Environment: variables,
+		Cpu:         config.Cpu,
+		MemoryInGB:  config.Memory,
+		IdentityId:  config.Identity,
+		SubnetId:    config.SubnetId,
+	}
+
+	if group.Cpu == 0 {
+		group.Cpu = defaultContainerCpu
+	}
+
+	if group.MemoryInGB == 0 {
+		group.MemoryInGB = defaultContainerMemory
+	}
+
+	return config.ResourceGroup, group, nil
+}
+
+// containerGroupName returns a unique name for the container group running the hook.
+func containerGroupName(hookName string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating container group name: %w", err)
+	}
+
+	name := strings.Trim(containerGroupNameInvalidChars.ReplaceAllString(strings.ToLower(hookName), "-"), "-")
+	// Container group names are at most 63 characters long
+	if len(name) > 44 {
+		name = strings.TrimRight(name[:44], "-")
+	}
+
+	return fmt.Sprintf("azd-hook-%s-%s", name, hex.EncodeToString(suffix)), nil
+}
@@ -0,0 +1,97 @@
+package ext
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainerHook_ContainerGroup(t *testing.T) {
+	cwd := t.TempDir()
+	scriptPath := filepath.Join("scripts", "schema.sh")
+	require.NoError(t, os.MkdirAll(filepath.Join(cwd, "scripts"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(cwd, scriptPath), []byte("sqlcmd -i schema.sql"), osutil.PermissionFile))
+
+	env := environment.EphemeralWithValues("test", map[string]string{
+		environment.ResourceGroupEnvVarName: "rg-test",
+		environment.LocationEnvVarName:      "eastus2",
+		"SQL_SUBNET_ID":                     "SUBNET_ID",
+	})
+
+	mockContext := mocks.NewMockContext(context.Background())
+	runner := NewHooksRunner(NewHooksManager(cwd), mockContext.CommandRunner, mockContext.Console, nil, cwd, nil, env)
+
+	t.Run("Defaults", func(t *testing.T) {
+		hookConfig := &HookConfig{
+			Name:      "postprovision",
+			Run:       scriptPath,
+			cwd:       cwd,
+			Container: &ContainerHookConfig{SubnetId: "${SQL_SUBNET_ID}", Identity: "IDENTITY_ID"},
+		}
+		require.NoError(t, hookConfig.validate())
+
+		resourceGroup, group, err := runner.containerGroup(hookConfig)
+		require.NoError(t, err)
+		require.Equal(t, "rg-test", resourceGroup)
+		require.Equal(t, azsdk.ContainerGroup{
+			Location: "eastus2",
+			Image:    "mcr.microsoft.com/azure-cli",
+			Command:  []string{"bash", "-c", "sqlcmd -i schema.sql"},
+			Environment: map[string]string{
+				environment.ResourceGroupEnvVarName: "rg-test",
+				environment.LocationEnvVarName:      "eastus2",
+				"SQL_SUBNET_ID":                     "SUBNET_ID",
+				EnvNameEnvVarName:                   "test",
+			},
+			Cpu:        1,
+			MemoryInGB: 1.5,
+			IdentityId: "IDENTITY_ID",
+			SubnetId:   "SUBNET_ID",
+		}, group)
+	})
+
+	t.Run("UnresolvedReference", func(t *testing.T) {
+		hookConfig := &HookConfig{
+			Name:      "postprovision",
+			Shell:     ShellTypePowershell,
+			Run:       scriptPath,
+			cwd:       cwd,
+			Container: &ContainerHookConfig{SubnetId: "${MISSING_SUBNET_ID}"},
+		}
+		require.NoError(t, hookConfig.validate())
+
+		_, _, err := runner.containerGroup(hookConfig)
+		var unresolvedErr *environment.UnresolvedReferencesError
+		require.ErrorAs(t, err, &unresolvedErr)
+	})
+
+	t.Run("Interactive", func(t *testing.T) {
+		hookConfig := &HookConfig{
+			Name:        "postprovision",
+			Run:         scriptPath,
+			cwd:         cwd,
+			Interactive: true,
+			Container:   &ContainerHookConfig{},
+		}
+		require.ErrorIs(t, hookConfig.validate(), ErrContainerInteractive)
+	})
+}
+
+func Test_ContainerHook_ContainerGroupName(t *testing.T) {
+	name, err := containerGroupName("Post_Provision")
+	require.NoError(t, err)
+	require.Regexp(t, `^azd-hook-post-provision-[0-9a-f]{8}$`, name)
+
+	first, err := containerGroupName("postprovision")
+	require.NoError(t, err)
+	second, err := containerGroupName("postprovision")
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+}
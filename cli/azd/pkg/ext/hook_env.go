@@ -0,0 +1,59 @@
+package ext
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+)
+
+// The variables azd sets in the environment of hooks, on top of the values of the azd environment, so scripts don't have
+// to locate and parse the .env file of the environment.
+const (
+	// The name of the azd environment
+	EnvNameEnvVarName = "AZD_ENV_NAME"
+	// The name of the service, set for service hooks only
+	ServiceNameEnvVarName = "AZD_SERVICE_NAME"
+	// The path of the package of the service, set for the service hooks running once the service is packaged:
+	// postpackage, predeploy and postdeploy
+	ServicePackagePathEnvVarName = "AZD_SERVICE_PACKAGE_PATH"
+	// The outputs of the last provisioning of the environment, as a JSON object keyed by output name, set once the
+	// environment is provisioned
+	ProvisionOutputsEnvVarName = "AZD_PROVISION_OUTPUTS"
+)
+
+// ForService returns a runner of the same hooks, running them for the service: the name of the service and the path of
+// its package are set in the environment of the hooks. packagePath is empty when the service isn't packaged yet.
+func (h *HooksRunner) ForService(serviceName string, packagePath string) *HooksRunner {
+	runner := *h
+	runner.serviceName = serviceName
+	runner.servicePackagePath = packagePath
+
+	return &runner
+}
+
+// environ returns the variables of the environment of the hooks, the values of the azd environment followed by the
+// variables set by azd.
+func (h *HooksRunner) environ() []string {
+	variables := h.env.Environ()
+	variables = append(variables, fmt.Sprintf("%s=%s", EnvNameEnvVarName, h.env.GetEnvName()))
+
+	if h.serviceName != "" {
+		variables = append(variables, fmt.Sprintf("%s=%s", ServiceNameEnvVarName, h.serviceName))
+	}
+
+	if h.servicePackagePath != "" {
+		variables = append(variables, fmt.Sprintf("%s=%s", ServicePackagePathEnvVarName, h.servicePackagePath))
+	}
+
+	if outputs, has := h.env.Config.Get(environment.ProvisionOutputsConfigPath); has {
+		if content, err := json.Marshal(outputs); err != nil {
+			log.Printf("failed marshalling provision outputs for hooks: %v", err)
+		} else {
+			variables = append(variables, fmt.Sprintf("%s=%s", ProvisionOutputsEnvVarName, string(content)))
+		}
+	}
+
+	return variables
+}
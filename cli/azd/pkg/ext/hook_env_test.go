@@ -0,0 +1,48 @@
+package ext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_HooksRunner_environ(t *testing.T) {
+	cwd := t.TempDir()
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		"API_URL": "https://api.azurewebsites.net",
+	})
+
+	mockContext := mocks.NewMockContext(context.Background())
+	runner := NewHooksRunner(NewHooksManager(cwd), mockContext.CommandRunner, mockContext.Console, nil, cwd, nil, env)
+
+	t.Run("Project", func(t *testing.T) {
+		require.ElementsMatch(t, []string{
+			"API_URL=https://api.azurewebsites.net",
+			"AZD_ENV_NAME=dev",
+		}, runner.environ())
+	})
+
+	t.Run("Service", func(t *testing.T) {
+		serviceRunner := runner.ForService("api", "/tmp/api.zip")
+		require.ElementsMatch(t, []string{
+			"API_URL=https://api.azurewebsites.net",
+			"AZD_ENV_NAME=dev",
+			"AZD_SERVICE_NAME=api",
+			"AZD_SERVICE_PACKAGE_PATH=/tmp/api.zip",
+		}, serviceRunner.environ())
+
+		// The runner of the project is left unchanged
+		require.NotContains(t, runner.environ(), "AZD_SERVICE_NAME=api")
+	})
+
+	t.Run("Provisioned", func(t *testing.T) {
+		require.NoError(t, env.Config.Set(environment.ProvisionOutputsConfigPath, map[string]any{
+			"API_URL": "https://api.azurewebsites.net",
+		}))
+
+		require.Contains(t, runner.environ(), `AZD_PROVISION_OUTPUTS={"API_URL":"https://api.azurewebsites.net"}`)
+	})
+}
@@ -66,6 +66,16 @@ func (h *HooksManager) GetByParams(
 	return h.filterConfigs(hooks, predicate)
 }
 
+// Gets an array of hook configurations matching the specified hook name, ex) predeploy or seed-database
+// Will return an error if any configuration errors are found
+func (h *HooksManager) GetByName(hooks map[string]*HookConfig, name string) ([]*HookConfig, error) {
+	predicate := func(scriptName string, hookConfig *HookConfig) bool {
+		return strings.EqualFold(scriptName, name)
+	}
+
+	return h.filterConfigs(hooks, predicate)
+}
+
 // Filters the specified hook configurations based on the predicate
 // Will return an error if any configuration errors are found
 func (h *HooksManager) filterConfigs(
@@ -99,6 +99,39 @@ func Test_GetByParams(t *testing.T) {
 	})
 }
 
+func Test_GetByName(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	hooks := map[string]*HookConfig{
+		"preinit": {
+			Run: "scripts/preinit.sh",
+		},
+		"seed-database": {
+			Run: "scripts/seed-database.sh",
+		},
+	}
+
+	ensureScriptsExist(t, hooks)
+
+	hooksManager := NewHooksManager(tempDir)
+
+	t.Run("Match", func(t *testing.T) {
+		validHooks, err := hooksManager.GetByName(hooks, "seed-database")
+
+		require.NoError(t, err)
+		require.Len(t, validHooks, 1)
+		require.Equal(t, hooks["seed-database"], validHooks[0])
+	})
+
+	t.Run("No Match", func(t *testing.T) {
+		validHooks, err := hooksManager.GetByName(hooks, "postinit")
+
+		require.NoError(t, err)
+		require.Empty(t, validHooks)
+	})
+}
+
 func ensureScriptsExist(t *testing.T, configs map[string]*HookConfig) {
 	for _, hook := range configs {
 		ext := filepath.Ext(hook.Run)
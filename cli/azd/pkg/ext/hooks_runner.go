@@ -12,6 +12,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bash"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/powershell"
 )
@@ -22,17 +23,23 @@ type HooksRunner struct {
 	hooksManager  *HooksManager
 	commandRunner exec.CommandRunner
 	console       input.Console
+	azCli         azcli.AzCli
 	cwd           string
 	hooks         map[string]*HookConfig
 	env           *environment.Environment
+	// Set for the hooks of a service, see ForService
+	serviceName        string
+	servicePackagePath string
 }
 
 // NewHooks creates a new instance of CommandHooks
 // When `cwd` is empty defaults to current shell working directory
+// `azCli` runs the hooks configured with a container, and may be nil when no hook runs in a container
 func NewHooksRunner(
 	hooksManager *HooksManager,
 	commandRunner exec.CommandRunner,
 	console input.Console,
+	azCli azcli.AzCli,
 	cwd string,
 	hooks map[string]*HookConfig,
 	env *environment.Environment,
@@ -50,6 +57,7 @@ func NewHooksRunner(
 		hooksManager:  hooksManager,
 		commandRunner: commandRunner,
 		console:       console,
+		azCli:         azCli,
 		cwd:           cwd,
 		hooks:         hooks,
 		env:           env,
@@ -83,6 +91,25 @@ func (h *HooksRunner) RunHooks(ctx context.Context, hookType HookType, commands
 		return fmt.Errorf("failed running scripts for hooks '%s', %w", strings.Join(commands, ","), err)
 	}
 
+	return h.runHooks(ctx, hooks)
+}
+
+// Invokes the registered script hook with the specified name, independently of any command.
+// Returns ErrHookNotFound when no hook with the specified name is registered.
+func (h *HooksRunner) RunHook(ctx context.Context, name string) error {
+	hooks, err := h.hooksManager.GetByName(h.hooks, name)
+	if err != nil {
+		return fmt.Errorf("failed running scripts for hook '%s', %w", name, err)
+	}
+
+	if len(hooks) == 0 {
+		return ErrHookNotFound
+	}
+
+	return h.runHooks(ctx, hooks)
+}
+
+func (h *HooksRunner) runHooks(ctx context.Context, hooks []*HookConfig) error {
 	for _, hookConfig := range hooks {
 		if err := h.env.Reload(); err != nil {
 			return fmt.Errorf("reloading environment before running hook: %w", err)
@@ -110,9 +137,9 @@ func (h *HooksRunner) GetScript(hookConfig *HookConfig) (tools.Script, error) {
 
 	switch hookConfig.Shell {
 	case ShellTypeBash:
-		return bash.NewBashScript(h.commandRunner, h.cwd, h.env.Environ()), nil
+		return bash.NewBashScript(h.commandRunner, h.cwd, h.environ()), nil
 	case ShellTypePowershell:
-		return powershell.NewPowershellScript(h.commandRunner, h.cwd, h.env.Environ()), nil
+		return powershell.NewPowershellScript(h.commandRunner, h.cwd, h.environ()), nil
 	default:
 		return nil, fmt.Errorf(
 			"shell type '%s' is not a valid option. Only 'sh' and 'pwsh' are supported",
@@ -127,6 +154,21 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig) erro
 		return err
 	}
 
+	// ${env:KEY} is PowerShell syntax, resolved by PowerShell itself when the script runs, like a value the script sets
+	if hookConfig.location == ScriptLocationInline && hookConfig.Shell != ShellTypePowershell {
+		missing := []string{}
+		for _, name := range environment.EnvReferences(hookConfig.script) {
+			if _, has := h.env.LookupEnv(name); !has {
+				missing = append(missing, name)
+			}
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf(
+				"'%s' hook can't run: %w", hookConfig.Name, &environment.UnresolvedReferencesError{Names: missing})
+		}
+	}
+
 	formatter := h.console.GetFormatter()
 	consoleInteractive := formatter == nil || formatter.Kind() == output.NoneFormat
 	scriptInteractive := consoleInteractive && hookConfig.Interactive
@@ -145,13 +187,25 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig) erro
 		)
 	}
 
-	log.Printf("Executing script '%s'\n", hookConfig.path)
-	res, err := script.Execute(ctx, hookConfig.path, scriptInteractive)
+	var exitCode int
+	if hookConfig.Container != nil {
+		log.Printf("Executing script '%s' in a container\n", hookConfig.path)
+		exitCode, err = h.execContainerHook(ctx, hookConfig, consoleInteractive)
+		if err == nil && exitCode != 0 {
+			err = fmt.Errorf("the container exited with code %d", exitCode)
+		}
+	} else {
+		log.Printf("Executing script '%s'\n", hookConfig.path)
+		var res exec.RunResult
+		res, err = script.Execute(ctx, hookConfig.path, scriptInteractive)
+		exitCode = res.ExitCode
+	}
+
 	if err != nil {
 		execErr := fmt.Errorf(
 			"'%s' hook failed with exit code: '%d', Path: '%s'. : %w",
 			hookConfig.Name,
-			res.ExitCode,
+			exitCode,
 			hookConfig.path,
 			err,
 		)
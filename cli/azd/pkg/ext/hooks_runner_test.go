@@ -60,14 +60,14 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPreHook = true
 			require.Equal(t, "scripts/precommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, append(env.Environ(), "AZD_ENV_NAME=test"), args.Env)
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 		err := runner.RunHooks(*mockContext.Context, HookTypePre, "command")
 
 		require.True(t, ranPreHook)
@@ -86,14 +86,14 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/postcommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, append(env.Environ(), "AZD_ENV_NAME=test"), args.Env)
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 		err := runner.RunHooks(*mockContext.Context, HookTypePost, "command")
 
 		require.False(t, ranPreHook)
@@ -112,14 +112,14 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/preinteractive.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			require.ElementsMatch(t, append(env.Environ(), "AZD_ENV_NAME=test"), args.Env)
 			require.Equal(t, true, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 		err := runner.RunHooks(*mockContext.Context, HookTypePre, "interactive")
 
 		require.False(t, ranPreHook)
@@ -141,7 +141,7 @@ func Test_Hooks_Execute(t *testing.T) {
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 		err := runner.RunHooks(*mockContext.Context, HookTypePre, "inline")
 
 		require.False(t, ranPreHook)
@@ -178,7 +178,7 @@ func Test_Hooks_Execute(t *testing.T) {
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 		err := runner.Invoke(*mockContext.Context, []string{"command"}, func() error {
 			ranAction = true
 			hookLog = append(hookLog, "action")
@@ -231,7 +231,7 @@ func Test_Hooks_GetScript(t *testing.T) {
 		hookConfig := hooks["bash"]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 
 		script, err := runner.GetScript(hookConfig)
 		require.NotNil(t, script)
@@ -245,7 +245,7 @@ func Test_Hooks_GetScript(t *testing.T) {
 		hookConfig := hooks["pwsh"]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 
 		script, err := runner.GetScript(hookConfig)
 		require.NotNil(t, script)
@@ -262,7 +262,7 @@ func Test_Hooks_GetScript(t *testing.T) {
 		hookConfig := hooks["inline"]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
 
 		script, err := runner.GetScript(hookConfig)
 		require.NotNil(t, script)
@@ -279,6 +279,102 @@ func Test_Hooks_GetScript(t *testing.T) {
 	})
 }
 
+func Test_Hooks_EnvReferences(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.EphemeralWithValues(
+		"test",
+		map[string]string{
+			"a": "apple",
+		},
+	)
+
+	hooks := map[string]*HookConfig{
+		"prebash": {
+			Shell: ShellTypeBash,
+			Run:   "echo ${env:a} ${local} $${env:a}",
+		},
+		"prepwsh": {
+			Shell: ShellTypePowershell,
+			Run:   "Write-Host ${env:a}",
+		},
+		"premissing": {
+			Shell: ShellTypeBash,
+			Run:   "echo ${env:a} ${env:missing}",
+		},
+		"prepwshruntime": {
+			Shell: ShellTypePowershell,
+			Run:   "$env:runtime = 'set'; Write-Host ${env:runtime}",
+		},
+	}
+
+	t.Run("Bash", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
+
+		_, err := runner.GetScript(hooks["prebash"])
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(hooks["prebash"].path)
+		require.NoError(t, err)
+		require.Contains(t, string(content), "echo ${a} ${local} ${env:a}")
+	})
+
+	t.Run("Powershell", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
+
+		_, err := runner.GetScript(hooks["prepwsh"])
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(hooks["prepwsh"].path)
+		require.NoError(t, err)
+		require.Contains(t, string(content), "Write-Host ${env:a}")
+	})
+
+	t.Run("Unresolved", func(t *testing.T) {
+		ran := false
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, "missing")
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "${missing} not set in the environment")
+		require.False(t, ran)
+	})
+
+	t.Run("PowershellNotChecked", func(t *testing.T) {
+		ran := false
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, mockContext.Console, nil, cwd, hooks, env)
+		err := runner.RunHooks(*mockContext.Context, HookTypePre, "pwshruntime")
+
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+}
+
 type scriptValidationTest struct {
 	name          string
 	config        *HookConfig
@@ -301,6 +397,7 @@ func Test_GetScript_Validation(t *testing.T) {
 		hooksManager,
 		mockContext.CommandRunner,
 		mockContext.Console,
+		nil,
 		tempDir,
 		map[string]*HookConfig{},
 		env,
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 )
 
@@ -32,6 +33,8 @@ var (
 	)
 	ErrRunRequired           error = errors.New("run is always required")
 	ErrUnsupportedScriptType error = errors.New("script type is not valid. Only '.sh' and '.ps1' are supported")
+	ErrHookNotFound          error = errors.New("hook not found")
+	ErrContainerInteractive  error = errors.New("hooks running in a container can't be interactive")
 )
 
 // Generic action function that may return an error
@@ -63,12 +66,34 @@ type HookConfig struct {
 	ContinueOnError bool `yaml:"continueOnError,omitempty"`
 	// When set to true will bind the stdin, stdout & stderr to the running console
 	Interactive bool `yaml:"interactive,omitempty"`
+	// When set runs the script in a transient container of Azure Container Instances instead of the local shell
+	Container *ContainerHookConfig `yaml:"container,omitempty"`
 	// When running on windows use this override config
 	Windows *HookConfig `yaml:"windows,omitempty"`
 	// When running on linux/macos use this override config
 	Posix *HookConfig `yaml:"posix,omitempty"`
 }
 
+// Configuration of the transient container running a hook, for the hooks which need network line-of-sight on private
+// resources, like a database only reachable in a virtual network. All values support environment variable substitution.
+type ContainerHookConfig struct {
+	// The image of the container, which must provide the shell of the hook.
+	// Defaults to the Azure CLI image for sh hooks and to the Azure PowerShell image for pwsh hooks.
+	Image string `yaml:"image,omitempty"`
+	// The resource id of the user-assigned managed identity of the container, used by the script to sign in to Azure
+	Identity string `yaml:"identity,omitempty"`
+	// The resource id of the subnet of the virtual network the container is deployed to
+	SubnetId string `yaml:"subnetId,omitempty"`
+	// The resource group of the container. Defaults to AZURE_RESOURCE_GROUP.
+	ResourceGroup string `yaml:"resourceGroup,omitempty"`
+	// The location of the container. Defaults to AZURE_LOCATION.
+	Location string `yaml:"location,omitempty"`
+	// The CPU cores of the container. Defaults to 1.
+	Cpu float64 `yaml:"cpu,omitempty"`
+	// The memory of the container in GB. Defaults to 1.5.
+	Memory float64 `yaml:"memory,omitempty"`
+}
+
 // Validates and normalizes the hook configuration
 func (hc *HookConfig) validate() error {
 	if hc.validated {
@@ -79,6 +104,10 @@ func (hc *HookConfig) validate() error {
 		return ErrRunRequired
 	}
 
+	if hc.Container != nil && hc.Interactive {
+		return ErrContainerInteractive
+	}
+
 	hc.Run = strings.ReplaceAll(hc.Run, "/", string(os.PathSeparator))
 
 	scriptPath := hc.Run
@@ -146,6 +175,19 @@ func inferScriptTypeFromFilePath(path string) (ShellType, error) {
 	}
 }
 
+// replaceEnvReferences rewrites the ${env:KEY} references of an inline sh script to ${KEY}, the values of the environment
+// being set for the script. Other references, like ${KEY}, belong to the script and are kept as is. PowerShell scripts
+// are kept as is, ${env:KEY} being PowerShell syntax.
+func replaceEnvReferences(shell ShellType, script string) string {
+	if shell == ShellTypePowershell {
+		return script
+	}
+
+	return environment.ReplaceEnvReferences(script, func(name string) string {
+		return fmt.Sprintf("${%s}", name)
+	})
+}
+
 func createTempScript(hookConfig *HookConfig) (string, error) {
 	var ext string
 	scriptHeader := []string{}
@@ -174,7 +216,7 @@ func createTempScript(hookConfig *HookConfig) (string, error) {
 	}
 	scriptBuilder.WriteString("\n")
 	scriptBuilder.WriteString("# Auto generated file from Azure Developer CLI\n")
-	scriptBuilder.WriteString(hookConfig.script)
+	scriptBuilder.WriteString(replaceEnvReferences(hookConfig.Shell, hookConfig.script))
 
 	// Temp generated files are cleaned up automatically after script execution has completed.
 	_, err = file.WriteString(scriptBuilder.String())
@@ -44,3 +44,23 @@ func GetSlugForRemote(remoteUrl string) (string, error) {
 
 	return "", ErrRemoteHostIsNotGitHub
 }
+
+// GetSlugForRemoteOnHost is like GetSlugForRemote for remotes of hostname, like a GitHub Enterprise Server instance.
+func GetSlugForRemoteOnHost(remoteUrl string, hostname string) (string, error) {
+	if hostname == "" || hostname == "github.com" {
+		return GetSlugForRemote(remoteUrl)
+	}
+
+	host := regexp.QuoteMeta(hostname)
+	for _, r := range []*regexp.Regexp{
+		regexp.MustCompile(fmt.Sprintf(`^git@%s:(.*?)(?:\.git)?$`, host)),
+		regexp.MustCompile(fmt.Sprintf(`^https://%s/(.*?)(?:\.git)?$`, host)),
+	} {
+		captures := r.FindStringSubmatch(remoteUrl)
+		if captures != nil {
+			return captures[1], nil
+		}
+	}
+
+	return "", ErrRemoteHostIsNotGitHub
+}
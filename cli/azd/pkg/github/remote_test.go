@@ -38,3 +38,30 @@ func TestGetGitHubSlugForRemote(t *testing.T) {
 		assert.Equal(t, tst.result, slug, "expected equal for %s", tst.remote)
 	}
 }
+
+func TestGetGitHubSlugForRemoteOnHost(t *testing.T) {
+	cases := []struct {
+		remote  string
+		result  string
+		isError bool
+	}{
+		{remote: "git@github.contoso.com:Foo/bar.git", result: "Foo/bar"},
+		{remote: "https://github.contoso.com/Foo/bar.git", result: "Foo/bar"},
+		{remote: "https://github.contoso.com/Foo/bar", result: "Foo/bar"},
+
+		{remote: "https://github.com/Foo/bar.git", isError: true},
+		{remote: "https://githubXcontoso.com/Foo/bar.git", isError: true},
+	}
+
+	for _, tst := range cases {
+		slug, err := GetSlugForRemoteOnHost(tst.remote, "github.contoso.com")
+
+		if tst.isError {
+			require.Error(t, err, "expected error for %s", tst.remote)
+		} else {
+			require.NoError(t, err, "expected no error for %s", tst.remote)
+		}
+
+		assert.Equal(t, tst.result, slug, "expected equal for %s", tst.remote)
+	}
+}
@@ -14,8 +14,15 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/compare"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"golang.org/x/exp/slices"
 )
 
+// EnvNameTag is the tag identifying the resource groups, and resources, provisioned for an environment.
+const EnvNameTag = "azd-env-name"
+
+// ProjectNameTag is the tag of the deployments holding the name of the project in azure.yaml
+const ProjectNameTag = "azd-project-name"
+
 type AzureResourceManager struct {
 	azCli azcli.AzCli
 }
@@ -153,7 +160,7 @@ func (rm *AzureResourceManager) GetResourceGroupsForEnvironment(
 	envName string,
 ) ([]azcli.AzCliResource, error) {
 	res, err := rm.azCli.ListResourceGroup(ctx, subscriptionId, &azcli.ListResourceGroupOptions{
-		TagFilter: &azcli.Filter{Key: "azd-env-name", Value: envName},
+		TagFilter: &azcli.Filter{Key: EnvNameTag, Value: envName},
 	})
 
 	if err != nil {
@@ -169,6 +176,106 @@ func (rm *AzureResourceManager) GetResourceGroupsForEnvironment(
 	return res, nil
 }
 
+// FindOrphanedResources returns the resources of the resource groups of an environment which are tagged with the
+// environment, like the resources provisioned by azd, but aren't part of the deployed resources anymore, typically
+// because they were removed from the template. Resources without the tag, like the ones created by hand, are never
+// orphans.
+func (rm *AzureResourceManager) FindOrphanedResources(
+	ctx context.Context,
+	subscriptionId string,
+	envName string,
+	deployedResourceIds []string,
+) ([]azcli.AzCliResource, error) {
+	resourceGroups, err := rm.GetResourceGroupsForEnvironment(ctx, subscriptionId, envName)
+	var notFoundError *azureutil.ResourceNotFoundError
+	if errors.As(err, &notFoundError) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting resource groups for environment: %s: %w", envName, err)
+	}
+
+	deployed := map[string]bool{}
+	for _, id := range deployedResourceIds {
+		deployed[strings.ToLower(id)] = true
+	}
+
+	orphans := []azcli.AzCliResource{}
+	for _, group := range resourceGroups {
+		resources, err := rm.azCli.ListResourceGroupResources(ctx, subscriptionId, group.Name, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing resources of resource group %s: %w", group.Name, err)
+		}
+
+		for _, resource := range resources {
+			if resource.Tags[EnvNameTag] == envName && !deployed[strings.ToLower(resource.Id)] {
+				orphans = append(orphans, resource)
+			}
+		}
+	}
+
+	return orphans, nil
+}
+
+// FindEnvironmentOfResourceGroup returns the name of the environment the resource group was provisioned for, read
+// from the azd-env-name tag of the resource group, or of its resources when the resource group isn't tagged, like
+// when it was created outside of the template. It also returns the resource group.
+func (rm *AzureResourceManager) FindEnvironmentOfResourceGroup(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+) (string, *azcli.AzCliResource, error) {
+	groups, err := rm.azCli.ListResourceGroup(ctx, subscriptionId, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("listing resource groups: %w", err)
+	}
+
+	var resourceGroup *azcli.AzCliResource
+	for i := range groups {
+		if strings.EqualFold(groups[i].Name, resourceGroupName) {
+			resourceGroup = &groups[i]
+			break
+		}
+	}
+
+	if resourceGroup == nil {
+		return "", nil, azureutil.ResourceNotFound(
+			fmt.Errorf("resource group '%s' not found in subscription '%s'", resourceGroupName, subscriptionId),
+		)
+	}
+
+	if envName := resourceGroup.Tags[EnvNameTag]; envName != "" {
+		return envName, resourceGroup, nil
+	}
+
+	resources, err := rm.azCli.ListResourceGroupResources(ctx, subscriptionId, resourceGroup.Name, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("listing resources of resource group %s: %w", resourceGroup.Name, err)
+	}
+
+	envNames := []string{}
+	for _, resource := range resources {
+		if envName := resource.Tags[EnvNameTag]; envName != "" && !slices.Contains(envNames, envName) {
+			envNames = append(envNames, envName)
+		}
+	}
+
+	switch len(envNames) {
+	case 0:
+		return "", nil, fmt.Errorf(
+			"neither resource group '%s' nor its resources are tagged with %s", resourceGroup.Name, EnvNameTag)
+	case 1:
+		return envNames[0], resourceGroup, nil
+	default:
+		return "", nil, fmt.Errorf(
+			"the resources of resource group '%s' are tagged with several environments: %s, tag the resource group "+
+				"with the %s of its environment",
+			resourceGroup.Name,
+			strings.Join(envNames, ", "),
+			EnvNameTag,
+		)
+	}
+}
+
 // GetDefaultResourceGroups gets the default resource groups regardless of azd-env-name setting
 // azd initially released with {envname}-rg for a default resource group name.  We now don't hardcode the default
 // We search for them instead using the rg- prefix or -rg suffix
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -469,3 +470,139 @@ func TestFindResourceGroupForEnvironment(t *testing.T) {
 		})
 	}
 }
+
+func TestFindOrphanedResources(t *testing.T) {
+	const SUBSCRIPTION_ID = "273f1e6b-6c19-4c9e-8b67-5fbe78b14063"
+	groupId := fmt.Sprintf("/subscriptions/%s/resourceGroups/rg-test-env", SUBSCRIPTION_ID)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == "GET" && strings.HasSuffix(strings.ToLower(request.URL.Path), "/resourcegroups")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+			Value: []*armresources.ResourceGroup{
+				{
+					ID:       convert.RefOf(groupId),
+					Name:     convert.RefOf("rg-test-env"),
+					Type:     convert.RefOf("Microsoft.Resources/resourceGroups"),
+					Location: convert.RefOf("eastus2"),
+				},
+			},
+		})
+	})
+
+	resource := func(name string, tags map[string]*string) *armresources.GenericResourceExpanded {
+		return &armresources.GenericResourceExpanded{
+			ID:       convert.RefOf(groupId + "/providers/Microsoft.Web/sites/" + name),
+			Name:     convert.RefOf(name),
+			Type:     convert.RefOf("Microsoft.Web/sites"),
+			Location: convert.RefOf("eastus2"),
+			Tags:     tags,
+		}
+	}
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == "GET" &&
+			strings.HasSuffix(strings.ToLower(request.URL.Path), "/resourcegroups/rg-test-env/resources")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		envTag := map[string]*string{EnvNameTag: convert.RefOf("test-env")}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceListResult{
+			Value: []*armresources.GenericResourceExpanded{
+				resource("web", envTag),
+				resource("legacy-api", envTag),
+				resource("manual", nil),
+				resource("other-env", map[string]*string{EnvNameTag: convert.RefOf("prod")}),
+			},
+		})
+	})
+
+	arm := NewAzureResourceManager(azCli)
+	orphans, err := arm.FindOrphanedResources(*mockContext.Context, SUBSCRIPTION_ID, "test-env", []string{
+		strings.ToUpper(groupId + "/providers/Microsoft.Web/sites/web"),
+	})
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	require.Equal(t, "legacy-api", orphans[0].Name)
+}
+
+func TestFindEnvironmentOfResourceGroup(t *testing.T) {
+	const SUBSCRIPTION_ID = "273f1e6b-6c19-4c9e-8b67-5fbe78b14063"
+
+	group := func(name string, tags map[string]*string) *armresources.ResourceGroup {
+		return &armresources.ResourceGroup{
+			ID:       convert.RefOf(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", SUBSCRIPTION_ID, name)),
+			Name:     convert.RefOf(name),
+			Type:     convert.RefOf("Microsoft.Resources/resourceGroups"),
+			Location: convert.RefOf("eastus2"),
+			Tags:     tags,
+		}
+	}
+
+	resource := func(name string, envName string) *armresources.GenericResourceExpanded {
+		return &armresources.GenericResourceExpanded{
+			ID:       convert.RefOf("/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Web/sites/" + name),
+			Name:     convert.RefOf(name),
+			Type:     convert.RefOf("Microsoft.Web/sites"),
+			Location: convert.RefOf("eastus2"),
+			Tags:     map[string]*string{EnvNameTag: convert.RefOf(envName)},
+		}
+	}
+
+	setup := func(resources ...*armresources.GenericResourceExpanded) (*mocks.MockContext, *AzureResourceManager) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == "GET" && strings.HasSuffix(strings.ToLower(request.URL.Path), "/resourcegroups")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceGroupListResult{
+				Value: []*armresources.ResourceGroup{
+					group("rg-tagged", map[string]*string{EnvNameTag: convert.RefOf("dev")}),
+					group("rg-untagged", nil),
+				},
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == "GET" &&
+				strings.HasSuffix(strings.ToLower(request.URL.Path), "/resourcegroups/rg-untagged/resources")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.ResourceListResult{
+				Value: resources,
+			})
+		})
+
+		return mockContext, NewAzureResourceManager(mockazcli.NewAzCliFromMockContext(mockContext))
+	}
+
+	t.Run("TaggedResourceGroup", func(t *testing.T) {
+		mockContext, arm := setup()
+		envName, resourceGroup, err := arm.FindEnvironmentOfResourceGroup(
+			*mockContext.Context, SUBSCRIPTION_ID, "RG-TAGGED")
+		require.NoError(t, err)
+		require.Equal(t, "dev", envName)
+		require.Equal(t, "rg-tagged", resourceGroup.Name)
+		require.Equal(t, "eastus2", resourceGroup.Location)
+	})
+
+	t.Run("TaggedResources", func(t *testing.T) {
+		mockContext, arm := setup(resource("web", "test"), resource("api", "test"))
+		envName, _, err := arm.FindEnvironmentOfResourceGroup(*mockContext.Context, SUBSCRIPTION_ID, "rg-untagged")
+		require.NoError(t, err)
+		require.Equal(t, "test", envName)
+	})
+
+	t.Run("SeveralEnvironments", func(t *testing.T) {
+		mockContext, arm := setup(resource("web", "test"), resource("api", "prod"))
+		_, _, err := arm.FindEnvironmentOfResourceGroup(*mockContext.Context, SUBSCRIPTION_ID, "rg-untagged")
+		require.ErrorContains(t, err, "tagged with several environments: test, prod")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockContext, arm := setup()
+		_, _, err := arm.FindEnvironmentOfResourceGroup(*mockContext.Context, SUBSCRIPTION_ID, "rg-missing")
+		var notFoundErr *azureutil.ResourceNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+	})
+}
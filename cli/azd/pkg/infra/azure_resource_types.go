@@ -23,6 +23,7 @@ const (
 	AzureResourceTypeLogAnalyticsWorkspace   AzureResourceType = "Microsoft.OperationalInsights/workspaces"
 	AzureResourceTypePortalDashboard         AzureResourceType = "Microsoft.Portal/dashboards"
 	AzureResourceTypePostgreSqlServer        AzureResourceType = "Microsoft.DBforPostgreSQL/flexibleServers"
+	AzureResourceTypeMySqlServer             AzureResourceType = "Microsoft.DBforMySQL/flexibleServers"
 	AzureResourceTypeResourceGroup           AzureResourceType = "Microsoft.Resources/resourceGroups"
 	AzureResourceTypeStorageAccount          AzureResourceType = "Microsoft.Storage/storageAccounts"
 	AzureResourceTypeStaticWebSite           AzureResourceType = "Microsoft.Web/staticSites"
@@ -35,6 +36,9 @@ const (
 	AzureResourceTypeAgentPool               AzureResourceType = "Microsoft.ContainerService/managedClusters/agentPools"
 	AzureResourceTypeCognitiveServiceAccount AzureResourceType = "Microsoft.CognitiveServices/accounts"
 	AzureResourceTypeSearchService           AzureResourceType = "Microsoft.Search/searchServices"
+	AzureResourceTypeIotHub                  AzureResourceType = "Microsoft.Devices/IotHubs"
+	AzureResourceTypeDatabricksWorkspace     AzureResourceType = "Microsoft.Databricks/workspaces"
+	AzureResourceTypeSynapseWorkspace        AzureResourceType = "Microsoft.Synapse/workspaces"
 )
 
 const resourceLevelSeparator = "/"
@@ -78,6 +82,8 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Azure SQL Server"
 	case AzureResourceTypePostgreSqlServer:
 		return "Azure Database for PostgreSQL flexible server"
+	case AzureResourceTypeMySqlServer:
+		return "Azure Database for MySQL flexible server"
 	case AzureResourceTypeCDNProfile:
 		return "Azure Front Door / CDN profile"
 	case AzureResourceTypeLoadTest:
@@ -96,6 +102,12 @@ func GetResourceTypeDisplayName(resourceType AzureResourceType) string {
 		return "Search service"
 	case AzureResourceTypeSpringApp:
 		return "Azure Spring Apps"
+	case AzureResourceTypeIotHub:
+		return "IoT Hub"
+	case AzureResourceTypeDatabricksWorkspace:
+		return "Azure Databricks Service"
+	case AzureResourceTypeSynapseWorkspace:
+		return "Synapse workspace"
 	}
 
 	return ""
@@ -122,3 +134,29 @@ func IsTopLevelResourceType(resourceType AzureResourceType) bool {
 	// Should not contain second separator
 	return !strings.Contains(resType[firstIndex+1:], resourceLevelSeparator)
 }
+
+// statefulResourceTypes are the resource types storing data which is lost when their resources are deleted
+var statefulResourceTypes = []AzureResourceType{
+	AzureResourceTypeSqlServer,
+	AzureResourceTypePostgreSqlServer,
+	AzureResourceTypeMySqlServer,
+	AzureResourceTypeCosmosDb,
+	AzureResourceTypeCacheForRedis,
+	AzureResourceTypeStorageAccount,
+	AzureResourceTypeKeyVault,
+}
+
+// IsStatefulResourceType returns true if resources of the resource type store data which is lost when they are deleted,
+// like databases, storage accounts and key vaults. The child resources of these types, like the databases of a SQL
+// server, store data too. Resource types are case insensitive, ARM returns them in different casings.
+func IsStatefulResourceType(resourceType AzureResourceType) bool {
+	resType := strings.ToLower(string(resourceType))
+	for _, stateful := range statefulResourceTypes {
+		statefulType := strings.ToLower(string(stateful))
+		if resType == statefulType || strings.HasPrefix(resType, statefulType+resourceLevelSeparator) {
+			return true
+		}
+	}
+
+	return false
+}
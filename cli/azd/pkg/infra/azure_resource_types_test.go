@@ -34,3 +34,15 @@ func TestIsTopLevelResourceType(t *testing.T) {
 		})
 	}
 }
+
+func TestIsStatefulResourceType(t *testing.T) {
+	assert.True(t, IsStatefulResourceType(AzureResourceTypeSqlServer))
+	assert.True(t, IsStatefulResourceType(AzureResourceTypeStorageAccount))
+	assert.True(t, IsStatefulResourceType(AzureResourceTypeKeyVault))
+	assert.True(t, IsStatefulResourceType(AzureResourceTypeCacheForRedis))
+	assert.True(t, IsStatefulResourceType("Microsoft.Sql/servers/databases"))
+	assert.True(t, IsStatefulResourceType("microsoft.storage/storageaccounts"))
+	assert.False(t, IsStatefulResourceType("Microsoft.Sql/serversX"))
+	assert.False(t, IsStatefulResourceType(AzureResourceTypeContainerApp))
+	assert.False(t, IsStatefulResourceType(AzureResourceTypeLogAnalyticsWorkspace))
+}
@@ -0,0 +1,198 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// The maximum length of the name of a deployment
+	deploymentNameMaxLength = 64
+
+	// The number of attempts to generate a deployment name which isn't used by another deployment
+	deploymentNameAttempts = 3
+)
+
+// GenerateDeploymentName returns a name for a deployment of the environment made of the name of the environment, the time
+// of the deployment and a short random hash, like dev-20231016093512-3fa2b1. The name of the environment is truncated to
+// keep the name within the 64 characters allowed.
+func GenerateDeploymentName(envName string, now time.Time) (string, error) {
+	hash := make([]byte, 3)
+	if _, err := rand.Read(hash); err != nil {
+		return "", fmt.Errorf("generating deployment name: %w", err)
+	}
+
+	suffix := fmt.Sprintf("-%s-%s", now.UTC().Format("20060102150405"), hex.EncodeToString(hash))
+	if maxLength := deploymentNameMaxLength - len(suffix); len(envName) > maxLength {
+		envName = envName[:maxLength]
+	}
+
+	return envName + suffix, nil
+}
+
+// DeploymentPortalUrl returns the url of the page of the deployment of the scope in the Azure Portal.
+func DeploymentPortalUrl(scope Scope) string {
+	return "https://portal.azure.com/#blade/HubsExtension/DeploymentDetailsBlade/overview/id/" +
+		url.PathEscape(scope.DeploymentUrl())
+}
+
+// NewDeploymentName returns a name for a new subscription deployment of the environment, which isn't used by an existing
+// deployment of the subscription.
+func (rm *AzureResourceManager) NewDeploymentName(
+	ctx context.Context,
+	subscriptionId string,
+	envName string,
+) (string, error) {
+	for i := 0; i < deploymentNameAttempts; i++ {
+		name, err := GenerateDeploymentName(envName, time.Now())
+		if err != nil {
+			return "", err
+		}
+
+		_, err = rm.azCli.GetSubscriptionDeployment(ctx, subscriptionId, name)
+		if errors.Is(err, azcli.ErrDeploymentNotFound) {
+			return name, nil
+		} else if err != nil {
+			return "", fmt.Errorf("checking deployment %s: %w", name, err)
+		}
+	}
+
+	return "", fmt.Errorf("could not find an unused name for a deployment of environment %s", envName)
+}
+
+// DeploymentFilter selects the subscription deployments of an environment.
+type DeploymentFilter struct {
+	EnvName string
+	// The name of the project, matched against the ProjectNameTag of the deployments tagged with the environment, as
+	// projects in the same subscription can have environments with the same name.
+	ProjectName string
+	// The resource group of the environment, when known. Deployments targeting other resource groups are excluded.
+	ResourceGroup string
+}
+
+// GetEnvironmentDeployments returns the subscription deployments of the environment, from the oldest to the latest.
+// The deployments are the ones tagged with the name of the environment and of the project, and the deployment named
+// after the environment, which was updated by each provisioning before the deployments were given unique names.
+func (rm *AzureResourceManager) GetEnvironmentDeployments(
+	ctx context.Context,
+	subscriptionId string,
+	filter DeploymentFilter,
+) ([]*armresources.DeploymentExtended, error) {
+	deployments, err := rm.azCli.ListSubscriptionDeployments(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return environmentDeployments(deployments, filter), nil
+}
+
+// FindLatestDeployment returns the latest subscription deployment of the environment, or azcli.ErrDeploymentNotFound
+// when the environment has no deployment.
+func (rm *AzureResourceManager) FindLatestDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	filter DeploymentFilter,
+) (*armresources.DeploymentExtended, error) {
+	deployments, err := rm.GetEnvironmentDeployments(ctx, subscriptionId, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(deployments) == 0 {
+		return nil, azcli.ErrDeploymentNotFound
+	}
+
+	return deployments[len(deployments)-1], nil
+}
+
+// environmentDeployments returns the deployments matching the filter, sorted from the oldest to the latest.
+func environmentDeployments(
+	deployments []*armresources.DeploymentExtended,
+	filter DeploymentFilter,
+) []*armresources.DeploymentExtended {
+	var result []*armresources.DeploymentExtended
+	for _, deployment := range deployments {
+		if deployment.Name == nil {
+			continue
+		}
+
+		envName := tagValue(deployment.Tags, EnvNameTag)
+		switch {
+		case envName == filter.EnvName:
+			if filter.ProjectName != "" && tagValue(deployment.Tags, ProjectNameTag) != filter.ProjectName {
+				continue
+			}
+		case envName == "" && *deployment.Name == filter.EnvName:
+			// The deployments named after the environment predate the tags
+		default:
+			continue
+		}
+
+		if filter.ResourceGroup != "" {
+			resourceGroups := deploymentResourceGroups(deployment)
+			if len(resourceGroups) > 0 && slices.IndexFunc(resourceGroups, func(resourceGroup string) bool {
+				return strings.EqualFold(resourceGroup, filter.ResourceGroup)
+			}) < 0 {
+				continue
+			}
+		}
+
+		result = append(result, deployment)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return deploymentTimestamp(result[i]).Before(deploymentTimestamp(result[j]))
+	})
+
+	return result
+}
+
+// deploymentResourceGroups returns the resource groups the deployment depends on, see GetResourceGroupsForDeployment.
+func deploymentResourceGroups(deployment *armresources.DeploymentExtended) []string {
+	if deployment.Properties == nil {
+		return nil
+	}
+
+	var resourceGroups []string
+	for _, dependency := range deployment.Properties.Dependencies {
+		for _, dependent := range dependency.DependsOn {
+			if dependent.ResourceType != nil && dependent.ResourceName != nil &&
+				*dependent.ResourceType == string(AzureResourceTypeResourceGroup) {
+				resourceGroups = append(resourceGroups, *dependent.ResourceName)
+			}
+		}
+	}
+
+	return resourceGroups
+}
+
+func tagValue(tags map[string]*string, name string) string {
+	if value, has := tags[name]; has && value != nil {
+		return *value
+	}
+
+	return ""
+}
+
+// deploymentTimestamp returns the time the deployment last ran.
+func deploymentTimestamp(deployment *armresources.DeploymentExtended) time.Time {
+	if deployment.Properties == nil || deployment.Properties.Timestamp == nil {
+		return time.Time{}
+	}
+
+	return *deployment.Properties.Timestamp
+}
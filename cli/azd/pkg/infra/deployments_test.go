@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package infra
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateDeploymentName(t *testing.T) {
+	now := time.Date(2023, 10, 16, 9, 35, 12, 0, time.UTC)
+
+	name, err := GenerateDeploymentName("dev", now)
+	require.NoError(t, err)
+	require.Regexp(t, regexp.MustCompile(`^dev-20231016093512-[0-9a-f]{6}$`), name)
+
+	other, err := GenerateDeploymentName("dev", now)
+	require.NoError(t, err)
+	require.NotEqual(t, name, other)
+
+	name, err = GenerateDeploymentName(strings.Repeat("a", 64), now)
+	require.NoError(t, err)
+	require.Len(t, name, deploymentNameMaxLength)
+	require.True(t, strings.HasPrefix(name, strings.Repeat("a", 42)+"-20231016093512-"))
+}
+
+func TestEnvironmentDeployments(t *testing.T) {
+	deployments := []*armresources.DeploymentExtended{
+		testDeployment("dev-20231016093512-3fa2b1", "dev", "shop", "rg-dev", 3),
+		testDeployment("prod-20231010080000-a1b2c3", "prod", "shop", "rg-prod", 1),
+		testDeployment("dev", "", "", "", 0),
+		testDeployment("dev-20231001120000-0c1d2e", "dev", "shop", "RG-DEV", 2),
+		testDeployment("devbox", "", "", "", 4),
+		// The environments of other projects, or of other resource groups, can have the same name
+		testDeployment("dev-20231005120000-4d5e6f", "dev", "blog", "rg-dev", 5),
+		testDeployment("dev-20231006120000-7a8b9c", "dev", "shop", "rg-dev-old", 6),
+	}
+
+	names := func(filter DeploymentFilter) []string {
+		var names []string
+		for _, deployment := range environmentDeployments(deployments, filter) {
+			names = append(names, *deployment.Name)
+		}
+		return names
+	}
+
+	require.Equal(t,
+		[]string{"dev", "dev-20231001120000-0c1d2e", "dev-20231016093512-3fa2b1"},
+		names(DeploymentFilter{EnvName: "dev", ProjectName: "shop", ResourceGroup: "rg-dev"}))
+	require.Equal(t,
+		[]string{"dev", "dev-20231001120000-0c1d2e", "dev-20231016093512-3fa2b1", "dev-20231006120000-7a8b9c"},
+		names(DeploymentFilter{EnvName: "dev", ProjectName: "shop"}))
+	require.Equal(t,
+		[]string{"dev", "dev-20231005120000-4d5e6f"},
+		names(DeploymentFilter{EnvName: "dev", ProjectName: "blog", ResourceGroup: "rg-dev"}))
+}
+
+func testDeployment(
+	name string, envName string, projectName string, resourceGroup string, day int,
+) *armresources.DeploymentExtended {
+	deployment := &armresources.DeploymentExtended{
+		ID:   convert.RefOf("/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deployments/" + name),
+		Name: convert.RefOf(name),
+		Properties: &armresources.DeploymentPropertiesExtended{
+			Timestamp: convert.RefOf(time.Date(2023, 10, day+1, 0, 0, 0, 0, time.UTC)),
+		},
+	}
+
+	if envName != "" {
+		deployment.Tags = map[string]*string{EnvNameTag: convert.RefOf(envName)}
+	}
+
+	if projectName != "" {
+		deployment.Tags[ProjectNameTag] = convert.RefOf(projectName)
+	}
+
+	if resourceGroup != "" {
+		deployment.Properties.Dependencies = []*armresources.Dependency{
+			{
+				DependsOn: []*armresources.BasicDependency{
+					{
+						ResourceName: convert.RefOf(resourceGroup),
+						ResourceType: convert.RefOf(string(AzureResourceTypeResourceGroup)),
+					},
+				},
+			},
+		}
+	}
+
+	return deployment
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+)
+
+// IsArmTemplate reports whether the module is an ARM JSON template, like infra/main.json, rather than a Bicep module.
+func IsArmTemplate(modulePath string) bool {
+	return strings.EqualFold(filepath.Ext(modulePath), ".json")
+}
+
+// buildTemplate returns the ARM template of the module: the module itself when it is an ARM JSON template, or else the
+// template compiled from the Bicep module.
+func buildTemplate(ctx context.Context, bicepCli bicep.BicepCli, modulePath string) (azure.RawArmTemplate, error) {
+	if !IsArmTemplate(modulePath) {
+		compiled, err := bicepCli.Build(ctx, modulePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile bicep template: %w", err)
+		}
+
+		return azure.RawArmTemplate(compiled), nil
+	}
+
+	content, err := os.ReadFile(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading arm template: %w", err)
+	}
+
+	var header struct {
+		Schema string `json:"$schema"`
+	}
+	if err := json.Unmarshal(content, &header); err != nil {
+		return nil, fmt.Errorf("parsing arm template %s: %w", filepath.Base(modulePath), err)
+	}
+
+	// Like https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#
+	if !strings.Contains(strings.ToLower(header.Schema), "deploymenttemplate.json") {
+		return nil, fmt.Errorf(
+			"%s isn't an ARM template, its $schema must be a deployment template schema", filepath.Base(modulePath))
+	}
+
+	return azure.RawArmTemplate(content), nil
+}
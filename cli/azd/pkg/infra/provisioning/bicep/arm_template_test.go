@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+const testArmTemplate = `{
+  "$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "environmentName": { "type": "String" },
+    "adminPassword": { "type": "SecureString" }
+  },
+  "resources": [],
+  "outputs": {
+    "AZURE_LOCATION": { "type": "String", "value": "[deployment().location]" }
+  }
+}`
+
+func TestModuleFilePathsArmTemplate(t *testing.T) {
+	projectDir := t.TempDir()
+	infraDir := filepath.Join(projectDir, "infra")
+	require.NoError(t, os.MkdirAll(infraDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "main.json"), []byte(testArmTemplate), 0600))
+
+	modulePath, parametersFilePath := ModuleFilePaths(projectDir, Options{})
+	require.Equal(t, filepath.Join(infraDir, "main.json"), modulePath)
+	require.Equal(t, filepath.Join(infraDir, "main.parameters.json"), parametersFilePath)
+	require.True(t, IsArmTemplate(modulePath))
+
+	// The Bicep module is preferred, main.json is typically its compiled template then
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "main.bicep"), []byte(""), 0600))
+	modulePath, _ = ModuleFilePaths(projectDir, Options{})
+	require.Equal(t, filepath.Join(infraDir, "main.bicep"), modulePath)
+}
+
+func TestBuildTemplateArmTemplate(t *testing.T) {
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "main.json")
+	require.NoError(t, os.WriteFile(templatePath, []byte(testArmTemplate), 0600))
+
+	// The ARM template is deployed as is, without Bicep
+	template, err := buildTemplate(context.Background(), nil, templatePath)
+	require.NoError(t, err)
+	require.JSONEq(t, testArmTemplate, string(template))
+
+	provider := &BicepProvider{}
+	_, armTemplate, err := provider.compileBicep(context.Background(), templatePath)
+	require.NoError(t, err)
+	adminPassword := armTemplate.Parameters["adminPassword"]
+	require.True(t, adminPassword.Secure())
+	require.Equal(t, ParameterTypeString, provider.mapBicepTypeToInterfaceType(adminPassword.Type))
+
+	notTemplatePath := filepath.Join(dir, "settings.json")
+	require.NoError(t, os.WriteFile(notTemplatePath, []byte(`{"name": "settings"}`), 0600))
+	_, err = buildTemplate(context.Background(), nil, notTemplatePath)
+	require.ErrorContains(t, err, "isn't an ARM template")
+}
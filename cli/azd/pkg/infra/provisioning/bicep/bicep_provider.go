@@ -36,6 +36,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/profiling"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
@@ -140,6 +141,11 @@ func (p *BicepProvider) Plan(
 			}
 
 			modulePath := p.modulePath()
+			if err := p.restoreModules(ctx, modulePath); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
 			asyncContext.SetProgress(&DeploymentPlanningProgress{Message: "Compiling Bicep template", Timestamp: time.Now()})
 			rawTemplate, template, err := p.compileBicep(ctx, modulePath)
 			if err != nil {
@@ -147,12 +153,40 @@ func (p *BicepProvider) Plan(
 				return
 			}
 
+			parameters, err = p.applyParameterOverrides(template, parameters)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
 			configuredParameters, err := p.ensureParameters(ctx, asyncContext, template, parameters)
 			if err != nil {
 				asyncContext.SetError(err)
 				return
 			}
 
+			configuredParameters, err = p.injectTags(template, configuredParameters)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			configuredParameters, err = p.ensureQuota(ctx, rawTemplate, configuredParameters)
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			if err := p.ensureResourceProviders(ctx, rawTemplate); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			if err := p.ensurePermissions(ctx, rawTemplate); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
 			deployment, err := p.convertToDeployment(template)
 			if err != nil {
 				asyncContext.SetError(err)
@@ -216,16 +250,39 @@ func (p *BicepProvider) Deploy(
 				}
 			}()
 
-			// Start the deployment
-			p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
 			bicepDeploymentData := pd.Details.(BicepDeploymentDetails)
-
-			deployResult, err := p.deployModule(ctx, scope, bicepDeploymentData.Template, bicepDeploymentData.Parameters)
+			hash, err := deploymentHash(scope, bicepDeploymentData.Template, bicepDeploymentData.Parameters)
 			if err != nil {
 				asyncContext.SetError(err)
 				return
 			}
 
+			// The outputs of the last deployment are still current when the template and its parameters are unchanged
+			var deployResult *armresources.DeploymentExtended
+			if !p.options.Force {
+				deployResult = p.unchangedDeployment(ctx, scope, hash)
+			}
+
+			if deployResult != nil {
+				message := "Creating/Updating resources, the template and its parameters are unchanged since the last " +
+					"deployment (use --force to deploy them again)"
+				p.console.ShowSpinner(ctx, message, input.Step)
+				p.console.StopSpinner(ctx, message, input.StepSkipped)
+			} else {
+				// Start the deployment
+				p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
+
+				deployResult, err = p.deployModule(ctx, scope, bicepDeploymentData.Template, bicepDeploymentData.Parameters)
+				if err != nil {
+					asyncContext.SetError(err)
+					return
+				}
+
+				if err := p.saveDeploymentHash(scope, hash); err != nil {
+					log.Printf("failed saving the hash of the deployment: %v", err)
+				}
+			}
+
 			deployment := pd.Deployment
 			deployment.Outputs = p.createOutputParameters(
 				bicepDeploymentData.TemplateOutputs,
@@ -296,6 +353,11 @@ func (p *BicepProvider) Destroy(
 				return
 			}
 
+			if err := p.checkData(ctx, options, groupedResources); err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
 			if err := p.destroyResourceGroups(ctx, options, groupedResources, len(allResources)); err != nil {
 				asyncContext.SetError(fmt.Errorf("deleting resource groups: %w", err))
 				return
@@ -334,8 +396,14 @@ func (p *BicepProvider) Destroy(
 				return
 			}
 
-			if err := p.deleteDeployment(ctx); err != nil {
-				asyncContext.SetError(fmt.Errorf("deleting subscription deployment: %w", err))
+			if err := p.deleteDeployments(ctx); err != nil {
+				asyncContext.SetError(fmt.Errorf("deleting subscription deployments: %w", err))
+				return
+			}
+
+			// The deleted resources are deployed again by the next provisioning
+			if err := p.clearDeploymentHash(); err != nil {
+				asyncContext.SetError(fmt.Errorf("clearing the hash of the deployment: %w", err))
 				return
 			}
 
@@ -350,7 +418,17 @@ func (p *BicepProvider) Destroy(
 
 func (p *BicepProvider) getResourceGroups(ctx context.Context) ([]string, error) {
 	resourceManager := infra.NewAzureResourceManager(p.azCli)
-	resourceGroups, err := resourceManager.GetResourceGroupsForDeployment(ctx, p.env.GetSubscriptionId(), p.env.GetEnvName())
+
+	// The resource groups are the ones of the latest deployment of the environment
+	deploymentName := p.env.GetEnvName()
+	latest, err := resourceManager.FindLatestDeployment(ctx, p.env.GetSubscriptionId(), p.deploymentFilter())
+	if err == nil {
+		deploymentName = *latest.Name
+	} else if !errors.Is(err, azcli.ErrDeploymentNotFound) {
+		return []string{}, fmt.Errorf("finding the latest deployment: %w", err)
+	}
+
+	resourceGroups, err := resourceManager.GetResourceGroupsForDeployment(ctx, p.env.GetSubscriptionId(), deploymentName)
 	if err != nil {
 		return []string{}, err
 	}
@@ -392,6 +470,88 @@ func generateResourceGroupsToDelete(groupedResources map[string][]azcli.AzCliRes
 	return append(lines, "")
 }
 
+// statefulResources returns the resources storing data lost when they're deleted, like databases, storage accounts and
+// key vaults, sorted by resource group and name.
+func statefulResources(groupedResources map[string][]azcli.AzCliResource) []azcli.AzCliResource {
+	var resources []azcli.AzCliResource
+	for _, groupResources := range groupedResources {
+		for _, resource := range groupResources {
+			if infra.IsStatefulResourceType(infra.AzureResourceType(resource.Type)) {
+				resources = append(resources, resource)
+			}
+		}
+	}
+
+	slices.SortFunc(resources, func(a, b azcli.AzCliResource) bool {
+		return a.Id < b.Id
+	})
+
+	return resources
+}
+
+// generateStatefulResourcesInventory returns the lines of the inventory of the resources storing data.
+func generateStatefulResourcesInventory(resources []azcli.AzCliResource) []string {
+	lines := []string{"Resource(s) storing data which is lost when they are deleted:", ""}
+
+	for _, resource := range resources {
+		displayName := infra.GetResourceTypeDisplayName(infra.AzureResourceType(resource.Type))
+		if displayName == "" {
+			displayName = resource.Type
+		}
+
+		lines = append(lines, fmt.Sprintf("  • %s: %s", displayName, output.WithHighLightFormat(resource.Name)))
+	}
+
+	return append(lines, "")
+}
+
+// checkData displays the inventory of the resources storing data before they're deleted, and offers to back up their
+// data with the backup configured by the options. The backup runs without prompting when the deletion is forced.
+func (p *BicepProvider) checkData(
+	ctx context.Context,
+	options DestroyOptions,
+	groupedResources map[string][]azcli.AzCliResource,
+) error {
+	if options.SkipDataCheck() {
+		return nil
+	}
+
+	resources := statefulResources(groupedResources)
+	if len(resources) == 0 {
+		return nil
+	}
+
+	p.console.MessageUxItem(ctx, &ux.MultilineMessage{Lines: generateStatefulResourcesInventory(resources)})
+
+	backup := options.Backup()
+	if backup == nil {
+		p.console.Message(ctx, output.WithGrayFormat(
+			"Define a 'backup' hook in azure.yaml to export the data, like database bacpacs or storage snapshots, "+
+				"before the resources are deleted.\n"))
+		return nil
+	}
+
+	if !options.Force() {
+		runBackup, err := p.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      "Run the backup hook to export the data before deleting the resources?",
+			DefaultValue: true,
+		})
+		if err != nil {
+			return fmt.Errorf("prompting for backup: %w", err)
+		}
+
+		if !runBackup {
+			return nil
+		}
+	}
+
+	if err := backup(ctx); err != nil {
+		return fmt.Errorf("backing up data: %w", err)
+	}
+
+	return nil
+}
+
 // Deletes the azure resources within the deployment
 func (p *BicepProvider) destroyResourceGroups(
 	ctx context.Context,
@@ -427,10 +587,9 @@ func (p *BicepProvider) destroyResourceGroups(
 		message := fmt.Sprintf("Deleting resource group: %s",
 			output.WithHighLightFormat(resourceGroup),
 		)
-		p.console.ShowSpinner(ctx, message, input.Step)
-		err := p.azCli.DeleteResourceGroup(ctx, p.env.GetSubscriptionId(), resourceGroup)
-
-		p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+		err := input.RunWithSpinner(ctx, p.console, message, func(ctx context.Context) error {
+			return p.azCli.DeleteResourceGroup(ctx, p.env.GetSubscriptionId(), resourceGroup)
+		})
 		if err != nil {
 			return err
 		}
@@ -700,27 +859,50 @@ func (p *BicepProvider) purgeAPIManagement(
 	return nil
 }
 
-// Deletes the azure deployment
-func (p *BicepProvider) deleteDeployment(ctx context.Context) error {
-	deploymentName := p.env.GetEnvName()
-	message := fmt.Sprintf("Deleting deployment: %s", output.WithHighLightFormat(deploymentName))
-	p.console.ShowSpinner(ctx, message, input.Step)
-	err := p.azCli.DeleteSubscriptionDeployment(ctx, p.env.GetSubscriptionId(), deploymentName)
-	p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
-	return err
+// deploymentFilter selects the deployments of the environment, and not the ones of environments with the same name in
+// other projects or resource groups.
+func (p *BicepProvider) deploymentFilter() infra.DeploymentFilter {
+	return infra.DeploymentFilter{
+		EnvName:       p.env.GetEnvName(),
+		ProjectName:   p.options.ProjectName,
+		ResourceGroup: p.env.Getenv(environment.ResourceGroupEnvVarName),
+	}
+}
+
+// Deletes the azure deployments of the environment
+func (p *BicepProvider) deleteDeployments(ctx context.Context) error {
+	resourceManager := infra.NewAzureResourceManager(p.azCli)
+	deployments, err := resourceManager.GetEnvironmentDeployments(ctx, p.env.GetSubscriptionId(), p.deploymentFilter())
+	if err != nil {
+		return fmt.Errorf("listing deployments: %w", err)
+	}
+
+	for _, deployment := range deployments {
+		deploymentName := *deployment.Name
+		message := fmt.Sprintf("Deleting deployment: %s", output.WithHighLightFormat(deploymentName))
+		err := input.RunWithSpinner(ctx, p.console, message, func(ctx context.Context) error {
+			return p.azCli.DeleteSubscriptionDeployment(ctx, p.env.GetSubscriptionId(), deploymentName)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (p *BicepProvider) mapBicepTypeToInterfaceType(s string) ParameterType {
-	switch s {
-	case "String", "string", "secureString", "securestring":
+	// ARM templates written by hand use any casing, like SecureString
+	switch strings.ToLower(s) {
+	case "string", "securestring":
 		return ParameterTypeString
-	case "Bool", "bool":
+	case "bool":
 		return ParameterTypeBoolean
-	case "Int", "int":
+	case "int":
 		return ParameterTypeNumber
-	case "Object", "object", "secureObject", "secureobject":
+	case "object", "secureobject":
 		return ParameterTypeObject
-	case "Array", "array":
+	case "array":
 		return ParameterTypeArray
 	default:
 		panic(fmt.Sprintf("unexpected bicep type: '%s'", s))
@@ -804,20 +986,20 @@ func (p *BicepProvider) loadParameters(
 	return armParameters.Parameters, nil
 }
 
+// compileBicep returns the ARM template of the module, compiled from the Bicep module or read from the ARM JSON template.
 func (p *BicepProvider) compileBicep(
 	ctx context.Context, modulePath string,
 ) (azure.RawArmTemplate, azure.ArmTemplate, error) {
-
-	compiled, err := p.bicepCli.Build(ctx, modulePath)
+	endStage := profiling.TrackStage("bicep build")
+	rawTemplate, err := buildTemplate(ctx, p.bicepCli, modulePath)
+	endStage()
 	if err != nil {
-		return nil, azure.ArmTemplate{}, fmt.Errorf("failed to compile bicep template: %w", err)
+		return nil, azure.ArmTemplate{}, err
 	}
 
-	rawTemplate := azure.RawArmTemplate(compiled)
-
 	var template azure.ArmTemplate
 	if err := json.Unmarshal(rawTemplate, &template); err != nil {
-		log.Printf("failed unmarshalling compiled arm template to JSON (err: %v), template contents:\n%s", err, compiled)
+		log.Printf("failed unmarshalling compiled arm template to JSON (err: %v), template contents:\n%s", err, rawTemplate)
 		return nil, azure.ArmTemplate{}, fmt.Errorf("failed unmarshalling arm template from json: %w", err)
 	}
 
@@ -857,29 +1039,45 @@ func (p *BicepProvider) deployModule(
 	armTemplate azure.RawArmTemplate,
 	armParameters azure.ArmParameters,
 ) (*armresources.DeploymentExtended, error) {
+	defer profiling.TrackStage("arm deploy")()
+
 	return scope.Deploy(ctx, armTemplate, armParameters)
 }
 
 // Gets the path to the project parameters file path
 func (p *BicepProvider) parametersTemplateFilePath() string {
-	infraPath := p.options.Path
-	if strings.TrimSpace(infraPath) == "" {
-		infraPath = "infra"
-	}
-
-	parametersFilename := fmt.Sprintf("%s.parameters.json", p.options.Module)
-	return filepath.Join(p.projectPath, infraPath, parametersFilename)
+	_, parametersFilePath := ModuleFilePaths(p.projectPath, p.options)
+	return parametersFilePath
 }
 
 // Gets the folder path to the specified module
 func (p *BicepProvider) modulePath() string {
-	infraPath := p.options.Path
+	modulePath, _ := ModuleFilePaths(p.projectPath, p.options)
+	return modulePath
+}
+
+// ModuleFilePaths returns the paths of the Bicep module and of its parameters file for the infrastructure options. When
+// the module has no Bicep file but an ARM JSON template, like infra/main.json, the module is the ARM template.
+func ModuleFilePaths(projectPath string, options Options) (modulePath string, parametersFilePath string) {
+	infraPath := options.Path
 	if strings.TrimSpace(infraPath) == "" {
 		infraPath = "infra"
 	}
 
-	moduleFilename := fmt.Sprintf("%s.bicep", p.options.Module)
-	return filepath.Join(p.projectPath, infraPath, moduleFilename)
+	module := options.Module
+	if module == "" {
+		module = "main"
+	}
+
+	modulePath = filepath.Join(projectPath, infraPath, fmt.Sprintf("%s.bicep", module))
+	if _, err := os.Stat(modulePath); errors.Is(err, os.ErrNotExist) {
+		armTemplatePath := filepath.Join(projectPath, infraPath, fmt.Sprintf("%s.json", module))
+		if _, err := os.Stat(armTemplatePath); err == nil {
+			modulePath = armTemplatePath
+		}
+	}
+
+	return modulePath, filepath.Join(projectPath, infraPath, fmt.Sprintf("%s.parameters.json", module))
 }
 
 // Ensures the provisioning parameters are valid and prompts the user for input as needed
@@ -972,6 +1170,98 @@ func (p *BicepProvider) ensureParameters(
 	return configuredParameters, nil
 }
 
+// injectTags merges the additional tags declared for the project into the `tags` parameter of the template. Tags which
+// are already set by the template or the parameters file are overridden, except the tags reserved by azd.
+func (p *BicepProvider) injectTags(
+	template azure.ArmTemplate,
+	parameters azure.ArmParameters,
+) (azure.ArmParameters, error) {
+	tags, err := ResolveTags(p.options, p.env)
+	if err != nil {
+		return nil, fmt.Errorf("resolving tags: %w", err)
+	}
+
+	if len(tags) == 0 {
+		return parameters, nil
+	}
+
+	// The tags are verified on the service resources, fail now rather than when deploying
+	param, has := template.Parameters[TagsParameterName]
+	if !has || p.mapBicepTypeToInterfaceType(param.Type) != ParameterTypeObject {
+		return nil, fmt.Errorf(
+			"additional tags are declared in %s, but the template has no '%s' object parameter to apply them to "+
+				"the resources. Add the parameter to the template, or remove the tags",
+			TagsConfigPath,
+			TagsParameterName,
+		)
+	}
+
+	merged := map[string]any{}
+	if value, ok := param.DefaultValue.(map[string]any); ok {
+		maps.Copy(merged, value)
+	}
+	if value, ok := parameters[TagsParameterName].Value.(map[string]any); ok {
+		maps.Copy(merged, value)
+	}
+
+	for name, value := range tags {
+		if strings.HasPrefix(name, "azd-") {
+			log.Printf("ignoring additional tag '%s', tags prefixed with azd- are reserved", name)
+			continue
+		}
+
+		merged[name] = value
+	}
+
+	if parameters == nil {
+		parameters = azure.ArmParameters{}
+	}
+
+	parameters[TagsParameterName] = azure.ArmParameterValue{Value: merged}
+	return parameters, nil
+}
+
+// applyParameterOverrides sets the parameters overriding the values of the parameters file and of the environment for
+// this run. Overridden parameters are neither prompted for nor saved in the environment.
+func (p *BicepProvider) applyParameterOverrides(
+	template azure.ArmTemplate,
+	parameters azure.ArmParameters,
+) (azure.ArmParameters, error) {
+	if len(p.options.Parameters) == 0 {
+		return parameters, nil
+	}
+
+	if parameters == nil {
+		parameters = azure.ArmParameters{}
+	}
+
+	for name, value := range p.options.Parameters {
+		param, has := template.Parameters[name]
+		if !has {
+			return nil, fmt.Errorf("overriding parameter '%s': the template doesn't declare this parameter", name)
+		}
+
+		paramType := p.mapBicepTypeToInterfaceType(param.Type)
+
+		// Values of --parameter are strings, arrays and objects are given as JSON
+		if val, ok := value.(string); ok && (paramType == ParameterTypeArray || paramType == ParameterTypeObject) {
+			if err := json.Unmarshal([]byte(val), &value); err != nil {
+				return nil, fmt.Errorf("overriding parameter '%s': the value of a %s parameter must be JSON: %w",
+					name, param.Type, err)
+			}
+		}
+
+		value = armParameterFileValue(paramType, value)
+		if !isValueAssignableToParameterType(paramType, value) {
+			return nil, fmt.Errorf("overriding parameter '%s': the value isn't a valid %s", name, param.Type)
+		}
+
+		parameters[name] = azure.ArmParameterValue{Value: value}
+	}
+
+	return parameters, nil
+}
+
 // Convert the ARM parameters file value into a value suitable for deployment
 func armParameterFileValue(paramType ParameterType, value any) any {
 	// Relax the handling of bool and number types to accept convertible strings
@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appconfiguration/armappconfiguration"
@@ -26,6 +27,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockhttp"
@@ -121,6 +123,8 @@ func TestBicepPlanPrompt(t *testing.T) {
 		return strings.Contains(options.Message, "Save the value in the environment for future use")
 	}).Respond(false)
 
+	preparePermissionsMocks(mockContext, "*")
+
 	infraProvider := createBicepProvider(t, mockContext)
 	planningTask := infraProvider.Plan(*mockContext.Context)
 
@@ -458,6 +462,8 @@ func preparePlanningMocks(
 		},
 	}
 
+	preparePermissionsMocks(mockContext, "*")
+
 	bicepBytes, _ := json.Marshal(armTemplate)
 	deployResult := `
 	{
@@ -617,6 +623,37 @@ func prepareDestroyMocks(mockContext *mocks.MockContext) {
 				strings.Contains(request.URL.Path, "deletedservices/apim2-123"))
 	}).RespondFn(httpRespondFn)
 
+	// List the deployments of the environment
+	deploymentList := armresources.DeploymentListResult{
+		Value: []*armresources.DeploymentExtended{
+			{
+				ID:   convert.RefOf("/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deployments/test-env"),
+				Name: convert.RefOf("test-env"),
+				Properties: &armresources.DeploymentPropertiesExtended{
+					Timestamp: convert.RefOf(time.Date(2023, 10, 16, 9, 35, 12, 0, time.UTC)),
+				},
+			},
+			{
+				ID:   convert.RefOf("/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deployments/other-env"),
+				Name: convert.RefOf("other-env"),
+				Tags: map[string]*string{infra.EnvNameTag: convert.RefOf("other-env")},
+			},
+		},
+	}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(
+				strings.TrimSuffix(request.URL.Path, "/"),
+				"/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Resources/deployments")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deploymentListBytes, _ := json.Marshal(deploymentList)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBuffer(deploymentListBytes)),
+		}, nil
+	})
+
 	// Delete deployment
 	mockPollingUrl := "https://url-to-poll.net/keep-deleting"
 	mockContext.HttpClient.When(func(request *http.Request) bool {
@@ -730,3 +767,114 @@ func httpRespondFn(request *http.Request) (*http.Response, error) {
 		Body:       http.NoBody,
 	}, nil
 }
+
+func TestInjectTags(t *testing.T) {
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		"COST_CENTER": "cc-42",
+	})
+	provider := &BicepProvider{
+		env: env,
+		options: Options{
+			Tags: map[string]string{
+				"cost-center":  "${COST_CENTER}",
+				"azd-env-name": "other",
+			},
+		},
+	}
+
+	template := azure.ArmTemplate{
+		Parameters: azure.ArmTemplateParameterDefinitions{
+			"tags": {Type: "object", DefaultValue: map[string]any{"owner": "app-team"}},
+		},
+	}
+
+	parameters, err := provider.injectTags(template, azure.ArmParameters{
+		"tags": {Value: map[string]any{"azd-env-name": "dev"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"owner":        "app-team",
+		"azd-env-name": "dev",
+		"cost-center":  "cc-42",
+	}, parameters["tags"].Value)
+
+	// Templates without a tags parameter can't apply the tags
+	_, err = provider.injectTags(azure.ArmTemplate{}, azure.ArmParameters{})
+	require.ErrorContains(t, err, "no 'tags' object parameter")
+
+	// Templates are deployed unchanged without additional tags
+	provider.options.Tags = nil
+	parameters, err = provider.injectTags(azure.ArmTemplate{}, azure.ArmParameters{})
+	require.NoError(t, err)
+	require.Empty(t, parameters)
+}
+
+func TestApplyParameterOverrides(t *testing.T) {
+	provider := &BicepProvider{
+		options: Options{
+			Parameters: map[string]any{
+				"sku":      "B1",
+				"replicas": "3",
+				"zones":    `["1", "2"]`,
+				"settings": map[string]any{"debug": true},
+			},
+		},
+	}
+
+	template := azure.ArmTemplate{
+		Parameters: azure.ArmTemplateParameterDefinitions{
+			"sku":      {Type: "string"},
+			"replicas": {Type: "int"},
+			"zones":    {Type: "array"},
+			"settings": {Type: "object"},
+		},
+	}
+
+	parameters, err := provider.applyParameterOverrides(template, azure.ArmParameters{
+		"sku":      {Value: "P1v3"},
+		"location": {Value: "westus2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, azure.ArmParameters{
+		"sku":      {Value: "B1"},
+		"replicas": {Value: int64(3)},
+		"zones":    {Value: []any{"1", "2"}},
+		"settings": {Value: map[string]any{"debug": true}},
+		"location": {Value: "westus2"},
+	}, parameters)
+
+	t.Run("UndeclaredParameter", func(t *testing.T) {
+		provider := &BicepProvider{options: Options{Parameters: map[string]any{"size": "large"}}}
+
+		_, err := provider.applyParameterOverrides(template, azure.ArmParameters{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the template doesn't declare this parameter")
+	})
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		provider := &BicepProvider{options: Options{Parameters: map[string]any{"replicas": "many"}}}
+
+		_, err := provider.applyParameterOverrides(template, azure.ArmParameters{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the value isn't a valid int")
+	})
+}
+
+func TestStatefulResources(t *testing.T) {
+	resources := statefulResources(map[string][]azcli.AzCliResource{
+		"rg-web": {
+			{Id: "/rg-web/app", Name: "app", Type: string(infra.AzureResourceTypeContainerApp)},
+			{Id: "/rg-web/st", Name: "st", Type: string(infra.AzureResourceTypeStorageAccount)},
+		},
+		"rg-data": {
+			{Id: "/rg-data/sql", Name: "sql", Type: string(infra.AzureResourceTypeSqlServer)},
+			{Id: "/rg-data/kv", Name: "kv", Type: string(infra.AzureResourceTypeKeyVault)},
+		},
+	})
+
+	var names []string
+	for _, resource := range resources {
+		names = append(names, resource.Name)
+	}
+	require.Equal(t, []string{"kv", "sql", "st"}, names)
+}
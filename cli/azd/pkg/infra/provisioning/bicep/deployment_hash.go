@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+)
+
+// The paths of the last successful deployment in the configuration of the environment. Provisioning doesn't submit the
+// deployment again while the template, its parameters and the scope are unchanged.
+const (
+	deploymentHashConfigPath = "provision.deployment.hash"
+	deploymentNameConfigPath = "provision.deployment.name"
+)
+
+// deploymentHash returns the hash of the compiled template and the parameters deployed to the scope. The name of the
+// deployment isn't part of the hash, as each provisioning may create a new deployment.
+func deploymentHash(
+	scope infra.Scope,
+	template azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+) (string, error) {
+	// The keys of the parameters are marshalled sorted, the same parameters always have the same hash
+	parametersJson, err := json.Marshal(parameters)
+	if err != nil {
+		return "", fmt.Errorf("marshalling parameters: %w", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(scopeKey(scope)))
+	hash.Write([]byte{0})
+	hash.Write(template)
+	hash.Write([]byte{0})
+	hash.Write(parametersJson)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// scopeKey identifies where the scope deploys to, like subscriptions/<id>/locations/<location>.
+func scopeKey(scope infra.Scope) string {
+	switch s := scope.(type) {
+	case *infra.ResourceGroupScope:
+		return fmt.Sprintf("subscriptions/%s/resourceGroups/%s", s.SubscriptionId(), s.ResourceGroup())
+	case *infra.SubscriptionScope:
+		return fmt.Sprintf("subscriptions/%s/locations/%s", s.SubscriptionId(), s.Location())
+	default:
+		return scope.DeploymentUrl()
+	}
+}
+
+// namedScope returns a scope like scope, for the deployment with the given name.
+func (p *BicepProvider) namedScope(scope infra.Scope, name string) infra.Scope {
+	switch s := scope.(type) {
+	case *infra.ResourceGroupScope:
+		return infra.NewResourceGroupScope(p.azCli, s.SubscriptionId(), s.ResourceGroup(), name)
+	case *infra.SubscriptionScope:
+		return infra.NewSubscriptionScope(p.azCli, s.Location(), s.SubscriptionId(), name)
+	default:
+		return nil
+	}
+}
+
+// unchangedDeployment returns the last successful deployment of the environment when it deployed the template and the
+// parameters with the same hash, or nil when they changed or the deployment doesn't exist anymore.
+func (p *BicepProvider) unchangedDeployment(
+	ctx context.Context,
+	scope infra.Scope,
+	hash string,
+) *armresources.DeploymentExtended {
+	lastHash, _ := p.env.Config.Get(deploymentHashConfigPath)
+	lastName, _ := p.env.Config.Get(deploymentNameConfigPath)
+	name, ok := lastName.(string)
+	if lastHash != hash || !ok || name == "" {
+		return nil
+	}
+
+	lastScope := p.namedScope(scope, name)
+	if lastScope == nil {
+		return nil
+	}
+
+	deployment, err := lastScope.GetDeployment(ctx)
+	if err != nil {
+		log.Printf("failed getting the last deployment '%s', deploying the template: %v", name, err)
+		return nil
+	}
+
+	if deployment.Properties == nil || deployment.Properties.ProvisioningState == nil ||
+		*deployment.Properties.ProvisioningState != armresources.ProvisioningStateSucceeded {
+		return nil
+	}
+
+	return deployment
+}
+
+// saveDeploymentHash records the deployment of the scope as the last successful deployment of the environment.
+func (p *BicepProvider) saveDeploymentHash(scope infra.Scope, hash string) error {
+	if err := p.env.Config.Set(deploymentHashConfigPath, hash); err != nil {
+		return err
+	}
+
+	if err := p.env.Config.Set(deploymentNameConfigPath, scope.Name()); err != nil {
+		return err
+	}
+
+	return p.env.Save()
+}
+
+// clearDeploymentHash forgets the last successful deployment of the environment. The environment is saved by the caller.
+func (p *BicepProvider) clearDeploymentHash() error {
+	if err := p.env.Config.Unset(deploymentHashConfigPath); err != nil {
+		return err
+	}
+
+	return p.env.Config.Unset(deploymentNameConfigPath)
+}
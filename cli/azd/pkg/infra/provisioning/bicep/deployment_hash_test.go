@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentHash(t *testing.T) {
+	template := azure.RawArmTemplate(`{"resources":[]}`)
+	parameters := azure.ArmParameters{
+		"location":        {Value: "eastus2"},
+		"environmentName": {Value: "dev"},
+	}
+	scope := infra.NewSubscriptionScope(nil, "eastus2", "SUBSCRIPTION_ID", "dev-1")
+
+	hash, err := deploymentHash(scope, template, parameters)
+	require.NoError(t, err)
+
+	// A new deployment of the same template and parameters has the same hash
+	sameHash, err := deploymentHash(
+		infra.NewSubscriptionScope(nil, "eastus2", "SUBSCRIPTION_ID", "dev-2"), template, parameters)
+	require.NoError(t, err)
+	require.Equal(t, hash, sameHash)
+
+	changed := map[string]func() (string, error){
+		"Template": func() (string, error) {
+			return deploymentHash(scope, azure.RawArmTemplate(`{"resources":[{}]}`), parameters)
+		},
+		"Parameters": func() (string, error) {
+			return deploymentHash(scope, template, azure.ArmParameters{
+				"location":        {Value: "westus"},
+				"environmentName": {Value: "dev"},
+			})
+		},
+		"Scope": func() (string, error) {
+			return deploymentHash(
+				infra.NewResourceGroupScope(nil, "SUBSCRIPTION_ID", "rg-dev", "dev-1"), template, parameters)
+		},
+	}
+
+	for name, changedHash := range changed {
+		t.Run(name, func(t *testing.T) {
+			otherHash, err := changedHash()
+			require.NoError(t, err)
+			require.NotEqual(t, hash, otherHash)
+		})
+	}
+}
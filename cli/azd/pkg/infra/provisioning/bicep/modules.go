@@ -0,0 +1,410 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// bicepConfigFileName is the configuration file Bicep finds in the directory of a template, or in one of its parents.
+const bicepConfigFileName = "bicepconfig.json"
+
+// environmentModulesDir is the directory of the environment holding the modules restored for the environment, with
+// the layout of the Bicep cache. Environments pin the modules they were provisioned with, whatever the modules restored
+// for the other environments.
+const environmentModulesDir = "bicep"
+
+// The files of a module in the Bicep cache
+var moduleFiles = []string{"main.json", "manifest", "metadata"}
+
+// moduleMetadata is the metadata file of a module in the Bicep cache.
+type moduleMetadata struct {
+	ManifestDigest string `json:"manifestDigest"`
+}
+
+// bicepConfig is the subset of bicepconfig.json read and generated by azd.
+// https://learn.microsoft.com/azure/azure-resource-manager/bicep/bicep-config
+type bicepConfig struct {
+	CacheRootDirectory string              `json:"cacheRootDirectory,omitempty"`
+	ModuleAliases      *bicepModuleAliases `json:"moduleAliases,omitempty"`
+}
+
+type bicepModuleAliases struct {
+	Br map[string]bicepModuleAlias `json:"br,omitempty"`
+}
+
+type bicepModuleAlias struct {
+	Registry   string `json:"registry"`
+	ModulePath string `json:"modulePath,omitempty"`
+}
+
+// moduleReference is a reference to a module of a registry, like br:contoso.azurecr.io/bicep/storage:v1 or
+// br/contoso:storage:v1.
+type moduleReference struct {
+	// The reference as written in the template
+	Raw        string
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+var moduleReferenceRegex = regexp.MustCompile(`(?m)^\s*module\s+\w+\s+'(br[:/][^']+)'`)
+
+// restoreModules downloads the modules of the private registries of azure.yaml referenced by the templates of the
+// directory of the module into the modules of the environment, and copies them to the Bicep cache when the cache holds
+// another digest of the modules, so Bicep builds the templates without authenticating to the registries. The modules
+// restored for the environment are not downloaded again. A bicepconfig.json with an alias for each registry is
+// generated when the templates have none.
+func (p *BicepProvider) restoreModules(ctx context.Context, modulePath string) error {
+	if len(p.options.Registries) == 0 {
+		return nil
+	}
+
+	if p.env.Root == "" {
+		log.Printf("skipping the restore of bicep modules, environment %s isn't saved", p.env.GetEnvName())
+		return nil
+	}
+
+	infraDir := filepath.Dir(modulePath)
+	config, err := p.ensureBicepConfig(ctx, infraDir)
+	if err != nil {
+		return err
+	}
+
+	references, err := findModuleReferences(infraDir)
+	if err != nil {
+		return err
+	}
+
+	cacheRoot, err := bicepCacheRoot(config)
+	if err != nil {
+		return err
+	}
+
+	for _, raw := range references {
+		reference, ok := parseModuleReference(raw, config.ModuleAliases)
+		if !ok {
+			continue
+		}
+
+		subscriptionId, ok := p.registrySubscription(reference.Registry)
+		if !ok {
+			// Modules of other registries, like the public registry, are restored by Bicep
+			continue
+		}
+
+		envModuleDir := filepath.Join(p.env.Root, environmentModulesDir, "br", reference.cachePath())
+		digest, err := readModuleDigest(envModuleDir)
+		if err != nil {
+			return fmt.Errorf("restoring module %s: %w", raw, err)
+		}
+
+		if digest == "" {
+			digest, err = p.pullModule(ctx, subscriptionId, reference, envModuleDir)
+			if err != nil {
+				return fmt.Errorf("restoring module %s: %w", raw, err)
+			}
+		}
+
+		cacheModuleDir := filepath.Join(cacheRoot, "br", reference.cachePath())
+		cachedDigest, err := readModuleDigest(cacheModuleDir)
+		if err != nil {
+			return fmt.Errorf("restoring module %s: %w", raw, err)
+		}
+
+		if cachedDigest == digest {
+			continue
+		}
+
+		log.Printf("copying bicep module %s with digest %s to the bicep cache", raw, digest)
+		if err := copyModule(envModuleDir, cacheModuleDir); err != nil {
+			return fmt.Errorf("restoring module %s: %w", raw, err)
+		}
+	}
+
+	return nil
+}
+
+// pullModule downloads the module to the directory, and returns the digest of its manifest.
+func (p *BicepProvider) pullModule(
+	ctx context.Context,
+	subscriptionId string,
+	reference moduleReference,
+	moduleDir string,
+) (string, error) {
+	location := reference.Tag
+	if reference.Digest != "" {
+		location = reference.Digest
+	}
+
+	log.Printf("restoring bicep module %s", reference.Raw)
+	module, err := p.azCli.PullBicepModule(ctx, subscriptionId, reference.Registry, reference.Repository, location)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := json.Marshal(moduleMetadata{ManifestDigest: module.ManifestDigest})
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(moduleDir, osutil.PermissionDirectory); err != nil {
+		return "", err
+	}
+
+	// The metadata is written last, a module without metadata is downloaded again
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{"main.json", module.Template},
+		{"manifest", module.Manifest},
+		{"metadata", metadata},
+	} {
+		if err := os.WriteFile(filepath.Join(moduleDir, file.name), file.content, osutil.PermissionFile); err != nil {
+			return "", err
+		}
+	}
+
+	return module.ManifestDigest, nil
+}
+
+// readModuleDigest returns the digest of the manifest of the module of the directory, or an empty string when the
+// directory has no complete module.
+func readModuleDigest(moduleDir string) (string, error) {
+	for _, name := range moduleFiles {
+		if _, err := os.Stat(filepath.Join(moduleDir, name)); errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(moduleDir, "metadata"))
+	if err != nil {
+		return "", err
+	}
+
+	var metadata moduleMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		log.Printf("ignoring the invalid metadata of bicep module %s: %v", moduleDir, err)
+		return "", nil
+	}
+
+	return metadata.ManifestDigest, nil
+}
+
+// copyModule copies the files of the module to another directory, the metadata last.
+func copyModule(sourceDir string, targetDir string) error {
+	if err := os.MkdirAll(targetDir, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	for _, name := range moduleFiles {
+		content, err := os.ReadFile(filepath.Join(sourceDir, name))
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(targetDir, name), content, osutil.PermissionFile); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureBicepConfig reads the bicepconfig.json of the templates, or generates one with an alias for each registry of
+// azure.yaml when there is none.
+func (p *BicepProvider) ensureBicepConfig(ctx context.Context, infraDir string) (*bicepConfig, error) {
+	configPath := filepath.Join(infraDir, bicepConfigFileName)
+	content, err := os.ReadFile(configPath)
+	if err == nil {
+		var config bicepConfig
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", configPath, err)
+		}
+
+		for _, registry := range p.options.Registries {
+			if config.ModuleAliases == nil || config.ModuleAliases.Br[registry.Alias].Registry == "" {
+				p.console.Message(ctx, output.WithWarningFormat(
+					"WARNING: %s has no module alias '%s' for registry %s", configPath, registry.Alias, registry.Server))
+			}
+		}
+
+		return &config, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading %s: %w", configPath, err)
+	}
+
+	config := bicepConfig{
+		ModuleAliases: &bicepModuleAliases{Br: map[string]bicepModuleAlias{}},
+	}
+	for _, registry := range p.options.Registries {
+		config.ModuleAliases.Br[registry.Alias] = bicepModuleAlias{
+			Registry:   registry.Server,
+			ModulePath: strings.Trim(registry.ModulePath, "/"),
+		}
+	}
+
+	content, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(configPath, append(content, '\n'), osutil.PermissionFile); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", configPath, err)
+	}
+
+	p.console.Message(ctx, fmt.Sprintf("Created %s with the module registries of azure.yaml.",
+		output.WithHighLightFormat(filepath.Join(filepath.Base(infraDir), bicepConfigFileName))))
+	return &config, nil
+}
+
+// registrySubscription returns the subscription azd authenticates to a registry of azure.yaml with, and false for
+// registries missing from azure.yaml.
+func (p *BicepProvider) registrySubscription(server string) (string, bool) {
+	for _, registry := range p.options.Registries {
+		if strings.EqualFold(registry.Server, server) {
+			if registry.SubscriptionId != "" {
+				return registry.SubscriptionId, true
+			}
+
+			return p.env.GetSubscriptionId(), true
+		}
+	}
+
+	return "", false
+}
+
+// findModuleReferences returns the registry module references of the Bicep files of the directory and its
+// subdirectories.
+func findModuleReferences(dir string) ([]string, error) {
+	var references []string
+	seen := map[string]bool{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(path) != ".bicep" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range moduleReferenceRegex.FindAllStringSubmatch(string(content), -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				references = append(references, match[1])
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finding module references: %w", err)
+	}
+
+	return references, nil
+}
+
+// parseModuleReference parses a reference to a module of a registry, resolving the aliases of bicepconfig.json. It
+// returns false for references it can't parse, or with an unknown alias.
+func parseModuleReference(raw string, aliases *bicepModuleAliases) (moduleReference, bool) {
+	reference := moduleReference{Raw: raw}
+
+	var path string
+	if value, ok := strings.CutPrefix(raw, "br:"); ok {
+		registry, rest, found := strings.Cut(value, "/")
+		if !found {
+			return moduleReference{}, false
+		}
+
+		reference.Registry = registry
+		path = rest
+	} else if value, ok := strings.CutPrefix(raw, "br/"); ok {
+		aliasName, rest, found := strings.Cut(value, ":")
+		if !found || aliases == nil {
+			return moduleReference{}, false
+		}
+
+		alias, has := aliases.Br[aliasName]
+		if !has {
+			return moduleReference{}, false
+		}
+
+		reference.Registry = alias.Registry
+		path = rest
+		if alias.ModulePath != "" {
+			path = strings.Trim(alias.ModulePath, "/") + "/" + rest
+		}
+	} else {
+		return moduleReference{}, false
+	}
+
+	if repository, digest, found := strings.Cut(path, "@"); found {
+		reference.Repository = repository
+		reference.Digest = digest
+	} else if index := strings.LastIndex(path, ":"); index > 0 {
+		reference.Repository = path[:index]
+		reference.Tag = path[index+1:]
+	}
+
+	if reference.Repository == "" || (reference.Tag == "" && reference.Digest == "") {
+		return moduleReference{}, false
+	}
+
+	return reference, true
+}
+
+// cachePath returns the directory of the module in the br directory of the Bicep cache.
+func (r moduleReference) cachePath() string {
+	location := r.Tag + "$"
+	if r.Digest != "" {
+		location = strings.ReplaceAll(r.Digest, ":", "#")
+	}
+
+	return filepath.Join(
+		url.QueryEscape(strings.ToLower(r.Registry)),
+		strings.ReplaceAll(strings.ToLower(r.Repository), "/", "$"),
+		location,
+	)
+}
+
+// bicepCacheRoot returns the root directory of the Bicep cache, ~/.bicep unless bicepconfig.json sets another one.
+func bicepCacheRoot(config *bicepConfig) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding the Bicep cache: %w", err)
+	}
+
+	if config.CacheRootDirectory == "" {
+		return filepath.Join(home, ".bicep"), nil
+	}
+
+	if root, ok := strings.CutPrefix(config.CacheRootDirectory, "~"); ok {
+		return filepath.Join(home, root), nil
+	}
+
+	return config.CacheRootDirectory, nil
+}
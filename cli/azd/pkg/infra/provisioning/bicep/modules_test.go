@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModuleReference(t *testing.T) {
+	aliases := &bicepModuleAliases{Br: map[string]bicepModuleAlias{
+		"contoso": {Registry: "contoso.azurecr.io", ModulePath: "bicep/modules"},
+	}}
+
+	tests := []struct {
+		raw      string
+		expected moduleReference
+		ok       bool
+	}{
+		{
+			raw: "br:contoso.azurecr.io/bicep/storage:v1",
+			expected: moduleReference{
+				Registry: "contoso.azurecr.io", Repository: "bicep/storage", Tag: "v1",
+			},
+			ok: true,
+		},
+		{
+			raw: "br/contoso:storage:v1",
+			expected: moduleReference{
+				Registry: "contoso.azurecr.io", Repository: "bicep/modules/storage", Tag: "v1",
+			},
+			ok: true,
+		},
+		{
+			raw: "br:contoso.azurecr.io/bicep/storage@sha256:0123",
+			expected: moduleReference{
+				Registry: "contoso.azurecr.io", Repository: "bicep/storage", Digest: "sha256:0123",
+			},
+			ok: true,
+		},
+		{raw: "br/public:avm/res/storage:0.1.0", ok: false},
+		{raw: "br:contoso.azurecr.io/bicep/storage", ok: false},
+		{raw: "ts:sub/rg/spec:v1", ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			reference, ok := parseModuleReference(test.raw, aliases)
+			require.Equal(t, test.ok, ok)
+			if test.ok {
+				test.expected.Raw = test.raw
+				require.Equal(t, test.expected, reference)
+			}
+		})
+	}
+}
+
+func TestModuleReferenceCachePath(t *testing.T) {
+	tagged := moduleReference{Registry: "contoso.azurecr.io", Repository: "bicep/storage", Tag: "v1"}
+	require.Equal(t, filepath.Join("contoso.azurecr.io", "bicep$storage", "v1$"), tagged.cachePath())
+
+	pinned := moduleReference{Registry: "contoso.azurecr.io", Repository: "storage", Digest: "sha256:0123"}
+	require.Equal(t, filepath.Join("contoso.azurecr.io", "storage", "sha256#0123"), pinned.cachePath())
+}
+
+func TestRestoreModules(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	infraDir := t.TempDir()
+	mainBicep := "module storage 'br/contoso:storage:v1' = {\n  name: 'storage'\n}\n" +
+		"module identity 'br/public:avm/res/identity:0.1.0' = {\n  name: 'identity'\n}\n"
+	err := os.WriteFile(filepath.Join(infraDir, "main.bicep"), []byte(mainBicep), osutil.PermissionFile)
+	require.NoError(t, err)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	pulls := 0
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Host == "contoso.azurecr.io"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		switch {
+		case request.URL.Path == "/oauth2/exchange":
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]string{"refresh_token": "REFRESH"})
+		case request.URL.Path == "/oauth2/token":
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]string{"access_token": "ACCESS"})
+		case request.URL.Path == "/v2/bicep/modules/storage/manifests/v1":
+			pulls++
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"layers": []map[string]string{
+					{"mediaType": "application/vnd.ms.bicep.module.layer.v1+json", "digest": "sha256:layer"},
+				},
+			})
+		case strings.HasSuffix(request.URL.Path, "/blobs/sha256:layer"):
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"resources": []any{}})
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+	})
+
+	provider := createBicepProvider(t, mockContext)
+	provider.env.Root = t.TempDir()
+	provider.options.Registries = []ModuleRegistry{
+		{Alias: "contoso", Server: "contoso.azurecr.io", ModulePath: "bicep/modules"},
+	}
+
+	err = provider.restoreModules(*mockContext.Context, filepath.Join(infraDir, "main.bicep"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pulls)
+
+	content, err := os.ReadFile(filepath.Join(infraDir, bicepConfigFileName))
+	require.NoError(t, err)
+	var config bicepConfig
+	require.NoError(t, json.Unmarshal(content, &config))
+	require.Equal(t, bicepModuleAlias{Registry: "contoso.azurecr.io", ModulePath: "bicep/modules"},
+		config.ModuleAliases.Br["contoso"])
+
+	modulePath := filepath.Join("br", "contoso.azurecr.io", "bicep$modules$storage", "v1$")
+	envModuleDir := filepath.Join(provider.env.Root, environmentModulesDir, modulePath)
+	cacheModuleDir := filepath.Join(home, ".bicep", modulePath)
+	for _, moduleDir := range []string{envModuleDir, cacheModuleDir} {
+		template, err := os.ReadFile(filepath.Join(moduleDir, "main.json"))
+		require.NoError(t, err)
+		require.JSONEq(t, `{"resources": []}`, string(template))
+	}
+
+	envMetadata, err := os.ReadFile(filepath.Join(envModuleDir, "metadata"))
+	require.NoError(t, err)
+
+	// The module restored for the environment is not downloaded again
+	err = provider.restoreModules(*mockContext.Context, filepath.Join(infraDir, "main.bicep"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pulls)
+
+	// Another digest in the Bicep cache, like the module restored for another environment, is replaced by the module of
+	// the environment
+	otherMetadata := []byte(`{"manifestDigest":"sha256:other"}`)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheModuleDir, "metadata"), otherMetadata, osutil.PermissionFile))
+
+	err = provider.restoreModules(*mockContext.Context, filepath.Join(infraDir, "main.bicep"))
+	require.NoError(t, err)
+	require.Equal(t, 1, pulls)
+
+	cacheMetadata, err := os.ReadFile(filepath.Join(cacheModuleDir, "metadata"))
+	require.NoError(t, err)
+	require.Equal(t, envMetadata, cacheMetadata)
+}
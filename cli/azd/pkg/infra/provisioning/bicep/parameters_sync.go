@@ -0,0 +1,339 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const parametersFileSchema = "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#"
+
+// The environment values of the parameters every azd template declares
+var wellKnownParameterEnvVarNames = map[string]string{
+	"environmentName": environment.EnvNameEnvVarName,
+	"location":        environment.LocationEnvVarName,
+	"principalId":     environment.PrincipalIdEnvVarName,
+}
+
+// Matches parameter values which are a single environment value reference, like ${AZURE_LOCATION}
+var envVarReferenceRegex = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*)\}$`)
+
+// ParametersSyncResult reports the changes made to a parameters file.
+type ParametersSyncResult struct {
+	// The parameters added to the parameters file
+	Added []string
+	// The parameters removed from the parameters file because the template no longer declares them
+	Removed []string
+	// The environment values set from the literal values of an imported parameters file
+	Imported []string
+}
+
+// ParametersSync keeps the parameters file of a Bicep module in sync with the parameters of the template and the values
+// of the environment. The generated parameters reference environment values, like ${AZURE_LOCATION}, so the file can be
+// committed and diffed while the values stay in the environment.
+type ParametersSync struct {
+	bicepCli bicep.BicepCli
+}
+
+// NewParametersSync creates a new instance of the ParametersSync
+func NewParametersSync(bicepCli bicep.BicepCli) *ParametersSync {
+	return &ParametersSync{
+		bicepCli: bicepCli,
+	}
+}
+
+// Generate writes the parameters file of the module. The existing parameters are kept as is, parameters the template no
+// longer declares are removed, and the required parameters, or the ones with a value in the environment, are added
+// with a reference to their environment value.
+func (s *ParametersSync) Generate(
+	ctx context.Context,
+	modulePath string,
+	parametersFilePath string,
+	env *environment.Environment,
+) (*ParametersSyncResult, error) {
+	template, err := s.compile(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters, err := readParametersFile(parametersFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ParametersSyncResult{}
+
+	for _, name := range sortedKeys(parameters) {
+		if _, has := template.Parameters[name]; !has {
+			delete(parameters, name)
+			result.Removed = append(result.Removed, name)
+		}
+	}
+
+	for _, name := range sortedKeys(template.Parameters) {
+		if _, has := parameters[name]; has {
+			continue
+		}
+
+		envVarName := ParameterEnvVarName(name)
+		envValue, hasValue := env.Values[envVarName]
+		if template.Parameters[name].DefaultValue != nil && !hasValue {
+			continue
+		}
+
+		if isStructuredParameterType(template.Parameters[name].Type) {
+			// Environment values are strings, object and array parameters keep a literal value. The required ones
+			// without a value are prompted for when provisioning.
+			if !hasValue || !json.Valid([]byte(envValue)) {
+				continue
+			}
+
+			parameters[name] = json.RawMessage(fmt.Sprintf(`{"value": %s}`, envValue))
+			result.Added = append(result.Added, name)
+			continue
+		}
+
+		parameters[name] = envVarReference(envVarName)
+		result.Added = append(result.Added, name)
+	}
+
+	if err := writeParametersFile(parametersFilePath, parameters); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Import stores the literal values of an existing parameters file in the environment and writes the parameters file of
+// the module with references to them. References to Key Vault secrets and values which already reference the
+// environment are kept as is. The caller saves the environment.
+func (s *ParametersSync) Import(
+	ctx context.Context,
+	sourcePath string,
+	parametersFilePath string,
+	env *environment.Environment,
+) (*ParametersSyncResult, error) {
+	source, err := readParametersFile(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(source) == 0 {
+		return nil, fmt.Errorf("%s does not contain any parameters", sourcePath)
+	}
+
+	parameters, err := readParametersFile(parametersFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ParametersSyncResult{}
+
+	for _, name := range sortedKeys(source) {
+		var parameter azure.ArmParameterValue
+		if err := json.Unmarshal(source[name], &parameter); err != nil || parameter.Value == nil {
+			// Key Vault references and other non literal values are kept as is
+			parameters[name] = source[name]
+			continue
+		}
+
+		switch parameter.Value.(type) {
+		case map[string]any, []any:
+			// Environment values are strings, object and array values stay literal to keep their type
+			parameters[name] = source[name]
+			continue
+		}
+
+		value, err := environmentValue(parameter.Value)
+		if err != nil {
+			return nil, fmt.Errorf("converting the value of parameter '%s': %w", name, err)
+		}
+
+		if envVarReferenceRegex.MatchString(value) || strings.Contains(value, "$(") {
+			parameters[name] = source[name]
+			continue
+		}
+
+		// Reuse the environment value the parameter already references
+		envVarName := ParameterEnvVarName(name)
+		if existing, has := parameters[name]; has {
+			if referenced := referencedEnvVarName(existing); referenced != "" {
+				envVarName = referenced
+			}
+		}
+
+		env.Values[envVarName] = value
+		parameters[name] = envVarReference(envVarName)
+		result.Imported = append(result.Imported, envVarName)
+	}
+
+	if err := writeParametersFile(parametersFilePath, parameters); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *ParametersSync) compile(ctx context.Context, modulePath string) (azure.ArmTemplate, error) {
+	compiled, err := buildTemplate(ctx, s.bicepCli, modulePath)
+	if err != nil {
+		return azure.ArmTemplate{}, err
+	}
+
+	var template azure.ArmTemplate
+	if err := json.Unmarshal(compiled, &template); err != nil {
+		return azure.ArmTemplate{}, fmt.Errorf("failed unmarshalling arm template from json: %w", err)
+	}
+
+	return template, nil
+}
+
+// ParameterEnvVarName returns the name of the environment value of a template parameter. The parameters declared by
+// every azd template use their well known environment values, other parameters use their name in upper snake case,
+// prefixed with AZURE_, like AZURE_DATABASE_SKU for databaseSku.
+func ParameterEnvVarName(parameterName string) string {
+	if envVarName, has := wellKnownParameterEnvVarNames[parameterName]; has {
+		return envVarName
+	}
+
+	var sb strings.Builder
+	sb.WriteString("AZURE_")
+
+	runes := []rune(parameterName)
+	for i, r := range runes {
+		switch {
+		case r == '-' || r == '.' || r == ' ':
+			sb.WriteRune('_')
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			sb.WriteRune('_')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(unicode.ToUpper(r))
+		}
+	}
+
+	return sb.String()
+}
+
+// environmentValue converts a literal parameter value to its environment value. Strings are stored as is, booleans and
+// numbers are stored as JSON, which azd converts back to the type of the parameter when provisioning.
+func environmentValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	content, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// isStructuredParameterType returns true for the object and array parameter types, which can't be read from the
+// environment.
+func isStructuredParameterType(parameterType string) bool {
+	switch strings.ToLower(parameterType) {
+	case "object", "secureobject", "array":
+		return true
+	default:
+		return false
+	}
+}
+
+func envVarReference(envVarName string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(`{"value": "${%s}"}`, envVarName))
+}
+
+// referencedEnvVarName returns the name of the environment value the parameter references, or an empty string when the
+// parameter is not a single environment value reference.
+func referencedEnvVarName(parameter json.RawMessage) string {
+	var value azure.ArmParameterValue
+	if err := json.Unmarshal(parameter, &value); err != nil {
+		return ""
+	}
+
+	s, ok := value.Value.(string)
+	if !ok {
+		return ""
+	}
+
+	if match := envVarReferenceRegex.FindStringSubmatch(s); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+// readParametersFile reads the parameters of a parameters file, the parameters are empty when the file does not exist.
+func readParametersFile(path string) (map[string]json.RawMessage, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]json.RawMessage{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading parameters file: %w", err)
+	}
+
+	var file struct {
+		Parameters map[string]json.RawMessage `json:"parameters"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("parsing parameters file %s: %w", path, err)
+	}
+
+	if file.Parameters == nil {
+		file.Parameters = map[string]json.RawMessage{}
+	}
+
+	return file.Parameters, nil
+}
+
+// writeParametersFile writes the parameters sorted by name, so the generated files are stable and diffable.
+func writeParametersFile(path string, parameters map[string]json.RawMessage) error {
+	file := struct {
+		Schema         string                     `json:"$schema"`
+		ContentVersion string                     `json:"contentVersion"`
+		Parameters     map[string]json.RawMessage `json:"parameters"`
+	}{
+		Schema:         parametersFileSchema,
+		ContentVersion: "1.0.0.0",
+		Parameters:     parameters,
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return fmt.Errorf("marshalling parameters file: %w", err)
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing parameters file: %w", err)
+	}
+
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
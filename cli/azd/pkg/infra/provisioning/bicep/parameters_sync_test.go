@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterEnvVarName(t *testing.T) {
+	tests := map[string]string{
+		"environmentName": "AZURE_ENV_NAME",
+		"location":        "AZURE_LOCATION",
+		"principalId":     "AZURE_PRINCIPAL_ID",
+		"databaseSku":     "AZURE_DATABASE_SKU",
+		"apiURL":          "AZURE_API_URL",
+		"URLPath":         "AZURE_URL_PATH",
+		"app-name":        "AZURE_APP_NAME",
+	}
+
+	for parameterName, expected := range tests {
+		require.Equal(t, expected, ParameterEnvVarName(parameterName), parameterName)
+	}
+}
+
+func TestParametersSyncGenerate(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockBicepBuild(t, mockContext, azure.ArmTemplateParameterDefinitions{
+		"environmentName": {Type: "string"},
+		"location":        {Type: "string"},
+		"databaseSku":     {Type: "string", DefaultValue: "Basic"},
+		"replicaCount":    {Type: "int", DefaultValue: 1},
+		"apiName":         {Type: "string"},
+		"tags":            {Type: "object"},
+		"allowedIps":      {Type: "array"},
+	})
+
+	parametersFilePath := filepath.Join(t.TempDir(), "main.parameters.json")
+	writeTestParametersFile(t, parametersFilePath, `{
+		"parameters": {
+			"apiName": {"value": "${API_NAME}"},
+			"removedParameter": {"value": "value"}
+		}
+	}`)
+
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		environment.LocationEnvVarName: "westus2",
+		"AZURE_REPLICA_COUNT":          "3",
+		"AZURE_TAGS":                   `{"team": "web"}`,
+	})
+
+	sync := NewParametersSync(&testBicep{commandRunner: mockContext.CommandRunner})
+	result, err := sync.Generate(*mockContext.Context, "main.bicep", parametersFilePath, env)
+	require.NoError(t, err)
+	// Object and array parameters keep a literal value, the ones without a value are prompted for when provisioning
+	require.Equal(t, []string{"environmentName", "location", "replicaCount", "tags"}, result.Added)
+	require.Equal(t, []string{"removedParameter"}, result.Removed)
+
+	content, err := os.ReadFile(parametersFilePath)
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "apiName": {
+      "value": "${API_NAME}"
+    },
+    "environmentName": {
+      "value": "${AZURE_ENV_NAME}"
+    },
+    "location": {
+      "value": "${AZURE_LOCATION}"
+    },
+    "replicaCount": {
+      "value": "${AZURE_REPLICA_COUNT}"
+    },
+    "tags": {
+      "value": {
+        "team": "web"
+      }
+    }
+  }
+}
+`, string(content))
+}
+
+func TestParametersSyncImport(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	projectDir := t.TempDir()
+
+	sourcePath := filepath.Join(projectDir, "azuredeploy.parameters.json")
+	writeTestParametersFile(t, sourcePath, `{
+		"parameters": {
+			"location": {"value": "eastus"},
+			"apiName": {"value": "contoso-api"},
+			"replicaCount": {"value": 3},
+			"tags": {"value": {"team": "web"}},
+			"environmentName": {"value": "${AZURE_ENV_NAME}"},
+			"adminPassword": {
+				"reference": {
+					"keyVault": {"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv"},
+					"secretName": "adminPassword"
+				}
+			}
+		}
+	}`)
+
+	parametersFilePath := filepath.Join(projectDir, "main.parameters.json")
+	writeTestParametersFile(t, parametersFilePath, `{
+		"parameters": {
+			"apiName": {"value": "${API_NAME}"}
+		}
+	}`)
+
+	env := environment.EphemeralWithValues("dev", nil)
+
+	sync := NewParametersSync(nil)
+	result, err := sync.Import(*mockContext.Context, sourcePath, parametersFilePath, env)
+	require.NoError(t, err)
+	require.Equal(t, []string{"API_NAME", "AZURE_LOCATION", "AZURE_REPLICA_COUNT"}, result.Imported)
+
+	require.Equal(t, "contoso-api", env.Values["API_NAME"])
+	require.Equal(t, "eastus", env.Values[environment.LocationEnvVarName])
+	require.Equal(t, "3", env.Values["AZURE_REPLICA_COUNT"])
+	require.NotContains(t, env.Values, "AZURE_TAGS")
+
+	var file struct {
+		Parameters map[string]json.RawMessage `json:"parameters"`
+	}
+	content, err := os.ReadFile(parametersFilePath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(content, &file))
+
+	require.Equal(t, "API_NAME", referencedEnvVarName(file.Parameters["apiName"]))
+	require.Equal(t, "AZURE_LOCATION", referencedEnvVarName(file.Parameters["location"]))
+	require.Equal(t, "AZURE_ENV_NAME", referencedEnvVarName(file.Parameters["environmentName"]))
+	require.Contains(t, string(file.Parameters["adminPassword"]), "keyVault")
+	require.JSONEq(t, `{"value": {"team": "web"}}`, string(file.Parameters["tags"]))
+}
+
+func mockBicepBuild(t *testing.T, mockContext *mocks.MockContext, parameters azure.ArmTemplateParameterDefinitions) {
+	template, err := json.Marshal(azure.ArmTemplate{
+		Schema:         "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+		ContentVersion: "1.0.0.0",
+		Parameters:     parameters,
+	})
+	require.NoError(t, err)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(args.Cmd, "bicep") && args.Args[0] == "build"
+	}).Respond(exec.NewRunResult(0, string(template), ""))
+}
+
+func writeTestParametersFile(t *testing.T, path string, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The type of the role assignments of a template
+const roleAssignmentResourceType = "Microsoft.Authorization/roleAssignments"
+
+// templateActions returns the actions needed to deploy the template at the subscription scope: creating the deployment,
+// and creating role assignments when the template, or one of its modules, declares some.
+func templateActions(rawTemplate azure.RawArmTemplate) ([]string, error) {
+	var template map[string]any
+	if err := json.Unmarshal(rawTemplate, &template); err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+
+	actions := []string{azcli.DeploymentsWriteAction}
+	assignsRoles := false
+	forEachTemplateResource(template, func(resourceType string) {
+		assignsRoles = assignsRoles || strings.EqualFold(resourceType, roleAssignmentResourceType)
+	})
+
+	if assignsRoles {
+		actions = append(actions, azcli.RoleAssignmentsWriteAction)
+	}
+
+	return actions, nil
+}
+
+// ensurePermissions fails with an *azcli.MissingPermissionsError when the signed-in principal isn't allowed to deploy
+// the template in the subscription of the environment, instead of the deployment failing after creating part of the
+// resources. The check is skipped when the permissions can't be read.
+func (p *BicepProvider) ensurePermissions(ctx context.Context, rawTemplate azure.RawArmTemplate) error {
+	actions, err := templateActions(rawTemplate)
+	if err != nil {
+		log.Printf("skipping the permissions check: %v", err)
+		return nil
+	}
+
+	subscriptionId := p.env.GetSubscriptionId()
+	scope := azure.SubscriptionRID(subscriptionId)
+	missing, err := p.azCli.MissingPermissions(ctx, subscriptionId, scope, actions)
+	if err != nil {
+		log.Printf("skipping the permissions check: %v", err)
+		return nil
+	}
+
+	if len(missing) > 0 {
+		return &azcli.MissingPermissionsError{Scope: scope, Actions: missing}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateActions(t *testing.T) {
+	actions, err := templateActions(azure.RawArmTemplate(`{"resources": [{"type": "Microsoft.Web/sites"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{azcli.DeploymentsWriteAction}, actions)
+
+	// Role assignments of modules are deployed by the same principal
+	actions, err = templateActions(azure.RawArmTemplate(`{
+		"resources": {
+			"roles": {
+				"type": "Microsoft.Resources/deployments",
+				"properties": {
+					"template": {
+						"resources": [{ "type": "Microsoft.Authorization/roleAssignments" }]
+					}
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{azcli.DeploymentsWriteAction, azcli.RoleAssignmentsWriteAction}, actions)
+}
+
+func TestEnsurePermissions(t *testing.T) {
+	const template = `{"resources": [{"type": "Microsoft.Authorization/roleAssignments"}]}`
+
+	t.Run("Allowed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		preparePermissionsMocks(mockContext, "*")
+
+		provider := createBicepProvider(t, mockContext)
+		require.NoError(t, provider.ensurePermissions(*mockContext.Context, azure.RawArmTemplate(template)))
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		// Contributor allows all the actions but the ones of Microsoft.Authorization
+		preparePermissionsMocks(mockContext, "*", "Microsoft.Authorization/*/Write")
+
+		provider := createBicepProvider(t, mockContext)
+		err := provider.ensurePermissions(*mockContext.Context, azure.RawArmTemplate(template))
+
+		var permissionsErr *azcli.MissingPermissionsError
+		require.ErrorAs(t, err, &permissionsErr)
+		require.Equal(t, "/subscriptions/SUBSCRIPTION_ID", permissionsErr.Scope)
+		require.Equal(t, []string{azcli.RoleAssignmentsWriteAction}, permissionsErr.Actions)
+	})
+
+	t.Run("Unreadable", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Authorization/permissions")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusForbidden)
+		})
+
+		provider := createBicepProvider(t, mockContext)
+		require.NoError(t, provider.ensurePermissions(*mockContext.Context, azure.RawArmTemplate(template)))
+	})
+}
+
+// preparePermissionsMocks mocks the permissions of the signed-in principal in the subscription, allowing the action
+// and excluding the notActions.
+func preparePermissionsMocks(mockContext *mocks.MockContext, action string, notActions ...string) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			request.URL.Path == "/subscriptions/SUBSCRIPTION_ID/providers/Microsoft.Authorization/permissions"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []any{
+				map[string]any{"actions": []string{action}, "notActions": notActions},
+			},
+		})
+	})
+}
@@ -0,0 +1,604 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	resourceTypeVirtualMachine         = "Microsoft.Compute/virtualMachines"
+	resourceTypeVirtualMachineScaleSet = "Microsoft.Compute/virtualMachineScaleSets"
+)
+
+// The usage of Microsoft.App counting the Container Apps environments of a region
+const managedEnvironmentCountUsage = "ManagedEnvironmentCount"
+
+// The usage of Microsoft.Compute counting all the cores of a region
+const totalCoresUsage = "cores"
+
+// The template parameter receiving the location of the environment
+const locationParameterName = "location"
+
+// Matches template expressions which are a single parameter reference, like [parameters('location')]
+var parameterReferenceRegex = regexp.MustCompile(`^\[parameters\('([^']+)'\)\]$`)
+
+// The pricing tiers of App Service plans, by the prefix of their sku names
+var appServiceTiers = []struct {
+	prefix string
+	suffix string
+	tier   string
+}{
+	{prefix: "F", tier: "Free"},
+	{prefix: "D", tier: "Shared"},
+	{prefix: "B", tier: "Basic"},
+	{prefix: "S", tier: "Standard"},
+	{prefix: "P", suffix: "v2", tier: "PremiumV2"},
+	{prefix: "P", suffix: "v3", tier: "PremiumV3"},
+	{prefix: "I", suffix: "v2", tier: "IsolatedV2"},
+	{prefix: "Y", tier: "Dynamic"},
+	{prefix: "EP", tier: "ElasticPremium"},
+}
+
+// quotaRequirement is a resource of a template which counts against a regional quota, or whose sku is only offered in
+// some regions.
+type quotaRequirement struct {
+	ResourceType string
+	Location     string
+	// The size of virtual machines, or the pricing tier of App Service plans
+	Sku string
+	// The number of instances, like the nodes of an AKS agent pool
+	Count int64
+}
+
+// templateScope resolves the values of the resources of a template, which are only known before the deployment when
+// they're literals or references to parameters.
+type templateScope struct {
+	parameters map[string]any
+	location   string
+}
+
+func (s templateScope) resolve(value any) (any, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return value, value != nil
+	}
+
+	// [[ escapes literal values starting with [
+	if strings.HasPrefix(str, "[[") {
+		return str[1:], true
+	}
+
+	if !strings.HasPrefix(str, "[") {
+		return str, true
+	}
+
+	// Resources deployed to the resource group of the environment share its location
+	if str == "[resourceGroup().location]" || str == "[deployment().location]" {
+		return s.location, s.location != ""
+	}
+
+	if match := parameterReferenceRegex.FindStringSubmatch(str); match != nil {
+		parameterValue, has := s.parameters[match[1]]
+		return parameterValue, has && parameterValue != nil
+	}
+
+	return nil, false
+}
+
+func (s templateScope) resolveString(value any) (string, bool) {
+	resolved, ok := s.resolve(value)
+	if !ok {
+		return "", false
+	}
+
+	str, ok := resolved.(string)
+	return str, ok
+}
+
+func (s templateScope) resolveInt(value any, defaultValue int64) int64 {
+	resolved, ok := s.resolve(value)
+	if !ok {
+		return defaultValue
+	}
+
+	switch number := resolved.(type) {
+	case float64:
+		return int64(number)
+	case int64:
+		return number
+	case int:
+		return int64(number)
+	default:
+		return defaultValue
+	}
+}
+
+// collectQuotaRequirements finds the resources of a compiled template which count against regional quotas, including
+// the resources of its modules. Resources whose location or sku is computed during the deployment are skipped.
+func collectQuotaRequirements(
+	rawTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+	defaultLocation string,
+) ([]quotaRequirement, error) {
+	values := make(map[string]any, len(parameters))
+	for name, parameter := range parameters {
+		values[name] = parameter.Value
+	}
+
+	var template map[string]any
+	if err := json.Unmarshal(rawTemplate, &template); err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+
+	requirements := []quotaRequirement{}
+	collectTemplateRequirements(template, values, defaultLocation, &requirements)
+	return requirements, nil
+}
+
+func collectTemplateRequirements(
+	template map[string]any,
+	values map[string]any,
+	defaultLocation string,
+	requirements *[]quotaRequirement,
+) {
+	scope := templateScope{parameters: map[string]any{}, location: defaultLocation}
+	if definitions, ok := template["parameters"].(map[string]any); ok {
+		for name, definition := range definitions {
+			if value, has := values[name]; has {
+				scope.parameters[name] = value
+				continue
+			}
+
+			if definition, ok := definition.(map[string]any); ok {
+				if value, ok := scope.resolve(definition["defaultValue"]); ok {
+					scope.parameters[name] = value
+				}
+			}
+		}
+	}
+
+	collectResources(template, scope, requirements)
+}
+
+func collectResources(template map[string]any, scope templateScope, requirements *[]quotaRequirement) {
+	// Templates list their resources, or map them by symbolic name since language version 2.0
+	var resources []any
+	switch value := template["resources"].(type) {
+	case []any:
+		resources = value
+	case map[string]any:
+		resources = maps.Values(value)
+	}
+
+	for _, value := range resources {
+		resource, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		collectResourceRequirements(scope, resource, requirements)
+	}
+}
+
+func collectResourceRequirements(scope templateScope, resource map[string]any, requirements *[]quotaRequirement) {
+	resourceType, _ := resource["type"].(string)
+	properties, _ := resource["properties"].(map[string]any)
+	sku, _ := resource["sku"].(map[string]any)
+
+	location := scope.location
+	if value, has := resource["location"]; has {
+		resolved, ok := scope.resolveString(value)
+		if !ok {
+			log.Printf("skipping quota check of a %s resource, its location is computed during the deployment", resourceType)
+			return
+		}
+
+		location = resolved
+	}
+
+	count := int64(1)
+	if copyLoop, ok := resource["copy"].(map[string]any); ok {
+		count = scope.resolveInt(copyLoop["count"], 1)
+	}
+
+	add := func(skuValue any, instances int64) {
+		skuName, ok := scope.resolveString(skuValue)
+		if !ok || skuName == "" {
+			return
+		}
+
+		*requirements = append(*requirements, quotaRequirement{
+			ResourceType: resourceType,
+			Location:     location,
+			Sku:          skuName,
+			Count:        count * instances,
+		})
+	}
+
+	switch resourceType {
+	case string(infra.AzureResourceTypeDeployment):
+		nested, ok := properties["template"].(map[string]any)
+		if !ok {
+			return
+		}
+
+		// Modules compiled by Bicep evaluate their expressions in the scope of the nested template, which receives the
+		// values of the parameters of the deployment. Other nested templates use the parameters of their parent.
+		options, _ := properties["expressionEvaluationOptions"].(map[string]any)
+		if options["scope"] != "inner" {
+			collectResources(nested, templateScope{parameters: scope.parameters, location: location}, requirements)
+			return
+		}
+
+		nestedValues := map[string]any{}
+		if parameters, ok := properties["parameters"].(map[string]any); ok {
+			for name, parameter := range parameters {
+				if parameter, ok := parameter.(map[string]any); ok {
+					if value, ok := scope.resolve(parameter["value"]); ok {
+						nestedValues[name] = value
+					}
+				}
+			}
+		}
+
+		collectTemplateRequirements(nested, nestedValues, location, requirements)
+	case resourceTypeVirtualMachine:
+		if hardwareProfile, ok := properties["hardwareProfile"].(map[string]any); ok {
+			add(hardwareProfile["vmSize"], 1)
+		}
+	case resourceTypeVirtualMachineScaleSet:
+		add(sku["name"], scope.resolveInt(sku["capacity"], 1))
+	case string(infra.AzureResourceTypeManagedCluster):
+		agentPools, _ := properties["agentPoolProfiles"].([]any)
+		for _, value := range agentPools {
+			if agentPool, ok := value.(map[string]any); ok {
+				add(agentPool["vmSize"], scope.resolveInt(agentPool["count"], 1))
+			}
+		}
+	case string(infra.AzureResourceTypeAgentPool):
+		add(properties["vmSize"], scope.resolveInt(properties["count"], 1))
+	case string(infra.AzureResourceTypeServicePlan):
+		if tier, ok := scope.resolveString(sku["tier"]); ok && tier != "" {
+			add(tier, 1)
+		} else if name, ok := scope.resolveString(sku["name"]); ok {
+			add(appServiceTier(name), 1)
+		}
+	case string(infra.AzureResourceTypeContainerAppEnvironment):
+		*requirements = append(*requirements, quotaRequirement{
+			ResourceType: resourceType,
+			Location:     location,
+			Count:        count,
+		})
+	}
+}
+
+// normalizeLocation returns the name of a location from its name or display name, like eastus for East US.
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+// appServiceTier returns the pricing tier of an App Service plan sku, like PremiumV3 for P1v3.
+func appServiceTier(skuName string) string {
+	name := strings.ToUpper(skuName)
+	tier := ""
+	for _, candidate := range appServiceTiers {
+		if strings.HasPrefix(name, candidate.prefix) && strings.HasSuffix(name, strings.ToUpper(candidate.suffix)) {
+			tier = candidate.tier
+		}
+	}
+
+	return tier
+}
+
+// existingResources returns the resources already provisioned in the resource group of the environment, and whether
+// the environment was provisioned. Environments are provisioned once their resource group is known.
+func (p *BicepProvider) existingResources(ctx context.Context) ([]azcli.AzCliResource, bool) {
+	resourceGroup := p.env.Getenv(environment.ResourceGroupEnvVarName)
+	if resourceGroup == "" {
+		return nil, false
+	}
+
+	resources, err := p.azCli.ListResourceGroupResources(ctx, p.env.GetSubscriptionId(), resourceGroup, nil)
+	if err != nil {
+		log.Printf("listing the resources of %s for the quota check: %v", resourceGroup, err)
+		return nil, true
+	}
+
+	return resources, true
+}
+
+// removeExisting removes the requirements of the resources which already exist, which are part of the current usage
+// of the quotas. Resources are matched by type and location, the names of the resources of a template usually being
+// computed during the deployment.
+func removeExisting(requirements []quotaRequirement, existing []azcli.AzCliResource) []quotaRequirement {
+	resourceKey := func(resourceType string, location string) string {
+		return strings.ToLower(resourceType) + "|" + normalizeLocation(location)
+	}
+
+	counts := map[string]int{}
+	for _, resource := range existing {
+		counts[resourceKey(resource.Type, resource.Location)]++
+	}
+
+	remaining := []quotaRequirement{}
+	for _, requirement := range requirements {
+		switch requirement.ResourceType {
+		// The agent pools are part of their cluster, which has a requirement for each of its pools
+		case string(infra.AzureResourceTypeManagedCluster), string(infra.AzureResourceTypeAgentPool):
+			key := resourceKey(string(infra.AzureResourceTypeManagedCluster), requirement.Location)
+			if counts[key] > 0 {
+				continue
+			}
+		default:
+			key := resourceKey(requirement.ResourceType, requirement.Location)
+			if counts[key] >= int(requirement.Count) {
+				counts[key] -= int(requirement.Count)
+				continue
+			}
+
+			requirement.Count -= int64(counts[key])
+			counts[key] = 0
+		}
+
+		remaining = append(remaining, requirement)
+	}
+
+	return remaining
+}
+
+// checkQuota returns the problems which would fail the deployment of the resources, like a quota of cores exceeded by
+// the nodes of a cluster, or a sku which isn't offered in the region. Quotas which can't be read are skipped.
+func (p *BicepProvider) checkQuota(ctx context.Context, requirements []quotaRequirement) []string {
+	subscriptionId := p.env.GetSubscriptionId()
+	problems := []string{}
+
+	byLocation := map[string][]quotaRequirement{}
+	for _, requirement := range requirements {
+		location := normalizeLocation(requirement.Location)
+		byLocation[location] = append(byLocation[location], requirement)
+	}
+
+	locations := maps.Keys(byLocation)
+	slices.Sort(locations)
+
+	for _, location := range locations {
+		cores := map[string]int64{}
+		managedEnvironments := int64(0)
+		appServiceTiers := []string{}
+
+		for _, requirement := range byLocation[location] {
+			switch requirement.ResourceType {
+			case string(infra.AzureResourceTypeContainerAppEnvironment):
+				managedEnvironments += requirement.Count
+			case string(infra.AzureResourceTypeServicePlan):
+				if !slices.Contains(appServiceTiers, requirement.Sku) {
+					appServiceTiers = append(appServiceTiers, requirement.Sku)
+				}
+			default:
+				cores[requirement.Sku] += requirement.Count
+			}
+		}
+
+		if len(cores) > 0 {
+			problems = append(problems, p.checkComputeQuota(ctx, subscriptionId, location, cores)...)
+		}
+
+		if managedEnvironments > 0 {
+			usages, err := p.azCli.ListLocationUsages(ctx, subscriptionId, "Microsoft.App", location)
+			if err != nil {
+				log.Printf("skipping quota check of Container Apps environments: %v", err)
+			} else {
+				problems = append(problems, checkUsage(usages, managedEnvironmentCountUsage, managedEnvironments, location)...)
+			}
+		}
+
+		for _, tier := range appServiceTiers {
+			regions, err := p.azCli.ListAppServiceRegions(ctx, subscriptionId, tier)
+			if err != nil {
+				log.Printf("skipping availability check of App Service plans: %v", err)
+				continue
+			}
+
+			if slices.IndexFunc(regions, func(region string) bool {
+				return normalizeLocation(region) == location
+			}) == -1 {
+				problems = append(problems, fmt.Sprintf("App Service plans of the %s tier aren't offered in %s", tier, location))
+			}
+		}
+	}
+
+	return problems
+}
+
+// checkComputeQuota checks the sizes of the virtual machines are offered in the region, and their cores fit in the
+// quotas of their families and of the region.
+func (p *BicepProvider) checkComputeQuota(
+	ctx context.Context,
+	subscriptionId string,
+	location string,
+	instances map[string]int64,
+) []string {
+	skus, err := p.azCli.ListComputeSkus(ctx, subscriptionId, location)
+	if err != nil {
+		log.Printf("skipping quota check of virtual machines: %v", err)
+		return nil
+	}
+
+	usages, err := p.azCli.ListLocationUsages(ctx, subscriptionId, "Microsoft.Compute", location)
+	if err != nil {
+		log.Printf("skipping quota check of virtual machines: %v", err)
+		return nil
+	}
+
+	problems := []string{}
+	familyCores := map[string]int64{}
+	totalCores := int64(0)
+
+	sizes := maps.Keys(instances)
+	slices.Sort(sizes)
+
+	for _, size := range sizes {
+		index := slices.IndexFunc(skus, func(sku *azsdk.ComputeSku) bool {
+			return strings.EqualFold(sku.Name, size)
+		})
+		if index == -1 || skus[index].Restricted {
+			problems = append(problems, fmt.Sprintf("the virtual machine size %s isn't offered in %s", size, location))
+			continue
+		}
+
+		sku := skus[index]
+		familyCores[sku.Family] += sku.VCpus * instances[size]
+		totalCores += sku.VCpus * instances[size]
+	}
+
+	families := maps.Keys(familyCores)
+	slices.Sort(families)
+	for _, family := range families {
+		problems = append(problems, checkUsage(usages, family, familyCores[family], location)...)
+	}
+
+	if totalCores > 0 {
+		problems = append(problems, checkUsage(usages, totalCoresUsage, totalCores, location)...)
+	}
+
+	return problems
+}
+
+// checkUsage returns a problem when the required amount doesn't fit in the remaining quota of the usage.
+func checkUsage(usages []*azsdk.QuotaUsage, name string, required int64, location string) []string {
+	index := slices.IndexFunc(usages, func(usage *azsdk.QuotaUsage) bool {
+		return strings.EqualFold(usage.Name, name)
+	})
+	if index == -1 {
+		return nil
+	}
+
+	usage := usages[index]
+	available := usage.Limit - usage.CurrentValue
+	if required <= available {
+		return nil
+	}
+
+	if available < 0 {
+		available = 0
+	}
+
+	displayName := usage.DisplayName
+	if displayName == "" {
+		displayName = usage.Name
+	}
+
+	return []string{fmt.Sprintf(
+		"%s in %s: %d required, %d of %d available",
+		displayName,
+		location,
+		required,
+		available,
+		usage.Limit,
+	)}
+}
+
+// ensureQuota warns about the resources of the template which would fail to deploy because of quotas or regional
+// availability, before starting a deployment which would only fail after a while. The user can continue, pick another
+// location when the environment wasn't provisioned yet, or cancel the provisioning.
+func (p *BicepProvider) ensureQuota(
+	ctx context.Context,
+	rawTemplate azure.RawArmTemplate,
+	parameters azure.ArmParameters,
+) (azure.ArmParameters, error) {
+	for {
+		location := p.env.GetLocation()
+		requirements, err := collectQuotaRequirements(rawTemplate, parameters, location)
+		if err != nil {
+			log.Printf("skipping quota checks: %v", err)
+			return parameters, nil
+		}
+
+		if len(requirements) == 0 {
+			return parameters, nil
+		}
+
+		existing, provisioned := p.existingResources(ctx)
+		problems := p.checkQuota(ctx, removeExisting(requirements, existing))
+		if len(problems) == 0 {
+			return parameters, nil
+		}
+
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The deployment is likely to fail because of quotas or regional availability:"))
+		for _, problem := range problems {
+			p.console.Message(ctx, output.WithWarningFormat("  - %s", problem))
+		}
+
+		continueOption := fmt.Sprintf("Continue provisioning in %s", location)
+		changeLocationOption := "Choose another location"
+		cancelOption := "Cancel provisioning"
+
+		// The resources of a provisioned environment can't move to another location
+		options := []string{continueOption, changeLocationOption, cancelOption}
+		if provisioned {
+			options = []string{continueOption, cancelOption}
+		}
+
+		choice, err := p.console.Select(ctx, input.ConsoleOptions{
+			Message:      "How do you want to proceed?",
+			Options:      options,
+			DefaultValue: options[0],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("prompting to continue provisioning: %w", err)
+		}
+
+		switch options[choice] {
+		case continueOption:
+			return parameters, nil
+		case cancelOption:
+			return nil, errors.New("provisioning canceled, the deployment would fail because of quotas")
+		}
+
+		newLocation, err := p.prompters.Location(
+			ctx,
+			p.env.GetSubscriptionId(),
+			"Select an Azure location to provision to:",
+			func(loc account.Location) bool { return loc.Name != location },
+		)
+		if err != nil {
+			return nil, fmt.Errorf("prompting for location: %w", err)
+		}
+
+		// Only the location parameter follows the environment, other parameters may name the same region on purpose
+		for name, parameter := range parameters {
+			if !strings.EqualFold(name, locationParameterName) {
+				continue
+			}
+
+			if value, ok := parameter.Value.(string); ok && strings.EqualFold(value, location) {
+				parameters[name] = azure.ArmParameterValue{Value: newLocation}
+			}
+		}
+
+		p.env.SetLocation(newLocation)
+		if err := p.env.Save(); err != nil {
+			return nil, fmt.Errorf("saving location: %w", err)
+		}
+	}
+}
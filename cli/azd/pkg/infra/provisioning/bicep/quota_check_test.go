@@ -0,0 +1,281 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
+	"github.com/stretchr/testify/require"
+)
+
+const quotaTemplateJson = `{
+	"$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+	"contentVersion": "1.0.0.0",
+	"parameters": {
+		"location": { "type": "string" },
+		"planSku": { "type": "string", "defaultValue": "P1v3" }
+	},
+	"resources": [
+		{
+			"type": "Microsoft.Resources/resourceGroups",
+			"name": "rg-test",
+			"location": "[parameters('location')]"
+		},
+		{
+			"type": "Microsoft.Resources/deployments",
+			"name": "resources",
+			"resourceGroup": "rg-test",
+			"properties": {
+				"expressionEvaluationOptions": { "scope": "inner" },
+				"parameters": {
+					"location": { "value": "[parameters('location')]" },
+					"planSku": { "value": "[parameters('planSku')]" },
+					"nodeCount": { "value": 3 }
+				},
+				"template": {
+					"parameters": {
+						"location": { "type": "string" },
+						"planSku": { "type": "string" },
+						"nodeCount": { "type": "int" }
+					},
+					"resources": [
+						{
+							"type": "Microsoft.ContainerService/managedClusters",
+							"name": "aks-test",
+							"location": "[parameters('location')]",
+							"properties": {
+								"agentPoolProfiles": [
+									{ "name": "system", "vmSize": "Standard_D4s_v3", "count": "[parameters('nodeCount')]" }
+								]
+							}
+						},
+						{
+							"type": "Microsoft.Web/serverfarms",
+							"name": "plan-test",
+							"location": "[resourceGroup().location]",
+							"sku": { "name": "[parameters('planSku')]" }
+						},
+						{
+							"type": "Microsoft.App/managedEnvironments",
+							"name": "cae-test",
+							"location": "[parameters('location')]"
+						},
+						{
+							"type": "Microsoft.Compute/virtualMachines",
+							"name": "vm-test",
+							"location": "[reference('other').location]",
+							"properties": { "hardwareProfile": { "vmSize": "Standard_D2s_v3" } }
+						}
+					]
+				}
+			}
+		}
+	]
+}`
+
+func TestCollectQuotaRequirements(t *testing.T) {
+	requirements, err := collectQuotaRequirements(
+		azure.RawArmTemplate(quotaTemplateJson),
+		azure.ArmParameters{"location": {Value: "westus2"}},
+		"westus2",
+	)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []quotaRequirement{
+		{ResourceType: "Microsoft.ContainerService/managedClusters", Location: "westus2", Sku: "Standard_D4s_v3", Count: 3},
+		{ResourceType: "Microsoft.Web/serverfarms", Location: "westus2", Sku: "PremiumV3", Count: 1},
+		{ResourceType: "Microsoft.App/managedEnvironments", Location: "westus2", Count: 1},
+	}, requirements)
+}
+
+func TestAppServiceTier(t *testing.T) {
+	require.Equal(t, "Basic", appServiceTier("B1"))
+	require.Equal(t, "PremiumV3", appServiceTier("P1v3"))
+	require.Equal(t, "PremiumV2", appServiceTier("p2V2"))
+	require.Equal(t, "ElasticPremium", appServiceTier("EP1"))
+	require.Equal(t, "", appServiceTier("Unknown"))
+}
+
+func TestEnsureQuota(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareQuotaMocks(mockContext)
+
+	provider := createBicepProvider(t, mockContext)
+	provider.prompters.Location = func(_ context.Context, _, _ string, _ func(loc account.Location) bool) (string, error) {
+		return "eastus", nil
+	}
+
+	selected := []string{}
+	mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+		return options.Message == "How do you want to proceed?"
+	}).RespondFn(func(options input.ConsoleOptions) (any, error) {
+		selected = append(selected, options.Options[0])
+		return 1, nil
+	})
+
+	parameters, err := provider.ensureQuota(
+		*mockContext.Context,
+		azure.RawArmTemplate(quotaTemplateJson),
+		azure.ArmParameters{"location": {Value: "westus2"}, "replicaLocation": {Value: "westus2"}},
+	)
+	require.NoError(t, err)
+
+	// Only the first location lacks quota, the nodes need 12 cores and 6 are available
+	require.Equal(t, []string{"Continue provisioning in westus2"}, selected)
+	consoleOutput := strings.Join(mockContext.Console.Output(), "\n")
+	require.Contains(t, consoleOutput, "Standard DSv3 Family vCPUs in westus2: 12 required, 6 of 10 available")
+	require.Contains(t, consoleOutput, "App Service plans of the PremiumV3 tier aren't offered in westus2")
+
+	require.Equal(t, "eastus", parameters["location"].Value)
+	require.Equal(t, "westus2", parameters["replicaLocation"].Value)
+	require.Equal(t, "eastus", provider.env.GetLocation())
+}
+
+func TestEnsureQuotaProvisioned(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareQuotaMocks(mockContext)
+
+	// The cluster exists, its nodes are part of the current usage
+	mockarmresources.AddAzResourceListMock(
+		mockContext.HttpClient,
+		convert.RefOf("rg-test"),
+		[]*armresources.GenericResourceExpanded{
+			{
+				ID:       convert.RefOf("aks-test"),
+				Name:     convert.RefOf("aks-test"),
+				Type:     convert.RefOf(string(infra.AzureResourceTypeManagedCluster)),
+				Location: convert.RefOf("westus2"),
+			},
+		},
+	)
+
+	provider := createBicepProvider(t, mockContext)
+	provider.env.Values[environment.ResourceGroupEnvVarName] = "rg-test"
+
+	options := []string{}
+	mockContext.Console.WhenSelect(func(options input.ConsoleOptions) bool {
+		return options.Message == "How do you want to proceed?"
+	}).RespondFn(func(selectOptions input.ConsoleOptions) (any, error) {
+		options = selectOptions.Options
+		return 0, nil
+	})
+
+	_, err := provider.ensureQuota(
+		*mockContext.Context,
+		azure.RawArmTemplate(quotaTemplateJson),
+		azure.ArmParameters{"location": {Value: "westus2"}},
+	)
+	require.NoError(t, err)
+
+	// The provisioned environment can't move to another location
+	require.Equal(t, []string{"Continue provisioning in westus2", "Cancel provisioning"}, options)
+	consoleOutput := strings.Join(mockContext.Console.Output(), "\n")
+	require.NotContains(t, consoleOutput, "Standard DSv3 Family vCPUs")
+	require.Contains(t, consoleOutput, "App Service plans of the PremiumV3 tier aren't offered in westus2")
+}
+
+func TestRemoveExisting(t *testing.T) {
+	requirements := []quotaRequirement{
+		{ResourceType: "Microsoft.App/managedEnvironments", Location: "westus2", Count: 3},
+		{ResourceType: "Microsoft.ContainerService/managedClusters", Location: "westus2", Sku: "Standard_D4s_v3", Count: 3},
+		{ResourceType: "Microsoft.ContainerService/managedClusters", Location: "eastus", Sku: "Standard_D4s_v3", Count: 3},
+	}
+
+	remaining := removeExisting(requirements, []azcli.AzCliResource{
+		{Type: "Microsoft.App/managedEnvironments", Location: "West US 2"},
+		{Type: "Microsoft.ContainerService/managedClusters", Location: "westus2"},
+	})
+
+	require.Equal(t, []quotaRequirement{
+		{ResourceType: "Microsoft.App/managedEnvironments", Location: "westus2", Count: 2},
+		{ResourceType: "Microsoft.ContainerService/managedClusters", Location: "eastus", Sku: "Standard_D4s_v3", Count: 3},
+	}, remaining)
+}
+
+func prepareQuotaMocks(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Compute/skus")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"resourceType": "virtualMachines",
+					"name":         "Standard_D4s_v3",
+					"family":       "standardDSv3Family",
+					"capabilities": []map[string]any{{"name": "vCPUs", "value": "4"}},
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Compute/locations/westus2/usages")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"name":         map[string]any{"value": "standardDSv3Family", "localizedValue": "Standard DSv3 Family vCPUs"},
+					"currentValue": 4,
+					"limit":        10,
+				},
+				{
+					"name":         map[string]any{"value": "cores", "localizedValue": "Total Regional vCPUs"},
+					"currentValue": 4,
+					"limit":        100,
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Compute/locations/eastus/usages")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"name":         map[string]any{"value": "standardDSv3Family", "localizedValue": "Standard DSv3 Family vCPUs"},
+					"currentValue": 0,
+					"limit":        100,
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.Contains(request.URL.Path, "/providers/Microsoft.App/locations/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{
+					"name":         map[string]any{"value": "ManagedEnvironmentCount"},
+					"currentValue": 1,
+					"limit":        15,
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/geoRegions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []map[string]any{
+				{"name": "East US"},
+				{"name": "West Europe"},
+			},
+		})
+	})
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// The resource providers registered in every subscription
+var alwaysRegisteredProviders = []string{"microsoft.resources", "microsoft.authorization"}
+
+// How often, and how long, the registration of the resource providers is polled
+var (
+	providerRegistrationPollInterval = 5 * time.Second
+	providerRegistrationTimeout      = 10 * time.Minute
+)
+
+// templateProviders returns the sorted namespaces of the resource providers of the resources of a compiled template,
+// including the resources of its modules, like Microsoft.App for Microsoft.App/containerApps.
+func templateProviders(rawTemplate azure.RawArmTemplate) ([]string, error) {
+	var template map[string]any
+	if err := json.Unmarshal(rawTemplate, &template); err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+
+	namespaces := map[string]string{}
+	collectProviders(template, namespaces)
+
+	providers := maps.Values(namespaces)
+	slices.Sort(providers)
+	return providers, nil
+}
+
+// collectProviders adds the namespaces of the resources of the template to namespaces, by their lower case name.
+func collectProviders(template map[string]any, namespaces map[string]string) {
+	forEachTemplateResource(template, func(resourceType string) {
+		namespace, _, found := strings.Cut(resourceType, "/")
+		if !found || slices.Contains(alwaysRegisteredProviders, strings.ToLower(namespace)) {
+			return
+		}
+
+		namespaces[strings.ToLower(namespace)] = namespace
+	})
+}
+
+// forEachTemplateResource calls fn with the type of each resource of the template, including the resources of its
+// modules, like Microsoft.App/containerApps.
+func forEachTemplateResource(template map[string]any, fn func(resourceType string)) {
+	// Templates list their resources, or map them by symbolic name since language version 2.0
+	var resources []any
+	switch value := template["resources"].(type) {
+	case []any:
+		resources = value
+	case map[string]any:
+		resources = maps.Values(value)
+	}
+
+	for _, value := range resources {
+		resource, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		resourceType, _ := resource["type"].(string)
+		if strings.EqualFold(resourceType, string(infra.AzureResourceTypeDeployment)) {
+			properties, _ := resource["properties"].(map[string]any)
+			if nested, ok := properties["template"].(map[string]any); ok {
+				forEachTemplateResource(nested, fn)
+			}
+		}
+
+		fn(resourceType)
+	}
+}
+
+// ensureResourceProviders registers the resource providers of the template which aren't registered in the subscription
+// of the environment, after confirmation unless the options register them, and waits for their registration. Deploying
+// the resources of an unregistered provider would fail with a MissingSubscriptionRegistration error. The states which
+// can't be read are skipped, the deployment reports the providers it can't use.
+func (p *BicepProvider) ensureResourceProviders(ctx context.Context, rawTemplate azure.RawArmTemplate) error {
+	providers, err := templateProviders(rawTemplate)
+	if err != nil {
+		log.Printf("skipping the registration check of the resource providers: %v", err)
+		return nil
+	}
+
+	subscriptionId := p.env.GetSubscriptionId()
+	unregistered := []string{}
+	pending := []string{}
+	for _, namespace := range providers {
+		state, err := p.azCli.GetResourceProviderState(ctx, subscriptionId, namespace)
+		if err != nil {
+			log.Printf("skipping the registration check of %s: %v", namespace, err)
+			continue
+		}
+
+		switch state {
+		case azcli.ResourceProviderRegistered:
+		case "Registering":
+			pending = append(pending, namespace)
+		default:
+			unregistered = append(unregistered, namespace)
+		}
+	}
+
+	if len(unregistered) > 0 {
+		if !p.options.RegisterProviders {
+			p.console.Message(ctx, output.WithWarningFormat(
+				"WARNING: The template uses resource providers which aren't registered in the subscription %s: %s",
+				subscriptionId,
+				strings.Join(unregistered, ", "),
+			))
+
+			register, err := p.console.Confirm(ctx, input.ConsoleOptions{
+				Message:      "Register the resource providers?",
+				DefaultValue: true,
+			})
+			if err != nil {
+				return fmt.Errorf("prompting to register resource providers: %w", err)
+			}
+
+			if !register {
+				return fmt.Errorf(
+					"the deployment would fail, the resource providers %s aren't registered. Register them with %s",
+					strings.Join(unregistered, ", "),
+					output.WithHighLightFormat("azd provision --register-providers"),
+				)
+			}
+		}
+
+		for _, namespace := range unregistered {
+			if err := p.azCli.RegisterResourceProvider(ctx, subscriptionId, namespace); err != nil {
+				return err
+			}
+		}
+
+		pending = append(pending, unregistered...)
+	}
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("Registering resource providers %s", strings.Join(pending, ", "))
+	p.console.ShowSpinner(ctx, message, input.Step)
+	err = p.waitForProviderRegistration(ctx, subscriptionId, pending)
+	p.console.StopSpinner(ctx, message, input.GetStepResultFormat(err))
+
+	return err
+}
+
+// waitForProviderRegistration polls the state of the resource providers until they're all registered.
+func (p *BicepProvider) waitForProviderRegistration(ctx context.Context, subscriptionId string, providers []string) error {
+	deadline := time.Now().Add(providerRegistrationTimeout)
+	for len(providers) > 0 {
+		remaining := []string{}
+		for _, namespace := range providers {
+			state, err := p.azCli.GetResourceProviderState(ctx, subscriptionId, namespace)
+			if err != nil {
+				return err
+			}
+
+			if state != azcli.ResourceProviderRegistered {
+				remaining = append(remaining, namespace)
+			}
+		}
+
+		providers = remaining
+		if len(providers) == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf(
+				"the resource providers %s are still registering after %s, provision again once they're registered",
+				strings.Join(providers, ", "),
+				providerRegistrationTimeout,
+			)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for resource providers %s: %w", strings.Join(providers, ", "), ctx.Err())
+		case <-time.After(providerRegistrationPollInterval):
+		}
+	}
+
+	return nil
+}
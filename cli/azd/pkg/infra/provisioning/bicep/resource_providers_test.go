@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateProviders(t *testing.T) {
+	providers, err := templateProviders(azure.RawArmTemplate(quotaTemplateJson))
+	require.NoError(t, err)
+	require.Equal(t, []string{"Microsoft.App", "Microsoft.Compute", "Microsoft.ContainerService", "Microsoft.Web"}, providers)
+}
+
+func TestEnsureResourceProviders(t *testing.T) {
+	providerRegistrationPollInterval = time.Millisecond
+	t.Cleanup(func() { providerRegistrationPollInterval = 5 * time.Second })
+
+	const template = `{
+		"resources": {
+			"app": { "type": "Microsoft.App/containerApps", "name": "ca-test" },
+			"logs": { "type": "Microsoft.OperationalInsights/workspaces", "name": "log-test" },
+			"group": { "type": "Microsoft.Resources/resourceGroups", "name": "rg-test" }
+		}
+	}`
+
+	prepareMocks := func(mockContext *mocks.MockContext, registered map[string]bool) *[]string {
+		registrations := []string{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.HasPrefix(request.URL.Path, "/subscriptions/SUBSCRIPTION_ID/providers/")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			namespace := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+			state := "NotRegistered"
+			if registered[namespace] {
+				state = "Registered"
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"namespace":         namespace,
+				"registrationState": state,
+			})
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, "/register")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			namespace := strings.TrimSuffix(request.URL.Path, "/register")
+			namespace = namespace[strings.LastIndex(namespace, "/")+1:]
+			registrations = append(registrations, namespace)
+			registered[namespace] = true
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"namespace":         namespace,
+				"registrationState": "Registering",
+			})
+		})
+
+		return &registrations
+	}
+
+	t.Run("Confirmed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registrations := prepareMocks(mockContext, map[string]bool{"Microsoft.App": true})
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return options.Message == "Register the resource providers?"
+		}).Respond(true)
+
+		provider := createBicepProvider(t, mockContext)
+		require.NoError(t, provider.ensureResourceProviders(*mockContext.Context, azure.RawArmTemplate(template)))
+		require.Equal(t, []string{"Microsoft.OperationalInsights"}, *registrations)
+	})
+
+	t.Run("Declined", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registrations := prepareMocks(mockContext, map[string]bool{})
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return true
+		}).Respond(false)
+
+		provider := createBicepProvider(t, mockContext)
+		err := provider.ensureResourceProviders(*mockContext.Context, azure.RawArmTemplate(template))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Microsoft.App, Microsoft.OperationalInsights aren't registered")
+		require.Empty(t, *registrations)
+	})
+
+	t.Run("RegisterProviders", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registrations := prepareMocks(mockContext, map[string]bool{})
+
+		provider := createBicepProvider(t, mockContext)
+		provider.options.RegisterProviders = true
+		require.NoError(t, provider.ensureResourceProviders(*mockContext.Context, azure.RawArmTemplate(template)))
+		require.Equal(t, []string{"Microsoft.App", "Microsoft.OperationalInsights"}, *registrations)
+	})
+}
@@ -0,0 +1,310 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// LayerOptions is a layer of the infrastructure of a project, provisioned with its own provider. The layers of a project
+// are provisioned in order, the outputs of a layer are saved in the environment before the next layer is planned, so the
+// parameters of the next layers can reference them, like ${SQL_SERVER_ID} in main.parameters.json or main.tfvars.json.
+type LayerOptions struct {
+	// The name of the layer, shown in the progress of the provisioning
+	Name     string       `yaml:"name"`
+	Provider ProviderKind `yaml:"provider"`
+	// The directory of the templates of the layer, relative to the project
+	Path   string `yaml:"path"`
+	Module string `yaml:"module"`
+}
+
+// validateLayers checks the layers have a name and a path, and that at most one of them is provisioned with Bicep or
+// ARM: the deployments of an environment are named after the environment, the deployments of two layers would replace
+// each other.
+func validateLayers(layers []LayerOptions) error {
+	names := map[string]bool{}
+	armLayer := ""
+	for _, layer := range layers {
+		if layer.Name == "" {
+			return errors.New("the layers of infra must have a name")
+		}
+
+		if names[layer.Name] {
+			return fmt.Errorf("infra has several layers named '%s'", layer.Name)
+		}
+		names[layer.Name] = true
+
+		if layer.Path == "" {
+			return fmt.Errorf("layer '%s' of infra has no path", layer.Name)
+		}
+
+		if layer.Provider == "" || layer.Provider == Bicep || layer.Provider == Arm {
+			if armLayer != "" {
+				return fmt.Errorf(
+					"layers '%s' and '%s' of infra are both provisioned with Bicep, at most one layer can use Bicep",
+					armLayer,
+					layer.Name,
+				)
+			}
+			armLayer = layer.Name
+		}
+	}
+
+	return nil
+}
+
+// providerLayer is a layer of the infrastructure and its provider.
+type providerLayer struct {
+	name     string
+	provider Provider
+}
+
+// layeredProvider provisions the layers of the infrastructure of a project in order, each with the provider of the
+// layer, for projects mixing Bicep and Terraform. The layers are destroyed in the reverse order.
+type layeredProvider struct {
+	env    *environment.Environment
+	layers []providerLayer
+}
+
+// layeredPlan is the plan of the first layer. The next layers are planned when they're deployed, once the outputs of the
+// previous layers are in the environment.
+type layeredPlan struct {
+	firstLayerPlan *DeploymentPlan
+}
+
+func newLayeredProvider(env *environment.Environment, layers []providerLayer) *layeredProvider {
+	return &layeredProvider{
+		env:    env,
+		layers: layers,
+	}
+}
+
+func (p *layeredProvider) Name() string {
+	return "Layered"
+}
+
+// RequiredExternalTools returns the tools required by the providers of the layers, without duplicates.
+func (p *layeredProvider) RequiredExternalTools() []tools.ExternalTool {
+	names := map[string]bool{}
+	requiredTools := []tools.ExternalTool{}
+	for _, layer := range p.layers {
+		for _, tool := range layer.provider.RequiredExternalTools() {
+			if !names[tool.Name()] {
+				names[tool.Name()] = true
+				requiredTools = append(requiredTools, tool)
+			}
+		}
+	}
+
+	return requiredTools
+}
+
+func (p *layeredProvider) EnsureConfigured(ctx context.Context) error {
+	for _, layer := range p.layers {
+		if err := layer.provider.EnsureConfigured(ctx); err != nil {
+			return fmt.Errorf("configuring layer %s: %w", layer.name, err)
+		}
+	}
+
+	return nil
+}
+
+// State merges the outputs and the resources of the layers, the outputs of the last layers override the outputs of the
+// first layers with the same name.
+func (p *layeredProvider) State(
+	ctx context.Context,
+	scope infra.Scope,
+) *async.InteractiveTaskWithProgress[*StateResult, *StateProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*StateResult, *StateProgress]) {
+			state := &State{
+				Outputs:   map[string]OutputParameter{},
+				Resources: []Resource{},
+			}
+
+			for _, layer := range p.layers {
+				result, err := awaitLayerTask(layer.provider.State(ctx, scope), func(progress *StateProgress) {
+					asyncContext.SetProgress(&StateProgress{
+						Message:   layerProgressMessage(layer.name, progress.Message),
+						Timestamp: progress.Timestamp,
+					})
+				})
+				if err != nil {
+					asyncContext.SetError(fmt.Errorf("retrieving state of layer %s: %w", layer.name, err))
+					return
+				}
+
+				for name, output := range result.State.Outputs {
+					state.Outputs[name] = output
+				}
+				state.Resources = append(state.Resources, result.State.Resources...)
+			}
+
+			asyncContext.SetResult(&StateResult{State: state})
+		})
+}
+
+// Plan plans the first layer, the next layers are planned by Deploy.
+func (p *layeredProvider) Plan(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*DeploymentPlan, *DeploymentPlanningProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeploymentPlan, *DeploymentPlanningProgress]) {
+			firstLayer := p.layers[0]
+			plan, err := p.planLayer(ctx, firstLayer, func(message string) {
+				asyncContext.SetProgress(&DeploymentPlanningProgress{Message: message, Timestamp: time.Now()})
+			})
+			if err != nil {
+				asyncContext.SetError(err)
+				return
+			}
+
+			asyncContext.SetResult(&DeploymentPlan{
+				Deployment: plan.Deployment,
+				Details:    &layeredPlan{firstLayerPlan: plan},
+			})
+		})
+}
+
+// Deploy deploys the layers in order. The outputs of each layer are saved in the environment before the next layer is
+// planned.
+func (p *layeredProvider) Deploy(
+	ctx context.Context,
+	plan *DeploymentPlan,
+	scope infra.Scope,
+) *async.InteractiveTaskWithProgress[*DeployResult, *DeployProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeployResult, *DeployProgress]) {
+			reportProgress := func(message string) {
+				asyncContext.SetProgress(&DeployProgress{Message: message, Timestamp: time.Now()})
+			}
+
+			deployment := &Deployment{
+				Parameters: map[string]InputParameter{},
+				Outputs:    map[string]OutputParameter{},
+			}
+
+			for i, layer := range p.layers {
+				var layerPlan *DeploymentPlan
+				if details, ok := plan.Details.(*layeredPlan); ok && i == 0 {
+					layerPlan = details.firstLayerPlan
+				} else {
+					var err error
+					layerPlan, err = p.planLayer(ctx, layer, reportProgress)
+					if err != nil {
+						asyncContext.SetError(err)
+						return
+					}
+				}
+
+				result, err := awaitLayerTask(layer.provider.Deploy(ctx, layerPlan, scope), func(progress *DeployProgress) {
+					reportProgress(layerProgressMessage(layer.name, progress.Message))
+				})
+				if err != nil {
+					asyncContext.SetError(fmt.Errorf("deploying layer %s: %w", layer.name, err))
+					return
+				}
+
+				if err := UpdateEnvironment(p.env, result.Deployment.Outputs); err != nil {
+					asyncContext.SetError(fmt.Errorf("updating environment with outputs of layer %s: %w", layer.name, err))
+					return
+				}
+
+				for name, parameter := range result.Deployment.Parameters {
+					deployment.Parameters[name] = parameter
+				}
+				for name, output := range result.Deployment.Outputs {
+					deployment.Outputs[name] = output
+				}
+			}
+
+			asyncContext.SetResult(&DeployResult{Deployment: deployment})
+		})
+}
+
+// Destroy destroys the layers in the reverse order, so the resources of a layer are deleted before the resources they
+// depend on.
+func (p *layeredProvider) Destroy(
+	ctx context.Context,
+	deployment *Deployment,
+	options DestroyOptions,
+) *async.InteractiveTaskWithProgress[*DestroyResult, *DestroyProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DestroyResult, *DestroyProgress]) {
+			destroyResult := &DestroyResult{
+				Resources: []azcli.AzCliResource{},
+				Outputs:   map[string]OutputParameter{},
+			}
+
+			for i := len(p.layers) - 1; i >= 0; i-- {
+				layer := p.layers[i]
+				result, err := awaitLayerTask(
+					layer.provider.Destroy(ctx, deployment, options),
+					func(progress *DestroyProgress) {
+						asyncContext.SetProgress(&DestroyProgress{
+							Message:   layerProgressMessage(layer.name, progress.Message),
+							Timestamp: progress.Timestamp,
+						})
+					},
+				)
+				if err != nil {
+					asyncContext.SetError(fmt.Errorf("destroying layer %s: %w", layer.name, err))
+					return
+				}
+
+				destroyResult.Resources = append(destroyResult.Resources, result.Resources...)
+				for name, output := range result.Outputs {
+					destroyResult.Outputs[name] = output
+				}
+			}
+
+			asyncContext.SetResult(destroyResult)
+		})
+}
+
+func (p *layeredProvider) planLayer(
+	ctx context.Context,
+	layer providerLayer,
+	reportProgress func(message string),
+) (*DeploymentPlan, error) {
+	plan, err := awaitLayerTask(layer.provider.Plan(ctx), func(progress *DeploymentPlanningProgress) {
+		reportProgress(layerProgressMessage(layer.name, progress.Message))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("planning layer %s: %w", layer.name, err)
+	}
+
+	return plan, nil
+}
+
+// awaitLayerTask awaits a task of the provider of a layer, reporting its progress.
+func awaitLayerTask[R comparable, P comparable](
+	task *async.InteractiveTaskWithProgress[R, P],
+	reportProgress func(progress P),
+) (R, error) {
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		for progress := range task.Progress() {
+			reportProgress(progress)
+		}
+	}()
+
+	result, err := task.Await()
+	<-progressDone
+	return result, err
+}
+
+func layerProgressMessage(layerName string, message string) string {
+	return fmt.Sprintf("%s: %s", layerName, message)
+}
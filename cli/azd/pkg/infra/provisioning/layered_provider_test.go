@@ -0,0 +1,185 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_validateLayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		layers   []LayerOptions
+		expected string
+	}{
+		{
+			name: "Valid",
+			layers: []LayerOptions{
+				{Name: "network", Provider: Terraform, Path: "infra/network"},
+				{Name: "apps", Provider: Bicep, Path: "infra/apps"},
+			},
+		},
+		{
+			name:     "NoName",
+			layers:   []LayerOptions{{Provider: Terraform, Path: "infra/network"}},
+			expected: "must have a name",
+		},
+		{
+			name: "DuplicateName",
+			layers: []LayerOptions{
+				{Name: "network", Provider: Terraform, Path: "infra/network"},
+				{Name: "network", Provider: Terraform, Path: "infra/apps"},
+			},
+			expected: "several layers named 'network'",
+		},
+		{
+			name:     "NoPath",
+			layers:   []LayerOptions{{Name: "network", Provider: Terraform}},
+			expected: "layer 'network' of infra has no path",
+		},
+		{
+			name: "SeveralBicepLayers",
+			layers: []LayerOptions{
+				{Name: "network", Path: "infra/network"},
+				{Name: "apps", Provider: Bicep, Path: "infra/apps"},
+			},
+			expected: "at most one layer can use Bicep",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLayers(tt.layers)
+			if tt.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func Test_layeredProvider(t *testing.T) {
+	env := environment.EphemeralWithValues("test", nil)
+	calls := []string{}
+
+	network := &fakeLayerProvider{
+		name:    "network",
+		env:     env,
+		calls:   &calls,
+		outputs: map[string]OutputParameter{"SUBNET_ID": {Type: ParameterTypeString, Value: "SUBNET"}},
+	}
+	apps := &fakeLayerProvider{
+		name:    "apps",
+		env:     env,
+		calls:   &calls,
+		outputs: map[string]OutputParameter{"API_URL": {Type: ParameterTypeString, Value: "https://api"}},
+	}
+
+	provider := newLayeredProvider(env, []providerLayer{
+		{name: "network", provider: network},
+		{name: "apps", provider: apps},
+	})
+
+	t.Run("Deploy", func(t *testing.T) {
+		plan, err := provider.Plan(context.Background()).Await()
+		require.NoError(t, err)
+		require.Equal(t, []string{"plan network"}, calls)
+
+		result, err := provider.Deploy(context.Background(), plan, nil).Await()
+		require.NoError(t, err)
+		require.Equal(t, []string{"plan network", "deploy network", "plan apps", "deploy apps"}, calls)
+
+		// The outputs of the network layer are in the environment when the apps layer is planned
+		require.Equal(t, "SUBNET", apps.plannedSubnetId)
+		require.Equal(t, map[string]OutputParameter{
+			"SUBNET_ID": {Type: ParameterTypeString, Value: "SUBNET"},
+			"API_URL":   {Type: ParameterTypeString, Value: "https://api"},
+		}, result.Deployment.Outputs)
+	})
+
+	t.Run("Destroy", func(t *testing.T) {
+		calls = []string{}
+		result, err := provider.Destroy(context.Background(), &Deployment{}, NewDestroyOptions(true, false)).Await()
+		require.NoError(t, err)
+		require.Equal(t, []string{"destroy apps", "destroy network"}, calls)
+		require.Len(t, result.Outputs, 2)
+	})
+}
+
+// fakeLayerProvider records the operations of a layer in calls.
+type fakeLayerProvider struct {
+	name    string
+	env     *environment.Environment
+	calls   *[]string
+	outputs map[string]OutputParameter
+	// The value of SUBNET_ID in the environment when the layer was planned
+	plannedSubnetId string
+}
+
+func (p *fakeLayerProvider) Name() string {
+	return p.name
+}
+
+func (p *fakeLayerProvider) RequiredExternalTools() []tools.ExternalTool {
+	return nil
+}
+
+func (p *fakeLayerProvider) EnsureConfigured(ctx context.Context) error {
+	return nil
+}
+
+func (p *fakeLayerProvider) State(
+	ctx context.Context,
+	scope infra.Scope,
+) *async.InteractiveTaskWithProgress[*StateResult, *StateProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*StateResult, *StateProgress]) {
+			asyncContext.SetResult(&StateResult{State: &State{Outputs: p.outputs}})
+		})
+}
+
+func (p *fakeLayerProvider) Plan(
+	ctx context.Context,
+) *async.InteractiveTaskWithProgress[*DeploymentPlan, *DeploymentPlanningProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeploymentPlan, *DeploymentPlanningProgress]) {
+			*p.calls = append(*p.calls, "plan "+p.name)
+			p.plannedSubnetId = p.env.Getenv("SUBNET_ID")
+			asyncContext.SetResult(&DeploymentPlan{})
+		})
+}
+
+func (p *fakeLayerProvider) Deploy(
+	ctx context.Context,
+	plan *DeploymentPlan,
+	scope infra.Scope,
+) *async.InteractiveTaskWithProgress[*DeployResult, *DeployProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DeployResult, *DeployProgress]) {
+			*p.calls = append(*p.calls, "deploy "+p.name)
+			asyncContext.SetResult(&DeployResult{Deployment: &Deployment{Outputs: p.outputs}})
+		})
+}
+
+func (p *fakeLayerProvider) Destroy(
+	ctx context.Context,
+	deployment *Deployment,
+	options DestroyOptions,
+) *async.InteractiveTaskWithProgress[*DestroyResult, *DestroyProgress] {
+	return async.RunInteractiveTaskWithProgress(
+		func(asyncContext *async.InteractiveTaskContextWithProgress[*DestroyResult, *DestroyProgress]) {
+			*p.calls = append(*p.calls, "destroy "+p.name)
+			asyncContext.SetResult(&DestroyResult{Resources: []azcli.AzCliResource{}, Outputs: p.outputs})
+		})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/progress"
 	"github.com/azure/azure-dev/cli/azd/pkg/spin"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
@@ -32,8 +33,13 @@ type Manager struct {
 	userProfileService *azcli.UserProfileService
 	subResolver        account.SubscriptionTenantResolver
 	interactive        bool
+	// progressBus receives the progress of the planning and the deployment, see SetProgressBus
+	progressBus *progress.Bus
 }
 
+// The operation of the progress events published by the manager
+const progressOperation = "provision"
+
 // Prepares for an infrastructure provision operation
 func (m *Manager) Plan(ctx context.Context) (*DeploymentPlan, error) {
 	deploymentPlan, err := m.plan(ctx)
@@ -118,20 +124,36 @@ func (m *Manager) Destroy(ctx context.Context, deployment *Deployment, options D
 	return destroyResult, nil
 }
 
+// SetProgressBus publishes the steps of the planning and of the deployment, and the progress reported by the
+// provisioning provider, to bus.
+func (m *Manager) SetProgressBus(bus *progress.Bus) {
+	m.progressBus = bus
+}
+
 // Plans the infrastructure provisioning and orchestrates interactive terminal operations
 func (m *Manager) plan(ctx context.Context) (*DeploymentPlan, error) {
+	step := m.progressBus.Step(progressOperation, m.env.GetEnvName(), "Planning infrastructure provisioning")
+	step.Start(ctx)
+
 	planningTask := m.provider.Plan(ctx)
+	// The progress is published before the step completes
+	progressDone := make(chan struct{})
 	go func() {
-		for progress := range planningTask.Progress() {
-			log.Println(progress.Message)
+		defer close(progressDone)
+		for planningProgress := range planningTask.Progress() {
+			log.Println(planningProgress.Message)
+			step.Progress(ctx, planningProgress.Message)
 		}
 	}()
 
 	deploymentPlan, err := planningTask.Await()
+	<-progressDone
 	if err != nil {
+		step.Fail(ctx, err)
 		return nil, fmt.Errorf("planning infrastructure provisioning: %w", err)
 	}
 
+	step.Complete(ctx)
 	return deploymentPlan, nil
 }
 
@@ -141,22 +163,32 @@ func (m *Manager) deploy(
 	plan *DeploymentPlan,
 	scope infra.Scope,
 ) (*DeployResult, error) {
+	step := m.progressBus.Step(progressOperation, m.env.GetEnvName(), "Deploying infrastructure")
+	step.Start(ctx)
+
 	deployTask := m.provider.Deploy(ctx, plan, scope)
 
+	// The progress is published before the step completes
+	progressDone := make(chan struct{})
 	go func() {
-		for progress := range deployTask.Progress() {
-			log.Println(progress.Message)
+		defer close(progressDone)
+		for deployProgress := range deployTask.Progress() {
+			log.Println(deployProgress.Message)
+			step.Progress(ctx, deployProgress.Message)
 		}
 	}()
 
 	deployResult, err := deployTask.Await()
+	<-progressDone
 	if err != nil {
+		step.Fail(ctx, err)
 		return nil, fmt.Errorf("error deploying infrastructure: %w", err)
 	}
 
 	// make sure any spinner is stopped
 	m.console.StopSpinner(ctx, "", input.StepDone)
 
+	step.Complete(ctx)
 	return deployResult, nil
 }
 
@@ -256,6 +288,7 @@ func NewManager(
 		accountManager:     accountManager,
 		userProfileService: userProfileService,
 		subResolver:        subResolver,
+		progressBus:        progress.NewBus(),
 	}
 
 	prompters := Prompters{
@@ -3,7 +3,11 @@
 
 package provisioning
 
-import "github.com/azure/azure-dev/cli/azd/pkg/output"
+import (
+	"context"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
 
 type ActionOptions struct {
 	// The desired console output format
@@ -34,8 +38,15 @@ type DestroyOptions struct {
 	force bool
 	// Whether or not to purge any key vaults associated with the deployment
 	purge bool
+	// Whether or not to skip the inventory of the resources storing data before deleting them
+	skipDataCheck bool
+	// Backs up the data of the resources before deleting them, nil when no backup is configured
+	backup BackupFn
 }
 
+// BackupFn backs up the data of the resources to destroy, like exporting databases or taking snapshots of storage.
+type BackupFn func(ctx context.Context) error
+
 func (o *DestroyOptions) Purge() bool {
 	return o.purge
 }
@@ -44,6 +55,22 @@ func (o *DestroyOptions) Force() bool {
 	return o.force
 }
 
+func (o *DestroyOptions) SkipDataCheck() bool {
+	return o.skipDataCheck
+}
+
+func (o *DestroyOptions) Backup() BackupFn {
+	return o.backup
+}
+
+// WithDataCheck returns a copy of the options configuring the inventory of the resources storing data, and the backup
+// of their data offered before they're deleted.
+func (o DestroyOptions) WithDataCheck(skipDataCheck bool, backup BackupFn) DestroyOptions {
+	o.skipDataCheck = skipDataCheck
+	o.backup = backup
+	return o
+}
+
 func NewDestroyOptions(force bool, purge bool) DestroyOptions {
 	return DestroyOptions{
 		force: force,
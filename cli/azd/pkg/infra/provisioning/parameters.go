@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+)
+
+// ParseParameterOverrides returns the parameters overriding the parameters of the template for a single run, from a
+// parameters file and from `key=value` pairs, which take precedence.
+//
+// The parameters file is either a JSON object of parameter names to values, or an ARM deployment parameters file, like
+// main.parameters.json. Values of `key=value` pairs are strings, which providers convert to the type of the parameter.
+func ParseParameterOverrides(values []string, parametersFile string) (map[string]any, error) {
+	parameters := map[string]any{}
+
+	if parametersFile != "" {
+		fromFile, err := readParametersFile(parametersFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range fromFile {
+			parameters[name] = value
+		}
+	}
+
+	for _, value := range values {
+		name, parameterValue, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid parameter '%s', parameters must be formatted as key=value", value)
+		}
+
+		parameters[name] = parameterValue
+	}
+
+	return parameters, nil
+}
+
+func readParametersFile(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading parameters file: %w", err)
+	}
+
+	var parameters map[string]any
+	if err := json.Unmarshal(content, &parameters); err != nil {
+		return nil, fmt.Errorf("parsing parameters file '%s': %w", path, err)
+	}
+
+	// ARM deployment parameters files nest the values of the parameters, like parameters.location.value
+	if _, has := parameters["$schema"]; !has {
+		return parameters, nil
+	}
+
+	var armParameters azure.ArmParameterFile
+	if err := json.Unmarshal(content, &armParameters); err != nil {
+		return nil, fmt.Errorf("parsing parameters file '%s': %w", path, err)
+	}
+
+	parameters = make(map[string]any, len(armParameters.Parameters))
+	for name, parameter := range armParameters.Parameters {
+		parameters[name] = parameter.Value
+	}
+
+	return parameters, nil
+}
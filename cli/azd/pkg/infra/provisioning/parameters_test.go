@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParameterOverrides(t *testing.T) {
+	parametersFile := filepath.Join(t.TempDir(), "ci.parameters.json")
+	require.NoError(t, os.WriteFile(parametersFile, []byte(`{
+		"sku": "P1v3",
+		"replicas": 3,
+		"zones": ["1", "2"]
+	}`), 0600))
+
+	parameters, err := ParseParameterOverrides([]string{"sku=B1", "tag=a=b"}, parametersFile)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"sku":      "B1",
+		"replicas": float64(3),
+		"zones":    []any{"1", "2"},
+		"tag":      "a=b",
+	}, parameters)
+
+	t.Run("ArmParametersFile", func(t *testing.T) {
+		armParametersFile := filepath.Join(t.TempDir(), "main.parameters.json")
+		require.NoError(t, os.WriteFile(armParametersFile, []byte(`{
+			"$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+			"contentVersion": "1.0.0.0",
+			"parameters": {
+				"sku": { "value": "P1v3" }
+			}
+		}`), 0600))
+
+		parameters, err := ParseParameterOverrides(nil, armParametersFile)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"sku": "P1v3"}, parameters)
+	})
+
+	t.Run("InvalidParameter", func(t *testing.T) {
+		_, err := ParseParameterOverrides([]string{"sku"}, "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parameters must be formatted as key=value")
+	})
+}
@@ -73,6 +73,57 @@ type Options struct {
 	Provider ProviderKind `yaml:"provider"`
 	Path     string       `yaml:"path"`
 	Module   string       `yaml:"module"`
+	// Additional tags applied to the provisioned resources, see ResolveTags.
+	Tags map[string]string `yaml:"tags,omitempty"`
+	// Parameters overriding the parameters of the template for a single run, like the values of
+	// `azd provision --parameter`. They're never saved in the environment, see ParseParameterOverrides.
+	Parameters map[string]any `yaml:"-"`
+	// Force submits the deployment even when the template and its parameters are unchanged since the last successful
+	// deployment of the environment, like `azd provision --force`. It is never saved.
+	Force bool `yaml:"-"`
+	// RegisterProviders registers the resource providers of the template which aren't registered in the subscription
+	// without prompting, like `azd provision --register-providers`. It is never saved.
+	RegisterProviders bool `yaml:"-"`
+	// Deployment configures the names of the deployments of the environments.
+	Deployment DeploymentOptions `yaml:"deployment,omitempty"`
+	// ProjectName is the name of the project in azure.yaml, which identifies the deployments of its environments.
+	ProjectName string `yaml:"-"`
+	// Registries are the private registries holding the Bicep modules referenced by the templates.
+	Registries []ModuleRegistry `yaml:"registries,omitempty"`
+	// Layers split the infrastructure in layers provisioned in order, each with its own provider, like a Terraform layer
+	// creating the network and a Bicep layer creating the applications. Provider, Path and Module are ignored when set.
+	Layers []LayerOptions `yaml:"layers,omitempty"`
+}
+
+// ModuleRegistry is a private registry of Bicep modules, like the Azure Container Registry holding the curated modules
+// of an organization.
+type ModuleRegistry struct {
+	// The alias of the registry in module references, like br/<alias>:storage:v1
+	Alias string `yaml:"alias"`
+	// The login server of the registry, like contoso.azurecr.io
+	Server string `yaml:"server"`
+	// The path prepended to the module paths of the alias, like bicep/modules
+	ModulePath string `yaml:"modulePath,omitempty"`
+	// The subscription of the registry, when it isn't the subscription of the environment. azd authenticates to the
+	// registry in the tenant of this subscription.
+	SubscriptionId string `yaml:"subscriptionId,omitempty"`
+}
+
+// DeploymentNaming is how the deployments of an environment are named.
+type DeploymentNaming string
+
+const (
+	// Each provisioning creates a deployment named after the environment, the time and a short hash, which keeps the
+	// history of the deployments of the environment.
+	DeploymentNamingUnique DeploymentNaming = "unique"
+	// Each provisioning updates the deployment named after the environment.
+	DeploymentNamingEnvironment DeploymentNaming = "environment"
+)
+
+type DeploymentOptions struct {
+	// How the deployments are named, unique by default. Azure deletes the oldest deployments of a subscription when it
+	// approaches its limit of 800 deployments.
+	Naming DeploymentNaming `yaml:"naming,omitempty"`
 }
 
 type DeploymentPlan struct {
@@ -163,6 +214,21 @@ func NewProvider(
 ) (Provider, error) {
 	var provider Provider
 
+	if len(infraOptions.Layers) > 0 {
+		return newLayersProvider(
+			ctx,
+			console,
+			azCli,
+			commandRunner,
+			env,
+			projectPath,
+			infraOptions,
+			prompters,
+			principalProvider,
+			alphaFeatureManager,
+		)
+	}
+
 	if infraOptions.Provider == "" {
 		infraOptions.Provider = Bicep
 	}
@@ -191,3 +257,50 @@ func NewProvider(
 
 	return provider, nil
 }
+
+// newLayersProvider creates the providers of the layers of the infrastructure, see LayerOptions.
+func newLayersProvider(
+	ctx context.Context,
+	console input.Console,
+	azCli azcli.AzCli,
+	commandRunner exec.CommandRunner,
+	env *environment.Environment,
+	projectPath string,
+	infraOptions Options,
+	prompters Prompters,
+	principalProvider CurrentPrincipalIdProvider,
+	alphaFeatureManager *alpha.FeatureManager,
+) (Provider, error) {
+	if err := validateLayers(infraOptions.Layers); err != nil {
+		return nil, err
+	}
+
+	layers := make([]providerLayer, 0, len(infraOptions.Layers))
+	for _, layer := range infraOptions.Layers {
+		layerOptions := infraOptions
+		layerOptions.Provider = layer.Provider
+		layerOptions.Path = layer.Path
+		layerOptions.Module = layer.Module
+		layerOptions.Layers = nil
+
+		provider, err := NewProvider(
+			ctx,
+			console,
+			azCli,
+			commandRunner,
+			env,
+			projectPath,
+			layerOptions,
+			prompters,
+			principalProvider,
+			alphaFeatureManager,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("creating provider of layer %s: %w", layer.Name, err)
+		}
+
+		layers = append(layers, providerLayer{name: layer.Name, provider: provider})
+	}
+
+	return newLayeredProvider(env, layers), nil
+}
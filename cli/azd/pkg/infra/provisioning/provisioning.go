@@ -13,7 +13,9 @@ import (
 
 func UpdateEnvironment(env *environment.Environment, outputs map[string]OutputParameter) error {
 	if len(outputs) > 0 {
+		values := map[string]any{}
 		for key, param := range outputs {
+			values[key] = param.Value
 			// Complex types marshalled as JSON strings, simple types marshalled as simple strings
 			if param.Type == ParameterTypeArray || param.Type == ParameterTypeObject {
 				bytes, err := json.Marshal(param.Value)
@@ -26,6 +28,11 @@ func UpdateEnvironment(env *environment.Environment, outputs map[string]OutputPa
 			}
 		}
 
+		// Hooks get the outputs with their types, see ext.ProvisionOutputsEnvVarName
+		if err := env.Config.Set(environment.ProvisionOutputsConfigPath, values); err != nil {
+			return fmt.Errorf("setting provision outputs: %w", err)
+		}
+
 		if err := env.Save(); err != nil {
 			return fmt.Errorf("writing environment: %w", err)
 		}
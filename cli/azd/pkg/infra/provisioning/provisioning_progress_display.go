@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/url"
 	"sort"
 	"strings"
 	"time"
@@ -66,10 +65,7 @@ func (display *ProvisioningProgressDisplay) ReportProgress(
 		}
 
 		display.deploymentStarted = true
-		deploymentUrl := fmt.Sprintf(
-			output.WithLinkFormat("https://portal.azure.com/#blade/HubsExtension/DeploymentDetailsBlade/overview/id/%s\n"),
-			url.PathEscape(display.scope.DeploymentUrl()),
-		)
+		deploymentUrl := output.WithLinkFormat("%s\n", infra.DeploymentPortalUrl(display.scope))
 
 		display.console.MessageUxItem(
 			ctx,
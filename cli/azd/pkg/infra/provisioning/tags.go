@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/drone/envsubst"
+)
+
+// TagsConfigPath is the path of the additional tags in the configuration of an environment.
+const TagsConfigPath = "infra.tags"
+
+// TagsParameterName is the name of the template parameter which receives the additional tags. Templates apply it to
+// their resources alongside the azd-env-name and azd-service-name tags.
+const TagsParameterName = "tags"
+
+// ResolveTags returns the additional tags applied to the provisioned resources, like a cost center or an owner.
+//
+// Tags are declared in `infra.tags` of azure.yaml, where values support environment variable substitution, and in
+// `infra.tags` of the configuration of the environment, which take precedence.
+func ResolveTags(options Options, env *environment.Environment) (map[string]string, error) {
+	tags := map[string]string{}
+
+	for name, value := range options.Tags {
+		expanded, err := envsubst.Eval(value, env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding tag '%s': %w", name, err)
+		}
+
+		tags[name] = expanded
+	}
+
+	if env.Config == nil {
+		return tags, nil
+	}
+
+	if raw, has := env.Config.Get(TagsConfigPath); has {
+		envTags, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s of the environment configuration must be an object", TagsConfigPath)
+		}
+
+		for name, value := range envTags {
+			tags[name] = fmt.Sprint(value)
+		}
+	}
+
+	return tags, nil
+}
+
+// InjectsTags reports whether the provisioning provider applies the additional tags to the template, which only Bicep
+// templates support. The tags are only verified on the service resources when they're applied.
+func InjectsTags(options Options) bool {
+	return options.Provider == "" || options.Provider == Bicep
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveTags(t *testing.T) {
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		"COST_CENTER": "cc-42",
+	})
+	require.NoError(t, env.Config.Set("infra.tags.owner", "platform-team"))
+	require.NoError(t, env.Config.Set("infra.tags.stage", "dev"))
+
+	tags, err := ResolveTags(Options{
+		Tags: map[string]string{
+			"cost-center": "${COST_CENTER}",
+			"owner":       "app-team",
+		},
+	}, env)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"cost-center": "cc-42",
+		"owner":       "platform-team",
+		"stage":       "dev",
+	}, tags)
+}
+
+func TestResolveTagsInvalidConfig(t *testing.T) {
+	env := environment.EphemeralWithValues("dev", nil)
+	require.NoError(t, env.Config.Set("infra.tags", "owner=platform-team"))
+
+	_, err := ResolveTags(Options{}, env)
+	require.Error(t, err)
+}
+
+func TestInjectsTags(t *testing.T) {
+	require.True(t, InjectsTags(Options{}))
+	require.True(t, InjectsTags(Options{Provider: Bicep}))
+	require.False(t, InjectsTags(Options{Provider: Terraform}))
+}
@@ -32,6 +32,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
 	"github.com/drone/envsubst"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // TerraformProvider exposes infrastructure provisioning using Azure Terraform templates
@@ -315,9 +317,31 @@ func (t *TerraformProvider) createPlanArgs(isRemoteBackendConfig bool) []string
 		args = append(args, fmt.Sprintf("-state=%s", t.localStateFilePath()))
 	}
 
+	// Overridden parameters follow the parameters file, so their values take precedence
+	names := maps.Keys(t.options.Parameters)
+	slices.Sort(names)
+	for _, name := range names {
+		args = append(args, fmt.Sprintf("-var=%s=%s", name, terraformVarValue(t.options.Parameters[name])))
+	}
+
 	return args
 }
 
+// terraformVarValue formats the value of a -var argument. Strings are passed as is, other values as JSON, which
+// terraform parses like HCL expressions for lists and maps.
+func terraformVarValue(value any) string {
+	if val, ok := value.(string); ok {
+		return val
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
+
 // Creates the terraform apply CLI arguments
 func (t *TerraformProvider) createApplyArgs(
 	isRemoteBackendConfig bool, data TerraformDeploymentDetails) ([]string, error) {
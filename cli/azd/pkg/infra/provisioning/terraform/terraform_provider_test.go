@@ -214,6 +214,26 @@ func TestTerraformState(t *testing.T) {
 	)
 }
 
+func TestTerraformPlanArgsParameterOverrides(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+
+	infraProvider := createTerraformProvider(mockContext)
+	infraProvider.options.Parameters = map[string]any{
+		"sku":      "B1",
+		"replicas": float64(3),
+		"zones":    []any{"1", "2"},
+	}
+
+	args := infraProvider.createPlanArgs(true)
+	require.Equal(t, []string{
+		fmt.Sprintf("-var-file=%s", infraProvider.parametersFilePath()),
+		"-var=replicas=3",
+		"-var=sku=B1",
+		`-var=zones=["1","2"]`,
+	}, args)
+}
+
 func createTerraformProvider(mockContext *mocks.MockContext) *TerraformProvider {
 	projectDir := "../../../../test/functional/testdata/samples/resourcegroupterraform"
 	options := Options{
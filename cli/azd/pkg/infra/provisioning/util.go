@@ -14,6 +14,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/locale"
 )
 
 // EnsureSubscriptionAndLocation ensures that a subscription and location are configured in the environment, prompting
@@ -26,7 +27,7 @@ func EnsureSubscriptionAndLocation(
 	if env.GetSubscriptionId() == "" {
 		subscriptionId, err := promptSubscription(
 			ctx,
-			"Please select an Azure Subscription to use:",
+			locale.Sprintf(locale.SubscriptionPrompt),
 			console,
 			env,
 			accountManager)
@@ -45,7 +46,7 @@ func EnsureSubscriptionAndLocation(
 		location, err := promptLocation(
 			ctx,
 			env.GetSubscriptionId(),
-			"Please select an Azure location to use:",
+			locale.Sprintf(locale.LocationPrompt),
 			func(_ account.Location) bool { return true },
 			console,
 			env,
@@ -88,6 +88,8 @@ type SubscriptionScope struct {
 	name           string
 	subscriptionId string
 	location       string
+	// The tags of the deployment
+	tags map[string]*string
 }
 
 // Gets the deployment name
@@ -114,7 +116,7 @@ func (s *SubscriptionScope) Location() string {
 func (s *SubscriptionScope) Deploy(
 	ctx context.Context, template azure.RawArmTemplate, parameters azure.ArmParameters,
 ) (*armresources.DeploymentExtended, error) {
-	return s.azCli.DeployToSubscription(ctx, s.subscriptionId, s.name, template, parameters, s.location)
+	return s.azCli.DeployToSubscription(ctx, s.subscriptionId, s.name, template, parameters, s.location, s.tags)
 }
 
 // GetDeployment fetches the result of the most recent deployment.
@@ -137,3 +139,22 @@ func NewSubscriptionScope(
 		location:       location,
 	}
 }
+
+// NewEnvironmentSubscriptionScope is like NewSubscriptionScope, but tags the deployment with the name of the environment
+// and of the project, so that the deployments of the environment are found whatever their names.
+func NewEnvironmentSubscriptionScope(
+	azCli azcli.AzCli, location string, subscriptionId string, deploymentName string, envName string, projectName string,
+) Scope {
+	tags := map[string]*string{EnvNameTag: &envName}
+	if projectName != "" {
+		tags[ProjectNameTag] = &projectName
+	}
+
+	return &SubscriptionScope{
+		azCli:          azCli,
+		name:           deploymentName,
+		subscriptionId: subscriptionId,
+		location:       location,
+		tags:           tags,
+	}
+}
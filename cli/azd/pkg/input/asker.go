@@ -57,6 +57,16 @@ func askOneNoPrompt(p survey.Prompt, response interface{}) error {
 		default:
 			return fmt.Errorf("bad type %T for result, should be (*int or *string)", response)
 		}
+	case *survey.Password:
+		// Secrets have no default value
+		return fmt.Errorf("no default response for prompt '%s'", v.Message)
+	case *survey.MultiSelect:
+		defaultValue, ok := v.Default.([]string)
+		if !ok {
+			return fmt.Errorf("no default response for prompt '%s'", v.Message)
+		}
+
+		*(response.(*[]string)) = defaultValue
 	case *survey.Confirm:
 		*(response.(*bool)) = v.Default
 	default:
@@ -126,6 +136,41 @@ func askOnePrompt(p survey.Prompt, response interface{}, isTerminal bool, stdout
 		}
 		*pResponse = result
 		return nil
+	case *survey.Password:
+		// Without a terminal, the input can't be hidden
+		fmt.Fprintf(stdout, "%s ", v.Message)
+		result, err := readStringNoBuffer(stdin, '\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading response: %w", err)
+		}
+		*(response.(*string)) = strings.TrimSpace(result)
+		return nil
+	case *survey.MultiSelect:
+		defaultValue, hasDefault := v.Default.([]string)
+		for {
+			fmt.Fprintf(stdout, "%s", v.Message[0:len(v.Message)-1])
+			fmt.Fprintf(stdout, " (comma separated values of %s", strings.Join(v.Options, ", "))
+			if hasDefault {
+				fmt.Fprintf(stdout, ", or hit enter to use the default %s", strings.Join(defaultValue, ", "))
+			}
+			fmt.Fprintf(stdout, ")%s ", v.Message[len(v.Message)-1:])
+			result, err := readStringNoBuffer(stdin, '\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("reading response: %w", err)
+			}
+			result = strings.TrimSpace(result)
+			if result == "" && hasDefault {
+				*(response.(*[]string)) = defaultValue
+				return nil
+			}
+
+			selected, invalid := parseMultiSelectResponse(result, v.Options)
+			if invalid == "" {
+				*(response.(*[]string)) = selected
+				return nil
+			}
+			fmt.Fprintf(stdout, "error: %s is not an allowed choice\n", invalid)
+		}
 	case *survey.Select:
 		for {
 			fmt.Fprintf(stdout, "%s", v.Message[0:len(v.Message)-1])
@@ -186,3 +231,31 @@ func askOnePrompt(p survey.Prompt, response interface{}, isTerminal bool, stdout
 		panic(fmt.Sprintf("don't know how to prompt for type %T", p))
 	}
 }
+
+// parseMultiSelectResponse returns the options selected by the comma separated values of response, or the first value
+// which isn't one of the options.
+func parseMultiSelectResponse(response string, options []string) ([]string, string) {
+	selected := []string{}
+	for _, value := range strings.Split(response, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		found := false
+		for _, option := range options {
+			if option == value {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, value
+		}
+
+		selected = append(selected, value)
+	}
+
+	return selected, ""
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -59,6 +60,8 @@ type Console interface {
 	Prompt(ctx context.Context, options ConsoleOptions) (string, error)
 	// Prompts the user to select from a set of values
 	Select(ctx context.Context, options ConsoleOptions) (int, error)
+	// Prompts the user to select any number of values from a set of values
+	MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error)
 	// Prompts the user to confirm an operation
 	Confirm(ctx context.Context, options ConsoleOptions) (bool, error)
 	// Sets the underlying writer for the console
@@ -73,6 +76,8 @@ type Console interface {
 type AskerConsole struct {
 	asker   Asker
 	handles ConsoleHandles
+	// when true, prompts are answered with their default value
+	noPrompt bool
 	// the writer the console was constructed with, and what we reset to when SetWriter(nil) is called.
 	defaultWriter io.Writer
 	// the writer which output is written to.
@@ -81,6 +86,10 @@ type AskerConsole struct {
 	spinner       *yacspin.Spinner
 	currentIndent string
 	consoleWidth  int
+	// when true, spinners are replaced by timestamped log lines, see writePlainLine
+	plain bool
+	// the title of the step being reported in plain mode, empty when there is none
+	plainStep string
 }
 
 type ConsoleOptions struct {
@@ -88,6 +97,10 @@ type ConsoleOptions struct {
 	Help         string
 	Options      []string
 	DefaultValue any
+	// Whether the value of a prompt is a secret, like a password or a token, which isn't echoed
+	IsPassword bool
+	// Validates the value of a prompt, the user is prompted again until the value is valid
+	Validate PromptValidator
 }
 
 type ConsoleHandles struct {
@@ -169,6 +182,15 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 		return
 	}
 
+	if c.plain {
+		// repeated updates with the same title are written once, to avoid filling logs with redundant lines
+		if title != c.plainStep {
+			c.plainStep = title
+			c.writePlainLine(title)
+		}
+		return
+	}
+
 	if c.consoleWidth <= cMinConsoleWidth {
 		// no spinner for consoles with width <= cMinConsoleWidth
 		c.Message(ctx, title)
@@ -233,6 +255,14 @@ func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, form
 		return
 	}
 
+	if c.plain {
+		if c.plainStep != "" && lastMessage != "" {
+			c.writePlainLine(strings.TrimSpace(fmt.Sprintf("%s %s", c.getStopChar(format), lastMessage)))
+		}
+		c.plainStep = ""
+		return
+	}
+
 	// calling stop for non existing spinner
 	if c.spinner == nil {
 		return
@@ -254,9 +284,18 @@ func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, form
 }
 
 func (c *AskerConsole) IsSpinnerRunning(ctx context.Context) bool {
+	if c.plain {
+		return c.plainStep != ""
+	}
+
 	return c.spinner != nil && c.spinner.Status() != yacspin.SpinnerStopped
 }
 
+// writePlainLine writes a timestamped line, used instead of a spinner in plain mode.
+func (c *AskerConsole) writePlainLine(line string) {
+	fmt.Fprintf(c.writer, "%s %s\n", time.Now().Format(time.RFC3339), line)
+}
+
 var donePrefix string = output.WithSuccessFormat("(✓) Done:")
 
 func (c *AskerConsole) getStopChar(format SpinnerUxType) string {
@@ -274,29 +313,52 @@ func (c *AskerConsole) getStopChar(format SpinnerUxType) string {
 	return fmt.Sprintf("%s%s", c.getIndent(format), stopChar)
 }
 
-// Prompts the user for a single value
+// Prompts the user for a single value. Secrets are prompted without echoing them, and the user is prompted again while
+// the value is invalid.
 func (c *AskerConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
 	var defaultValue string
 	if value, ok := options.DefaultValue.(string); ok {
 		defaultValue = value
 	}
 
-	survey := &survey.Input{
+	var prompt survey.Prompt = &survey.Input{
 		Message: options.Message,
 		Default: defaultValue,
 		Help:    options.Help,
 	}
+	if options.IsPassword {
+		prompt = &survey.Password{
+			Message: options.Message,
+			Help:    options.Help,
+		}
+	}
 
-	var response string
+	for {
+		var response string
 
-	err := c.doInteraction(func(c *AskerConsole) error {
-		return c.asker(survey, &response)
-	})
-	if err != nil {
-		return response, err
-	}
+		err := c.doInteraction(func(c *AskerConsole) error {
+			return c.asker(prompt, &response)
+		})
+		if err != nil {
+			return response, err
+		}
 
-	return response, nil
+		if options.Validate == nil {
+			return response, nil
+		}
+
+		err = options.Validate(response)
+		if err == nil {
+			return response, nil
+		}
+
+		// The default value is the only answer without prompting, asking again would return it again
+		if c.noPrompt {
+			return "", fmt.Errorf("invalid response for prompt '%s': %w", options.Message, err)
+		}
+
+		c.Message(ctx, output.WithErrorFormat("Error: %s", err))
+	}
 }
 
 // Prompts the user to select from a set of values
@@ -306,6 +368,9 @@ func (c *AskerConsole) Select(ctx context.Context, options ConsoleOptions) (int,
 		Options: options.Options,
 		Default: options.DefaultValue,
 		Help:    options.Help,
+		Filter: func(filter string, value string, index int) bool {
+			return FuzzyMatch(filter, value)
+		},
 	}
 
 	var response int
@@ -320,6 +385,50 @@ func (c *AskerConsole) Select(ctx context.Context, options ConsoleOptions) (int,
 	return response, nil
 }
 
+// Prompts the user to select any number of values from a set of values. DefaultValue is the []string of the values
+// selected by default.
+func (c *AskerConsole) MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error) {
+	prompt := &survey.MultiSelect{
+		Message: options.Message,
+		Options: options.Options,
+		Help:    options.Help,
+		Filter: func(filter string, value string, index int) bool {
+			return FuzzyMatch(filter, value)
+		},
+	}
+	if value, ok := options.DefaultValue.([]string); ok {
+		prompt.Default = value
+	}
+
+	var response []string
+
+	err := c.doInteraction(func(c *AskerConsole) error {
+		return c.asker(prompt, &response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// FuzzyMatch reports whether the characters of filter appear in value in the same order, ignoring case. Typing the
+// initials of an option, like "prd" for "contoso-prod", narrows a select prompt to the matching options.
+func FuzzyMatch(filter string, value string) bool {
+	remaining := []rune(strings.ToLower(filter))
+	for _, r := range strings.ToLower(value) {
+		if len(remaining) == 0 {
+			break
+		}
+
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+
+	return len(remaining) == 0
+}
+
 // Prompts the user to confirm an operation
 func (c *AskerConsole) Confirm(ctx context.Context, options ConsoleOptions) (bool, error) {
 	var defaultValue bool
@@ -364,17 +473,27 @@ func getConsoleWidth() int {
 	return width
 }
 
-// Creates a new console with the specified writer, handles and formatter.
-func NewConsole(noPrompt bool, isTerminal bool, w io.Writer, handles ConsoleHandles, formatter output.Formatter) Console {
+// NewConsole creates a console writing to w. When plain is set, spinners are replaced by timestamped log lines,
+// suited for screen readers and CI logs.
+func NewConsole(
+	noPrompt bool,
+	isTerminal bool,
+	plain bool,
+	w io.Writer,
+	handles ConsoleHandles,
+	formatter output.Formatter,
+) Console {
 	asker := NewAsker(noPrompt, isTerminal, handles.Stdout, handles.Stdin)
 
 	return &AskerConsole{
 		asker:         asker,
 		handles:       handles,
+		noPrompt:      noPrompt,
 		defaultWriter: w,
 		writer:        w,
 		formatter:     formatter,
 		consoleWidth:  getConsoleWidth(),
+		plain:         plain,
 	}
 }
 
@@ -386,6 +505,16 @@ func GetStepResultFormat(result error) SpinnerUxType {
 	return formatResult
 }
 
+// RunWithSpinner shows a spinner with the title while step runs, then stops it as done or failed, depending on the error
+// returned by step.
+func RunWithSpinner(ctx context.Context, console Console, title string, step func(ctx context.Context) error) error {
+	console.ShowSpinner(ctx, title, Step)
+	err := step(ctx)
+	console.StopSpinner(ctx, title, GetStepResultFormat(err))
+
+	return err
+}
+
 // Handle doing interactive calls. It check if there's a spinner running to pause it before doing interactive actions.
 func (c *AskerConsole) doInteraction(fn func(c *AskerConsole) error) error {
 
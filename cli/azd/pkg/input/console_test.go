@@ -4,7 +4,12 @@
 package input
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -38,3 +43,162 @@ func Test_consoleTruncate(t *testing.T) {
 		require.Equal(t, expected, produced)
 	})
 }
+
+func Test_consolePlain(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	console := NewConsole(true, false, true, buf, ConsoleHandles{Stdout: buf, Stderr: buf}, nil)
+
+	console.ShowSpinner(ctx, "Deploying service api", Step)
+	require.True(t, console.IsSpinnerRunning(ctx))
+
+	// repeated updates of the same step are written once
+	console.ShowSpinner(ctx, "Deploying service api", Step)
+	console.StopSpinner(ctx, "Deploying service api", StepDone)
+	require.False(t, console.IsSpinnerRunning(ctx))
+
+	// stopping a step that is not running is a no-op
+	console.StopSpinner(ctx, "Deploying service api", StepDone)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	for _, line := range lines {
+		timestamp, _, found := strings.Cut(line, " ")
+		require.True(t, found)
+
+		_, err := time.Parse(time.RFC3339, timestamp)
+		require.NoError(t, err)
+		require.NotContains(t, line, "\x1b[")
+	}
+
+	require.True(t, strings.HasSuffix(lines[0], " Deploying service api"))
+	require.True(t, strings.HasSuffix(lines[1], "Done: Deploying service api"))
+}
+
+func Test_RunWithSpinner(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	console := NewConsole(true, false, true, buf, ConsoleHandles{Stdout: buf, Stderr: buf}, nil)
+
+	err := RunWithSpinner(ctx, console, "Deploying service api", func(ctx context.Context) error {
+		require.True(t, console.IsSpinnerRunning(ctx))
+		return nil
+	})
+	require.NoError(t, err)
+	require.False(t, console.IsSpinnerRunning(ctx))
+
+	stepErr := errors.New("deployment failed")
+	err = RunWithSpinner(ctx, console, "Deploying service web", func(ctx context.Context) error {
+		return stepErr
+	})
+	require.ErrorIs(t, err, stepErr)
+	require.False(t, console.IsSpinnerRunning(ctx))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.True(t, strings.HasSuffix(lines[1], "Done: Deploying service api"))
+	require.True(t, strings.HasSuffix(lines[3], "Failed: Deploying service web"))
+}
+
+func Test_FuzzyMatch(t *testing.T) {
+	require.True(t, FuzzyMatch("", "contoso-prod"))
+	require.True(t, FuzzyMatch("prod", "contoso-prod"))
+	require.True(t, FuzzyMatch("prd", "contoso-prod"))
+	require.True(t, FuzzyMatch("CP", "contoso-prod"))
+	require.False(t, FuzzyMatch("dorp", "contoso-prod"))
+	require.False(t, FuzzyMatch("prod-2", "contoso-prod"))
+}
+
+func Test_consolePromptValidation(t *testing.T) {
+	validate := func(value string) error {
+		if value != "good" {
+			return errors.New("value should be good")
+		}
+
+		return nil
+	}
+
+	t.Run("prompts again until valid", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		stdin := strings.NewReader("bad\ngood\n")
+		console := NewConsole(false, false, false, buf, ConsoleHandles{Stdin: stdin, Stdout: buf, Stderr: buf}, nil)
+
+		value, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:  "Enter a value:",
+			Validate: validate,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "good", value)
+		require.Contains(t, buf.String(), "value should be good")
+	})
+
+	t.Run("invalid default without prompting", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := NewConsole(true, false, false, buf, ConsoleHandles{Stdout: buf, Stderr: buf}, nil)
+
+		_, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:      "Enter a value:",
+			DefaultValue: "bad",
+			Validate:     validate,
+		})
+		require.ErrorContains(t, err, "value should be good")
+	})
+}
+
+func Test_consolePassword(t *testing.T) {
+	t.Run("reads secret", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		stdin := strings.NewReader("s3cret\n")
+		console := NewConsole(false, false, false, buf, ConsoleHandles{Stdin: stdin, Stdout: buf, Stderr: buf}, nil)
+
+		value, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:    "Enter your client secret:",
+			IsPassword: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "s3cret", value)
+	})
+
+	t.Run("no default without prompting", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := NewConsole(true, false, false, buf, ConsoleHandles{Stdout: buf, Stderr: buf}, nil)
+
+		_, err := console.Prompt(context.Background(), ConsoleOptions{
+			Message:    "Enter your client secret:",
+			IsPassword: true,
+		})
+		require.Error(t, err)
+	})
+}
+
+func Test_consoleMultiSelect(t *testing.T) {
+	options := []string{"api", "web", "worker"}
+
+	t.Run("comma separated values", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		stdin := strings.NewReader("api, mobile\nworker, api\n")
+		console := NewConsole(false, false, false, buf, ConsoleHandles{Stdin: stdin, Stdout: buf, Stderr: buf}, nil)
+
+		values, err := console.MultiSelect(context.Background(), ConsoleOptions{
+			Message: "Select the services:",
+			Options: options,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"worker", "api"}, values)
+		require.Contains(t, buf.String(), "mobile is not an allowed choice")
+	})
+
+	t.Run("default without prompting", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := NewConsole(true, false, false, buf, ConsoleHandles{Stdout: buf, Stderr: buf}, nil)
+
+		values, err := console.MultiSelect(context.Background(), ConsoleOptions{
+			Message:      "Select the services:",
+			Options:      options,
+			DefaultValue: []string{"web"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"web"}, values)
+	})
+}
@@ -0,0 +1,439 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+)
+
+// The methods of the notifications and requests written by a MachineConsole.
+const (
+	// A message for the user, params is a MachineMessage
+	MachineMethodMessage = "message"
+	// A stage of the command starting or ending, params is a MachineStage
+	MachineMethodStage = "stage"
+	// Output of the command or of the tools it runs, params is a MachineOutput
+	MachineMethodOutput = "output"
+	// A progress event of a long running operation, params is a progress.Event
+	MachineMethodProgress = "progress"
+	// The result of the command, the last notification written, params is a MachineResult
+	MachineMethodResult = "result"
+	// A request for the client to answer a prompt, params is a MachinePrompt
+	MachineMethodPrompt = "prompt"
+)
+
+// The states of a MachineStage.
+const (
+	MachineStageStarted = "started"
+	MachineStageDone    = "done"
+	MachineStageFailed  = "failed"
+	MachineStageWarning = "warning"
+	MachineStageSkipped = "skipped"
+)
+
+// The kinds of a MachinePrompt, which define the type of the answer.
+const (
+	// The answer is a string
+	MachinePromptText = "text"
+	// The answer is a string, which the client shouldn't echo
+	MachinePromptPassword = "password"
+	// The answer is the index of the selected option
+	MachinePromptSelect = "select"
+	// The answer is the array of the selected options
+	MachinePromptMultiSelect = "multiSelect"
+	// The answer is a boolean
+	MachinePromptConfirm = "confirm"
+)
+
+// MachineMessage are the params of a message notification.
+type MachineMessage struct {
+	Message string `json:"message"`
+	// The structured item the message was formatted from, if any
+	Item ux.UxItem `json:"item,omitempty"`
+}
+
+// MachineStage are the params of a stage notification.
+type MachineStage struct {
+	Title string `json:"title"`
+	State string `json:"state"`
+}
+
+// MachineOutput are the params of an output notification.
+type MachineOutput struct {
+	Text string `json:"text"`
+}
+
+// MachineResult are the params of the result notification.
+type MachineResult struct {
+	Message  string `json:"message,omitempty"`
+	FollowUp string `json:"followUp,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// The category of the error, see the exitcode package
+	Category string `json:"category,omitempty"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// MachinePrompt are the params of a prompt request.
+type MachinePrompt struct {
+	Kind         string   `json:"kind"`
+	Message      string   `json:"message"`
+	Help         string   `json:"help,omitempty"`
+	Options      []string `json:"options,omitempty"`
+	DefaultValue any      `json:"default,omitempty"`
+	// Why the previous answer to the prompt was rejected, the prompt is requested again with the same id
+	Error string `json:"error,omitempty"`
+}
+
+type machineRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	Id      *int   `json:"id,omitempty"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type machineResponse struct {
+	Id     *int            `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ErrMachineClientClosed is returned by prompts when the client closed stdin instead of answering them.
+var ErrMachineClientClosed = errors.New("the client closed the input stream")
+
+// MachineConsole is the console used with --machine, for IDEs and other tools embedding azd. It writes newline
+// delimited JSON-RPC 2.0 notifications to stdout instead of human formatted text, and sends prompts as requests the
+// client answers with a response on stdin.
+type MachineConsole struct {
+	handles   ConsoleHandles
+	noPrompt  bool
+	formatter output.Formatter
+	reader    *bufio.Reader
+	// the stream the protocol is written to
+	protocol io.Writer
+	// the writer wrapping what is written to it in output notifications, and what we reset to when SetWriter(nil) is
+	// called.
+	output io.Writer
+	writer io.Writer
+	// the title of the running stage, empty when there is none
+	stage string
+	// the id of the last prompt request
+	lastId int
+	lock   sync.Mutex
+}
+
+// NewMachineConsole creates a console writing the protocol to the stdout of handles and reading the answers to prompts
+// from its stdin. When noPrompt is set, prompts are answered with their default value instead.
+func NewMachineConsole(noPrompt bool, handles ConsoleHandles, formatter output.Formatter) *MachineConsole {
+	c := &MachineConsole{
+		noPrompt:  noPrompt,
+		formatter: formatter,
+		reader:    bufio.NewReader(handles.Stdin),
+		protocol:  handles.Stdout,
+	}
+
+	// What commands and the tools they run write to stdout is wrapped in output notifications, so the client only
+	// reads the protocol from stdout
+	c.output = &machineOutputWriter{console: c}
+	c.writer = c.output
+	c.handles = ConsoleHandles{
+		Stdin:  handles.Stdin,
+		Stdout: c.output,
+		Stderr: handles.Stderr,
+	}
+
+	return c
+}
+
+// Notify writes a notification with the method and its params.
+func (c *MachineConsole) Notify(ctx context.Context, method string, params any) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.write(machineRequest{JsonRpc: "2.0", Method: method, Params: params})
+}
+
+func (c *MachineConsole) write(request machineRequest) {
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		log.Printf("failed marshalling the '%s' notification: %v", request.Method, err)
+		return
+	}
+
+	fmt.Fprintln(c.protocol, string(jsonRequest))
+}
+
+func (c *MachineConsole) Message(ctx context.Context, message string) {
+	c.Notify(ctx, MachineMethodMessage, MachineMessage{Message: message})
+}
+
+func (c *MachineConsole) MessageUxItem(ctx context.Context, item ux.UxItem) {
+	c.Notify(ctx, MachineMethodMessage, MachineMessage{Message: item.ToString(""), Item: item})
+}
+
+// ShowSpinner notifies the start of a stage. The stage isn't notified again while its title is unchanged.
+func (c *MachineConsole) ShowSpinner(ctx context.Context, title string, format SpinnerUxType) {
+	if title == c.stage {
+		return
+	}
+
+	c.stage = title
+	c.Notify(ctx, MachineMethodStage, MachineStage{Title: title, State: MachineStageStarted})
+}
+
+// StopSpinner notifies the end of the running stage, with the state matching format.
+func (c *MachineConsole) StopSpinner(ctx context.Context, lastMessage string, format SpinnerUxType) {
+	title := lastMessage
+	if title == "" {
+		title = c.stage
+	}
+	c.stage = ""
+
+	if title == "" {
+		return
+	}
+
+	c.Notify(ctx, MachineMethodStage, MachineStage{Title: title, State: machineStageState(format)})
+}
+
+func machineStageState(format SpinnerUxType) string {
+	switch format {
+	case StepDone:
+		return MachineStageDone
+	case StepFailed:
+		return MachineStageFailed
+	case StepWarning:
+		return MachineStageWarning
+	case StepSkipped:
+		return MachineStageSkipped
+	default:
+		return MachineStageDone
+	}
+}
+
+func (c *MachineConsole) IsSpinnerRunning(ctx context.Context) bool {
+	return c.stage != ""
+}
+
+// Prompt requests a string from the client. The prompt is requested again while the answer is invalid.
+func (c *MachineConsole) Prompt(ctx context.Context, options ConsoleOptions) (string, error) {
+	kind := MachinePromptText
+	if options.IsPassword {
+		kind = MachinePromptPassword
+	}
+
+	prompt := MachinePrompt{
+		Kind:         kind,
+		Message:      options.Message,
+		Help:         options.Help,
+		DefaultValue: options.DefaultValue,
+	}
+	if options.IsPassword {
+		prompt.DefaultValue = nil
+	}
+
+	if c.noPrompt {
+		value, _ := options.DefaultValue.(string)
+		if options.Validate != nil {
+			if err := options.Validate(value); err != nil {
+				return "", fmt.Errorf("invalid response for prompt '%s': %w", options.Message, err)
+			}
+		}
+
+		return value, nil
+	}
+
+	id := c.nextId()
+	for {
+		var response string
+		if err := c.request(ctx, id, prompt, &response); err != nil {
+			return "", err
+		}
+
+		if options.Validate == nil {
+			return response, nil
+		}
+
+		err := options.Validate(response)
+		if err == nil {
+			return response, nil
+		}
+
+		prompt.Error = err.Error()
+	}
+}
+
+// Select requests the index of the selected option from the client.
+func (c *MachineConsole) Select(ctx context.Context, options ConsoleOptions) (int, error) {
+	if c.noPrompt {
+		for i, option := range options.Options {
+			if option == options.DefaultValue {
+				return i, nil
+			}
+		}
+
+		return -1, fmt.Errorf("no default response for prompt '%s'", options.Message)
+	}
+
+	prompt := MachinePrompt{
+		Kind:         MachinePromptSelect,
+		Message:      options.Message,
+		Help:         options.Help,
+		Options:      options.Options,
+		DefaultValue: options.DefaultValue,
+	}
+
+	id := c.nextId()
+	for {
+		var response int
+		if err := c.request(ctx, id, prompt, &response); err != nil {
+			return -1, err
+		}
+
+		if response >= 0 && response < len(options.Options) {
+			return response, nil
+		}
+
+		prompt.Error = fmt.Sprintf("%d is not the index of an option", response)
+	}
+}
+
+// MultiSelect requests the selected options from the client.
+func (c *MachineConsole) MultiSelect(ctx context.Context, options ConsoleOptions) ([]string, error) {
+	defaultValue, _ := options.DefaultValue.([]string)
+	if c.noPrompt {
+		return defaultValue, nil
+	}
+
+	prompt := MachinePrompt{
+		Kind:         MachinePromptMultiSelect,
+		Message:      options.Message,
+		Help:         options.Help,
+		Options:      options.Options,
+		DefaultValue: defaultValue,
+	}
+
+	var response []string
+	if err := c.request(ctx, c.nextId(), prompt, &response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Confirm requests the confirmation of an operation from the client.
+func (c *MachineConsole) Confirm(ctx context.Context, options ConsoleOptions) (bool, error) {
+	defaultValue, _ := options.DefaultValue.(bool)
+	if c.noPrompt {
+		return defaultValue, nil
+	}
+
+	prompt := MachinePrompt{
+		Kind:         MachinePromptConfirm,
+		Message:      options.Message,
+		Help:         options.Help,
+		DefaultValue: defaultValue,
+	}
+
+	var response bool
+	if err := c.request(ctx, c.nextId(), prompt, &response); err != nil {
+		return false, err
+	}
+
+	return response, nil
+}
+
+func (c *MachineConsole) nextId() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.lastId++
+	return c.lastId
+}
+
+// request writes the prompt request and reads lines from stdin until the response with the same id, which is
+// unmarshalled into result. Responses to other requests are ignored.
+func (c *MachineConsole) request(ctx context.Context, id int, prompt MachinePrompt, result any) error {
+	c.lock.Lock()
+	c.write(machineRequest{JsonRpc: "2.0", Id: &id, Method: MachineMethodPrompt, Params: prompt})
+	c.lock.Unlock()
+
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			return fmt.Errorf("answering prompt '%s': %w", prompt.Message, ErrMachineClientClosed)
+		} else if len(line) == 0 && err != nil {
+			return fmt.Errorf("reading the answer to prompt '%s': %w", prompt.Message, err)
+		}
+
+		var response machineResponse
+		if err := json.Unmarshal(line, &response); err != nil {
+			log.Printf("ignoring invalid response '%s': %v", string(line), err)
+			continue
+		}
+
+		if response.Id == nil || *response.Id != id {
+			log.Printf("ignoring response '%s' to another request", string(line))
+			continue
+		}
+
+		if response.Error != nil {
+			return fmt.Errorf("prompt '%s' was cancelled: %s", prompt.Message, response.Error.Message)
+		}
+
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("invalid answer to prompt '%s': %w", prompt.Message, err)
+		}
+
+		return nil
+	}
+}
+
+// Sets the underlying writer for output the console or
+// if writer is nil, sets it back to the writer of output notifications.
+func (c *MachineConsole) SetWriter(writer io.Writer) {
+	if writer == nil {
+		writer = c.output
+	}
+
+	c.writer = writer
+}
+
+func (c *MachineConsole) GetWriter() io.Writer {
+	return c.writer
+}
+
+// Handles returns the streams of the console. What is written to Stdout is wrapped in output notifications.
+func (c *MachineConsole) Handles() ConsoleHandles {
+	return c.handles
+}
+
+func (c *MachineConsole) GetFormatter() output.Formatter {
+	return c.formatter
+}
+
+func (c *MachineConsole) IsUnformatted() bool {
+	return c.formatter == nil || c.formatter.Kind() == output.NoneFormat
+}
+
+// machineOutputWriter writes what is written to it as output notifications.
+type machineOutputWriter struct {
+	console *MachineConsole
+}
+
+func (w *machineOutputWriter) Write(p []byte) (int, error) {
+	w.console.Notify(context.Background(), MachineMethodOutput, MachineOutput{Text: string(p)})
+	return len(p), nil
+}
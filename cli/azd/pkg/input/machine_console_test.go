@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MachineConsole_Notifications(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	console := NewMachineConsole(false, ConsoleHandles{Stdin: strings.NewReader(""), Stdout: buf, Stderr: buf}, nil)
+
+	console.ShowSpinner(ctx, "Packaging service web", Step)
+	console.ShowSpinner(ctx, "Packaging service web", Step)
+	console.StopSpinner(ctx, "", StepDone)
+	console.Message(ctx, "Deployed")
+	fmt.Fprint(console.Handles().Stdout, "build output")
+
+	require.Equal(t, strings.Join([]string{
+		`{"jsonrpc":"2.0","method":"stage","params":{"title":"Packaging service web","state":"started"}}`,
+		`{"jsonrpc":"2.0","method":"stage","params":{"title":"Packaging service web","state":"done"}}`,
+		`{"jsonrpc":"2.0","method":"message","params":{"message":"Deployed"}}`,
+		`{"jsonrpc":"2.0","method":"output","params":{"text":"build output"}}`,
+		"",
+	}, "\n"), buf.String())
+}
+
+func Test_MachineConsole_Prompts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Prompt", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		// Responses to other requests and invalid answers are ignored or prompted again
+		stdin := strings.NewReader(strings.Join([]string{
+			`{"jsonrpc":"2.0","id":7,"result":"ignored"}`,
+			`{"jsonrpc":"2.0","id":1,"result":""}`,
+			`{"jsonrpc":"2.0","id":1,"result":"dev"}`,
+			"",
+		}, "\n"))
+		console := NewMachineConsole(false, ConsoleHandles{Stdin: stdin, Stdout: buf}, nil)
+
+		value, err := console.Prompt(ctx, ConsoleOptions{
+			Message: "Enter a new environment name:",
+			Validate: func(response string) error {
+				if response == "" {
+					return errors.New("the name is required")
+				}
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "dev", value)
+		require.Equal(t, strings.Join([]string{
+			`{"jsonrpc":"2.0","id":1,"method":"prompt","params":{"kind":"text","message":"Enter a new environment name:"}}`,
+			`{"jsonrpc":"2.0","id":1,"method":"prompt","params":{"kind":"text","message":"Enter a new environment name:",` +
+				`"error":"the name is required"}}`,
+			"",
+		}, "\n"), buf.String())
+	})
+
+	t.Run("Select", func(t *testing.T) {
+		stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"result":1}` + "\n")
+		console := NewMachineConsole(false, ConsoleHandles{Stdin: stdin, Stdout: &bytes.Buffer{}}, nil)
+
+		selected, err := console.Select(ctx, ConsoleOptions{
+			Message: "Select a location",
+			Options: []string{"eastus", "westus"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, selected)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		stdin := strings.NewReader(`{"jsonrpc":"2.0","id":1,"error":{"code":-32800,"message":"closed"}}` + "\n")
+		console := NewMachineConsole(false, ConsoleHandles{Stdin: stdin, Stdout: &bytes.Buffer{}}, nil)
+
+		_, err := console.Confirm(ctx, ConsoleOptions{Message: "Delete the resources?"})
+		require.Error(t, err)
+	})
+
+	t.Run("ClientClosed", func(t *testing.T) {
+		console := NewMachineConsole(
+			false, ConsoleHandles{Stdin: strings.NewReader(""), Stdout: &bytes.Buffer{}}, nil)
+
+		_, err := console.Confirm(ctx, ConsoleOptions{Message: "Delete the resources?"})
+		require.ErrorIs(t, err, ErrMachineClientClosed)
+	})
+
+	t.Run("NoPrompt", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		console := NewMachineConsole(true, ConsoleHandles{Stdin: strings.NewReader(""), Stdout: buf}, nil)
+
+		confirmed, err := console.Confirm(ctx, ConsoleOptions{Message: "Continue?", DefaultValue: true})
+		require.NoError(t, err)
+		require.True(t, confirmed)
+		require.Empty(t, buf.String())
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package locale provides the message catalog used to localize user facing messages (prompts, progress and errors).
+//
+// Messages are looked up by MessageId in the catalog of the detected locale, falling back to the language of the
+// locale (ex: pt-BR -> pt) and finally to the source English catalog. Catalogs are YAML files embedded from
+// resources/locales.
+package locale
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/resources"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvVarName is the environment variable that overrides the locale detected from the operating system.
+	EnvVarName = "AZD_LOCALE"
+
+	// DefaultLocale is the locale of the source catalog, used when no translation is available.
+	DefaultLocale = "en"
+
+	catalogsDir = "locales"
+)
+
+// The environment variables inspected, in order of precedence, to detect the locale of the user. This follows the
+// POSIX conventions for LC_ALL, LC_MESSAGES and LANG.
+var localeEnvVars = []string{EnvVarName, "LC_ALL", "LC_MESSAGES", "LANG"}
+
+// Detect returns the locale of the user from the environment, normalized to a BCP 47 like tag (ex: pt-BR). The
+// DefaultLocale is returned when no locale is configured.
+func Detect(getenv func(string) string) string {
+	for _, name := range localeEnvVars {
+		if value := normalize(getenv(name)); value != "" {
+			return value
+		}
+	}
+
+	return DefaultLocale
+}
+
+// normalize converts POSIX locale names (ex: pt_BR.UTF-8, de_DE@euro) to a tag like pt-BR. The C and POSIX locales
+// do not carry a language and are ignored.
+func normalize(value string) string {
+	if idx := strings.IndexAny(value, ".@"); idx >= 0 {
+		value = value[:idx]
+	}
+
+	value = strings.ReplaceAll(strings.TrimSpace(value), "_", "-")
+	if value == "" || value == "C" || value == "POSIX" {
+		return ""
+	}
+
+	parts := strings.SplitN(value, "-", 2)
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		parts[1] = strings.ToUpper(parts[1])
+	}
+
+	return strings.Join(parts, "-")
+}
+
+// Catalog resolves messages for a locale.
+type Catalog struct {
+	locale   string
+	messages []map[MessageId]string
+}
+
+// NewCatalog loads the catalogs for the specified locale from fsys. Messages are resolved from the most specific
+// catalog available, ex: pt-BR, then pt and finally the default catalog, which must exist.
+func NewCatalog(fsys fs.FS, locale string) (*Catalog, error) {
+	candidates := []string{locale}
+	if lang, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, lang)
+	}
+
+	catalog := &Catalog{
+		locale: DefaultLocale,
+	}
+
+	for _, candidate := range candidates {
+		if candidate == DefaultLocale {
+			break
+		}
+
+		messages, err := loadMessages(fsys, candidate)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if len(catalog.messages) == 0 {
+			catalog.locale = candidate
+		}
+		catalog.messages = append(catalog.messages, messages)
+	}
+
+	defaultMessages, err := loadMessages(fsys, DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+	catalog.messages = append(catalog.messages, defaultMessages)
+
+	return catalog, nil
+}
+
+func loadMessages(fsys fs.FS, locale string) (map[MessageId]string, error) {
+	contents, err := fs.ReadFile(fsys, path.Join(catalogsDir, locale+".yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("reading message catalog for locale '%s': %w", locale, err)
+	}
+
+	messages := map[MessageId]string{}
+	if err := yaml.Unmarshal(contents, &messages); err != nil {
+		return nil, fmt.Errorf("parsing message catalog for locale '%s': %w", locale, err)
+	}
+
+	return messages, nil
+}
+
+// Locale returns the locale of the most specific catalog loaded.
+func (c *Catalog) Locale() string {
+	return c.locale
+}
+
+// Sprintf formats the message identified by id with the specified arguments. The id itself is returned when the
+// message is not defined in any catalog, so a missing message never prevents azd from running.
+func (c *Catalog) Sprintf(id MessageId, args ...any) string {
+	for _, messages := range c.messages {
+		if format, has := messages[id]; has {
+			return fmt.Sprintf(format, args...)
+		}
+	}
+
+	log.Printf("message '%s' is not defined in the message catalog", id)
+	return string(id)
+}
+
+var (
+	defaultCatalog     *Catalog
+	defaultCatalogOnce sync.Once
+)
+
+// Default returns the catalog for the locale detected from the current process environment. A catalog of the locale
+// failing to load is logged and the catalog of the DefaultLocale is used instead, a broken translation never prevents
+// azd from running.
+func Default() *Catalog {
+	defaultCatalogOnce.Do(func() {
+		defaultCatalog = loadCatalog(resources.Locales, Detect(os.Getenv))
+	})
+
+	return defaultCatalog
+}
+
+// loadCatalog loads the catalog of the locale, falling back to the catalog of the DefaultLocale, and to an empty
+// catalog displaying message ids when even the default catalog fails to load.
+func loadCatalog(fsys fs.FS, locale string) *Catalog {
+	catalog, err := NewCatalog(fsys, locale)
+	if err == nil {
+		return catalog
+	}
+
+	log.Printf("loading message catalog for locale '%s', falling back to '%s': %v", locale, DefaultLocale, err)
+
+	catalog, err = NewCatalog(fsys, DefaultLocale)
+	if err == nil {
+		return catalog
+	}
+
+	log.Printf("loading message catalog for locale '%s': %v", DefaultLocale, err)
+	return &Catalog{locale: DefaultLocale}
+}
+
+// Sprintf formats the message identified by id using the catalog of the current locale.
+func Sprintf(id MessageId, args ...any) string {
+	return Default().Sprintf(id, args...)
+}
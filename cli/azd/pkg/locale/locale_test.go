@@ -0,0 +1,142 @@
+package locale
+
+import (
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/azure/azure-dev/cli/azd/resources"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Detect(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{"NoLocale", map[string]string{}, DefaultLocale},
+		{"Lang", map[string]string{"LANG": "pt_BR.UTF-8"}, "pt-BR"},
+		{"Modifier", map[string]string{"LANG": "de_DE@euro"}, "de-DE"},
+		{"PosixIgnored", map[string]string{"LC_ALL": "C", "LANG": "fr_FR.UTF-8"}, "fr-FR"},
+		{"LcAllOverridesLang", map[string]string{"LC_ALL": "es_ES.UTF-8", "LANG": "fr_FR.UTF-8"}, "es-ES"},
+		{"AzdLocaleOverrides", map[string]string{EnvVarName: "ja", "LC_ALL": "es_ES.UTF-8"}, "ja"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, Detect(func(name string) string {
+				return tt.env[name]
+			}))
+		})
+	}
+}
+
+func Test_Catalog(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.yaml": {Data: []byte(
+			"cmd.test.title: \"Testing\"\ncmd.test.step: \"Testing service %s\"\ncmd.test.success: \"Done!\"\n")},
+		"locales/pt.yaml":    {Data: []byte("cmd.test.title: \"Testando\"\ncmd.test.step: \"Testando serviço %s\"\n")},
+		"locales/pt-BR.yaml": {Data: []byte("cmd.test.title: \"Testando agora\"\n")},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		catalog, err := NewCatalog(fsys, "en-US")
+		require.NoError(t, err)
+		require.Equal(t, DefaultLocale, catalog.Locale())
+		require.Equal(t, "Testing service api", catalog.Sprintf("cmd.test.step", "api"))
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		catalog, err := NewCatalog(fsys, "pt-BR")
+		require.NoError(t, err)
+		require.Equal(t, "pt-BR", catalog.Locale())
+		require.Equal(t, "Testando agora", catalog.Sprintf("cmd.test.title"))
+		require.Equal(t, "Testando serviço api", catalog.Sprintf("cmd.test.step", "api"))
+		require.Equal(t, "Done!", catalog.Sprintf("cmd.test.success"))
+	})
+
+	t.Run("UnknownMessage", func(t *testing.T) {
+		catalog, err := NewCatalog(fsys, "fr")
+		require.NoError(t, err)
+		require.Equal(t, DefaultLocale, catalog.Locale())
+		require.Equal(t, "cmd.test.unknown", catalog.Sprintf("cmd.test.unknown"))
+	})
+}
+
+func Test_loadCatalog(t *testing.T) {
+	t.Run("InvalidTranslation", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/en.yaml": {Data: []byte("cmd.test.title: \"Testing\"\n")},
+			"locales/fr.yaml": {Data: []byte("cmd.test.title: [\n")},
+		}
+
+		catalog := loadCatalog(fsys, "fr-FR")
+		require.Equal(t, DefaultLocale, catalog.Locale())
+		require.Equal(t, "Testing", catalog.Sprintf("cmd.test.title"))
+	})
+
+	t.Run("NoCatalog", func(t *testing.T) {
+		catalog := loadCatalog(fstest.MapFS{}, "fr-FR")
+		require.Equal(t, DefaultLocale, catalog.Locale())
+		require.Equal(t, "cmd.test.title", catalog.Sprintf("cmd.test.title"))
+	})
+}
+
+var formatVerbRegex = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// Test_Catalogs validates the embedded catalogs: every message id used by azd is defined in the source catalog and
+// every translation only contains known messages, with the same format verbs as the source message.
+func Test_Catalogs(t *testing.T) {
+	source, err := loadMessages(resources.Locales, DefaultLocale)
+	require.NoError(t, err)
+
+	for _, id := range []MessageId{
+		BuildTitle, BuildStep, BuildSuccess,
+		DeployTitle, DeployStep, DeploySuccess,
+		DownTitle, DownTitleNote, DownFetching, DownSuccess,
+		DoctorTitle, DoctorTitleNote, DoctorRunning, DoctorNoIssues, DoctorIssues,
+		EnvRefreshTitle, EnvRefreshTitleNote,
+		InitTitle, InitSuccess, InitResourceGroupSuccess, InitBranchWithoutTemplate, InitTemplateAndResourceGroup,
+		InitAlreadyInitialized,
+		PackageTitle, PackageStep, PackageSuccess,
+		ProvisionTitle, ProvisionTitleNote, ProvisionSuccess,
+		RestoreTitle, RestoreStep, RestoreSuccess,
+		UpResuming, UpNoRun,
+		EnvironmentNamePrompt, EnvironmentNameInvalid, SubscriptionPrompt, LocationPrompt,
+		ServiceDisabled,
+	} {
+		require.Contains(t, source, id)
+	}
+
+	entries, err := fs.ReadDir(resources.Locales, catalogsDir)
+	require.NoError(t, err)
+
+	for _, entry := range entries {
+		if path.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+
+		locale := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		if locale == DefaultLocale {
+			continue
+		}
+
+		t.Run(locale, func(t *testing.T) {
+			messages, err := loadMessages(resources.Locales, locale)
+			require.NoError(t, err)
+
+			for id, message := range messages {
+				sourceMessage, has := source[id]
+				require.True(t, has, "message '%s' is not defined in the source catalog", id)
+				require.Equal(t,
+					formatVerbRegex.FindAllString(sourceMessage, -1),
+					formatVerbRegex.FindAllString(message, -1),
+					"message '%s' must use the same format verbs as the source catalog", id)
+			}
+		})
+	}
+}
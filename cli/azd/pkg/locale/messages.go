@@ -0,0 +1,60 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package locale
+
+// MessageId identifies a message in the message catalogs. Every id must be defined in the source (en) catalog.
+type MessageId string
+
+const (
+	BuildTitle   MessageId = "cmd.build.title"
+	BuildStep    MessageId = "cmd.build.step"
+	BuildSuccess MessageId = "cmd.build.success"
+
+	DeployTitle   MessageId = "cmd.deploy.title"
+	DeployStep    MessageId = "cmd.deploy.step"
+	DeploySuccess MessageId = "cmd.deploy.success"
+
+	DownTitle     MessageId = "cmd.down.title"
+	DownTitleNote MessageId = "cmd.down.titleNote"
+	DownFetching  MessageId = "cmd.down.fetching"
+	DownSuccess   MessageId = "cmd.down.success"
+
+	DoctorTitle     MessageId = "cmd.doctor.title"
+	DoctorTitleNote MessageId = "cmd.doctor.titleNote"
+	DoctorRunning   MessageId = "cmd.doctor.running"
+	DoctorNoIssues  MessageId = "cmd.doctor.noIssues"
+	DoctorIssues    MessageId = "cmd.doctor.issues"
+
+	EnvRefreshTitle     MessageId = "cmd.env.refresh.title"
+	EnvRefreshTitleNote MessageId = "cmd.env.refresh.titleNote"
+
+	InitTitle                    MessageId = "cmd.init.title"
+	InitSuccess                  MessageId = "cmd.init.success"
+	InitResourceGroupSuccess     MessageId = "cmd.init.resourceGroupSuccess"
+	InitBranchWithoutTemplate    MessageId = "cmd.init.branchWithoutTemplate"
+	InitTemplateAndResourceGroup MessageId = "cmd.init.templateAndResourceGroup"
+	InitAlreadyInitialized       MessageId = "cmd.init.alreadyInitialized"
+
+	PackageTitle   MessageId = "cmd.package.title"
+	PackageStep    MessageId = "cmd.package.step"
+	PackageSuccess MessageId = "cmd.package.success"
+
+	ProvisionTitle     MessageId = "cmd.provision.title"
+	ProvisionTitleNote MessageId = "cmd.provision.titleNote"
+	ProvisionSuccess   MessageId = "cmd.provision.success"
+
+	RestoreTitle   MessageId = "cmd.restore.title"
+	RestoreStep    MessageId = "cmd.restore.step"
+	RestoreSuccess MessageId = "cmd.restore.success"
+
+	UpResuming MessageId = "cmd.up.resuming"
+	UpNoRun    MessageId = "cmd.up.noRun"
+
+	EnvironmentNamePrompt  MessageId = "prompt.environmentName"
+	EnvironmentNameInvalid MessageId = "error.environmentNameInvalid"
+	SubscriptionPrompt     MessageId = "prompt.subscription"
+	LocationPrompt         MessageId = "prompt.location"
+
+	ServiceDisabled MessageId = "service.disabled"
+)
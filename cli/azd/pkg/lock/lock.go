@@ -0,0 +1,197 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package lock provides the lock of an environment, held by the operations changing it, like provisioning and deploying,
+// so two operations can't change the same environment concurrently and corrupt its state. The lock is a file stored with
+// the environment.
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// FileName is the name of the lock file, in the directory of the environment.
+const FileName = "azd.lock"
+
+// ErrLocked is returned when the lock is held by another operation.
+var ErrLocked = errors.New("environment is locked")
+
+// the number of times Acquire tries to create the lock file, when the lock is released by other operations between the
+// attempts
+const maxAcquireAttempts = 5
+
+// Holder describes the operation holding the lock.
+type Holder struct {
+	// The command which holds the lock, ex) azd provision
+	Command string `json:"command"`
+	// The user of the machine the command runs on.
+	User string `json:"user,omitempty"`
+	// The machine the command runs on.
+	Host string `json:"host,omitempty"`
+	// The process of the command.
+	Pid        int       `json:"pid"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+}
+
+// NewHolder returns the holder of the lock for the command, run by the current process.
+func NewHolder(command string) Holder {
+	holder := Holder{
+		Command:    command,
+		Pid:        os.Getpid(),
+		AcquiredAt: time.Now().UTC(),
+	}
+	if currentUser, err := user.Current(); err == nil {
+		holder.User = currentUser.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		holder.Host = host
+	}
+
+	return holder
+}
+
+func (h *Holder) String() string {
+	description := fmt.Sprintf("'%s'", h.Command)
+	if h.User != "" {
+		description += fmt.Sprintf(" run by %s", h.User)
+	}
+	if h.Host != "" {
+		description += fmt.Sprintf(" on %s", h.Host)
+	}
+
+	return fmt.Sprintf("%s (pid %d) since %s", description, h.Pid, h.AcquiredAt.Local().Format(time.RFC1123))
+}
+
+// LockedError is returned when the lock is held by another operation.
+type LockedError struct {
+	// The operation holding the lock.
+	Holder Holder
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s by %s", ErrLocked.Error(), e.Holder.String())
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+// Lock is the lock of an environment.
+type Lock struct {
+	path string
+}
+
+// New returns the lock of the environment stored in envRoot.
+func New(envRoot string) *Lock {
+	return &Lock{
+		path: filepath.Join(envRoot, FileName),
+	}
+}
+
+// Acquire acquires the lock for holder, or returns a *LockedError when it's held by another operation. The function
+// returned releases the lock.
+func (l *Lock) Acquire(holder Holder) (func() error, error) {
+	content, err := json.Marshal(holder)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling lock holder: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), osutil.PermissionDirectory); err != nil {
+		return nil, fmt.Errorf("creating environment directory: %w", err)
+	}
+
+	// The holder is written to a temporary file first, so the lock file never exists without its holder
+	temp, err := os.CreateTemp(filepath.Dir(l.path), FileName+".*")
+	if err != nil {
+		return nil, fmt.Errorf("creating lock file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return nil, fmt.Errorf("writing lock file: %w", err)
+	}
+
+	if err := temp.Close(); err != nil {
+		return nil, fmt.Errorf("writing lock file: %w", err)
+	}
+
+	acquired := false
+	for attempt := 0; attempt < maxAcquireAttempts; attempt++ {
+		// Linking the file fails when the lock file exists, which makes acquiring the lock atomic
+		err := os.Link(temp.Name(), l.path)
+		if err == nil {
+			acquired = true
+			break
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("creating lock file: %w", err)
+		}
+
+		current, err := l.Holder()
+		if err != nil {
+			return nil, err
+		}
+
+		// The lock was released in the meantime when there's no holder, the next attempt acquires it
+		if current != nil {
+			return nil, &LockedError{Holder: *current}
+		}
+	}
+
+	if !acquired {
+		return nil, fmt.Errorf("%w: the lock was acquired and released by other operations while acquiring it", ErrLocked)
+	}
+
+	return func() error {
+		current, err := l.Holder()
+		if err != nil {
+			return err
+		}
+
+		// The lock may have been removed with azd env unlock --force, and acquired by another operation since
+		if current == nil || current.Pid != holder.Pid || !current.AcquiredAt.Equal(holder.AcquiredAt) {
+			return nil
+		}
+
+		return l.Remove()
+	}, nil
+}
+
+// Holder returns the operation holding the lock, or nil when the lock isn't held. A lock file which can't be parsed
+// is held by an unknown operation.
+func (l *Lock) Holder() (*Holder, error) {
+	content, err := os.ReadFile(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lock file: %w", err)
+	}
+
+	var holder Holder
+	if err := json.Unmarshal(content, &holder); err != nil {
+		return &Holder{Command: "unknown"}, nil
+	}
+
+	return &holder, nil
+}
+
+// Remove removes the lock, whichever operation holds it. It's used to remove a lock left by an operation which didn't
+// complete, like a process which was killed.
+func (l *Lock) Remove() error {
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing lock file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package lock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	envRoot := filepath.Join(t.TempDir(), "dev")
+	lock := New(envRoot)
+
+	holder, err := lock.Holder()
+	require.NoError(t, err)
+	require.Nil(t, holder)
+
+	provision := Holder{Command: "azd provision", User: "alice", Pid: 1, AcquiredAt: time.Now().UTC()}
+	release, err := lock.Acquire(provision)
+	require.NoError(t, err)
+
+	holder, err = lock.Holder()
+	require.NoError(t, err)
+	require.Equal(t, "azd provision", holder.Command)
+
+	// The temporary file holding the holder is removed once the lock is acquired
+	entries, err := os.ReadDir(envRoot)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, FileName, entries[0].Name())
+
+	_, err = lock.Acquire(Holder{Command: "azd deploy", Pid: 2, AcquiredAt: time.Now().UTC()})
+	var lockedErr *LockedError
+	require.True(t, errors.As(err, &lockedErr))
+	require.True(t, errors.Is(err, ErrLocked))
+	require.Equal(t, "alice", lockedErr.Holder.User)
+
+	require.NoError(t, release())
+	holder, err = lock.Holder()
+	require.NoError(t, err)
+	require.Nil(t, holder)
+}
+
+func TestLockRemove(t *testing.T) {
+	lock := New(t.TempDir())
+
+	release, err := lock.Acquire(Holder{Command: "azd provision", Pid: 1, AcquiredAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	// A stale lock is removed, and acquired by another operation
+	require.NoError(t, lock.Remove())
+	_, err = lock.Acquire(Holder{Command: "azd deploy", Pid: 2, AcquiredAt: time.Now().UTC()})
+	require.NoError(t, err)
+
+	// Releasing the stale lock leaves the lock of the other operation
+	require.NoError(t, release())
+	holder, err := lock.Holder()
+	require.NoError(t, err)
+	require.Equal(t, "azd deploy", holder.Command)
+}
+
+func TestLockUnknownHolder(t *testing.T) {
+	envRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(envRoot, FileName), []byte("{"), 0600))
+
+	_, err := New(envRoot).Acquire(Holder{Command: "azd deploy", Pid: 2, AcquiredAt: time.Now().UTC()})
+	require.True(t, errors.Is(err, ErrLocked))
+}
+
+func TestNewHolder(t *testing.T) {
+	holder := NewHolder("azd deploy")
+
+	require.Equal(t, "azd deploy", holder.Command)
+	require.Equal(t, os.Getpid(), holder.Pid)
+	require.False(t, holder.AcquiredAt.IsZero())
+}
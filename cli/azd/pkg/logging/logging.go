@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package logging captures the detailed logs of every azd invocation to a log file, independently of the --debug flag,
+// so logs of a failed operation can be shared after the fact.
+//
+// Each invocation writes to its own file in the logs directory of the azd user configuration directory. The number of
+// files kept and the size of each file are bounded, the oldest files are removed when a new file is created.
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+const (
+	// DefaultMaxFiles is the number of log files kept, including the file of the current invocation.
+	DefaultMaxFiles = 20
+
+	// DefaultMaxSize is the maximum size, in bytes, of a single log file. Logs written past this size are dropped.
+	DefaultMaxSize = 10 * 1024 * 1024
+
+	logsDir       = "logs"
+	logFilePrefix = "azd-"
+	logFileSuffix = ".log"
+
+	// The timestamp in the file names sorts in chronological order.
+	timestampFormat = "20060102T150405.000"
+)
+
+// ErrNoLogs is returned when no log file has been written yet.
+var ErrNoLogs = errors.New("no log files found")
+
+// Dir returns the directory where log files are written.
+func Dir() (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, logsDir), nil
+}
+
+// File is the log file of an invocation. Writes past the maximum size are dropped, a single marker is written to
+// indicate that the log has been truncated.
+type File struct {
+	path    string
+	maxSize int64
+
+	mu        sync.Mutex
+	file      *os.File
+	size      int64
+	truncated bool
+}
+
+var (
+	currentMu sync.Mutex
+	current   string
+)
+
+// Open creates the log file of the current invocation in dir, and removes the oldest log files so at most maxFiles
+// files are kept.
+func Open(dir string, maxFiles int, maxSize int64, now time.Time) (*File, error) {
+	if err := os.MkdirAll(dir, osutil.PermissionDirectoryOwnerOnly); err != nil {
+		return nil, fmt.Errorf("creating logs directory: %w", err)
+	}
+
+	if err := rotate(dir, maxFiles-1); err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s%s-%d%s", logFilePrefix, now.Format(timestampFormat), os.Getpid(), logFileSuffix)
+	path := filepath.Join(dir, name)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, osutil.PermissionFileOwnerOnly)
+	if err != nil {
+		return nil, fmt.Errorf("creating log file: %w", err)
+	}
+
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = path
+
+	return &File{
+		path:    path,
+		maxSize: maxSize,
+		file:    file,
+	}, nil
+}
+
+// Path returns the path of the log file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// Write writes p to the log file, until the maximum size of the file is reached.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.truncated {
+		return len(p), nil
+	}
+
+	if f.size+int64(len(p)) > f.maxSize {
+		f.truncated = true
+		_, err := f.file.WriteString("\n... log truncated, the maximum size of the log file has been reached\n")
+		return len(p), err
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the log file.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
+
+// List returns the paths of the log files in dir, most recent first. The log file of the current invocation is
+// excluded, so commands inspecting the logs return the logs of the previous invocations.
+func List(dir string) ([]string, error) {
+	files, err := listAll(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	result := make([]string, 0, len(files))
+	for i := len(files) - 1; i >= 0; i-- {
+		if files[i] != current {
+			result = append(result, files[i])
+		}
+	}
+
+	return result, nil
+}
+
+// Latest returns the path of the most recent log file of a previous invocation, or ErrNoLogs.
+func Latest(dir string) (string, error) {
+	files, err := List(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(files) == 0 {
+		return "", ErrNoLogs
+	}
+
+	return files[0], nil
+}
+
+// listAll returns the paths of the log files in dir, oldest first.
+func listAll(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading logs directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, logFilePrefix) || !strings.HasSuffix(name, logFileSuffix) {
+			continue
+		}
+
+		files = append(files, filepath.Join(dir, name))
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// rotate removes the oldest log files in dir so at most keep files remain.
+func rotate(dir string, keep int) error {
+	files, err := listAll(dir)
+	if err != nil {
+		return err
+	}
+
+	for len(files) > keep && len(files) > 0 {
+		if err := os.Remove(files[0]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing old log file: %w", err)
+		}
+
+		files = files[1:]
+	}
+
+	return nil
+}
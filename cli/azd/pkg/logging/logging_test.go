@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Open_Rotates(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var paths []string
+	for i := 0; i < 5; i++ {
+		file, err := Open(dir, 3, DefaultMaxSize, start.Add(time.Duration(i)*time.Second))
+		require.NoError(t, err)
+		_, err = fmt.Fprintf(file, "invocation %d\n", i)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		paths = append(paths, file.Path())
+	}
+
+	all, err := listAll(dir)
+	require.NoError(t, err)
+	require.Equal(t, paths[2:], all)
+
+	// The log file of the current invocation is excluded
+	files, err := List(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{paths[3], paths[2]}, files)
+
+	latest, err := Latest(dir)
+	require.NoError(t, err)
+	require.Equal(t, paths[3], latest)
+}
+
+func Test_Latest_NoLogs(t *testing.T) {
+	_, err := Latest(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorIs(t, err, ErrNoLogs)
+}
+
+func Test_File_MaxSize(t *testing.T) {
+	file, err := Open(t.TempDir(), DefaultMaxFiles, 10, time.Now())
+	require.NoError(t, err)
+
+	_, err = file.Write([]byte("0123456789"))
+	require.NoError(t, err)
+
+	// Writes past the maximum size are dropped without failing the caller
+	n, err := file.Write([]byte("dropped"))
+	require.NoError(t, err)
+	require.Equal(t, 7, n)
+
+	_, err = file.Write([]byte("dropped"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	contents, err := os.ReadFile(file.Path())
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "0123456789")
+	require.NotContains(t, string(contents), "dropped")
+	require.Contains(t, string(contents), "log truncated")
+}
@@ -44,3 +44,14 @@ func WithUnderline(text string, a ...interface{}) string {
 func WithBackticks(text string) string {
 	return "`" + text + "`"
 }
+
+// IsPlainRequested returns true when the environment requests output without colors or other ANSI control
+// sequences, either with NO_COLOR (see https://no-color.org) or a dumb terminal.
+func IsPlainRequested(getenv func(string) string) bool {
+	return getenv("NO_COLOR") != "" || getenv("TERM") == "dumb"
+}
+
+// DisableColors disables the colors of all the output formatted by this package.
+func DisableColors() {
+	color.NoColor = true
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter writes each line to the underlying writer preceded by a prefix. Partial lines are buffered until
+// the line is complete or the writer is flushed. Writers that share an underlying writer should share the same
+// lock so the lines written concurrently are not interleaved. PrefixWriter is safe for concurrent use.
+type PrefixWriter struct {
+	writer io.Writer
+	prefix string
+	lock   *sync.Mutex
+	buffer []byte
+}
+
+// NewPrefixWriter creates a PrefixWriter which guards the writes to writer with lock.
+func NewPrefixWriter(writer io.Writer, prefix string, lock *sync.Mutex) *PrefixWriter {
+	return &PrefixWriter{
+		writer: writer,
+		prefix: prefix,
+		lock:   lock,
+	}
+}
+
+func (pw *PrefixWriter) Write(p []byte) (int, error) {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	pw.buffer = append(pw.buffer, p...)
+
+	for {
+		i := bytes.IndexByte(pw.buffer, '\n')
+		if i < 0 {
+			break
+		}
+
+		if err := pw.writeLine(pw.buffer[:i+1]); err != nil {
+			return 0, err
+		}
+
+		pw.buffer = pw.buffer[i+1:]
+	}
+
+	// Compact the remaining partial line so the buffer does not keep growing for long running processes
+	pw.buffer = append([]byte(nil), pw.buffer...)
+
+	return len(p), nil
+}
+
+// Flush writes the buffered partial line, if any, terminated with a new line.
+func (pw *PrefixWriter) Flush() error {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+
+	if len(pw.buffer) == 0 {
+		return nil
+	}
+
+	line := append(pw.buffer, '\n')
+	pw.buffer = nil
+
+	return pw.writeLine(line)
+}
+
+func (pw *PrefixWriter) writeLine(line []byte) error {
+	if _, err := io.WriteString(pw.writer, pw.prefix); err != nil {
+		return err
+	}
+
+	_, err := pw.writer.Write(line)
+	return err
+}
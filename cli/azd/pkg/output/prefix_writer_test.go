@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package output
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	lock := &sync.Mutex{}
+
+	api := NewPrefixWriter(buf, "api | ", lock)
+	web := NewPrefixWriter(buf, "web | ", lock)
+
+	_, err := api.Write([]byte("listening"))
+	require.NoError(t, err)
+	_, err = web.Write([]byte("ready\ncompiled\n"))
+	require.NoError(t, err)
+	_, err = api.Write([]byte(" on 8080\nrequest"))
+	require.NoError(t, err)
+
+	require.NoError(t, api.Flush())
+	require.NoError(t, web.Flush())
+
+	require.Equal(t, "web | ready\nweb | compiled\napi | listening on 8080\napi | request\n", buf.String())
+}
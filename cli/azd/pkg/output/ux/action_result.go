@@ -14,11 +14,17 @@ type ActionResult struct {
 	SuccessMessage string
 	FollowUp       string
 	Err            error
+	// The correlation ID of the requests sent by the failed action, printed after the error to share it with support
+	CorrelationId string
 }
 
 func (ar *ActionResult) ToString(currentIndentation string) (result string) {
 	if ar.Err != nil {
-		return output.WithErrorFormat("\n%s: %s", "ERROR", ar.Err.Error())
+		result = output.WithErrorFormat("\n%s: %s", "ERROR", ar.Err.Error())
+		if ar.CorrelationId != "" {
+			result += output.WithGrayFormat("\nCorrelation ID: %s", ar.CorrelationId)
+		}
+		return result
 	}
 	if ar.SuccessMessage != "" {
 		result = output.WithSuccessFormat("\n%s: %s", "SUCCESS", ar.SuccessMessage)
@@ -39,6 +39,15 @@ func TestActionResult_ToString(t *testing.T) {
 			},
 			expected: output.WithErrorFormat("\n%s: %s", "ERROR", "An error :("),
 		},
+		{
+			name: "ErrorWithCorrelationId",
+			ar: &ActionResult{
+				Err:           errors.New("An error :("),
+				CorrelationId: "CORRELATION_ID",
+			},
+			expected: output.WithErrorFormat("\n%s: %s", "ERROR", "An error :(") +
+				output.WithGrayFormat("\nCorrelation ID: %s", "CORRELATION_ID"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package profiling helps find out where azd spends its time, with pprof profiles of the azd process and a report of
+// the duration of the slow stages of provision and deploy (bicep build, ARM deployment, packaging and uploads).
+//
+// Both are enabled with hidden flags: `--profile cpu|mem` writes a profile to the current directory, and `--timings`
+// prints the duration of each stage when the command completes.
+package profiling
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+const (
+	// ProfileCpu samples the CPU usage of azd for the whole command.
+	ProfileCpu = "cpu"
+	// ProfileMem captures the heap allocations of azd at the end of the command.
+	ProfileMem = "mem"
+
+	// The timestamp in the file names sorts in chronological order.
+	timestampFormat = "20060102T150405"
+)
+
+// StartProfile starts a profile of the specified kind, written to a file in dir. The returned function stops the
+// profile and returns the path of the profile.
+func StartProfile(kind string, dir string, now time.Time) (stop func() (string, error), err error) {
+	if kind != ProfileCpu && kind != ProfileMem {
+		return nil, fmt.Errorf("invalid profile '%s', supported profiles are: %s, %s", kind, ProfileCpu, ProfileMem)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("azd-%s-%s.pprof", kind, now.Format(timestampFormat)))
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating profile: %w", err)
+	}
+
+	if kind == ProfileCpu {
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("starting cpu profile: %w", err)
+		}
+
+		return func() (string, error) {
+			pprof.StopCPUProfile()
+			return path, file.Close()
+		}, nil
+	}
+
+	return func() (string, error) {
+		defer file.Close()
+
+		// Collect garbage so the profile reflects live objects
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			return "", fmt.Errorf("writing heap profile: %w", err)
+		}
+
+		return path, file.Close()
+	}, nil
+}
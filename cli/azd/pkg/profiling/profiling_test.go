@@ -0,0 +1,63 @@
+package profiling
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StartProfile(t *testing.T) {
+	for _, kind := range []string{ProfileCpu, ProfileMem} {
+		t.Run(kind, func(t *testing.T) {
+			stop, err := StartProfile(kind, t.TempDir(), time.Now())
+			require.NoError(t, err)
+
+			path, err := stop()
+			require.NoError(t, err)
+
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			require.NotZero(t, info.Size())
+		})
+	}
+
+	_, err := StartProfile("block", t.TempDir(), time.Now())
+	require.Error(t, err)
+}
+
+func Test_TrackStage(t *testing.T) {
+	t.Cleanup(func() { recorder = &stageRecorder{} })
+
+	// Stages are not recorded until timings are enabled
+	TrackStage("ignored")()
+	require.Empty(t, Stages())
+
+	EnableTimings()
+	endOuter := TrackStage("arm deploy")
+	TrackStage("bicep build")()
+	endOuter()
+
+	stages := Stages()
+	require.Len(t, stages, 2)
+	require.Equal(t, "arm deploy", stages[0].Name)
+	require.Equal(t, "bicep build", stages[1].Name)
+}
+
+func Test_WriteTimingReport(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteTimingReport(&buf, []Stage{
+		{Name: "bicep build", Duration: 2 * time.Second},
+		{Name: "arm deploy", Duration: 6 * time.Second},
+	}, 10*time.Second)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		"STAGE        DURATION  SHARE\n"+
+			"bicep build  2s        20.0%\n"+
+			"arm deploy   6s        60.0%\n"+
+			"total        10s       \n",
+		buf.String())
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package profiling
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Stage is a timed stage of a command.
+type Stage struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+}
+
+type stageRecorder struct {
+	mu      sync.Mutex
+	enabled bool
+	stages  []Stage
+}
+
+// Stages are recorded process wide, services are packaged and deployed concurrently.
+var recorder = &stageRecorder{}
+
+// EnableTimings starts recording the duration of stages. Stages are not recorded otherwise.
+func EnableTimings() {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recorder.enabled = true
+}
+
+// TimingsEnabled returns true when the duration of stages is recorded.
+func TimingsEnabled() bool {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	return recorder.enabled
+}
+
+// TrackStage starts timing a stage, the returned function records the duration of the stage.
+func TrackStage(name string) (end func()) {
+	if !TimingsEnabled() {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+
+		recorder.stages = append(recorder.stages, Stage{
+			Name:     name,
+			Start:    start,
+			Duration: time.Since(start),
+		})
+	}
+}
+
+// Stages returns the recorded stages, ordered by start time.
+func Stages() []Stage {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	stages := make([]Stage, len(recorder.stages))
+	copy(stages, recorder.stages)
+
+	// Stages are recorded when they end, nested stages end before the stage containing them
+	sort.SliceStable(stages, func(i, j int) bool {
+		return stages[i].Start.Before(stages[j].Start)
+	})
+
+	return stages
+}
+
+// WriteTimingReport writes the duration of each stage, along with the share of the total duration of the command.
+func WriteTimingReport(w io.Writer, stages []Stage, total time.Duration) error {
+	tabs := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tabs, "STAGE\tDURATION\tSHARE")
+	for _, stage := range stages {
+		share := 0.0
+		if total > 0 {
+			share = float64(stage.Duration) / float64(total) * 100
+		}
+
+		fmt.Fprintf(tabs, "%s\t%s\t%.1f%%\n", stage.Name, stage.Duration.Round(time.Millisecond), share)
+	}
+	fmt.Fprintf(tabs, "total\t%s\t\n", total.Round(time.Millisecond))
+
+	return tabs.Flush()
+}
@@ -0,0 +1,153 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package progress provides an event bus used by long running operations (restore, build, package, deploy, ...) to
+// publish structured progress events. Events are dispatched to pluggable sinks which decide how the progress is
+// rendered, allowing the same operation to drive an interactive spinner, plain CI logs or a machine readable stream.
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// EventKind describes the transition of a step reported by an Event.
+type EventKind string
+
+const (
+	EventStarted   EventKind = "started"
+	EventProgress  EventKind = "progress"
+	EventCompleted EventKind = "completed"
+	EventFailed    EventKind = "failed"
+	EventSkipped   EventKind = "skipped"
+)
+
+// Event is a structured progress notification published by a long running operation.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      EventKind `json:"kind"`
+	// The operation the event belongs to, ex) deploy
+	Operation string `json:"operation"`
+	// The target of the operation, ex) the name of the service being deployed
+	Target string `json:"target,omitempty"`
+	// The human readable title of the step, ex) Deploying service api
+	Title string `json:"title"`
+	// Additional details for progress events
+	Message string `json:"message,omitempty"`
+	// The error message for failed events
+	Error string `json:"error,omitempty"`
+}
+
+// Sink receives the events published on a Bus.
+type Sink interface {
+	Handle(ctx context.Context, event Event)
+}
+
+// SinkFunc is a Sink implementation for regular functions.
+type SinkFunc func(ctx context.Context, event Event)
+
+// Handle implements the Sink interface
+func (f SinkFunc) Handle(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+// Bus dispatches published events to all subscribed sinks.
+// Events are delivered synchronously and in the order they were published.
+type Bus struct {
+	sinks []Sink
+	lock  sync.Mutex
+}
+
+// Creates a new Bus that dispatches events to the specified sinks
+func NewBus(sinks ...Sink) *Bus {
+	return &Bus{
+		sinks: sinks,
+	}
+}
+
+// Subscribe adds a sink that receives all events published after the call.
+func (b *Bus) Subscribe(sink Sink) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.sinks = append(b.sinks, sink)
+}
+
+// Publish dispatches the event to every subscribed sink. The event timestamp is set when not already specified.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, sink := range b.sinks {
+		sink.Handle(ctx, event)
+	}
+}
+
+// Step creates a helper used to publish the events for a single step of an operation.
+func (b *Bus) Step(operation string, target string, title string) *Step {
+	return &Step{
+		bus:       b,
+		operation: operation,
+		target:    target,
+		title:     title,
+	}
+}
+
+// Step publishes the lifecycle events of a single step of an operation.
+type Step struct {
+	bus       *Bus
+	operation string
+	target    string
+	title     string
+}
+
+// Start publishes an EventStarted event for the step.
+func (s *Step) Start(ctx context.Context) {
+	s.publish(ctx, EventStarted, "", nil)
+}
+
+// Progress publishes an EventProgress event with the specified message for the step.
+func (s *Step) Progress(ctx context.Context, message string) {
+	s.publish(ctx, EventProgress, message, nil)
+}
+
+// Complete publishes an EventCompleted event for the step.
+func (s *Step) Complete(ctx context.Context) {
+	s.publish(ctx, EventCompleted, "", nil)
+}
+
+// Fail publishes an EventFailed event with the specified error for the step.
+func (s *Step) Fail(ctx context.Context, err error) {
+	s.publish(ctx, EventFailed, "", err)
+}
+
+// Skip publishes an EventSkipped event for the step.
+func (s *Step) Skip(ctx context.Context) {
+	s.publish(ctx, EventSkipped, "", nil)
+}
+
+// SkipWithReason publishes an EventSkipped event with the reason the step is skipped, displayed to the user.
+func (s *Step) SkipWithReason(ctx context.Context, reason string) {
+	s.publish(ctx, EventSkipped, reason, nil)
+}
+
+func (s *Step) publish(ctx context.Context, kind EventKind, message string, err error) {
+	event := Event{
+		Kind:      kind,
+		Operation: s.operation,
+		Target:    s.target,
+		Title:     s.title,
+		Message:   message,
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	s.bus.Publish(ctx, event)
+}
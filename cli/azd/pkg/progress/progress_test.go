@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Bus_Publish(t *testing.T) {
+	var first, second []Event
+	bus := NewBus(SinkFunc(func(ctx context.Context, event Event) {
+		first = append(first, event)
+	}))
+	bus.Subscribe(SinkFunc(func(ctx context.Context, event Event) {
+		second = append(second, event)
+	}))
+
+	step := bus.Step("deploy", "api", "Deploying service api")
+	step.Start(context.Background())
+	step.Progress(context.Background(), "Uploading package")
+	step.Fail(context.Background(), errors.New("boom"))
+
+	require.Equal(t, first, second)
+	require.Len(t, first, 3)
+
+	require.Equal(t, EventStarted, first[0].Kind)
+	require.Equal(t, "deploy", first[0].Operation)
+	require.Equal(t, "api", first[0].Target)
+	require.False(t, first[0].Timestamp.IsZero())
+
+	require.Equal(t, EventProgress, first[1].Kind)
+	require.Equal(t, "Uploading package", first[1].Message)
+
+	require.Equal(t, EventFailed, first[2].Kind)
+	require.Equal(t, "boom", first[2].Error)
+}
+
+func Test_LogSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := NewLogSink(buf)
+	timestamp := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	sink.Handle(context.Background(), Event{Timestamp: timestamp, Kind: EventStarted, Title: "Packaging service web"})
+	sink.Handle(context.Background(), Event{Timestamp: timestamp, Kind: EventCompleted, Title: "Packaging service web"})
+
+	require.Equal(
+		t,
+		"2023-05-01T10:30:00Z Packaging service web\n2023-05-01T10:30:00Z Packaging service web: done\n",
+		buf.String(),
+	)
+}
+
+func Test_FormatEvent_Skipped(t *testing.T) {
+	require.Equal(t, "Deploying service worker: skipped", FormatEvent(Event{
+		Kind:  EventSkipped,
+		Title: "Deploying service worker",
+	}))
+	require.Equal(t, "Deploying service worker: skipped: disabled in environment dev", FormatEvent(Event{
+		Kind:    EventSkipped,
+		Title:   "Deploying service worker",
+		Message: "disabled in environment dev",
+	}))
+}
+
+func Test_JsonSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := NewJsonSink(buf)
+
+	sink.Handle(context.Background(), Event{Kind: EventProgress, Operation: "build", Title: "Building", Message: "npm"})
+	sink.Handle(context.Background(), Event{Kind: EventCompleted, Operation: "build", Title: "Building"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	require.Equal(t, EventProgress, event.Kind)
+	require.Equal(t, "npm", event.Message)
+}
+
+func Test_ParseMode(t *testing.T) {
+	mode, err := ParseMode("")
+	require.NoError(t, err)
+	require.Equal(t, ModeInteractive, mode)
+
+	mode, err = ParseMode(" JSON ")
+	require.NoError(t, err)
+	require.Equal(t, ModeJson, mode)
+
+	_, err = ParseMode("fancy")
+	require.Error(t, err)
+}
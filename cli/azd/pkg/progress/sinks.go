@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+)
+
+// Mode selects the sink used to render progress events.
+type Mode string
+
+const (
+	// Renders progress with the interactive console spinner
+	ModeInteractive Mode = "interactive"
+	// Renders progress as timestamped log lines, suited for CI logs and screen readers
+	ModePlain Mode = "plain"
+	// Renders progress as JSON lines, suited for IDEs and other tools
+	ModeJson Mode = "json"
+	// Suppresses progress output, events are only written to the debug log
+	ModeQuiet Mode = "quiet"
+)
+
+// The environment variable used to override the progress mode
+const ModeEnvVarName = "AZD_PROGRESS"
+
+// ParseMode converts the specified value to a Mode. An empty value resolves to ModeInteractive.
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return ModeInteractive, nil
+	case ModeInteractive, ModePlain, ModeJson, ModeQuiet:
+		return mode, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid progress mode '%s', supported values are '%s', '%s', '%s' and '%s'",
+			value, ModeInteractive, ModePlain, ModeJson, ModeQuiet,
+		)
+	}
+}
+
+// NewSink creates the sink for the specified mode.
+func NewSink(mode Mode, console input.Console) Sink {
+	switch mode {
+	case ModePlain:
+		return NewLogSink(console.Handles().Stdout)
+	case ModeJson:
+		// JSON progress is written to stderr to keep stdout available for command results
+		return NewJsonSink(console.Handles().Stderr)
+	case ModeQuiet:
+		return NewQuietSink()
+	default:
+		return NewConsoleSink(console)
+	}
+}
+
+type consoleSink struct {
+	console input.Console
+}
+
+// NewConsoleSink creates a sink that renders events using the console spinner.
+func NewConsoleSink(console input.Console) Sink {
+	return &consoleSink{
+		console: console,
+	}
+}
+
+func (s *consoleSink) Handle(ctx context.Context, event Event) {
+	switch event.Kind {
+	case EventStarted:
+		s.console.ShowSpinner(ctx, event.Title, input.Step)
+	case EventProgress:
+		s.console.ShowSpinner(ctx, fmt.Sprintf("%s (%s)", event.Title, event.Message), input.Step)
+	case EventCompleted:
+		s.console.StopSpinner(ctx, event.Title, input.StepDone)
+	case EventFailed:
+		s.console.StopSpinner(ctx, event.Title, input.StepFailed)
+	case EventSkipped:
+		if event.Message != "" {
+			s.console.StopSpinner(ctx, fmt.Sprintf("%s (%s)", event.Title, event.Message), input.StepSkipped)
+			return
+		}
+		s.console.StopSpinner(ctx, event.Title, input.StepSkipped)
+	}
+}
+
+type logSink struct {
+	writer io.Writer
+}
+
+// NewLogSink creates a sink that writes each event as a timestamped line of plain text.
+func NewLogSink(writer io.Writer) Sink {
+	return &logSink{
+		writer: writer,
+	}
+}
+
+func (s *logSink) Handle(ctx context.Context, event Event) {
+	fmt.Fprintf(s.writer, "%s %s\n", event.Timestamp.Format(time.RFC3339), FormatEvent(event))
+}
+
+type jsonSink struct {
+	writer io.Writer
+}
+
+// NewJsonSink creates a sink that writes each event as a single line of JSON.
+func NewJsonSink(writer io.Writer) Sink {
+	return &jsonSink{
+		writer: writer,
+	}
+}
+
+func (s *jsonSink) Handle(ctx context.Context, event Event) {
+	jsonEvent, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("failed marshalling progress event: %v", err)
+		return
+	}
+
+	fmt.Fprintln(s.writer, string(jsonEvent))
+}
+
+// NewQuietSink creates a sink that only writes events to the debug log.
+func NewQuietSink() Sink {
+	return SinkFunc(func(ctx context.Context, event Event) {
+		log.Println(FormatEvent(event))
+	})
+}
+
+// FormatEvent returns a single line, human readable description of the event.
+func FormatEvent(event Event) string {
+	switch event.Kind {
+	case EventProgress:
+		return fmt.Sprintf("%s: %s", event.Title, event.Message)
+	case EventCompleted:
+		return fmt.Sprintf("%s: done", event.Title)
+	case EventFailed:
+		if event.Error != "" {
+			return fmt.Sprintf("%s: failed: %s", event.Title, event.Error)
+		}
+		return fmt.Sprintf("%s: failed", event.Title)
+	case EventSkipped:
+		if event.Message != "" {
+			return fmt.Sprintf("%s: skipped: %s", event.Title, event.Message)
+		}
+		return fmt.Sprintf("%s: skipped", event.Title)
+	default:
+		return event.Title
+	}
+}
@@ -12,13 +12,18 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/trivy"
 	"github.com/benbjohnson/clock"
+	"golang.org/x/exp/slices"
 )
 
 type ContainerHelper struct {
 	env                      *environment.Environment
 	containerRegistryService azcli.ContainerRegistryService
 	docker                   docker.Docker
+	trivy                    trivy.Trivy
+	oras                     oras.Oras
 	clock                    clock.Clock
 }
 
@@ -27,25 +32,78 @@ func NewContainerHelper(
 	clock clock.Clock,
 	containerRegistryService azcli.ContainerRegistryService,
 	docker docker.Docker,
+	trivy trivy.Trivy,
+	oras oras.Oras,
 ) *ContainerHelper {
 	return &ContainerHelper{
 		env:                      env,
 		containerRegistryService: containerRegistryService,
 		docker:                   docker,
+		trivy:                    trivy,
+		oras:                     oras,
 		clock:                    clock,
 	}
 }
 
-func (ch *ContainerHelper) RegistryName(ctx context.Context) (string, error) {
-	loginServer, has := ch.env.Values[environment.ContainerRegistryEndpointEnvVarName]
-	if !has {
-		return "", fmt.Errorf(
-			"could not determine container registry endpoint, ensure %s is set as an output of your infrastructure",
-			environment.ContainerRegistryEndpointEnvVarName,
+// Registry returns the registry the images of the service are pushed to. Services push to the Azure Container Registry
+// of the environment unless they configure another registry with docker.registry.
+func (ch *ContainerHelper) Registry(ctx context.Context, serviceConfig *ServiceConfig) (Registry, error) {
+	endpoint, err := serviceConfig.Docker.Registry.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint == "" {
+		loginServer, has := ch.env.Values[environment.ContainerRegistryEndpointEnvVarName]
+		if !has {
+			return nil, fmt.Errorf(
+				"could not determine container registry endpoint, ensure %s is set as an output of your infrastructure",
+				environment.ContainerRegistryEndpointEnvVarName,
+			)
+		}
+
+		endpoint = loginServer
+	}
+
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	if isAcrEndpoint(endpoint) {
+		return &acrRegistry{
+			loginServer:              endpoint,
+			containerRegistryService: ch.containerRegistryService,
+		}, nil
+	}
+
+	username, err := serviceConfig.Docker.Username.Envsubst(ch.env.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	// The password is stored with the deployed service, it must come from the environment, which may source it from a
+	// secret store, rather than being committed in azure.yaml
+	referenced := false
+	password, err := serviceConfig.Docker.Password.Envsubst(func(name string) string {
+		referenced = true
+		return ch.env.Getenv(name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if password != "" && !referenced {
+		return nil, fmt.Errorf(
+			"docker.password of service '%s' must reference an environment value, like ${REGISTRY_PASSWORD}, "+
+				"instead of containing the password",
+			serviceConfig.Name,
 		)
 	}
 
-	return loginServer, nil
+	return &dockerRegistry{
+		endpoint: endpoint,
+		username: username,
+		password: password,
+		docker:   ch.docker,
+	}, nil
 }
 
 func (ch *ContainerHelper) RemoteImageTag(
@@ -53,14 +111,14 @@ func (ch *ContainerHelper) RemoteImageTag(
 	serviceConfig *ServiceConfig,
 	localImageTag string,
 ) (string, error) {
-	loginServer, err := ch.RegistryName(ctx)
+	registry, err := ch.Registry(ctx, serviceConfig)
 	if err != nil {
 		return "", err
 	}
 
 	return fmt.Sprintf(
 		"%s/%s",
-		loginServer,
+		registry.Endpoint(),
 		localImageTag,
 	), nil
 }
@@ -95,8 +153,7 @@ func (ch *ContainerHelper) Deploy(
 ) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
-			// Get ACR Login Server
-			loginServer, err := ch.RegistryName(ctx)
+			registry, err := ch.Registry(ctx, serviceConfig)
 			if err != nil {
 				task.SetError(err)
 				return
@@ -113,6 +170,14 @@ func (ch *ContainerHelper) Deploy(
 				return
 			}
 
+			if serviceConfig.Project != nil && serviceConfig.Project.ImageScan != nil {
+				task.SetProgress(NewServiceProgress("Scanning container image"))
+				if err := ch.scanImage(ctx, serviceConfig, localImageTag); err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
 			// Tag image
 			// Get remote tag from the container helper then call docker cli tag command
 			remoteTag, err := ch.RemoteImageTag(ctx, serviceConfig, localImageTag)
@@ -127,10 +192,9 @@ func (ch *ContainerHelper) Deploy(
 				return
 			}
 
-			log.Printf("logging into container registry '%s'\n", loginServer)
+			log.Printf("logging into container registry '%s'\n", registry.Endpoint())
 			task.SetProgress(NewServiceProgress("Logging into container registry"))
-			err = ch.containerRegistryService.LoginAcr(ctx, targetResource.SubscriptionId(), loginServer)
-			if err != nil {
+			if err := registry.Login(ctx, targetResource); err != nil {
 				task.SetError(err)
 				return
 			}
@@ -143,6 +207,17 @@ func (ch *ContainerHelper) Deploy(
 				return
 			}
 
+			if packageOutput.Sbom != "" && serviceConfig.Project != nil && serviceConfig.Project.Sbom != nil &&
+				serviceConfig.Project.Sbom.Upload {
+				log.Printf("attaching %s to %s", packageOutput.Sbom, remoteTag)
+				task.SetProgress(NewServiceProgress("Attaching SBOM to container image"))
+				err := ch.oras.Attach(ctx, remoteTag, sbomArtifactType(packageOutput.Sbom), packageOutput.Sbom)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
 			// Save the name of the image we pushed into the environment with a well known key.
 			log.Printf("writing image name to environment")
 			ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteTag)
@@ -157,3 +232,72 @@ func (ch *ContainerHelper) Deploy(
 			})
 		})
 }
+
+// scanImage fails when the image has vulnerabilities of the severity configured in the imageScan options of the
+// project, or of a higher severity.
+func (ch *ContainerHelper) scanImage(ctx context.Context, serviceConfig *ServiceConfig, image string) error {
+	options := serviceConfig.Project.ImageScan
+
+	severity := strings.ToUpper(options.Severity)
+	if severity == "" {
+		severity = "HIGH"
+	}
+
+	severityIndex := slices.Index(trivy.Severities, severity)
+	if severityIndex < 0 {
+		return fmt.Errorf(
+			"invalid imageScan severity '%s', expected one of %s",
+			options.Severity,
+			strings.Join(trivy.Severities, ", "),
+		)
+	}
+
+	if err := tools.EnsureInstalled(ctx, ch.trivy); err != nil {
+		return err
+	}
+
+	log.Printf("scanning %s for vulnerabilities", image)
+	vulnerabilities, err := ch.trivy.ScanImage(
+		ctx,
+		serviceConfig.Path(),
+		image,
+		trivy.Severities[severityIndex:],
+		options.IgnoreUnfixed,
+	)
+	if err != nil {
+		return err
+	}
+
+	var found []string
+	for _, vulnerability := range vulnerabilities {
+		if slices.Contains(options.Ignore, vulnerability.VulnerabilityID) {
+			continue
+		}
+
+		fix := "no fix available"
+		if vulnerability.FixedVersion != "" {
+			fix = "fixed in " + vulnerability.FixedVersion
+		}
+
+		found = append(found, fmt.Sprintf(
+			"%s (%s) in %s %s, %s",
+			vulnerability.VulnerabilityID,
+			vulnerability.Severity,
+			vulnerability.PkgName,
+			vulnerability.InstalledVersion,
+			fix,
+		))
+	}
+
+	if len(found) > 0 {
+		return fmt.Errorf(
+			"the container image of service '%s' has %d vulnerabilities of severity %s or higher:\n  %s",
+			serviceConfig.Name,
+			len(found),
+			severity,
+			strings.Join(found, "\n  "),
+		)
+	}
+
+	return nil
+}
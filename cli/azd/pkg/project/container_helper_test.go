@@ -3,9 +3,13 @@ package project
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/trivy"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/benbjohnson/clock"
 	"github.com/stretchr/testify/assert"
@@ -47,7 +51,7 @@ func Test_ContainerHelper_LocalImageTag(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			env := environment.EphemeralWithValues("dev", map[string]string{})
-			containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil)
+			containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil, nil, nil)
 			serviceConfig.Docker = tt.dockerConfig
 
 			tag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
@@ -62,7 +66,7 @@ func Test_ContainerHelper_RemoteImageTag(t *testing.T) {
 	env := environment.EphemeralWithValues("dev", map[string]string{
 		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
 	})
-	containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil)
+	containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil, nil, nil)
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 	localTag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
 	require.NoError(t, err)
@@ -76,9 +80,268 @@ func Test_ContainerHelper_RemoteImageTag_NoContainer_Registry(t *testing.T) {
 
 	env := environment.Ephemeral()
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
-	containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil)
+	containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil, nil, nil)
 
 	imageTag, err := containerHelper.RemoteImageTag(*mockContext.Context, serviceConfig, "local_tag")
 	require.Error(t, err)
 	require.Empty(t, imageTag)
 }
+
+func Test_ContainerHelper_Registry(t *testing.T) {
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+		"REGISTRY_USERNAME":                             "contoso-bot",
+		"REGISTRY_PASSWORD":                             "token",
+	})
+
+	t.Run("DefaultsToAcr", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil, nil, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+
+		registry, err := containerHelper.Registry(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.IsType(t, &acrRegistry{}, registry)
+		require.Equal(t, "contoso.azurecr.io", registry.Endpoint())
+		require.Nil(t, registry.Credentials())
+	})
+
+	t.Run("OtherRegistry", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper := NewContainerHelper(env, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner), nil, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Registry = NewExpandableString("ghcr.io/contoso/")
+		serviceConfig.Docker.Username = NewExpandableString("${REGISTRY_USERNAME}")
+		serviceConfig.Docker.Password = NewExpandableString("${REGISTRY_PASSWORD}")
+
+		var loginArgs []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker login")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			loginArgs = args.Args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		registry, err := containerHelper.Registry(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+		require.IsType(t, &dockerRegistry{}, registry)
+		require.Equal(t, "ghcr.io/contoso", registry.Endpoint())
+
+		remoteTag, err := containerHelper.RemoteImageTag(*mockContext.Context, serviceConfig, "api:latest")
+		require.NoError(t, err)
+		require.Equal(t, "ghcr.io/contoso/api:latest", remoteTag)
+
+		err = registry.Login(*mockContext.Context, environment.NewTargetResource("SUB_ID", "RG_ID", "", ""))
+		require.NoError(t, err)
+		require.Equal(t, []string{"login", "--username", "contoso-bot", "--password", "token", "ghcr.io"}, loginArgs)
+		require.Equal(t, &RegistryCredentials{
+			Server:   "ghcr.io",
+			Username: "contoso-bot",
+			Password: "token",
+		}, registry.Credentials())
+	})
+
+	t.Run("PlainTextPassword", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper := NewContainerHelper(env, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner), nil, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Registry = NewExpandableString("ghcr.io/contoso")
+		serviceConfig.Docker.Username = NewExpandableString("contoso-bot")
+		serviceConfig.Docker.Password = NewExpandableString("token")
+
+		_, err := containerHelper.Registry(*mockContext.Context, serviceConfig)
+		require.ErrorContains(t, err, "must reference an environment value")
+	})
+
+	t.Run("DockerConfigCredentials", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper := NewContainerHelper(env, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner), nil, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Docker.Registry = NewExpandableString("docker.io/contoso")
+
+		registry, err := containerHelper.Registry(*mockContext.Context, serviceConfig)
+		require.NoError(t, err)
+
+		// No docker login is run, the mock command runner panics on unexpected commands
+		err = registry.Login(*mockContext.Context, environment.NewTargetResource("SUB_ID", "RG_ID", "", ""))
+		require.NoError(t, err)
+		require.Nil(t, registry.Credentials())
+	})
+}
+
+func Test_ContainerHelper_ScanImage(t *testing.T) {
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
+	})
+	vulnerabilities := []trivy.Vulnerability{
+		{
+			VulnerabilityID:  "CVE-2023-0001",
+			PkgName:          "openssl",
+			InstalledVersion: "3.0.1",
+			FixedVersion:     "3.0.8",
+			Severity:         "CRITICAL",
+		},
+		{
+			VulnerabilityID:  "CVE-2023-0002",
+			PkgName:          "zlib",
+			InstalledVersion: "1.2.11",
+			Severity:         "HIGH",
+		},
+	}
+
+	t.Run("FailsDeploy", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		scanner := &fakeTrivy{vulnerabilities: vulnerabilities}
+		containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil, scanner, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Project.ImageScan = &ImageScanOptions{Severity: "high"}
+
+		// The image is never tagged nor pushed, the docker cli is nil
+		deployTask := containerHelper.Deploy(
+			*mockContext.Context,
+			serviceConfig,
+			&ServicePackageResult{PackagePath: "my-project/api:azd-deploy-0"},
+			environment.NewTargetResource("SUB_ID", "RG_ID", "", ""),
+		)
+		logProgress(deployTask)
+		_, err := deployTask.Await()
+
+		require.Error(t, err)
+		require.Equal(t, "my-project/api:azd-deploy-0", scanner.image)
+		require.Equal(t, []string{"HIGH", "CRITICAL"}, scanner.severities)
+		require.Equal(
+			t,
+			"the container image of service 'api' has 2 vulnerabilities of severity HIGH or higher:\n"+
+				"  CVE-2023-0001 (CRITICAL) in openssl 3.0.1, fixed in 3.0.8\n"+
+				"  CVE-2023-0002 (HIGH) in zlib 1.2.11, no fix available",
+			err.Error(),
+		)
+	})
+
+	t.Run("IgnoredVulnerabilities", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		scanner := &fakeTrivy{vulnerabilities: vulnerabilities}
+		containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil, scanner, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Project.ImageScan = &ImageScanOptions{
+			Severity:      "CRITICAL",
+			IgnoreUnfixed: true,
+			Ignore:        []string{"CVE-2023-0001", "CVE-2023-0002"},
+		}
+
+		err := containerHelper.scanImage(*mockContext.Context, serviceConfig, "api:latest")
+		require.NoError(t, err)
+		require.Equal(t, []string{"CRITICAL"}, scanner.severities)
+		require.True(t, scanner.ignoreUnfixed)
+	})
+
+	t.Run("InvalidSeverity", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		containerHelper := NewContainerHelper(env, clock.NewMock(), nil, nil, &fakeTrivy{}, nil)
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Project.ImageScan = &ImageScanOptions{Severity: "severe"}
+
+		err := containerHelper.scanImage(*mockContext.Context, serviceConfig, "api:latest")
+		require.ErrorContains(t, err, "invalid imageScan severity 'severe'")
+	})
+}
+
+func Test_ContainerHelper_AttachSbom(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.EphemeralWithValues("dev", nil)
+	orasCli := &fakeOras{}
+	containerHelper := NewContainerHelper(
+		env, clock.NewMock(), nil, docker.NewDocker(mockContext.CommandRunner), nil, orasCli,
+	)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Registry = NewExpandableString("ghcr.io/contoso")
+	serviceConfig.Project.Sbom = &SbomOptions{Upload: true}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker tag") || strings.Contains(command, "docker push")
+	}).Respond(exec.NewRunResult(0, "", ""))
+
+	deployTask := containerHelper.Deploy(
+		*mockContext.Context,
+		serviceConfig,
+		&ServicePackageResult{PackagePath: "my-project/api:azd-deploy-0", Sbom: "/sbom/api.cdx.json"},
+		environment.NewTargetResource("SUB_ID", "RG_ID", "", ""),
+	)
+	logProgress(deployTask)
+	_, err := deployTask.Await()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"ghcr.io/contoso/my-project/api:azd-deploy-0 application/vnd.cyclonedx+json /sbom/api.cdx.json",
+	}, orasCli.attached)
+}
+
+type fakeOras struct {
+	attached []string
+}
+
+func (f *fakeOras) CheckInstalled(context.Context) error {
+	return nil
+}
+
+func (f *fakeOras) InstallUrl() string {
+	return ""
+}
+
+func (f *fakeOras) Name() string {
+	return "ORAS"
+}
+
+func (f *fakeOras) Attach(_ context.Context, image string, artifactType string, filePath string) error {
+	f.attached = append(f.attached, strings.Join([]string{image, artifactType, filePath}, " "))
+	return nil
+}
+
+type fakeTrivy struct {
+	vulnerabilities []trivy.Vulnerability
+	image           string
+	severities      []string
+	ignoreUnfixed   bool
+	sboms           []string
+}
+
+func (f *fakeTrivy) CheckInstalled(context.Context) error {
+	return nil
+}
+
+func (f *fakeTrivy) InstallUrl() string {
+	return ""
+}
+
+func (f *fakeTrivy) Name() string {
+	return "Trivy"
+}
+
+func (f *fakeTrivy) ScanImage(
+	_ context.Context,
+	_ string,
+	image string,
+	severities []string,
+	ignoreUnfixed bool,
+) ([]trivy.Vulnerability, error) {
+	f.image = image
+	f.severities = severities
+	f.ignoreUnfixed = ignoreUnfixed
+	return f.vulnerabilities, nil
+}
+
+func (f *fakeTrivy) GenerateImageSbom(
+	_ context.Context,
+	_ string,
+	image string,
+	format trivy.SbomFormat,
+	outputPath string,
+) error {
+	f.sboms = append(f.sboms, strings.Join([]string{"image", image, string(format), outputPath}, " "))
+	return nil
+}
+
+func (f *fakeTrivy) GenerateFsSbom(_ context.Context, path string, format trivy.SbomFormat, outputPath string) error {
+	f.sboms = append(f.sboms, strings.Join([]string{"fs", path, string(format), outputPath}, " "))
+	return nil
+}
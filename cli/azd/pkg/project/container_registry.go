@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+)
+
+// The login server suffixes of Azure Container Registry in the Azure clouds
+var acrLoginServerSuffixes = []string{".azurecr.io", ".azurecr.cn", ".azurecr.us"}
+
+// Registry is a container registry the images of services are pushed to.
+type Registry interface {
+	// Endpoint returns the registry images are tagged with, like contoso.azurecr.io or ghcr.io/contoso.
+	Endpoint() string
+	// Login logs docker into the registry.
+	Login(ctx context.Context, targetResource *environment.TargetResource) error
+	// Credentials returns the credentials the deployed service pulls its image with. It returns nil when the host pulls
+	// the image with its Azure identity, or with credentials configured outside of azd.
+	Credentials() *RegistryCredentials
+}
+
+// RegistryCredentials are the credentials of a registry other than Azure Container Registry.
+type RegistryCredentials struct {
+	// The login server of the registry, like ghcr.io
+	Server   string
+	Username string
+	Password string
+}
+
+// acrRegistry is an Azure Container Registry, logged into with the credentials of the current Azure principal.
+type acrRegistry struct {
+	loginServer              string
+	containerRegistryService azcli.ContainerRegistryService
+}
+
+func (r *acrRegistry) Endpoint() string {
+	return r.loginServer
+}
+
+func (r *acrRegistry) Login(ctx context.Context, targetResource *environment.TargetResource) error {
+	return r.containerRegistryService.LoginAcr(ctx, targetResource.SubscriptionId(), r.loginServer)
+}
+
+func (r *acrRegistry) Credentials() *RegistryCredentials {
+	return nil
+}
+
+// dockerRegistry is any other registry, like GHCR, Docker Hub or a private registry, logged into with a username and
+// password, or with the credentials stored in the docker config.
+type dockerRegistry struct {
+	endpoint string
+	username string
+	password string
+	docker   docker.Docker
+}
+
+func (r *dockerRegistry) Endpoint() string {
+	return r.endpoint
+}
+
+func (r *dockerRegistry) Login(ctx context.Context, _ *environment.TargetResource) error {
+	if r.username == "" && r.password == "" {
+		log.Printf("no credentials configured for registry '%s', using the docker config", r.endpoint)
+		return nil
+	}
+
+	if r.username == "" || r.password == "" {
+		return errors.New("both docker.username and docker.password must be set to log into the registry")
+	}
+
+	return r.docker.Login(ctx, r.loginServer(), r.username, r.password)
+}
+
+func (r *dockerRegistry) Credentials() *RegistryCredentials {
+	if r.username == "" || r.password == "" {
+		log.Printf(
+			"no credentials configured for registry '%s', the service pulls its image with the credentials of its host",
+			r.endpoint,
+		)
+		return nil
+	}
+
+	return &RegistryCredentials{
+		Server:   r.loginServer(),
+		Username: r.username,
+		Password: r.password,
+	}
+}
+
+// loginServer returns the host of the registry. The endpoint may contain a namespace, like ghcr.io/contoso, docker
+// logs into the host.
+func (r *dockerRegistry) loginServer() string {
+	loginServer, _, _ := strings.Cut(r.endpoint, "/")
+	return loginServer
+}
+
+// isAcrEndpoint returns true when the registry endpoint is an Azure Container Registry.
+func isAcrEndpoint(endpoint string) bool {
+	loginServer, _, _ := strings.Cut(strings.ToLower(endpoint), "/")
+	for _, suffix := range acrLoginServerSuffixes {
+		if strings.HasSuffix(loginServer, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
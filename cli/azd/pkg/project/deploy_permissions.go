@@ -0,0 +1,45 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The actions needed to deploy a service to its target resource, by type of the target resource
+var deployActions = map[infra.AzureResourceType][]string{
+	infra.AzureResourceTypeWebSite:       {"Microsoft.Web/sites/publish/action"},
+	infra.AzureResourceTypeContainerApp:  {"Microsoft.App/containerApps/write"},
+	infra.AzureResourceTypeStaticWebSite: {"Microsoft.Web/staticSites/listSecrets/action"},
+}
+
+// ensureDeployPermissions fails with an *azcli.MissingPermissionsError when the signed-in principal isn't allowed to
+// deploy to the target resource, instead of the deployment failing with the error of the service. The check is
+// skipped for the other types of resources, and when the permissions can't be read.
+func ensureDeployPermissions(ctx context.Context, cli azcli.AzCli, targetResource *environment.TargetResource) error {
+	actions, has := deployActions[infra.AzureResourceType(targetResource.ResourceType())]
+	if !has || targetResource.ResourceName() == "" {
+		return nil
+	}
+
+	scope := azure.ResourceGroupRID(targetResource.SubscriptionId(), targetResource.ResourceGroupName()) +
+		"/providers/" + targetResource.ResourceType() + "/" + targetResource.ResourceName()
+	missing, err := cli.MissingPermissions(ctx, targetResource.SubscriptionId(), scope, actions)
+	if err != nil {
+		log.Printf("skipping the permissions check of %s: %v", targetResource.ResourceName(), err)
+		return nil
+	}
+
+	if len(missing) > 0 {
+		return &azcli.MissingPermissionsError{Scope: scope, Actions: missing}
+	}
+
+	return nil
+}
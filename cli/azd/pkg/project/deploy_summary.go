@@ -0,0 +1,203 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// The time given to an endpoint to answer its health check
+const endpointHealthCheckTimeout = 10 * time.Second
+
+// The result of the health check of an endpoint
+type EndpointHealthStatus string
+
+const (
+	// The endpoint answered with a status code below 500
+	EndpointHealthy EndpointHealthStatus = "healthy"
+	// The endpoint answered with a server error
+	EndpointUnhealthy EndpointHealthStatus = "unhealthy"
+	// The endpoint couldn't be reached before the timeout
+	EndpointUnreachable EndpointHealthStatus = "unreachable"
+	// The endpoint isn't an HTTP endpoint, like the endpoint of a database
+	EndpointNotChecked EndpointHealthStatus = "notChecked"
+)
+
+// EndpointHealth is the result of the health check of an endpoint of a deployed service.
+type EndpointHealth struct {
+	Url        string               `json:"url"`
+	Status     EndpointHealthStatus `json:"status"`
+	StatusCode int                  `json:"statusCode,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
+// ServiceDeploySummary summarizes the deployment of a service: the health of its endpoints, the link to its resource in
+// the Azure Portal and the time spent packaging and deploying it.
+type ServiceDeploySummary struct {
+	Name            string           `json:"name"`
+	Endpoints       []EndpointHealth `json:"endpoints"`
+	ResourceId      string           `json:"resourceId,omitempty"`
+	PortalUrl       string           `json:"portalUrl,omitempty"`
+	DurationSeconds float64          `json:"durationSeconds"`
+}
+
+// DeploySummary is the report printed at the end of `azd deploy` and `azd up`, also written in their JSON output so CI
+// pipelines can keep it as a deployment report.
+type DeploySummary struct {
+	Services []*ServiceDeploySummary `json:"services"`
+}
+
+// NewServiceDeploySummary summarizes the deployment of the service, checking the health of its endpoints.
+func NewServiceDeploySummary(
+	ctx context.Context,
+	httpClient httputil.HttpClient,
+	serviceName string,
+	deployResult *ServiceDeployResult,
+	duration time.Duration,
+) *ServiceDeploySummary {
+	summary := &ServiceDeploySummary{
+		Name:            serviceName,
+		Endpoints:       CheckEndpointsHealth(ctx, httpClient, deployResult.Endpoints),
+		ResourceId:      deployResult.TargetResourceId,
+		DurationSeconds: duration.Round(time.Second).Seconds(),
+	}
+
+	if deployResult.TargetResourceId != "" {
+		summary.PortalUrl = fmt.Sprintf("https://portal.azure.com/#@/resource%s", deployResult.TargetResourceId)
+	}
+
+	return summary
+}
+
+// CheckEndpointsHealth sends a GET request to each HTTP endpoint, concurrently, and returns the health of the endpoints
+// in the same order. Endpoints which aren't HTTP URLs are not checked.
+func CheckEndpointsHealth(ctx context.Context, httpClient httputil.HttpClient, endpoints []string) []EndpointHealth {
+	results := make([]EndpointHealth, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			results[i] = checkEndpointHealth(ctx, httpClient, endpoint)
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func checkEndpointHealth(ctx context.Context, httpClient httputil.HttpClient, endpoint string) EndpointHealth {
+	health := EndpointHealth{Url: endpoint, Status: EndpointNotChecked}
+
+	// Some targets describe their endpoints, like 'https://<host>/ (primary)', only the URL is checked
+	fields := strings.Fields(endpoint)
+	if len(fields) == 0 {
+		return health
+	}
+
+	endpointUrl, err := url.Parse(fields[0])
+	if err != nil || (endpointUrl.Scheme != "http" && endpointUrl.Scheme != "https") {
+		return health
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, endpointHealthCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpointUrl.String(), nil)
+	if err != nil {
+		return health
+	}
+
+	response, err := httpClient.Do(req)
+	if err != nil {
+		health.Status = EndpointUnreachable
+		health.Error = err.Error()
+		return health
+	}
+	defer response.Body.Close()
+
+	health.StatusCode = response.StatusCode
+	health.Status = EndpointHealthy
+	if response.StatusCode >= http.StatusInternalServerError {
+		health.Status = EndpointUnhealthy
+	}
+
+	return health
+}
+
+// Renders the summary as a table of the endpoints of the services, followed by the links to their resources
+func (s *DeploySummary) ToString(currentIndentation string) string {
+	if len(s.Services) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%sDeployment summary:\n\n", currentIndentation))
+
+	tabs := tabwriter.NewWriter(
+		&sb, output.TableColumnMinWidth, output.TableTabSize, output.TablePadSize, output.TablePadCharacter, output.TableFlags)
+	// The health is colored, it's the last column so the escape sequences don't break the alignment
+	fmt.Fprintf(tabs, "%s  Service\tDuration\tEndpoint\tHealth\n", currentIndentation)
+	for _, service := range s.Services {
+		duration := (time.Duration(service.DurationSeconds) * time.Second).String()
+		if len(service.Endpoints) == 0 {
+			fmt.Fprintf(tabs, "%s  %s\t%s\t-\t-\n", currentIndentation, service.Name, duration)
+			continue
+		}
+
+		for i, endpoint := range service.Endpoints {
+			name := service.Name
+			if i > 0 {
+				name, duration = "", ""
+			}
+
+			fmt.Fprintf(
+				tabs, "%s  %s\t%s\t%s\t%s\n", currentIndentation, name, duration, endpoint.Url, endpoint.healthText())
+		}
+	}
+	_ = tabs.Flush()
+
+	portalLinks := []string{}
+	for _, service := range s.Services {
+		if service.PortalUrl != "" {
+			portalLinks = append(portalLinks,
+				fmt.Sprintf("%s  %s: %s\n", currentIndentation, service.Name, output.WithLinkFormat(service.PortalUrl)))
+		}
+	}
+
+	if len(portalLinks) > 0 {
+		sb.WriteString(fmt.Sprintf("\n%sResources in the Azure Portal:\n", currentIndentation))
+		sb.WriteString(strings.Join(portalLinks, ""))
+	}
+
+	return sb.String()
+}
+
+// healthText is the health of the endpoint as displayed in the summary, like 'Healthy (200)'.
+func (h EndpointHealth) healthText() string {
+	switch h.Status {
+	case EndpointHealthy:
+		return output.WithSuccessFormat("Healthy (%d)", h.StatusCode)
+	case EndpointUnhealthy:
+		return output.WithErrorFormat("Unhealthy (%d)", h.StatusCode)
+	case EndpointUnreachable:
+		return output.WithWarningFormat("Unreachable")
+	default:
+		return "-"
+	}
+}
+
+func (s *DeploySummary) MarshalJSON() ([]byte, error) {
+	type summary DeploySummary
+	return json.Marshal((*summary)(s))
+}
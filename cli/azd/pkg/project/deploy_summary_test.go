@@ -0,0 +1,87 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckEndpointsHealth(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "api.azurewebsites.net"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "web.azurewebsites.net"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusServiceUnavailable)
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.Host == "down.azurewebsites.net"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return nil, errors.New("no such host")
+	})
+
+	health := CheckEndpointsHealth(*mockContext.Context, mockContext.HttpClient, []string{
+		"https://api.azurewebsites.net/ (primary)",
+		"https://web.azurewebsites.net/",
+		"https://down.azurewebsites.net/",
+		"sqlserver.database.windows.net",
+	})
+
+	require.Equal(t, []EndpointHealth{
+		{Url: "https://api.azurewebsites.net/ (primary)", Status: EndpointHealthy, StatusCode: http.StatusNotFound},
+		{Url: "https://web.azurewebsites.net/", Status: EndpointUnhealthy, StatusCode: http.StatusServiceUnavailable},
+		{Url: "https://down.azurewebsites.net/", Status: EndpointUnreachable, Error: "no such host"},
+		{Url: "sqlserver.database.windows.net", Status: EndpointNotChecked},
+	}, health)
+}
+
+func Test_DeploySummary(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return true
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	resourceId := "/subscriptions/SUBSCRIPTION_ID/resourceGroups/RESOURCE_GROUP/providers/Microsoft.Web/sites/api"
+	summary := &DeploySummary{
+		Services: []*ServiceDeploySummary{
+			NewServiceDeploySummary(*mockContext.Context, mockContext.HttpClient, "api", &ServiceDeployResult{
+				TargetResourceId: resourceId,
+				Endpoints:        []string{"https://api.azurewebsites.net/"},
+			}, 61*time.Second+400*time.Millisecond),
+			NewServiceDeploySummary(*mockContext.Context, mockContext.HttpClient, "worker", &ServiceDeployResult{
+				Endpoints: []string{},
+			}, 5*time.Second),
+		},
+	}
+
+	api := summary.Services[0]
+	require.Equal(t, "https://portal.azure.com/#@/resource"+resourceId, api.PortalUrl)
+	require.Equal(t, float64(61), api.DurationSeconds)
+	require.Equal(t, []EndpointHealth{
+		{Url: "https://api.azurewebsites.net/", Status: EndpointHealthy, StatusCode: http.StatusOK},
+	}, api.Endpoints)
+
+	text := summary.ToString("")
+	require.Contains(t, text, "Deployment summary:")
+	require.Contains(t, text, "https://api.azurewebsites.net/")
+	require.Contains(t, text, "1m1s")
+	require.Contains(t, text, "worker")
+	require.Contains(t, text, "api: ")
+	require.NotContains(t, text, "worker: ")
+
+	json, err := summary.MarshalJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(json), `"durationSeconds":61`)
+	require.Contains(t, string(json), `"status":"healthy"`)
+}
@@ -0,0 +1,237 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// The compose files used to start a service with docker compose, in order of precedence
+var composeFileNames = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// The entry points used to start python services, in order of precedence
+var pythonEntryPoints = []string{"app.py", "main.py"}
+
+// DevRunner starts services on the local machine using the runner of their language, like `dotnet run` or
+// `npm run dev`, or docker compose when the service contains a compose file. Container services without a local runner
+// are built from their Dockerfile and run with docker.
+type DevRunner struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewDevRunner creates a new instance of the DevRunner
+func NewDevRunner(commandRunner exec.CommandRunner) *DevRunner {
+	return &DevRunner{
+		commandRunner: commandRunner,
+	}
+}
+
+// RunArgs resolves the command which starts the service locally.
+func (r *DevRunner) RunArgs(serviceConfig *ServiceConfig) (exec.RunArgs, error) {
+	servicePath := serviceConfig.Path()
+
+	for _, fileName := range composeFileNames {
+		if _, err := os.Stat(filepath.Join(servicePath, fileName)); err == nil {
+			return exec.NewRunArgs("docker", "compose", "-f", fileName, "up").WithCwd(servicePath), nil
+		}
+	}
+
+	if serviceConfig.Host == AzureFunctionTarget {
+		return exec.NewRunArgs("func", "start").WithCwd(servicePath), nil
+	}
+
+	switch serviceConfig.Language {
+	case ServiceLanguageDotNet, ServiceLanguageCsharp, ServiceLanguageFsharp:
+		return exec.NewRunArgs("dotnet", "run").WithCwd(servicePath), nil
+	case ServiceLanguageJavaScript, ServiceLanguageTypeScript:
+		script, err := npmDevScript(servicePath)
+		if err != nil {
+			return exec.RunArgs{}, err
+		}
+
+		return exec.NewRunArgs("npm", "run", script).WithCwd(servicePath), nil
+	case ServiceLanguageGo:
+		return exec.NewRunArgs("go", "run", serviceConfig.Go.mainPackage()).WithCwd(servicePath), nil
+	case ServiceLanguagePython:
+		for _, entryPoint := range pythonEntryPoints {
+			if _, err := os.Stat(filepath.Join(servicePath, entryPoint)); err == nil {
+				return exec.NewRunArgs("python", entryPoint).WithCwd(servicePath), nil
+			}
+		}
+	}
+
+	if isContainerHost(serviceConfig.Host) {
+		if _, err := os.Stat(filepath.Join(servicePath, dockerfilePath(serviceConfig))); err == nil {
+			// The values of docker.env are passed by name, docker reads them from the environment values
+			args := []string{"run", "--rm", "--init", "--publish-all"}
+			for _, name := range serviceConfig.Docker.Env {
+				args = append(args, "--env", name)
+			}
+
+			return exec.NewRunArgs("docker", append(args, devImageName(serviceConfig))...).WithCwd(servicePath), nil
+		}
+	}
+
+	return exec.RunArgs{}, fmt.Errorf(
+		"no local runner found for service '%s' (language '%s'), add a compose.yaml to the service to run it locally",
+		serviceConfig.Name,
+		serviceConfig.Language,
+	)
+}
+
+// Run starts the services with the environment values in env and writes their output to writer, each line prefixed
+// with the name of the service. Run blocks until all the services exit; when a service fails, the remaining services
+// are stopped.
+func (r *DevRunner) Run(ctx context.Context, services []*ServiceConfig, env []string, writer io.Writer) error {
+	runArgs := make([]exec.RunArgs, len(services))
+	nameWidth := 0
+	for i, svc := range services {
+		args, err := r.RunArgs(svc)
+		if err != nil {
+			return err
+		}
+
+		runArgs[i] = args.WithEnv(env)
+		if len(svc.Name) > nameWidth {
+			nameWidth = len(svc.Name)
+		}
+	}
+
+	// The images of the services run with docker are built first, a service doesn't start before the others built
+	for i, svc := range services {
+		if runArgs[i].Cmd != "docker" || len(runArgs[i].Args) == 0 || runArgs[i].Args[0] != "run" {
+			continue
+		}
+
+		buildArgs, err := devBuildArgs(svc, env)
+		if err != nil {
+			return err
+		}
+
+		if _, err := r.commandRunner.Run(ctx, buildArgs.WithEnv(env).WithEnrichError(true)); err != nil {
+			return fmt.Errorf("building the image of service '%s': %w", svc.Name, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	errs := make([]error, len(services))
+
+	for i, svc := range services {
+		prefix := output.WithHighLightFormat("%-*s | ", nameWidth, svc.Name)
+		serviceWriter := output.NewPrefixWriter(writer, prefix, &lock)
+
+		wg.Add(1)
+		go func(i int, svc *ServiceConfig, args exec.RunArgs) {
+			defer wg.Done()
+
+			_, err := r.commandRunner.Run(ctx, args.WithStdOut(serviceWriter).WithStdErr(serviceWriter))
+			_ = serviceWriter.Flush()
+
+			// Services stopped because another service failed or the user interrupted are not failures
+			if err != nil && ctx.Err() == nil {
+				errs[i] = fmt.Errorf("service '%s' exited: %w", svc.Name, err)
+				cancel()
+			}
+		}(i, svc, runArgs[i])
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isContainerHost reports whether the service is deployed as a container image.
+func isContainerHost(host ServiceTargetKind) bool {
+	return host == ContainerAppTarget || host == AksTarget
+}
+
+// dockerfilePath returns the path of the Dockerfile of the service, relative to the service.
+func dockerfilePath(serviceConfig *ServiceConfig) string {
+	return getDockerOptionsWithDefaults(serviceConfig.Docker).Path
+}
+
+// devImageName returns the name of the image `azd dev` builds for the service.
+func devImageName(serviceConfig *ServiceConfig) string {
+	name := strings.ToLower(serviceConfig.Name) + "-dev"
+	if serviceConfig.Project != nil && serviceConfig.Project.Name != "" {
+		name = strings.ToLower(serviceConfig.Project.Name) + "-" + name
+	}
+
+	return name
+}
+
+// devBuildArgs returns the command building the image of the service with its docker options. The values of docker.env
+// are passed by name, docker reads them from the environment values in env.
+func devBuildArgs(serviceConfig *ServiceConfig, env []string) (exec.RunArgs, error) {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	args := []string{"build", "-f", dockerOptions.Path, "-t", devImageName(serviceConfig)}
+
+	values := map[string]string{}
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			values[key] = value
+		}
+	}
+
+	for _, buildArg := range dockerOptions.BuildArgs {
+		value, err := buildArg.Envsubst(func(name string) string { return values[name] })
+		if err != nil {
+			return exec.RunArgs{}, fmt.Errorf("resolving docker.buildArgs of service %s: %w", serviceConfig.Name, err)
+		}
+
+		args = append(args, "--build-arg", value)
+	}
+
+	for _, name := range dockerOptions.Env {
+		args = append(args, "--build-arg", name)
+	}
+
+	args = append(args, dockerOptions.Context)
+
+	return exec.NewRunArgs("docker", args...).WithCwd(serviceConfig.Path()), nil
+}
+
+// npmDevScript returns the `dev` script of package.json, falling back to `start` when there is no `dev` script.
+func npmDevScript(servicePath string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(servicePath, "package.json"))
+	if err != nil {
+		return "", fmt.Errorf("reading package.json: %w", err)
+	}
+
+	var packageJson struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(content, &packageJson); err != nil {
+		return "", fmt.Errorf("parsing package.json: %w", err)
+	}
+
+	for _, script := range []string{"dev", "start"} {
+		if _, has := packageJson.Scripts[script]; has {
+			return script, nil
+		}
+	}
+
+	return "", errors.New("package.json does not define a 'dev' or 'start' script")
+}
@@ -0,0 +1,183 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevRunnerRunArgs(t *testing.T) {
+	tests := map[string]struct {
+		language ServiceLanguageKind
+		host     ServiceTargetKind
+		files    map[string]string
+		expected []string
+	}{
+		"Compose": {
+			language: ServiceLanguagePython,
+			files:    map[string]string{"docker-compose.yml": "services: {}"},
+			expected: []string{"docker", "compose", "-f", "docker-compose.yml", "up"},
+		},
+		"Function": {
+			language: ServiceLanguageJavaScript,
+			host:     AzureFunctionTarget,
+			expected: []string{"func", "start"},
+		},
+		"DotNet": {
+			language: ServiceLanguageCsharp,
+			expected: []string{"dotnet", "run"},
+		},
+		"NpmDev": {
+			language: ServiceLanguageTypeScript,
+			files:    map[string]string{"package.json": `{"scripts": {"dev": "vite", "start": "node index.js"}}`},
+			expected: []string{"npm", "run", "dev"},
+		},
+		"NpmStart": {
+			language: ServiceLanguageJavaScript,
+			files:    map[string]string{"package.json": `{"scripts": {"start": "node index.js"}}`},
+			expected: []string{"npm", "run", "start"},
+		},
+		"Python": {
+			language: ServiceLanguagePython,
+			files:    map[string]string{"main.py": ""},
+			expected: []string{"python", "main.py"},
+		},
+		"Dockerfile": {
+			language: ServiceLanguageJava,
+			host:     ContainerAppTarget,
+			files:    map[string]string{"Dockerfile": "FROM scratch"},
+			expected: []string{"docker", "run", "--rm", "--init", "--publish-all", "--env", "API_KEY", "api-dev"},
+		},
+		"Unsupported": {
+			language: ServiceLanguageJava,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			serviceConfig := createDevServiceConfig(t, "api", test.language, test.files)
+			serviceConfig.Host = test.host
+			serviceConfig.Docker.Env = []string{"API_KEY"}
+
+			runner := NewDevRunner(nil)
+			runArgs, err := runner.RunArgs(serviceConfig)
+			if test.expected == nil {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, append([]string{runArgs.Cmd}, runArgs.Args...))
+			require.Equal(t, serviceConfig.Path(), runArgs.Cwd)
+		})
+	}
+}
+
+func TestDevRunnerRun(t *testing.T) {
+	// Disable the colored service prefixes for the expected output
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	services := []*ServiceConfig{
+		createDevServiceConfig(t, "api", ServiceLanguageDotNet, nil),
+		createDevServiceConfig(t, "web", ServiceLanguageJavaScript, map[string]string{
+			"package.json": `{"scripts": {"dev": "vite"}}`,
+		}),
+	}
+
+	t.Run("PrefixesOutput", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.Contains(t, args.Env, "AZURE_ENV_NAME=dev")
+			fmt.Fprintf(args.Stdout, "%s started\n", args.Cmd)
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		buf := &bytes.Buffer{}
+		runner := NewDevRunner(mockContext.CommandRunner)
+		err := runner.Run(*mockContext.Context, services, []string{"AZURE_ENV_NAME=dev"}, buf)
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.ElementsMatch(t, []string{"api | dotnet started", "web | npm started"}, lines)
+	})
+
+	t.Run("Dockerfile", func(t *testing.T) {
+		service := createDevServiceConfig(t, "api", ServiceLanguageJava, map[string]string{"Dockerfile": "FROM scratch"})
+		service.Host = ContainerAppTarget
+		service.Docker.Env = []string{"API_KEY"}
+
+		var commands []string
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.Contains(t, args.Env, "API_KEY=secret")
+			commands = append(commands, strings.Join(append([]string{args.Cmd}, args.Args...), " "))
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		runner := NewDevRunner(mockContext.CommandRunner)
+		err := runner.Run(*mockContext.Context, []*ServiceConfig{service}, []string{"API_KEY=secret"}, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"docker build -f ./Dockerfile -t api-dev --build-arg API_KEY .",
+			"docker run --rm --init --publish-all --env API_KEY api-dev",
+		}, commands)
+	})
+
+	t.Run("FailingService", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "npm")
+		}).Respond(exec.NewRunResult(0, "", ""))
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "dotnet")
+		}).SetError(errors.New("build failed"))
+
+		runner := NewDevRunner(mockContext.CommandRunner)
+		err := runner.Run(*mockContext.Context, services, nil, &bytes.Buffer{})
+		require.ErrorContains(t, err, "service 'api' exited")
+	})
+}
+
+func createDevServiceConfig(
+	t *testing.T,
+	name string,
+	language ServiceLanguageKind,
+	files map[string]string,
+) *ServiceConfig {
+	projectPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, name), osutil.PermissionDirectory))
+
+	for fileName, content := range files {
+		err := os.WriteFile(filepath.Join(projectPath, name, fileName), []byte(content), osutil.PermissionFile)
+		require.NoError(t, err)
+	}
+
+	return &ServiceConfig{
+		Project:      &ProjectConfig{Path: projectPath},
+		Name:         name,
+		RelativePath: name,
+		Language:     language,
+	}
+}
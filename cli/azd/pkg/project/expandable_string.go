@@ -6,7 +6,7 @@ package project
 import (
 	"fmt"
 
-	"github.com/drone/envsubst"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 )
 
 func NewExpandableString(template string) ExpandableString {
@@ -15,20 +15,34 @@ func NewExpandableString(template string) ExpandableString {
 	}
 }
 
-// ExpandableString is a string that has ${foo} style references inside which can be evaluated.
+// ExpandableString is a string that has ${foo} or ${env:foo} style references inside which can be evaluated.
+// A reference is escaped with a second $, like $${foo}.
 type ExpandableString struct {
 	template string
 }
 
+// Empty returns true when the template is empty, like a property missing from azure.yaml.
+func (e ExpandableString) Empty() bool {
+	return e.template == ""
+}
+
 // Envsubst evaluates the template, substituting values as [envsubst.Eval] would.
 func (e ExpandableString) Envsubst(mapping func(string) string) (string, error) {
-	return envsubst.Eval(e.template, mapping)
+	return environment.Interpolate(e.template, func(key string) (string, bool) {
+		return mapping(key), true
+	})
+}
+
+// Resolve evaluates the template against the values of env, and fails with an
+// [environment.UnresolvedReferencesError] when the template references values which aren't set.
+func (e ExpandableString) Resolve(env *environment.Environment) (string, error) {
+	return environment.Interpolate(e.template, env.LookupEnv)
 }
 
 // MustEnvsubst evaluates the template, substituting values as [envsubst.Eval] would and panics if there
 // is an error (for example, the string is malformed).
 func (e ExpandableString) MustEnvsubst(mapping func(string) string) string {
-	if v, err := envsubst.Eval(e.template, mapping); err != nil {
+	if v, err := e.Envsubst(mapping); err != nil {
 		panic(fmt.Sprintf("MustEnvsubst: %v", err))
 	} else {
 		return v
@@ -6,6 +6,7 @@ package project
 import (
 	"testing"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 )
@@ -23,3 +24,32 @@ func TestExpandableStringYaml(t *testing.T) {
 
 	assert.Equal(t, "${foo}\n", string(marshalled))
 }
+
+func TestExpandableStringResolve(t *testing.T) {
+	env := environment.EphemeralWithValues("test", map[string]string{
+		"APP_NAME": "todo",
+	})
+
+	value, err := NewExpandableString("app-${env:APP_NAME}-${AZURE_ENV_NAME}").Resolve(env)
+	assert.NoError(t, err)
+	assert.Equal(t, "app-todo-test", value)
+
+	value, err = NewExpandableString("$${APP_NAME}").Resolve(env)
+	assert.NoError(t, err)
+	assert.Equal(t, "${APP_NAME}", value)
+
+	_, err = NewExpandableString("app-${UNSET_APP_NAME}").Resolve(env)
+	assert.ErrorContains(t, err, "${UNSET_APP_NAME} not set in the environment")
+
+	// Envsubst substitutes the unset values with the value of the mapping
+	value, err = NewExpandableString("app-${UNSET_APP_NAME}").Envsubst(env.Getenv)
+	assert.NoError(t, err)
+	assert.Equal(t, "app-", value)
+}
+
+func TestExpandableStringEmpty(t *testing.T) {
+	var e ExpandableString
+	assert.True(t, e.Empty())
+
+	assert.False(t, NewExpandableString("${foo}").Empty())
+}
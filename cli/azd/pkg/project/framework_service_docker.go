@@ -23,6 +23,19 @@ type DockerProjectOptions struct {
 	Context  string           `json:"context"`
 	Platform string           `json:"platform"`
 	Tag      ExpandableString `json:"tag"`
+	// The registry the image is pushed to, like ghcr.io/contoso. Defaults to the Azure Container Registry of the
+	// environment.
+	Registry ExpandableString `json:"registry"`
+	// The credentials of registries other than Azure Container Registry. When omitted, the credentials stored in the
+	// docker config are used.
+	Username ExpandableString `json:"username"`
+	Password ExpandableString `json:"password"`
+	// The build args passed to docker build, like VERSION=${APP_VERSION}
+	BuildArgs []ExpandableString `yaml:"buildArgs" json:"buildArgs"`
+	// The names of the environment values passed to docker build as build args, and to the container when `azd dev`
+	// runs it. Unlike buildArgs, the values are passed through the environment of docker, so they are never written to
+	// the logs, which suits secrets.
+	Env []string `yaml:"env" json:"env"`
 }
 
 type dockerBuildResult struct {
@@ -141,6 +154,23 @@ func (p *dockerProject) Build(
 				strings.ToLower(serviceConfig.Name),
 			)
 
+			buildArgs := make([]string, len(dockerOptions.BuildArgs))
+			for i, buildArg := range dockerOptions.BuildArgs {
+				value, err := buildArg.Resolve(p.env)
+				if err != nil {
+					task.SetError(fmt.Errorf("resolving docker.buildArgs of service %s: %w", serviceConfig.Name, err))
+					return
+				}
+
+				buildArgs[i] = value
+			}
+
+			buildEnv, err := dockerEnv(p.env, serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			// Build the container
 			task.SetProgress(NewServiceProgress("Building docker image"))
 			imageId, err := p.docker.Build(
@@ -150,6 +180,8 @@ func (p *dockerProject) Build(
 				dockerOptions.Platform,
 				dockerOptions.Context,
 				imageName,
+				buildArgs,
+				buildEnv,
 			)
 			if err != nil {
 				task.SetError(fmt.Errorf("building container: %s at %s: %w", serviceConfig.Name, dockerOptions.Context, err))
@@ -208,6 +240,23 @@ func (p *dockerProject) Package(
 	)
 }
 
+// dockerEnv returns the environment values named by docker.env of the service, as KEY=value.
+func dockerEnv(env *environment.Environment, serviceConfig *ServiceConfig) ([]string, error) {
+	values := make([]string, len(serviceConfig.Docker.Env))
+	for i, name := range serviceConfig.Docker.Env {
+		value, has := env.LookupEnv(name)
+		if !has {
+			return nil, fmt.Errorf(
+				"resolving docker.env of service %s: %s isn't set in environment %s",
+				serviceConfig.Name, name, env.GetEnvName())
+		}
+
+		values[i] = fmt.Sprintf("%s=%s", name, value)
+	}
+
+	return values, nil
+}
+
 func getDockerOptionsWithDefaults(options DockerProjectOptions) DockerProjectOptions {
 	if options.Path == "" {
 		options.Path = "./Dockerfile"
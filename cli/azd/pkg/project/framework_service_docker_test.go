@@ -85,7 +85,7 @@ services:
 	internalFramework := NewNpmProject(npmCli, env)
 	progressMessages := []string{}
 
-	framework := NewDockerProject(env, docker, NewContainerHelper(env, clock.NewMock(), nil, docker))
+	framework := NewDockerProject(env, docker, NewContainerHelper(env, clock.NewMock(), nil, docker, nil, nil))
 	framework.SetSource(internalFramework)
 
 	buildTask := framework.Build(*mockContext.Context, service, nil)
@@ -174,7 +174,7 @@ services:
 	internalFramework := NewNpmProject(npmCli, env)
 	status := ""
 
-	framework := NewDockerProject(env, docker, NewContainerHelper(env, clock.NewMock(), nil, docker))
+	framework := NewDockerProject(env, docker, NewContainerHelper(env, clock.NewMock(), nil, docker, nil, nil))
 	framework.SetSource(internalFramework)
 
 	buildTask := framework.Build(*mockContext.Context, service, nil)
@@ -211,7 +211,7 @@ func Test_DockerProject_Build(t *testing.T) {
 	dockerCli := docker.NewDocker(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 
-	dockerProject := NewDockerProject(env, dockerCli, NewContainerHelper(env, clock.NewMock(), nil, dockerCli))
+	dockerProject := NewDockerProject(env, dockerCli, NewContainerHelper(env, clock.NewMock(), nil, dockerCli, nil, nil))
 	buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
 	logProgress(buildTask)
 
@@ -256,7 +256,7 @@ func Test_DockerProject_Package(t *testing.T) {
 	dockerCli := docker.NewDocker(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 
-	dockerProject := NewDockerProject(env, dockerCli, NewContainerHelper(env, clock.NewMock(), nil, dockerCli))
+	dockerProject := NewDockerProject(env, dockerCli, NewContainerHelper(env, clock.NewMock(), nil, dockerCli, nil, nil))
 	packageTask := dockerProject.Package(
 		*mockContext.Context,
 		serviceConfig,
@@ -284,3 +284,79 @@ func Test_DockerProject_Package(t *testing.T) {
 		runArgs.Args,
 	)
 }
+
+func Test_DockerProject_BuildArgs(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker build")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "IMAGE_ID", ""), nil
+		})
+
+	env := environment.EphemeralWithValues("test", map[string]string{
+		"APP_VERSION": "1.2.0",
+		"API_KEY":     "secret",
+	})
+	dockerCli := docker.NewDocker(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.BuildArgs = []ExpandableString{
+		NewExpandableString("VERSION=${env:APP_VERSION}"),
+		NewExpandableString("CHANNEL=${CHANNEL=stable}"),
+	}
+
+	dockerProject := NewDockerProject(env, dockerCli, NewContainerHelper(env, clock.NewMock(), nil, dockerCli, nil, nil))
+	buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
+	logProgress(buildTask)
+
+	_, err := buildTask.Await()
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{
+			"build", "-q",
+			"-f", "./Dockerfile",
+			"--platform", "amd64",
+			"-t", "test-app-api",
+			"--build-arg", "VERSION=1.2.0",
+			"--build-arg", "CHANNEL=stable",
+			".",
+		},
+		runArgs.Args,
+	)
+
+	t.Run("Env", func(t *testing.T) {
+		serviceConfig.Docker.Env = []string{"API_KEY"}
+		defer func() { serviceConfig.Docker.Env = nil }()
+
+		buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
+		logProgress(buildTask)
+
+		_, err := buildTask.Await()
+		require.NoError(t, err)
+		require.Contains(t, runArgs.Args, "API_KEY")
+		require.NotContains(t, strings.Join(runArgs.Args, " "), "secret")
+		require.Equal(t, []string{"API_KEY=secret"}, runArgs.Env)
+
+		serviceConfig.Docker.Env = []string{"MISSING_KEY"}
+		buildTask = dockerProject.Build(*mockContext.Context, serviceConfig, nil)
+		logProgress(buildTask)
+
+		_, err = buildTask.Await()
+		require.ErrorContains(t, err, "MISSING_KEY isn't set in environment test")
+	})
+
+	t.Run("Unresolved", func(t *testing.T) {
+		serviceConfig.Docker.BuildArgs = []ExpandableString{NewExpandableString("REGION=${DEPLOY_REGION}")}
+
+		buildTask := dockerProject.Build(*mockContext.Context, serviceConfig, nil)
+		logProgress(buildTask)
+
+		_, err := buildTask.Await()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "${DEPLOY_REGION} not set in the environment")
+	})
+}
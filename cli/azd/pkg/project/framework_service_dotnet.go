@@ -5,12 +5,14 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -22,6 +24,62 @@ const (
 	defaultDotNetBuildConfiguration string = "Release"
 )
 
+// DotNetOptions configures how .NET services are built and published.
+type DotNetOptions struct {
+	// The build configuration, Release by default.
+	Configuration string `yaml:"configuration,omitempty"`
+	// The runtime identifier of the host, like linux-x64 or win-x64. Required by the other publish options, so the
+	// application isn't published for the platform of the machine running azd.
+	RuntimeIdentifier string `yaml:"runtimeIdentifier,omitempty"`
+	// Publishes the .NET runtime with the application, like for the custom handlers of Azure Functions.
+	SelfContained *bool `yaml:"selfContained,omitempty"`
+	// Compiles the assemblies ahead of time to reduce the startup time of the application.
+	ReadyToRun bool `yaml:"readyToRun,omitempty"`
+	// Trims the assemblies not used by a self-contained application.
+	Trimmed bool `yaml:"trimmed,omitempty"`
+	// The publish profile of the project, the name of a .pubxml file of Properties/PublishProfiles.
+	PublishProfile string `yaml:"publishProfile,omitempty"`
+}
+
+// configuration returns the build configuration of the options, Release by default.
+func (o *DotNetOptions) configuration() string {
+	if o.Configuration == "" {
+		return defaultDotNetBuildConfiguration
+	}
+
+	return o.Configuration
+}
+
+// validate checks the publish options targeting a platform have the runtime identifier of the platform.
+func (o *DotNetOptions) validate() error {
+	if o.RuntimeIdentifier == "" {
+		switch {
+		case o.SelfContained != nil && *o.SelfContained:
+			return errors.New("dotnet.selfContained requires dotnet.runtimeIdentifier")
+		case o.ReadyToRun:
+			return errors.New("dotnet.readyToRun requires dotnet.runtimeIdentifier")
+		case o.Trimmed:
+			return errors.New("dotnet.trimmed requires dotnet.runtimeIdentifier")
+		}
+	}
+
+	if o.Trimmed && (o.SelfContained == nil || !*o.SelfContained) {
+		return errors.New("dotnet.trimmed requires dotnet.selfContained, only self-contained applications can be trimmed")
+	}
+
+	return nil
+}
+
+func (o *DotNetOptions) publishOptions() dotnet.PublishOptions {
+	return dotnet.PublishOptions{
+		RuntimeIdentifier: o.RuntimeIdentifier,
+		SelfContained:     o.SelfContained,
+		ReadyToRun:        o.ReadyToRun,
+		Trimmed:           o.Trimmed,
+		PublishProfile:    o.PublishProfile,
+	}
+}
+
 type dotnetProject struct {
 	env       *environment.Environment
 	dotnetCli dotnet.DotNetCli
@@ -109,12 +167,13 @@ func (dp *dotnetProject) Build(
 				task.SetError(err)
 				return
 			}
-			if err := dp.dotnetCli.Build(ctx, projFile, defaultDotNetBuildConfiguration, ""); err != nil {
+			configuration := serviceConfig.DotNet.configuration()
+			if err := dp.dotnetCli.Build(ctx, projFile, configuration, ""); err != nil {
 				task.SetError(err)
 				return
 			}
 
-			defaultOutputDir := filepath.Join("./bin", defaultDotNetBuildConfiguration)
+			defaultOutputDir := filepath.Join("./bin", configuration)
 
 			// Attempt to find the default build output location
 			buildOutputDir := serviceConfig.Path()
@@ -147,7 +206,12 @@ func (dp *dotnetProject) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
-			packageDest, err := os.MkdirTemp("", "azd")
+			if err := serviceConfig.DotNet.validate(); err != nil {
+				task.SetError(fmt.Errorf("publish options of %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			packageDest, err := artifacts.MkdirTemp("azd")
 			if err != nil {
 				task.SetError(fmt.Errorf("creating package directory for %s: %w", serviceConfig.Name, err))
 				return
@@ -159,7 +223,13 @@ func (dp *dotnetProject) Package(
 				task.SetError(err)
 				return
 			}
-			if err := dp.dotnetCli.Publish(ctx, projFile, defaultDotNetBuildConfiguration, packageDest); err != nil {
+			if err := dp.dotnetCli.Publish(
+				ctx,
+				projFile,
+				serviceConfig.DotNet.configuration(),
+				packageDest,
+				serviceConfig.DotNet.publishOptions(),
+			); err != nil {
 				task.SetError(err)
 				return
 			}
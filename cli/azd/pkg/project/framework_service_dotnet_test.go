@@ -272,3 +272,93 @@ func Test_DotNetProject_Package(t *testing.T) {
 		runArgs.Args,
 	)
 }
+
+func Test_DotNetProject_Package_PublishOptions(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+	require.NoError(t, os.MkdirAll("./src/api", osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile("./src/api/api.csproj", nil, osutil.PermissionFile))
+
+	var runArgs exec.RunArgs
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "dotnet publish")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			err := os.WriteFile(filepath.Join(args.Args[5], "api"), nil, osutil.PermissionFile)
+			return exec.NewRunResult(0, "", ""), err
+		})
+
+	selfContained := true
+	serviceConfig := createTestServiceConfig("./src/api", AzureFunctionTarget, ServiceLanguageCsharp)
+	serviceConfig.DotNet = DotNetOptions{
+		Configuration:     "Production",
+		RuntimeIdentifier: "linux-x64",
+		SelfContained:     &selfContained,
+		ReadyToRun:        true,
+		Trimmed:           true,
+	}
+
+	dotnetProject := NewDotNetProject(dotnet.NewDotNetCli(mockContext.CommandRunner), environment.Ephemeral())
+	packageTask := dotnetProject.Package(*mockContext.Context, serviceConfig, &ServiceBuildResult{})
+	logProgress(packageTask)
+
+	result, err := packageTask.Await()
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{"publish",
+			filepath.Join("src", "api", "api.csproj"),
+			"-c",
+			"Production",
+			"--output",
+			result.PackagePath,
+			"-r",
+			"linux-x64",
+			"--self-contained=true",
+			"-p:PublishReadyToRun=true",
+			"-p:PublishTrimmed=true",
+		},
+		runArgs.Args,
+	)
+}
+
+func Test_DotNetOptions_validate(t *testing.T) {
+	selfContained := true
+	tests := []struct {
+		name     string
+		options  DotNetOptions
+		expected string
+	}{
+		{name: "Default"},
+		{name: "RuntimeIdentifier", options: DotNetOptions{RuntimeIdentifier: "linux-x64", ReadyToRun: true}},
+		{
+			name:     "SelfContainedWithoutRuntimeIdentifier",
+			options:  DotNetOptions{SelfContained: &selfContained},
+			expected: "dotnet.selfContained requires dotnet.runtimeIdentifier",
+		},
+		{
+			name:     "ReadyToRunWithoutRuntimeIdentifier",
+			options:  DotNetOptions{ReadyToRun: true},
+			expected: "dotnet.readyToRun requires dotnet.runtimeIdentifier",
+		},
+		{
+			name:     "TrimmedFrameworkDependent",
+			options:  DotNetOptions{RuntimeIdentifier: "linux-x64", Trimmed: true},
+			expected: "dotnet.trimmed requires dotnet.selfContained",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.validate()
+			if tt.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
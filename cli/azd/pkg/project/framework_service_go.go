@@ -0,0 +1,231 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
+)
+
+// The executable of the custom handlers of Azure Functions, referenced by the defaultExecutablePath of host.json
+const goFunctionHandlerBinary = "handler"
+
+// GoOptions configures how Go services are built.
+type GoOptions struct {
+	// The main package of the service, like ./cmd/api. The package of the service directory by default.
+	Package string `yaml:"package,omitempty"`
+	// The operating system of the host, linux by default.
+	OS string `yaml:"os,omitempty"`
+	// The architecture of the host, amd64 by default.
+	Arch string `yaml:"arch,omitempty"`
+	// The name of the binary, handler for Azure Functions and the name of the service otherwise.
+	Binary string `yaml:"binary,omitempty"`
+}
+
+// buildOptions returns the options of go build cross-compiling the service for the host. cgo is disabled, so the
+// binary doesn't depend on the C libraries of the machine running azd.
+func (o *GoOptions) buildOptions() golang.BuildOptions {
+	options := golang.BuildOptions{
+		OS:         o.OS,
+		Arch:       o.Arch,
+		DisableCgo: true,
+		Flags:      []string{"-trimpath"},
+	}
+
+	if options.OS == "" {
+		options.OS = "linux"
+	}
+	if options.Arch == "" {
+		options.Arch = "amd64"
+	}
+
+	return options
+}
+
+// mainPackage returns the main package of the service, relative to the service directory.
+func (o *GoOptions) mainPackage() string {
+	if o.Package == "" {
+		return "."
+	}
+
+	return o.Package
+}
+
+// binary returns the name of the binary of the service, with the .exe extension for Windows hosts.
+func (o *GoOptions) binary(serviceConfig *ServiceConfig) string {
+	binary := o.Binary
+	if binary == "" {
+		binary = serviceConfig.Name
+		if serviceConfig.Host == AzureFunctionTarget {
+			binary = goFunctionHandlerBinary
+		}
+	}
+
+	if o.OS == "windows" && filepath.Ext(binary) != ".exe" {
+		binary += ".exe"
+	}
+
+	return binary
+}
+
+type goProject struct {
+	env *environment.Environment
+	cli golang.GoCli
+}
+
+// NewGoProject creates a new instance of a Go project
+func NewGoProject(cli golang.GoCli, env *environment.Environment) FrameworkService {
+	return &goProject{
+		env: env,
+		cli: cli,
+	}
+}
+
+func (gp *goProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		// go build will automatically download the modules & build the project if needed
+		Package: FrameworkPackageRequirements{
+			RequireRestore: false,
+			RequireBuild:   false,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (gp *goProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{gp.cli}
+}
+
+// Initializes the Go project
+func (gp *goProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Restores the modules of the project into the module cache of Go, shared by the builds of all the services
+func (gp *goProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Downloading Go modules"))
+			if err := gp.cli.ModDownload(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Builds the binary of the service for the host. Unchanged packages are reused from the build cache of Go.
+func (gp *goProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			buildDest, err := artifacts.MkdirTemp("azd")
+			if err != nil {
+				task.SetError(fmt.Errorf("creating build directory for %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Building Go project"))
+			if err := gp.build(ctx, serviceConfig, buildDest); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: buildDest,
+			})
+		},
+	)
+}
+
+// Package copies the files of the service, like host.json and the function.json files of Azure Functions, without the
+// Go sources, and builds the binary of the service at the root of the package.
+func (gp *goProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			packageDest, err := artifacts.MkdirTemp("azd")
+			if err != nil {
+				task.SetError(fmt.Errorf("creating package directory for %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Copying deployment package"))
+			if err := buildForZip(
+				serviceConfig.Path(),
+				packageDest,
+				buildForZipOptions{
+					excludeConditions: []excludeDirEntryCondition{
+						excludeGoSources,
+					},
+				}); err != nil {
+				task.SetError(fmt.Errorf("packaging for %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Building Go binary"))
+			if err := gp.build(ctx, serviceConfig, packageDest); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			if err := validatePackageOutput(packageDest); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: packageDest,
+			})
+		},
+	)
+}
+
+// build cross-compiles the main package of the service into outputDir.
+func (gp *goProject) build(ctx context.Context, serviceConfig *ServiceConfig, outputDir string) error {
+	output := filepath.Join(outputDir, serviceConfig.Go.binary(serviceConfig))
+	return gp.cli.Build(
+		ctx,
+		serviceConfig.Path(),
+		serviceConfig.Go.mainPackage(),
+		output,
+		serviceConfig.Go.buildOptions(),
+	)
+}
+
+// excludeGoSources excludes the sources, the modules and the vendored packages of Go projects, compiled into the binary.
+func excludeGoSources(path string, file os.FileInfo) bool {
+	if file.IsDir() {
+		return file.Name() == "vendor" || file.Name() == ".git"
+	}
+
+	switch file.Name() {
+	case "go.mod", "go.sum", "go.work", "go.work.sum":
+		return true
+	}
+
+	return strings.HasSuffix(file.Name(), ".go")
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GoProject_Restore(t *testing.T) {
+	var runArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "go mod download")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageGo)
+	goProject := NewGoProject(golang.NewGoCli(mockContext.CommandRunner), environment.Ephemeral())
+	restoreTask := goProject.Restore(*mockContext.Context, serviceConfig)
+	logProgress(restoreTask)
+
+	_, err := restoreTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, "go", runArgs.Cmd)
+	require.Equal(t, serviceConfig.Path(), runArgs.Cwd)
+}
+
+func Test_GoProject_Package(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       ServiceTargetKind
+		options    GoOptions
+		wantBinary string
+		wantEnv    []string
+	}{
+		{
+			name:       "Function",
+			host:       AzureFunctionTarget,
+			wantBinary: "handler",
+			wantEnv:    []string{"GOOS=linux", "GOARCH=amd64", "CGO_ENABLED=0"},
+		},
+		{
+			name:       "WindowsAppService",
+			host:       AppServiceTarget,
+			options:    GoOptions{OS: "windows", Package: "./cmd/api"},
+			wantBinary: "api.exe",
+			wantEnv:    []string{"GOOS=windows", "GOARCH=amd64", "CGO_ENABLED=0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			var runArgs exec.RunArgs
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return strings.Contains(command, "go build")
+				}).
+				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+					runArgs = args
+					return exec.NewRunResult(0, "", ""), os.WriteFile(args.Args[2], nil, osutil.PermissionExecutableFile)
+				})
+
+			serviceConfig := createTestServiceConfig("./src/api", tt.host, ServiceLanguageGo)
+			serviceConfig.Go = tt.options
+			for _, file := range []string{"main.go", "go.mod", "go.sum", "host.json"} {
+				require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+				require.NoError(t, os.WriteFile(filepath.Join(serviceConfig.Path(), file), nil, osutil.PermissionFile))
+			}
+
+			goProject := NewGoProject(golang.NewGoCli(mockContext.CommandRunner), environment.Ephemeral())
+			packageTask := goProject.Package(*mockContext.Context, serviceConfig, &ServiceBuildResult{})
+			logProgress(packageTask)
+
+			result, err := packageTask.Await()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantEnv, runArgs.Env)
+			require.Equal(t, tt.options.mainPackage(), runArgs.Args[len(runArgs.Args)-1])
+
+			entries, err := os.ReadDir(result.PackagePath)
+			require.NoError(t, err)
+			names := []string{}
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			require.ElementsMatch(t, []string{"host.json", tt.wantBinary}, names)
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
+	"github.com/otiai10/copy"
+)
+
+// The default, conventional App Service Java package name
+const AppServiceJavaPackageName = "app"
+
+type JavaBuildToolKind string
+
+const (
+	JavaBuildToolMaven  JavaBuildToolKind = "maven"
+	JavaBuildToolGradle JavaBuildToolKind = "gradle"
+)
+
+// JavaOptions configures how Java services are built.
+type JavaOptions struct {
+	// The build tool of the service. Detected from the build files of the service when not set.
+	BuildTool JavaBuildToolKind `yaml:"buildTool,omitempty"`
+	// Runs the tests of the service when it's packaged. The tests are skipped by default.
+	RunTests bool `yaml:"runTests,omitempty"`
+}
+
+// javaBuildTool is the build tool of a Java service, Maven or Gradle, each using its wrapper script when the project
+// has one.
+type javaBuildTool interface {
+	tools.ExternalTool
+	SetPath(projectPath string, rootProjectPath string)
+	ResolveDependencies(ctx context.Context, projectPath string) error
+	Compile(ctx context.Context, projectPath string) error
+	Package(ctx context.Context, projectPath string, skipTests bool) error
+	BuildOutputPath(projectPath string) string
+}
+
+type javaProject struct {
+	env       *environment.Environment
+	mavenCli  maven.MavenCli
+	gradleCli gradle.GradleCli
+	javacCli  javac.JavacCli
+
+	// The build tools of the initialized services
+	buildToolKinds map[JavaBuildToolKind]bool
+}
+
+// NewJavaProject creates a new instance of a Java project, built with Maven or Gradle
+func NewJavaProject(
+	env *environment.Environment,
+	mavenCli maven.MavenCli,
+	gradleCli gradle.GradleCli,
+	javaCli javac.JavacCli,
+) FrameworkService {
+	return &javaProject{
+		env:            env,
+		mavenCli:       mavenCli,
+		gradleCli:      gradleCli,
+		javacCli:       javaCli,
+		buildToolKinds: map[JavaBuildToolKind]bool{},
+	}
+}
+
+func (m *javaProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		// Maven and Gradle will automatically restore & build the project if needed
+		Package: FrameworkPackageRequirements{
+			RequireRestore: false,
+			RequireBuild:   false,
+		},
+	}
+}
+
+// Gets the required external tools for the project, the build tools of the initialized services and javac
+func (m *javaProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	requiredTools := []tools.ExternalTool{}
+	if m.buildToolKinds[JavaBuildToolMaven] || len(m.buildToolKinds) == 0 {
+		requiredTools = append(requiredTools, m.mavenCli)
+	}
+	if m.buildToolKinds[JavaBuildToolGradle] {
+		requiredTools = append(requiredTools, m.gradleCli)
+	}
+
+	return append(requiredTools, m.javacCli)
+}
+
+// Initializes the java project
+func (m *javaProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	kind, err := javaBuildToolKind(serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	m.buildToolKinds[kind] = true
+	m.buildTool(kind).SetPath(serviceConfig.Path(), serviceConfig.Project.Path)
+	return nil
+}
+
+// javaBuildToolKind returns the build tool set in the options of the service, or Gradle when the service has a Gradle
+// build file, Maven otherwise.
+func javaBuildToolKind(serviceConfig *ServiceConfig) (JavaBuildToolKind, error) {
+	switch serviceConfig.Java.BuildTool {
+	case JavaBuildToolMaven, JavaBuildToolGradle:
+		return serviceConfig.Java.BuildTool, nil
+	case "":
+	default:
+		return "", fmt.Errorf(
+			"unsupported java build tool '%s' for service '%s', supported build tools are maven and gradle",
+			serviceConfig.Java.BuildTool,
+			serviceConfig.Name,
+		)
+	}
+
+	for _, buildFile := range []string{"build.gradle", "build.gradle.kts"} {
+		if _, err := os.Stat(filepath.Join(serviceConfig.Path(), buildFile)); err == nil {
+			return JavaBuildToolGradle, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("checking for %s: %w", buildFile, err)
+		}
+	}
+
+	return JavaBuildToolMaven, nil
+}
+
+func (m *javaProject) buildTool(kind JavaBuildToolKind) javaBuildTool {
+	if kind == JavaBuildToolGradle {
+		return m.gradleCli
+	}
+
+	return m.mavenCli
+}
+
+func (m *javaProject) serviceBuildTool(serviceConfig *ServiceConfig) (javaBuildTool, error) {
+	kind, err := javaBuildToolKind(serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.buildTool(kind), nil
+}
+
+// Restores dependencies using the Maven or Gradle CLI
+func (m *javaProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			buildTool, err := m.serviceBuildTool(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			toolName := strings.ToLower(buildTool.Name())
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Resolving %s dependencies", toolName)))
+			if err := buildTool.ResolveDependencies(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(fmt.Errorf("resolving %s dependencies: %w", toolName, err))
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Builds the java project
+func (m *javaProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			buildTool, err := m.serviceBuildTool(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Compiling %s project", strings.ToLower(buildTool.Name()))))
+			if err := buildTool.Compile(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: serviceConfig.Path(),
+			})
+		},
+	)
+}
+
+func (m *javaProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			buildTool, err := m.serviceBuildTool(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			packageDest, err := artifacts.MkdirTemp("azd")
+			if err != nil {
+				task.SetError(fmt.Errorf("creating staging directory: %w", err))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Packaging %s project", strings.ToLower(buildTool.Name()))))
+			if err := buildTool.Package(ctx, serviceConfig.Path(), !serviceConfig.Java.RunTests); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			packageSrcPath := buildOutput.BuildOutputPath
+			if packageSrcPath == "" {
+				packageSrcPath = serviceConfig.Path()
+			}
+
+			if serviceConfig.OutputPath != "" {
+				packageSrcPath = filepath.Join(packageSrcPath, serviceConfig.OutputPath)
+			} else {
+				packageSrcPath = buildTool.BuildOutputPath(packageSrcPath)
+			}
+
+			packageSrcFileInfo, err := os.Stat(packageSrcPath)
+			if err != nil {
+				if serviceConfig.OutputPath == "" {
+					task.SetError(fmt.Errorf("reading default build output path %s: %w", packageSrcPath, err))
+				} else {
+					task.SetError(fmt.Errorf("reading dist path %s: %w", packageSrcPath, err))
+				}
+				return
+			}
+
+			archive := ""
+			if packageSrcFileInfo.IsDir() {
+				archive, err = m.discoverArchive(packageSrcPath)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+			} else {
+				archive = packageSrcPath
+				if !isSupportedJavaArchive(archive) {
+					ext := filepath.Ext(archive)
+					task.SetError(
+						fmt.Errorf(
+							//nolint:lll
+							"file %s with extension %s is not a supported java archive file (.ear, .war, .jar)", ext, archive))
+					return
+				}
+			}
+
+			task.SetProgress(NewServiceProgress("Copying deployment package"))
+			ext := strings.ToLower(filepath.Ext(archive))
+			err = copy.Copy(archive, filepath.Join(packageDest, AppServiceJavaPackageName+ext))
+			if err != nil {
+				task.SetError(fmt.Errorf("copying to staging directory failed: %w", err))
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: packageDest,
+			})
+		},
+	)
+}
+
+func isSupportedJavaArchive(archiveFile string) bool {
+	ext := strings.ToLower(filepath.Ext(archiveFile))
+	return ext == ".jar" || ext == ".war" || ext == ".ear"
+}
+
+func (m *javaProject) discoverArchive(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("discovering java archive files in %s: %w", dir, err)
+	}
+
+	archiveFiles := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		// Skip the plain jar built by Gradle next to the executable jar of Spring Boot applications
+		name := entry.Name()
+		if isSupportedJavaArchive(name) && !strings.HasSuffix(name, "-plain.jar") {
+			archiveFiles = append(archiveFiles, name)
+		}
+	}
+
+	switch len(archiveFiles) {
+	case 0:
+		return "", fmt.Errorf("no java archive files (.jar, .ear, .war) found in %s", dir)
+	case 1:
+		return filepath.Join(dir, archiveFiles[0]), nil
+	default:
+		names := strings.Join(archiveFiles, ", ")
+		return "", fmt.Errorf(
+			//nolint:lll
+			"multiple java archive files (.jar, .ear, .war) found in %s: %s. To pick a specific archive to be used, specify the relative path to the archive file using the 'dist' property in azure.yaml",
+			dir,
+			names,
+		)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/gradle"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
@@ -43,9 +45,10 @@ func Test_MavenProject(t *testing.T) {
 		env := environment.Ephemeral()
 		serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJava)
 		mavenCli := maven.NewMavenCli(mockContext.CommandRunner)
+		gradleCli := gradle.NewGradleCli(mockContext.CommandRunner)
 		javaCli := javac.NewCli(mockContext.CommandRunner)
 
-		mavenProject := NewMavenProject(env, mavenCli, javaCli)
+		mavenProject := NewJavaProject(env, mavenCli, gradleCli, javaCli)
 		err = mavenProject.Initialize(*mockContext.Context, serviceConfig)
 		require.NoError(t, err)
 
@@ -79,9 +82,10 @@ func Test_MavenProject(t *testing.T) {
 		env := environment.Ephemeral()
 		serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJava)
 		mavenCli := maven.NewMavenCli(mockContext.CommandRunner)
+		gradleCli := gradle.NewGradleCli(mockContext.CommandRunner)
 		javaCli := javac.NewCli(mockContext.CommandRunner)
 
-		mavenProject := NewMavenProject(env, mavenCli, javaCli)
+		mavenProject := NewJavaProject(env, mavenCli, gradleCli, javaCli)
 		err = mavenProject.Initialize(*mockContext.Context, serviceConfig)
 		require.NoError(t, err)
 
@@ -114,6 +118,7 @@ func Test_MavenProject(t *testing.T) {
 		env := environment.Ephemeral()
 		serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageJava)
 		mavenCli := maven.NewMavenCli(mockContext.CommandRunner)
+		gradleCli := gradle.NewGradleCli(mockContext.CommandRunner)
 		javaCli := javac.NewCli(mockContext.CommandRunner)
 
 		// Simulate a build output with a jar file
@@ -123,7 +128,7 @@ func Test_MavenProject(t *testing.T) {
 		err = os.WriteFile(filepath.Join(buildOutputDir, "test.jar"), []byte("test"), osutil.PermissionFile)
 		require.NoError(t, err)
 
-		mavenProject := NewMavenProject(env, mavenCli, javaCli)
+		mavenProject := NewJavaProject(env, mavenCli, gradleCli, javaCli)
 		err = mavenProject.Initialize(*mockContext.Context, serviceConfig)
 		require.NoError(t, err)
 
@@ -283,8 +288,9 @@ func Test_MavenProject_Package(t *testing.T) {
 
 			env := environment.Ephemeral()
 			mavenCli := maven.NewMavenCli(mockContext.CommandRunner)
+			gradleCli := gradle.NewGradleCli(mockContext.CommandRunner)
 			javaCli := javac.NewCli(mockContext.CommandRunner)
-			mavenProject := NewMavenProject(env, mavenCli, javaCli)
+			mavenProject := NewJavaProject(env, mavenCli, gradleCli, javaCli)
 			err = mavenProject.Initialize(*mockContext.Context, tt.args.svc)
 			require.NoError(t, err)
 
@@ -312,6 +318,87 @@ func Test_MavenProject_Package(t *testing.T) {
 	}
 }
 
+func Test_GradleProject_Package(t *testing.T) {
+	tests := []struct {
+		name     string
+		runTests bool
+		wantArgs []string
+	}{
+		{name: "SkipTests", wantArgs: []string{"build", "--console=plain", "-x", "test"}},
+		{name: "RunTests", runTests: true, wantArgs: []string{"build", "--console=plain"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp := t.TempDir()
+			svcDir := filepath.Join(temp, "src", "api")
+			require.NoError(t, os.MkdirAll(svcDir, osutil.PermissionDirectory))
+			require.NoError(t, os.WriteFile(filepath.Join(svcDir, "build.gradle"), nil, osutil.PermissionFile))
+			err := os.WriteFile(filepath.Join(svcDir, getGradlewCmd()), nil, osutil.PermissionExecutableFile)
+			require.NoError(t, err)
+
+			var runArgs exec.RunArgs
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return strings.Contains(command, fmt.Sprintf("%s build", getGradlewCmd()))
+				}).
+				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+					runArgs = args
+
+					// Spring Boot applications build a plain jar next to the executable jar
+					libsDir := filepath.Join(svcDir, "build", "libs")
+					require.NoError(t, os.MkdirAll(libsDir, osutil.PermissionDirectory))
+					for _, name := range []string{"api.jar", "api-plain.jar"} {
+						require.NoError(t, os.WriteFile(filepath.Join(libsDir, name), []byte("test"), osutil.PermissionFile))
+					}
+					return exec.NewRunResult(0, "", ""), nil
+				})
+
+			serviceConfig := &ServiceConfig{
+				Project:         &ProjectConfig{Path: temp},
+				Name:            "api",
+				RelativePath:    "src/api",
+				Host:            AppServiceTarget,
+				Language:        ServiceLanguageJava,
+				Java:            JavaOptions{RunTests: tt.runTests},
+				EventDispatcher: ext.NewEventDispatcher[ServiceLifecycleEventArgs](),
+			}
+
+			env := environment.Ephemeral()
+			mavenCli := maven.NewMavenCli(mockContext.CommandRunner)
+			gradleCli := gradle.NewGradleCli(mockContext.CommandRunner)
+			javaCli := javac.NewCli(mockContext.CommandRunner)
+			javaProject := NewJavaProject(env, mavenCli, gradleCli, javaCli)
+			require.NoError(t, javaProject.Initialize(*mockContext.Context, serviceConfig))
+			require.Equal(t,
+				[]tools.ExternalTool{gradleCli, javaCli},
+				javaProject.RequiredExternalTools(*mockContext.Context),
+			)
+
+			packageTask := javaProject.Package(*mockContext.Context, serviceConfig, &ServiceBuildResult{})
+			logProgress(packageTask)
+
+			result, err := packageTask.Await()
+			require.NoError(t, err)
+			require.Contains(t, runArgs.Cmd, getGradlewCmd())
+			require.Equal(t, tt.wantArgs, runArgs.Args)
+
+			contents, err := os.ReadFile(filepath.Join(result.PackagePath, AppServiceJavaPackageName+".jar"))
+			require.NoError(t, err)
+			require.Equal(t, "test", string(contents))
+		})
+	}
+}
+
+func getGradlewCmd() string {
+	if runtime.GOOS == "windows" {
+		return "gradlew.bat"
+	} else {
+		return "gradlew"
+	}
+}
+
 func getMvnwCmd() string {
 	if runtime.GOOS == "windows" {
 		return "mvnw.cmd"
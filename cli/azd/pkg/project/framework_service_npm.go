@@ -5,16 +5,26 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/otiai10/copy"
 )
 
+// NodeOptions configures how Node.js services are packaged.
+type NodeOptions struct {
+	// Installs the production dependencies of the service into the package, from its lockfile, instead of installing
+	// them on the host after the deployment like the remote build of App Service does.
+	BundleDependencies bool `yaml:"bundleDependencies,omitempty"`
+}
+
 type npmProject struct {
 	env *environment.Environment
 	cli npm.NpmCli
@@ -48,15 +58,33 @@ func (np *npmProject) Initialize(ctx context.Context, serviceConfig *ServiceConf
 	return nil
 }
 
-// Restores dependencies for the NPM project using npm install command
+// Restores dependencies for the NPM project using the install command of the package manager of its lockfile, npm
+// install when the project has no lockfile. The dependencies of the services of a workspace are installed at the root
+// of the workspace, where they're hoisted.
 func (np *npmProject) Restore(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 ) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
-			task.SetProgress(NewServiceProgress("Installing NPM dependencies"))
-			if err := np.cli.Install(ctx, serviceConfig.Path()); err != nil {
+			lockFile, err := npm.FindLockFile(serviceConfig.Path(), serviceConfig.Project.Path)
+			if err != nil {
+				task.SetError(fmt.Errorf("finding lockfile of %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			if lockFile == nil {
+				task.SetProgress(NewServiceProgress("Installing NPM dependencies"))
+				err = np.cli.Install(ctx, serviceConfig.Path())
+			} else {
+				task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing %s dependencies", lockFile.PackageManager)))
+				err = np.cli.InstallDependencies(
+					ctx,
+					filepath.Dir(lockFile.Path),
+					npm.InstallOptions{PackageManager: lockFile.PackageManager},
+				)
+			}
+			if err != nil {
 				task.SetError(err)
 				return
 			}
@@ -103,7 +131,7 @@ func (np *npmProject) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
-			packageDest, err := os.MkdirTemp("", "azd")
+			packageDest, err := artifacts.MkdirTemp("azd")
 			if err != nil {
 				task.SetError(fmt.Errorf("creating package directory for %s: %w", serviceConfig.Name, err))
 				return
@@ -156,6 +184,14 @@ func (np *npmProject) Package(
 				return
 			}
 
+			if serviceConfig.Node.BundleDependencies {
+				task.SetProgress(NewServiceProgress("Installing production dependencies into the package"))
+				if err := np.bundleDependencies(ctx, serviceConfig, packageDest); err != nil {
+					task.SetError(fmt.Errorf("bundling dependencies of %s: %w", serviceConfig.Name, err))
+					return
+				}
+			}
+
 			if err := validatePackageOutput(packageDest); err != nil {
 				task.SetError(err)
 				return
@@ -169,6 +205,44 @@ func (np *npmProject) Package(
 	)
 }
 
+// bundleDependencies installs the production dependencies of the service into the package. The exact versions of the
+// lockfile of the service are installed. The lockfile of a workspace lists the dependencies of all the packages of the
+// workspace, the dependencies of the services of a workspace are resolved from their package.json.
+func (np *npmProject) bundleDependencies(ctx context.Context, serviceConfig *ServiceConfig, packageDest string) error {
+	if _, err := os.Stat(filepath.Join(packageDest, "package.json")); err != nil {
+		return fmt.Errorf("the package has no package.json, the dist directory must contain the package.json: %w", err)
+	}
+
+	servicePath, err := filepath.Abs(serviceConfig.Path())
+	if err != nil {
+		return err
+	}
+
+	lockFile, err := npm.FindLockFile(servicePath, serviceConfig.Project.Path)
+	if err != nil {
+		return fmt.Errorf("finding lockfile: %w", err)
+	}
+
+	options := npm.InstallOptions{Production: true}
+	if lockFile != nil {
+		options.PackageManager = lockFile.PackageManager
+
+		if filepath.Dir(lockFile.Path) == servicePath {
+			// The lockfile isn't in the package when the dist directory is a subdirectory of the service
+			packageLockFile := filepath.Join(packageDest, filepath.Base(lockFile.Path))
+			if _, err := os.Stat(packageLockFile); errors.Is(err, os.ErrNotExist) {
+				if err := copy.Copy(lockFile.Path, packageLockFile); err != nil {
+					return fmt.Errorf("copying lockfile: %w", err)
+				}
+			}
+
+			options.Frozen = true
+		}
+	}
+
+	return np.cli.InstallDependencies(ctx, packageDest, options)
+}
+
 const cNodeModulesName = "node_modules"
 
 func excludeNodeModules(path string, file os.FileInfo) bool {
@@ -123,3 +123,118 @@ func Test_NpmProject_Package(t *testing.T) {
 		runArgs.Args,
 	)
 }
+
+func Test_NpmProject_Package_BundleDependencies(t *testing.T) {
+	tests := []struct {
+		name         string
+		lockFilePath string
+		wantCmd      string
+		wantArgs     []string
+	}{
+		{
+			name:     "NoLockFile",
+			wantCmd:  "npm",
+			wantArgs: []string{"install", "--omit=dev"},
+		},
+		{
+			name:         "ServiceLockFile",
+			lockFilePath: filepath.Join("src", "api", "yarn.lock"),
+			wantCmd:      "yarn",
+			wantArgs:     []string{"install", "--non-interactive", "--frozen-lockfile", "--production"},
+		},
+		{
+			name:         "WorkspaceLockFile",
+			lockFilePath: "pnpm-lock.yaml",
+			wantCmd:      "pnpm",
+			wantArgs:     []string{"install", "--prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			var installArgs exec.RunArgs
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return strings.Contains(command, "npm run build")
+				}).
+				Respond(exec.NewRunResult(0, "", ""))
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return strings.Contains(command, " install")
+				}).
+				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+					installArgs = args
+					return exec.NewRunResult(0, "", ""), nil
+				})
+
+			env := environment.Ephemeral()
+			npmCli := npm.NewNpmCli(mockContext.CommandRunner)
+			serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+			serviceConfig.Node = NodeOptions{BundleDependencies: true}
+			require.NoError(t, os.MkdirAll(filepath.Join(serviceConfig.Path(), "node_modules"), osutil.PermissionDirectory))
+			err := os.WriteFile(filepath.Join(serviceConfig.Path(), "package.json"), nil, osutil.PermissionFile)
+			require.NoError(t, err)
+			if tt.lockFilePath != "" {
+				require.NoError(t, os.WriteFile(tt.lockFilePath, nil, osutil.PermissionFile))
+			}
+
+			npmProject := NewNpmProject(npmCli, env)
+			packageTask := npmProject.Package(
+				*mockContext.Context,
+				serviceConfig,
+				&ServiceBuildResult{
+					BuildOutputPath: serviceConfig.Path(),
+				},
+			)
+			logProgress(packageTask)
+
+			result, err := packageTask.Await()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCmd, installArgs.Cmd)
+			require.Equal(t, tt.wantArgs, installArgs.Args)
+			require.Equal(t, result.PackagePath, installArgs.Cwd)
+
+			// The dependencies installed for development aren't copied into the package
+			_, err = os.Stat(filepath.Join(result.PackagePath, "node_modules"))
+			require.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func Test_NpmProject_Restore_Workspace(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	var runArgs exec.RunArgs
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "npm install")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	env := environment.Ephemeral()
+	npmCli := npm.NewNpmCli(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguageTypeScript)
+	require.NoError(t, os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile("package-lock.json", nil, osutil.PermissionFile))
+
+	npmProject := NewNpmProject(npmCli, env)
+	restoreTask := npmProject.Restore(*mockContext.Context, serviceConfig)
+	logProgress(restoreTask)
+
+	_, err := restoreTask.Await()
+	require.NoError(t, err)
+
+	// The dependencies are installed at the root of the workspace
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.Equal(t, wd, runArgs.Cwd)
+}
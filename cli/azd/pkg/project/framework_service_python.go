@@ -11,12 +11,27 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 )
 
+// The platform of the packages bundled for the Linux hosts of App Service and Azure Functions
+const cDefaultPythonPlatform = "manylinux2014_x86_64"
+
+// PythonOptions configures how Python services are packaged.
+type PythonOptions struct {
+	// Installs the dependencies of the service into the package, in an isolated virtual environment, instead of
+	// installing them on the host after the deployment like the remote build of App Service does.
+	BundleDependencies bool `yaml:"bundleDependencies,omitempty"`
+	// The platform of the bundled packages, manylinux2014_x86_64 by default.
+	Platform string `yaml:"platform,omitempty"`
+	// The Python version of the host, like 3.11. Read from the runtime stack of the App Service options when not set.
+	Version string `yaml:"version,omitempty"`
+}
+
 type pythonProject struct {
 	env *environment.Environment
 	cli *python.PythonCli
@@ -83,7 +98,11 @@ func (pp *pythonProject) Restore(
 			}
 
 			task.SetProgress(NewServiceProgress("Installing Python PIP dependencies"))
-			err = pp.cli.InstallRequirements(ctx, serviceConfig.Path(), vEnvName, "requirements.txt")
+			if pythonRequirementsFile(serviceConfig.Path()) == "" {
+				err = pp.cli.InstallProject(ctx, serviceConfig.Path(), vEnvName)
+			} else {
+				err = pp.cli.InstallRequirements(ctx, serviceConfig.Path(), vEnvName, cRequirementsFileName)
+			}
 			if err != nil {
 				task.SetError(
 					fmt.Errorf("requirements for project '%s' could not be installed: %w", serviceConfig.Path(), err),
@@ -125,7 +144,7 @@ func (pp *pythonProject) Package(
 ) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
-			packageDest, err := os.MkdirTemp("", "azd")
+			packageDest, err := artifacts.MkdirTemp("azd")
 			if err != nil {
 				task.SetError(fmt.Errorf("creating package directory for %s: %w", serviceConfig.Name, err))
 				return
@@ -149,12 +168,21 @@ func (pp *pythonProject) Package(
 					excludeConditions: []excludeDirEntryCondition{
 						excludeVirtualEnv,
 						excludePyCache,
+						excludePythonToolCaches,
 					},
 				}); err != nil {
 				task.SetError(fmt.Errorf("packaging for %s: %w", serviceConfig.Name, err))
 				return
 			}
 
+			if serviceConfig.Python.BundleDependencies {
+				task.SetProgress(NewServiceProgress("Installing Python dependencies into the package"))
+				if err := pp.bundleDependencies(ctx, serviceConfig, packageSource, packageDest); err != nil {
+					task.SetError(fmt.Errorf("bundling dependencies of %s: %w", serviceConfig.Name, err))
+					return
+				}
+			}
+
 			if err := validatePackageOutput(packageDest); err != nil {
 				task.SetError(err)
 				return
@@ -168,6 +196,74 @@ func (pp *pythonProject) Package(
 	)
 }
 
+// bundleDependencies installs the dependencies of the service into the package, for the platform and the Python
+// version of the host. The packages are installed with the pip of a new virtual environment, so the packages installed
+// for development, like test frameworks, aren't bundled. Azure Functions loads the packages from
+// .python_packages/lib/site-packages, other hosts from the root of the package.
+func (pp *pythonProject) bundleDependencies(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageSource string,
+	packageDest string,
+) error {
+	packageSource, err := filepath.Abs(packageSource)
+	if err != nil {
+		return err
+	}
+
+	options := python.TargetInstallOptions{
+		RequirementsFile: pythonRequirementsFile(packageSource),
+		Target:           packageDest,
+		Platform:         serviceConfig.Python.Platform,
+		PythonVersion:    serviceConfig.Python.Version,
+	}
+
+	if serviceConfig.Host == AzureFunctionTarget {
+		options.Target = filepath.Join(packageDest, ".python_packages", "lib", "site-packages")
+	}
+
+	if options.Platform == "" {
+		options.Platform = cDefaultPythonPlatform
+	}
+
+	if options.PythonVersion == "" {
+		stack := strings.ToUpper(serviceConfig.AppService.RuntimeStack)
+		if version, ok := strings.CutPrefix(stack, "PYTHON|"); ok {
+			options.PythonVersion = version
+		}
+	}
+
+	venvDir, err := os.MkdirTemp("", "azd-python")
+	if err != nil {
+		return fmt.Errorf("creating virtual environment directory: %w", err)
+	}
+	defer os.RemoveAll(venvDir)
+
+	return pp.cli.InstallToTarget(ctx, packageSource, filepath.Join(venvDir, "venv"), options)
+}
+
+const (
+	cRequirementsFileName = "requirements.txt"
+	cPyprojectFileName    = "pyproject.toml"
+)
+
+// pythonRequirementsFile returns the path of requirements.txt in the project directory, or an empty string for the
+// projects declaring their dependencies in pyproject.toml only, like Poetry projects. Development dependencies are
+// expected to be declared apart, in requirements-dev.txt or in the dev group of pyproject.toml, so they're excluded.
+func pythonRequirementsFile(projectPath string) string {
+	requirementsPath := filepath.Join(projectPath, cRequirementsFileName)
+	if _, err := os.Stat(requirementsPath); err == nil {
+		return requirementsPath
+	}
+
+	if _, err := os.Stat(filepath.Join(projectPath, cPyprojectFileName)); err == nil {
+		return ""
+	}
+
+	// Neither file exists, pip reports the missing requirements file
+	return requirementsPath
+}
+
 const cVenvConfigFileName = "pyvenv.cfg"
 
 func isPythonVirtualEnv(path string) bool {
@@ -186,6 +282,20 @@ func excludePyCache(path string, file os.FileInfo) bool {
 	return file.IsDir() && strings.ToLower(file.Name()) == "__pycache__"
 }
 
+// excludePythonToolCaches excludes the caches of the test and lint tools from the package.
+func excludePythonToolCaches(path string, file os.FileInfo) bool {
+	if !file.IsDir() {
+		return false
+	}
+
+	switch strings.ToLower(file.Name()) {
+	case ".pytest_cache", ".mypy_cache", ".ruff_cache", ".tox", ".nox":
+		return true
+	}
+
+	return false
+}
+
 func (pp *pythonProject) getVenvName(serviceConfig *ServiceConfig) string {
 	trimmedPath := strings.TrimSpace(serviceConfig.Path())
 	if len(trimmedPath) > 0 && trimmedPath[len(trimmedPath)-1] == os.PathSeparator {
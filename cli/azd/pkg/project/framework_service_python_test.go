@@ -133,6 +133,73 @@ func Test_PythonProject_Package(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_PythonProject_Package_BundleDependencies(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	var pipArgs exec.RunArgs
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "-m venv")
+		}).
+		Respond(exec.NewRunResult(0, "", ""))
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "-m pip install")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			pipArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	env := environment.Ephemeral()
+	pythonCli := python.NewPythonCli(mockContext.CommandRunner)
+	serviceConfig := createTestServiceConfig("./src/api", AzureFunctionTarget, ServiceLanguagePython)
+	serviceConfig.Python = PythonOptions{BundleDependencies: true}
+	serviceConfig.AppService = AppServiceOptions{RuntimeStack: "PYTHON|3.11"}
+	require.NoError(t, os.MkdirAll(filepath.Join(serviceConfig.Path(), ".pytest_cache"), osutil.PermissionDirectory))
+	requirementsPath := filepath.Join(serviceConfig.Path(), "requirements.txt")
+	require.NoError(t, os.WriteFile(requirementsPath, []byte("flask"), osutil.PermissionFile))
+
+	pythonProject := NewPythonProject(pythonCli, env)
+	packageTask := pythonProject.Package(
+		*mockContext.Context,
+		serviceConfig,
+		&ServiceBuildResult{
+			BuildOutputPath: serviceConfig.Path(),
+		},
+	)
+	logProgress(packageTask)
+
+	result, err := packageTask.Await()
+	require.NoError(t, err)
+
+	absRequirementsPath, err := filepath.Abs(requirementsPath)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"-m", "pip", "install", "--disable-pip-version-check",
+		"--target", filepath.Join(result.PackagePath, ".python_packages", "lib", "site-packages"),
+		"--platform", "manylinux2014_x86_64", "--only-binary=:all:", "--implementation", "cp",
+		"--python-version", "3.11",
+		"-r", absRequirementsPath,
+	}, pipArgs.Args)
+
+	_, err = os.Stat(filepath.Join(result.PackagePath, ".pytest_cache"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_pythonRequirementsFile(t *testing.T) {
+	projectPath := t.TempDir()
+	require.Equal(t, filepath.Join(projectPath, "requirements.txt"), pythonRequirementsFile(projectPath))
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "pyproject.toml"), nil, osutil.PermissionFile))
+	require.Equal(t, "", pythonRequirementsFile(projectPath))
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectPath, "requirements.txt"), nil, osutil.PermissionFile))
+	require.Equal(t, filepath.Join(projectPath, "requirements.txt"), pythonRequirementsFile(projectPath))
+}
+
 func pythonExe() string {
 	if runtime.GOOS == "windows" {
 		return "py" // https://peps.python.org/pep-0397
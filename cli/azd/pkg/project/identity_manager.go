@@ -0,0 +1,226 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The API versions used to read the managed identity of the resources hosting services, by resource type
+var managedIdentityApiVersions = map[string]string{
+	string(infra.AzureResourceTypeWebSite):      "2022-03-01",
+	string(infra.AzureResourceTypeContainerApp): "2022-10-01",
+}
+
+// ServiceUsesConfig declares a resource used by a service, and the roles assigned to the managed identity of the service
+// on the resource after provisioning.
+type ServiceUsesConfig struct {
+	// The name of a resource of the resource group of the environment, or the id of a resource. Supports environment
+	// variable substitution, like ${AZURE_KEY_VAULT_NAME}.
+	Resource ExpandableString `yaml:"resource"`
+	// The names of the roles, like Key Vault Secrets User or Storage Blob Data Contributor
+	Roles []string `yaml:"roles"`
+}
+
+// RoleAssignment is a role assigned to the managed identity of a service on a resource it uses.
+type RoleAssignment struct {
+	Service     string
+	ResourceId  string
+	Role        string
+	PrincipalId string
+}
+
+// IdentityManager applies the role assignments declared by the uses section of the services, so the managed identity
+// of each service can access the resources it uses.
+type IdentityManager struct {
+	env             *environment.Environment
+	azCli           azcli.AzCli
+	resourceManager ResourceManager
+}
+
+// NewIdentityManager creates a new instance of the IdentityManager
+func NewIdentityManager(
+	env *environment.Environment,
+	azCli azcli.AzCli,
+	resourceManager ResourceManager,
+) *IdentityManager {
+	return &IdentityManager{
+		env:             env,
+		azCli:           azCli,
+		resourceManager: resourceManager,
+	}
+}
+
+// ApplyRoleAssignments assigns the roles declared by the services of the project to their managed identity. Roles which
+// are already assigned are left as is, so applying them after each provisioning is safe.
+func (im *IdentityManager) ApplyRoleAssignments(
+	ctx context.Context,
+	projectConfig *ProjectConfig,
+) ([]RoleAssignment, error) {
+	services := []*ServiceConfig{}
+	for _, svc := range projectConfig.GetServicesStable() {
+		if len(svc.Uses) == 0 || !svc.EnabledIn(im.env.GetEnvName()) {
+			continue
+		}
+
+		if err := validateUses(svc); err != nil {
+			return nil, err
+		}
+
+		services = append(services, svc)
+	}
+
+	if len(services) == 0 {
+		return nil, nil
+	}
+
+	subscriptionId := im.env.GetSubscriptionId()
+	resourceGroupName, err := im.resourceManager.GetResourceGroupName(ctx, subscriptionId, projectConfig)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource group name: %w", err)
+	}
+
+	assignments := []RoleAssignment{}
+	for _, svc := range services {
+		principalId, err := im.servicePrincipalId(ctx, subscriptionId, resourceGroupName, svc)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, uses := range svc.Uses {
+			resourceId, err := im.resourceId(ctx, subscriptionId, resourceGroupName, svc, uses)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, role := range uses.Roles {
+				if err := im.azCli.EnsureRoleAssignment(ctx, subscriptionId, resourceId, role, principalId); err != nil {
+					return nil, fmt.Errorf("assigning role '%s' to service %s: %w", role, svc.Name, err)
+				}
+
+				assignments = append(assignments, RoleAssignment{
+					Service:     svc.Name,
+					ResourceId:  resourceId,
+					Role:        role,
+					PrincipalId: principalId,
+				})
+			}
+		}
+	}
+
+	return assignments, nil
+}
+
+func validateUses(svc *ServiceConfig) error {
+	for i, uses := range svc.Uses {
+		if uses.Resource == (ExpandableString{}) {
+			return fmt.Errorf("service %s: uses[%d].resource must be set", svc.Name, i)
+		}
+
+		if len(uses.Roles) == 0 {
+			return fmt.Errorf("service %s: uses[%d].roles must list at least one role", svc.Name, i)
+		}
+	}
+
+	return nil
+}
+
+// servicePrincipalId returns the principal id of the managed identity of the resource hosting the service.
+func (im *IdentityManager) servicePrincipalId(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	svc *ServiceConfig,
+) (string, error) {
+	resource, err := im.resourceManager.GetServiceResource(ctx, subscriptionId, resourceGroupName, svc, "provision")
+	if err != nil {
+		return "", err
+	}
+
+	apiVersion, has := managedIdentityApiVersions[resource.Type]
+	if !has {
+		return "", fmt.Errorf(
+			"service %s uses resources, but managed identities of '%s' resources aren't supported", svc.Name, resource.Type)
+	}
+
+	extended, err := im.azCli.GetResource(ctx, subscriptionId, resource.Id, apiVersion)
+	if err != nil {
+		return "", fmt.Errorf("getting the managed identity of service %s: %w", svc.Name, err)
+	}
+
+	if extended.PrincipalId == "" {
+		return "", fmt.Errorf(
+			"service %s uses resources, but '%s' has no managed identity. Enable its system assigned identity",
+			svc.Name,
+			resource.Name,
+		)
+	}
+
+	return extended.PrincipalId, nil
+}
+
+// resourceId returns the id of a resource used by a service, finding it by name in the resource group of the
+// environment when it isn't an id.
+func (im *IdentityManager) resourceId(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	svc *ServiceConfig,
+	uses ServiceUsesConfig,
+) (string, error) {
+	return findResourceId(ctx, im.env, im.azCli, subscriptionId, resourceGroupName, svc, uses.Resource)
+}
+
+// findResourceId resolves the reference of a resource used by a service to the id of the resource. The reference is
+// the id of the resource, or its name in the resource group of the environment.
+func findResourceId(
+	ctx context.Context,
+	env *environment.Environment,
+	azCli azcli.AzCli,
+	subscriptionId string,
+	resourceGroupName string,
+	svc *ServiceConfig,
+	reference ExpandableString,
+) (string, error) {
+	resource, err := reference.Resolve(env)
+	if err != nil {
+		return "", fmt.Errorf("resolving the resource used by service %s: %w", svc.Name, err)
+	}
+
+	if strings.HasPrefix(strings.ToLower(resource), "/subscriptions/") {
+		return resource, nil
+	}
+
+	filter := fmt.Sprintf("name eq '%s'", resource)
+	resources, err := azCli.ListResourceGroupResources(
+		ctx,
+		subscriptionId,
+		resourceGroupName,
+		&azcli.ListResourceGroupResourcesOptions{
+			Filter: &filter,
+		},
+	)
+	if err != nil {
+		return "", fmt.Errorf("finding resource '%s' used by service %s: %w", resource, svc.Name, err)
+	}
+
+	switch len(resources) {
+	case 0:
+		return "", fmt.Errorf(
+			"resource '%s' used by service %s wasn't found in resource group '%s'", resource, svc.Name, resourceGroupName)
+	case 1:
+		return resources[0].Id, nil
+	default:
+		return "", fmt.Errorf(
+			"resource group '%s' has %d resources named '%s', use the id of the resource used by service %s",
+			resourceGroupName,
+			len(resources),
+			resource,
+			svc.Name,
+		)
+	}
+}
@@ -0,0 +1,161 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockgraphsdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityManagerApplyRoleAssignments(t *testing.T) {
+	const testProj = `
+name: test-proj
+resourceGroup: rg-test
+services:
+  api:
+    project: src/api
+    language: js
+    host: containerapp
+    uses:
+      - resource: ${AZURE_KEY_VAULT_NAME}
+        roles:
+          - Key Vault Secrets User
+  web:
+    project: src/web
+    language: js
+    host: containerapp
+`
+	const resourceGroupId = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test"
+	const containerAppId = resourceGroupId + "/providers/Microsoft.App/containerApps/ca-api"
+	const keyVaultId = resourceGroupId + "/providers/Microsoft.KeyVault/vaults/kv-test"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockarmresources.AddAzResourceListMock(
+		mockContext.HttpClient,
+		convert.RefOf("rg-test"),
+		[]*armresources.GenericResourceExpanded{
+			{
+				ID:       convert.RefOf(containerAppId),
+				Name:     convert.RefOf("ca-api"),
+				Type:     convert.RefOf(string(infra.AzureResourceTypeContainerApp)),
+				Location: convert.RefOf("eastus2"),
+				Tags: map[string]*string{
+					defaultServiceTag: convert.RefOf("api"),
+				},
+			},
+			{
+				ID:       convert.RefOf(keyVaultId),
+				Name:     convert.RefOf("kv-test"),
+				Type:     convert.RefOf(string(infra.AzureResourceTypeKeyVault)),
+				Location: convert.RefOf("eastus2"),
+			},
+		},
+	)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && request.URL.Path == containerAppId
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armresources.GenericResource{
+			ID:       convert.RefOf(containerAppId),
+			Name:     convert.RefOf("ca-api"),
+			Type:     convert.RefOf(string(infra.AzureResourceTypeContainerApp)),
+			Location: convert.RefOf("eastus2"),
+			Identity: &armresources.Identity{
+				PrincipalID: convert.RefOf("PRINCIPAL_ID"),
+			},
+		})
+	})
+
+	mockgraphsdk.RegisterRoleDefinitionListMock(mockContext, http.StatusOK, []*armauthorization.RoleDefinition{
+		{
+			ID:   convert.RefOf("ROLE_DEFINITION_ID"),
+			Name: convert.RefOf("ROLE_DEFINITION_NAME"),
+		},
+	})
+
+	assignmentPaths := []string{}
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut &&
+			strings.Contains(request.URL.Path, "/providers/Microsoft.Authorization/roleAssignments/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		assignmentPaths = append(assignmentPaths, request.URL.Path)
+
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		var parameters armauthorization.RoleAssignmentCreateParameters
+		require.NoError(t, json.Unmarshal(body, &parameters))
+		require.Equal(t, "PRINCIPAL_ID", *parameters.Properties.PrincipalID)
+		require.Equal(t, "ROLE_DEFINITION_ID", *parameters.Properties.RoleDefinitionID)
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusCreated, armauthorization.RoleAssignment{
+			ID: convert.RefOf("ASSIGNMENT_ID"),
+		})
+	})
+
+	env := environment.EphemeralWithValues("envA", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"AZURE_KEY_VAULT_NAME":               "kv-test",
+	})
+
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+	identityManager := NewIdentityManager(env, azCli, NewResourceManager(env, azCli))
+
+	assignments, err := identityManager.ApplyRoleAssignments(*mockContext.Context, projectConfig)
+	require.NoError(t, err)
+	require.Equal(t, []RoleAssignment{
+		{
+			Service:     "api",
+			ResourceId:  keyVaultId,
+			Role:        "Key Vault Secrets User",
+			PrincipalId: "PRINCIPAL_ID",
+		},
+	}, assignments)
+
+	require.Len(t, assignmentPaths, 1)
+	require.True(
+		t, strings.HasPrefix(assignmentPaths[0], keyVaultId+"/providers/Microsoft.Authorization/roleAssignments/"))
+
+	// Applying the role assignments again uses the same assignment
+	_, err = identityManager.ApplyRoleAssignments(*mockContext.Context, projectConfig)
+	require.NoError(t, err)
+	require.Len(t, assignmentPaths, 2)
+	require.Equal(t, assignmentPaths[0], assignmentPaths[1])
+
+	t.Run("MissingResource", func(t *testing.T) {
+		env.Values["AZURE_KEY_VAULT_NAME"] = "kv-missing"
+		t.Cleanup(func() {
+			env.Values["AZURE_KEY_VAULT_NAME"] = "kv-test"
+		})
+
+		_, err := identityManager.ApplyRoleAssignments(*mockContext.Context, projectConfig)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "resource 'kv-missing' used by service api wasn't found in resource group 'rg-test'")
+	})
+
+	t.Run("MissingRoles", func(t *testing.T) {
+		invalidConfig, err := Parse(*mockContext.Context, strings.Replace(
+			testProj, "        roles:\n          - Key Vault Secrets User\n", "", 1))
+		require.NoError(t, err)
+
+		_, err = identityManager.ApplyRoleAssignments(*mockContext.Context, invalidConfig)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "service api: uses[0].roles must list at least one role")
+	})
+}
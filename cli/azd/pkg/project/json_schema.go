@@ -0,0 +1,450 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/azure/azure-dev/schemas"
+	"gopkg.in/yaml.v3"
+)
+
+// jsonSchema is a JSON schema, supporting the keywords used by the schemas of azure.yaml
+// (<workspace root>/schemas/vN.M/azure.yaml.json). The annotations, like title and description, are ignored.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 jsonSchemaTypes        `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Required             []string               `json:"required"`
+	MinProperties        *int                   `json:"minProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	Enum                 []any                  `json:"enum"`
+	Const                json.RawMessage        `json:"const"`
+	Pattern              string                 `json:"pattern"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	Not                  *jsonSchema            `json:"not"`
+	If                   *jsonSchema            `json:"if"`
+	Then                 *jsonSchema            `json:"then"`
+	Else                 *jsonSchema            `json:"else"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+
+	// Set for the boolean schemas, true accepting any value and false none
+	boolean *bool
+	// The schema referenced by Ref
+	ref *jsonSchema
+	// The compiled Pattern
+	pattern *regexp.Regexp
+}
+
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		s.boolean = &boolean
+		return nil
+	}
+
+	type schema jsonSchema
+	return json.Unmarshal(data, (*schema)(s))
+}
+
+// jsonSchemaTypes are the types allowed by a schema, the type keyword being either a single type or a list.
+type jsonSchemaTypes []string
+
+func (t *jsonSchemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = jsonSchemaTypes{single}
+		return nil
+	}
+
+	var types []string
+	if err := json.Unmarshal(data, &types); err != nil {
+		return err
+	}
+
+	*t = types
+	return nil
+}
+
+// loadAzureYamlSchema loads the JSON schema of azure.yaml for a schema version.
+func loadAzureYamlSchema(version string) (*jsonSchema, error) {
+	content, err := schemas.AzureYaml(version)
+	if err != nil {
+		return nil, fmt.Errorf("reading the schema of version %s: %w", version, err)
+	}
+
+	var schema jsonSchema
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, fmt.Errorf("parsing the schema of version %s: %w", version, err)
+	}
+
+	if err := schema.resolve(&schema); err != nil {
+		return nil, fmt.Errorf("parsing the schema of version %s: %w", version, err)
+	}
+
+	return &schema, nil
+}
+
+// resolve resolves the references and compiles the patterns of the schema and its subschemas.
+func (s *jsonSchema) resolve(root *jsonSchema) error {
+	if s.Ref != "" {
+		name, has := strings.CutPrefix(s.Ref, "#/definitions/")
+		if !has || root.Definitions[name] == nil {
+			return fmt.Errorf("unsupported reference '%s'", s.Ref)
+		}
+
+		s.ref = root.Definitions[name]
+	}
+
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", s.Pattern, err)
+		}
+
+		s.pattern = pattern
+	}
+
+	for _, subschema := range s.subschemas() {
+		if err := subschema.resolve(root); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *jsonSchema) subschemas() []*jsonSchema {
+	subschemas := []*jsonSchema{}
+	for _, property := range s.Properties {
+		subschemas = append(subschemas, property)
+	}
+
+	for _, definition := range s.Definitions {
+		subschemas = append(subschemas, definition)
+	}
+
+	subschemas = append(subschemas, s.AllOf...)
+	subschemas = append(subschemas, s.AnyOf...)
+
+	for _, subschema := range []*jsonSchema{s.AdditionalProperties, s.Items, s.Not, s.If, s.Then, s.Else} {
+		if subschema != nil {
+			subschemas = append(subschemas, subschema)
+		}
+	}
+
+	return subschemas
+}
+
+func (s *jsonSchema) isFalse() bool {
+	return s.boolean != nil && !*s.boolean
+}
+
+// validateJsonSchema validates a node of a YAML document against a JSON schema. The errors are sorted by line.
+func validateJsonSchema(node *yaml.Node, schema *jsonSchema) []SchemaError {
+	errs := []SchemaError{}
+	seen := map[SchemaError]bool{}
+
+	// The same violation is reported by each subschema declaring the property, like the branches of allOf
+	for _, schemaErr := range validateJsonSchemaNode(node, schema, "") {
+		if !seen[schemaErr] {
+			seen[schemaErr] = true
+			errs = append(errs, schemaErr)
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Line < errs[j].Line
+	})
+
+	return errs
+}
+
+func validateJsonSchemaNode(node *yaml.Node, schema *jsonSchema, path string) []SchemaError {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+
+	// An empty value leaves the property unset, like the decoder does
+	if node.Tag == "!!null" {
+		return nil
+	}
+
+	if schema.boolean != nil {
+		if !*schema.boolean {
+			return []SchemaError{{Line: node.Line, Path: path, Message: "isn't allowed here"}}
+		}
+
+		return nil
+	}
+
+	errs := []SchemaError{}
+	if schema.ref != nil {
+		errs = append(errs, validateJsonSchemaNode(node, schema.ref, path)...)
+	}
+
+	if len(schema.Type) > 0 && !matchesJsonSchemaType(node, schema.Type) {
+		descriptions := make([]string, len(schema.Type))
+		for i, schemaType := range schema.Type {
+			descriptions[i] = jsonSchemaTypeDescriptions[schemaType]
+		}
+
+		return append(errs, SchemaError{
+			Line:    node.Line,
+			Path:    path,
+			Message: fmt.Sprintf("expected %s", strings.Join(descriptions, " or ")),
+		})
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		errs = append(errs, validateJsonSchemaMapping(node, schema, path)...)
+	case yaml.SequenceNode:
+		errs = append(errs, validateJsonSchemaSequence(node, schema, path)...)
+	case yaml.ScalarNode:
+		errs = append(errs, validateJsonSchemaScalar(node, schema, path)...)
+	}
+
+	for _, subschema := range schema.AllOf {
+		errs = append(errs, validateJsonSchemaNode(node, subschema, path)...)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		// When no branch matches, the violations of the closest branch are the most helpful
+		var closest []SchemaError
+		for i, subschema := range schema.AnyOf {
+			branchErrs := validateJsonSchemaNode(node, subschema, path)
+			if i == 0 || len(branchErrs) < len(closest) {
+				closest = branchErrs
+			}
+		}
+
+		errs = append(errs, closest...)
+	}
+
+	if schema.Not != nil && len(validateJsonSchemaNode(node, schema.Not, path)) == 0 {
+		errs = append(errs, SchemaError{Line: node.Line, Path: path, Message: "isn't allowed here"})
+	}
+
+	if schema.If != nil {
+		if len(validateJsonSchemaNode(node, schema.If, path)) == 0 {
+			if schema.Then != nil {
+				errs = append(errs, validateJsonSchemaNode(node, schema.Then, path)...)
+			}
+		} else if schema.Else != nil {
+			errs = append(errs, validateJsonSchemaNode(node, schema.Else, path)...)
+		}
+	}
+
+	return errs
+}
+
+func validateJsonSchemaMapping(node *yaml.Node, schema *jsonSchema, path string) []SchemaError {
+	errs := []SchemaError{}
+	for _, required := range schema.Required {
+		if key, _ := mappingValue(node, required); key == nil {
+			errs = append(errs, SchemaError{
+				Line:    node.Line,
+				Path:    path,
+				Message: fmt.Sprintf("missing required property %s", required),
+			})
+		}
+	}
+
+	if schema.MinProperties != nil && len(node.Content)/2 < *schema.MinProperties {
+		errs = append(errs, SchemaError{
+			Line:    node.Line,
+			Path:    path,
+			Message: fmt.Sprintf("expected at least %d properties", *schema.MinProperties),
+		})
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		propertyPath := joinSchemaPath(path, key.Value)
+
+		if property, has := schema.Properties[key.Value]; has {
+			if property.isFalse() {
+				errs = append(errs, SchemaError{Line: key.Line, Path: propertyPath, Message: "isn't allowed here"})
+				continue
+			}
+
+			errs = append(errs, validateJsonSchemaNode(node.Content[i+1], property, propertyPath)...)
+		} else if schema.AdditionalProperties != nil {
+			if schema.AdditionalProperties.isFalse() {
+				errs = append(errs, SchemaError{Line: key.Line, Path: propertyPath, Message: "unknown property"})
+				continue
+			}
+
+			errs = append(errs, validateJsonSchemaNode(node.Content[i+1], schema.AdditionalProperties, propertyPath)...)
+		}
+	}
+
+	return errs
+}
+
+func validateJsonSchemaSequence(node *yaml.Node, schema *jsonSchema, path string) []SchemaError {
+	errs := []SchemaError{}
+	if schema.MinItems != nil && len(node.Content) < *schema.MinItems {
+		errs = append(errs, SchemaError{
+			Line:    node.Line,
+			Path:    path,
+			Message: fmt.Sprintf("expected at least %d items", *schema.MinItems),
+		})
+	}
+
+	if schema.MaxItems != nil && len(node.Content) > *schema.MaxItems {
+		errs = append(errs, SchemaError{
+			Line:    node.Line,
+			Path:    path,
+			Message: fmt.Sprintf("expected at most %d items", *schema.MaxItems),
+		})
+	}
+
+	if schema.Items != nil {
+		for i, item := range node.Content {
+			errs = append(errs, validateJsonSchemaNode(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	return errs
+}
+
+func validateJsonSchemaScalar(node *yaml.Node, schema *jsonSchema, path string) []SchemaError {
+	errs := []SchemaError{}
+	invalid := func(format string, args ...any) {
+		errs = append(errs, SchemaError{Line: node.Line, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(schema.Enum) > 0 {
+		allowed := []string{}
+		matches := false
+		for _, value := range schema.Enum {
+			matches = matches || matchesJsonValue(node, value)
+			if value != "" {
+				allowed = append(allowed, fmt.Sprint(value))
+			}
+		}
+
+		if !matches {
+			invalid("'%s' isn't one of the allowed values: %s", node.Value, strings.Join(allowed, ", "))
+		}
+	}
+
+	if schema.Const != nil {
+		var value any
+		if err := json.Unmarshal(schema.Const, &value); err == nil && !matchesJsonValue(node, value) {
+			invalid("expected '%v'", value)
+		}
+	}
+
+	if schema.pattern != nil && !schema.pattern.MatchString(node.Value) {
+		invalid("'%s' doesn't match the pattern %s", node.Value, schema.Pattern)
+	}
+
+	length := utf8.RuneCountInString(node.Value)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		invalid("expected at least %d characters", *schema.MinLength)
+	}
+
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		invalid("expected at most %d characters", *schema.MaxLength)
+	}
+
+	if number, err := strconv.ParseFloat(node.Value, 64); err == nil {
+		if schema.Minimum != nil && number < *schema.Minimum {
+			invalid("expected a value of at least %v", *schema.Minimum)
+		}
+
+		if schema.Maximum != nil && number > *schema.Maximum {
+			invalid("expected a value of at most %v", *schema.Maximum)
+		}
+	}
+
+	return errs
+}
+
+var jsonSchemaTypeDescriptions = map[string]string{
+	"object":  "an object",
+	"array":   "a list",
+	"string":  "a string",
+	"integer": "an integer",
+	"number":  "a number",
+	"boolean": "true or false",
+	"null":    "an empty value",
+}
+
+// matchesJsonSchemaType returns whether a node is one of the JSON schema types. Any single value is accepted as a
+// string, since the decoder accepts unquoted values, like 1.1, for string properties.
+func matchesJsonSchemaType(node *yaml.Node, types []string) bool {
+	for _, schemaType := range types {
+		switch schemaType {
+		case "object":
+			if node.Kind == yaml.MappingNode {
+				return true
+			}
+		case "array":
+			if node.Kind == yaml.SequenceNode {
+				return true
+			}
+		case "string":
+			if node.Kind == yaml.ScalarNode {
+				return true
+			}
+		case "integer":
+			if node.Kind == yaml.ScalarNode && node.Tag == "!!int" {
+				return true
+			}
+		case "number":
+			if node.Kind == yaml.ScalarNode && (node.Tag == "!!int" || node.Tag == "!!float") {
+				return true
+			}
+		case "boolean":
+			if node.Kind == yaml.ScalarNode && node.Tag == "!!bool" {
+				return true
+			}
+		case "null":
+			if node.Tag == "!!null" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchesJsonValue returns whether a scalar node is equal to a value decoded from JSON.
+func matchesJsonValue(node *yaml.Node, value any) bool {
+	if node.Kind != yaml.ScalarNode {
+		return false
+	}
+
+	switch value := value.(type) {
+	case string:
+		return node.Value == value
+	case bool:
+		return node.Tag == "!!bool" && strings.EqualFold(node.Value, strconv.FormatBool(value))
+	case float64:
+		number, err := strconv.ParseFloat(node.Value, 64)
+		return err == nil && (node.Tag == "!!int" || node.Tag == "!!float") && number == value
+	case nil:
+		return node.Tag == "!!null"
+	default:
+		return false
+	}
+}
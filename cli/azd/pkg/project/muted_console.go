@@ -62,6 +62,11 @@ func (sc *MutedConsole) Select(ctx context.Context, options input.ConsoleOptions
 	return sc.ParentConsole.Select(ctx, options)
 }
 
+// Use parent console for input
+func (sc *MutedConsole) MultiSelect(ctx context.Context, options input.ConsoleOptions) ([]string, error) {
+	return sc.ParentConsole.MultiSelect(ctx, options)
+}
+
 // Use parent console for input
 func (sc *MutedConsole) Confirm(ctx context.Context, options input.ConsoleOptions) (bool, error) {
 	return sc.ParentConsole.Confirm(ctx, options)
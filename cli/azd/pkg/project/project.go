@@ -21,12 +21,13 @@ import (
 
 const (
 	//nolint:lll
-	projectSchemaAnnotation = "# yaml-language-server: $schema=https://raw.githubusercontent.com/Azure/azure-dev/main/schemas/v1.0/azure.yaml.json"
+	SchemaAnnotation = "# yaml-language-server: $schema=https://raw.githubusercontent.com/Azure/azure-dev/main/schemas/v1.0/azure.yaml.json"
 )
 
 func New(ctx context.Context, projectFilePath string, projectName string) (*ProjectConfig, error) {
 	newProject := &ProjectConfig{
-		Name: projectName,
+		SchemaVersion: LatestSchemaVersion,
+		Name:          projectName,
 	}
 
 	err := Save(ctx, newProject, projectFilePath)
@@ -41,6 +42,25 @@ func New(ctx context.Context, projectFilePath string, projectName string) (*Proj
 func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 	var projectConfig ProjectConfig
 
+	version, schemaErrors, err := ValidateSchema([]byte(yamlContent))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to parse azure.yaml file. Please check the format of the file, "+
+				"and also verify you have the latest version of the CLI: %w",
+			err,
+		)
+	}
+
+	if len(schemaErrors) > 0 {
+		if version != SchemaVersion1_0 {
+			return nil, &SchemaValidationError{Version: version, Errors: schemaErrors}
+		}
+
+		for _, schemaErr := range schemaErrors {
+			log.Printf("azure.yaml doesn't match the latest schema, run 'azd project upgrade': %s", schemaErr)
+		}
+	}
+
 	if err := yaml.Unmarshal([]byte(yamlContent), &projectConfig); err != nil {
 		return nil, fmt.Errorf(
 			"unable to parse azure.yaml file. Please check the format of the file, "+
@@ -50,6 +70,7 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 	}
 
 	projectConfig.EventDispatcher = ext.NewEventDispatcher[ProjectLifecycleEventArgs]()
+	projectConfig.Infra.ProjectName = projectConfig.Name
 
 	if projectConfig.RequiredVersions != nil && projectConfig.RequiredVersions.Azd != nil {
 		supportedRange, err := semver.ParseRange(*projectConfig.RequiredVersions.Azd)
@@ -81,6 +102,14 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 		if err != nil {
 			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
 		}
+
+		if err := validateWebAppConfig(svc); err != nil {
+			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
+		}
+	}
+
+	if err := projectConfig.validateProfiles(); err != nil {
+		return nil, fmt.Errorf("parsing profiles: %w", err)
 	}
 
 	return &projectConfig, nil
@@ -146,7 +175,7 @@ func Save(ctx context.Context, projectConfig *ProjectConfig, projectFilePath str
 		return fmt.Errorf("marshalling project yaml: %w", err)
 	}
 
-	projectFileContents := bytes.NewBufferString(projectSchemaAnnotation + "\n\n")
+	projectFileContents := bytes.NewBufferString(SchemaAnnotation + "\n\n")
 	_, err = projectFileContents.Write(projectBytes)
 	if err != nil {
 		return fmt.Errorf("preparing new project file contents: %w", err)
@@ -2,25 +2,41 @@ package project
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"sort"
+	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/secrets"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 // ProjectConfig is the top level object serialized into an azure.yaml file.
 // When changing project structure, make sure to update the JSON schema file for azure.yaml (<workspace
 // root>/schemas/vN.M/azure.yaml.json).
 type ProjectConfig struct {
-	RequiredVersions  *RequiredVersions          `yaml:"requiredVersions,omitempty"`
-	Name              string                     `yaml:"name"`
-	ResourceGroupName ExpandableString           `yaml:"resourceGroup,omitempty"`
-	Path              string                     `yaml:",omitempty"`
-	Metadata          *ProjectMetadata           `yaml:"metadata,omitempty"`
-	Services          map[string]*ServiceConfig  `yaml:",omitempty"`
-	Infra             provisioning.Options       `yaml:"infra"`
-	Pipeline          PipelineOptions            `yaml:"pipeline"`
-	Hooks             map[string]*ext.HookConfig `yaml:"hooks,omitempty"`
+	// The version of the schema of the file, see LatestSchemaVersion. Files without a version use version 1.0.
+	SchemaVersion     string                        `yaml:"schemaVersion,omitempty"`
+	RequiredVersions  *RequiredVersions             `yaml:"requiredVersions,omitempty"`
+	Name              string                        `yaml:"name"`
+	ResourceGroupName ExpandableString              `yaml:"resourceGroup,omitempty"`
+	Path              string                        `yaml:",omitempty"`
+	Metadata          *ProjectMetadata              `yaml:"metadata,omitempty"`
+	Services          map[string]*ServiceConfig     `yaml:",omitempty"`
+	Infra             provisioning.Options          `yaml:"infra"`
+	Pipeline          PipelineOptions               `yaml:"pipeline"`
+	AppConfig         *AppConfigOptions             `yaml:"appConfig,omitempty"`
+	ImageScan         *ImageScanOptions             `yaml:"imageScan,omitempty"`
+	Sbom              *SbomOptions                  `yaml:"sbom,omitempty"`
+	Hooks             map[string]*ext.HookConfig    `yaml:"hooks,omitempty"`
+	Secrets           map[string]*secrets.Reference `yaml:"secrets,omitempty"`
+	// Profiles are named groups of services, deployed or run locally together with the --profile flag, by profile name
+	Profiles map[string][]string `yaml:"profiles,omitempty"`
 
 	*ext.EventDispatcher[ProjectLifecycleEventArgs] `yaml:",omitempty"`
 }
@@ -35,6 +51,60 @@ type RequiredVersions struct {
 // options supported in azure.yaml
 type PipelineOptions struct {
 	Provider string `yaml:"provider"`
+	// Stages are the azd environments the generated GitHub workflow deploys to in order, each by a job running in the
+	// GitHub environment with the same name.
+	Stages []string `yaml:"stages,omitempty"`
+	// Environments are the protection rules of the GitHub environments of the workflows, by environment name.
+	Environments map[string]*PipelineEnvironmentOptions `yaml:"environments,omitempty"`
+	// Container runs the jobs of the generated GitHub workflow in a container image with azd and its tools installed,
+	// instead of installing azd on the runner.
+	Container *PipelineContainerOptions `yaml:"container,omitempty"`
+}
+
+// PipelineContainerOptions configures the container image the generated workflow runs azd in.
+type PipelineContainerOptions struct {
+	// The image, pinned with a version tag or a digest. Defaults to the official azd image of the version of azd
+	// configuring the pipeline.
+	Image string `yaml:"image,omitempty"`
+}
+
+// PipelineEnvironmentOptions are the protection rules of a GitHub environment deployed to by the workflows.
+type PipelineEnvironmentOptions struct {
+	// The users, or the teams as org/team, required to approve the deployments to the environment
+	Reviewers []string `yaml:"reviewers,omitempty"`
+	// The minutes to wait before the deployments to the environment start
+	WaitTimer int `yaml:"waitTimer,omitempty"`
+}
+
+// AppConfigOptions configures the sync of Azure App Configuration settings and feature flags, which are defined per
+// environment in files like appconfig/dev.yaml.
+type AppConfigOptions struct {
+	// The endpoint of the store. Defaults to the AZURE_APP_CONFIGURATION_ENDPOINT environment value
+	Endpoint ExpandableString `yaml:"endpoint"`
+	// The directory of the environment files, relative to the project. Defaults to appconfig
+	Path string `yaml:"path"`
+	// Deletes the settings and feature flags of the store which aren't in the environment file
+	DeleteOrphans bool `yaml:"deleteOrphans"`
+}
+
+// ImageScanOptions configures the vulnerability scan of the container images of the project, which runs with Trivy
+// after the images are built and before they are pushed, and fails the deploy of images with vulnerabilities.
+type ImageScanOptions struct {
+	// The lowest severity failing the deploy: UNKNOWN, LOW, MEDIUM, HIGH or CRITICAL. Defaults to HIGH
+	Severity string `yaml:"severity"`
+	// Ignores the vulnerabilities which have no fix yet
+	IgnoreUnfixed bool `yaml:"ignoreUnfixed"`
+	// The ids of the vulnerabilities accepted by the project, like CVE-2023-1234
+	Ignore []string `yaml:"ignore"`
+}
+
+// SbomOptions configures the software bills of materials generated with Trivy when the services are packaged. The
+// SBOMs are written to the .azure/<environment>/sbom directory of the project.
+type SbomOptions struct {
+	// The format of the SBOMs: cyclonedx or spdx. Defaults to cyclonedx
+	Format string `yaml:"format"`
+	// Attaches the SBOMs of container images to the images pushed to the registry, with ORAS
+	Upload bool `yaml:"upload"`
 }
 
 // Project lifecycle event arguments
@@ -64,6 +134,69 @@ func (p *ProjectConfig) HasService(name string) bool {
 	return false
 }
 
+// ResolveEnvReferences resolves the resource group, the resource names and the docker build args of the project against
+// env when the environment is loaded, so a malformed value fails before the command runs. The build args referencing
+// values which aren't set are only logged, the values may be outputs of a provisioning which hasn't run yet. The values
+// are resolved again when they're used, after the provisioning updated the environment.
+func (p *ProjectConfig) ResolveEnvReferences(env *environment.Environment) error {
+	if _, err := p.ResourceGroupName.Envsubst(env.Getenv); err != nil {
+		return fmt.Errorf("resolving resourceGroup: %w", err)
+	}
+
+	for _, svc := range p.GetServicesStable() {
+		if _, err := svc.ResourceName.Envsubst(env.Getenv); err != nil {
+			return fmt.Errorf("resolving resourceName of service %s: %w", svc.Name, err)
+		}
+
+		for _, buildArg := range svc.Docker.BuildArgs {
+			_, err := buildArg.Resolve(env)
+
+			var unresolvedErr *environment.UnresolvedReferencesError
+			if errors.As(err, &unresolvedErr) {
+				log.Printf("docker.buildArgs of service %s: %v", svc.Name, err)
+			} else if err != nil {
+				return fmt.Errorf("resolving docker.buildArgs of service %s: %w", svc.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProfileServices returns the names of the services of the profile, failing when the project has no such profile.
+func (p *ProjectConfig) ProfileServices(profile string) ([]string, error) {
+	services, has := p.Profiles[profile]
+	if !has {
+		profiles := maps.Keys(p.Profiles)
+		slices.Sort(profiles)
+		if len(profiles) == 0 {
+			return nil, fmt.Errorf("profile '%s' doesn't exist, no profiles are defined in azure.yaml", profile)
+		}
+
+		return nil, fmt.Errorf(
+			"profile '%s' doesn't exist, the profiles of the project are: %s", profile, strings.Join(profiles, ", "))
+	}
+
+	return services, nil
+}
+
+// validateProfiles checks the profiles of the project reference services of the project.
+func (p *ProjectConfig) validateProfiles() error {
+	for profile, services := range p.Profiles {
+		if len(services) == 0 {
+			return fmt.Errorf("profile '%s' has no services", profile)
+		}
+
+		for _, service := range services {
+			if !p.HasService(service) {
+				return fmt.Errorf("profile '%s' references service '%s', which doesn't exist", profile, service)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Retrieves the list of services in the project, in a stable ordering that is deterministic.
 func (p *ProjectConfig) GetServicesStable() []*ServiceConfig {
 	// Sort services by friendly name an then collect them into a list. This provides a stable ordering of services.
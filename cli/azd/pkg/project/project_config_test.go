@@ -47,6 +47,19 @@ func TestProjectConfigParse_Invalid(t *testing.T) {
 					azd: notarange
 			`),
 		},
+		{
+			name: "ProfileUnknownService",
+			projectConfig: heredoc.Doc(`
+				name: proj-profile-unknown-service
+				services:
+					web:
+						language: js
+						host: appservice
+				profiles:
+					backend:
+						- api
+			`),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -120,6 +133,40 @@ services:
 	require.False(t, projectConfig.HasService("foobar"))
 }
 
+func TestProjectConfigProfileServices(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  web:
+    project: src/web
+    language: js
+    host: appservice
+  api:
+    project: src/api
+    language: js
+    host: appservice
+  worker:
+    project: src/worker
+    language: js
+    host: appservice
+profiles:
+  backend:
+    - api
+    - worker
+`
+
+	mockContext := mocks.NewMockContext(context.Background())
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	services, err := projectConfig.ProfileServices("backend")
+	require.NoError(t, err)
+	require.Equal(t, []string{"api", "worker"}, services)
+
+	_, err = projectConfig.ProfileServices("frontend")
+	require.ErrorContains(t, err, "profile 'frontend' doesn't exist, the profiles of the project are: backend")
+}
+
 func TestProjectWithCustomDockerOptions(t *testing.T) {
 	const testProj = `
 name: test-proj
@@ -148,6 +195,33 @@ services:
 	require.Equal(t, "../", service.Docker.Context)
 }
 
+func TestProjectConfigResolveEnvReferences(t *testing.T) {
+	const testProj = `
+name: test-proj
+resourceGroup: rg-${AZURE_ENV_NAME}
+services:
+  web:
+    project: src/web
+    language: js
+    host: containerapp
+    resourceName: ${SERVICE_WEB_NAME}
+    docker:
+      buildArgs:
+        - REGION=${env:DEPLOY_REGION}
+`
+
+	mockContext := mocks.NewMockContext(context.Background())
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	// The values set by the provisioning, like the resource names, aren't required when the environment is loaded
+	env := environment.EphemeralWithValues("dev", nil)
+	require.NoError(t, projectConfig.ResolveEnvReferences(env))
+
+	projectConfig.Services["web"].Docker.BuildArgs = []ExpandableString{NewExpandableString("REGION=${DEPLOY_REGION")}
+	require.Error(t, projectConfig.ResolveEnvReferences(env))
+}
+
 func TestProjectConfigAddHandler(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	project := getProjectConfig()
@@ -0,0 +1,288 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+)
+
+// The versions of the azure.yaml schema.
+// When adding a version, add the migration from the previous version to schemaMigrations and add the JSON schema of
+// azure.yaml for the version (<workspace root>/schemas/vN.M/azure.yaml.json), which the files are validated against.
+const (
+	// SchemaVersion1_0 is the version of the projects without a schemaVersion property.
+	SchemaVersion1_0 = "1.0"
+	// SchemaVersion1_1 removes the deprecated module property of services and the py language alias, and rejects
+	// unknown properties.
+	SchemaVersion1_1 = "1.1"
+
+	// LatestSchemaVersion is the version of new projects and the target of `azd project upgrade`.
+	LatestSchemaVersion = SchemaVersion1_1
+)
+
+var supportedSchemaVersions = []string{SchemaVersion1_0, SchemaVersion1_1}
+
+// schemaMigration migrates an azure.yaml document from the previous schema version to version. It returns a description
+// of each change.
+type schemaMigration struct {
+	version string
+	migrate func(root *yaml.Node) []string
+}
+
+var schemaMigrations = []schemaMigration{
+	{version: SchemaVersion1_1, migrate: migrateSchema1_1},
+}
+
+// SchemaError is a violation of the azure.yaml schema.
+type SchemaError struct {
+	// The line of the file, starting at 1
+	Line int
+	// The path of the property, like services.api.language
+	Path    string
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Message)
+}
+
+// SchemaValidationError is returned when azure.yaml doesn't match the schema version it declares.
+type SchemaValidationError struct {
+	Version string
+	Errors  []SchemaError
+}
+
+func (e *SchemaValidationError) Error() string {
+	lines := []string{fmt.Sprintf("azure.yaml doesn't match version %s of the schema:", e.Version)}
+	for _, schemaErr := range e.Errors {
+		lines = append(lines, "  "+schemaErr.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// UpgradeResult is the result of migrating an azure.yaml file to the latest schema version.
+type UpgradeResult struct {
+	FromVersion string
+	ToVersion   string
+	// The description of each change, empty when the file already uses the latest version
+	Changes []string
+	// The content of the migrated file
+	Content []byte
+}
+
+// UpgradeSchema migrates the content of an azure.yaml file to LatestSchemaVersion. Comments and the order of the
+// properties are preserved. A SchemaValidationError is returned when the migrated file still doesn't match the latest
+// schema, for instance because of a misspelled property, with the lines of the original file.
+func UpgradeSchema(content []byte) (*UpgradeResult, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("parsing azure.yaml: %w", err)
+	}
+
+	root := documentRoot(&document)
+	if root == nil {
+		return nil, fmt.Errorf("azure.yaml is empty or isn't a mapping")
+	}
+
+	version, err := schemaVersion(root)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &UpgradeResult{
+		FromVersion: version,
+		ToVersion:   LatestSchemaVersion,
+		Content:     content,
+	}
+
+	if version == LatestSchemaVersion {
+		return result, nil
+	}
+
+	for _, migration := range schemaMigrations {
+		if slices.Index(supportedSchemaVersions, migration.version) <= slices.Index(supportedSchemaVersions, version) {
+			continue
+		}
+
+		result.Changes = append(result.Changes, migration.migrate(root)...)
+	}
+
+	setSchemaVersion(root, LatestSchemaVersion)
+	result.Changes = append(result.Changes, fmt.Sprintf("set schemaVersion to %s", LatestSchemaVersion))
+
+	schema, err := loadAzureYamlSchema(LatestSchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if schemaErrors := validateJsonSchema(root, schema); len(schemaErrors) > 0 {
+		return nil, &SchemaValidationError{Version: LatestSchemaVersion, Errors: schemaErrors}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("marshalling azure.yaml: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("marshalling azure.yaml: %w", err)
+	}
+
+	result.Content = buf.Bytes()
+	return result, nil
+}
+
+// ValidateSchema validates an azure.yaml document against the JSON schema of the version it declares. It returns the
+// version and its violations. Projects using version 1.0 aren't rejected because of unknown properties or values, which
+// older versions of azd ignored, the violations are returned for them to be reported as warnings.
+func ValidateSchema(content []byte) (string, []SchemaError, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return "", nil, err
+	}
+
+	root := documentRoot(&document)
+	if root == nil {
+		return SchemaVersion1_0, nil, nil
+	}
+
+	version, err := schemaVersion(root)
+	if err != nil {
+		return "", nil, err
+	}
+
+	schema, err := loadAzureYamlSchema(version)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return version, validateJsonSchema(root, schema), nil
+}
+
+func documentRoot(document *yaml.Node) *yaml.Node {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	return document.Content[0]
+}
+
+// schemaVersion returns the schema version declared by the root of an azure.yaml document.
+func schemaVersion(root *yaml.Node) (string, error) {
+	_, value := mappingValue(root, "schemaVersion")
+	if value == nil {
+		return SchemaVersion1_0, nil
+	}
+
+	if value.Kind != yaml.ScalarNode || !slices.Contains(supportedSchemaVersions, value.Value) {
+		return "", fmt.Errorf(
+			"line %d: schemaVersion '%s' isn't supported by this version of azd, the supported versions are %s. "+
+				"Visit https://aka.ms/azure-dev/install to install the latest version.",
+			value.Line,
+			value.Value,
+			strings.Join(supportedSchemaVersions, ", "),
+		)
+	}
+
+	return value.Value, nil
+}
+
+func setSchemaVersion(root *yaml.Node, version string) {
+	if _, value := mappingValue(root, "schemaVersion"); value != nil {
+		value.Value = version
+		value.Tag = "!!str"
+		return
+	}
+
+	root.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: "schemaVersion"},
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: version},
+	}, root.Content...)
+}
+
+// mappingValue returns the key and value nodes of a property of a mapping node, or nil when the property isn't set.
+func mappingValue(mapping *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+func removeMappingValue(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// migrateSchema1_1 moves the deprecated module property of services to infra.module, and replaces the py language
+// alias with python.
+func migrateSchema1_1(root *yaml.Node) []string {
+	changes := []string{}
+
+	_, services := mappingValue(root, "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return changes
+	}
+
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		name := services.Content[i].Value
+		service := services.Content[i+1]
+		if service.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if _, module := mappingValue(service, "module"); module != nil {
+			removeMappingValue(service, "module")
+
+			_, infra := mappingValue(service, "infra")
+			if infra == nil {
+				infra = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				service.Content = append(service.Content,
+					&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "infra"},
+					infra,
+				)
+			}
+
+			if _, infraModule := mappingValue(infra, "module"); infraModule == nil && infra.Kind == yaml.MappingNode {
+				infra.Content = append(infra.Content,
+					&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "module"},
+					module,
+				)
+				changes = append(changes, fmt.Sprintf("services.%s: moved module to infra.module", name))
+			} else {
+				changes = append(changes, fmt.Sprintf("services.%s: removed module, infra.module is already set", name))
+			}
+		}
+
+		if _, language := mappingValue(service, "language"); language != nil && language.Value == "py" {
+			language.Value = string(ServiceLanguagePython)
+			changes = append(changes, fmt.Sprintf("services.%s: replaced language py with python", name))
+		}
+	}
+
+	return changes
+}
+
+func joinSchemaPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeSchema(t *testing.T) {
+	const testProj = `# yaml-language-server: $schema=https://aka.ms/azure-dev/schema
+
+name: test-proj
+services:
+  # The backend of the application
+  api:
+    project: src/api
+    language: py
+    host: appservice
+    module: app/api
+  web:
+    project: src/web
+    language: js
+    host: appservice
+`
+	result, err := UpgradeSchema([]byte(testProj))
+	require.NoError(t, err)
+	require.Equal(t, SchemaVersion1_0, result.FromVersion)
+	require.Equal(t, LatestSchemaVersion, result.ToVersion)
+	require.Equal(t, []string{
+		"services.api: moved module to infra.module",
+		"services.api: replaced language py with python",
+		"set schemaVersion to 1.1",
+	}, result.Changes)
+
+	require.Equal(t, `# yaml-language-server: $schema=https://aka.ms/azure-dev/schema
+
+schemaVersion: "1.1"
+name: test-proj
+services:
+  # The backend of the application
+  api:
+    project: src/api
+    language: python
+    host: appservice
+    infra:
+      module: app/api
+  web:
+    project: src/web
+    language: js
+    host: appservice
+`, string(result.Content))
+
+	projectConfig, err := Parse(context.Background(), string(result.Content))
+	require.NoError(t, err)
+	require.Equal(t, LatestSchemaVersion, projectConfig.SchemaVersion)
+	require.Equal(t, "app/api", projectConfig.Services["api"].Infra.Module)
+
+	t.Run("AlreadyLatest", func(t *testing.T) {
+		upgraded, err := UpgradeSchema(result.Content)
+		require.NoError(t, err)
+		require.Empty(t, upgraded.Changes)
+		require.Equal(t, result.Content, upgraded.Content)
+	})
+
+	t.Run("UnknownProperties", func(t *testing.T) {
+		_, err := UpgradeSchema([]byte("name: test-proj\nservices:\n  api:\n    projcet: src/api\n    language: js\n"))
+		require.Error(t, err)
+
+		var validationErr *SchemaValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Equal(t, []SchemaError{
+			{Line: 4, Path: "services.api", Message: "missing required property project"},
+			{Line: 4, Path: "services.api.projcet", Message: "unknown property"},
+		}, validationErr.Errors)
+	})
+}
+
+func TestParseSchemaVersion(t *testing.T) {
+	t.Run("UnknownPropertiesOfLegacyVersion", func(t *testing.T) {
+		projectConfig, err := Parse(context.Background(), "name: test-proj\nunknown: value\n")
+		require.NoError(t, err)
+		require.Equal(t, "test-proj", projectConfig.Name)
+	})
+
+	t.Run("UnknownPropertiesOfLatestVersion", func(t *testing.T) {
+		_, err := Parse(context.Background(), `schemaVersion: "1.1"
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    hots: appservice
+    infra: bicep
+`)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "line 7: services.api.hots: unknown property")
+		require.Contains(t, err.Error(), "line 8: services.api.infra: expected an object")
+	})
+
+	t.Run("InvalidValuesOfLatestVersion", func(t *testing.T) {
+		_, err := Parse(context.Background(), `schemaVersion: "1.1"
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: py
+    host: appservice
+    docker:
+      path: ./Dockerfile
+hooks:
+  preprovision:
+    shell: sh
+`)
+		require.Error(t, err)
+
+		var validationErr *SchemaValidationError
+		require.True(t, errors.As(err, &validationErr))
+		require.Equal(t, []SchemaError{
+			{
+				Line:    6,
+				Path:    "services.api.language",
+				Message: "'py' isn't one of the allowed values: dotnet, csharp, fsharp, python, js, ts, java",
+			},
+			{Line: 8, Path: "services.api.docker", Message: "isn't allowed here"},
+			{Line: 12, Path: "hooks.preprovision", Message: "missing required property run"},
+		}, validationErr.Errors)
+	})
+
+	t.Run("InvalidValuesOfLegacyVersion", func(t *testing.T) {
+		projectConfig, err := Parse(context.Background(), `name: test-proj
+services:
+  api:
+    project: src/api
+    language: py
+    host: appservice
+`)
+		require.NoError(t, err)
+		require.Equal(t, ServiceLanguagePython, projectConfig.Services["api"].Language)
+	})
+
+	t.Run("NewProject", func(t *testing.T) {
+		projectConfig, err := New(context.Background(), filepath.Join(t.TempDir(), "azure.yaml"), "test-proj")
+		require.NoError(t, err)
+		require.Equal(t, LatestSchemaVersion, projectConfig.SchemaVersion)
+	})
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		_, err := Parse(context.Background(), "schemaVersion: 9.0\nname: test-proj\n")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "line 1: schemaVersion '9.0' isn't supported")
+	})
+}
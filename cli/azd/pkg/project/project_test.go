@@ -5,6 +5,7 @@ package project
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
@@ -106,6 +107,214 @@ services:
 	require.Equal(t, resourceName, targetResource.ResourceName())
 }
 
+func TestResourceTagsVerified(t *testing.T) {
+	const testProj = `
+name: test-proj
+metadata:
+  template: test-proj-template
+resourceGroup: rg-test
+infra:
+  tags:
+    cost-center: ${COST_CENTER}
+    owner: platform-team
+services:
+  api:
+    project: src/api
+    language: js
+    host: appservice
+`
+	tests := map[string]struct {
+		tags map[string]*string
+		err  string
+	}{
+		"Tagged": {
+			tags: map[string]*string{
+				"cost-center": convert.RefOf("cc-42"),
+				"owner":       convert.RefOf("platform-team"),
+			},
+		},
+		"MissingTag": {
+			tags: map[string]*string{
+				"cost-center": convert.RefOf("cc-42"),
+			},
+			err: "missing the tags 'owner: platform-team'",
+		},
+		"WrongValue": {
+			tags: map[string]*string{
+				"cost-center": convert.RefOf("cc-1"),
+				"owner":       convert.RefOf("platform-team"),
+			},
+			err: "missing the tags 'cost-center: cc-42'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			tags := map[string]*string{defaultServiceTag: convert.RefOf("api")}
+			for key, value := range test.tags {
+				tags[key] = value
+			}
+
+			mockarmresources.AddAzResourceListMock(
+				mockContext.HttpClient,
+				convert.RefOf("rg-test"),
+				[]*armresources.GenericResourceExpanded{
+					{
+						ID:       convert.RefOf("app-api-abc123"),
+						Name:     convert.RefOf("app-api-abc123"),
+						Type:     convert.RefOf(string(infra.AzureResourceTypeWebSite)),
+						Location: convert.RefOf("eastus2"),
+						Tags:     tags,
+					},
+				},
+			)
+			azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+			env := environment.EphemeralWithValues("envA", map[string]string{
+				environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+				"COST_CENTER":                        "cc-42",
+			})
+			projectConfig, err := Parse(*mockContext.Context, testProj)
+			require.NoError(t, err)
+
+			resourceManager := NewResourceManager(env, azCli)
+			_, err = resourceManager.GetTargetResource(
+				*mockContext.Context, env.GetSubscriptionId(), projectConfig.Services["api"])
+			if test.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.err)
+			}
+		})
+	}
+}
+
+func TestServiceResourcesFromResourceGraph(t *testing.T) {
+	const testProj = `
+name: test-proj
+metadata:
+  template: test-proj-template
+resourceGroup: rg-test
+services:
+  api:
+    project: src/api
+    language: js
+    host: appservice
+  web:
+    project: src/web
+    language: js
+    host: appservice
+`
+	mockContext := mocks.NewMockContext(context.Background())
+
+	queries := 0
+	mockContext.HttpClient.When(mockarmresources.IsResourceGraphQuery).RespondFn(
+		func(request *http.Request) (*http.Response, error) {
+			queries++
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"data": []map[string]any{
+					{
+						"id":       "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test/providers/Microsoft.Web/sites/app-api",
+						"name":     "app-api",
+						"type":     string(infra.AzureResourceTypeWebSite),
+						"location": "eastus2",
+						"tags":     map[string]string{defaultServiceTag: "api"},
+					},
+					{
+						"id":       "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test/providers/Microsoft.Web/sites/app-web",
+						"name":     "app-web",
+						"type":     string(infra.AzureResourceTypeWebSite),
+						"location": "eastus2",
+						"tags":     map[string]string{defaultServiceTag: "web"},
+					},
+				},
+			})
+		})
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+	env := environment.EphemeralWithValues("envA", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	resourceManager := NewResourceManager(env, azCli)
+
+	apiResource, err := resourceManager.GetTargetResource(
+		*mockContext.Context, env.GetSubscriptionId(), projectConfig.Services["api"])
+	require.NoError(t, err)
+	require.Equal(t, "app-api", apiResource.ResourceName())
+	require.Equal(t, "rg-test", apiResource.ResourceGroupName())
+
+	webResource, err := resourceManager.GetTargetResource(
+		*mockContext.Context, env.GetSubscriptionId(), projectConfig.Services["web"])
+	require.NoError(t, err)
+	require.Equal(t, "app-web", webResource.ResourceName())
+	require.Equal(t, "rg-test", webResource.ResourceGroupName())
+
+	// The tagged resources of all the services are found with a single query
+	require.Equal(t, 1, queries)
+}
+
+func TestServiceResourcesFallBackToResourceGroup(t *testing.T) {
+	const testProj = `
+name: test-proj
+metadata:
+  template: test-proj-template
+resourceGroup: rg-test
+services:
+  api:
+    project: src/api
+    language: js
+    host: appservice
+`
+	mockContext := mocks.NewMockContext(context.Background())
+
+	// Resource Graph doesn't have the resources provisioned moments ago yet
+	mockContext.HttpClient.When(mockarmresources.IsResourceGraphQuery).RespondFn(
+		func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"data": []map[string]any{},
+			})
+		})
+	mockarmresources.AddAzResourceListMock(
+		mockContext.HttpClient,
+		convert.RefOf("rg-test"),
+		[]*armresources.GenericResourceExpanded{
+			{
+				ID:       convert.RefOf("app-api"),
+				Name:     convert.RefOf("app-api"),
+				Type:     convert.RefOf(string(infra.AzureResourceTypeWebSite)),
+				Location: convert.RefOf("eastus2"),
+				Tags:     map[string]*string{defaultServiceTag: convert.RefOf("api")},
+			},
+		},
+	)
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+
+	env := environment.EphemeralWithValues("envA", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+	})
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	resourceManager := NewResourceManager(env, azCli)
+	apiResource, err := resourceManager.GetTargetResource(
+		*mockContext.Context, env.GetSubscriptionId(), projectConfig.Services["api"])
+	require.NoError(t, err)
+	require.Equal(t, "app-api", apiResource.ResourceName())
+}
+
+func Test_serviceResourcesQuery(t *testing.T) {
+	query := serviceResourcesQuery("RG-Test'")
+
+	require.Contains(t, query, "where resourceGroup =~ 'RG-Test\\''")
+	require.Contains(t, query, "isnotempty(tags['azd-service-name'])")
+}
+
 func TestResourceGroupOverrideFromProjectFile(t *testing.T) {
 	const testProj = `
 name: test-proj
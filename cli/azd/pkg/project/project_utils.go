@@ -4,23 +4,42 @@
 package project
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/azure/azure-dev/cli/azd/pkg/artifacts"
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
+	"github.com/azure/azure-dev/cli/azd/pkg/shutdown"
 	"github.com/otiai10/copy"
 )
 
 // CreateDeployableZip creates a zip file of a folder, recursively.
-// Returns the path to the created zip file or an error if it fails.
-func createDeployableZip(appName string, path string) (string, error) {
+// Returns the path to the created zip file, in the artifacts directory of azd, or an error if it fails.
+func createDeployableZip(ctx context.Context, appName string, path string) (string, error) {
 	// TODO: should probably avoid picking up files that weren't meant to be deployed (ie, local .env files, etc..)
-	zipFile, err := os.CreateTemp("", "azddeploy*.zip")
+	zipFile, err := artifacts.CreateTemp("azddeploy*.zip")
 	if err != nil {
 		return "", fmt.Errorf("failed when creating zip package to deploy %s: %w", appName, err)
 	}
 
-	if err := rzip.CreateFromDirectory(path, zipFile); err != nil {
+	// Remove the partially written zip file when azd is cancelled while compressing
+	unregister := shutdown.Register(ctx, "remove deployment package", func(ctx context.Context) error {
+		if err := os.Remove(zipFile.Name()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		return nil
+	})
+	defer unregister()
+
+	if err := rzip.CreateFromDirectory(ctx, path, zipFile); err != nil {
 		// if we fail here just do our best to close things out and cleanup
 		zipFile.Close()
 		os.Remove(zipFile.Name())
@@ -36,6 +55,59 @@ func createDeployableZip(appName string, path string) (string, error) {
 	return zipFile.Name(), nil
 }
 
+// withUploadProgress wraps the deployment package so reading it for the upload reports the uploaded size and percentage
+// as progress of the task. The returned reader is seekable, so a retried upload starts over instead of sending a
+// partial package.
+func withUploadProgress(
+	zipFile *os.File,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) (io.ReadSeeker, error) {
+	info, err := zipFile.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading size of deployment package: %w", err)
+	}
+
+	return streaming.NewRequestProgress(streaming.NopCloser(zipFile), uploadProgress(info.Size(), task)), nil
+}
+
+// uploadProgress returns a function reporting the uploaded size and percentage of a deployment package of total bytes
+// as progress of the task. It can be called concurrently, by uploads sending parts of the package in parallel.
+func uploadProgress(
+	total int64,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) func(uploaded int64) {
+	lastPercent := -1
+	mu := sync.Mutex{}
+
+	return func(uploaded int64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		percent := 100
+		if total > 0 {
+			percent = int(uploaded * 100 / total)
+		}
+
+		// Only report whole percent changes, the body is read in small chunks
+		if percent == lastPercent {
+			return
+		}
+		lastPercent = percent
+
+		message := fmt.Sprintf(
+			"Uploading deployment package (%s of %s, %d%%)", formatMegabytes(uploaded), formatMegabytes(total), percent)
+		if uploaded >= total {
+			message = "Waiting for the deployment of the package to complete"
+		}
+
+		task.SetProgress(NewServiceProgress(message))
+	}
+}
+
+func formatMegabytes(size int64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+}
+
 // excludeDirEntryCondition resolves when a file or directory should be considered or not as part of build, when build is a
 // copy-paste source strategy. Return true to exclude the directory entry.
 type excludeDirEntryCondition func(path string, file os.FileInfo) bool
@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/azureutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
@@ -40,13 +45,19 @@ type ResourceManager interface {
 type resourceManager struct {
 	env   *environment.Environment
 	azCli azcli.AzCli
+
+	// serviceResources caches the resources tagged with 'azd-service-name' found by a Resource Graph query, keyed by
+	// subscription and resource group, so the services of an operation are resolved with a single query.
+	serviceResources map[string][]azcli.AzCliResource
+	cacheMu          sync.Mutex
 }
 
 // NewResourceManager creates a new instance of the project resource manager
 func NewResourceManager(env *environment.Environment, azCli azcli.AzCli) ResourceManager {
 	return &resourceManager{
-		env:   env,
-		azCli: azCli,
+		env:              env,
+		azCli:            azCli,
+		serviceResources: map[string][]azcli.AzCliResource{},
 	}
 }
 
@@ -66,7 +77,7 @@ func (rm *resourceManager) GetResourceGroupName(
 ) (string, error) {
 	name, err := projectConfig.ResourceGroupName.Envsubst(rm.env.Getenv)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("resolving resourceGroup: %w", err)
 	}
 
 	if strings.TrimSpace(name) != "" {
@@ -90,24 +101,76 @@ func (rm *resourceManager) GetResourceGroupName(
 // GetServiceResources finds azure service resources targeted by the service.
 //
 // If an explicit `ResourceName` is specified in `azure.yaml`, a resource with that name is searched for.
-// Otherwise, searches for resources with 'azd-service-name' tag set to the service key.
+// Otherwise, searches for resources of the resource group with 'azd-service-name' tag set to the service key.
 func (rm *resourceManager) GetServiceResources(
 	ctx context.Context,
 	subscriptionId string,
 	resourceGroupName string,
 	serviceConfig *ServiceConfig,
 ) ([]azcli.AzCliResource, error) {
-	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", defaultServiceTag, serviceConfig.Name)
-
+	// Unset values expand to an empty string, the resource names being outputs of a provisioning which may not have run
+	// yet, and the resources are then found by their tag
 	subst, err := serviceConfig.ResourceName.Envsubst(rm.env.Getenv)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("resolving resourceName of service %s: %w", serviceConfig.Name, err)
 	}
 
 	if strings.TrimSpace(subst) != "" {
-		filter = fmt.Sprintf("name eq '%s'", subst)
+		filter := fmt.Sprintf("name eq '%s'", subst)
+		return rm.azCli.ListResourceGroupResources(
+			ctx,
+			subscriptionId,
+			resourceGroupName,
+			&azcli.ListResourceGroupResourcesOptions{
+				Filter: &filter,
+			},
+		)
+	}
+
+	return rm.findTaggedServiceResources(ctx, subscriptionId, resourceGroupName, serviceConfig.Name)
+}
+
+// findTaggedServiceResources finds the resources of the resource group tagged with 'azd-service-name' set to the
+// service name.
+//
+// The tagged resources of all the services are found with a single Resource Graph query, which is cached for the
+// duration of the command. Resource Graph is eventually consistent, so resources provisioned moments ago may be missing
+// from the query results: when the query fails or has no resource for the service, the resources of the resource
+// group are listed instead.
+func (rm *resourceManager) findTaggedServiceResources(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	serviceName string,
+) ([]azcli.AzCliResource, error) {
+	rm.cacheMu.Lock()
+	defer rm.cacheMu.Unlock()
+
+	cacheKey := fmt.Sprintf("%s/%s", subscriptionId, strings.ToLower(resourceGroupName))
+	if cached, has := rm.serviceResources[cacheKey]; has {
+		if resources := filterByServiceTag(cached, serviceName); len(resources) > 0 {
+			return resources, nil
+		}
+	}
+
+	query := serviceResourcesQuery(resourceGroupName)
+	resources, err := rm.azCli.QueryResources(ctx, subscriptionId, query)
+	if err != nil {
+		log.Printf("failed querying resource graph, listing the resources of '%s' instead: %v", resourceGroupName, err)
+	} else {
+		rm.serviceResources[cacheKey] = resources
+		if matches := filterByServiceTag(resources, serviceName); len(matches) > 0 {
+			return matches, nil
+		}
+
+		log.Printf(
+			"resource graph has no resource for service '%s' yet, listing the resources of '%s' instead",
+			serviceName,
+			resourceGroupName,
+		)
 	}
 
+	filter := fmt.Sprintf("tagName eq '%s' and tagValue eq '%s'", defaultServiceTag, serviceName)
 	return rm.azCli.ListResourceGroupResources(
 		ctx,
 		subscriptionId,
@@ -118,6 +181,30 @@ func (rm *resourceManager) GetServiceResources(
 	)
 }
 
+// serviceResourcesQuery returns the Resource Graph query finding the resources of the resource group tagged with
+// 'azd-service-name'.
+func serviceResourcesQuery(resourceGroupName string) string {
+	escape := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace
+
+	return fmt.Sprintf(`resources
+| where resourceGroup =~ '%[2]s' and isnotempty(tags['%[1]s'])
+| project id, name, type, location, tags`,
+		defaultServiceTag,
+		escape(resourceGroupName),
+	)
+}
+
+func filterByServiceTag(resources []azcli.AzCliResource, serviceName string) []azcli.AzCliResource {
+	matches := []azcli.AzCliResource{}
+	for _, resource := range resources {
+		if resource.Tags[defaultServiceTag] == serviceName {
+			matches = append(matches, resource)
+		}
+	}
+
+	return matches
+}
+
 // GetServiceResources gets the specific azure service resource targeted by the service.
 //
 // rerunCommand specifies the command that users should rerun in case of misconfiguration.
@@ -131,7 +218,7 @@ func (rm *resourceManager) GetServiceResource(
 ) (azcli.AzCliResource, error) {
 	expandedResourceName, err := serviceConfig.ResourceName.Envsubst(rm.env.Getenv)
 	if err != nil {
-		return azcli.AzCliResource{}, fmt.Errorf("expanding name: %w", err)
+		return azcli.AzCliResource{}, fmt.Errorf("resolving resourceName of service %s: %w", serviceConfig.Name, err)
 	}
 
 	resources, err := rm.GetServiceResources(ctx, subscriptionId, resourceGroupName, serviceConfig)
@@ -182,13 +269,88 @@ func (rm *resourceManager) GetServiceResource(
 		}
 	}
 
+	if err := rm.verifyTags(resources[0], serviceConfig, rerunCommand); err != nil {
+		return azcli.AzCliResource{}, err
+	}
+
 	return resources[0], nil
 }
 
+// verifyTags ensures the service resource carries the additional tags declared for the project, see
+// provisioning.ResolveTags.
+func (rm *resourceManager) verifyTags(
+	resource azcli.AzCliResource,
+	serviceConfig *ServiceConfig,
+	rerunCommand string,
+) error {
+	if serviceConfig.Project == nil {
+		return nil
+	}
+
+	if !provisioning.InjectsTags(serviceConfig.Project.Infra) {
+		log.Printf("skipping tags verification, %s doesn't apply the additional tags", serviceConfig.Project.Infra.Provider)
+		return nil
+	}
+
+	tags, err := provisioning.ResolveTags(serviceConfig.Project.Infra, rm.env)
+	if err != nil {
+		return fmt.Errorf("resolving tags: %w", err)
+	}
+
+	mismatched := []string{}
+	for name, value := range tags {
+		if strings.HasPrefix(name, "azd-") {
+			continue
+		}
+
+		if actual, has := resource.Tags[name]; !has || actual != value {
+			mismatched = append(mismatched, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+
+	if len(mismatched) == 0 {
+		return nil
+	}
+
+	sort.Strings(mismatched)
+	return fmt.Errorf(
+		//nolint:lll
+		"resource '%s' is missing the tags '%s'. Ensure the '%s' parameter is applied to the service resource in your infrastructure configuration, and rerun %s",
+		resource.Name,
+		strings.Join(mismatched, "', '"),
+		provisioning.TagsParameterName,
+		rerunCommand,
+	)
+}
+
 func (rm *resourceManager) GetTargetResource(
 	ctx context.Context,
 	subscriptionId string,
 	serviceConfig *ServiceConfig,
+) (*environment.TargetResource, error) {
+	if !serviceConfig.Host.RequiresAzureResource() {
+		// Service target plugins receive the tagged resource of the service when there is one
+		if !serviceConfig.Host.IsBuiltIn() {
+			targetResource, err := rm.lookupTargetResource(ctx, subscriptionId, serviceConfig)
+			if err == nil {
+				return targetResource, nil
+			}
+
+			log.Printf("service '%s' has no Azure resource: %v", serviceConfig.Name, err)
+		}
+
+		// Services handed off to external endpoints have no Azure resource
+		return environment.NewTargetResource(subscriptionId, "", "", ""), nil
+	}
+
+	return rm.lookupTargetResource(ctx, subscriptionId, serviceConfig)
+}
+
+// lookupTargetResource finds the Azure resource the service is deployed to.
+func (rm *resourceManager) lookupTargetResource(
+	ctx context.Context,
+	subscriptionId string,
+	serviceConfig *ServiceConfig,
 ) (*environment.TargetResource, error) {
 	resourceGroupName, err := rm.GetResourceGroupName(ctx, subscriptionId, serviceConfig.Project)
 	if err != nil {
@@ -200,6 +362,11 @@ func (rm *resourceManager) GetTargetResource(
 		return nil, err
 	}
 
+	// The resource id is authoritative for the resource group of the resource
+	if resourceGroup := azure.GetResourceGroupName(azureResource.Id); resourceGroup != nil {
+		resourceGroupName = *resourceGroup
+	}
+
 	return environment.NewTargetResource(
 		subscriptionId,
 		resourceGroupName,
@@ -0,0 +1,94 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/trivy"
+)
+
+// sbomFormat returns the Trivy format and the file extension of the SBOMs configured by the options.
+func sbomFormat(options *SbomOptions) (trivy.SbomFormat, string, error) {
+	switch strings.ToLower(options.Format) {
+	case "", "cyclonedx":
+		return trivy.SbomFormatCycloneDx, ".cdx.json", nil
+	case "spdx":
+		return trivy.SbomFormatSpdx, ".spdx.json", nil
+	default:
+		return "", "", fmt.Errorf("invalid sbom format '%s', expected cyclonedx or spdx", options.Format)
+	}
+}
+
+// sbomArtifactType returns the OCI artifact type of an SBOM attached to a container image.
+func sbomArtifactType(sbomPath string) string {
+	if strings.HasSuffix(sbomPath, ".spdx.json") {
+		return "application/spdx+json"
+	}
+
+	return "application/vnd.cyclonedx+json"
+}
+
+// generateSbom writes the SBOM of the package of the service, and returns its path. The SBOM of container images lists
+// the packages of the image, and the SBOM of other packages the dependencies declared by the lock files and manifests
+// of the service, like package-lock.json, packages.lock.json, requirements.txt or go.mod.
+func (sm *serviceManager) generateSbom(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageResult *ServicePackageResult,
+) (string, error) {
+	format, extension, err := sbomFormat(serviceConfig.Project.Sbom)
+	if err != nil {
+		return "", err
+	}
+
+	var trivyCli trivy.Trivy
+	if err := sm.serviceLocator.Resolve(&trivyCli); err != nil {
+		return "", fmt.Errorf("resolving trivy: %w", err)
+	}
+
+	sbomDir := filepath.Join(
+		serviceConfig.Project.Path, azdcontext.EnvironmentDirectoryName, sm.env.GetEnvName(), "sbom",
+	)
+	if err := os.MkdirAll(sbomDir, osutil.PermissionDirectory); err != nil {
+		return "", fmt.Errorf("creating sbom directory: %w", err)
+	}
+
+	sbomPath := filepath.Join(sbomDir, serviceConfig.Name+extension)
+	if details, ok := packageResult.Details.(*dockerPackageResult); ok && details.ImageTag != "" {
+		err = trivyCli.GenerateImageSbom(ctx, serviceConfig.Path(), details.ImageTag, format, sbomPath)
+	} else {
+		err = trivyCli.GenerateFsSbom(ctx, serviceConfig.Path(), format, sbomPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return sbomPath, nil
+}
+
+// sbomTools returns the tools generating and uploading the SBOMs of the services.
+func (sm *serviceManager) sbomTools(options *SbomOptions) ([]tools.ExternalTool, error) {
+	var trivyCli trivy.Trivy
+	if err := sm.serviceLocator.Resolve(&trivyCli); err != nil {
+		return nil, fmt.Errorf("resolving trivy: %w", err)
+	}
+
+	sbomTools := []tools.ExternalTool{trivyCli}
+	if options.Upload {
+		var orasCli oras.Oras
+		if err := sm.serviceLocator.Resolve(&orasCli); err != nil {
+			return nil, fmt.Errorf("resolving oras: %w", err)
+		}
+
+		sbomTools = append(sbomTools, orasCli)
+	}
+
+	return sbomTools, nil
+}
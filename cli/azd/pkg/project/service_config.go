@@ -1,6 +1,7 @@
 package project
 
 import (
+	"path"
 	"path/filepath"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
@@ -28,10 +29,42 @@ type ServiceConfig struct {
 	K8s AksOptions `yaml:"k8s"`
 	// The optional Azure Spring Apps options
 	Spring SpringOptions `yaml:"spring"`
+	// The optional App Service options
+	AppService AppServiceOptions `yaml:"appService"`
+	// The optional webhook options
+	Webhook WebhookOptions `yaml:"webhook"`
+	// The optional API Management options
+	Apim ApimOptions `yaml:"apim"`
+	// The optional IoT Edge options
+	IotEdge IotEdgeOptions `yaml:"iotEdge"`
+	// The optional Databricks and Synapse options
+	DataPlatform DataPlatformOptions `yaml:"dataPlatform"`
+	// The optional Java build options
+	Java JavaOptions `yaml:"java"`
+	// The optional Python packaging options
+	Python PythonOptions `yaml:"python"`
+	// The optional Node.js packaging options
+	Node NodeOptions `yaml:"node"`
+	// The optional .NET publish options
+	DotNet DotNetOptions `yaml:"dotnet"`
+	// The optional Go build options
+	Go GoOptions `yaml:"go"`
+	// The optional test commands run by azd test
+	Test *TestOptions `yaml:"test,omitempty"`
 	// The infrastructure provisioning configuration
 	Infra provisioning.Options `yaml:"infra"`
 	// Hook configuration for service
 	Hooks map[string]*ext.HookConfig `yaml:"hooks,omitempty"`
+	// The resources used by the service, and the roles assigned to its managed identity on them
+	Uses []ServiceUsesConfig `yaml:"uses,omitempty"`
+	// The Service Connector connections created from the service to the resources it uses when it's deployed
+	Connections []ServiceConnectionConfig `yaml:"connections,omitempty"`
+	// The CORS policy of the service, applied after each deployment
+	Cors *CorsOptions `yaml:"cors,omitempty"`
+	// The custom domains bound to the service after each deployment
+	CustomDomains []CustomDomainConfig `yaml:"customDomains,omitempty"`
+	// The environments the service is enabled in, all environments when not set
+	Condition *ServiceCondition `yaml:"condition,omitempty"`
 
 	*ext.EventDispatcher[ServiceLifecycleEventArgs] `yaml:",omitempty"`
 
@@ -42,3 +75,37 @@ type ServiceConfig struct {
 func (sc *ServiceConfig) Path() string {
 	return filepath.Join(sc.Project.Path, sc.RelativePath)
 }
+
+// ServiceCondition configures the environments a service is enabled in, like a worker enabled only in production or a
+// mock API enabled only in development. Environments are matched by name or by patterns like "dev-*".
+type ServiceCondition struct {
+	// The environments the service is enabled in, all environments when empty
+	Environments []string `yaml:"environments,omitempty"`
+	// The environments the service is disabled in, taking precedence over Environments
+	ExcludeEnvironments []string `yaml:"excludeEnvironments,omitempty"`
+}
+
+// EnabledIn returns true when the service is enabled in the environment. Services without a condition are enabled in
+// all environments.
+func (sc *ServiceConfig) EnabledIn(envName string) bool {
+	if sc.Condition == nil {
+		return true
+	}
+
+	if matchesEnvironment(sc.Condition.ExcludeEnvironments, envName) {
+		return false
+	}
+
+	return len(sc.Condition.Environments) == 0 || matchesEnvironment(sc.Condition.Environments, envName)
+}
+
+// matchesEnvironment returns true when the name of the environment matches one of the patterns.
+func matchesEnvironment(patterns []string, envName string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, envName); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -11,6 +11,45 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestServiceConfigEnabledIn(t *testing.T) {
+	tests := map[string]struct {
+		condition *ServiceCondition
+		enabled   []string
+		disabled  []string
+	}{
+		"NoCondition": {
+			enabled: []string{"dev", "prod"},
+		},
+		"Environments": {
+			condition: &ServiceCondition{Environments: []string{"prod", "staging-*"}},
+			enabled:   []string{"prod", "staging-west"},
+			disabled:  []string{"dev", "staging"},
+		},
+		"ExcludeEnvironments": {
+			condition: &ServiceCondition{ExcludeEnvironments: []string{"prod"}},
+			enabled:   []string{"dev", "test"},
+			disabled:  []string{"prod"},
+		},
+		"ExcludeTakesPrecedence": {
+			condition: &ServiceCondition{Environments: []string{"dev-*"}, ExcludeEnvironments: []string{"dev-shared"}},
+			enabled:   []string{"dev-alice"},
+			disabled:  []string{"dev-shared", "prod"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			service := &ServiceConfig{Name: "api", Condition: test.condition}
+			for _, envName := range test.enabled {
+				require.True(t, service.EnabledIn(envName), envName)
+			}
+			for _, envName := range test.disabled {
+				require.False(t, service.EnabledIn(envName), envName)
+			}
+		})
+	}
+}
+
 func TestServiceConfigAddHandler(t *testing.T) {
 	ctx := context.Background()
 	service := getServiceConfig()
@@ -0,0 +1,190 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The name of the container of the Container Apps created by the azd templates
+const defaultConnectionContainer = "main"
+
+// The client types of Service Connector, by the language of the service. The client type selects the names of the
+// configuration values, like AZURE_STORAGEBLOB_RESOURCEENDPOINT, in the form the client libraries of the language read.
+var connectionClientTypes = map[ServiceLanguageKind]string{
+	ServiceLanguageDotNet:     "dotnet",
+	ServiceLanguageCsharp:     "dotnet",
+	ServiceLanguageFsharp:     "dotnet",
+	ServiceLanguagePython:     "python",
+	ServiceLanguageJavaScript: "nodejs",
+	ServiceLanguageTypeScript: "nodejs",
+	ServiceLanguageJava:       "java",
+	ServiceLanguageGo:         "go",
+}
+
+// ServiceConnectionConfig declares a Service Connector connection from the resource hosting a service to a resource it
+// uses, like a storage account or a database, created when the service is deployed.
+type ServiceConnectionConfig struct {
+	// The name of the connection, unique for the service
+	Name string `yaml:"name"`
+	// The name of a resource of the resource group of the environment, or the id of a resource. Supports environment
+	// variable substitution, like ${AZURE_STORAGE_ACCOUNT_NAME}.
+	Resource ExpandableString `yaml:"resource"`
+	// The path of the sub resource connected to, relative to the resource, like blobServices/default
+	SubResource string `yaml:"subResource,omitempty"`
+	// The authentication of the connection, systemAssignedIdentity or secret. Defaults to systemAssignedIdentity.
+	AuthType string `yaml:"authType,omitempty"`
+	// The client type of the connection, like python or dotnet. Defaults to the client type of the service language.
+	ClientType string `yaml:"clientType,omitempty"`
+	// The container connecting, for Container Apps. Defaults to the main container.
+	Container string `yaml:"container,omitempty"`
+}
+
+// ServiceConnector creates the Service Connector connections declared by the connections section of the services.
+type ServiceConnector struct {
+	env             *environment.Environment
+	azCli           azcli.AzCli
+	resourceManager ResourceManager
+}
+
+// NewServiceConnector creates a new instance of the ServiceConnector
+func NewServiceConnector(
+	env *environment.Environment,
+	azCli azcli.AzCli,
+	resourceManager ResourceManager,
+) *ServiceConnector {
+	return &ServiceConnector{
+		env:             env,
+		azCli:           azCli,
+		resourceManager: resourceManager,
+	}
+}
+
+// Connect creates or updates the connections of the deployed service, and returns the configuration values Service
+// Connector set in the app settings of the service. Connections which already exist are updated, so connecting after
+// each deployment is safe.
+func (sc *ServiceConnector) Connect(
+	ctx context.Context,
+	svc *ServiceConfig,
+) ([]azsdk.ServiceConnectionConfiguration, error) {
+	if len(svc.Connections) == 0 {
+		return nil, nil
+	}
+
+	if err := validateConnections(svc); err != nil {
+		return nil, err
+	}
+
+	subscriptionId := sc.env.GetSubscriptionId()
+	resourceGroupName, err := sc.resourceManager.GetResourceGroupName(ctx, subscriptionId, svc.Project)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource group name: %w", err)
+	}
+
+	source, err := sc.resourceManager.GetServiceResource(ctx, subscriptionId, resourceGroupName, svc, "deploy")
+	if err != nil {
+		return nil, err
+	}
+
+	configurations := []azsdk.ServiceConnectionConfiguration{}
+	for _, connection := range svc.Connections {
+		targetId, err := findResourceId(
+			ctx, sc.env, sc.azCli, subscriptionId, resourceGroupName, svc, connection.Resource)
+		if err != nil {
+			return nil, err
+		}
+
+		if connection.SubResource != "" {
+			targetId = targetId + "/" + strings.Trim(connection.SubResource, "/")
+		}
+
+		connectionConfigurations, err := sc.azCli.CreateServiceConnection(
+			ctx,
+			subscriptionId,
+			source.Id,
+			connection.Name,
+			azsdk.ServiceConnection{
+				TargetId:   targetId,
+				AuthType:   connectionAuthType(connection),
+				ClientType: connectionClientType(svc, connection),
+				Scope:      connectionScope(svc, connection),
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("connecting service %s: %w", svc.Name, err)
+		}
+
+		configurations = append(configurations, connectionConfigurations...)
+	}
+
+	return configurations, nil
+}
+
+func validateConnections(svc *ServiceConfig) error {
+	names := map[string]bool{}
+	for i, connection := range svc.Connections {
+		if connection.Name == "" {
+			return fmt.Errorf("service %s: connections[%d].name must be set", svc.Name, i)
+		}
+
+		if names[connection.Name] {
+			return fmt.Errorf("service %s: connection '%s' is declared more than once", svc.Name, connection.Name)
+		}
+		names[connection.Name] = true
+
+		if connection.Resource == (ExpandableString{}) {
+			return fmt.Errorf("service %s: connections[%d].resource must be set", svc.Name, i)
+		}
+
+		switch connection.AuthType {
+		case "", azsdk.ServiceConnectionAuthSystemIdentity, azsdk.ServiceConnectionAuthSecret:
+		default:
+			return fmt.Errorf(
+				"service %s: connections[%d].authType '%s' isn't supported, use '%s' or '%s'",
+				svc.Name,
+				i,
+				connection.AuthType,
+				azsdk.ServiceConnectionAuthSystemIdentity,
+				azsdk.ServiceConnectionAuthSecret,
+			)
+		}
+	}
+
+	return nil
+}
+
+func connectionAuthType(connection ServiceConnectionConfig) string {
+	if connection.AuthType != "" {
+		return connection.AuthType
+	}
+
+	return azsdk.ServiceConnectionAuthSystemIdentity
+}
+
+func connectionClientType(svc *ServiceConfig, connection ServiceConnectionConfig) string {
+	if connection.ClientType != "" {
+		return connection.ClientType
+	}
+
+	if clientType, has := connectionClientTypes[svc.Language]; has {
+		return clientType
+	}
+
+	return "none"
+}
+
+func connectionScope(svc *ServiceConfig, connection ServiceConnectionConfig) string {
+	if connection.Container != "" {
+		return connection.Container
+	}
+
+	if svc.Host == ContainerAppTarget {
+		return defaultConnectionContainer
+	}
+
+	return ""
+}
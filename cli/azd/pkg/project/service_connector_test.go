@@ -0,0 +1,152 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceConnectorConnect(t *testing.T) {
+	const testProj = `
+name: test-proj
+resourceGroup: rg-test
+services:
+  api:
+    project: src/api
+    language: py
+    host: containerapp
+    connections:
+      - name: storage
+        resource: ${AZURE_STORAGE_ACCOUNT_NAME}
+        subResource: blobServices/default
+`
+	const resourceGroupId = "/subscriptions/SUBSCRIPTION_ID/resourceGroups/rg-test"
+	const containerAppId = resourceGroupId + "/providers/Microsoft.App/containerApps/ca-api"
+	const storageAccountId = resourceGroupId + "/providers/Microsoft.Storage/storageAccounts/sttest"
+	const linkerPath = containerAppId + "/providers/Microsoft.ServiceLinker/linkers/storage"
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockarmresources.AddAzResourceListMock(
+		mockContext.HttpClient,
+		convert.RefOf("rg-test"),
+		[]*armresources.GenericResourceExpanded{
+			{
+				ID:       convert.RefOf(containerAppId),
+				Name:     convert.RefOf("ca-api"),
+				Type:     convert.RefOf(string(infra.AzureResourceTypeContainerApp)),
+				Location: convert.RefOf("eastus2"),
+				Tags: map[string]*string{
+					defaultServiceTag: convert.RefOf("api"),
+				},
+			},
+			{
+				ID:       convert.RefOf(storageAccountId),
+				Name:     convert.RefOf("sttest"),
+				Type:     convert.RefOf(string(infra.AzureResourceTypeStorageAccount)),
+				Location: convert.RefOf("eastus2"),
+			},
+		},
+	)
+
+	var linker map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && request.URL.Path == linkerPath
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &linker))
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, linker)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && request.URL.Path == linkerPath+"/listConfigurations"
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"configurations": []map[string]string{
+				{"name": "AZURE_STORAGEBLOB_RESOURCEENDPOINT", "value": "https://sttest.blob.core.windows.net/"},
+			},
+		})
+	})
+
+	env := environment.EphemeralWithValues("envA", map[string]string{
+		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+		"AZURE_STORAGE_ACCOUNT_NAME":         "sttest",
+	})
+
+	projectConfig, err := Parse(*mockContext.Context, testProj)
+	require.NoError(t, err)
+
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+	serviceConnector := NewServiceConnector(env, azCli, NewResourceManager(env, azCli))
+
+	configurations, err := serviceConnector.Connect(*mockContext.Context, projectConfig.Services["api"])
+	require.NoError(t, err)
+	require.Equal(t, []azsdk.ServiceConnectionConfiguration{
+		{Name: "AZURE_STORAGEBLOB_RESOURCEENDPOINT", Value: "https://sttest.blob.core.windows.net/"},
+	}, configurations)
+
+	properties := linker["properties"].(map[string]any)
+	require.Equal(t, map[string]any{
+		"type": "AzureResource",
+		"id":   storageAccountId + "/blobServices/default",
+	}, properties["targetService"])
+	require.Equal(t, map[string]any{"authType": "systemAssignedIdentity"}, properties["authInfo"])
+	require.Equal(t, "python", properties["clientType"])
+	require.Equal(t, "main", properties["scope"])
+}
+
+func TestServiceConnectorValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		connections []ServiceConnectionConfig
+		err         string
+	}{
+		{
+			name:        "NoName",
+			connections: []ServiceConnectionConfig{{Resource: NewExpandableString("sttest")}},
+			err:         "connections[0].name must be set",
+		},
+		{
+			name:        "NoResource",
+			connections: []ServiceConnectionConfig{{Name: "storage"}},
+			err:         "connections[0].resource must be set",
+		},
+		{
+			name: "Duplicate",
+			connections: []ServiceConnectionConfig{
+				{Name: "storage", Resource: NewExpandableString("sttest")},
+				{Name: "storage", Resource: NewExpandableString("sttest2")},
+			},
+			err: "connection 'storage' is declared more than once",
+		},
+		{
+			name: "AuthType",
+			connections: []ServiceConnectionConfig{
+				{Name: "storage", Resource: NewExpandableString("sttest"), AuthType: "userAssignedIdentity"},
+			},
+			err: "authType 'userAssignedIdentity' isn't supported",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateConnections(&ServiceConfig{Name: "api", Connections: test.connections})
+			require.Error(t, err)
+			require.True(t, strings.Contains(err.Error(), test.err), err.Error())
+		})
+	}
+}
@@ -6,12 +6,17 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry/events"
+	"github.com/azure/azure-dev/cli/azd/internal/telemetry/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/profiling"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
 const (
@@ -95,6 +100,7 @@ type ServiceManager interface {
 type serviceManager struct {
 	env                 *environment.Environment
 	resourceManager     ResourceManager
+	azCli               azcli.AzCli
 	serviceLocator      ioc.ServiceLocator
 	operationCache      map[string]any
 	alphaFeatureManager *alpha.FeatureManager
@@ -104,12 +110,14 @@ type serviceManager struct {
 func NewServiceManager(
 	env *environment.Environment,
 	resourceManager ResourceManager,
+	azCli azcli.AzCli,
 	serviceLocator ioc.ServiceLocator,
 	alphaFeatureManager *alpha.FeatureManager,
 ) ServiceManager {
 	return &serviceManager{
 		env:                 env,
 		resourceManager:     resourceManager,
+		azCli:               azCli,
 		serviceLocator:      serviceLocator,
 		operationCache:      map[string]any{},
 		alphaFeatureManager: alphaFeatureManager,
@@ -132,6 +140,15 @@ func (sm *serviceManager) GetRequiredTools(ctx context.Context, serviceConfig *S
 	requiredTools = append(requiredTools, frameworkService.RequiredExternalTools(ctx)...)
 	requiredTools = append(requiredTools, serviceTarget.RequiredExternalTools(ctx)...)
 
+	if serviceConfig.Project != nil && serviceConfig.Project.Sbom != nil {
+		sbomTools, err := sm.sbomTools(serviceConfig.Project.Sbom)
+		if err != nil {
+			return nil, err
+		}
+
+		requiredTools = append(requiredTools, sbomTools...)
+	}
+
 	return tools.Unique(requiredTools), nil
 }
 
@@ -189,7 +206,8 @@ func (sm *serviceManager) Restore(
 			task,
 			ServiceEventRestore,
 			serviceConfig,
-			func() *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+			nil,
+			func(ctx context.Context) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
 				return frameworkService.Restore(ctx, serviceConfig)
 			},
 		)
@@ -236,7 +254,8 @@ func (sm *serviceManager) Build(
 			task,
 			ServiceEventBuild,
 			serviceConfig,
-			func() *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+			nil,
+			func(ctx context.Context) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
 				return frameworkService.Build(ctx, serviceConfig, restoreOutput)
 			},
 		)
@@ -285,9 +304,11 @@ func (sm *serviceManager) Package(
 			return
 		}
 
+		// The package path is set once the service is packaged, for the postpackage hooks
 		eventArgs := ServiceLifecycleEventArgs{
 			Project: serviceConfig.Project,
 			Service: serviceConfig,
+			Args:    map[string]any{},
 		}
 
 		hasBuildOutput := buildOutput != nil
@@ -335,8 +356,10 @@ func (sm *serviceManager) Package(
 
 		var packageResult *ServicePackageResult
 
-		err = serviceConfig.Invoke(ctx, ServiceEventPackage, eventArgs, func() error {
-			frameworkPackageTask := frameworkService.Package(ctx, serviceConfig, buildOutput)
+		spanCtx, span := startServiceSpan(ctx, ServiceEventPackage, serviceConfig)
+		endStage := profiling.TrackStage(fmt.Sprintf("%s %s", ServiceEventPackage, serviceConfig.Name))
+		err = serviceConfig.Invoke(spanCtx, ServiceEventPackage, eventArgs, func() error {
+			frameworkPackageTask := frameworkService.Package(spanCtx, serviceConfig, buildOutput)
 			syncProgress(task, frameworkPackageTask.Progress())
 
 			frameworkPackageResult, err := frameworkPackageTask.Await()
@@ -344,7 +367,7 @@ func (sm *serviceManager) Package(
 				return err
 			}
 
-			serviceTargetPackageTask := serviceTarget.Package(ctx, serviceConfig, frameworkPackageResult)
+			serviceTargetPackageTask := serviceTarget.Package(spanCtx, serviceConfig, frameworkPackageResult)
 			syncProgress(task, serviceTargetPackageTask.Progress())
 
 			serviceTargetPackageResult, err := serviceTargetPackageTask.Await()
@@ -353,10 +376,24 @@ func (sm *serviceManager) Package(
 			}
 
 			packageResult = serviceTargetPackageResult
+			eventArgs.Args[PackagePathEventArg] = packageResult.PackagePath
+
+			if serviceConfig.Project.Sbom != nil {
+				task.SetProgress(NewServiceProgress("Generating SBOM"))
+				sbomPath, err := sm.generateSbom(ctx, serviceConfig, packageResult)
+				if err != nil {
+					return fmt.Errorf("generating SBOM: %w", err)
+				}
+
+				packageResult.Sbom = sbomPath
+			}
+
 			sm.setOperationResult(ctx, serviceConfig, string(ServiceEventPackage), packageResult)
 
 			return nil
 		})
+		endStage()
+		span.EndWithStatus(err)
 
 		if err != nil {
 			task.SetError(fmt.Errorf("failed packaging service '%s': %w", serviceConfig.Name, err))
@@ -401,12 +438,18 @@ func (sm *serviceManager) Deploy(
 			return
 		}
 
+		if err := ensureDeployPermissions(ctx, sm.azCli, targetResource); err != nil {
+			task.SetError(fmt.Errorf("failed deploying service '%s': %w", serviceConfig.Name, err))
+			return
+		}
+
 		deployResult, err := runCommand(
 			ctx,
 			task,
 			ServiceEventDeploy,
 			serviceConfig,
-			func() *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+			map[string]any{PackagePathEventArg: packagePath(packageResult)},
+			func(ctx context.Context) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
 				return serviceTarget.Deploy(ctx, serviceConfig, packageResult, targetResource)
 			},
 		)
@@ -445,12 +488,24 @@ func (sm *serviceManager) GetServiceTarget(ctx context.Context, serviceConfig *S
 	}
 
 	if err := sm.serviceLocator.ResolveNamed(host, &target); err != nil {
-		panic(fmt.Errorf(
-			"failed to resolve service host '%s' for service '%s', %w",
+		// Hosts which aren't built in are implemented by service target plugins
+		if _, pluginErr := findServiceTargetPlugin(serviceConfig.Host); pluginErr == nil {
+			var pluginManager *ServiceTargetPluginManager
+			if err := sm.serviceLocator.Resolve(&pluginManager); err != nil {
+				return nil, fmt.Errorf("resolving service target plugin manager: %w", err)
+			}
+
+			return pluginManager.ServiceTarget(ctx, serviceConfig.Host)
+		}
+
+		return nil, fmt.Errorf(
+			"unsupported host '%s' for service '%s', install the service target plugin %s%s: %w",
 			serviceConfig.Host,
 			serviceConfig.Name,
+			serviceTargetPluginPrefix,
+			serviceConfig.Host,
 			err,
-		))
+		)
 	}
 
 	return target, nil
@@ -537,17 +592,21 @@ func runCommand[T comparable, P comparable](
 	task *async.TaskContextWithProgress[T, P],
 	eventName ext.Event,
 	serviceConfig *ServiceConfig,
-	taskFunc func() *async.TaskWithProgress[T, P],
+	args map[string]any,
+	taskFunc func(ctx context.Context) *async.TaskWithProgress[T, P],
 ) (T, error) {
 	eventArgs := ServiceLifecycleEventArgs{
 		Project: serviceConfig.Project,
 		Service: serviceConfig,
+		Args:    args,
 	}
 
 	var result T
 
-	err := serviceConfig.Invoke(ctx, eventName, eventArgs, func() error {
-		serviceTask := taskFunc()
+	spanCtx, span := startServiceSpan(ctx, eventName, serviceConfig)
+	endStage := profiling.TrackStage(fmt.Sprintf("%s %s", eventName, serviceConfig.Name))
+	err := serviceConfig.Invoke(spanCtx, eventName, eventArgs, func() error {
+		serviceTask := taskFunc(spanCtx)
 		go syncProgress(task, serviceTask.Progress())
 
 		taskResult, err := serviceTask.Await()
@@ -558,6 +617,8 @@ func runCommand[T comparable, P comparable](
 		result = taskResult
 		return nil
 	})
+	endStage()
+	span.EndWithStatus(err)
 
 	if err != nil {
 		return result, err
@@ -566,6 +627,27 @@ func runCommand[T comparable, P comparable](
 	return result, nil
 }
 
+// packagePath returns the path of the package of the result, or an empty string when there is no package.
+func packagePath(packageResult *ServicePackageResult) string {
+	if packageResult == nil {
+		return ""
+	}
+
+	return packageResult.PackagePath
+}
+
+// startServiceSpan starts the telemetry span tracking a stage (restore, build, package, deploy) of a service, including
+// the hooks of the stage.
+func startServiceSpan(ctx context.Context, stage ext.Event, serviceConfig *ServiceConfig) (context.Context, telemetry.Span) {
+	spanCtx, span := telemetry.GetTracer().Start(ctx, events.GetServiceStageEventName(string(stage)))
+	span.SetAttributes(
+		fields.ServiceHostKey.String(string(serviceConfig.Host)),
+		fields.ServiceLanguageKey.String(string(serviceConfig.Language)),
+	)
+
+	return spanCtx, span
+}
+
 func syncProgress[T comparable, P comparable](task *async.TaskContextWithProgress[T, P], progressChannel <-chan P) {
 	for progress := range progressChannel {
 		task.SetProgress(progress)
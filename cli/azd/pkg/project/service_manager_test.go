@@ -3,6 +3,8 @@ package project
 import (
 	"context"
 	"errors"
+	"net/http"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/trivy"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockarmresources"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
@@ -48,7 +51,7 @@ func createServiceManager(mockContext *mocks.MockContext, env *environment.Envir
 			},
 		}))
 
-	return NewServiceManager(env, resourceManager, serviceLocator, alphaManager)
+	return NewServiceManager(env, resourceManager, azCli, serviceLocator, alphaManager)
 }
 
 func Test_ServiceManager_GetRequiredTools(t *testing.T) {
@@ -180,6 +183,34 @@ func Test_ServiceManager_Package(t *testing.T) {
 	require.True(t, raisedPostPackageEvent)
 }
 
+func Test_ServiceManager_Package_Sbom(t *testing.T) {
+	tempDir := t.TempDir()
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForServiceManager(mockContext)
+	trivyCli := &fakeTrivy{}
+	mockContext.Container.RegisterSingleton(func() trivy.Trivy {
+		return trivyCli
+	})
+
+	env := environment.EphemeralWithValues("test", nil)
+	sm := createServiceManager(mockContext, env)
+	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
+	serviceConfig.Project.Path = tempDir
+	serviceConfig.Project.Sbom = &SbomOptions{Format: "spdx"}
+
+	packageTask := sm.Package(*mockContext.Context, serviceConfig, nil)
+	logProgress(packageTask)
+
+	result, err := packageTask.Await()
+	require.NoError(t, err)
+
+	sbomPath := filepath.Join(tempDir, ".azure", "test", "sbom", "api.spdx.json")
+	require.Equal(t, sbomPath, result.Sbom)
+	require.Equal(t, []string{
+		strings.Join([]string{"fs", serviceConfig.Path(), "spdx-json", sbomPath}, " "),
+	}, trivyCli.sboms)
+}
+
 func Test_ServiceManager_Deploy(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
@@ -373,6 +404,14 @@ func setupMocksForServiceManager(mockContext *mocks.MockContext) {
 		return exec.NewRunResult(0, "", ""), nil
 	})
 
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Authorization/permissions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []any{map[string]any{"actions": []string{"*"}}},
+		})
+	})
+
 	mockarmresources.AddResourceGroupListMock(mockContext.HttpClient, "SUBSCRIPTION_ID", []*armresources.ResourceGroup{
 		{
 			ID:       convert.RefOf("ID"),
@@ -578,6 +617,10 @@ func (st *fakeServiceTarget) Endpoints(
 	return []string{"https://test.azurewebsites.net"}, nil
 }
 
+func (st *fakeServiceTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{}
+}
+
 type fakeTool struct {
 }
 
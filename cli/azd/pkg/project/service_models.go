@@ -10,6 +10,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 )
 
+// The argument of the service events holding the path of the package of the service, set once it's packaged
+const PackagePathEventArg = "packagePath"
+
 // ServiceLifecycleEventArgs are the event arguments available when
 // any service lifecycle event has been triggered
 type ServiceLifecycleEventArgs struct {
@@ -64,17 +67,26 @@ func (sbr *ServiceBuildResult) MarshalJSON() ([]byte, error) {
 type ServicePackageResult struct {
 	Build       *ServiceBuildResult `json:"build"`
 	PackagePath string              `json:"packagePath"`
-	Details     interface{}         `json:"details"`
+	// The path of the software bill of materials of the package, when the project generates SBOMs
+	Sbom    string      `json:"sbom,omitempty"`
+	Details interface{} `json:"details"`
 }
 
 // Supports rendering messages for UX items
 func (spr *ServicePackageResult) ToString(currentIndentation string) string {
+	var result string
 	uxItem, ok := spr.Details.(ux.UxItem)
 	if ok {
-		return uxItem.ToString(currentIndentation)
+		result = uxItem.ToString(currentIndentation)
+	} else {
+		result = fmt.Sprintf("%s- Package Output: %s", currentIndentation, output.WithLinkFormat(spr.PackagePath))
+	}
+
+	if spr.Sbom != "" {
+		result += fmt.Sprintf("\n%s- SBOM: %s", currentIndentation, output.WithLinkFormat(spr.Sbom))
 	}
 
-	return fmt.Sprintf("%s- Package Output: %s", currentIndentation, output.WithLinkFormat(spr.PackagePath))
+	return result
 }
 
 func (spr *ServicePackageResult) MarshalJSON() ([]byte, error) {
@@ -6,6 +6,7 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -24,16 +25,19 @@ const (
 	StaticWebAppTarget  ServiceTargetKind = "staticwebapp"
 	SpringAppTarget     ServiceTargetKind = "springapp"
 	AksTarget           ServiceTargetKind = "aks"
+	WebhookTarget       ServiceTargetKind = "webhook"
+	ApiManagementTarget ServiceTargetKind = "apim"
+	IotEdgeTarget       ServiceTargetKind = "iotedge"
+	DataPlatformTarget  ServiceTargetKind = "dataplatform"
 )
 
 func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
-	switch kind {
-	case AppServiceTarget,
-		ContainerAppTarget,
-		AzureFunctionTarget,
-		StaticWebAppTarget,
-		SpringAppTarget,
-		AksTarget:
+	if kind.IsBuiltIn() {
+		return kind, nil
+	}
+
+	// Other hosts are implemented by service target plugins
+	if _, err := findServiceTargetPlugin(kind); err == nil {
 		return kind, nil
 	}
 
@@ -70,6 +74,123 @@ type ServiceTarget interface {
 		serviceConfig *ServiceConfig,
 		targetResource *environment.TargetResource,
 	) ([]string, error)
+
+	// Capabilities gets the optional operations supported by the target.
+	Capabilities() ServiceTargetCapabilities
+}
+
+// ServiceTargetCapability is an optional operation of a service target.
+type ServiceTargetCapability string
+
+const (
+	// Deploying to a staging slot of the target resource, which is then swapped with production
+	SlotsCapability ServiceTargetCapability = "slots"
+	// Building the package on the target resource instead of locally
+	RemoteBuildCapability ServiceTargetCapability = "remoteBuild"
+	// Streaming the logs of the deployed service
+	LogStreamingCapability ServiceTargetCapability = "logStreaming"
+	// Rolling back the service to its previous deployment
+	RollbackCapability ServiceTargetCapability = "rollback"
+)
+
+// ServiceTargetCapabilities are the optional operations supported by a service target, so commands can fail fast when
+// the host of a service doesn't support them.
+type ServiceTargetCapabilities struct {
+	Slots        bool
+	RemoteBuild  bool
+	LogStreaming bool
+	Rollback     bool
+}
+
+// Supports returns true if the capability is supported, otherwise false.
+func (c ServiceTargetCapabilities) Supports(capability ServiceTargetCapability) bool {
+	switch capability {
+	case SlotsCapability:
+		return c.Slots
+	case RemoteBuildCapability:
+		return c.RemoteBuild
+	case LogStreamingCapability:
+		return c.LogStreaming
+	case RollbackCapability:
+		return c.Rollback
+	}
+
+	return false
+}
+
+// List returns the supported capabilities.
+func (c ServiceTargetCapabilities) List() []ServiceTargetCapability {
+	var capabilities []ServiceTargetCapability
+	for _, capability := range []ServiceTargetCapability{
+		SlotsCapability,
+		RemoteBuildCapability,
+		LogStreamingCapability,
+		RollbackCapability,
+	} {
+		if c.Supports(capability) {
+			capabilities = append(capabilities, capability)
+		}
+	}
+
+	return capabilities
+}
+
+// Description returns the name of the capability in messages, like "log streaming".
+func (capability ServiceTargetCapability) Description() string {
+	switch capability {
+	case SlotsCapability:
+		return "deployment slots"
+	case RemoteBuildCapability:
+		return "remote build"
+	case LogStreamingCapability:
+		return "log streaming"
+	case RollbackCapability:
+		return "rollback"
+	}
+
+	return string(capability)
+}
+
+// ErrCapabilityNotSupported is returned when the host of a service doesn't support an operation.
+var ErrCapabilityNotSupported = errors.New("capability not supported")
+
+// RequireCapability returns an error wrapping ErrCapabilityNotSupported when the service target of the service
+// doesn't support the capability.
+func RequireCapability(
+	serviceConfig *ServiceConfig,
+	serviceTarget ServiceTarget,
+	capability ServiceTargetCapability,
+) error {
+	if serviceTarget.Capabilities().Supports(capability) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"service '%s' is hosted on '%s', which does not support %s: %w",
+		serviceConfig.Name,
+		serviceConfig.Host,
+		capability.Description(),
+		ErrCapabilityNotSupported,
+	)
+}
+
+// PortMapping forwards a local port to a port of a deployed service.
+type PortMapping struct {
+	Local  int
+	Remote int
+}
+
+// PortForwarder is implemented by the service targets which can forward a local port to a deployed service, so
+// services only reachable from a private network can be called while debugging.
+type PortForwarder interface {
+	// PortForward forwards the ports until the context is cancelled. A zero remote port uses the default port of the
+	// target.
+	PortForward(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		ports PortMapping,
+	) error
 }
 
 // NewServiceDeployResult is a helper function to create a new ServiceDeployResult
@@ -120,6 +241,35 @@ func (st ServiceTargetKind) SupportsDelayedProvisioning() bool {
 	return st == AksTarget
 }
 
+// IsBuiltIn returns true if the service target kind is implemented by azd, and false if it is implemented by a
+// service target plugin.
+func (st ServiceTargetKind) IsBuiltIn() bool {
+	switch st {
+	case AppServiceTarget,
+		ContainerAppTarget,
+		AzureFunctionTarget,
+		StaticWebAppTarget,
+		SpringAppTarget,
+		AksTarget,
+		WebhookTarget,
+		ApiManagementTarget,
+		IotEdgeTarget,
+		DataPlatformTarget:
+		return true
+	}
+
+	return false
+}
+
+// RequiresAzureResource returns true if the services of the service target kind are deployed to an Azure resource
+// of the environment, otherwise false.
+//
+// As an example, WebhookTarget hands the package off to an external endpoint, and thus returns false. Service target
+// plugins may deploy to a tagged resource of the environment, but don't require one, and also return false.
+func (st ServiceTargetKind) RequiresAzureResource() bool {
+	return st.IsBuiltIn() && st != WebhookTarget
+}
+
 func checkResourceType(resource *environment.TargetResource, expectedResourceType infra.AzureResourceType) error {
 	if !strings.EqualFold(resource.ResourceType(), string(expectedResourceType)) {
 		return resourceTypeMismatchError(
@@ -2,6 +2,8 @@ package project
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -11,15 +13,21 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
+	"gopkg.in/yaml.v3"
 )
 
 const (
 	defaultDeploymentPath = "manifests"
+	// The port of the k8s service forwarded when no remote port is given
+	defaultAksRemotePort = 80
+	// The docker-registry secret holding the credentials of a registry other than Azure Container Registry
+	aksImagePullSecretName = "azd-registry"
 )
 
 // The AKS configuration options
@@ -34,6 +42,8 @@ type AksOptions struct {
 	Deployment AksDeploymentOptions `yaml:"deployment"`
 	// The services service configuration options
 	Service AksServiceOptions `yaml:"service"`
+	// The services workload identity options, when the pods of the service access Azure resources
+	WorkloadIdentity *AksWorkloadIdentityOptions `yaml:"workloadIdentity,omitempty"`
 }
 
 // The AKS ingress options
@@ -52,25 +62,38 @@ type AksServiceOptions struct {
 	Name string `yaml:"name"`
 }
 
+// The AKS workload identity options. The pods of the service act as a user-assigned managed identity provisioned
+// by the infrastructure, through a federated identity credential trusting the service account of the service.
+type AksWorkloadIdentityOptions struct {
+	// The name of the user-assigned managed identity, in the resource group of the cluster.
+	// Defaults to the SERVICE_<NAME>_IDENTITY_NAME environment value
+	IdentityName ExpandableString `yaml:"identityName"`
+	// The name of the k8s service account of the pods. Defaults to the service name
+	ServiceAccount string `yaml:"serviceAccount"`
+}
+
 type aksTarget struct {
-	env                    *environment.Environment
-	managedClustersService azcli.ManagedClustersService
-	kubectl                kubectl.KubectlCli
-	containerHelper        *ContainerHelper
+	env                      *environment.Environment
+	managedClustersService   azcli.ManagedClustersService
+	managedIdentitiesService azcli.ManagedIdentitiesService
+	kubectl                  kubectl.KubectlCli
+	containerHelper          *ContainerHelper
 }
 
 // Creates a new instance of the AKS service target
 func NewAksTarget(
 	env *environment.Environment,
 	managedClustersService azcli.ManagedClustersService,
+	managedIdentitiesService azcli.ManagedIdentitiesService,
 	kubectlCli kubectl.KubectlCli,
 	containerHelper *ContainerHelper,
 ) ServiceTarget {
 	return &aksTarget{
-		env:                    env,
-		managedClustersService: managedClustersService,
-		kubectl:                kubectlCli,
-		containerHelper:        containerHelper,
+		env:                      env,
+		managedClustersService:   managedClustersService,
+		managedIdentitiesService: managedIdentitiesService,
+		kubectl:                  kubectlCli,
+		containerHelper:          containerHelper,
 	}
 }
 
@@ -124,44 +147,8 @@ func (t *aksTarget) Deploy(
 			}
 
 			// Login to AKS cluster
-			clusterName, has := t.env.Values[environment.AksClusterEnvVarName]
-			if !has {
-				task.SetError(fmt.Errorf(
-					"could not determine AKS cluster, ensure %s is set as an output of your infrastructure",
-					environment.AksClusterEnvVarName,
-				))
-				return
-			}
-
-			log.Printf("getting AKS credentials for cluster '%s'\n", clusterName)
 			task.SetProgress(NewServiceProgress("Getting AKS credentials"))
-			clusterCreds, err := t.managedClustersService.GetAdminCredentials(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				clusterName,
-			)
-			if err != nil {
-				task.SetError(fmt.Errorf(
-					"failed retrieving cluster admin credentials. Ensure your cluster has been configured to support admin credentials, %w",
-					err,
-				))
-				return
-			}
-
-			if len(clusterCreds.Kubeconfigs) == 0 {
-				task.SetError(fmt.Errorf(
-					"cluster credentials is empty. Ensure your cluster has been configured to support admin credentials. , %w",
-					err,
-				))
-				return
-			}
-
-			// The kubeConfig that we care about will also be at position 0
-			// I don't know if there is a valid use case where this credential results would container multiple configs
-			task.SetProgress(NewServiceProgress("Configuring k8s config context"))
-			err = t.configureK8sContext(ctx, clusterName, clusterCreds.Kubeconfigs[0])
-			if err != nil {
+			if err := t.ensureClusterContext(ctx, targetResource); err != nil {
 				task.SetError(err)
 				return
 			}
@@ -170,7 +157,7 @@ func (t *aksTarget) Deploy(
 			containerDeployTask := t.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource)
 			syncProgress(task, containerDeployTask.Progress())
 
-			_, err = containerDeployTask.Await()
+			_, err := containerDeployTask.Await()
 			if err != nil {
 				task.SetError(err)
 				return
@@ -220,6 +207,31 @@ func (t *aksTarget) Deploy(
 				return
 			}
 
+			registry, err := t.containerHelper.Registry(ctx, serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			registryCredentials := registry.Credentials()
+			if registryCredentials != nil {
+				task.SetProgress(NewServiceProgress("Creating k8s image pull secret"))
+				if err := t.applyImagePullSecret(ctx, namespace, registryCredentials); err != nil {
+					task.SetError(fmt.Errorf("failed applying image pull secret: %w", err))
+					return
+				}
+			}
+
+			var workloadIdentity *aksWorkloadIdentity
+			if serviceConfig.K8s.WorkloadIdentity != nil {
+				task.SetProgress(NewServiceProgress("Configuring workload identity"))
+				workloadIdentity, err = t.configureWorkloadIdentity(ctx, serviceConfig, targetResource, namespace)
+				if err != nil {
+					task.SetError(fmt.Errorf("failed configuring workload identity: %w", err))
+					return
+				}
+			}
+
 			task.SetProgress(NewServiceProgress("Applying k8s manifests"))
 			t.kubectl.SetEnv(t.env.Values)
 			deploymentPath := serviceConfig.K8s.DeploymentPath
@@ -242,6 +254,20 @@ func (t *aksTarget) Deploy(
 				deploymentName = serviceConfig.Name
 			}
 
+			if workloadIdentity != nil {
+				if err := t.useWorkloadIdentity(ctx, namespace, deploymentName, workloadIdentity); err != nil {
+					task.SetError(fmt.Errorf("failed configuring workload identity: %w", err))
+					return
+				}
+			}
+
+			if registryCredentials != nil {
+				if err := t.useImagePullSecret(ctx, namespace, deploymentName); err != nil {
+					task.SetError(fmt.Errorf("failed configuring image pull secret: %w", err))
+					return
+				}
+			}
+
 			// It is not a requirement for a AZD deploy to contain a deployment object
 			// If we don't find any deployment within the namespace we will continue
 			task.SetProgress(NewServiceProgress("Verifying deployment"))
@@ -284,6 +310,14 @@ func (t *aksTarget) Deploy(
 		})
 }
 
+// Gets the capabilities of AKS, which rolls back by undoing the rollout of the deployments
+func (t *aksTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{
+		LogStreaming: true,
+		Rollback:     true,
+	}
+}
+
 // Gets the service endpoints for the AKS service target
 func (t *aksTarget) Endpoints(
 	ctx context.Context,
@@ -321,6 +355,78 @@ func (t *aksTarget) Endpoints(
 	return endpoints, nil
 }
 
+// Forwards a local port to the k8s service of the AKS service target
+func (t *aksTarget) PortForward(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	ports PortMapping,
+) error {
+	if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	if err := t.ensureClusterContext(ctx, targetResource); err != nil {
+		return err
+	}
+
+	serviceName := serviceConfig.K8s.Service.Name
+	if serviceName == "" {
+		serviceName = serviceConfig.Name
+	}
+
+	remotePort := ports.Remote
+	if remotePort == 0 {
+		remotePort = defaultAksRemotePort
+	}
+
+	return t.kubectl.PortForward(
+		ctx,
+		fmt.Sprintf("svc/%s", serviceName),
+		ports.Local,
+		remotePort,
+		&kubectl.KubeCliFlags{
+			Namespace: t.getK8sNamespace(serviceConfig),
+		},
+	)
+}
+
+// Retrieves the admin credentials of the AKS cluster and makes it the current k8s config context
+func (t *aksTarget) ensureClusterContext(ctx context.Context, targetResource *environment.TargetResource) error {
+	clusterName, has := t.env.Values[environment.AksClusterEnvVarName]
+	if !has {
+		return fmt.Errorf(
+			"could not determine AKS cluster, ensure %s is set as an output of your infrastructure",
+			environment.AksClusterEnvVarName,
+		)
+	}
+
+	log.Printf("getting AKS credentials for cluster '%s'\n", clusterName)
+	clusterCreds, err := t.managedClustersService.GetAdminCredentials(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		clusterName,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed retrieving cluster admin credentials. Ensure your cluster has been configured to support admin credentials, %w",
+			err,
+		)
+	}
+
+	if len(clusterCreds.Kubeconfigs) == 0 {
+		return fmt.Errorf(
+			"cluster credentials is empty. Ensure your cluster has been configured to support admin credentials. , %w",
+			err,
+		)
+	}
+
+	// The kubeConfig that we care about will also be at position 0
+	// I don't know if there is a valid use case where this credential results would container multiple configs
+	return t.configureK8sContext(ctx, clusterName, clusterCreds.Kubeconfigs[0])
+}
+
 func (t *aksTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -542,3 +648,313 @@ func (t *aksTarget) getK8sNamespace(serviceConfig *ServiceConfig) string {
 
 	return namespace
 }
+
+// The label and the annotations of the Azure workload identity webhook, which injects the token of the service account
+// in the pods using workload identity
+const (
+	workloadIdentityUseLabel           = "azure.workload.identity/use"
+	workloadIdentityClientIdAnnotation = "azure.workload.identity/client-id"
+	workloadIdentityTenantIdAnnotation = "azure.workload.identity/tenant-id"
+)
+
+// aksWorkloadIdentity is the workload identity configured for the pods of a service
+type aksWorkloadIdentity struct {
+	ServiceAccount string
+	Identity       *azsdk.ManagedIdentity
+}
+
+// Federates the managed identity of the service with the service account of its pods, trusting the OIDC issuer of the
+// cluster, and creates the service account annotated with the client ID of the identity.
+func (t *aksTarget) configureWorkloadIdentity(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	namespace string,
+) (*aksWorkloadIdentity, error) {
+	options := serviceConfig.K8s.WorkloadIdentity
+
+	identityName, err := options.IdentityName.Envsubst(t.env.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("expanding identity name: %w", err)
+	}
+
+	if identityName == "" {
+		identityName = t.env.GetServiceProperty(serviceConfig.Name, "IDENTITY_NAME")
+	}
+
+	if identityName == "" {
+		return nil, fmt.Errorf(
+			"could not determine the managed identity of service '%s', set k8s.workloadIdentity.identityName or "+
+				"output the name of the identity as SERVICE_%s_IDENTITY_NAME from your infrastructure",
+			serviceConfig.Name,
+			strings.ReplaceAll(strings.ToUpper(serviceConfig.Name), "-", "_"),
+		)
+	}
+
+	serviceAccount := options.ServiceAccount
+	if serviceAccount == "" {
+		serviceAccount = serviceConfig.Name
+	}
+
+	clusterName := t.env.Values[environment.AksClusterEnvVarName]
+	cluster, err := t.managedClustersService.Get(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		clusterName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster '%s': %w", clusterName, err)
+	}
+
+	if cluster.Properties == nil || cluster.Properties.OidcIssuerProfile == nil ||
+		cluster.Properties.OidcIssuerProfile.IssuerURL == nil || *cluster.Properties.OidcIssuerProfile.IssuerURL == "" {
+		return nil, fmt.Errorf(
+			"cluster '%s' has no OIDC issuer, enable the OIDC issuer and workload identity of the cluster in your "+
+				"infrastructure",
+			clusterName,
+		)
+	}
+
+	identity, err := t.managedIdentitiesService.Get(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		identityName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting managed identity '%s': %w", identityName, err)
+	}
+
+	log.Printf("federating managed identity '%s' with service account '%s/%s'", identityName, namespace, serviceAccount)
+	err = t.managedIdentitiesService.CreateOrUpdateFederatedCredential(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		identityName,
+		federatedCredentialName(clusterName, namespace, serviceAccount),
+		azsdk.FederatedCredential{
+			Issuer:    *cluster.Properties.OidcIssuerProfile.IssuerURL,
+			Subject:   fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+			Audiences: []string{azsdk.FederatedCredentialAudience},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("federating managed identity '%s': %w", identityName, err)
+	}
+
+	serviceAccountManifest, err := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ServiceAccount",
+		"metadata": map[string]any{
+			"name":      serviceAccount,
+			"namespace": namespace,
+			"annotations": map[string]string{
+				workloadIdentityClientIdAnnotation: identity.ClientId,
+				workloadIdentityTenantIdAnnotation: identity.TenantId,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := t.kubectl.ApplyWithInput(ctx, string(serviceAccountManifest), nil); err != nil {
+		return nil, fmt.Errorf("failed applying service account: %w", err)
+	}
+
+	return &aksWorkloadIdentity{
+		ServiceAccount: serviceAccount,
+		Identity:       identity,
+	}, nil
+}
+
+// Updates the pods of the deployments of the service to run with the service account of the workload identity, and
+// labels them for the workload identity webhook. Manifests already using the service account are left unchanged.
+func (t *aksTarget) useWorkloadIdentity(
+	ctx context.Context,
+	namespace string,
+	deploymentNameFilter string,
+	workloadIdentity *aksWorkloadIdentity,
+) error {
+	deployments, err := kubectl.GetResources[workloadIdentityDeployment](
+		ctx, t.kubectl, kubectl.ResourceTypeDeployment, &kubectl.KubeCliFlags{Namespace: namespace},
+	)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"metadata": map[string]any{
+					"labels": map[string]string{workloadIdentityUseLabel: "true"},
+				},
+				"spec": map[string]any{
+					"serviceAccountName": workloadIdentity.ServiceAccount,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, deployment := range deployments.Items {
+		if !strings.Contains(deployment.Metadata.Name, deploymentNameFilter) {
+			continue
+		}
+
+		template := deployment.Spec.Template
+		if template.Metadata.Labels[workloadIdentityUseLabel] == "true" &&
+			template.Spec.ServiceAccountName == workloadIdentity.ServiceAccount {
+			continue
+		}
+
+		_, err := t.kubectl.Exec(
+			ctx,
+			&kubectl.KubeCliFlags{Namespace: namespace},
+			"patch", "deployment", deployment.Metadata.Name, "--type", "merge", "-p", string(patch),
+		)
+		if err != nil {
+			return fmt.Errorf("failed patching deployment '%s': %w", deployment.Metadata.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// workloadIdentityDeployment is the pod template of a deployment read to check whether it uses workload identity
+type workloadIdentityDeployment struct {
+	Metadata kubectl.ResourceMetadata `json:"metadata"`
+	Spec     struct {
+		Template struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				ServiceAccountName string `json:"serviceAccountName"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+// applyImagePullSecret creates or updates the docker-registry secret the pods of the namespace pull the images pushed
+// to a registry other than Azure Container Registry with. The secret is applied from stdin so the password isn't
+// passed on the command line.
+func (t *aksTarget) applyImagePullSecret(
+	ctx context.Context,
+	namespace string,
+	credentials *RegistryCredentials,
+) error {
+	dockerConfig, err := json.Marshal(map[string]any{
+		"auths": map[string]any{
+			credentials.Server: map[string]string{
+				"username": credentials.Username,
+				"password": credentials.Password,
+				"auth": base64.StdEncoding.EncodeToString(
+					[]byte(credentials.Username + ":" + credentials.Password),
+				),
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	secretManifest, err := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "kubernetes.io/dockerconfigjson",
+		"metadata": map[string]any{
+			"name":      aksImagePullSecretName,
+			"namespace": namespace,
+		},
+		"data": map[string]string{
+			".dockerconfigjson": base64.StdEncoding.EncodeToString(dockerConfig),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = t.kubectl.ApplyWithInput(ctx, string(secretManifest), nil)
+	return err
+}
+
+// Adds the image pull secret to the pods of the deployments of the service. Manifests already referencing the secret
+// are left unchanged.
+func (t *aksTarget) useImagePullSecret(ctx context.Context, namespace string, deploymentNameFilter string) error {
+	deployments, err := kubectl.GetResources[imagePullSecretDeployment](
+		ctx, t.kubectl, kubectl.ResourceTypeDeployment, &kubectl.KubeCliFlags{Namespace: namespace},
+	)
+	if err != nil {
+		return err
+	}
+
+	// The image pull secrets of pods are merged by name, the other secrets of the deployment are kept
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{
+					"imagePullSecrets": []map[string]string{{"name": aksImagePullSecretName}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, deployment := range deployments.Items {
+		if !strings.Contains(deployment.Metadata.Name, deploymentNameFilter) {
+			continue
+		}
+
+		hasSecret := false
+		for _, secret := range deployment.Spec.Template.Spec.ImagePullSecrets {
+			hasSecret = hasSecret || secret.Name == aksImagePullSecretName
+		}
+
+		if hasSecret {
+			continue
+		}
+
+		_, err := t.kubectl.Exec(
+			ctx,
+			&kubectl.KubeCliFlags{Namespace: namespace},
+			"patch", "deployment", deployment.Metadata.Name, "--type", "strategic", "-p", string(patch),
+		)
+		if err != nil {
+			return fmt.Errorf("failed patching deployment '%s': %w", deployment.Metadata.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// imagePullSecretDeployment is the pod template of a deployment read to check whether it uses the image pull secret
+type imagePullSecretDeployment struct {
+	Metadata kubectl.ResourceMetadata `json:"metadata"`
+	Spec     struct {
+		Template struct {
+			Spec struct {
+				ImagePullSecrets []struct {
+					Name string `json:"name"`
+				} `json:"imagePullSecrets"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+// Federated identity credential names are at most 120 characters
+const maxFederatedCredentialNameLength = 120
+
+func federatedCredentialName(clusterName string, namespace string, serviceAccount string) string {
+	name := fmt.Sprintf("%s-%s-%s", clusterName, namespace, serviceAccount)
+	if len(name) > maxFederatedCredentialNameLength {
+		name = name[:maxFederatedCredentialNameLength]
+	}
+
+	return name
+}
@@ -2,8 +2,10 @@ package project
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -108,6 +110,198 @@ func Test_Package_Deploy_HappyPath(t *testing.T) {
 	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-test:azd-deploy-0", env.Values["SERVICE_API_IMAGE_NAME"])
 }
 
+func Test_Deploy_WorkloadIdentity(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	// Get cluster
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "Microsoft.ContainerService/managedClusters/AKS_CLUSTER")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		cluster := armcontainerservice.ManagedCluster{
+			Properties: &armcontainerservice.ManagedClusterProperties{
+				OidcIssuerProfile: &armcontainerservice.ManagedClusterOIDCIssuerProfile{
+					Enabled:   convert.RefOf(true),
+					IssuerURL: convert.RefOf("https://oidc.prod-aks.azure.com/ISSUER/"),
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, cluster)
+	})
+
+	// Get managed identity
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "Microsoft.ManagedIdentity/userAssignedIdentities/id-api")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"name": "id-api",
+			"properties": map[string]string{
+				"clientId": "CLIENT_ID",
+				"tenantId": "TENANT_ID",
+			},
+		})
+	})
+
+	// Create federated identity credential
+	var federatedCredential map[string]any
+	var federatedCredentialPath string
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.Contains(request.URL.Path, "federatedIdentityCredentials")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		federatedCredentialPath = request.URL.Path
+		if err := json.NewDecoder(request.Body).Decode(&federatedCredential); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, federatedCredential)
+	})
+
+	// Apply service account
+	var serviceAccount string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		content, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.RunResult{}, err
+		}
+
+		if strings.Contains(string(content), "ServiceAccount") {
+			serviceAccount = string(content)
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	// Patch deployment
+	var patchArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl patch deployment")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		patchArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.K8s.WorkloadIdentity = &AksWorkloadIdentityOptions{}
+	env := createEnv()
+	env.SetServiceProperty("api", "IDENTITY_NAME", "id-api")
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "CLUSTER_NAME", string(infra.AzureResourceTypeManagedCluster))
+	packageOutput := &ServicePackageResult{
+		Build: &ServiceBuildResult{BuildOutputPath: "IMAGE_ID"},
+		Details: &dockerPackageResult{
+			ImageTag: "IMAGE_TAG",
+		},
+	}
+
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+
+	require.True(t, strings.HasSuffix(federatedCredentialPath, "/federatedIdentityCredentials/AKS_CLUSTER-Test-App-api"))
+	require.Equal(t, map[string]any{
+		"properties": map[string]any{
+			"issuer":    "https://oidc.prod-aks.azure.com/ISSUER/",
+			"subject":   "system:serviceaccount:Test-App:api",
+			"audiences": []any{"api://AzureADTokenExchange"},
+		},
+	}, federatedCredential)
+
+	require.Contains(t, serviceAccount, "azure.workload.identity/client-id: CLIENT_ID")
+	require.Contains(t, serviceAccount, "namespace: Test-App")
+
+	require.Equal(t, "api-deployment", patchArgs[2])
+	require.Contains(t, patchArgs, `{"spec":{"template":{"metadata":{"labels":{"azure.workload.identity/use":"true"}},`+
+		`"spec":{"serviceAccountName":"api"}}}}`)
+}
+
+func Test_Deploy_ImagePullSecret(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	// Apply image pull secret
+	var pullSecret map[string]any
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl apply -f -")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		content, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.RunResult{}, err
+		}
+
+		if strings.Contains(string(content), "kubernetes.io/dockerconfigjson") {
+			if err := yaml.Unmarshal(content, &pullSecret); err != nil {
+				return exec.RunResult{}, err
+			}
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	// Patch deployment
+	var patchArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "kubectl patch deployment")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		patchArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.Docker.Registry = NewExpandableString("ghcr.io/contoso")
+	serviceConfig.Docker.Username = NewExpandableString("${REGISTRY_USERNAME}")
+	serviceConfig.Docker.Password = NewExpandableString("${REGISTRY_PASSWORD}")
+	env := createEnv()
+	env.Values["REGISTRY_USERNAME"] = "contoso-bot"
+	env.Values["REGISTRY_PASSWORD"] = "token"
+
+	serviceTarget := createAksServiceTarget(mockContext, serviceConfig, env)
+	err = setupK8sManifests(t, serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "CLUSTER_NAME", string(infra.AzureResourceTypeManagedCluster))
+	packageOutput := &ServicePackageResult{
+		Build: &ServiceBuildResult{BuildOutputPath: "IMAGE_ID"},
+		Details: &dockerPackageResult{
+			ImageTag: "IMAGE_TAG",
+		},
+	}
+
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageOutput, scope)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]any{"name": "azd-registry", "namespace": "Test-App"}, pullSecret["metadata"])
+	data := pullSecret["data"].(map[string]any)
+	dockerConfig, err := base64.StdEncoding.DecodeString(data[".dockerconfigjson"].(string))
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"auths":{"ghcr.io":{"username":"contoso-bot","password":"token","auth":"Y29udG9zby1ib3Q6dG9rZW4="}}}`,
+		string(dockerConfig),
+	)
+
+	require.Equal(t, "api-deployment", patchArgs[2])
+	require.Contains(t, patchArgs, `{"spec":{"template":{"spec":{"imagePullSecrets":[{"name":"azd-registry"}]}}}}`)
+}
+
 func Test_Deploy_No_Cluster_Name(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -489,12 +683,14 @@ func createAksServiceTarget(
 		})
 
 	managedClustersService := azcli.NewManagedClustersService(credentialProvider, mockContext.HttpClient)
+	managedIdentitiesService := azcli.NewManagedIdentitiesService(credentialProvider, mockContext.HttpClient)
 	containerRegistryService := azcli.NewContainerRegistryService(credentialProvider, mockContext.HttpClient, dockerCli)
-	containerHelper := NewContainerHelper(env, clock.NewMock(), containerRegistryService, dockerCli)
+	containerHelper := NewContainerHelper(env, clock.NewMock(), containerRegistryService, dockerCli, nil, nil)
 
 	return NewAksTarget(
 		env,
 		managedClustersService,
+		managedIdentitiesService,
 		kubeCtl,
 		containerHelper,
 	)
@@ -0,0 +1,373 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The API definition file looked up in the build output when none is configured
+const defaultApimDefinition = "openapi.json"
+
+// ApimOptions configures the API imported into the API Management service of apim services.
+type ApimOptions struct {
+	// The id of the API in the API Management service. Defaults to the name of the service.
+	ApiId string `yaml:"apiId"`
+	// The path of the API, appended to the gateway url. Defaults to the name of the service.
+	Path string `yaml:"path"`
+	// The display name of the API. Defaults to the title of the API definition.
+	DisplayName string `yaml:"displayName"`
+	// The OpenAPI definition, relative to the build output of the service, or to the service path.
+	// Defaults to openapi.json.
+	Definition string `yaml:"definition"`
+	// The url of the backend the gateway forwards the calls to, like ${API_BASE_URL}. Defaults to the servers of the
+	// API definition.
+	ServiceUrl ExpandableString `yaml:"serviceUrl"`
+	// The version of the API, like v1. Versioned APIs are added to a version set, which selects them by path segment.
+	Version string `yaml:"version"`
+	// The id of the version set of versioned APIs. Defaults to the id of the API.
+	VersionSet string `yaml:"versionSet"`
+}
+
+type apimTarget struct {
+	env         *environment.Environment
+	apimService azcli.ApiManagementService
+}
+
+// NewApimTarget creates the API Management service target, which imports the API definition of the service into
+// an API Management service. Each deployment creates a new revision of the API and makes it current.
+func NewApimTarget(env *environment.Environment, apimService azcli.ApiManagementService) ServiceTarget {
+	return &apimTarget{
+		env:         env,
+		apimService: apimService,
+	}
+}
+
+// Gets the required external tools for the API Management target
+func (t *apimTarget) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the API Management target
+func (t *apimTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// The API definition is deployed from the build output as is
+func (t *apimTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(packageOutput)
+		},
+	)
+}
+
+// Imports the API definition as a new revision of the API, and releases it
+func (t *apimTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+				task.SetError(fmt.Errorf("validating target resource: %w", err))
+				return
+			}
+
+			definitionPath, err := t.definitionPath(serviceConfig, packageOutput)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			definition, err := os.ReadFile(definitionPath)
+			if err != nil {
+				task.SetError(fmt.Errorf("reading api definition: %w", err))
+				return
+			}
+
+			serviceUrl, err := serviceConfig.Apim.ServiceUrl.Envsubst(t.env.Getenv)
+			if err != nil {
+				task.SetError(fmt.Errorf("expanding apim.serviceUrl: %w", err))
+				return
+			}
+
+			options := serviceConfig.Apim
+			api := azcli.ApimApi{
+				Path:        apimPath(serviceConfig),
+				DisplayName: options.DisplayName,
+				ServiceUrl:  serviceUrl,
+				Format:      apimDefinitionFormat(definitionPath, definition),
+				Definition:  string(definition),
+			}
+			apiId := apimApiId(serviceConfig)
+
+			if options.Version != "" {
+				versionSetId := options.VersionSet
+				if versionSetId == "" {
+					versionSetId = apiId
+				}
+
+				displayName := options.DisplayName
+				if displayName == "" {
+					displayName = versionSetId
+				}
+
+				task.SetProgress(NewServiceProgress("Updating API version set"))
+				api.VersionSetId, err = t.apimService.EnsureApiVersionSet(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					versionSetId,
+					displayName,
+				)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+
+				api.Version = options.Version
+				apiId = fmt.Sprintf("%s-%s", apiId, options.Version)
+			}
+
+			revision, err := t.importApi(ctx, task, targetResource, apiId, api)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Fetching endpoints for API Management service"))
+			endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			details, _ := json.Marshal(map[string]string{
+				"apiId":    apiId,
+				"revision": revision,
+			})
+
+			sdr := NewServiceDeployResult(
+				azure.ApimApiRID(
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					apiId,
+				),
+				ApiManagementTarget,
+				string(details),
+				endpoints,
+			)
+			sdr.Package = packageOutput
+
+			task.SetResult(sdr)
+		},
+	)
+}
+
+// importApi creates the API, or a new revision of the API which is released as the current revision, and returns the
+// imported revision.
+func (t *apimTarget) importApi(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	targetResource *environment.TargetResource,
+	apiId string,
+	api azcli.ApimApi,
+) (string, error) {
+	latestRevision, exists, err := t.apimService.GetLatestApiRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		apiId,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if !exists {
+		task.SetProgress(NewServiceProgress("Importing API definition"))
+		api.Revision = "1"
+		if err := t.apimService.ImportApi(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			apiId,
+			api,
+		); err != nil {
+			return "", err
+		}
+
+		return api.Revision, nil
+	}
+
+	// The next revision follows the highest revision rather than the current one, which is lower after a rollback
+	latest, err := strconv.Atoi(latestRevision)
+	if err != nil {
+		return "", fmt.Errorf("api '%s' has an unexpected revision '%s'", apiId, latestRevision)
+	}
+
+	api.Revision = strconv.Itoa(latest + 1)
+	api.SourceApiId = fmt.Sprintf("/apis/%s", apiId)
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Importing API definition as revision %s", api.Revision)))
+	if err := t.apimService.ImportApi(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		fmt.Sprintf("%s;rev=%s", apiId, api.Revision),
+		api,
+	); err != nil {
+		return "", err
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Releasing revision %s", api.Revision)))
+	if err := t.apimService.ReleaseApiRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		apiId,
+		api.Revision,
+		fmt.Sprintf("Deployed by azd to environment %s", t.env.GetEnvName()),
+	); err != nil {
+		return "", err
+	}
+
+	return api.Revision, nil
+}
+
+// Gets the capabilities of API Management, which rolls back by making a previous revision of the API current
+func (t *apimTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{
+		Rollback: true,
+	}
+}
+
+// Gets the url of the API on the gateway of the API Management service
+func (t *apimTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	gatewayUrl, err := t.apimService.GetGatewayUrl(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s", strings.TrimSuffix(gatewayUrl, "/"), apimPath(serviceConfig))
+	if serviceConfig.Apim.Version != "" {
+		endpoint = fmt.Sprintf("%s/%s", endpoint, serviceConfig.Apim.Version)
+	}
+
+	return []string{endpoint}, nil
+}
+
+// definitionPath finds the API definition in the build output, then in the service path.
+func (t *apimTarget) definitionPath(serviceConfig *ServiceConfig, packageOutput *ServicePackageResult) (string, error) {
+	definition := serviceConfig.Apim.Definition
+	if definition == "" {
+		definition = defaultApimDefinition
+	}
+
+	if filepath.IsAbs(definition) {
+		return definition, nil
+	}
+
+	candidates := []string{}
+	if packageOutput != nil && packageOutput.PackagePath != "" {
+		if info, err := os.Stat(packageOutput.PackagePath); err == nil && info.IsDir() {
+			candidates = append(candidates, filepath.Join(packageOutput.PackagePath, definition))
+		}
+	}
+	candidates = append(candidates, filepath.Join(serviceConfig.Path(), definition))
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("reading api definition: %w", err)
+		}
+	}
+
+	return "", fmt.Errorf(
+		"api definition '%s' wasn't found in the build output or the path of service %s. Set apim.definition",
+		definition,
+		serviceConfig.Name,
+	)
+}
+
+func (t *apimTarget) validateTargetResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	if err := checkResourceType(targetResource, infra.AzureResourceTypeApim); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func apimApiId(serviceConfig *ServiceConfig) string {
+	if serviceConfig.Apim.ApiId != "" {
+		return serviceConfig.Apim.ApiId
+	}
+
+	return serviceConfig.Name
+}
+
+func apimPath(serviceConfig *ServiceConfig) string {
+	if serviceConfig.Apim.Path != "" {
+		return strings.Trim(serviceConfig.Apim.Path, "/")
+	}
+
+	return serviceConfig.Name
+}
+
+// apimDefinitionFormat returns the import format of an API definition, OpenAPI 3 in JSON or YAML, or Swagger 2.0.
+func apimDefinitionFormat(path string, definition []byte) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".yaml" || ext == ".yml" {
+		return "openapi"
+	}
+
+	var document struct {
+		Swagger string `json:"swagger"`
+	}
+	if err := json.Unmarshal(definition, &document); err == nil && document.Swagger != "" {
+		return "swagger-json"
+	}
+
+	return "openapi+json"
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenApiDefinition = `{"openapi": "3.0.1", "info": {"title": "Todo API", "version": "1.0"}, "paths": {}}`
+
+func TestApimTargetDeploy(t *testing.T) {
+	env := environment.EphemeralWithValues("dev", map[string]string{
+		"API_BASE_URL": "https://app-api.azurewebsites.net",
+	})
+	targetResource := environment.NewTargetResource("SUB", "RG", "apim-test", string(infra.AzureResourceTypeApim))
+
+	packagePath := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(packagePath, "openapi.json"), []byte(testOpenApiDefinition), osutil.PermissionFile))
+
+	serviceConfig := createTestServiceConfig("./src/api", ApiManagementTarget, ServiceLanguageJavaScript)
+	serviceConfig.Apim = ApimOptions{
+		Path:       "todo",
+		ServiceUrl: NewExpandableString("${API_BASE_URL}"),
+		Version:    "v1",
+	}
+
+	mockContext := mocks.NewMockContext(context.Background())
+	apimService := &fakeApimService{current: map[string]string{}, latest: map[string]int{}}
+	serviceTarget := NewApimTarget(env, apimService)
+
+	deploy := func() *ServiceDeployResult {
+		deployTask := serviceTarget.Deploy(
+			*mockContext.Context,
+			serviceConfig,
+			&ServicePackageResult{PackagePath: packagePath},
+			targetResource,
+		)
+		logProgress(deployTask)
+		deployResult, err := deployTask.Await()
+		require.NoError(t, err)
+
+		return deployResult
+	}
+
+	// The first deployment creates the API in the version set
+	deployResult := deploy()
+	require.Equal(t, ApiManagementTarget, deployResult.Kind)
+	require.Equal(t, []string{"https://apim-test.azure-api.net/todo/v1"}, deployResult.Endpoints)
+	require.Equal(t, []string{"api-v1"}, apimService.imported)
+	require.Equal(t, azcli.ApimApi{
+		Path:         "todo",
+		ServiceUrl:   "https://app-api.azurewebsites.net",
+		Format:       "openapi+json",
+		Definition:   testOpenApiDefinition,
+		Revision:     "1",
+		Version:      "v1",
+		VersionSetId: "/versionSets/api",
+	}, apimService.lastImport)
+	require.Empty(t, apimService.released)
+
+	// The next deployments create and release new revisions
+	deploy()
+	require.Equal(t, []string{"api-v1", "api-v1;rev=2"}, apimService.imported)
+	require.Equal(t, "/apis/api-v1", apimService.lastImport.SourceApiId)
+	require.Equal(t, []string{"api-v1;rev=2"}, apimService.released)
+	require.Equal(t, "2", apimService.current["api-v1"])
+
+	// After a rollback to the first revision, the next revision follows the highest revision
+	apimService.current["api-v1"] = "1"
+	deploy()
+	require.Equal(t, []string{"api-v1", "api-v1;rev=2", "api-v1;rev=3"}, apimService.imported)
+	require.Equal(t, "3", apimService.current["api-v1"])
+
+	t.Run("MissingDefinition", func(t *testing.T) {
+		serviceConfig := createTestServiceConfig("./src/api", ApiManagementTarget, ServiceLanguageJavaScript)
+		serviceConfig.Apim = ApimOptions{Definition: "swagger.yaml"}
+
+		deployTask := serviceTarget.Deploy(
+			*mockContext.Context,
+			serviceConfig,
+			&ServicePackageResult{PackagePath: packagePath},
+			targetResource,
+		)
+		logProgress(deployTask)
+		_, err := deployTask.Await()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "api definition 'swagger.yaml' wasn't found")
+	})
+}
+
+func TestApimDefinitionFormat(t *testing.T) {
+	require.Equal(t, "openapi", apimDefinitionFormat("openapi.yaml", []byte("openapi: 3.0.1")))
+	require.Equal(t, "openapi+json", apimDefinitionFormat("openapi.json", []byte(testOpenApiDefinition)))
+	require.Equal(t, "swagger-json", apimDefinitionFormat("swagger.json", []byte(`{"swagger": "2.0"}`)))
+}
+
+type fakeApimService struct {
+	current    map[string]string
+	latest     map[string]int
+	imported   []string
+	released   []string
+	lastImport azcli.ApimApi
+}
+
+func (s *fakeApimService) GetGatewayUrl(_ context.Context, _, _, serviceName string) (string, error) {
+	return fmt.Sprintf("https://%s.azure-api.net", serviceName), nil
+}
+
+func (s *fakeApimService) GetLatestApiRevision(_ context.Context, _, _, _, apiId string) (string, bool, error) {
+	latest, has := s.latest[apiId]
+	return strconv.Itoa(latest), has, nil
+}
+
+func (s *fakeApimService) EnsureApiVersionSet(_ context.Context, _, _, _, versionSetId, _ string) (string, error) {
+	return "/versionSets/" + versionSetId, nil
+}
+
+func (s *fakeApimService) ImportApi(_ context.Context, _, _, _, apiId string, api azcli.ApimApi) error {
+	s.imported = append(s.imported, apiId)
+	s.lastImport = api
+
+	name, _, _ := strings.Cut(apiId, ";")
+	revision, err := strconv.Atoi(api.Revision)
+	if err != nil {
+		return err
+	}
+
+	if revision > s.latest[name] {
+		s.latest[name] = revision
+	}
+	if revision == 1 {
+		s.current[name] = api.Revision
+	}
+
+	return nil
+}
+
+func (s *fakeApimService) ReleaseApiRevision(_ context.Context, _, _, _, apiId, revision, _ string) error {
+	s.released = append(s.released, fmt.Sprintf("%s;rev=%s", apiId, revision))
+	s.current[apiId] = revision
+	return nil
+}
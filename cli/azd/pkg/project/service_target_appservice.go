@@ -12,25 +12,63 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
+// AppServiceOptions configures the site of the App Service, applied after each deployment when it differs from the
+// configuration of the App Service. Settings not set are left unchanged, like the settings set by the infrastructure.
+type AppServiceOptions struct {
+	// The runtime stack of the Linux App Service, like PYTHON|3.11 or NODE|20-lts.
+	RuntimeStack string `yaml:"runtimeStack,omitempty"`
+	// The command starting the application, like gunicorn --bind=0.0.0.0 app:app.
+	StartupCommand string `yaml:"startupCommand,omitempty"`
+	// The path probed by the health check, like /health.
+	HealthCheckPath string `yaml:"healthCheckPath,omitempty"`
+	// Keeps the application loaded when idle.
+	AlwaysOn *bool `yaml:"alwaysOn,omitempty"`
+}
+
+// siteConfig returns the site configuration of the options and the CORS policy of the service, or nil when no setting
+// is set.
+func (o *AppServiceOptions) siteConfig(cors *CorsOptions) *azcli.AzCliAppServiceSiteConfig {
+	config := azcli.AzCliAppServiceSiteConfig{AlwaysOn: o.AlwaysOn, Cors: cors.settings()}
+	if o.RuntimeStack != "" {
+		config.LinuxFxVersion = &o.RuntimeStack
+	}
+	if o.StartupCommand != "" {
+		config.AppCommandLine = &o.StartupCommand
+	}
+	if o.HealthCheckPath != "" {
+		config.HealthCheckPath = &o.HealthCheckPath
+	}
+
+	if config == (azcli.AzCliAppServiceSiteConfig{}) {
+		return nil
+	}
+
+	return &config
+}
+
 type appServiceTarget struct {
-	env *environment.Environment
-	cli azcli.AzCli
+	env           *environment.Environment
+	cli           azcli.AzCli
+	commandRunner exec.CommandRunner
 }
 
 // NewAppServiceTarget creates a new instance of the AppServiceTarget
 func NewAppServiceTarget(
 	env *environment.Environment,
 	azCli azcli.AzCli,
+	commandRunner exec.CommandRunner,
 ) ServiceTarget {
 
 	return &appServiceTarget{
-		env: env,
-		cli: azCli,
+		env:           env,
+		cli:           azCli,
+		commandRunner: commandRunner,
 	}
 }
 
@@ -53,7 +91,7 @@ func (st *appServiceTarget) Package(
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
 			task.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
-			zipFilePath, err := createDeployableZip(serviceConfig.Name, packageOutput.PackagePath)
+			zipFilePath, err := createDeployableZip(ctx, serviceConfig.Name, packageOutput.PackagePath)
 			if err != nil {
 				task.SetError(err)
 				return
@@ -87,22 +125,50 @@ func (st *appServiceTarget) Deploy(
 				return
 			}
 
-			defer os.Remove(packageOutput.PackagePath)
 			defer zipFile.Close()
 
 			task.SetProgress(NewServiceProgress("Uploading deployment package"))
+			uploadFile, err := withUploadProgress(zipFile, task)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			res, err := st.cli.DeployAppServiceZip(
 				ctx,
 				targetResource.SubscriptionId(),
 				targetResource.ResourceGroupName(),
 				targetResource.ResourceName(),
-				zipFile,
+				uploadFile,
 			)
 			if err != nil {
 				task.SetError(fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err))
 				return
 			}
 
+			if siteConfig := serviceConfig.AppService.siteConfig(serviceConfig.Cors); siteConfig != nil {
+				task.SetProgress(NewServiceProgress("Updating app service configuration"))
+				_, err := st.cli.UpdateAppServiceSiteConfig(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					*siteConfig,
+				)
+				if err != nil {
+					task.SetError(fmt.Errorf("configuring service %s: %w", serviceConfig.Name, err))
+					return
+				}
+			}
+
+			if len(serviceConfig.CustomDomains) > 0 {
+				task.SetProgress(NewServiceProgress("Configuring custom domains"))
+				if err := bindCustomDomains(ctx, st.cli, st.env, serviceConfig, targetResource); err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
 			task.SetProgress(NewServiceProgress("Fetching endpoints for app service"))
 			endpoints, err := st.Endpoints(ctx, serviceConfig, targetResource)
 			if err != nil {
@@ -127,6 +193,15 @@ func (st *appServiceTarget) Deploy(
 	)
 }
 
+// Gets the capabilities of App Service, which builds the package remotely when SCM_DO_BUILD_DURING_DEPLOYMENT is set
+func (st *appServiceTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{
+		Slots:        true,
+		RemoteBuild:  true,
+		LogStreaming: true,
+	}
+}
+
 // Gets the exposed endpoints for the App Service
 func (st *appServiceTarget) Endpoints(
 	ctx context.Context,
@@ -151,6 +226,20 @@ func (st *appServiceTarget) Endpoints(
 	return endpoints, nil
 }
 
+// Opens a tunnel from a local port to the SSH port of the App Service container
+func (st *appServiceTarget) PortForward(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	ports PortMapping,
+) error {
+	if err := st.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return createWebAppRemoteConnection(ctx, st.commandRunner, targetResource, ports)
+}
+
 func (st *appServiceTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
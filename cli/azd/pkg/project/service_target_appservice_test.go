@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
@@ -57,3 +58,41 @@ func TestNewAppServiceTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestAppServicePortForward(t *testing.T) {
+	t.Parallel()
+
+	targetResource := environment.NewTargetResource("SUB_ID", "RG_ID", "res", string(infra.AzureResourceTypeWebSite))
+
+	t.Run("TunnelsToSshPort", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "az webapp create-remote-connection")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		serviceTarget := &appServiceTarget{commandRunner: mockContext.CommandRunner}
+		err := serviceTarget.PortForward(*mockContext.Context, &ServiceConfig{}, targetResource, PortMapping{Local: 8080})
+		require.NoError(t, err)
+		require.True(t, runArgs.Interactive)
+		require.Equal(t, []string{
+			"webapp", "create-remote-connection",
+			"--subscription", "SUB_ID",
+			"--resource-group", "RG_ID",
+			"--name", "res",
+			"--port", "8080",
+		}, runArgs.Args)
+	})
+
+	t.Run("UnsupportedRemotePort", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		serviceTarget := &appServiceTarget{commandRunner: mockContext.CommandRunner}
+		err := serviceTarget.PortForward(
+			*mockContext.Context, &ServiceConfig{}, targetResource, PortMapping{Local: 8080, Remote: 80})
+		require.Error(t, err)
+	})
+}
@@ -84,6 +84,21 @@ func (at *containerAppTarget) Deploy(
 				return
 			}
 
+			registry, err := at.containerHelper.Registry(ctx, serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			var registryCredentials *containerapps.RegistryCredentials
+			if credentials := registry.Credentials(); credentials != nil {
+				registryCredentials = &containerapps.RegistryCredentials{
+					Server:   credentials.Server,
+					Username: credentials.Username,
+					Password: credentials.Password,
+				}
+			}
+
 			imageName := at.env.GetServiceProperty(serviceConfig.Name, "IMAGE_NAME")
 			task.SetProgress(NewServiceProgress("Updating container app revision"))
 			err = at.containerAppService.AddRevision(
@@ -92,6 +107,7 @@ func (at *containerAppTarget) Deploy(
 				targetResource.ResourceGroupName(),
 				targetResource.ResourceName(),
 				imageName,
+				registryCredentials,
 			)
 			if err != nil {
 				task.SetError(fmt.Errorf("updating container app service: %w", err))
@@ -119,6 +135,14 @@ func (at *containerAppTarget) Deploy(
 	)
 }
 
+// Gets the capabilities of the Container App, which rolls back by activating a previous revision
+func (at *containerAppTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{
+		LogStreaming: true,
+		Rollback:     true,
+	}
+}
+
 // Gets endpoint for the container app service
 func (at *containerAppTarget) Endpoints(
 	ctx context.Context,
@@ -0,0 +1,489 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// The folders of the artifacts of dataplatform services, relative to the service path, when none are configured
+const (
+	defaultDataPlatformNotebooks = "notebooks"
+	defaultDataPlatformJobs      = "jobs"
+	defaultDataPlatformPipelines = "pipelines"
+)
+
+// The languages of Databricks source notebooks, by file extension
+var databricksNotebookLanguages = map[string]string{
+	".py":    "PYTHON",
+	".scala": "SCALA",
+	".sql":   "SQL",
+	".r":     "R",
+}
+
+// DataPlatformOptions configures the artifacts deployed to the Databricks or Synapse workspace of dataplatform
+// services.
+type DataPlatformOptions struct {
+	// The folder of the notebooks, relative to the service path. Defaults to notebooks.
+	Notebooks string `yaml:"notebooks"`
+	// The folder of the job definitions, Databricks jobs or Synapse Spark job definitions, relative to the service
+	// path. Defaults to jobs.
+	Jobs string `yaml:"jobs"`
+	// The folder of the Synapse pipelines, relative to the service path. Defaults to pipelines.
+	Pipelines string `yaml:"pipelines"`
+	// The Databricks workspace folder the notebooks are imported into. Defaults to /Shared/<service name>.
+	WorkspacePath string `yaml:"workspacePath"`
+}
+
+// The artifacts deployed to the workspace
+type dataPlatformDeployResult struct {
+	Notebooks []string `json:"notebooks"`
+	Jobs      []string `json:"jobs"`
+	Pipelines []string `json:"pipelines"`
+}
+
+type dataPlatformTarget struct {
+	env                 *environment.Environment
+	dataPlatformService azcli.DataPlatformService
+}
+
+// NewDataPlatformTarget creates the data platform service target, which deploys the notebooks, jobs and pipelines of
+// the service to the Azure Databricks or Synapse workspace of the environment.
+func NewDataPlatformTarget(env *environment.Environment, dataPlatformService azcli.DataPlatformService) ServiceTarget {
+	return &dataPlatformTarget{
+		env:                 env,
+		dataPlatformService: dataPlatformService,
+	}
+}
+
+// Gets the required external tools for the data platform target
+func (t *dataPlatformTarget) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the data platform target
+func (t *dataPlatformTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// The artifacts are deployed from the service path as is
+func (t *dataPlatformTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			task.SetResult(packageOutput)
+		},
+	)
+}
+
+// Deploys the notebooks, jobs and pipelines of the service to the workspace
+func (t *dataPlatformTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+				task.SetError(fmt.Errorf("validating target resource: %w", err))
+				return
+			}
+
+			var result *dataPlatformDeployResult
+			var resourceId string
+			var err error
+
+			if isSynapseWorkspace(targetResource) {
+				result, err = t.deploySynapse(ctx, task, serviceConfig, targetResource)
+				resourceId = azure.SynapseWorkspaceRID(
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+				)
+			} else {
+				result, err = t.deployDatabricks(ctx, task, serviceConfig, targetResource)
+				resourceId = azure.DatabricksWorkspaceRID(
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+				)
+			}
+
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			if len(result.Notebooks) == 0 && len(result.Jobs) == 0 && len(result.Pipelines) == 0 {
+				task.SetError(fmt.Errorf(
+					"service %s has no notebooks, jobs or pipelines to deploy, add them to the %s, %s or %s folders",
+					serviceConfig.Name,
+					dataPlatformFolder(serviceConfig.DataPlatform.Notebooks, defaultDataPlatformNotebooks),
+					dataPlatformFolder(serviceConfig.DataPlatform.Jobs, defaultDataPlatformJobs),
+					dataPlatformFolder(serviceConfig.DataPlatform.Pipelines, defaultDataPlatformPipelines),
+				))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Fetching endpoints for workspace"))
+			endpoints, err := t.Endpoints(ctx, serviceConfig, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			details, _ := json.Marshal(result)
+			sdr := NewServiceDeployResult(resourceId, DataPlatformTarget, string(details), endpoints)
+			sdr.Package = packageOutput
+
+			task.SetResult(sdr)
+		},
+	)
+}
+
+// deployDatabricks imports the notebooks into the workspace folder of the service, and creates or updates the jobs.
+func (t *dataPlatformTarget) deployDatabricks(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (*dataPlatformDeployResult, error) {
+	options := serviceConfig.DataPlatform
+	result := &dataPlatformDeployResult{Notebooks: []string{}, Jobs: []string{}, Pipelines: []string{}}
+
+	if options.Pipelines != "" {
+		return nil, fmt.Errorf(
+			"pipelines are only deployed to Synapse workspaces, remove dataPlatform.pipelines from service %s",
+			serviceConfig.Name,
+		)
+	}
+
+	workspacePath := options.WorkspacePath
+	if workspacePath == "" {
+		workspacePath = path.Join("/Shared", serviceConfig.Name)
+	}
+
+	notebooksPath := filepath.Join(serviceConfig.Path(), dataPlatformFolder(options.Notebooks, defaultDataPlatformNotebooks))
+	err := walkDataPlatformFolder(notebooksPath, func(filePath string, relativePath string) error {
+		ext := strings.ToLower(filepath.Ext(filePath))
+		notebook := &azsdk.DatabricksNotebook{
+			Path: path.Join(workspacePath, strings.TrimSuffix(filepath.ToSlash(relativePath), filepath.Ext(filePath))),
+		}
+
+		if ext == ".ipynb" {
+			notebook.Format = "JUPYTER"
+		} else if language, has := databricksNotebookLanguages[ext]; has {
+			notebook.Format = "SOURCE"
+			notebook.Language = language
+		} else {
+			return nil
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("reading notebook: %w", err)
+		}
+		notebook.Content = content
+
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Importing notebook %s", notebook.Path)))
+		if err := t.dataPlatformService.ImportDatabricksNotebook(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			notebook,
+		); err != nil {
+			return err
+		}
+
+		result.Notebooks = append(result.Notebooks, notebook.Path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobsPath := filepath.Join(serviceConfig.Path(), dataPlatformFolder(options.Jobs, defaultDataPlatformJobs))
+	err = walkDataPlatformFolder(jobsPath, func(filePath string, relativePath string) error {
+		if !strings.EqualFold(filepath.Ext(filePath), ".json") {
+			return nil
+		}
+
+		content, err := t.readJsonArtifact(filePath)
+		if err != nil {
+			return err
+		}
+
+		var settings map[string]any
+		if err := json.Unmarshal(content, &settings); err != nil {
+			return fmt.Errorf("parsing job '%s': %w", relativePath, err)
+		}
+
+		if _, has := settings["name"]; !has {
+			settings["name"] = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		}
+
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Updating job %s", settings["name"])))
+		if _, err := t.dataPlatformService.UpsertDatabricksJob(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			settings,
+		); err != nil {
+			return err
+		}
+
+		result.Jobs = append(result.Jobs, fmt.Sprint(settings["name"]))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// deploySynapse creates or updates the notebooks, Spark job definitions and pipelines of the workspace.
+func (t *dataPlatformTarget) deploySynapse(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (*dataPlatformDeployResult, error) {
+	options := serviceConfig.DataPlatform
+	result := &dataPlatformDeployResult{Notebooks: []string{}, Jobs: []string{}, Pipelines: []string{}}
+
+	folders := []struct {
+		folder   string
+		kind     string
+		deployed *[]string
+	}{
+		{dataPlatformFolder(options.Notebooks, defaultDataPlatformNotebooks), azsdk.SynapseNotebooks, &result.Notebooks},
+		{dataPlatformFolder(options.Jobs, defaultDataPlatformJobs), azsdk.SynapseSparkJobDefinitions, &result.Jobs},
+		{dataPlatformFolder(options.Pipelines, defaultDataPlatformPipelines), azsdk.SynapsePipelines, &result.Pipelines},
+	}
+
+	for _, folder := range folders {
+		err := walkDataPlatformFolder(
+			filepath.Join(serviceConfig.Path(), folder.folder),
+			func(filePath string, relativePath string) error {
+				var name string
+				var properties json.RawMessage
+
+				switch strings.ToLower(filepath.Ext(filePath)) {
+				case ".ipynb":
+					// Jupyter notebooks are the properties of Synapse notebooks
+					if folder.kind != azsdk.SynapseNotebooks {
+						return nil
+					}
+
+					content, err := os.ReadFile(filePath)
+					if err != nil {
+						return fmt.Errorf("reading notebook: %w", err)
+					}
+
+					name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+					properties = content
+				case ".json":
+					content, err := t.readJsonArtifact(filePath)
+					if err != nil {
+						return err
+					}
+
+					name, properties, err = parseSynapseArtifact(filePath, content)
+					if err != nil {
+						return err
+					}
+				default:
+					return nil
+				}
+
+				task.SetProgress(NewServiceProgress(fmt.Sprintf("Deploying %s %s", folder.kind, name)))
+				if err := t.dataPlatformService.PutSynapseArtifact(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					folder.kind,
+					name,
+					properties,
+				); err != nil {
+					return err
+				}
+
+				*folder.deployed = append(*folder.deployed, name)
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// jsonArtifactVariableRegex matches the ${NAME} references to environment values in job and pipeline definitions.
+var jsonArtifactVariableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// readJsonArtifact reads a job or pipeline definition, substituting the environment values it references, like
+// ${AZURE_STORAGE_ACCOUNT_NAME}. Definitions use $ for their own purposes, like Spark configurations and pipeline
+// expressions, so only ${NAME} references to values defined in the environment are substituted and anything else is
+// kept as is. Values are escaped for the JSON strings they are substituted in.
+func (t *dataPlatformTarget) readJsonArtifact(filePath string) ([]byte, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading '%s': %w", filePath, err)
+	}
+
+	return jsonArtifactVariableRegex.ReplaceAllFunc(content, func(match []byte) []byte {
+		name := jsonArtifactVariableRegex.FindSubmatch(match)[1]
+		value, has := t.env.LookupEnv(string(name))
+		if !has {
+			return match
+		}
+
+		escaped, err := json.Marshal(value)
+		if err != nil {
+			return match
+		}
+
+		return escaped[1 : len(escaped)-1]
+	}), nil
+}
+
+// Data platform services have none of the optional capabilities
+func (t *dataPlatformTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{}
+}
+
+// Gets the url of the workspace
+func (t *dataPlatformTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	var endpoint string
+	var err error
+
+	if isSynapseWorkspace(targetResource) {
+		endpoint, err = t.dataPlatformService.GetSynapseWorkspaceUrl(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+	} else {
+		endpoint, err = t.dataPlatformService.GetDatabricksWorkspaceUrl(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching service properties: %w", err)
+	}
+
+	return []string{endpoint}, nil
+}
+
+func (t *dataPlatformTarget) validateTargetResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	if isSynapseWorkspace(targetResource) {
+		return nil
+	}
+
+	if err := checkResourceType(targetResource, infra.AzureResourceTypeDatabricksWorkspace); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func isSynapseWorkspace(targetResource *environment.TargetResource) bool {
+	return strings.EqualFold(targetResource.ResourceType(), string(infra.AzureResourceTypeSynapseWorkspace))
+}
+
+func dataPlatformFolder(configured string, defaultFolder string) string {
+	if configured != "" {
+		return configured
+	}
+
+	return defaultFolder
+}
+
+// walkDataPlatformFolder calls fn with the files of the folder, in lexical order, and with their path relative to the
+// folder. Missing folders have no files.
+func walkDataPlatformFolder(folder string, fn func(filePath string, relativePath string) error) error {
+	if _, err := os.Stat(folder); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return filepath.WalkDir(folder, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(folder, filePath)
+		if err != nil {
+			return err
+		}
+
+		return fn(filePath, relativePath)
+	})
+}
+
+// parseSynapseArtifact reads an artifact in the format of the git integration of Synapse workspaces,
+// {"name": "...", "properties": {...}}. Artifacts without a name are named after their file.
+func parseSynapseArtifact(filePath string, content []byte) (string, json.RawMessage, error) {
+	var artifact struct {
+		Name       string          `json:"name"`
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(content, &artifact); err != nil {
+		return "", nil, fmt.Errorf("parsing '%s': %w", filePath, err)
+	}
+
+	if len(artifact.Properties) == 0 {
+		return "", nil, fmt.Errorf("'%s' has no properties, artifacts are formatted as {\"name\": ..., \"properties\": ...}",
+			filePath)
+	}
+
+	if artifact.Name == "" {
+		artifact.Name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+
+	return artifact.Name, artifact.Properties, nil
+}
@@ -0,0 +1,234 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataPlatformTargetDeployDatabricks(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	writeTestFiles(t, map[string]string{
+		"notebooks/ingest.py":         "# Databricks notebook source",
+		"notebooks/reports/daily.sql": "SELECT 1",
+		"notebooks/README.md":         "# Notebooks",
+		"jobs/nightly.json": `{"tasks": [{"notebook_task": {"notebook_path": "${NOTEBOOKS_PATH}/ingest"}}], ` +
+			`"spark_conf": {"spark.app.name": "$name", "spark.user": "${SPARK_USER}"}}`,
+	})
+
+	env := environment.EphemeralWithValues("test", map[string]string{
+		"NOTEBOOKS_PATH": "/Shared/api",
+	})
+	serviceConfig := createTestServiceConfig(".", DataPlatformTarget, ServiceLanguagePython)
+
+	dataPlatformService := &fakeDataPlatformService{}
+	deployResult := deployDataPlatform(t, env, serviceConfig, dataPlatformService, infra.AzureResourceTypeDatabricksWorkspace)
+
+	require.Equal(t, DataPlatformTarget, deployResult.Kind)
+	require.Equal(t, []string{"https://adb-1234.5.azuredatabricks.net"}, deployResult.Endpoints)
+
+	require.Len(t, dataPlatformService.notebooks, 2)
+	require.Equal(t, azsdk.DatabricksNotebook{
+		Path:     "/Shared/api/ingest",
+		Format:   "SOURCE",
+		Language: "PYTHON",
+		Content:  []byte("# Databricks notebook source"),
+	}, *dataPlatformService.notebooks[0])
+	require.Equal(t, "/Shared/api/reports/daily", dataPlatformService.notebooks[1].Path)
+	require.Equal(t, "SQL", dataPlatformService.notebooks[1].Language)
+
+	// Jobs are named after their file, and reference the values of the environment
+	require.Equal(t, []map[string]any{
+		{
+			"name": "nightly",
+			"tasks": []any{
+				map[string]any{"notebook_task": map[string]any{"notebook_path": "/Shared/api/ingest"}},
+			},
+			// References which aren't environment values are kept as is
+			"spark_conf": map[string]any{"spark.app.name": "$name", "spark.user": "${SPARK_USER}"},
+		},
+	}, dataPlatformService.jobs)
+}
+
+func TestDataPlatformTargetDeploySynapse(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	writeTestFiles(t, map[string]string{
+		"notebooks/explore.ipynb": `{"nbformat": 4, "nbformat_minor": 2, "metadata": {}, "cells": []}`,
+		"etl/copy.json":           `{"name": "CopySales", "properties": {"activities": []}}`,
+	})
+
+	env := environment.EphemeralWithValues("test", nil)
+	serviceConfig := createTestServiceConfig(".", DataPlatformTarget, ServiceLanguagePython)
+	serviceConfig.DataPlatform = DataPlatformOptions{Pipelines: "etl"}
+
+	dataPlatformService := &fakeDataPlatformService{}
+	deployResult := deployDataPlatform(t, env, serviceConfig, dataPlatformService, infra.AzureResourceTypeSynapseWorkspace)
+
+	require.Equal(t, []string{"https://web.azuresynapse.net?workspace=workspace"}, deployResult.Endpoints)
+	require.Equal(t, []string{"notebooks/explore", "pipelines/CopySales"}, dataPlatformService.artifacts)
+	require.JSONEq(t, `{"activities": []}`, string(dataPlatformService.artifactProperties["pipelines/CopySales"]))
+	require.JSONEq(
+		t,
+		`{"nbformat": 4, "nbformat_minor": 2, "metadata": {}, "cells": []}`,
+		string(dataPlatformService.artifactProperties["notebooks/explore"]),
+	)
+}
+
+func TestDataPlatformTargetNoArtifacts(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceConfig := createTestServiceConfig(".", DataPlatformTarget, ServiceLanguagePython)
+	serviceTarget := NewDataPlatformTarget(environment.EphemeralWithValues("test", nil), &fakeDataPlatformService{})
+
+	deployTask := serviceTarget.Deploy(
+		*mockContext.Context,
+		serviceConfig,
+		&ServicePackageResult{},
+		environment.NewTargetResource("SUB", "RG", "dbw", string(infra.AzureResourceTypeDatabricksWorkspace)),
+	)
+	logProgress(deployTask)
+	_, err := deployTask.Await()
+	require.ErrorContains(t, err, "service api has no notebooks, jobs or pipelines to deploy")
+}
+
+func TestNewDataPlatformTargetTypeValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*serviceTargetValidationTest{
+		"ValidateDatabricksSuccess": {
+			targetResource: environment.NewTargetResource(
+				"SUB_ID",
+				"RG_ID",
+				"res",
+				string(infra.AzureResourceTypeDatabricksWorkspace),
+			),
+			expectError: false,
+		},
+		"ValidateSynapseSuccess": {
+			targetResource: environment.NewTargetResource(
+				"SUB_ID",
+				"RG_ID",
+				"res",
+				string(infra.AzureResourceTypeSynapseWorkspace),
+			),
+			expectError: false,
+		},
+		"ValidateTypeFail": {
+			targetResource: environment.NewTargetResource("SUB_ID", "RG_ID", "res", "BadType"),
+			expectError:    true,
+		},
+	}
+
+	for test, data := range tests {
+		t.Run(test, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(context.Background())
+			serviceTarget := &dataPlatformTarget{}
+			serviceConfig := &ServiceConfig{}
+
+			err := serviceTarget.validateTargetResource(*mockContext.Context, serviceConfig, data.targetResource)
+			if data.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func deployDataPlatform(
+	t *testing.T,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	dataPlatformService *fakeDataPlatformService,
+	resourceType infra.AzureResourceType,
+) *ServiceDeployResult {
+	mockContext := mocks.NewMockContext(context.Background())
+	serviceTarget := NewDataPlatformTarget(env, dataPlatformService)
+
+	deployTask := serviceTarget.Deploy(
+		*mockContext.Context,
+		serviceConfig,
+		&ServicePackageResult{},
+		environment.NewTargetResource("SUB", "RG", "workspace", string(resourceType)),
+	)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+	require.NoError(t, err)
+
+	return deployResult
+}
+
+func writeTestFiles(t *testing.T, files map[string]string) {
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(name, []byte(content), osutil.PermissionFile))
+	}
+}
+
+type fakeDataPlatformService struct {
+	notebooks          []*azsdk.DatabricksNotebook
+	jobs               []map[string]any
+	artifacts          []string
+	artifactProperties map[string]json.RawMessage
+}
+
+func (s *fakeDataPlatformService) GetDatabricksWorkspaceUrl(_ context.Context, _, _, _ string) (string, error) {
+	return "https://adb-1234.5.azuredatabricks.net", nil
+}
+
+func (s *fakeDataPlatformService) ImportDatabricksNotebook(
+	_ context.Context,
+	_, _, _ string,
+	notebook *azsdk.DatabricksNotebook,
+) error {
+	s.notebooks = append(s.notebooks, notebook)
+	return nil
+}
+
+func (s *fakeDataPlatformService) UpsertDatabricksJob(
+	_ context.Context,
+	_, _, _ string,
+	settings map[string]any,
+) (int64, error) {
+	s.jobs = append(s.jobs, settings)
+	return int64(len(s.jobs)), nil
+}
+
+func (s *fakeDataPlatformService) GetSynapseWorkspaceUrl(_ context.Context, _, _, workspaceName string) (string, error) {
+	return "https://web.azuresynapse.net?workspace=" + workspaceName, nil
+}
+
+func (s *fakeDataPlatformService) PutSynapseArtifact(
+	_ context.Context,
+	_, _, _ string,
+	kind string,
+	name string,
+	properties json.RawMessage,
+) error {
+	if s.artifactProperties == nil {
+		s.artifactProperties = map[string]json.RawMessage{}
+	}
+
+	s.artifacts = append(s.artifacts, kind+"/"+name)
+	s.artifactProperties[kind+"/"+name] = properties
+	return nil
+}
@@ -6,12 +6,14 @@ package project
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
@@ -20,18 +22,21 @@ import (
 // functionAppTarget specifies an Azure Function to deploy to.
 // Implements `project.ServiceTarget`
 type functionAppTarget struct {
-	env *environment.Environment
-	cli azcli.AzCli
+	env           *environment.Environment
+	cli           azcli.AzCli
+	commandRunner exec.CommandRunner
 }
 
 // NewFunctionAppTarget creates a new instance of the Function App target
 func NewFunctionAppTarget(
 	env *environment.Environment,
 	azCli azcli.AzCli,
+	commandRunner exec.CommandRunner,
 ) ServiceTarget {
 	return &functionAppTarget{
-		env: env,
-		cli: azCli,
+		env:           env,
+		cli:           azCli,
+		commandRunner: commandRunner,
 	}
 }
 
@@ -54,7 +59,7 @@ func (f *functionAppTarget) Package(
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
 			task.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
-			zipFilePath, err := createDeployableZip(serviceConfig.Name, packageOutput.PackagePath)
+			zipFilePath, err := createDeployableZip(ctx, serviceConfig.Name, packageOutput.PackagePath)
 			if err != nil {
 				task.SetError(err)
 				return
@@ -68,7 +73,8 @@ func (f *functionAppTarget) Package(
 	)
 }
 
-// Deploys the prepared zip archive using Zip deploy to the Azure App Service resource
+// Deploys the prepared zip archive to the Azure Function App resource, using the one deploy API for the apps hosted on the
+// Flex Consumption plan and Zip deploy otherwise
 func (f *functionAppTarget) Deploy(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -88,22 +94,44 @@ func (f *functionAppTarget) Deploy(
 				return
 			}
 
-			defer os.Remove(packageOutput.PackagePath)
 			defer zipFile.Close()
 
 			task.SetProgress(NewServiceProgress("Uploading deployment package"))
-			res, err := f.cli.DeployFunctionAppUsingZipFile(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				targetResource.ResourceName(),
-				zipFile,
-			)
+			uploadFile, err := withUploadProgress(zipFile, task)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			res, err := f.deployPackage(ctx, serviceConfig, targetResource, uploadFile)
 			if err != nil {
 				task.SetError(err)
 				return
 			}
 
+			if serviceConfig.Cors != nil {
+				task.SetProgress(NewServiceProgress("Updating function app configuration"))
+				_, err := f.cli.UpdateAppServiceSiteConfig(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					azcli.AzCliAppServiceSiteConfig{Cors: serviceConfig.Cors.settings()},
+				)
+				if err != nil {
+					task.SetError(fmt.Errorf("configuring service %s: %w", serviceConfig.Name, err))
+					return
+				}
+			}
+
+			if len(serviceConfig.CustomDomains) > 0 {
+				task.SetProgress(NewServiceProgress("Configuring custom domains"))
+				if err := bindCustomDomains(ctx, f.cli, f.env, serviceConfig, targetResource); err != nil {
+					task.SetError(err)
+					return
+				}
+			}
+
 			task.SetProgress(NewServiceProgress("Fetching endpoints for function app"))
 			endpoints, err := f.Endpoints(ctx, serviceConfig, targetResource)
 			if err != nil {
@@ -128,6 +156,55 @@ func (f *functionAppTarget) Deploy(
 	)
 }
 
+// deployPackage uploads the package with the deployment API supported by the hosting plan of the function app. The Flex
+// Consumption plan only supports the one deploy API, which builds the package remotely when requested instead of
+// reading the SCM_DO_BUILD_DURING_DEPLOYMENT app setting.
+func (f *functionAppTarget) deployPackage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	uploadFile io.Reader,
+) (*string, error) {
+	tier, err := f.cli.GetFunctionAppPlanTier(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting hosting plan of function app: %w", err)
+	}
+
+	if strings.EqualFold(tier, azcli.FlexConsumptionTier) {
+		// The dependencies of Python apps are installed by the remote build, the other packages are built locally
+		return f.cli.PublishFunctionAppUsingZipFile(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			uploadFile,
+			serviceConfig.Language == ServiceLanguagePython,
+		)
+	}
+
+	return f.cli.DeployFunctionAppUsingZipFile(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		uploadFile,
+	)
+}
+
+// Gets the capabilities of the Function App
+func (f *functionAppTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{
+		Slots:        true,
+		RemoteBuild:  true,
+		LogStreaming: true,
+	}
+}
+
 // Gets the exposed endpoints for the Function App
 func (f *functionAppTarget) Endpoints(
 	ctx context.Context,
@@ -153,6 +230,20 @@ func (f *functionAppTarget) Endpoints(
 	}
 }
 
+// Opens a tunnel from a local port to the SSH port of the Function App container
+func (f *functionAppTarget) PortForward(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	ports PortMapping,
+) error {
+	if err := f.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	return createWebAppRemoteConnection(ctx, f.commandRunner, targetResource, ports)
+}
+
 func (f *functionAppTarget) validateTargetResource(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
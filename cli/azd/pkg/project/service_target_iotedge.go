@@ -0,0 +1,525 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/benbjohnson/clock"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// The deployment manifest template looked up in the service path when none is configured
+	defaultIotEdgeManifest = "deployment.template.json"
+	// The folder of the modules discovered when none are configured
+	defaultIotEdgeModulesFolder = "modules"
+	// The priority of the deployments when none is configured
+	defaultIotEdgePriority = 10
+)
+
+// The labels identifying the deployments created for a service, so the previous deployments can be replaced
+const (
+	iotEdgeServiceLabel = "azd-service-name"
+	iotEdgeEnvLabel     = "azd-env-name"
+)
+
+// References in deployment manifests, like ${MODULES.filter} or ${AZURE_CONTAINER_REGISTRY_ENDPOINT}. Unbraced
+// references are left as is, since manifests use keys like $edgeAgent.
+var iotEdgeReferenceRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_.]*)\}`)
+
+// IotEdgeOptions configures the modules and the deployment manifest of iotedge services.
+type IotEdgeOptions struct {
+	// The deployment manifest template, relative to the service path. Defaults to deployment.template.json.
+	// Images are referenced as ${MODULES.<name>} and environment values as ${KEY}.
+	Manifest string `yaml:"manifest"`
+	// The modules built and pushed to the registry. Defaults to the folders of the modules folder with a Dockerfile.
+	Modules []IotEdgeModuleOptions `yaml:"modules"`
+	// The target condition selecting the device group, like tags.environment='${AZURE_ENV_NAME}'
+	TargetCondition ExpandableString `yaml:"targetCondition"`
+	// The priority of the deployment over the other deployments targeting the same devices. Defaults to 10.
+	Priority int `yaml:"priority"`
+}
+
+// IotEdgeModuleOptions configures how the image of an IoT Edge module is built.
+type IotEdgeModuleOptions struct {
+	// The name of the module, as referenced by the deployment manifest
+	Name string `yaml:"name"`
+	// The build context of the module, relative to the service path. Defaults to modules/<name>.
+	Path string `yaml:"path"`
+	// The Dockerfile, relative to the path of the module. Defaults to Dockerfile.
+	Dockerfile string `yaml:"dockerfile"`
+	// The platform of the devices, like arm64. Defaults to amd64.
+	Platform string `yaml:"platform"`
+}
+
+// The images of the modules built by the package step, by module name
+type iotEdgePackageResult struct {
+	Images map[string]string `json:"images"`
+}
+
+func (r *iotEdgePackageResult) ToString(currentIndentation string) string {
+	names := maps.Keys(r.Images)
+	slices.Sort(names)
+
+	lines := []string{}
+	for _, name := range names {
+		lines = append(
+			lines,
+			fmt.Sprintf("%s- Module %s: %s", currentIndentation, name, output.WithLinkFormat(r.Images[name])),
+		)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (r *iotEdgePackageResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(*r)
+}
+
+type iotEdgeTarget struct {
+	env             *environment.Environment
+	containerHelper *ContainerHelper
+	docker          docker.Docker
+	iotHubService   azcli.IotHubService
+	clock           clock.Clock
+}
+
+// NewIotEdgeTarget creates the IoT Edge service target, which builds the images of the modules of the service, pushes
+// them to the container registry and applies the deployment manifest to the devices of a device group through the
+// IoT hub. Each deployment replaces the previous deployment of the service.
+func NewIotEdgeTarget(
+	env *environment.Environment,
+	containerHelper *ContainerHelper,
+	docker docker.Docker,
+	iotHubService azcli.IotHubService,
+	clock clock.Clock,
+) ServiceTarget {
+	return &iotEdgeTarget{
+		env:             env,
+		containerHelper: containerHelper,
+		docker:          docker,
+		iotHubService:   iotHubService,
+		clock:           clock,
+	}
+}
+
+// Gets the required external tools
+func (t *iotEdgeTarget) RequiredExternalTools(ctx context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{t.docker}
+}
+
+// Initializes the IoT Edge target
+func (t *iotEdgeTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Builds the images of the modules
+func (t *iotEdgeTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			modules, err := t.modules(serviceConfig)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			images := map[string]string{}
+			for _, module := range modules {
+				localTag := fmt.Sprintf("%s/%s-%s-%s:azd-deploy-%d",
+					strings.ToLower(serviceConfig.Project.Name),
+					strings.ToLower(serviceConfig.Name),
+					strings.ToLower(module.Name),
+					strings.ToLower(t.env.GetEnvName()),
+					t.clock.Now().Unix(),
+				)
+
+				task.SetProgress(NewServiceProgress(fmt.Sprintf("Building image of module %s", module.Name)))
+				modulePath := filepath.Join(serviceConfig.Path(), module.Path)
+				if _, err := t.docker.Build(
+					ctx,
+					modulePath,
+					filepath.Join(modulePath, module.Dockerfile),
+					module.Platform,
+					modulePath,
+					localTag,
+					nil,
+					nil,
+				); err != nil {
+					task.SetError(fmt.Errorf("building image of module %s: %w", module.Name, err))
+					return
+				}
+
+				images[module.Name] = localTag
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       packageOutput.Build,
+				PackagePath: serviceConfig.Path(),
+				Details:     &iotEdgePackageResult{Images: images},
+			})
+		},
+	)
+}
+
+// Pushes the images of the modules and applies the deployment manifest to the devices of the device group
+func (t *iotEdgeTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			if err := t.validateTargetResource(ctx, serviceConfig, targetResource); err != nil {
+				task.SetError(fmt.Errorf("validating target resource: %w", err))
+				return
+			}
+
+			packageDetails, ok := packageOutput.Details.(*iotEdgePackageResult)
+			if !ok || packageDetails == nil {
+				task.SetError(errors.New("failed retrieving package result details"))
+				return
+			}
+
+			targetCondition, err := serviceConfig.IotEdge.TargetCondition.Envsubst(t.env.Getenv)
+			if err != nil {
+				task.SetError(fmt.Errorf("expanding iotEdge.targetCondition: %w", err))
+				return
+			}
+
+			if targetCondition == "" {
+				task.SetError(fmt.Errorf(
+					"service %s doesn't select its devices, set iotEdge.targetCondition, like tags.environment='%s'",
+					serviceConfig.Name,
+					t.env.GetEnvName(),
+				))
+				return
+			}
+
+			images, err := t.pushImages(ctx, task, serviceConfig, packageDetails, targetResource)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Rendering deployment manifest"))
+			modulesContent, err := t.renderManifest(serviceConfig, images)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			priority := serviceConfig.IotEdge.Priority
+			if priority == 0 {
+				priority = defaultIotEdgePriority
+			}
+
+			deployment := &azsdk.IotHubConfiguration{
+				Id: strings.ToLower(fmt.Sprintf(
+					"azd-%s-%s-%d",
+					serviceConfig.Name,
+					t.env.GetEnvName(),
+					t.clock.Now().Unix(),
+				)),
+				Content:         azsdk.IotHubConfigurationContent{ModulesContent: modulesContent},
+				TargetCondition: targetCondition,
+				Priority:        priority,
+				Labels: map[string]string{
+					iotEdgeServiceLabel: serviceConfig.Name,
+					iotEdgeEnvLabel:     t.env.GetEnvName(),
+				},
+			}
+
+			if err := t.applyDeployment(ctx, task, targetResource, deployment); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			details, _ := json.Marshal(map[string]any{
+				"deploymentId":    deployment.Id,
+				"targetCondition": deployment.TargetCondition,
+				"images":          images,
+			})
+
+			sdr := NewServiceDeployResult(
+				azure.IotHubRID(
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+				),
+				IotEdgeTarget,
+				string(details),
+				[]string{},
+			)
+			sdr.Package = packageOutput
+
+			task.SetResult(sdr)
+		},
+	)
+}
+
+// pushImages tags the images of the modules for the registry and pushes them, and returns the pushed images by
+// module name.
+func (t *iotEdgeTarget) pushImages(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	serviceConfig *ServiceConfig,
+	packageDetails *iotEdgePackageResult,
+	targetResource *environment.TargetResource,
+) (map[string]string, error) {
+	registry, err := t.containerHelper.Registry(ctx, serviceConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("logging into container registry '%s'\n", registry.Endpoint())
+	task.SetProgress(NewServiceProgress("Logging into container registry"))
+	if err := registry.Login(ctx, targetResource); err != nil {
+		return nil, err
+	}
+
+	names := maps.Keys(packageDetails.Images)
+	slices.Sort(names)
+
+	images := map[string]string{}
+	for _, name := range names {
+		remoteTag, err := t.containerHelper.RemoteImageTag(ctx, serviceConfig, packageDetails.Images[name])
+		if err != nil {
+			return nil, fmt.Errorf("getting remote image tag: %w", err)
+		}
+
+		if err := t.docker.Tag(ctx, serviceConfig.Path(), packageDetails.Images[name], remoteTag); err != nil {
+			return nil, err
+		}
+
+		log.Printf("pushing %s to registry", remoteTag)
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Pushing image of module %s", name)))
+		if err := t.docker.Push(ctx, serviceConfig.Path(), remoteTag); err != nil {
+			return nil, err
+		}
+
+		images[name] = remoteTag
+	}
+
+	return images, nil
+}
+
+// applyDeployment creates the deployment, then deletes the previous deployments of the service in the environment,
+// so devices only move off the previous modules once the new deployment targets them.
+func (t *iotEdgeTarget) applyDeployment(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+	targetResource *environment.TargetResource,
+	deployment *azsdk.IotHubConfiguration,
+) error {
+	existing, err := t.iotHubService.ListDeployments(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+	if err != nil {
+		return err
+	}
+
+	task.SetProgress(NewServiceProgress("Creating IoT Edge deployment"))
+	if err := t.iotHubService.CreateDeployment(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		deployment,
+	); err != nil {
+		return err
+	}
+
+	for _, previous := range existing {
+		if previous.Id == deployment.Id ||
+			previous.Labels[iotEdgeServiceLabel] != deployment.Labels[iotEdgeServiceLabel] ||
+			previous.Labels[iotEdgeEnvLabel] != deployment.Labels[iotEdgeEnvLabel] {
+			continue
+		}
+
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Removing previous deployment %s", previous.Id)))
+		if err := t.iotHubService.DeleteDeployment(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			previous.Id,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderManifest substitutes the images of the modules and the environment values in the deployment manifest
+// template, and returns its modules content.
+func (t *iotEdgeTarget) renderManifest(
+	serviceConfig *ServiceConfig,
+	images map[string]string,
+) (map[string]any, error) {
+	manifest := serviceConfig.IotEdge.Manifest
+	if manifest == "" {
+		manifest = defaultIotEdgeManifest
+	}
+
+	manifestPath := filepath.Join(serviceConfig.Path(), manifest)
+	template, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading deployment manifest: %w", err)
+	}
+
+	rendered, err := renderIotEdgeManifest(string(template), images, t.env.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("rendering deployment manifest '%s': %w", manifest, err)
+	}
+
+	var document struct {
+		ModulesContent map[string]any `json:"modulesContent"`
+	}
+	if err := json.Unmarshal([]byte(rendered), &document); err != nil {
+		return nil, fmt.Errorf("parsing deployment manifest '%s': %w", manifest, err)
+	}
+
+	if len(document.ModulesContent) == 0 {
+		return nil, fmt.Errorf("deployment manifest '%s' has no modulesContent", manifest)
+	}
+
+	return document.ModulesContent, nil
+}
+
+// renderIotEdgeManifest substitutes the ${MODULES.<name>} references with the images of the modules, and the other
+// references with the values of lookup. References without a value fail with an
+// [environment.UnresolvedReferencesError].
+func renderIotEdgeManifest(
+	template string,
+	images map[string]string,
+	lookup func(string) (string, bool),
+) (string, error) {
+	missing := []string{}
+	rendered := iotEdgeReferenceRegex.ReplaceAllStringFunc(template, func(reference string) string {
+		name := iotEdgeReferenceRegex.FindStringSubmatch(reference)[1]
+
+		var value string
+		var has bool
+		if module, isModule := strings.CutPrefix(name, "MODULES."); isModule {
+			value, has = images[module]
+		} else {
+			value, has = lookup(name)
+		}
+
+		if !has {
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+
+			return reference
+		}
+
+		// Values are substituted in JSON strings
+		encoded, _ := json.Marshal(value)
+		return string(encoded[1 : len(encoded)-1])
+	})
+
+	if len(missing) > 0 {
+		return "", &environment.UnresolvedReferencesError{Names: missing}
+	}
+
+	return rendered, nil
+}
+
+// modules returns the configured modules with their defaults, or the modules discovered in the modules folder.
+func (t *iotEdgeTarget) modules(serviceConfig *ServiceConfig) ([]IotEdgeModuleOptions, error) {
+	modules := slices.Clone(serviceConfig.IotEdge.Modules)
+
+	if len(modules) == 0 {
+		entries, err := os.ReadDir(filepath.Join(serviceConfig.Path(), defaultIotEdgeModulesFolder))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading modules: %w", err)
+		}
+
+		for _, entry := range entries {
+			dockerfile := filepath.Join(serviceConfig.Path(), defaultIotEdgeModulesFolder, entry.Name(), "Dockerfile")
+			if _, err := os.Stat(dockerfile); entry.IsDir() && err == nil {
+				modules = append(modules, IotEdgeModuleOptions{Name: entry.Name()})
+			}
+		}
+
+		if len(modules) == 0 {
+			return nil, fmt.Errorf(
+				"service %s has no modules, add modules with a Dockerfile to the %s folder or set iotEdge.modules",
+				serviceConfig.Name,
+				defaultIotEdgeModulesFolder,
+			)
+		}
+	}
+
+	for i, module := range modules {
+		if module.Name == "" {
+			return nil, fmt.Errorf("module %d of service %s has no name", i, serviceConfig.Name)
+		}
+
+		if module.Path == "" {
+			modules[i].Path = filepath.Join(defaultIotEdgeModulesFolder, module.Name)
+		}
+
+		if module.Dockerfile == "" {
+			modules[i].Dockerfile = "Dockerfile"
+		}
+	}
+
+	return modules, nil
+}
+
+// IoT Edge deployments have none of the optional capabilities
+func (t *iotEdgeTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{}
+}
+
+// IoT Edge services don't expose endpoints, their modules run on the devices
+func (t *iotEdgeTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	return []string{}, nil
+}
+
+func (t *iotEdgeTarget) validateTargetResource(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	if err := checkResourceType(targetResource, infra.AzureResourceTypeIotHub); err != nil {
+		return err
+	}
+
+	return nil
+}
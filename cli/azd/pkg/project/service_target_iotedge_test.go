@@ -0,0 +1,206 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+)
+
+const testIotEdgeManifest = `{
+	"$schema-template": "4.0.0",
+	"modulesContent": {
+		"$edgeAgent": {
+			"properties.desired": {
+				"modules": {
+					"filter": {
+						"type": "docker",
+						"settings": { "image": "${MODULES.filter}" },
+						"env": { "THRESHOLD": { "value": "${FILTER_THRESHOLD}" } }
+					}
+				}
+			}
+		},
+		"$edgeHub": {
+			"properties.desired": { "schemaVersion": "1.2" }
+		}
+	}
+}`
+
+func TestIotEdgeTargetDeploy(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join("modules", "filter"), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(
+		filepath.Join("modules", "filter", "Dockerfile"), []byte("FROM scratch"), osutil.PermissionFile))
+	require.NoError(t, os.WriteFile("deployment.template.json", []byte(testIotEdgeManifest), osutil.PermissionFile))
+
+	mockContext := mocks.NewMockContext(context.Background())
+	setupMocksForDocker(mockContext)
+	setupMocksForAcr(mockContext)
+
+	builds := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		builds = append(builds, args.Cwd)
+		return exec.NewRunResult(0, "IMAGE_ID", ""), nil
+	})
+
+	env := createEnv()
+	env.Values["FILTER_THRESHOLD"] = "25"
+
+	serviceConfig := createTestServiceConfig(".", IotEdgeTarget, ServiceLanguagePython)
+	serviceConfig.IotEdge = IotEdgeOptions{
+		TargetCondition: NewExpandableString("tags.environment='${AZURE_ENV_NAME}'"),
+	}
+
+	iotHubService := &fakeIotHubService{
+		deployments: []*azsdk.IotHubConfiguration{
+			{Id: "azd-api-test-1", Labels: map[string]string{"azd-service-name": "api", "azd-env-name": "test"}},
+			{Id: "azd-api-other-1", Labels: map[string]string{"azd-service-name": "api", "azd-env-name": "other"}},
+			{Id: "manual"},
+		},
+	}
+	serviceTarget := createIotEdgeServiceTarget(mockContext, env, iotHubService)
+
+	packageTask := serviceTarget.Package(*mockContext.Context, serviceConfig, &ServicePackageResult{})
+	logProgress(packageTask)
+	packageResult, err := packageTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join("modules", "filter")}, builds)
+	require.Equal(t, &iotEdgePackageResult{
+		Images: map[string]string{"filter": "test-app/api-filter-test:azd-deploy-0"},
+	}, packageResult.Details)
+
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"IOT_HUB",
+		string(infra.AzureResourceTypeIotHub),
+	)
+	deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, targetResource)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+	require.NoError(t, err)
+	require.Equal(t, IotEdgeTarget, deployResult.Kind)
+
+	require.Len(t, iotHubService.created, 1)
+	deployment := iotHubService.created[0]
+	require.Equal(t, "azd-api-test-0", deployment.Id)
+	require.Equal(t, "tags.environment='test'", deployment.TargetCondition)
+	require.Equal(t, 10, deployment.Priority)
+
+	edgeAgent := deployment.Content.ModulesContent["$edgeAgent"].(map[string]any)
+	modules := edgeAgent["properties.desired"].(map[string]any)["modules"].(map[string]any)
+	module := modules["filter"].(map[string]any)
+	require.Equal(t, "REGISTRY.azurecr.io/test-app/api-filter-test:azd-deploy-0", module["settings"].(map[string]any)["image"])
+	require.Equal(t, "25", module["env"].(map[string]any)["THRESHOLD"].(map[string]any)["value"])
+
+	// Only the previous deployment of the service in the environment is replaced
+	require.Equal(t, []string{"azd-api-test-1"}, iotHubService.deleted)
+}
+
+func TestRenderIotEdgeManifest(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == "REGISTRY_PASSWORD" {
+			return `pa"ss`, true
+		}
+
+		return "", false
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		rendered, err := renderIotEdgeManifest(
+			`{"$edgeAgent": {"image": "${MODULES.filter}", "password": "${REGISTRY_PASSWORD}", "schema": "$schema"}}`,
+			map[string]string{"filter": "contoso.azurecr.io/filter:1"},
+			lookup,
+		)
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			`{"$edgeAgent": {"image": "contoso.azurecr.io/filter:1", "password": "pa\"ss", "schema": "$schema"}}`,
+			rendered,
+		)
+	})
+
+	t.Run("UnresolvedReferences", func(t *testing.T) {
+		_, err := renderIotEdgeManifest(
+			`{"image": "${MODULES.other}", "value": "${MISSING}", "again": "${MISSING}"}`,
+			map[string]string{"filter": "contoso.azurecr.io/filter:1"},
+			lookup,
+		)
+
+		var unresolvedErr *environment.UnresolvedReferencesError
+		require.True(t, errors.As(err, &unresolvedErr))
+		require.Equal(t, []string{"MODULES.other", "MISSING"}, unresolvedErr.Names)
+	})
+}
+
+func createIotEdgeServiceTarget(
+	mockContext *mocks.MockContext,
+	env *environment.Environment,
+	iotHubService azcli.IotHubService,
+) ServiceTarget {
+	dockerCli := docker.NewDocker(mockContext.CommandRunner)
+	credentialProvider := mockaccount.SubscriptionCredentialProviderFunc(
+		func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		})
+
+	containerRegistryService := azcli.NewContainerRegistryService(credentialProvider, mockContext.HttpClient, dockerCli)
+	containerHelper := NewContainerHelper(env, clock.NewMock(), containerRegistryService, dockerCli, nil, nil)
+
+	return NewIotEdgeTarget(env, containerHelper, dockerCli, iotHubService, clock.NewMock())
+}
+
+type fakeIotHubService struct {
+	deployments []*azsdk.IotHubConfiguration
+	created     []*azsdk.IotHubConfiguration
+	deleted     []string
+}
+
+func (s *fakeIotHubService) ListDeployments(
+	_ context.Context,
+	_ string,
+	_ string,
+	_ string,
+) ([]*azsdk.IotHubConfiguration, error) {
+	return s.deployments, nil
+}
+
+func (s *fakeIotHubService) CreateDeployment(
+	_ context.Context,
+	_ string,
+	_ string,
+	_ string,
+	deployment *azsdk.IotHubConfiguration,
+) error {
+	s.created = append(s.created, deployment)
+	return nil
+}
+
+func (s *fakeIotHubService) DeleteDeployment(_ context.Context, _ string, _ string, _ string, deploymentId string) error {
+	s.deleted = append(s.deleted, deploymentId)
+	return nil
+}
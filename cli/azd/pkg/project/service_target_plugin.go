@@ -0,0 +1,366 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"golang.org/x/exp/slices"
+)
+
+// ServiceTargetPluginsDirEnvVarName overrides the directory service target plugins are discovered in.
+const ServiceTargetPluginsDirEnvVarName = "AZD_SERVICE_TARGET_PLUGINS_DIR"
+
+// ServiceTargetPluginProtocolVersion is the version of the contract between azd and service target plugins.
+const ServiceTargetPluginProtocolVersion = 1
+
+// Service target plugins are named after the host they implement, like azd-target-onprem for the host onprem
+const serviceTargetPluginPrefix = "azd-target-"
+
+// The operations a service target plugin can implement
+const (
+	ServiceTargetPluginPackage   = "package"
+	ServiceTargetPluginDeploy    = "deploy"
+	ServiceTargetPluginEndpoints = "endpoints"
+)
+
+// ServiceTargetPluginsDir returns the directory service target plugins are discovered in, which is the plugins
+// directory of the azd config directory unless overridden with AZD_SERVICE_TARGET_PLUGINS_DIR.
+func ServiceTargetPluginsDir() (string, error) {
+	if dir := os.Getenv(ServiceTargetPluginsDirEnvVarName); dir != "" {
+		return dir, nil
+	}
+
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, "plugins"), nil
+}
+
+// findServiceTargetPlugin returns the path of the plugin implementing the host, or os.ErrNotExist when no plugin is
+// installed for it.
+func findServiceTargetPlugin(kind ServiceTargetKind) (string, error) {
+	if kind == "" {
+		return "", os.ErrNotExist
+	}
+
+	dir, err := ServiceTargetPluginsDir()
+	if err != nil {
+		return "", err
+	}
+
+	name := serviceTargetPluginPrefix + string(kind)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	path := filepath.Join(dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	if info.IsDir() {
+		return "", os.ErrNotExist
+	}
+
+	return path, nil
+}
+
+// The plugin contract: azd runs the plugin with the operation as its only argument, like `azd-target-onprem deploy`,
+// writes the request as JSON to stdin and reads the response as JSON from stdout. The environment values are set as
+// environment variables of the plugin, and anything written to stderr is logged. A non zero exit code fails the
+// operation.
+
+type serviceTargetPluginCapabilitiesRequest struct {
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+type serviceTargetPluginCapabilities struct {
+	ProtocolVersion int `json:"protocolVersion"`
+	// The operations implemented by the plugin, deploy is required, and the ServiceTargetCapability values supported by
+	// the plugin
+	Capabilities []string `json:"capabilities"`
+}
+
+type serviceTargetPluginService struct {
+	Name        string              `json:"name"`
+	Host        ServiceTargetKind   `json:"host"`
+	Language    ServiceLanguageKind `json:"language"`
+	ProjectPath string              `json:"projectPath"`
+	Path        string              `json:"path"`
+	OutputPath  string              `json:"outputPath"`
+}
+
+type serviceTargetPluginTargetResource struct {
+	SubscriptionId    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	ResourceName      string `json:"resourceName"`
+	ResourceType      string `json:"resourceType"`
+}
+
+type serviceTargetPluginRequest struct {
+	Service        serviceTargetPluginService         `json:"service"`
+	Package        *ServicePackageResult              `json:"package,omitempty"`
+	TargetResource *serviceTargetPluginTargetResource `json:"targetResource,omitempty"`
+}
+
+type serviceTargetPluginDeployResponse struct {
+	TargetResourceId string   `json:"targetResourceId"`
+	Endpoints        []string `json:"endpoints"`
+	Details          any      `json:"details"`
+}
+
+type serviceTargetPluginEndpointsResponse struct {
+	Endpoints []string `json:"endpoints"`
+}
+
+// ServiceTargetPluginManager creates the service targets of the hosts implemented by plugins.
+type ServiceTargetPluginManager struct {
+	env           *environment.Environment
+	commandRunner exec.CommandRunner
+}
+
+// NewServiceTargetPluginManager creates a new instance of the ServiceTargetPluginManager
+func NewServiceTargetPluginManager(
+	env *environment.Environment,
+	commandRunner exec.CommandRunner,
+) *ServiceTargetPluginManager {
+	return &ServiceTargetPluginManager{
+		env:           env,
+		commandRunner: commandRunner,
+	}
+}
+
+// ServiceTarget returns the service target of the plugin implementing the host, after negotiating the capabilities
+// of the plugin.
+func (m *ServiceTargetPluginManager) ServiceTarget(ctx context.Context, kind ServiceTargetKind) (ServiceTarget, error) {
+	path, err := findServiceTargetPlugin(kind)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no plugin installed for service host '%s'", kind)
+	} else if err != nil {
+		return nil, fmt.Errorf("finding plugin for service host '%s': %w", kind, err)
+	}
+
+	target := &pluginServiceTarget{
+		kind:          kind,
+		path:          path,
+		env:           m.env,
+		commandRunner: m.commandRunner,
+	}
+
+	var capabilities serviceTargetPluginCapabilities
+	request := serviceTargetPluginCapabilitiesRequest{ProtocolVersion: ServiceTargetPluginProtocolVersion}
+	if err := target.invoke(ctx, "capabilities", request, &capabilities); err != nil {
+		return nil, fmt.Errorf("negotiating capabilities of plugin '%s': %w", path, err)
+	}
+
+	if capabilities.ProtocolVersion != ServiceTargetPluginProtocolVersion {
+		return nil, fmt.Errorf(
+			"plugin '%s' implements protocol version %d, azd supports version %d",
+			path,
+			capabilities.ProtocolVersion,
+			ServiceTargetPluginProtocolVersion,
+		)
+	}
+
+	if !slices.Contains(capabilities.Capabilities, ServiceTargetPluginDeploy) {
+		return nil, fmt.Errorf("plugin '%s' does not implement the required '%s' capability", path, ServiceTargetPluginDeploy)
+	}
+
+	target.capabilities = capabilities.Capabilities
+	return target, nil
+}
+
+// pluginServiceTarget is a service target implemented by an external plugin
+type pluginServiceTarget struct {
+	kind          ServiceTargetKind
+	path          string
+	capabilities  []string
+	env           *environment.Environment
+	commandRunner exec.CommandRunner
+}
+
+// Plugins are responsible for their own tools
+func (t *pluginServiceTarget) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the plugin service target
+func (t *pluginServiceTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Packages the service with the plugin, or keeps the package of the framework when the plugin doesn't package
+func (t *pluginServiceTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	frameworkPackageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			if !t.supports(ServiceTargetPluginPackage) {
+				task.SetResult(frameworkPackageOutput)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Packaging with plugin %s", filepath.Base(t.path))))
+			request := serviceTargetPluginRequest{
+				Service: newServiceTargetPluginService(serviceConfig),
+				Package: frameworkPackageOutput,
+			}
+
+			var packageResult ServicePackageResult
+			if err := t.invoke(ctx, ServiceTargetPluginPackage, request, &packageResult); err != nil {
+				task.SetError(fmt.Errorf("packaging service %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			if packageResult.Build == nil && frameworkPackageOutput != nil {
+				packageResult.Build = frameworkPackageOutput.Build
+			}
+
+			task.SetResult(&packageResult)
+		},
+	)
+}
+
+// Deploys the package with the plugin
+func (t *pluginServiceTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress(fmt.Sprintf("Deploying with plugin %s", filepath.Base(t.path))))
+			request := serviceTargetPluginRequest{
+				Service:        newServiceTargetPluginService(serviceConfig),
+				Package:        packageOutput,
+				TargetResource: newServiceTargetPluginTargetResource(targetResource),
+			}
+
+			var deployResponse serviceTargetPluginDeployResponse
+			if err := t.invoke(ctx, ServiceTargetPluginDeploy, request, &deployResponse); err != nil {
+				task.SetError(fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			task.SetResult(&ServiceDeployResult{
+				Package:          packageOutput,
+				TargetResourceId: deployResponse.TargetResourceId,
+				Kind:             t.kind,
+				Endpoints:        deployResponse.Endpoints,
+				Details:          deployResponse.Details,
+			})
+		},
+	)
+}
+
+// Gets the endpoints of the service from the plugin, services of plugins without the capability have no endpoints
+func (t *pluginServiceTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	if !t.supports(ServiceTargetPluginEndpoints) {
+		return []string{}, nil
+	}
+
+	request := serviceTargetPluginRequest{
+		Service:        newServiceTargetPluginService(serviceConfig),
+		TargetResource: newServiceTargetPluginTargetResource(targetResource),
+	}
+
+	var endpointsResponse serviceTargetPluginEndpointsResponse
+	if err := t.invoke(ctx, ServiceTargetPluginEndpoints, request, &endpointsResponse); err != nil {
+		return nil, fmt.Errorf("fetching endpoints of service %s: %w", serviceConfig.Name, err)
+	}
+
+	return endpointsResponse.Endpoints, nil
+}
+
+// Gets the capabilities declared by the plugin
+func (t *pluginServiceTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{
+		Slots:        t.supports(string(SlotsCapability)),
+		RemoteBuild:  t.supports(string(RemoteBuildCapability)),
+		LogStreaming: t.supports(string(LogStreamingCapability)),
+		Rollback:     t.supports(string(RollbackCapability)),
+	}
+}
+
+func (t *pluginServiceTarget) supports(capability string) bool {
+	return slices.Contains(t.capabilities, capability)
+}
+
+// invoke runs an operation of the plugin, writing the request to stdin and reading the response from stdout
+func (t *pluginServiceTarget) invoke(ctx context.Context, operation string, request any, response any) error {
+	content, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("marshalling request: %w", err)
+	}
+
+	runArgs := exec.NewRunArgs(t.path, operation)
+	runArgs.StdIn = bytes.NewReader(content)
+	if t.env != nil {
+		runArgs = runArgs.WithEnv(t.env.Environ())
+	}
+
+	res, err := t.commandRunner.Run(ctx, runArgs)
+	if res.Stderr != "" {
+		log.Printf("plugin %s %s: %s", filepath.Base(t.path), operation, res.Stderr)
+	}
+	if err != nil {
+		return fmt.Errorf("running plugin operation '%s': %w", operation, err)
+	}
+
+	if err := json.Unmarshal([]byte(res.Stdout), response); err != nil {
+		return fmt.Errorf("parsing response of plugin operation '%s': %w", operation, err)
+	}
+
+	return nil
+}
+
+func newServiceTargetPluginService(serviceConfig *ServiceConfig) serviceTargetPluginService {
+	return serviceTargetPluginService{
+		Name:        serviceConfig.Name,
+		Host:        serviceConfig.Host,
+		Language:    serviceConfig.Language,
+		ProjectPath: serviceConfig.Project.Path,
+		Path:        serviceConfig.Path(),
+		OutputPath:  serviceConfig.OutputPath,
+	}
+}
+
+func newServiceTargetPluginTargetResource(
+	targetResource *environment.TargetResource,
+) *serviceTargetPluginTargetResource {
+	if targetResource == nil {
+		return nil
+	}
+
+	return &serviceTargetPluginTargetResource{
+		SubscriptionId:    targetResource.SubscriptionId(),
+		ResourceGroupName: targetResource.ResourceGroupName(),
+		ResourceName:      targetResource.ResourceName(),
+		ResourceType:      targetResource.ResourceType(),
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceTargetPlugin(t *testing.T) {
+	pluginPath := installTestServiceTargetPlugin(t, "onprem")
+
+	t.Run("ParseHost", func(t *testing.T) {
+		kind, err := parseServiceHost("onprem")
+		require.NoError(t, err)
+		require.Equal(t, ServiceTargetKind("onprem"), kind)
+
+		_, err = parseServiceHost("unknown")
+		require.Error(t, err)
+	})
+
+	t.Run("Deploy", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockServiceTargetPlugin(mockContext, pluginPath, `{"protocolVersion": 1, "capabilities": ["deploy"]}`)
+
+		var deployRequest serviceTargetPluginRequest
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == pluginPath && args.Args[0] == ServiceTargetPluginDeploy
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.Contains(t, args.Env, "AZURE_ENV_NAME=dev")
+			content, err := io.ReadAll(args.StdIn)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(content, &deployRequest))
+
+			return exec.NewRunResult(0, `{"targetResourceId": "onprem/api", "endpoints": ["http://api.contoso"]}`, ""), nil
+		})
+
+		env := environment.EphemeralWithValues("dev", nil)
+		pluginManager := NewServiceTargetPluginManager(env, mockContext.CommandRunner)
+		serviceTarget, err := pluginManager.ServiceTarget(*mockContext.Context, "onprem")
+		require.NoError(t, err)
+
+		serviceConfig := createTestServiceConfig("./src/api", "onprem", ServiceLanguageJavaScript)
+		packageOutput := &ServicePackageResult{PackagePath: "api.zip"}
+
+		packageTask := serviceTarget.Package(*mockContext.Context, serviceConfig, packageOutput)
+		logProgress(packageTask)
+		packageResult, err := packageTask.Await()
+		require.NoError(t, err)
+		require.Same(t, packageOutput, packageResult)
+
+		targetResource := environment.NewTargetResource("SUB", "RG", "api", "Contoso/OnPrem")
+		deployTask := serviceTarget.Deploy(*mockContext.Context, serviceConfig, packageResult, targetResource)
+		logProgress(deployTask)
+		deployResult, err := deployTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, "onprem/api", deployResult.TargetResourceId)
+		require.Equal(t, ServiceTargetKind("onprem"), deployResult.Kind)
+		require.Equal(t, []string{"http://api.contoso"}, deployResult.Endpoints)
+
+		require.Equal(t, "api", deployRequest.Service.Name)
+		require.Equal(t, "api.zip", deployRequest.Package.PackagePath)
+		require.Equal(t, "RG", deployRequest.TargetResource.ResourceGroupName)
+
+		endpoints, err := serviceTarget.Endpoints(*mockContext.Context, serviceConfig, targetResource)
+		require.NoError(t, err)
+		require.Empty(t, endpoints)
+	})
+
+	t.Run("Capabilities", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockServiceTargetPlugin(
+			mockContext, pluginPath, `{"protocolVersion": 1, "capabilities": ["deploy", "logStreaming", "rollback"]}`)
+
+		pluginManager := NewServiceTargetPluginManager(nil, mockContext.CommandRunner)
+		serviceTarget, err := pluginManager.ServiceTarget(*mockContext.Context, "onprem")
+		require.NoError(t, err)
+		require.Equal(t,
+			[]ServiceTargetCapability{LogStreamingCapability, RollbackCapability}, serviceTarget.Capabilities().List())
+
+		serviceConfig := createTestServiceConfig("./src/api", "onprem", ServiceLanguageJavaScript)
+		require.NoError(t, RequireCapability(serviceConfig, serviceTarget, RollbackCapability))
+
+		err = RequireCapability(serviceConfig, serviceTarget, SlotsCapability)
+		require.ErrorIs(t, err, ErrCapabilityNotSupported)
+		require.ErrorContains(t, err, "service 'api' is hosted on 'onprem', which does not support deployment slots")
+	})
+
+	t.Run("UnsupportedProtocolVersion", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockServiceTargetPlugin(mockContext, pluginPath, `{"protocolVersion": 2, "capabilities": ["deploy"]}`)
+
+		pluginManager := NewServiceTargetPluginManager(nil, mockContext.CommandRunner)
+		_, err := pluginManager.ServiceTarget(*mockContext.Context, "onprem")
+		require.ErrorContains(t, err, "protocol version 2")
+	})
+
+	t.Run("MissingDeployCapability", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockServiceTargetPlugin(mockContext, pluginPath, `{"protocolVersion": 1, "capabilities": ["package"]}`)
+
+		pluginManager := NewServiceTargetPluginManager(nil, mockContext.CommandRunner)
+		_, err := pluginManager.ServiceTarget(*mockContext.Context, "onprem")
+		require.ErrorContains(t, err, "'deploy' capability")
+	})
+
+	t.Run("NotInstalled", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		pluginManager := NewServiceTargetPluginManager(nil, mockContext.CommandRunner)
+		_, err := pluginManager.ServiceTarget(*mockContext.Context, "unknown")
+		require.ErrorContains(t, err, "no plugin installed")
+	})
+}
+
+func installTestServiceTargetPlugin(t *testing.T, kind string) string {
+	pluginsDir := t.TempDir()
+	t.Setenv(ServiceTargetPluginsDirEnvVarName, pluginsDir)
+
+	name := serviceTargetPluginPrefix + kind
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+
+	pluginPath := filepath.Join(pluginsDir, name)
+	require.NoError(t, os.WriteFile(pluginPath, []byte{}, osutil.PermissionExecutableFile))
+
+	return pluginPath
+}
+
+func mockServiceTargetPlugin(mockContext *mocks.MockContext, pluginPath string, capabilities string) {
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == pluginPath && args.Args[0] == "capabilities"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		content, _ := io.ReadAll(args.StdIn)
+		if !strings.Contains(string(content), `"protocolVersion":1`) {
+			return exec.NewRunResult(1, "", "unexpected request"), nil
+		}
+
+		return exec.NewRunResult(0, capabilities, ""), nil
+	})
+}
+
+func TestServiceTargetKindRequiresAzureResource(t *testing.T) {
+	require.True(t, AppServiceTarget.RequiresAzureResource())
+	require.True(t, ContainerAppTarget.RequiresAzureResource())
+	require.False(t, WebhookTarget.RequiresAzureResource())
+
+	// Plugins may use a tagged resource of the environment, but don't require one
+	require.False(t, ServiceTargetKind("onprem").IsBuiltIn())
+	require.False(t, ServiceTargetKind("onprem").RequiresAzureResource())
+}
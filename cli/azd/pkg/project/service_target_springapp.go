@@ -108,7 +108,7 @@ func (st *springAppTarget) Deploy(
 			ext := ".jar"
 			artifactPath := filepath.Join(packageOutput.PackagePath, AppServiceJavaPackageName+ext)
 
-			_, err = os.Stat(artifactPath)
+			artifactInfo, err := os.Stat(artifactPath)
 			if errors.Is(err, os.ErrNotExist) {
 				task.SetError(fmt.Errorf("artifact %s does not exist: %w", artifactPath, err))
 				return
@@ -127,6 +127,7 @@ func (st *springAppTarget) Deploy(
 				targetResource.ResourceName(),
 				serviceConfig.Name,
 				artifactPath,
+				uploadProgress(artifactInfo.Size(), task),
 			)
 
 			if err != nil {
@@ -183,6 +184,13 @@ func (st *springAppTarget) Deploy(
 	)
 }
 
+// Gets the capabilities of the Spring App
+func (st *springAppTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{
+		LogStreaming: true,
+	}
+}
+
 // Gets the exposed endpoints for the Spring Apps Service
 func (st *springAppTarget) Endpoints(
 	ctx context.Context,
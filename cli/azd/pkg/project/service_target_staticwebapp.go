@@ -150,6 +150,11 @@ func (at *staticWebAppTarget) Deploy(
 	)
 }
 
+// Static Web Apps have none of the optional capabilities
+func (at *staticWebAppTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{}
+}
+
 // Gets the endpoints for the static web app
 func (at *staticWebAppTarget) Endpoints(
 	ctx context.Context,
@@ -0,0 +1,48 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// WebAppRemoteConnectionPort is the port of the SSH server in App Service containers, the only port reachable through
+// a remote connection.
+const WebAppRemoteConnectionPort = 2222
+
+// createWebAppRemoteConnection opens a TCP tunnel from a local port to an App Service or Function App container
+// using `az webapp create-remote-connection`. The tunnel stays open until the context is cancelled.
+func createWebAppRemoteConnection(
+	ctx context.Context,
+	commandRunner exec.CommandRunner,
+	targetResource *environment.TargetResource,
+	ports PortMapping,
+) error {
+	if ports.Remote != 0 && ports.Remote != WebAppRemoteConnectionPort {
+		return fmt.Errorf(
+			"remote port %d is not supported, App Service tunnels only connect to the container SSH port %d",
+			ports.Remote,
+			WebAppRemoteConnectionPort,
+		)
+	}
+
+	runArgs := exec.NewRunArgs(
+		"az", "webapp", "create-remote-connection",
+		"--subscription", targetResource.SubscriptionId(),
+		"--resource-group", targetResource.ResourceGroupName(),
+		"--name", targetResource.ResourceName(),
+		"--port", strconv.Itoa(ports.Local),
+	).WithInteractive(true)
+
+	if _, err := commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("creating remote connection to %s: %w", targetResource.ResourceName(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// WebhookOptions configures the endpoint the packages of webhook services are handed off to.
+type WebhookOptions struct {
+	// The endpoint the package is posted to. Supports environment variable substitution.
+	Url ExpandableString `yaml:"url"`
+	// The headers sent with the request, like `Authorization: Bearer ${DEPLOY_TOKEN}`, so credentials stay in the
+	// environment.
+	Headers map[string]ExpandableString `yaml:"headers"`
+	// When set, the URL of the package, like a storage URL, is posted as JSON instead of the package itself.
+	ArtifactUrl ExpandableString `yaml:"artifactUrl"`
+}
+
+// The length the response body of the endpoint is truncated to in errors and deployment details, the endpoint may
+// echo the request or dump a stack trace.
+const webhookMaxBodyLength = 512
+
+// The payload posted when the webhook is configured with an artifact url
+type webhookArtifactRequest struct {
+	Service     string `json:"service"`
+	Environment string `json:"environment"`
+	ArtifactUrl string `json:"artifactUrl"`
+}
+
+// The optional JSON response of the endpoint
+type webhookResponse struct {
+	Endpoints []string `json:"endpoints"`
+}
+
+type webhookTarget struct {
+	env        *environment.Environment
+	httpClient httputil.HttpClient
+}
+
+// NewWebhookTarget creates a new instance of the webhook target, which hands the package of the service off to a
+// configured endpoint, like a custom deployment service.
+func NewWebhookTarget(env *environment.Environment, httpClient httputil.HttpClient) ServiceTarget {
+	return &webhookTarget{
+		env:        env,
+		httpClient: httpClient,
+	}
+}
+
+// Gets the required external tools for the webhook target
+func (t *webhookTarget) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the webhook target
+func (t *webhookTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Compresses the build output unless the package is handed off by url
+func (t *webhookTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			if !serviceConfig.Webhook.ArtifactUrl.Empty() {
+				task.SetResult(packageOutput)
+				return
+			}
+
+			info, err := os.Stat(packageOutput.PackagePath)
+			if err != nil {
+				task.SetError(fmt.Errorf("reading package: %w", err))
+				return
+			}
+
+			if !info.IsDir() {
+				task.SetResult(packageOutput)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Compressing deployment artifacts"))
+			zipFilePath, err := createDeployableZip(ctx, serviceConfig.Name, packageOutput.PackagePath)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       packageOutput.Build,
+				PackagePath: zipFilePath,
+			})
+		},
+	)
+}
+
+// Posts the package, or its url, to the configured endpoint
+func (t *webhookTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			webhookUrl, err := serviceConfig.Webhook.Url.Envsubst(t.env.Getenv)
+			if err != nil {
+				task.SetError(fmt.Errorf("expanding webhook url: %w", err))
+				return
+			}
+
+			if strings.TrimSpace(webhookUrl) == "" {
+				task.SetError(errors.New("webhook.url must be set for services with host 'webhook'"))
+				return
+			}
+
+			req, err := t.newRequest(ctx, webhookUrl, serviceConfig, packageOutput)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Posting package to webhook"))
+			res, err := t.httpClient.Do(req)
+			if err != nil {
+				// The error of the client repeats the url of the request, which may contain a token
+				var urlErr *url.Error
+				if errors.As(err, &urlErr) {
+					err = urlErr.Err
+				}
+
+				task.SetError(fmt.Errorf("posting package to webhook %s: %w", redactWebhookUrl(webhookUrl), err))
+				return
+			}
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				task.SetError(fmt.Errorf("reading webhook response: %w", err))
+				return
+			}
+
+			if res.StatusCode < 200 || res.StatusCode > 299 {
+				task.SetError(fmt.Errorf("webhook responded with status %d: %s", res.StatusCode, truncateWebhookBody(body)))
+				return
+			}
+
+			// The endpoints of the service are read from the response when the endpoint returns them
+			var response webhookResponse
+			_ = json.Unmarshal(body, &response)
+
+			sdr := NewServiceDeployResult(
+				redactWebhookUrl(webhookUrl), WebhookTarget, truncateWebhookBody(body), response.Endpoints)
+			sdr.Package = packageOutput
+
+			task.SetResult(sdr)
+		},
+	)
+}
+
+// Webhooks have none of the optional capabilities, the deployment is up to the endpoint
+func (t *webhookTarget) Capabilities() ServiceTargetCapabilities {
+	return ServiceTargetCapabilities{}
+}
+
+// Webhook services don't expose endpoints azd can discover after the deployment
+func (t *webhookTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	return []string{}, nil
+}
+
+// redactWebhookUrl returns the scheme and host of the webhook url, which identify the endpoint without the credentials
+// webhook urls commonly carry in their user info, path or query.
+func redactWebhookUrl(webhookUrl string) string {
+	parsed, err := url.Parse(webhookUrl)
+	if err != nil || parsed.Host == "" {
+		return "<redacted>"
+	}
+
+	redacted := fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
+	if strings.Trim(parsed.Path, "/") != "" || parsed.RawQuery != "" {
+		redacted += "/<redacted>"
+	}
+
+	return redacted
+}
+
+func truncateWebhookBody(body []byte) string {
+	if len(body) <= webhookMaxBodyLength {
+		return string(body)
+	}
+
+	return fmt.Sprintf("%s... (%d bytes truncated)", body[:webhookMaxBodyLength], len(body)-webhookMaxBodyLength)
+}
+
+func (t *webhookTarget) newRequest(
+	ctx context.Context,
+	webhookUrl string,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) (*http.Request, error) {
+	var body io.ReadCloser
+	contentType := "application/zip"
+
+	if !serviceConfig.Webhook.ArtifactUrl.Empty() {
+		artifactUrl, err := serviceConfig.Webhook.ArtifactUrl.Envsubst(t.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("expanding webhook artifact url: %w", err)
+		}
+
+		content, err := json.Marshal(webhookArtifactRequest{
+			Service:     serviceConfig.Name,
+			Environment: t.env.GetEnvName(),
+			ArtifactUrl: artifactUrl,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		body = io.NopCloser(bytes.NewReader(content))
+		contentType = "application/json"
+	} else {
+		file, err := os.Open(packageOutput.PackagePath)
+		if err != nil {
+			return nil, fmt.Errorf("opening package: %w", err)
+		}
+
+		body = file
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookUrl, body)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("creating webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range serviceConfig.Webhook.Headers {
+		headerValue, err := value.Envsubst(t.env.Getenv)
+		if err != nil {
+			body.Close()
+			return nil, fmt.Errorf("expanding webhook header '%s': %w", name, err)
+		}
+
+		req.Header.Set(name, headerValue)
+	}
+
+	return req, nil
+}
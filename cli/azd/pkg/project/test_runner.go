@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// TestOptions are the test commands of a service in azure.yaml, run by `azd test` from the directory of the service.
+type TestOptions struct {
+	// The command running the unit tests, like `npm test`
+	Unit string `yaml:"unit"`
+	// The command running the end to end tests against the deployed service
+	E2e string `yaml:"e2e"`
+}
+
+// TestSuiteKind is the kind of test suite of a service
+type TestSuiteKind string
+
+const (
+	TestSuiteUnit TestSuiteKind = "unit"
+	TestSuiteE2e  TestSuiteKind = "e2e"
+)
+
+// TestResult is the result of running a test suite of a service
+type TestResult struct {
+	Service  string
+	Suite    TestSuiteKind
+	Command  string
+	Duration time.Duration
+	Output   string
+	// The error of the test command, nil when the tests passed
+	Err error
+}
+
+// Passed returns true when the test command succeeded
+func (r *TestResult) Passed() bool {
+	return r.Err == nil
+}
+
+// TestRunner runs the test commands configured for services
+type TestRunner struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewTestRunner creates a new instance of the TestRunner
+func NewTestRunner(commandRunner exec.CommandRunner) *TestRunner {
+	return &TestRunner{
+		commandRunner: commandRunner,
+	}
+}
+
+// Command returns the command of the test suite of the service, or an empty string when the service doesn't configure
+// one.
+func (r *TestRunner) Command(serviceConfig *ServiceConfig, suite TestSuiteKind) string {
+	if serviceConfig.Test == nil {
+		return ""
+	}
+
+	if suite == TestSuiteE2e {
+		return serviceConfig.Test.E2e
+	}
+
+	return serviceConfig.Test.Unit
+}
+
+// Run runs the test suite of the service in a shell from the directory of the service, with the values of env set as
+// environment variables. The output of the tests is written to writer, prefixed with the name of the service. A
+// failing test command is reported by the result, not as an error.
+func (r *TestRunner) Run(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	suite TestSuiteKind,
+	env []string,
+	writer io.Writer,
+) (*TestResult, error) {
+	command := r.Command(serviceConfig, suite)
+	if command == "" {
+		return nil, fmt.Errorf("service '%s' has no %s test command", serviceConfig.Name, suite)
+	}
+
+	serviceWriter := output.NewPrefixWriter(writer, output.WithHighLightFormat("%s | ", serviceConfig.Name), &sync.Mutex{})
+	runArgs := exec.NewRunArgs(command).
+		WithShell(true).
+		WithCwd(serviceConfig.Path()).
+		WithEnv(env).
+		WithStdOut(serviceWriter).
+		WithStdErr(serviceWriter)
+
+	start := time.Now()
+	res, err := r.commandRunner.Run(ctx, runArgs)
+	_ = serviceWriter.Flush()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return &TestResult{
+		Service:  serviceConfig.Name,
+		Suite:    suite,
+		Command:  command,
+		Duration: time.Since(start),
+		Output:   strings.TrimSpace(strings.Join([]string{res.Stdout, res.Stderr}, "\n")),
+		Err:      err,
+	}, nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes the results as a JUnit report, with a test suite per service and a test case per suite of
+// the service, so CI systems can display the results of `azd test`.
+func WriteJUnitReport(writer io.Writer, results []*TestResult) error {
+	report := junitTestSuites{Name: "azd test"}
+	suiteIndexes := map[string]int{}
+	suiteDurations := map[string]time.Duration{}
+	var total time.Duration
+
+	for _, result := range results {
+		index, has := suiteIndexes[result.Service]
+		if !has {
+			report.Suites = append(report.Suites, junitTestSuite{Name: result.Service})
+			index = len(report.Suites) - 1
+			suiteIndexes[result.Service] = index
+		}
+
+		testCase := junitTestCase{
+			Name:      string(result.Suite),
+			ClassName: result.Service,
+			Time:      junitSeconds(result.Duration),
+			SystemOut: result.Output,
+		}
+
+		suite := &report.Suites[index]
+		suite.Tests++
+		report.Tests++
+		if !result.Passed() {
+			testCase.Failure = &junitFailure{
+				Message: fmt.Sprintf("'%s' failed: %s", result.Command, result.Err.Error()),
+				Content: result.Output,
+			}
+			suite.Failures++
+			report.Failures++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suiteDurations[result.Service] += result.Duration
+		total += result.Duration
+	}
+
+	for i := range report.Suites {
+		report.Suites[i].Time = junitSeconds(suiteDurations[report.Suites[i].Name])
+	}
+	report.Time = junitSeconds(total)
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("writing junit report: %w", err)
+	}
+
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
+
+func junitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
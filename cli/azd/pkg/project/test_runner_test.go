@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestRunnerRun(t *testing.T) {
+	// Disable the colored service prefixes for the expected output
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	serviceConfig := createDevServiceConfig(t, "api", ServiceLanguageJavaScript, nil)
+	serviceConfig.Test = &TestOptions{Unit: "npm test", E2e: "npm run e2e"}
+
+	t.Run("Passed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return command == "npm run e2e"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			require.True(t, args.UseShell)
+			require.Equal(t, serviceConfig.Path(), args.Cwd)
+			require.Contains(t, args.Env, "SERVICE_ENDPOINT=https://api.contoso.com")
+			fmt.Fprintln(args.Stdout, "1 passing")
+
+			return exec.NewRunResult(0, "1 passing", ""), nil
+		})
+
+		buf := &bytes.Buffer{}
+		runner := NewTestRunner(mockContext.CommandRunner)
+		result, err := runner.Run(
+			*mockContext.Context, serviceConfig, TestSuiteE2e, []string{"SERVICE_ENDPOINT=https://api.contoso.com"}, buf)
+		require.NoError(t, err)
+		require.True(t, result.Passed())
+		require.Equal(t, "npm run e2e", result.Command)
+		require.Equal(t, "1 passing", result.Output)
+		require.Equal(t, "api | 1 passing\n", buf.String())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return command == "npm test"
+		}).SetError(errors.New("exit code: 1"))
+
+		runner := NewTestRunner(mockContext.CommandRunner)
+		result, err := runner.Run(*mockContext.Context, serviceConfig, TestSuiteUnit, nil, &bytes.Buffer{})
+		require.NoError(t, err)
+		require.False(t, result.Passed())
+	})
+
+	t.Run("NoCommand", func(t *testing.T) {
+		serviceConfig := createDevServiceConfig(t, "web", ServiceLanguageJavaScript, nil)
+
+		runner := NewTestRunner(nil)
+		_, err := runner.Run(context.Background(), serviceConfig, TestSuiteUnit, nil, &bytes.Buffer{})
+		require.ErrorContains(t, err, "service 'web' has no unit test command")
+	})
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	results := []*TestResult{
+		{Service: "api", Suite: TestSuiteUnit, Command: "npm test", Duration: 1500 * time.Millisecond},
+		{
+			Service:  "web",
+			Suite:    TestSuiteUnit,
+			Command:  "pytest",
+			Duration: 2 * time.Second,
+			Output:   "1 failed",
+			Err:      errors.New("exit code: 1"),
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteJUnitReport(buf, results))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="azd test" tests="2" failures="1" time="3.500">
+  <testsuite name="api" tests="1" failures="0" time="1.500">
+    <testcase name="unit" classname="api" time="1.500"></testcase>
+  </testsuite>
+  <testsuite name="web" tests="1" failures="1" time="2.000">
+    <testcase name="unit" classname="web" time="2.000">
+      <failure message="&#39;pytest&#39; failed: exit code: 1">1 failed</failure>
+      <system-out>1 failed</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	require.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"golang.org/x/exp/slices"
+)
+
+// The certificates securing the custom domains
+const (
+	// A free certificate managed, and renewed, by App Service
+	CustomDomainCertificateManaged = "managed"
+	// No certificate, the domain is only served over HTTP, or secured by a certificate bound outside of azd
+	CustomDomainCertificateNone = "none"
+)
+
+// The hosts supporting the cors and customDomains settings of the services
+var webAppConfigHosts = []ServiceTargetKind{AppServiceTarget, AzureFunctionTarget}
+
+// CorsOptions configures the CORS policy of a service, applied after each deployment when it differs from the policy
+// of the service.
+type CorsOptions struct {
+	// The origins allowed to make cross-origin calls, like https://www.contoso.com, or * for all origins
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+	// Allows the requests with credentials, like cookies
+	SupportCredentials bool `yaml:"supportCredentials,omitempty"`
+}
+
+// settings returns the CORS policy of the options, or nil when the options aren't set.
+func (o *CorsOptions) settings() *azcli.AzCliCorsSettings {
+	if o == nil {
+		return nil
+	}
+
+	return &azcli.AzCliCorsSettings{
+		AllowedOrigins:     o.AllowedOrigins,
+		SupportCredentials: o.SupportCredentials,
+	}
+}
+
+// CustomDomainConfig declares a custom domain of a service, bound after each deployment. The DNS records of the domain
+// are managed outside of azd, the records to create are reported when the domain can't be verified.
+type CustomDomainConfig struct {
+	// The host name of the domain, like www.contoso.com. Supports environment variable substitution.
+	Name ExpandableString `yaml:"name"`
+	// The certificate securing the domain, managed or none. Defaults to managed.
+	Certificate string `yaml:"certificate,omitempty"`
+}
+
+// validateWebAppConfig validates the cors and customDomains settings of the service.
+func validateWebAppConfig(svc *ServiceConfig) error {
+	if svc.Cors == nil && len(svc.CustomDomains) == 0 {
+		return nil
+	}
+
+	if !slices.Contains(webAppConfigHosts, svc.Host) {
+		return fmt.Errorf("cors and customDomains are supported by the services hosted on %s and %s, not %s",
+			AppServiceTarget, AzureFunctionTarget, svc.Host)
+	}
+
+	if svc.Cors != nil && len(svc.Cors.AllowedOrigins) == 0 {
+		return fmt.Errorf("cors.allowedOrigins must be set")
+	}
+
+	for i, domain := range svc.CustomDomains {
+		if domain.Name == (ExpandableString{}) {
+			return fmt.Errorf("customDomains[%d].name must be set", i)
+		}
+
+		switch domain.Certificate {
+		case "", CustomDomainCertificateManaged, CustomDomainCertificateNone:
+		default:
+			return fmt.Errorf(
+				"customDomains[%d].certificate '%s' isn't supported, use '%s' or '%s'",
+				i,
+				domain.Certificate,
+				CustomDomainCertificateManaged,
+				CustomDomainCertificateNone,
+			)
+		}
+	}
+
+	return nil
+}
+
+// bindCustomDomains binds the custom domains of the service to the App Service, or Function App, of the target
+// resource.
+func bindCustomDomains(
+	ctx context.Context,
+	cli azcli.AzCli,
+	env *environment.Environment,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	for i, domain := range serviceConfig.CustomDomains {
+		hostName, err := domain.Name.Resolve(env)
+		if err != nil {
+			return fmt.Errorf("resolving custom domain of service %s: %w", serviceConfig.Name, err)
+		}
+
+		hostName = strings.ToLower(strings.TrimSpace(hostName))
+		if hostName == "" {
+			return fmt.Errorf("service %s: customDomains[%d].name resolves to an empty name", serviceConfig.Name, i)
+		}
+
+		err = cli.BindAppServiceCustomDomain(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			hostName,
+			domain.Certificate != CustomDomainCertificateNone,
+		)
+		if err != nil {
+			return fmt.Errorf("configuring custom domain of service %s: %w", serviceConfig.Name, err)
+		}
+	}
+
+	return nil
+}
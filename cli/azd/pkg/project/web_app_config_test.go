@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWebAppConfig(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  web:
+    project: src/web
+    language: js
+    host: appservice
+    cors:
+      allowedOrigins:
+        - https://www.contoso.com
+    customDomains:
+      - name: ${WEB_DOMAIN}
+      - name: admin.contoso.com
+        certificate: none
+`
+
+	projectConfig, err := Parse(context.Background(), testProj)
+	require.NoError(t, err)
+
+	service := projectConfig.Services["web"]
+	require.Equal(t, []string{"https://www.contoso.com"}, service.Cors.AllowedOrigins)
+	require.Len(t, service.CustomDomains, 2)
+	require.Equal(t, CustomDomainCertificateNone, service.CustomDomains[1].Certificate)
+}
+
+func TestValidateWebAppConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		service ServiceConfig
+		err     string
+	}{
+		{
+			name: "UnsupportedHost",
+			service: ServiceConfig{
+				Host: ContainerAppTarget,
+				Cors: &CorsOptions{AllowedOrigins: []string{"*"}},
+			},
+			err: "cors and customDomains are supported by the services hosted on appservice and function",
+		},
+		{
+			name:    "NoOrigins",
+			service: ServiceConfig{Host: AppServiceTarget, Cors: &CorsOptions{}},
+			err:     "cors.allowedOrigins must be set",
+		},
+		{
+			name:    "NoDomainName",
+			service: ServiceConfig{Host: AzureFunctionTarget, CustomDomains: []CustomDomainConfig{{}}},
+			err:     "customDomains[0].name must be set",
+		},
+		{
+			name: "Certificate",
+			service: ServiceConfig{
+				Host: AppServiceTarget,
+				CustomDomains: []CustomDomainConfig{
+					{Name: NewExpandableString("www.contoso.com"), Certificate: "custom"},
+				},
+			},
+			err: "customDomains[0].certificate 'custom' isn't supported",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateWebAppConfig(&test.service)
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
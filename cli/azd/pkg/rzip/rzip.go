@@ -5,54 +5,273 @@ package rzip
 
 import (
 	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"context"
+	"fmt"
+	"hash/crc32"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
-func CreateFromDirectory(source string, buf *os.File) error {
-	w := zip.NewWriter(buf)
-	err := filepath.WalkDir(source, func(path string, info fs.DirEntry, err error) error {
+// Files up to this size are compressed in parallel into memory. Larger files are streamed to the archive, which bounds
+// the memory used while packaging.
+const maxBufferedFileSize = 4 * 1024 * 1024
+
+// CreateFromDirectory writes a zip archive of the files in the source directory to writer.
+//
+// Files are compressed by parallel workers and written in the order of the directory walk, so archives of the same
+// directory are identical. The mode of the files is preserved, including executable bits. Symlinks to files and
+// directories of the source directory are followed, and symlinks to paths outside of the source directory, which
+// wouldn't be deployed, fail the archive. Zip64 is used when the archive, or a file in it, exceeds 4GB.
+func CreateFromDirectory(ctx context.Context, source string, writer io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := runtime.NumCPU()
+	jobs := make(chan *entry, workers)
+	// The entries in the order of the walk, compressed or waiting for a worker
+	queue := make(chan *entry, workers*2)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range jobs {
+				e.done <- e.compress()
+			}
+		}()
+	}
+
+	var walkErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(queue)
+		defer close(jobs)
+		walkErr = walk(ctx, source, jobs, queue)
+	}()
+
+	err := write(ctx, writer, queue)
+
+	// Stop the walk and wait for the workers, so no file is left open when the archive is removed after a failure
+	cancel()
+	for range queue {
+	}
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	return walkErr
+}
+
+// entry is a file of the archive
+type entry struct {
+	path   string
+	header *zip.FileHeader
+	// Signals that a worker compressed the file, nil for entries written by the writer directly
+	done chan error
+	// The content compressed by a worker, written as is to the archive
+	compressed []byte
+}
+
+// walker adds the files of the source directory to the queue of the archive.
+type walker struct {
+	ctx context.Context
+	// The source directory, with its symlinks resolved
+	root  string
+	jobs  chan<- *entry
+	queue chan<- *entry
+}
+
+func walk(ctx context.Context, source string, jobs chan<- *entry, queue chan<- *entry) error {
+	root, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return err
+	}
+
+	w := &walker{ctx: ctx, root: root, jobs: jobs, queue: queue}
+	return w.walkDir(root, "")
+}
+
+// walkDir adds the files of the directory to the archive, with their path relative to the directory prefixed by prefix.
+func (w *walker) walkDir(dir string, prefix string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() {
+		if d.IsDir() {
 			return nil
 		}
-		fileInfo, err := info.Info()
+
+		rel, err := filepath.Rel(dir, path)
 		if err != nil {
 			return err
 		}
+		name := filepath.ToSlash(filepath.Join(prefix, rel))
+
+		// For symlinks, the info is the one of the link and not of its target
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			target, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				return fmt.Errorf("resolving symlink %s: %w", name, err)
+			}
+
+			if !isWithin(w.root, target) {
+				return fmt.Errorf("symlink %s points to %s, outside of %s", name, target, w.root)
+			}
+
+			info, err = os.Stat(target)
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				// A link to a parent directory would be followed endlessly
+				if isWithin(target, filepath.Dir(path)) {
+					return fmt.Errorf("symlink %s points to its parent directory %s", name, target)
+				}
+
+				return w.walkDir(target, name)
+			}
+
+			path = target
+		}
 
-		header := &zip.FileHeader{
-			Name: strings.Replace(
-				strings.TrimPrefix(
-					strings.TrimPrefix(path, source),
-					string(filepath.Separator)), "\\", "/", -1),
-			Modified: fileInfo.ModTime(),
-			Method:   zip.Deflate,
+		if !info.Mode().IsRegular() {
+			// Sockets, pipes and devices can't be deployed
+			return nil
 		}
 
-		f, err := w.CreateHeader(header)
+		header, err := zip.FileInfoHeader(info)
 		if err != nil {
 			return err
 		}
-		in, err := os.Open(path)
-		if err != nil {
+		header.Name = name
+		header.Method = zip.Deflate
+
+		e := &entry{path: path, header: header}
+		if info.Size() <= maxBufferedFileSize {
+			e.done = make(chan error, 1)
+			select {
+			case w.jobs <- e:
+			case <-w.ctx.Done():
+				return w.ctx.Err()
+			}
+		}
+
+		select {
+		case w.queue <- e:
+			return nil
+		case <-w.ctx.Done():
+			return w.ctx.Err()
+		}
+	})
+}
+
+// isWithin returns true when path is the directory dir or one of its descendants.
+func isWithin(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// compress reads and compresses the file, setting the sizes and checksum of the header. Files which don't shrink are
+// stored uncompressed.
+func (e *entry) compress() error {
+	content, err := os.ReadFile(e.path)
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	flateWriter, err := flate.NewWriter(buf, flate.DefaultCompression)
+	if err != nil {
+		return err
+	}
+
+	if _, err := flateWriter.Write(content); err != nil {
+		return err
+	}
+
+	if err := flateWriter.Close(); err != nil {
+		return err
+	}
+
+	e.compressed = buf.Bytes()
+	if buf.Len() >= len(content) {
+		e.header.Method = zip.Store
+		e.compressed = content
+	}
+
+	e.header.CRC32 = crc32.ChecksumIEEE(content)
+	e.header.UncompressedSize64 = uint64(len(content))
+	e.header.CompressedSize64 = uint64(len(e.compressed))
+
+	return nil
+}
+
+func write(ctx context.Context, writer io.Writer, queue <-chan *entry) error {
+	zipWriter := zip.NewWriter(writer)
+
+	for e := range queue {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := e.write(zipWriter); err != nil {
+			return fmt.Errorf("adding %s to archive: %w", e.header.Name, err)
+		}
+	}
+
+	return zipWriter.Close()
+}
+
+func (e *entry) write(zipWriter *zip.Writer) error {
+	if e.done != nil {
+		if err := <-e.done; err != nil {
 			return err
 		}
-		_, err = io.Copy(f, in)
+
+		w, err := zipWriter.CreateRaw(e.header)
 		if err != nil {
 			return err
 		}
-		return nil
-	})
+
+		_, err = w.Write(e.compressed)
+		// Release the content as soon as it's written
+		e.compressed = nil
+		return err
+	}
+
+	w, err := zipWriter.CreateHeader(e.header)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(e.path)
 	if err != nil {
 		return err
 	}
+	defer in.Close()
 
-	return w.Close()
+	_, err = io.Copy(w, in)
+	return err
 }
@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package rzip
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFromDirectory(t *testing.T) {
+	source := t.TempDir()
+	large := strings.Repeat("azd", maxBufferedFileSize)
+	files := map[string]string{
+		"host.json":           `{"version": "2.0"}`,
+		"api/index.js":        "module.exports = {}",
+		"api/data/large.json": large,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(source, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	}
+
+	if runtime.GOOS != "windows" {
+		require.NoError(t, os.WriteFile(filepath.Join(source, "handler"), []byte("#!/bin/sh"), osutil.PermissionExecutableFile))
+		require.NoError(t, os.Symlink("api/index.js", filepath.Join(source, "index.js")))
+		require.NoError(t, os.Symlink("api", filepath.Join(source, "lib")))
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, CreateFromDirectory(context.Background(), source, buf))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	entries := map[string]*zip.File{}
+	names := []string{}
+	for _, file := range reader.File {
+		entries[file.Name] = file
+		names = append(names, file.Name)
+	}
+
+	for name, content := range files {
+		require.Contains(t, names, name)
+		require.Equal(t, content, readZipFile(t, entries[name]))
+	}
+
+	require.Equal(t, zip.Deflate, entries["api/data/large.json"].Method)
+
+	if runtime.GOOS != "windows" {
+		require.Equal(t, fs.FileMode(osutil.PermissionExecutableFile), entries["handler"].Mode().Perm())
+		// Symlinks within the source directory are followed
+		require.True(t, entries["index.js"].Mode().IsRegular())
+		require.Equal(t, files["api/index.js"], readZipFile(t, entries["index.js"]))
+		require.Equal(t, files["api/index.js"], readZipFile(t, entries["lib/index.js"]))
+	}
+
+	t.Run("Deterministic", func(t *testing.T) {
+		second := &bytes.Buffer{}
+		require.NoError(t, CreateFromDirectory(context.Background(), source, second))
+		require.Equal(t, buf.Bytes(), second.Bytes())
+	})
+
+	t.Run("SymlinkOutsideSource", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("creating symlinks requires elevation on Windows")
+		}
+
+		source := t.TempDir()
+		outside := filepath.Join(t.TempDir(), "secrets.env")
+		require.NoError(t, os.WriteFile(outside, []byte("KEY=value"), osutil.PermissionFile))
+		require.NoError(t, os.Symlink(outside, filepath.Join(source, "secrets.env")))
+
+		err := CreateFromDirectory(context.Background(), source, &bytes.Buffer{})
+		require.ErrorContains(t, err, "outside of")
+	})
+
+	t.Run("SymlinkToParent", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("creating symlinks requires elevation on Windows")
+		}
+
+		source := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(source, "api"), osutil.PermissionDirectory))
+		require.NoError(t, os.Symlink("..", filepath.Join(source, "api", "root")))
+
+		err := CreateFromDirectory(context.Background(), source, &bytes.Buffer{})
+		require.ErrorContains(t, err, "parent directory")
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := CreateFromDirectory(ctx, source, &bytes.Buffer{})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func readZipFile(t *testing.T, file *zip.File) string {
+	reader, err := file.Open()
+	require.NoError(t, err)
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(content)
+}
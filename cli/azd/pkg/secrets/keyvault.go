@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+)
+
+// KeyVaultSecrets reads the secrets of Azure Key Vaults, like azcli.AzCli.
+type KeyVaultSecrets interface {
+	GetKeyVaultSecret(
+		ctx context.Context,
+		subscriptionId string,
+		vaultName string,
+		secretName string,
+	) (*azcli.AzCliKeyVaultSecret, error)
+}
+
+// Matches the names of Key Vault secrets: letters, digits and dashes
+var keyVaultSecretNameRegex = regexp.MustCompile(`^[0-9a-zA-Z-]{1,127}$`)
+
+// KeyVaultSecretName returns the name of the Key Vault secret storing the environment value key. Key Vault doesn't allow
+// underscores in the names of secrets, they're replaced by dashes.
+func KeyVaultSecretName(key string) (string, error) {
+	name := strings.ReplaceAll(key, "_", "-")
+	if !keyVaultSecretNameRegex.MatchString(name) {
+		return "", fmt.Errorf(
+			"'%s' can't be stored in Key Vault, the names of secrets can only contain letters, digits, dashes and underscores",
+			key,
+		)
+	}
+
+	return name, nil
+}
+
+// KeyVaultReference returns the ref of the secret of a vault, `<vault>/<secret>`.
+func KeyVaultReference(vaultName string, secretName string) string {
+	return vaultName + "/" + secretName
+}
+
+// keyVaultProvider reads secrets from Azure Key Vault, with the credential of the subscription of the environment. The
+// ref is `<vault>/<secret>`, the latest version of the secret is read.
+type keyVaultProvider struct {
+	keyVault       KeyVaultSecrets
+	subscriptionId string
+}
+
+func newKeyVaultProvider(keyVault KeyVaultSecrets, subscriptionId string) Provider {
+	return &keyVaultProvider{
+		keyVault:       keyVault,
+		subscriptionId: subscriptionId,
+	}
+}
+
+func (p *keyVaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	vaultName, secretName, found := strings.Cut(ref, "/")
+	if !found || vaultName == "" || secretName == "" {
+		return "", fmt.Errorf("invalid keyvault reference '%s', expected <vault>/<secret>", ref)
+	}
+
+	if p.subscriptionId == "" {
+		return "", errors.New("the environment has no subscription, set AZURE_SUBSCRIPTION_ID to read Key Vault secrets")
+	}
+
+	secret, err := p.keyVault.GetKeyVaultSecret(ctx, p.subscriptionId, vaultName, secretName)
+	if err != nil {
+		return "", err
+	} else if secret == nil {
+		return "", fmt.Errorf("reading secret '%s' of key vault '%s'", secretName, vaultName)
+	}
+
+	return secret.Value, nil
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// vaultProvider reads secrets from HashiCorp Vault using the vault CLI. The ref is `<path>#<field>`, the CLI uses
+// the address and the token configured for it, like VAULT_ADDR and VAULT_TOKEN.
+type vaultProvider struct {
+	commandRunner exec.CommandRunner
+}
+
+func newVaultProvider(commandRunner exec.CommandRunner) Provider {
+	return &vaultProvider{
+		commandRunner: commandRunner,
+	}
+}
+
+func (p *vaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, field := splitField(ref)
+	if field == "" {
+		return "", fmt.Errorf("invalid vault reference '%s', expected <path>#<field>", ref)
+	}
+
+	return runSecretCommand(ctx, p.commandRunner, exec.NewRunArgs("vault", "kv", "get", "-field="+field, path))
+}
+
+// onePasswordProvider reads secrets using the 1Password CLI. The ref is a secret reference, like
+// `op://<vault>/<item>/<field>`.
+type onePasswordProvider struct {
+	commandRunner exec.CommandRunner
+}
+
+func newOnePasswordProvider(commandRunner exec.CommandRunner) Provider {
+	return &onePasswordProvider{
+		commandRunner: commandRunner,
+	}
+}
+
+func (p *onePasswordProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	if !strings.HasPrefix(ref, "op://") {
+		return "", fmt.Errorf("invalid 1password reference '%s', expected op://<vault>/<item>/<field>", ref)
+	}
+
+	return runSecretCommand(ctx, p.commandRunner, exec.NewRunArgs("op", "read", "--no-newline", ref))
+}
+
+// awsProvider reads secrets from AWS Secrets Manager using the aws CLI. The ref is the secret id, optionally
+// followed by `#<key>` to select a key of a secret stored as JSON.
+type awsProvider struct {
+	commandRunner exec.CommandRunner
+}
+
+func newAwsProvider(commandRunner exec.CommandRunner) Provider {
+	return &awsProvider{
+		commandRunner: commandRunner,
+	}
+}
+
+func (p *awsProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	secretId, key := splitField(ref)
+
+	value, err := runSecretCommand(ctx, p.commandRunner, exec.NewRunArgs(
+		"aws", "secretsmanager", "get-secret-value",
+		"--secret-id", secretId,
+		"--query", "SecretString",
+		"--output", "text",
+	))
+	if err != nil {
+		return "", err
+	}
+
+	if key == "" {
+		return value, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("secret '%s' is not a JSON object, it can't be referenced with #%s", secretId, key)
+	}
+
+	field, has := fields[key]
+	if !has {
+		return "", fmt.Errorf("secret '%s' has no key '%s'", secretId, key)
+	}
+
+	if s, ok := field.(string); ok {
+		return s, nil
+	}
+
+	return fmt.Sprint(field), nil
+}
+
+func runSecretCommand(ctx context.Context, commandRunner exec.CommandRunner, args exec.RunArgs) (string, error) {
+	// The output is never included in errors or logs, since it is the secret
+	res, err := commandRunner.Run(ctx, args)
+	if err != nil {
+		return "", fmt.Errorf("running %s: %s: %w", args.Cmd, strings.TrimSpace(res.Stderr), err)
+	}
+
+	return strings.TrimRight(res.Stdout, "\r\n"), nil
+}
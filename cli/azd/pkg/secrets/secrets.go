@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package secrets sources environment values from external secret stores, like HashiCorp Vault, the 1Password CLI,
+// AWS Secrets Manager or Azure Key Vault, at runtime. Resolved values are never written to the .env file of the
+// environment.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"golang.org/x/exp/maps"
+)
+
+// ProviderKind is the kind of an external secret store.
+type ProviderKind string
+
+const (
+	ProviderVault       ProviderKind = "vault"
+	ProviderOnePassword ProviderKind = "1password"
+	ProviderAws         ProviderKind = "aws"
+	ProviderKeyVault    ProviderKind = "keyvault"
+)
+
+// ErrUnknownProvider is returned when a secret references a provider which isn't supported.
+var ErrUnknownProvider = errors.New("unknown secret provider")
+
+// Reference maps an environment value to a secret of an external secret store, as declared in azure.yaml:
+//
+//	secrets:
+//	  DATABASE_PASSWORD:
+//	    provider: vault
+//	    ref: secret/myapp#password
+type Reference struct {
+	// The kind of the secret store.
+	Provider ProviderKind `yaml:"provider"`
+	// The reference to the secret, in the format of the provider.
+	Ref string `yaml:"ref"`
+}
+
+// Provider reads secrets from an external secret store.
+type Provider interface {
+	// Resolve returns the value of the secret identified by ref.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// Resolver resolves the secrets declared in azure.yaml using the provider of each secret.
+type Resolver struct {
+	providers map[ProviderKind]Provider
+	keyVault  KeyVaultSecrets
+}
+
+func NewResolver(commandRunner exec.CommandRunner, keyVault KeyVaultSecrets) *Resolver {
+	return &Resolver{
+		providers: map[ProviderKind]Provider{
+			ProviderVault:       newVaultProvider(commandRunner),
+			ProviderOnePassword: newOnePasswordProvider(commandRunner),
+			ProviderAws:         newAwsProvider(commandRunner),
+			ProviderKeyVault:    newKeyVaultProvider(keyVault, ""),
+		},
+		keyVault: keyVault,
+	}
+}
+
+// ForSubscription returns a resolver reading the Key Vault secrets with the credential of the subscription.
+func (r *Resolver) ForSubscription(subscriptionId string) *Resolver {
+	providers := maps.Clone(r.providers)
+	providers[ProviderKeyVault] = newKeyVaultProvider(r.keyVault, subscriptionId)
+
+	return &Resolver{
+		providers: providers,
+		keyVault:  r.keyVault,
+	}
+}
+
+// Resolve returns the values of the given secrets, keyed by the name of the environment value.
+func (r *Resolver) Resolve(ctx context.Context, references map[string]*Reference) (map[string]string, error) {
+	keys := make([]string, 0, len(references))
+	for key := range references {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := map[string]string{}
+	for _, key := range keys {
+		reference := references[key]
+		if reference == nil || reference.Ref == "" {
+			return nil, fmt.Errorf("secret '%s' is missing a ref", key)
+		}
+
+		provider, has := r.providers[reference.Provider]
+		if !has {
+			return nil, fmt.Errorf(
+				"secret '%s': %w '%s', supported providers are: %s",
+				key,
+				ErrUnknownProvider,
+				reference.Provider,
+				strings.Join([]string{
+					string(ProviderVault), string(ProviderOnePassword), string(ProviderAws), string(ProviderKeyVault),
+				}, ", "),
+			)
+		}
+
+		value, err := provider.Resolve(ctx, reference.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("resolving secret '%s' from %s: %w", key, reference.Provider, err)
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// splitField splits a reference of the form `<path>#<field>`, the field is empty when not specified.
+func splitField(ref string) (string, string) {
+	path, field, _ := strings.Cut(ref, "#")
+	return path, field
+}
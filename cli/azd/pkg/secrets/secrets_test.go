@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Resolver_Resolve(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return command == "vault kv get -field=password secret/myapp"
+	}).Respond(exec.NewRunResult(0, "vault-secret\n", ""))
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return command == "op read --no-newline op://dev/db/password"
+	}).Respond(exec.NewRunResult(0, "op-secret", ""))
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.HasPrefix(command, "aws secretsmanager get-secret-value --secret-id prod/db")
+	}).Respond(exec.NewRunResult(0, `{"username":"admin","port":5432}`+"\n", ""))
+
+	resolver := NewResolver(mockContext.CommandRunner, nil)
+	values, err := resolver.Resolve(*mockContext.Context, map[string]*Reference{
+		"VAULT_VALUE":    {Provider: ProviderVault, Ref: "secret/myapp#password"},
+		"OP_VALUE":       {Provider: ProviderOnePassword, Ref: "op://dev/db/password"},
+		"AWS_USERNAME":   {Provider: ProviderAws, Ref: "prod/db#username"},
+		"AWS_PORT":       {Provider: ProviderAws, Ref: "prod/db#port"},
+		"AWS_WHOLE_JSON": {Provider: ProviderAws, Ref: "prod/db"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"VAULT_VALUE":    "vault-secret",
+		"OP_VALUE":       "op-secret",
+		"AWS_USERNAME":   "admin",
+		"AWS_PORT":       "5432",
+		"AWS_WHOLE_JSON": `{"username":"admin","port":5432}`,
+	}, values)
+}
+
+func Test_Resolver_Errors(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "vault"
+	}).SetError(errors.New("permission denied"))
+
+	resolver := NewResolver(mockContext.CommandRunner, nil)
+
+	tests := map[string]struct {
+		reference *Reference
+		err       string
+	}{
+		"UnknownProvider": {&Reference{Provider: "keepass", Ref: "db"}, "unknown secret provider 'keepass'"},
+		"MissingRef":      {&Reference{Provider: ProviderVault}, "missing a ref"},
+		"VaultNoField":    {&Reference{Provider: ProviderVault, Ref: "secret/myapp"}, "expected <path>#<field>"},
+		"OnePasswordRef":  {&Reference{Provider: ProviderOnePassword, Ref: "dev/db"}, "expected op://"},
+		"CommandFailure":  {&Reference{Provider: ProviderVault, Ref: "secret/myapp#password"}, "permission denied"},
+		"KeyVaultRef":     {&Reference{Provider: ProviderKeyVault, Ref: "kv-app"}, "expected <vault>/<secret>"},
+		"NoSubscription":  {&Reference{Provider: ProviderKeyVault, Ref: "kv-app/DB-PASSWORD"}, "has no subscription"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := resolver.Resolve(*mockContext.Context, map[string]*Reference{"VALUE": test.reference})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+
+	t.Run("UnknownProviderIs", func(t *testing.T) {
+		_, err := resolver.Resolve(*mockContext.Context, map[string]*Reference{"VALUE": tests["UnknownProvider"].reference})
+		require.True(t, errors.Is(err, ErrUnknownProvider))
+	})
+}
+
+func Test_Resolver_KeyVault(t *testing.T) {
+	keyVault := &fakeKeyVault{secrets: map[string]string{"kv-app/DB-PASSWORD": "kv-secret"}}
+	resolver := NewResolver(nil, keyVault).ForSubscription("SUBSCRIPTION_ID")
+
+	values, err := resolver.Resolve(context.Background(), map[string]*Reference{
+		"DB_PASSWORD": {Provider: ProviderKeyVault, Ref: KeyVaultReference("kv-app", "DB-PASSWORD")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"DB_PASSWORD": "kv-secret"}, values)
+	require.Equal(t, "SUBSCRIPTION_ID", keyVault.subscriptionId)
+
+	_, err = resolver.Resolve(context.Background(), map[string]*Reference{
+		"API_KEY": {Provider: ProviderKeyVault, Ref: "kv-app/API-KEY"},
+	})
+	require.ErrorIs(t, err, azcli.ErrAzCliSecretNotFound)
+}
+
+func Test_KeyVaultSecretName(t *testing.T) {
+	name, err := KeyVaultSecretName("DB_PASSWORD")
+	require.NoError(t, err)
+	require.Equal(t, "DB-PASSWORD", name)
+
+	_, err = KeyVaultSecretName("db.password")
+	require.Error(t, err)
+}
+
+type fakeKeyVault struct {
+	secrets        map[string]string
+	subscriptionId string
+}
+
+func (f *fakeKeyVault) GetKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+) (*azcli.AzCliKeyVaultSecret, error) {
+	f.subscriptionId = subscriptionId
+	value, has := f.secrets[KeyVaultReference(vaultName, secretName)]
+	if !has {
+		return nil, azcli.ErrAzCliSecretNotFound
+	}
+
+	return &azcli.AzCliKeyVaultSecret{Name: secretName, Value: value}, nil
+}
@@ -0,0 +1,206 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package serve exposes the operations of azd over a local HTTP endpoint, for IDE extensions and portals which run many
+// operations and would otherwise start a process, and load the configuration and credentials of azd, for each of them.
+package serve
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+)
+
+// CommandRunner runs the azd command of the arguments, writing its output to stdout.
+type CommandRunner func(ctx context.Context, args []string, stdout io.Writer) error
+
+// OperationRequest is the body of the requests running an operation on a project.
+type OperationRequest struct {
+	// The directory of the project
+	Cwd string `json:"cwd"`
+	// The environment of the project, the default environment when empty
+	Environment string `json:"environment,omitempty"`
+	// The service to deploy, all the services when empty
+	Service string `json:"service,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type healthResponse struct {
+	Version string `json:"version"`
+}
+
+// Server serves the operations of azd to the clients holding its token. The operations run one at a time: the commands
+// of azd change the working directory of the process to the directory of the project.
+type Server struct {
+	token string
+	run   CommandRunner
+	mu    sync.Mutex
+	mux   *http.ServeMux
+}
+
+// NewServer creates a server authenticating the requests with the token and running the commands with run.
+func NewServer(token string, run CommandRunner) *Server {
+	s := &Server{
+		token: token,
+		run:   run,
+		mux:   http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/v1/health", s.handleHealth)
+	s.mux.HandleFunc("/v1/environments", s.handleEnvironments)
+	s.mux.HandleFunc("/v1/provision", s.handleProvision)
+	s.mux.HandleFunc("/v1/deploy", s.handleDeploy)
+
+	return s
+}
+
+// NewToken generates a random token for the clients of the server.
+func NewToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// ServeHTTP authenticates the request and routes it to its operation.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+
+	writeJson(w, http.StatusOK, healthResponse{Version: internal.Version})
+}
+
+// handleEnvironments lists the environments of the project of the cwd query parameter, in the format of
+// azd env list --output json.
+func (s *Server) handleEnvironments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "use GET")
+		return
+	}
+
+	cwd := r.URL.Query().Get("cwd")
+	if cwd == "" {
+		writeError(w, http.StatusBadRequest, "the cwd query parameter must be set")
+		return
+	}
+
+	if !s.mu.TryLock() {
+		writeError(w, http.StatusConflict, "another operation is running")
+		return
+	}
+	defer s.mu.Unlock()
+
+	var stdout bytes.Buffer
+	if err := s.run(r.Context(), []string{"env", "list", "--output", "json", "--cwd", cwd}, &stdout); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(stdout.Bytes())
+}
+
+func (s *Server) handleProvision(w http.ResponseWriter, r *http.Request) {
+	s.handleOperation(w, r, "provision")
+}
+
+func (s *Server) handleDeploy(w http.ResponseWriter, r *http.Request) {
+	s.handleOperation(w, r, "deploy")
+}
+
+// handleOperation runs the command in machine mode and streams its JSON-RPC notifications, one per line, as they're
+// written. The last notification is the result of the command, with its error when it failed.
+func (s *Server) handleOperation(w http.ResponseWriter, r *http.Request, command string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+
+	var request OperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("reading request: %v", err))
+		return
+	}
+
+	if request.Cwd == "" {
+		writeError(w, http.StatusBadRequest, "cwd must be set")
+		return
+	}
+
+	if !s.mu.TryLock() {
+		writeError(w, http.StatusConflict, "another operation is running")
+		return
+	}
+	defer s.mu.Unlock()
+
+	args := []string{command, "--machine", "--no-prompt", "--cwd", request.Cwd}
+	if request.Environment != "" {
+		args = append(args, "--environment", request.Environment)
+	}
+
+	if command == "deploy" {
+		if request.Service != "" {
+			args = append(args, request.Service)
+		} else {
+			args = append(args, "--all")
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	// The result notification reports the failures of the command, the error is only logged by the runner
+	_ = s.run(r.Context(), args, &flushWriter{w: w})
+}
+
+// flushWriter flushes each write to the client, so the notifications are streamed as the command writes them.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return n, err
+}
+
+func writeJson(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJson(w, status, errorResponse{Error: message})
+}
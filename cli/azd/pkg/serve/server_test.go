@@ -0,0 +1,95 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package serve
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerAuthentication(t *testing.T) {
+	server := NewServer("token", func(ctx context.Context, args []string, stdout io.Writer) error {
+		return nil
+	})
+
+	for _, header := range []string{"", "Bearer other", "token"} {
+		request := httptest.NewRequest(http.MethodGet, "/v1/health", nil)
+		request.Header.Set("Authorization", header)
+		recorder := httptest.NewRecorder()
+
+		server.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code, header)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/health", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestServerEnvironments(t *testing.T) {
+	var ranArgs []string
+	server := NewServer("token", func(ctx context.Context, args []string, stdout io.Writer) error {
+		ranArgs = args
+		_, err := stdout.Write([]byte(`[{"Name":"dev","IsDefault":true}]`))
+		return err
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/environments?cwd=/src/app", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, []string{"env", "list", "--output", "json", "--cwd", "/src/app"}, ranArgs)
+	require.JSONEq(t, `[{"Name":"dev","IsDefault":true}]`, recorder.Body.String())
+}
+
+func TestServerDeploy(t *testing.T) {
+	var ranArgs []string
+	server := NewServer("token", func(ctx context.Context, args []string, stdout io.Writer) error {
+		ranArgs = args
+		_, _ = stdout.Write([]byte(`{"jsonrpc":"2.0","method":"message","params":{"message":"Deploying"}}` + "\n"))
+		_, _ = stdout.Write([]byte(`{"jsonrpc":"2.0","method":"result","params":{"error":"failed"}}` + "\n"))
+		return errors.New("failed")
+	})
+
+	request := httptest.NewRequest(
+		http.MethodPost, "/v1/deploy", strings.NewReader(`{"cwd":"/src/app","environment":"dev","service":"api"}`))
+	request.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+	require.Equal(t,
+		[]string{"deploy", "--machine", "--no-prompt", "--cwd", "/src/app", "--environment", "dev", "api"}, ranArgs)
+	require.Len(t, strings.Split(strings.TrimSpace(recorder.Body.String()), "\n"), 2)
+	require.True(t, recorder.Flushed)
+}
+
+func TestServerOperationConflict(t *testing.T) {
+	server := NewServer("token", func(ctx context.Context, args []string, stdout io.Writer) error {
+		return nil
+	})
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/provision", strings.NewReader(`{"cwd":"/src/app"}`))
+	request.Header.Set("Authorization", "Bearer token")
+	recorder := httptest.NewRecorder()
+
+	server.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusConflict, recorder.Code)
+}
@@ -0,0 +1,255 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package shutdown coordinates the cancellation of azd when the user presses Ctrl+C or the process is terminated.
+//
+// Operations that leave state behind when interrupted (ARM deployments, temporary files, ...) register cleanup
+// functions with the Coordinator carried by their context. When a signal is received, the context is cancelled and the
+// registered cleanups run, in reverse order of registration, bounded by a grace period.
+package shutdown
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// ExitCodeCancelled is the exit code of azd when an operation is cancelled by the user (128 + SIGINT).
+	ExitCodeCancelled = 130
+
+	// DefaultGracePeriod is the time given to cleanups, and to the running command, to complete after a cancellation.
+	DefaultGracePeriod = 10 * time.Second
+)
+
+// CleanupFn releases state left behind by an interrupted operation. The context expires with the grace period.
+type CleanupFn func(ctx context.Context) error
+
+type cleanup struct {
+	id   int
+	name string
+	fn   CleanupFn
+}
+
+// Coordinator cancels the operations of azd and runs the cleanups they registered.
+type Coordinator struct {
+	gracePeriod time.Duration
+
+	mu        sync.Mutex
+	nextId    int
+	cleanups  []cleanup
+	cancelled bool
+	cancel    context.CancelFunc
+
+	cancelOnce sync.Once
+	done       chan struct{}
+}
+
+// NewCoordinator creates a Coordinator giving cleanups up to gracePeriod to complete.
+func NewCoordinator(gracePeriod time.Duration) *Coordinator {
+	return &Coordinator{
+		gracePeriod: gracePeriod,
+		done:        make(chan struct{}),
+	}
+}
+
+// Context returns a context carrying the coordinator, which is cancelled when the coordinator is cancelled.
+func (c *Coordinator) Context(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(WithCoordinator(parent, c))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancel = cancel
+
+	return ctx
+}
+
+// Register registers a cleanup to run when the coordinator is cancelled. The returned function unregisters the
+// cleanup, and must be called once the operation completes and there is nothing left to clean up.
+func (c *Coordinator) Register(name string, fn CleanupFn) (unregister func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	id := c.nextId
+	c.nextId++
+	c.cleanups = append(c.cleanups, cleanup{id: id, name: name, fn: fn})
+
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, registered := range c.cleanups {
+			if registered.id == id {
+				c.cleanups = append(c.cleanups[:i], c.cleanups[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Cancelled returns true when the coordinator has been cancelled.
+func (c *Coordinator) Cancelled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cancelled
+}
+
+// Cancel cancels the context of the coordinator and runs the registered cleanups, in reverse order of registration.
+// Cleanups are given up to the grace period to complete, errors are logged. Only the first call has any effect.
+func (c *Coordinator) Cancel() {
+	c.cancelOnce.Do(func() {
+		defer close(c.done)
+
+		// The cleanups are captured before cancelling the context, since operations unregister their cleanups as they
+		// return, which happens as soon as the context is cancelled.
+		c.mu.Lock()
+		c.cancelled = true
+		cleanups := make([]cleanup, len(c.cleanups))
+		copy(cleanups, c.cleanups)
+		cancel := c.cancel
+		c.mu.Unlock()
+
+		if cancel != nil {
+			cancel()
+		}
+
+		ctx, cancelCleanups := context.WithTimeout(context.Background(), c.gracePeriod)
+		defer cancelCleanups()
+
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			log.Printf("running cleanup '%s'", cleanups[i].name)
+			if err := cleanups[i].fn(ctx); err != nil {
+				log.Printf("cleanup '%s' failed: %v", cleanups[i].name, err)
+			}
+		}
+	})
+}
+
+// Wait blocks until the cleanups started by Cancel complete. Returns immediately when the coordinator has not been
+// cancelled.
+func (c *Coordinator) Wait() {
+	if !c.Cancelled() {
+		return
+	}
+
+	<-c.done
+}
+
+// exit terminates the process, replaced in tests.
+var exit = os.Exit
+
+var (
+	exitHooksMu    sync.Mutex
+	exitHooks      = map[int]func(){}
+	nextExitHookId int
+)
+
+// OnExit registers fn to run when azd exits after a cancellation without returning from the running command, on a
+// second signal or at the end of the grace period, like restoring the modes of the terminal changed by an interactive
+// command. The returned function unregisters fn.
+func OnExit(fn func()) (unregister func()) {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+
+	id := nextExitHookId
+	nextExitHookId++
+	exitHooks[id] = fn
+
+	return func() {
+		exitHooksMu.Lock()
+		defer exitHooksMu.Unlock()
+		delete(exitHooks, id)
+	}
+}
+
+// exitCancelled runs the exit hooks and exits with ExitCodeCancelled.
+func exitCancelled() {
+	exitHooksMu.Lock()
+	hooks := make([]func(), 0, len(exitHooks))
+	for _, hook := range exitHooks {
+		hooks = append(hooks, hook)
+	}
+	exitHooksMu.Unlock()
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	exit(ExitCodeCancelled)
+}
+
+// NotifySignals cancels the coordinator when SIGINT or SIGTERM is received, after calling onCancel. The process exits
+// with ExitCodeCancelled when a second signal is received, or when the command has not completed within the grace
+// period once cleanups have run. The returned function stops listening for signals.
+func (c *Coordinator) NotifySignals(onCancel func()) (stop func()) {
+	signals := make(chan os.Signal, 2)
+	stopped := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+		case <-stopped:
+			return
+		}
+
+		if onCancel != nil {
+			onCancel()
+		}
+
+		go func() {
+			select {
+			case <-signals:
+				log.Println("received a second signal, exiting immediately")
+				exitCancelled()
+			case <-stopped:
+			}
+		}()
+
+		c.Cancel()
+
+		select {
+		case <-time.After(c.gracePeriod):
+			log.Println("the command did not complete within the grace period after cancellation, exiting")
+			exitCancelled()
+		case <-stopped:
+		}
+	}()
+
+	var stopOnce sync.Once
+	return func() {
+		stopOnce.Do(func() {
+			signal.Stop(signals)
+			close(stopped)
+		})
+	}
+}
+
+type contextKey struct{}
+
+// WithCoordinator returns a context carrying the specified coordinator.
+func WithCoordinator(ctx context.Context, c *Coordinator) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the coordinator carried by ctx, or nil when there is none.
+func FromContext(ctx context.Context) *Coordinator {
+	c, _ := ctx.Value(contextKey{}).(*Coordinator)
+	return c
+}
+
+// Register registers a cleanup with the coordinator carried by ctx. When ctx does not carry a coordinator, for example
+// in tests, the cleanup is never run.
+func Register(ctx context.Context, name string, fn CleanupFn) (unregister func()) {
+	c := FromContext(ctx)
+	if c == nil {
+		return func() {}
+	}
+
+	return c.Register(name, fn)
+}
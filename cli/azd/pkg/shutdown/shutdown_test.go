@@ -0,0 +1,88 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Coordinator_Cancel(t *testing.T) {
+	coordinator := NewCoordinator(time.Second)
+	ctx := coordinator.Context(context.Background())
+
+	var ran []string
+	Register(ctx, "first", func(ctx context.Context) error {
+		ran = append(ran, "first")
+		return nil
+	})
+	unregister := Register(ctx, "unregistered", func(ctx context.Context) error {
+		ran = append(ran, "unregistered")
+		return nil
+	})
+	Register(ctx, "failing", func(ctx context.Context) error {
+		ran = append(ran, "failing")
+		return errors.New("cleanup failed")
+	})
+	Register(ctx, "last", func(ctx context.Context) error {
+		ran = append(ran, "last")
+		return nil
+	})
+
+	unregister()
+	require.False(t, coordinator.Cancelled())
+
+	coordinator.Cancel()
+	coordinator.Cancel()
+	coordinator.Wait()
+
+	require.True(t, coordinator.Cancelled())
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	// cleanups run in reverse order of registration, even when one of them fails
+	require.Equal(t, []string{"last", "failing", "first"}, ran)
+}
+
+func Test_Coordinator_NotCancelled(t *testing.T) {
+	coordinator := NewCoordinator(time.Second)
+	ctx := coordinator.Context(context.Background())
+
+	ran := false
+	Register(ctx, "cleanup", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+
+	// Wait returns immediately and cleanups are not run when the coordinator is not cancelled
+	coordinator.Wait()
+
+	require.False(t, ran)
+	require.NoError(t, ctx.Err())
+}
+
+func Test_Register_WithoutCoordinator(t *testing.T) {
+	unregister := Register(context.Background(), "cleanup", func(ctx context.Context) error {
+		return nil
+	})
+
+	require.NotNil(t, unregister)
+	unregister()
+}
+
+func Test_ExitCancelled_RunsExitHooks(t *testing.T) {
+	exitCode := -1
+	exit = func(code int) { exitCode = code }
+	t.Cleanup(func() { exit = os.Exit })
+
+	var ran []string
+	OnExit(func() { ran = append(ran, "registered") })
+	unregister := OnExit(func() { ran = append(ran, "unregistered") })
+	unregister()
+
+	exitCancelled()
+
+	require.Equal(t, []string{"registered"}, ran)
+	require.Equal(t, ExitCodeCancelled, exitCode)
+}
@@ -0,0 +1,284 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package support creates support bundles, zip files capturing the state of azd to be attached to issues. A bundle is
+// written when azd crashes, and on demand with `azd support bundle`.
+//
+// Bundles are meant to be shared: secrets found in logs are redacted, and only the names of the environment variables
+// used by azd are captured, never their values.
+package support
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+const (
+	// IssueUrl is where users file issues, attaching support bundles.
+	IssueUrl = "https://github.com/Azure/azure-dev/issues/new/choose"
+
+	// logTailSize is the number of bytes, from the end of the log file, included in a bundle.
+	logTailSize = 256 * 1024
+
+	// toolVersionTimeout bounds the time spent getting the version of a single tool.
+	toolVersionTimeout = 5 * time.Second
+
+	bundlesDir = "support"
+
+	// The timestamp in the file names sorts in chronological order.
+	timestampFormat = "20060102T150405"
+)
+
+// The prefixes of the environment variables captured in a bundle, which are the ones read by azd and the tools it
+// runs. Only names are captured.
+var environmentPrefixes = []string{"AZD_", "AZURE_", "ARM_", "DOCKER_", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY"}
+
+// The tools whose version is captured, along with the argument printing their version.
+var bundleTools = []struct {
+	name string
+	args []string
+}{
+	{"az", []string{"version", "--output", "json"}},
+	{"bicep", []string{"--version"}},
+	{"docker", []string{"--version"}},
+	{"dotnet", []string{"--version"}},
+	{"gh", []string{"--version"}},
+	{"git", []string{"--version"}},
+	{"java", []string{"-version"}},
+	{"kubectl", []string{"version", "--client"}},
+	{"mvn", []string{"--version"}},
+	{"node", []string{"--version"}},
+	{"npm", []string{"--version"}},
+	{"python3", []string{"--version"}},
+	{"terraform", []string{"--version"}},
+}
+
+// Crash describes a panic of azd.
+type Crash struct {
+	// The value passed to panic.
+	Value any
+	// The stack trace of the panicking goroutine.
+	Stack []byte
+}
+
+// Bundle is the content of a support bundle.
+type Bundle struct {
+	// The crash which caused the bundle to be created, nil for bundles created on demand.
+	Crash *Crash
+	// The path of the log file whose tail is included, may be empty.
+	LogPath string
+	// The version of each installed tool, keyed by tool name.
+	ToolVersions map[string]string
+}
+
+// Metadata describes the installation of azd a bundle was created on.
+type Metadata struct {
+	Timestamp time.Time `json:"timestamp"`
+	Version   string    `json:"version"`
+	Platform  string    `json:"platform"`
+	GoVersion string    `json:"goVersion"`
+	// The command line arguments, with secrets redacted.
+	Args []string `json:"args"`
+	// The names of the environment variables set, values are never captured.
+	Environment []string `json:"environment"`
+}
+
+// DefaultPath returns the path of a new bundle in the support directory of the azd user configuration directory.
+func DefaultPath(now time.Time) (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(configDir, bundlesDir)
+	if err := os.MkdirAll(dir, osutil.PermissionDirectoryOwnerOnly); err != nil {
+		return "", fmt.Errorf("creating support directory: %w", err)
+	}
+
+	return filepath.Join(dir, fmt.Sprintf("azd-support-%s.zip", now.Format(timestampFormat))), nil
+}
+
+// ToolVersions returns the version reported by each installed tool. Tools which are not installed are skipped.
+func ToolVersions(ctx context.Context, commandRunner exec.CommandRunner) map[string]string {
+	versions := map[string]string{}
+
+	for _, tool := range bundleTools {
+		if err := tools.ToolInPath(tool.name); err != nil {
+			continue
+		}
+
+		toolCtx, cancel := context.WithTimeout(ctx, toolVersionTimeout)
+		res, err := commandRunner.Run(toolCtx, exec.NewRunArgs(tool.name, tool.args...))
+		cancel()
+
+		if err != nil {
+			versions[tool.name] = fmt.Sprintf("error: %v", err)
+			continue
+		}
+
+		// Some tools, like java, print their version to stderr
+		versions[tool.name] = strings.TrimSpace(res.Stdout + res.Stderr)
+	}
+
+	return versions
+}
+
+// Write creates the support bundle at path.
+func Write(path string, bundle Bundle) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, osutil.PermissionFileOwnerOnly)
+	if err != nil {
+		return fmt.Errorf("creating support bundle: %w", err)
+	}
+	defer file.Close()
+
+	zipWriter := zip.NewWriter(file)
+
+	if err := writeJson(zipWriter, "metadata.json", newMetadata(time.Now())); err != nil {
+		return err
+	}
+
+	if bundle.Crash != nil {
+		crash := fmt.Sprintf("panic: %v\n\n%s", bundle.Crash.Value, bundle.Crash.Stack)
+		if err := writeEntry(zipWriter, "crash.txt", Redact(crash)); err != nil {
+			return err
+		}
+	}
+
+	if bundle.LogPath != "" {
+		tail, err := readTail(bundle.LogPath, logTailSize)
+		if err != nil {
+			tail = fmt.Sprintf("failed to read %s: %v", bundle.LogPath, err)
+		}
+
+		if err := writeEntry(zipWriter, "azd.log", Redact(tail)); err != nil {
+			return err
+		}
+	}
+
+	if bundle.ToolVersions != nil {
+		if err := writeJson(zipWriter, "tools.json", bundle.ToolVersions); err != nil {
+			return err
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("writing support bundle: %w", err)
+	}
+
+	return file.Close()
+}
+
+func newMetadata(now time.Time) Metadata {
+	args := make([]string, 0, len(os.Args))
+	for _, arg := range os.Args[1:] {
+		args = append(args, Redact(arg))
+	}
+
+	environment := []string{}
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, prefix := range environmentPrefixes {
+			if strings.HasPrefix(strings.ToUpper(name), prefix) {
+				environment = append(environment, name)
+				break
+			}
+		}
+	}
+	sort.Strings(environment)
+
+	return Metadata{
+		Timestamp:   now,
+		Version:     internal.Version,
+		Platform:    fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		GoVersion:   runtime.Version(),
+		Args:        args,
+		Environment: environment,
+	}
+}
+
+func writeJson(zipWriter *zip.Writer, name string, value any) error {
+	contents, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling %s: %w", name, err)
+	}
+
+	return writeEntry(zipWriter, name, string(contents))
+}
+
+func writeEntry(zipWriter *zip.Writer, name string, contents string) error {
+	entry, err := zipWriter.Create(name)
+	if err != nil {
+		return fmt.Errorf("adding %s to support bundle: %w", name, err)
+	}
+
+	if _, err := io.WriteString(entry, contents); err != nil {
+		return fmt.Errorf("writing %s to support bundle: %w", name, err)
+	}
+
+	return nil
+}
+
+// readTail reads up to size bytes from the end of the file.
+func readTail(path string, size int64) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if info.Size() > size {
+		if _, err := file.Seek(-size, io.SeekEnd); err != nil {
+			return "", err
+		}
+	}
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	return string(contents), nil
+}
+
+var redactRules = []struct {
+	match   *regexp.Regexp
+	replace string
+}{
+	// JSON web tokens, like Azure access tokens
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), "<redacted>"},
+	{regexp.MustCompile(`(?i)(bearer\s+)\S+`), "$1<redacted>"},
+	{regexp.MustCompile(`(?i)("(?:accessToken|password|secret|clientSecret|connectionString)"\s*:\s*)"[^"]*"`),
+		`$1"<redacted>"`},
+	{regexp.MustCompile(`(?i)(--(?:password|deployment-token|client-secret|username)[ =])\S+`), "$1<redacted>"},
+	{regexp.MustCompile(`(?i)((?:AccountKey|SharedAccessKey|Password|sig)=)[^;&\s]+`), "$1<redacted>"},
+}
+
+// Redact replaces the secrets found in text, like access tokens, passwords and keys of connection strings.
+func Redact(text string) string {
+	for _, rule := range redactRules {
+		text = rule.match.ReplaceAllString(text, rule.replace)
+	}
+
+	return text
+}
@@ -0,0 +1,91 @@
+package support
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Redact(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"Jwt", "token eyJhbGciOi.eyJzdWIiOi.c2lnbmF0dXJl used", "token <redacted> used"},
+		{"Bearer", "Authorization: Bearer abc123", "Authorization: Bearer <redacted>"},
+		{"Json", `{"accessToken": "abc", "expiresOn": "2023"}`, `{"accessToken": "<redacted>", "expiresOn": "2023"}`},
+		{"Flag", "docker login --password hunter2 --username me", "docker login --password <redacted> --username <redacted>"},
+		{
+			"ConnectionString",
+			"Endpoint=sb://ns/;SharedAccessKeyName=root;SharedAccessKey=abc=;EntityPath=q",
+			"Endpoint=sb://ns/;SharedAccessKeyName=root;SharedAccessKey=<redacted>;EntityPath=q",
+		},
+		{"None", "deploying service api", "deploying service api"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, Redact(test.text))
+		})
+	}
+}
+
+func Test_Write(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "azd.log")
+	log := strings.Repeat("x", logTailSize) + "\nrequest with Bearer secret-token\n"
+	require.NoError(t, os.WriteFile(logPath, []byte(log), 0600))
+
+	t.Setenv("AZD_TEST_SECRET", "secret-value")
+
+	bundlePath := filepath.Join(dir, "bundle.zip")
+	err := Write(bundlePath, Bundle{
+		Crash:        &Crash{Value: "boom", Stack: []byte("goroutine 1 [running]:")},
+		LogPath:      logPath,
+		ToolVersions: map[string]string{"git": "git version 2.40.0"},
+	})
+	require.NoError(t, err)
+
+	entries := readBundle(t, bundlePath)
+	require.Len(t, entries, 4)
+
+	require.Contains(t, entries["crash.txt"], "panic: boom")
+	require.Contains(t, entries["crash.txt"], "goroutine 1 [running]:")
+
+	// Only the tail of the log is included, and secrets are redacted
+	require.Len(t, entries["azd.log"], logTailSize-len("secret-token")+len("<redacted>"))
+	require.Contains(t, entries["azd.log"], "Bearer <redacted>")
+	require.NotContains(t, entries["azd.log"], "secret-token")
+
+	require.Contains(t, entries["tools.json"], "git version 2.40.0")
+
+	// Names of environment variables are captured, values are not
+	require.Contains(t, entries["metadata.json"], "AZD_TEST_SECRET")
+	require.NotContains(t, entries["metadata.json"], "secret-value")
+}
+
+func readBundle(t *testing.T, path string) map[string]string {
+	reader, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	entries := map[string]string{}
+	for _, file := range reader.File {
+		entry, err := file.Open()
+		require.NoError(t, err)
+
+		contents, err := io.ReadAll(entry)
+		require.NoError(t, err)
+		entry.Close()
+
+		entries[file.Name] = string(contents)
+	}
+
+	return entries
+}
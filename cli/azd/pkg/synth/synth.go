@@ -0,0 +1,586 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package synth generates baseline Bicep infrastructure from the services declared in azure.yaml, for projects without
+// infrastructure: the App Service plan, Function Apps or Container Apps environment hosting the services, with
+// monitoring and a Key Vault.
+package synth
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// The infrastructure files written by Generate, relative to the infrastructure directory
+const (
+	mainBicepFile      = "main.bicep"
+	resourcesBicepFile = "resources.bicep"
+	parametersFile     = "main.parameters.json"
+)
+
+// The runtime stacks of the App Services, by language of the service
+var appServiceStacks = map[project.ServiceLanguageKind]string{
+	project.ServiceLanguageDotNet:     "DOTNETCORE|8.0",
+	project.ServiceLanguageCsharp:     "DOTNETCORE|8.0",
+	project.ServiceLanguageFsharp:     "DOTNETCORE|8.0",
+	project.ServiceLanguageJavaScript: "NODE|20-lts",
+	project.ServiceLanguageTypeScript: "NODE|20-lts",
+	project.ServiceLanguagePython:     "PYTHON|3.11",
+	project.ServiceLanguageJava:       "JAVA|17-java17",
+}
+
+// The worker runtimes and runtime stacks of the Function Apps, by language of the service
+var functionRuntimes = map[project.ServiceLanguageKind][2]string{
+	project.ServiceLanguageDotNet:     {"dotnet-isolated", "DOTNET-ISOLATED|8.0"},
+	project.ServiceLanguageCsharp:     {"dotnet-isolated", "DOTNET-ISOLATED|8.0"},
+	project.ServiceLanguageFsharp:     {"dotnet-isolated", "DOTNET-ISOLATED|8.0"},
+	project.ServiceLanguageJavaScript: {"node", "Node|20"},
+	project.ServiceLanguageTypeScript: {"node", "Node|20"},
+	project.ServiceLanguagePython:     {"python", "Python|3.11"},
+	project.ServiceLanguageJava:       {"java", "Java|17"},
+	// Go functions are custom handlers, running the binary of the service
+	project.ServiceLanguageGo: {"custom", ""},
+}
+
+// app is a resource hosting a service in resources.bicep.
+type app struct {
+	// The symbolic name of the resource
+	Identifier string
+	// The name of the service, converted to a valid resource name
+	Name        string
+	ServiceName string
+	// The runtime stack of App Services and Function Apps, like PYTHON|3.11
+	Stack string
+	// The worker runtime of Function Apps, like python
+	WorkerRuntime string
+}
+
+// unsupportedService is a service whose host has no generated infrastructure.
+type unsupportedService struct {
+	Name string
+	Host project.ServiceTargetKind
+}
+
+// resourcesBicep is the content of resources.bicep.
+type resourcesBicep struct {
+	AppServices   []app
+	Functions     []app
+	ContainerApps []app
+	StaticWebApps []app
+	Unsupported   []unsupportedService
+}
+
+// Generate writes the Bicep infrastructure of the services of the project to infraDir. It returns os.ErrExist when
+// infraDir already exists, without writing any file.
+func Generate(infraDir string, projectConfig *project.ProjectConfig) error {
+	if _, err := os.Stat(infraDir); err == nil {
+		return fmt.Errorf("%s: %w", infraDir, os.ErrExist)
+	}
+
+	bicep, err := newResourcesBicep(projectConfig)
+	if err != nil {
+		return err
+	}
+
+	var resourcesContent bytes.Buffer
+	if err := resourcesTemplate.Execute(&resourcesContent, bicep); err != nil {
+		return fmt.Errorf("generating %s: %w", resourcesBicepFile, err)
+	}
+
+	var mainContent bytes.Buffer
+	if err := mainTemplate.Execute(&mainContent, bicep); err != nil {
+		return fmt.Errorf("generating %s: %w", mainBicepFile, err)
+	}
+
+	files := map[string][]byte{
+		mainBicepFile:      mainContent.Bytes(),
+		resourcesBicepFile: resourcesContent.Bytes(),
+		parametersFile:     []byte(mainParameters),
+	}
+
+	if err := os.MkdirAll(infraDir, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating %s: %w", infraDir, err)
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(infraDir, name), content, osutil.PermissionFile); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func newResourcesBicep(projectConfig *project.ProjectConfig) (*resourcesBicep, error) {
+	bicep := &resourcesBicep{}
+	names := map[string]string{}
+
+	serviceNames := maps.Keys(projectConfig.Services)
+	slices.Sort(serviceNames)
+
+	for _, serviceName := range serviceNames {
+		service := projectConfig.Services[serviceName]
+
+		name := resourceName(serviceName)
+		if other, has := names[name]; has {
+			return nil, fmt.Errorf(
+				"the name of service '%s' conflicts with service '%s' once converted to a resource name", serviceName, other)
+		}
+		names[name] = serviceName
+
+		hosted := app{
+			Identifier:  strings.ReplaceAll(name, "-", "_"),
+			Name:        name,
+			ServiceName: bicepString(serviceName),
+		}
+
+		switch service.Host {
+		case project.AppServiceTarget:
+			hosted.Stack = appServiceStacks[service.Language]
+			bicep.AppServices = append(bicep.AppServices, hosted)
+		case project.AzureFunctionTarget:
+			runtime, has := functionRuntimes[service.Language]
+			if !has {
+				return nil, fmt.Errorf(
+					"service '%s': the language '%s' isn't supported by Function Apps", serviceName, service.Language)
+			}
+			hosted.WorkerRuntime, hosted.Stack = runtime[0], runtime[1]
+			bicep.Functions = append(bicep.Functions, hosted)
+		case project.ContainerAppTarget:
+			bicep.ContainerApps = append(bicep.ContainerApps, hosted)
+		case project.StaticWebAppTarget:
+			bicep.StaticWebApps = append(bicep.StaticWebApps, hosted)
+		default:
+			bicep.Unsupported = append(bicep.Unsupported, unsupportedService{Name: serviceName, Host: service.Host})
+		}
+	}
+
+	return bicep, nil
+}
+
+// bicepString escapes a value for a single quoted Bicep string.
+func bicepString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, "${", `\${`).Replace(value)
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// resourceName converts the name of a service to a part of the names of its resources, which has lowercase letters,
+// numbers and dashes, starts with a letter and has at most 14 characters, so the names of container apps, made of the
+// name and a 13 characters token, are at most 32 characters.
+func resourceName(serviceName string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(serviceName), "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		name = "app"
+	} else if name[0] < 'a' || name[0] > 'z' {
+		name = "app-" + name
+	}
+
+	if len(name) > 14 {
+		name = strings.TrimRight(name[:14], "-")
+	}
+
+	return name
+}
+
+var resourcesTemplate = template.Must(template.New("resources").Parse(`// Generated by azd infra synth from azure.yaml
+param location string
+param tags object
+@description('Id of the user or app to grant access to the secrets of the Key Vault')
+param principalId string = ''
+
+var resourceToken = toLower(uniqueString(resourceGroup().id))
+
+// Key Vault Secrets User
+var secretsUserRoleId = '4633458b-17de-408a-b874-0445c86b69e6'
+// Key Vault Secrets Officer
+var secretsOfficerRoleId = 'b86a8fe4-44ce-4948-aee5-eccb2c155cd7'
+{{- range .Unsupported}}
+// The service '{{.Name}}' is hosted by '{{.Host}}', add its resources by hand, tagged with 'azd-service-name': '{{.Name}}'
+{{- end}}
+
+resource logAnalytics 'Microsoft.OperationalInsights/workspaces@2022-10-01' = {
+  name: 'log-${resourceToken}'
+  location: location
+  tags: tags
+  properties: {
+    sku: {
+      name: 'PerGB2018'
+    }
+  }
+}
+
+resource appInsights 'Microsoft.Insights/components@2020-02-02' = {
+  name: 'appi-${resourceToken}'
+  location: location
+  tags: tags
+  kind: 'web'
+  properties: {
+    Application_Type: 'web'
+    WorkspaceResourceId: logAnalytics.id
+  }
+}
+
+resource keyVault 'Microsoft.KeyVault/vaults@2022-07-01' = {
+  name: 'kv-${resourceToken}'
+  location: location
+  tags: tags
+  properties: {
+    tenantId: subscription().tenantId
+    sku: {
+      family: 'A'
+      name: 'standard'
+    }
+    enableRbacAuthorization: true
+  }
+}
+
+resource keyVaultPrincipalAccess 'Microsoft.Authorization/roleAssignments@2022-04-01' = if (!empty(principalId)) {
+  name: guid(keyVault.id, principalId, secretsOfficerRoleId)
+  scope: keyVault
+  properties: {
+    principalId: principalId
+    roleDefinitionId: subscriptionResourceId('Microsoft.Authorization/roleDefinitions', secretsOfficerRoleId)
+  }
+}
+{{- if .AppServices}}
+
+resource appServicePlan 'Microsoft.Web/serverfarms@2022-03-01' = {
+  name: 'plan-${resourceToken}'
+  location: location
+  tags: tags
+  kind: 'linux'
+  sku: {
+    name: 'B1'
+  }
+  properties: {
+    reserved: true
+  }
+}
+{{- end}}
+{{- range .AppServices}}
+
+resource {{.Identifier}}App 'Microsoft.Web/sites@2022-03-01' = {
+  name: 'app-{{.Name}}-${resourceToken}'
+  location: location
+  tags: union(tags, { 'azd-service-name': '{{.ServiceName}}' })
+  kind: 'app,linux'
+  identity: {
+    type: 'SystemAssigned'
+  }
+  properties: {
+    serverFarmId: appServicePlan.id
+    httpsOnly: true
+    siteConfig: {
+{{- if .Stack}}
+      linuxFxVersion: '{{.Stack}}'
+{{- end}}
+      alwaysOn: true
+      ftpsState: 'FtpsOnly'
+      minTlsVersion: '1.2'
+      appSettings: [
+        {
+          name: 'APPLICATIONINSIGHTS_CONNECTION_STRING'
+          value: appInsights.properties.ConnectionString
+        }
+        {
+          name: 'AZURE_KEY_VAULT_ENDPOINT'
+          value: keyVault.properties.vaultUri
+        }
+        {
+          name: 'SCM_DO_BUILD_DURING_DEPLOYMENT'
+          value: 'true'
+        }
+      ]
+    }
+  }
+}
+
+resource {{.Identifier}}KeyVaultAccess 'Microsoft.Authorization/roleAssignments@2022-04-01' = {
+  name: guid(keyVault.id, {{.Identifier}}App.id, secretsUserRoleId)
+  scope: keyVault
+  properties: {
+    principalId: {{.Identifier}}App.identity.principalId
+    principalType: 'ServicePrincipal'
+    roleDefinitionId: subscriptionResourceId('Microsoft.Authorization/roleDefinitions', secretsUserRoleId)
+  }
+}
+{{- end}}
+{{- if .Functions}}
+
+resource functionsStorage 'Microsoft.Storage/storageAccounts@2022-09-01' = {
+  name: 'st${resourceToken}'
+  location: location
+  tags: tags
+  kind: 'StorageV2'
+  sku: {
+    name: 'Standard_LRS'
+  }
+  properties: {
+    minimumTlsVersion: 'TLS1_2'
+    allowBlobPublicAccess: false
+  }
+}
+
+resource functionsPlan 'Microsoft.Web/serverfarms@2022-03-01' = {
+  name: 'plan-func-${resourceToken}'
+  location: location
+  tags: tags
+  kind: 'functionapp'
+  sku: {
+    name: 'Y1'
+    tier: 'Dynamic'
+  }
+  properties: {
+    reserved: true
+  }
+}
+
+var functionsStorageConnectionString = join([
+  'DefaultEndpointsProtocol=https'
+  'AccountName=${functionsStorage.name}'
+  'AccountKey=${functionsStorage.listKeys().keys[0].value}'
+  'EndpointSuffix=${environment().suffixes.storage}'
+], ';')
+{{- end}}
+{{- range .Functions}}
+
+resource {{.Identifier}}Func 'Microsoft.Web/sites@2022-03-01' = {
+  name: 'func-{{.Name}}-${resourceToken}'
+  location: location
+  tags: union(tags, { 'azd-service-name': '{{.ServiceName}}' })
+  kind: 'functionapp,linux'
+  identity: {
+    type: 'SystemAssigned'
+  }
+  properties: {
+    serverFarmId: functionsPlan.id
+    httpsOnly: true
+    siteConfig: {
+      linuxFxVersion: '{{.Stack}}'
+      ftpsState: 'FtpsOnly'
+      minTlsVersion: '1.2'
+      appSettings: [
+        {
+          name: 'AzureWebJobsStorage'
+          value: functionsStorageConnectionString
+        }
+        {
+          name: 'FUNCTIONS_EXTENSION_VERSION'
+          value: '~4'
+        }
+        {
+          name: 'FUNCTIONS_WORKER_RUNTIME'
+          value: '{{.WorkerRuntime}}'
+        }
+        {
+          name: 'APPLICATIONINSIGHTS_CONNECTION_STRING'
+          value: appInsights.properties.ConnectionString
+        }
+        {
+          name: 'AZURE_KEY_VAULT_ENDPOINT'
+          value: keyVault.properties.vaultUri
+        }
+      ]
+    }
+  }
+}
+
+resource {{.Identifier}}KeyVaultAccess 'Microsoft.Authorization/roleAssignments@2022-04-01' = {
+  name: guid(keyVault.id, {{.Identifier}}Func.id, secretsUserRoleId)
+  scope: keyVault
+  properties: {
+    principalId: {{.Identifier}}Func.identity.principalId
+    principalType: 'ServicePrincipal'
+    roleDefinitionId: subscriptionResourceId('Microsoft.Authorization/roleDefinitions', secretsUserRoleId)
+  }
+}
+{{- end}}
+{{- if .ContainerApps}}
+
+resource containerAppsEnvironment 'Microsoft.App/managedEnvironments@2023-05-01' = {
+  name: 'cae-${resourceToken}'
+  location: location
+  tags: tags
+  properties: {
+    appLogsConfiguration: {
+      destination: 'log-analytics'
+      logAnalyticsConfiguration: {
+        customerId: logAnalytics.properties.customerId
+        sharedKey: logAnalytics.listKeys().primarySharedKey
+      }
+    }
+  }
+}
+
+resource containerRegistry 'Microsoft.ContainerRegistry/registries@2023-07-01' = {
+  name: 'cr${resourceToken}'
+  location: location
+  tags: tags
+  sku: {
+    name: 'Basic'
+  }
+  properties: {
+    adminUserEnabled: true
+  }
+}
+{{- end}}
+{{- range .ContainerApps}}
+
+resource {{.Identifier}}ContainerApp 'Microsoft.App/containerApps@2023-05-01' = {
+  name: 'ca-{{.Name}}-${resourceToken}'
+  location: location
+  tags: union(tags, { 'azd-service-name': '{{.ServiceName}}' })
+  identity: {
+    type: 'SystemAssigned'
+  }
+  properties: {
+    managedEnvironmentId: containerAppsEnvironment.id
+    configuration: {
+      ingress: {
+        external: true
+        targetPort: 80
+      }
+      registries: [
+        {
+          server: containerRegistry.properties.loginServer
+          username: containerRegistry.listCredentials().username
+          passwordSecretRef: 'registry-password'
+        }
+      ]
+      secrets: [
+        {
+          name: 'registry-password'
+          value: containerRegistry.listCredentials().passwords[0].value
+        }
+      ]
+    }
+    template: {
+      containers: [
+        {
+          name: 'main'
+          // Replaced by the image of the service on azd deploy
+          image: 'mcr.microsoft.com/azuredocs/containerapps-helloworld:latest'
+          env: [
+            {
+              name: 'APPLICATIONINSIGHTS_CONNECTION_STRING'
+              value: appInsights.properties.ConnectionString
+            }
+            {
+              name: 'AZURE_KEY_VAULT_ENDPOINT'
+              value: keyVault.properties.vaultUri
+            }
+          ]
+        }
+      ]
+    }
+  }
+}
+
+resource {{.Identifier}}KeyVaultAccess 'Microsoft.Authorization/roleAssignments@2022-04-01' = {
+  name: guid(keyVault.id, {{.Identifier}}ContainerApp.id, secretsUserRoleId)
+  scope: keyVault
+  properties: {
+    principalId: {{.Identifier}}ContainerApp.identity.principalId
+    principalType: 'ServicePrincipal'
+    roleDefinitionId: subscriptionResourceId('Microsoft.Authorization/roleDefinitions', secretsUserRoleId)
+  }
+}
+{{- end}}
+{{- if .StaticWebApps}}
+
+// Static Web Apps are available in a subset of the regions
+var staticWebAppLocations = [ 'centralus', 'eastasia', 'eastus2', 'westeurope', 'westus2' ]
+var staticWebAppLocation = contains(staticWebAppLocations, location) ? location : 'eastus2'
+{{- end}}
+{{- range .StaticWebApps}}
+
+resource {{.Identifier}}StaticWebApp 'Microsoft.Web/staticSites@2022-03-01' = {
+  name: 'stapp-{{.Name}}-${resourceToken}'
+  location: staticWebAppLocation
+  tags: union(tags, { 'azd-service-name': '{{.ServiceName}}' })
+  sku: {
+    name: 'Free'
+    tier: 'Free'
+  }
+  properties: {}
+}
+{{- end}}
+
+output APPLICATIONINSIGHTS_CONNECTION_STRING string = appInsights.properties.ConnectionString
+output AZURE_KEY_VAULT_ENDPOINT string = keyVault.properties.vaultUri
+output AZURE_KEY_VAULT_NAME string = keyVault.name
+{{- if .ContainerApps}}
+output AZURE_CONTAINER_REGISTRY_ENDPOINT string = containerRegistry.properties.loginServer
+{{- end}}
+`))
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Generated by azd infra synth from azure.yaml
+targetScope = 'subscription'
+
+@minLength(1)
+@maxLength(64)
+@description('Name of the environment which is used to generate a short unique hash used in all resources.')
+param environmentName string
+
+@minLength(1)
+@description('Primary location for all resources')
+param location string
+
+@description('Id of the user or app to grant access to the secrets of the Key Vault')
+param principalId string = ''
+
+var tags = { 'azd-env-name': environmentName }
+
+resource rg 'Microsoft.Resources/resourceGroups@2022-09-01' = {
+  name: 'rg-${environmentName}'
+  location: location
+  tags: tags
+}
+
+module resources 'resources.bicep' = {
+  name: 'resources'
+  scope: rg
+  params: {
+    location: location
+    tags: tags
+    principalId: principalId
+  }
+}
+
+output AZURE_RESOURCE_GROUP string = rg.name
+output APPLICATIONINSIGHTS_CONNECTION_STRING string = resources.outputs.APPLICATIONINSIGHTS_CONNECTION_STRING
+output AZURE_KEY_VAULT_ENDPOINT string = resources.outputs.AZURE_KEY_VAULT_ENDPOINT
+output AZURE_KEY_VAULT_NAME string = resources.outputs.AZURE_KEY_VAULT_NAME
+{{- if .ContainerApps}}
+output AZURE_CONTAINER_REGISTRY_ENDPOINT string = resources.outputs.AZURE_CONTAINER_REGISTRY_ENDPOINT
+{{- end}}
+`))
+
+const mainParameters = `{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+  "contentVersion": "1.0.0.0",
+  "parameters": {
+    "environmentName": {
+      "value": "${AZURE_ENV_NAME}"
+    },
+    "location": {
+      "value": "${AZURE_LOCATION}"
+    },
+    "principalId": {
+      "value": "${AZURE_PRINCIPAL_ID}"
+    }
+  }
+}
+`
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package synth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	infraDir := filepath.Join(t.TempDir(), "infra")
+	projectConfig := &project.ProjectConfig{
+		Services: map[string]*project.ServiceConfig{
+			"web":    {Host: project.AppServiceTarget, Language: project.ServiceLanguagePython},
+			"worker": {Host: project.AzureFunctionTarget, Language: project.ServiceLanguageJavaScript},
+			"api":    {Host: project.ContainerAppTarget, Language: project.ServiceLanguageDotNet},
+			"spa":    {Host: project.StaticWebAppTarget, Language: project.ServiceLanguageTypeScript},
+			"jobs":   {Host: project.AksTarget, Language: project.ServiceLanguageDocker},
+		},
+	}
+
+	require.NoError(t, Generate(infraDir, projectConfig))
+
+	resources, err := os.ReadFile(filepath.Join(infraDir, "resources.bicep"))
+	require.NoError(t, err)
+	require.Contains(t, string(resources), "resource webApp 'Microsoft.Web/sites@2022-03-01'")
+	require.Contains(t, string(resources), "linuxFxVersion: 'PYTHON|3.11'")
+	require.Contains(t, string(resources), "resource workerFunc 'Microsoft.Web/sites@2022-03-01'")
+	require.Contains(t, string(resources), "value: 'node'")
+	require.Contains(t, string(resources), "resource apiContainerApp 'Microsoft.App/containerApps@2023-05-01'")
+	require.Contains(t, string(resources), "resource spaStaticWebApp 'Microsoft.Web/staticSites@2022-03-01'")
+	require.Contains(t, string(resources), "tags: union(tags, { 'azd-service-name': 'api' })")
+	require.Contains(t, string(resources), "// The service 'jobs' is hosted by 'aks'")
+	require.Contains(t, string(resources), "output AZURE_CONTAINER_REGISTRY_ENDPOINT string")
+
+	main, err := os.ReadFile(filepath.Join(infraDir, "main.bicep"))
+	require.NoError(t, err)
+	require.Contains(t, string(main), "output AZURE_CONTAINER_REGISTRY_ENDPOINT string")
+	require.FileExists(t, filepath.Join(infraDir, "main.parameters.json"))
+
+	// The existing infrastructure is never overwritten
+	err = Generate(infraDir, projectConfig)
+	require.True(t, errors.Is(err, os.ErrExist))
+}
+
+func TestGenerateWithoutContainerApps(t *testing.T) {
+	infraDir := filepath.Join(t.TempDir(), "infra")
+	projectConfig := &project.ProjectConfig{
+		Services: map[string]*project.ServiceConfig{
+			"web": {Host: project.AppServiceTarget, Language: project.ServiceLanguageJava},
+		},
+	}
+
+	require.NoError(t, Generate(infraDir, projectConfig))
+
+	main, err := os.ReadFile(filepath.Join(infraDir, "main.bicep"))
+	require.NoError(t, err)
+	require.NotContains(t, string(main), "AZURE_CONTAINER_REGISTRY_ENDPOINT")
+}
+
+func TestGenerateUnsupportedFunctionLanguage(t *testing.T) {
+	projectConfig := &project.ProjectConfig{
+		Services: map[string]*project.ServiceConfig{
+			"worker": {Host: project.AzureFunctionTarget, Language: project.ServiceLanguageDocker},
+		},
+	}
+
+	require.Error(t, Generate(filepath.Join(t.TempDir(), "infra"), projectConfig))
+}
+
+func TestResourceName(t *testing.T) {
+	require.Equal(t, "web-api", resourceName("Web_API"))
+	require.Equal(t, "app-1st", resourceName("1st"))
+	require.Equal(t, "a-very-long-se", resourceName("a-very-long-service-name"))
+}
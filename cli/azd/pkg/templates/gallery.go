@@ -0,0 +1,118 @@
+package templates
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"golang.org/x/exp/slices"
+)
+
+// GalleryMetadata describes a template in the template gallery.
+// https://azure.github.io/awesome-azd
+type GalleryMetadata struct {
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Author        string   `json:"author,omitempty"`
+	Source        string   `json:"source,omitempty"`
+	Tags          []string `json:"tags"`
+	Languages     []string `json:"languages"`
+	AzureServices []string `json:"azureServices"`
+	IaC           []string `json:"IaC"`
+}
+
+// The gallery tags of the hosts of the services
+var galleryHostTags = map[project.ServiceTargetKind]string{
+	project.AppServiceTarget:    "appservice",
+	project.AzureFunctionTarget: "functions",
+	project.ContainerAppTarget:  "aca",
+	project.AksTarget:           "aks",
+	project.StaticWebAppTarget:  "swa",
+	project.SpringAppTarget:     "springapps",
+}
+
+// NewGalleryMetadata creates the gallery metadata of the template of the project. The title and the description are
+// the title and the first paragraph of the README.md of the template, when it has one.
+func NewGalleryMetadata(projectConfig *project.ProjectConfig, author string, source string) (*GalleryMetadata, error) {
+	metadata := &GalleryMetadata{
+		Title:         projectConfig.Name,
+		Author:        author,
+		Source:        source,
+		Tags:          []string{},
+		Languages:     []string{},
+		AzureServices: []string{},
+		IaC:           []string{},
+	}
+
+	readme, err := os.ReadFile(filepath.Join(projectConfig.Path, "README.md"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading README.md: %w", err)
+	}
+
+	if title, description := parseReadme(readme); title != "" {
+		metadata.Title = title
+		metadata.Description = description
+	}
+
+	for _, name := range serviceNames(projectConfig) {
+		service := projectConfig.Services[name]
+
+		if service.Language != "" && !slices.Contains(metadata.Languages, string(service.Language)) {
+			metadata.Languages = append(metadata.Languages, string(service.Language))
+		}
+
+		if tag, has := galleryHostTags[service.Host]; has && !slices.Contains(metadata.AzureServices, tag) {
+			metadata.AzureServices = append(metadata.AzureServices, tag)
+		}
+	}
+
+	provider := projectConfig.Infra.Provider
+	if provider == "" {
+		provider = provisioning.Bicep
+	}
+	metadata.IaC = append(metadata.IaC, string(provider))
+
+	metadata.Tags = append(metadata.Tags, metadata.Languages...)
+	metadata.Tags = append(metadata.Tags, metadata.AzureServices...)
+
+	return metadata, nil
+}
+
+// parseReadme returns the title of a markdown document, and its first paragraph
+func parseReadme(content []byte) (string, string) {
+	var title string
+	var paragraph []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if title == "" {
+			if heading, ok := strings.CutPrefix(line, "# "); ok {
+				title = strings.TrimSpace(heading)
+			}
+			continue
+		}
+
+		switch {
+		case line == "" && len(paragraph) > 0:
+			return title, strings.Join(paragraph, " ")
+		case line == "", strings.HasPrefix(line, "#"), strings.HasPrefix(line, "!["), strings.HasPrefix(line, "<"),
+			strings.HasPrefix(line, "[!["):
+			// Skip blank lines, headings, images and badges before the first paragraph
+			if len(paragraph) > 0 {
+				return title, strings.Join(paragraph, " ")
+			}
+		default:
+			paragraph = append(paragraph, line)
+		}
+	}
+
+	return title, strings.Join(paragraph, " ")
+}
@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFileName is the name of the manifest of a template, at the root of the template. The manifest isn't copied to
+// the project initialized from the template.
+const ManifestFileName = "azd-template.yaml"
+
+// ProjectNameToken is the name of the token replaced by the name of the project initialized from a template, always
+// available to the templates with a manifest.
+const ProjectNameToken = "projectName"
+
+var tokenNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Manifest declares the tokens replaced in the files of a template when a project is initialized from it, so templates
+// don't ship hard-coded names. A token named projectName is written {{azd.projectName}} in the files of the template.
+type Manifest struct {
+	// The inputs of the template, prompted for when a project is initialized from it.
+	Inputs []ManifestInput `yaml:"inputs"`
+	// The files where the tokens are replaced, as glob patterns relative to the root of the template, like infra/*.bicep.
+	// A pattern without a slash matches the name of the files in any directory. All the text files when empty.
+	Files []string `yaml:"files"`
+}
+
+// ManifestInput is an input of a template, replacing the token of the same name.
+type ManifestInput struct {
+	// The name of the token, like apiServiceName.
+	Name string `yaml:"name"`
+	// The message of the prompt for the value, like "Name of the API service".
+	Prompt string `yaml:"prompt"`
+	// The value used when none is entered, or when prompts are disabled.
+	Default string `yaml:"default"`
+}
+
+// Token returns the text replaced by the value of the token with the specified name.
+func Token(name string) string {
+	return fmt.Sprintf("{{azd.%s}}", name)
+}
+
+// LoadManifest loads the manifest of the template in templateDir, or returns nil when the template has no manifest.
+func LoadManifest(templateDir string) (*Manifest, error) {
+	content, err := os.ReadFile(filepath.Join(templateDir, ManifestFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading template manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing template manifest %s: %w", ManifestFileName, err)
+	}
+
+	for _, input := range manifest.Inputs {
+		if !tokenNameRegex.MatchString(input.Name) {
+			return nil, fmt.Errorf(
+				"invalid input '%s' in %s: names start with a letter and contain letters, digits and underscores",
+				input.Name,
+				ManifestFileName)
+		}
+	}
+
+	for _, pattern := range manifest.Files {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid file pattern '%s' in %s: %w", pattern, ManifestFileName, err)
+		}
+	}
+
+	return &manifest, nil
+}
+
+// ReplaceTokens replaces the tokens of values, keyed by token name, in the files of the template in templateDir
+// matching the file patterns of the manifest. Binary files and the .git directory are skipped.
+func (m *Manifest) ReplaceTokens(templateDir string, values map[string]string) error {
+	oldNew := make([]string, 0, len(values)*2)
+	for name, value := range values {
+		oldNew = append(oldNew, Token(name), value)
+	}
+	replacer := strings.NewReplacer(oldNew...)
+
+	return filepath.WalkDir(templateDir, func(fullPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		rel, err := filepath.Rel(templateDir, fullPath)
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() || rel == ManifestFileName || !m.matches(filepath.ToSlash(rel)) {
+			return nil
+		}
+
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", rel, err)
+		}
+
+		if bytes.IndexByte(content, 0) != -1 || !bytes.Contains(content, []byte("{{azd.")) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		replaced := replacer.Replace(string(content))
+		if err := os.WriteFile(fullPath, []byte(replaced), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("replacing template tokens in %s: %w", rel, err)
+		}
+
+		return nil
+	})
+}
+
+// matches returns true when the file, relative to the root of the template, matches the file patterns of the manifest.
+func (m *Manifest) matches(file string) bool {
+	if len(m.Files) == 0 {
+		return true
+	}
+
+	for _, pattern := range m.Files {
+		name := file
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(file)
+		}
+
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
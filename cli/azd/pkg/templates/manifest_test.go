@@ -0,0 +1,66 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifest(t *testing.T) {
+	templateDir := writeTestTemplate(t, map[string]string{
+		"README.md": "# {{azd.projectName}}",
+	})
+
+	manifest, err := LoadManifest(templateDir)
+	require.NoError(t, err)
+	require.Nil(t, manifest)
+
+	templateDir = writeTestTemplate(t, map[string]string{
+		ManifestFileName: "inputs:\n  - name: api-name\n",
+	})
+
+	_, err = LoadManifest(templateDir)
+	require.Error(t, err)
+}
+
+func TestManifestReplaceTokens(t *testing.T) {
+	templateDir := writeTestTemplate(t, map[string]string{
+		ManifestFileName: `inputs:
+  - name: apiServiceName
+    prompt: Name of the API service
+    default: api
+files:
+  - "*.yaml"
+  - infra/*.bicep
+`,
+		"azure.yaml":         "name: {{azd.projectName}}\nservices:\n  {{azd.apiServiceName}}:\n    host: containerapp\n",
+		"infra/main.bicep":   "param name string = '{{azd.projectName}}'\n",
+		"src/api/README.md":  "# {{azd.apiServiceName}}\n",
+		"infra/app/api.yaml": "name: {{azd.apiServiceName}}-{{azd.unknown}}\n",
+	})
+
+	manifest, err := LoadManifest(templateDir)
+	require.NoError(t, err)
+	require.Equal(t, []ManifestInput{{Name: "apiServiceName", Prompt: "Name of the API service", Default: "api"}},
+		manifest.Inputs)
+
+	err = manifest.ReplaceTokens(templateDir, map[string]string{
+		ProjectNameToken: "contoso",
+		"apiServiceName": "orders",
+	})
+	require.NoError(t, err)
+
+	for file, expected := range map[string]string{
+		"azure.yaml":       "name: contoso\nservices:\n  orders:\n    host: containerapp\n",
+		"infra/main.bicep": "param name string = 'contoso'\n",
+		// Files not matching the patterns of the manifest are unchanged, as are undeclared tokens
+		"src/api/README.md":  "# {{azd.apiServiceName}}\n",
+		"infra/app/api.yaml": "name: orders-{{azd.unknown}}\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(templateDir, file))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(content), file)
+	}
+}
@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// SmokeTestOptions configures the sandbox a template is provisioned in by RunSmokeTest
+type SmokeTestOptions struct {
+	// The environment created for the test, and deleted with its resources once the test completes
+	EnvironmentName string
+	SubscriptionId  string
+	Location        string
+}
+
+// RunSmokeTest provisions the template of the directory in a new environment, with the azd executable at azdPath, and
+// deletes the provisioned resources and the environment once the provisioning completes, even when it fails.
+func RunSmokeTest(
+	ctx context.Context,
+	commandRunner exec.CommandRunner,
+	azdPath string,
+	templateDir string,
+	options SmokeTestOptions,
+) (err error) {
+	run := func(ctx context.Context, args ...string) error {
+		args = append(args, "--cwd", templateDir, "--no-prompt")
+		runArgs := exec.NewRunArgs(azdPath, args...).WithInteractive(true)
+		if _, err := commandRunner.Run(ctx, runArgs); err != nil {
+			return fmt.Errorf("running 'azd %s': %w", args[0], err)
+		}
+
+		return nil
+	}
+
+	// 'azd env new' selects the new environment, the environment selected before the test is selected again after it
+	azdCtx := azdcontext.NewAzdContextWithDirectory(templateDir)
+	defaultEnvironment, err := azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return err
+	}
+
+	if err := run(ctx, "env", "new", options.EnvironmentName,
+		"--subscription", options.SubscriptionId, "--location", options.Location); err != nil {
+		return err
+	}
+
+	defer func() {
+		// The resources are deleted even when the test is cancelled, like with Ctrl+C while provisioning
+		downErr := run(withoutCancel{ctx}, "down", "-e", options.EnvironmentName, "--force", "--purge")
+
+		envDir := azdCtx.EnvironmentRoot(options.EnvironmentName)
+		if removeErr := os.RemoveAll(envDir); removeErr != nil {
+			log.Printf("failed removing environment directory '%s': %v", envDir, removeErr)
+		}
+
+		if setErr := azdCtx.SetDefaultEnvironmentName(defaultEnvironment); setErr != nil {
+			log.Printf("failed selecting environment '%s': %v", defaultEnvironment, setErr)
+		}
+
+		if err == nil {
+			err = downErr
+		} else if downErr != nil {
+			log.Printf("failed deleting the resources of the smoke test: %v", downErr)
+		}
+	}()
+
+	return run(ctx, "provision", "-e", options.EnvironmentName)
+}
+
+// withoutCancel is a context keeping the values of its parent, which is never cancelled, like context.WithoutCancel of
+// Go 1.21.
+type withoutCancel struct {
+	parent context.Context
+}
+
+func (withoutCancel) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (withoutCancel) Done() <-chan struct{} {
+	return nil
+}
+
+func (withoutCancel) Err() error {
+	return nil
+}
+
+func (c withoutCancel) Value(key any) any {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,451 @@
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type ValidationSeverity string
+
+const (
+	// The template doesn't work with azd
+	ValidationError ValidationSeverity = "error"
+	// The template works with azd, but doesn't follow the conventions of the templates of the gallery
+	ValidationWarning ValidationSeverity = "warning"
+)
+
+// ValidationIssue is a convention a template doesn't follow
+type ValidationIssue struct {
+	Severity ValidationSeverity `json:"severity"`
+	// The convention checked, like azure.yaml or infra
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// ValidationResult is the result of the validation of a template
+type ValidationResult struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+// HasErrors returns true when the template doesn't work with azd
+func (r *ValidationResult) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == ValidationError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *ValidationResult) add(severity ValidationSeverity, check string, format string, a ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Severity: severity,
+		Check:    check,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// The workflows provisioning and deploying a template, one of which templates are expected to have
+var workflowPaths = []string{
+	filepath.Join(".github", "workflows", "azure-dev.yml"),
+	filepath.Join(".azdo", "pipelines", "azure-dev.yml"),
+}
+
+var (
+	bicepParamRegex  = regexp.MustCompile(`(?m)^\s*param\s+(\w+)\s`)
+	bicepOutputRegex = regexp.MustCompile(`(?m)^\s*output\s+(\w+)\s`)
+)
+
+// Validate checks the template of the directory follows the conventions of azd templates: a project validated by
+// ValidateProject, metadata, a dev container and a workflow.
+func Validate(ctx context.Context, templateDir string) (*ValidationResult, error) {
+	result, projectConfig, err := ValidateProject(ctx, templateDir)
+	if err != nil || projectConfig == nil {
+		return result, err
+	}
+
+	if projectConfig.Metadata == nil || projectConfig.Metadata.Template == "" {
+		result.add(ValidationWarning, azdcontext.ProjectFileName,
+			"missing metadata.template, like %s@0.0.1-beta", projectConfig.Name)
+	}
+
+	if !fileExists(filepath.Join(templateDir, ".devcontainer", "devcontainer.json")) {
+		result.add(ValidationWarning, "devcontainer", "missing .devcontainer/devcontainer.json")
+	}
+
+	hasWorkflow := false
+	for _, path := range workflowPaths {
+		if fileExists(filepath.Join(templateDir, path)) {
+			hasWorkflow = true
+			break
+		}
+	}
+
+	if !hasWorkflow {
+		result.add(ValidationWarning, "workflow", "missing a workflow, run 'azd pipeline config' to add one to %s",
+			strings.Join(workflowPaths, " or "))
+	}
+
+	if !fileExists(filepath.Join(templateDir, "README.md")) {
+		result.add(ValidationWarning, "readme", "missing README.md")
+	}
+
+	gitIgnore, err := os.ReadFile(filepath.Join(templateDir, ".gitignore"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading .gitignore: %w", err)
+	}
+
+	if !strings.Contains(string(gitIgnore), ".azure") {
+		result.add(ValidationWarning, "gitignore", ".gitignore doesn't ignore the .azure directory of the environments")
+	}
+
+	return result, nil
+}
+
+// ValidateProject checks the project of the directory works with azd: azure.yaml matches its schema, the project, the
+// language and the host of each service work together, the hooks run existing scripts and the infrastructure has the
+// expected parameters and outputs. The project is nil when azure.yaml can't be loaded.
+func ValidateProject(ctx context.Context, projectDir string) (*ValidationResult, *project.ProjectConfig, error) {
+	result := &ValidationResult{}
+	projectPath := filepath.Join(projectDir, azdcontext.ProjectFileName)
+
+	content, err := os.ReadFile(projectPath)
+	if err != nil {
+		result.add(ValidationError, azdcontext.ProjectFileName, "%s", err.Error())
+		return result, nil, nil
+	}
+
+	version, schemaErrors, err := project.ValidateSchema(content)
+	if err != nil {
+		result.add(ValidationError, azdcontext.ProjectFileName, "%s", err.Error())
+		return result, nil, nil
+	}
+
+	// Version 1.0 ignores unknown properties and values, the projects using it still load
+	for _, schemaErr := range schemaErrors {
+		if version == project.SchemaVersion1_0 {
+			result.add(ValidationWarning, "schema", "%s, ignored by version %s of the schema", schemaErr, version)
+		} else {
+			result.add(ValidationError, "schema", "%s", schemaErr)
+		}
+	}
+
+	if result.HasErrors() {
+		return result, nil, nil
+	}
+
+	projectConfig, err := project.Load(ctx, projectPath)
+	if err != nil {
+		result.add(ValidationError, azdcontext.ProjectFileName, "%s", err.Error())
+		return result, nil, nil
+	}
+
+	validateProject(projectConfig, result)
+	validateHooks(projectConfig, result)
+
+	if err := validateInfra(projectDir, projectConfig, result); err != nil {
+		return nil, nil, err
+	}
+
+	return result, projectConfig, nil
+}
+
+// The languages supported by the hosts running the code of the services without a container, the other hosts support
+// every language
+var hostLanguages = map[project.ServiceTargetKind][]project.ServiceLanguageKind{
+	project.AppServiceTarget: {
+		project.ServiceLanguageDotNet,
+		project.ServiceLanguageCsharp,
+		project.ServiceLanguageFsharp,
+		project.ServiceLanguageJavaScript,
+		project.ServiceLanguageTypeScript,
+		project.ServiceLanguagePython,
+		project.ServiceLanguageJava,
+	},
+	project.StaticWebAppTarget: {
+		project.ServiceLanguageDotNet,
+		project.ServiceLanguageCsharp,
+		project.ServiceLanguageFsharp,
+		project.ServiceLanguageJavaScript,
+		project.ServiceLanguageTypeScript,
+	},
+	project.SpringAppTarget: {project.ServiceLanguageJava},
+}
+
+// The files one of which the project of a service has for its language, like the package.json of Node.js projects
+var languageProjectFiles = map[project.ServiceLanguageKind][]string{
+	project.ServiceLanguageDotNet:     {"*.csproj", "*.fsproj", "*.vbproj"},
+	project.ServiceLanguageCsharp:     {"*.csproj"},
+	project.ServiceLanguageFsharp:     {"*.fsproj"},
+	project.ServiceLanguageJavaScript: {"package.json"},
+	project.ServiceLanguageTypeScript: {"package.json"},
+	project.ServiceLanguagePython:     {"requirements.txt", "pyproject.toml", "setup.py"},
+	project.ServiceLanguageJava:       {"pom.xml", "build.gradle", "build.gradle.kts"},
+	project.ServiceLanguageGo:         {"go.mod"},
+}
+
+func validateProject(projectConfig *project.ProjectConfig, result *ValidationResult) {
+	if projectConfig.Name == "" {
+		result.add(ValidationError, azdcontext.ProjectFileName, "missing name")
+	}
+
+	if len(projectConfig.Services) == 0 {
+		result.add(ValidationWarning, azdcontext.ProjectFileName, "missing services")
+	}
+
+	for _, name := range serviceNames(projectConfig) {
+		service := projectConfig.Services[name]
+		servicePath := filepath.Join(projectConfig.Path, service.RelativePath)
+		if service.RelativePath == "" {
+			result.add(ValidationError, azdcontext.ProjectFileName, "service '%s' is missing project", name)
+		} else if !fileExists(servicePath) {
+			result.add(ValidationError, azdcontext.ProjectFileName,
+				"project '%s' of service '%s' doesn't exist", service.RelativePath, name)
+		} else if !hasLanguageProjectFile(servicePath, service.Language) {
+			result.add(ValidationWarning, azdcontext.ProjectFileName,
+				"project '%s' of service '%s' has none of %s, expected for language '%s'",
+				service.RelativePath, name, strings.Join(languageProjectFiles[service.Language], ", "), service.Language)
+		}
+
+		if languages, has := hostLanguages[service.Host]; has && !slices.Contains(languages, service.Language) {
+			result.add(ValidationError, azdcontext.ProjectFileName,
+				"service '%s' uses language '%s', which isn't supported by host '%s'", name, service.Language, service.Host)
+		}
+	}
+}
+
+// hasLanguageProjectFile returns true when the project of a service has one of the files of its language. The projects
+// of .NET services can be the project file itself.
+func hasLanguageProjectFile(servicePath string, language project.ServiceLanguageKind) bool {
+	patterns, has := languageProjectFiles[language]
+	if !has {
+		return true
+	}
+
+	if info, err := os.Stat(servicePath); err == nil && !info.IsDir() {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if matches, _ := filepath.Glob(filepath.Join(servicePath, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Matches the run of the hooks which is the path of a script rather than an inline script
+var hookScriptPathRegex = regexp.MustCompile(`^\S+\.(sh|ps1)$`)
+
+// validateHooks checks the hooks of the project and of its services run existing scripts, or inline scripts with a
+// shell. The scripts of the service hooks are relative to the project of the service.
+func validateHooks(projectConfig *project.ProjectConfig, result *ValidationResult) {
+	for _, name := range hookNames(projectConfig.Hooks) {
+		validateHook(fmt.Sprintf("hook '%s'", name), projectConfig.Path, projectConfig.Hooks[name], result)
+	}
+
+	for _, serviceName := range serviceNames(projectConfig) {
+		service := projectConfig.Services[serviceName]
+		for _, name := range hookNames(service.Hooks) {
+			validateHook(fmt.Sprintf("hook '%s' of service '%s'", name, serviceName),
+				filepath.Join(projectConfig.Path, service.RelativePath), service.Hooks[name], result)
+		}
+	}
+}
+
+func validateHook(hookName string, cwd string, hook *ext.HookConfig, result *ValidationResult) {
+	if hook == nil {
+		return
+	}
+
+	// The run of the hook can be set by the overrides of the operating systems only
+	if hook.Run == "" && hook.Windows == nil && hook.Posix == nil {
+		result.add(ValidationError, "hooks", "%s is missing run", hookName)
+	}
+
+	if hook.Run != "" {
+		scriptPath := filepath.Join(cwd, filepath.FromSlash(hook.Run))
+		extension := filepath.Ext(scriptPath)
+
+		if info, err := os.Stat(scriptPath); err == nil && !info.IsDir() {
+			if hook.Shell == ext.ScriptTypeUnknown && extension != ".sh" && extension != ".ps1" {
+				result.add(ValidationError, "hooks",
+					"%s runs '%s', set shell to sh or pwsh for scripts other than .sh and .ps1", hookName, hook.Run)
+			}
+		} else if hookScriptPathRegex.MatchString(hook.Run) {
+			result.add(ValidationError, "hooks", "%s runs script '%s', which doesn't exist", hookName, hook.Run)
+		} else if hook.Shell == ext.ScriptTypeUnknown {
+			result.add(ValidationError, "hooks", "%s runs an inline script, set shell to sh or pwsh", hookName)
+		}
+	}
+
+	validateHook(hookName+" on windows", cwd, hook.Windows, result)
+	validateHook(hookName+" on posix", cwd, hook.Posix, result)
+}
+
+func hookNames(hooks map[string]*ext.HookConfig) []string {
+	names := maps.Keys(hooks)
+	slices.Sort(names)
+	return names
+}
+
+func validateInfra(templateDir string, projectConfig *project.ProjectConfig, result *ValidationResult) error {
+	infraDir := projectConfig.Infra.Path
+	if infraDir == "" {
+		infraDir = "infra"
+	}
+
+	module := projectConfig.Infra.Module
+	if module == "" {
+		module = "main"
+	}
+
+	if projectConfig.Infra.Provider == provisioning.Terraform {
+		for _, file := range []string{module + ".tf", module + ".tfvars.json"} {
+			if !fileExists(filepath.Join(templateDir, infraDir, file)) {
+				result.add(ValidationError, "infra", "missing %s", filepath.Join(infraDir, file))
+			}
+		}
+
+		return nil
+	}
+
+	if !fileExists(filepath.Join(templateDir, infraDir, module+".parameters.json")) {
+		result.add(ValidationError, "infra", "missing %s", filepath.Join(infraDir, module+".parameters.json"))
+	}
+
+	modulePath := filepath.Join(infraDir, module+".bicep")
+	content, err := os.ReadFile(filepath.Join(templateDir, modulePath))
+	if errors.Is(err, os.ErrNotExist) {
+		// Templates can provide an ARM JSON template instead of a Bicep module
+		armTemplatePath := filepath.Join(infraDir, module+".json")
+		if armContent, armErr := os.ReadFile(filepath.Join(templateDir, armTemplatePath)); armErr == nil {
+			modulePath, content, err = armTemplatePath, armContent, nil
+		}
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		result.add(ValidationError, "infra", "missing %s", modulePath)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading %s: %w", modulePath, err)
+	}
+
+	params, outputs, err := templateSymbols(modulePath, content)
+	if err != nil {
+		result.add(ValidationError, "infra", "%s", err.Error())
+		return nil
+	}
+
+	for _, param := range []string{"environmentName", "location"} {
+		if !params[param] {
+			result.add(ValidationError, "infra", "%s is missing parameter '%s'", modulePath, param)
+		}
+	}
+
+	if len(outputs) == 0 {
+		result.add(ValidationWarning, "infra", "%s has no outputs, services can't read the provisioned resources",
+			modulePath)
+	}
+
+	// Services running containers push their images to the registry of the infrastructure, unless they configure
+	// another registry with docker.registry
+	for _, name := range serviceNames(projectConfig) {
+		service := projectConfig.Services[name]
+		if (service.Host == project.ContainerAppTarget || service.Host == project.AksTarget) &&
+			usesEnvironmentRegistry(service) && !outputs["AZURE_CONTAINER_REGISTRY_ENDPOINT"] {
+			result.add(ValidationError, "infra",
+				"%s is missing output 'AZURE_CONTAINER_REGISTRY_ENDPOINT', required by service '%s'", modulePath, name)
+			break
+		}
+	}
+
+	// Services hosted on AKS are deployed to the cluster of the infrastructure
+	for _, name := range serviceNames(projectConfig) {
+		if projectConfig.Services[name].Host == project.AksTarget && !outputs[environment.AksClusterEnvVarName] {
+			result.add(ValidationError, "infra",
+				"%s is missing output '%s', required by service '%s'", modulePath, environment.AksClusterEnvVarName, name)
+			break
+		}
+	}
+
+	return nil
+}
+
+// usesEnvironmentRegistry returns true when the service pushes its images to the Azure Container Registry of the
+// environment: docker.registry isn't set, or it references AZURE_CONTAINER_REGISTRY_ENDPOINT.
+func usesEnvironmentRegistry(service *project.ServiceConfig) bool {
+	referencesEndpoint := false
+	registry := service.Docker.Registry.MustEnvsubst(func(name string) string {
+		if name == environment.ContainerRegistryEndpointEnvVarName {
+			referencesEndpoint = true
+			return ""
+		}
+
+		// The other values are only known when the service is deployed
+		return name
+	})
+
+	return registry == "" || referencesEndpoint
+}
+
+func serviceNames(projectConfig *project.ProjectConfig) []string {
+	names := maps.Keys(projectConfig.Services)
+	slices.Sort(names)
+	return names
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// templateSymbols returns the names of the parameters and of the outputs of the Bicep module or of the ARM JSON template.
+func templateSymbols(modulePath string, content []byte) (map[string]bool, map[string]bool, error) {
+	params := map[string]bool{}
+	outputs := map[string]bool{}
+
+	if filepath.Ext(modulePath) == ".json" {
+		var template azure.ArmTemplate
+		if err := json.Unmarshal(content, &template); err != nil {
+			return nil, nil, fmt.Errorf("%s isn't a valid ARM template: %w", modulePath, err)
+		}
+
+		for name := range template.Parameters {
+			params[name] = true
+		}
+
+		for name := range template.Outputs {
+			outputs[name] = true
+		}
+
+		return params, outputs, nil
+	}
+
+	for _, match := range bicepParamRegex.FindAllStringSubmatch(string(content), -1) {
+		params[match[1]] = true
+	}
+
+	for _, match := range bicepOutputRegex.FindAllStringSubmatch(string(content), -1) {
+		outputs[match[1]] = true
+	}
+
+	return params, outputs, nil
+}
@@ -0,0 +1,263 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+const testProjectFile = `name: todo
+metadata:
+  template: todo@0.0.1-beta
+services:
+  api:
+    project: ./src/api
+    language: python
+    host: containerapp
+  web:
+    project: ./src/web
+    language: js
+    host: appservice
+`
+
+const testMainBicep = `targetScope = 'subscription'
+
+param environmentName string
+param location string
+
+output AZURE_LOCATION string = location
+`
+
+const testMainArmTemplate = `{
+  "$schema": "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+  "parameters": {
+    "environmentName": { "type": "string" },
+    "location": { "type": "string" }
+  },
+  "outputs": {
+    "AZURE_LOCATION": { "type": "string", "value": "[parameters('location')]" },
+    "AZURE_CONTAINER_REGISTRY_ENDPOINT": { "type": "string", "value": "[parameters('location')]" }
+  }
+}`
+
+const testReadme = `# ToDo Application with Python
+
+[![Open in GitHub Codespaces](https://github.com/codespaces/badge.svg)](https://codespaces.new)
+
+A complete ToDo application
+written in Python.
+
+## Prerequisites
+`
+
+func writeTestTemplate(t *testing.T, files map[string]string) string {
+	templateDir := t.TempDir()
+	for path, content := range files {
+		fullPath := filepath.Join(templateDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(fullPath, []byte(content), osutil.PermissionFile))
+	}
+
+	return templateDir
+}
+
+func TestValidate(t *testing.T) {
+	templateDir := writeTestTemplate(t, map[string]string{
+		"azure.yaml":                      testProjectFile,
+		"src/api/main.py":                 "",
+		"src/api/requirements.txt":        "",
+		"src/web/index.js":                "",
+		"src/web/package.json":            "{}",
+		"infra/main.bicep":                testMainBicep,
+		"infra/main.parameters.json":      "{}",
+		".devcontainer/devcontainer.json": "{}",
+		".github/workflows/azure-dev.yml": "",
+		"README.md":                       testReadme,
+		".gitignore":                      ".azure\n",
+	})
+
+	result, err := Validate(context.Background(), templateDir)
+	require.NoError(t, err)
+	require.True(t, result.HasErrors())
+	require.Equal(t, []ValidationIssue{
+		{
+			Severity: ValidationError,
+			Check:    "infra",
+			Message: filepath.Join("infra", "main.bicep") +
+				" is missing output 'AZURE_CONTAINER_REGISTRY_ENDPOINT', required by service 'api'",
+		},
+	}, result.Issues)
+}
+
+func TestValidateExternalRegistry(t *testing.T) {
+	projectFile := strings.Replace(testProjectFile, "    host: containerapp\n",
+		"    host: containerapp\n    docker:\n      registry: ghcr.io/${GITHUB_OWNER}\n", 1)
+
+	templateDir := writeTestTemplate(t, map[string]string{
+		"azure.yaml":                      projectFile,
+		"src/api/main.py":                 "",
+		"src/api/requirements.txt":        "",
+		"src/web/index.js":                "",
+		"src/web/package.json":            "{}",
+		"infra/main.bicep":                testMainBicep,
+		"infra/main.parameters.json":      "{}",
+		".devcontainer/devcontainer.json": "{}",
+		".github/workflows/azure-dev.yml": "",
+		"README.md":                       testReadme,
+		".gitignore":                      ".azure\n",
+	})
+
+	result, err := Validate(context.Background(), templateDir)
+	require.NoError(t, err)
+	require.Empty(t, result.Issues)
+}
+
+func TestValidateArmTemplate(t *testing.T) {
+	templateDir := writeTestTemplate(t, map[string]string{
+		"azure.yaml":                      testProjectFile,
+		"src/api/main.py":                 "",
+		"src/api/requirements.txt":        "",
+		"src/web/index.js":                "",
+		"src/web/package.json":            "{}",
+		"infra/main.json":                 testMainArmTemplate,
+		"infra/main.parameters.json":      "{}",
+		".devcontainer/devcontainer.json": "{}",
+		".github/workflows/azure-dev.yml": "",
+		"README.md":                       testReadme,
+		".gitignore":                      ".azure\n",
+	})
+
+	result, err := Validate(context.Background(), templateDir)
+	require.NoError(t, err)
+	require.Empty(t, result.Issues)
+}
+
+func TestValidateMissingConventions(t *testing.T) {
+	templateDir := writeTestTemplate(t, map[string]string{
+		"azure.yaml":       "name: todo\n",
+		"infra/main.bicep": "param location string\n",
+	})
+
+	result, err := Validate(context.Background(), templateDir)
+	require.NoError(t, err)
+	require.True(t, result.HasErrors())
+
+	checks := map[string]ValidationSeverity{}
+	for _, issue := range result.Issues {
+		checks[issue.Check+": "+issue.Message] = issue.Severity
+	}
+
+	require.Equal(t, ValidationError, checks["infra: missing "+filepath.Join("infra", "main.parameters.json")])
+	require.Equal(t, ValidationError,
+		checks["infra: "+filepath.Join("infra", "main.bicep")+" is missing parameter 'environmentName'"])
+	require.Equal(t, ValidationWarning, checks["devcontainer: missing .devcontainer/devcontainer.json"])
+	require.Equal(t, ValidationWarning, checks["readme: missing README.md"])
+}
+
+func TestValidateMissingProject(t *testing.T) {
+	result, err := Validate(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	require.True(t, result.HasErrors())
+	require.Len(t, result.Issues, 1)
+	require.Equal(t, "azure.yaml", result.Issues[0].Check)
+}
+
+func TestValidateProject(t *testing.T) {
+	projectFile := `name: todo
+hooks:
+  preprovision:
+    run: ./scripts/missing.sh
+  postprovision:
+    run: echo provisioned
+  predeploy:
+    shell: sh
+    run: echo deploying
+services:
+  api:
+    project: ./src/api
+    language: go
+    host: appservice
+    hooks:
+      prepackage:
+        run: ./build.sh
+  web:
+    project: ./src/web
+    language: js
+    host: aks
+`
+
+	projectDir := writeTestTemplate(t, map[string]string{
+		"azure.yaml":                 projectFile,
+		"src/api/main.go":            "",
+		"src/api/build.sh":           "",
+		"src/web/index.js":           "",
+		"infra/main.bicep":           testMainBicep,
+		"infra/main.parameters.json": "{}",
+	})
+
+	result, projectConfig, err := ValidateProject(context.Background(), projectDir)
+	require.NoError(t, err)
+	require.NotNil(t, projectConfig)
+
+	var issues []string
+	for _, issue := range result.Issues {
+		issues = append(issues, fmt.Sprintf("%s %s: %s", issue.Severity, issue.Check, issue.Message))
+	}
+
+	mainBicep := filepath.Join("infra", "main.bicep")
+	require.Equal(t, []string{
+		"warning azure.yaml: project './src/api' of service 'api' has none of go.mod, expected for language 'go'",
+		"error azure.yaml: service 'api' uses language 'go', which isn't supported by host 'appservice'",
+		"warning azure.yaml: project './src/web' of service 'web' has none of package.json, expected for " +
+			"language 'js'",
+		"error hooks: hook 'postprovision' runs an inline script, set shell to sh or pwsh",
+		"error hooks: hook 'preprovision' runs script './scripts/missing.sh', which doesn't exist",
+		"error infra: " + mainBicep + " is missing output 'AZURE_CONTAINER_REGISTRY_ENDPOINT', required by " +
+			"service 'web'",
+		"error infra: " + mainBicep + " is missing output 'AZURE_AKS_CLUSTER_NAME', required by service 'web'",
+	}, issues)
+}
+
+func TestValidateProjectSchema(t *testing.T) {
+	projectDir := writeTestTemplate(t, map[string]string{
+		"azure.yaml": "schemaVersion: \"1.1\"\nname: todo\nservics: {}\n",
+	})
+
+	result, projectConfig, err := ValidateProject(context.Background(), projectDir)
+	require.NoError(t, err)
+	require.Nil(t, projectConfig)
+	require.True(t, result.HasErrors())
+	require.Len(t, result.Issues, 1)
+	require.Equal(t, "schema", result.Issues[0].Check)
+	require.Contains(t, result.Issues[0].Message, "servics")
+}
+
+func TestNewGalleryMetadata(t *testing.T) {
+	templateDir := writeTestTemplate(t, map[string]string{
+		"azure.yaml": testProjectFile,
+		"README.md":  testReadme,
+	})
+
+	projectConfig, err := project.Load(context.Background(), filepath.Join(templateDir, "azure.yaml"))
+	require.NoError(t, err)
+
+	metadata, err := NewGalleryMetadata(projectConfig, "Contoso", "https://github.com/contoso/todo")
+	require.NoError(t, err)
+	require.Equal(t, &GalleryMetadata{
+		Title:         "ToDo Application with Python",
+		Description:   "A complete ToDo application written in Python.",
+		Author:        "Contoso",
+		Source:        "https://github.com/contoso/todo",
+		Tags:          []string{"python", "js", "aca", "appservice"},
+		Languages:     []string{"python", "js"},
+		AzureServices: []string{"aca", "appservice"},
+		IaC:           []string{"bicep"},
+	}, metadata)
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -260,6 +261,56 @@ func (cli *azCli) applyRoleAssignmentWithRetry(
 	})
 }
 
+// EnsureRoleAssignment assigns the role to the principal on the scope. The name of the role assignment is derived from
+// the scope, the role and the principal, so ensuring an existing assignment succeeds without creating another one.
+// This operation will retry up to 10 times to wait for a new principal to be available in Azure AD
+func (cli *azCli) EnsureRoleAssignment(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	roleName string,
+	principalId string,
+) error {
+	roleDefinition, err := cli.getRoleDefinition(ctx, subscriptionId, scope, roleName)
+	if err != nil {
+		return err
+	}
+
+	roleAssignmentsClient, err := cli.createRoleAssignmentsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	roleAssignmentId := uuid.NewSHA1(
+		uuid.NameSpaceURL, []byte(strings.ToLower(scope+"|"+*roleDefinition.ID+"|"+principalId))).String()
+
+	return retry.Do(ctx, retry.WithMaxRetries(10, retry.NewConstant(time.Second*5)), func(ctx context.Context) error {
+		_, err = roleAssignmentsClient.Create(ctx, scope, roleAssignmentId, armauthorization.RoleAssignmentCreateParameters{
+			Properties: &armauthorization.RoleAssignmentProperties{
+				PrincipalID:      &principalId,
+				RoleDefinitionID: roleDefinition.ID,
+			},
+		}, nil)
+
+		if err != nil {
+			// If the response is a 409 conflict then the role has already been assigned.
+			var responseError *azcore.ResponseError
+			if errors.As(err, &responseError) && responseError.StatusCode == http.StatusConflict {
+				return nil
+			}
+
+			err = fmt.Errorf("failed assigning role '%s' to principal '%s' on '%s': %w", roleName, principalId, scope, err)
+			if responseError != nil && responseError.ErrorCode == "PrincipalNotFound" {
+				return retry.RetryableError(err)
+			}
+
+			return err
+		}
+
+		return nil
+	})
+}
+
 // Find the Azure role definition for the specified scope and role name
 func (cli *azCli) getRoleDefinition(
 	ctx context.Context,
@@ -0,0 +1,336 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
+	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// ApimApi is an API of an API Management service, imported from an API definition.
+type ApimApi struct {
+	// The path of the API, appended to the gateway url
+	Path        string
+	DisplayName string
+	// The url of the backend the gateway forwards the calls to
+	ServiceUrl string
+	// The format of the definition, like openapi or openapi+json
+	Format string
+	// The content of the definition
+	Definition string
+	// The revision created by the import, like 2
+	Revision string
+	// The id of the API the revision is created from, empty when the API is created
+	SourceApiId string
+	// The version of the API in its version set, empty when the API isn't versioned
+	Version      string
+	VersionSetId string
+}
+
+// ApiManagementService imports API definitions into API Management services and manages their revisions and version
+// sets.
+type ApiManagementService interface {
+	// Gets the url of the gateway of the API Management service
+	GetGatewayUrl(ctx context.Context, subscriptionId string, resourceGroupName string, serviceName string) (string, error)
+	// Gets the highest revision number of an API, or false when the API doesn't exist. After a rollback, the highest
+	// revision isn't the current revision.
+	GetLatestApiRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		serviceName string,
+		apiId string,
+	) (string, bool, error)
+	// Creates or updates a version set, using a path segment to select the versions, and returns its id
+	EnsureApiVersionSet(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		serviceName string,
+		versionSetId string,
+		displayName string,
+	) (string, error)
+	// Imports an API definition, creating the API or a revision of the API. Revisions are addressed as apiId;rev=N.
+	ImportApi(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		serviceName string,
+		apiId string,
+		api ApimApi,
+	) error
+	// Makes a revision the current revision of an API
+	ReleaseApiRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		serviceName string,
+		apiId string,
+		revision string,
+		notes string,
+	) error
+}
+
+type apiManagementService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
+	userAgent          string
+}
+
+// Creates a new instance of the ApiManagementService
+func NewApiManagementService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
+) ApiManagementService {
+	return &apiManagementService{
+		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
+		userAgent:          azdinternal.MakeUserAgentString(""),
+	}
+}
+
+func (as *apiManagementService) GetGatewayUrl(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	serviceName string,
+) (string, error) {
+	credential, err := as.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := clientOptionsBuilder(as.httpClient, as.userAgent).BuildArmClientOptions()
+	client, err := armapimanagement.NewServiceClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", fmt.Errorf("creating API Management client: %w", err)
+	}
+
+	service, err := client.Get(ctx, resourceGroupName, serviceName, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting api management service: %w", err)
+	}
+
+	if service.Properties == nil || service.Properties.GatewayURL == nil {
+		return "", fmt.Errorf("api management service '%s' has no gateway url", serviceName)
+	}
+
+	return *service.Properties.GatewayURL, nil
+}
+
+func (as *apiManagementService) GetLatestApiRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	serviceName string,
+	apiId string,
+) (string, bool, error) {
+	client, err := as.createApiClient(ctx, subscriptionId)
+	if err != nil {
+		return "", false, err
+	}
+
+	if _, err := client.Get(ctx, resourceGroupName, serviceName, apiId, nil); err != nil {
+		var responseErr *azcore.ResponseError
+		if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound {
+			return "", false, nil
+		}
+
+		return "", false, fmt.Errorf("getting api '%s': %w", apiId, err)
+	}
+
+	credential, err := as.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", false, err
+	}
+
+	options := clientOptionsBuilder(as.httpClient, as.userAgent).BuildArmClientOptions()
+	revisionClient, err := armapimanagement.NewAPIRevisionClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", false, fmt.Errorf("creating API Management revision client: %w", err)
+	}
+
+	latest := 1
+	pager := revisionClient.NewListByServicePager(resourceGroupName, serviceName, apiId, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return "", false, fmt.Errorf("listing revisions of api '%s': %w", apiId, err)
+		}
+
+		for _, revision := range page.Value {
+			if revision.APIRevision == nil {
+				continue
+			}
+
+			if number, err := strconv.Atoi(*revision.APIRevision); err == nil && number > latest {
+				latest = number
+			}
+		}
+	}
+
+	return strconv.Itoa(latest), true, nil
+}
+
+func (as *apiManagementService) EnsureApiVersionSet(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	serviceName string,
+	versionSetId string,
+	displayName string,
+) (string, error) {
+	credential, err := as.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := clientOptionsBuilder(as.httpClient, as.userAgent).BuildArmClientOptions()
+	client, err := armapimanagement.NewAPIVersionSetClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", fmt.Errorf("creating API Management version set client: %w", err)
+	}
+
+	versionSet, err := client.CreateOrUpdate(
+		ctx,
+		resourceGroupName,
+		serviceName,
+		versionSetId,
+		armapimanagement.APIVersionSetContract{
+			Properties: &armapimanagement.APIVersionSetContractProperties{
+				DisplayName:      to.Ptr(displayName),
+				VersioningScheme: to.Ptr(armapimanagement.VersioningSchemeSegment),
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf("creating version set '%s': %w", versionSetId, err)
+	}
+
+	return *versionSet.ID, nil
+}
+
+func (as *apiManagementService) ImportApi(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	serviceName string,
+	apiId string,
+	api ApimApi,
+) error {
+	client, err := as.createApiClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	properties := &armapimanagement.APICreateOrUpdateProperties{
+		Path:        to.Ptr(api.Path),
+		Format:      to.Ptr(armapimanagement.ContentFormat(api.Format)),
+		Value:       to.Ptr(api.Definition),
+		APIRevision: to.Ptr(api.Revision),
+		Protocols:   []*armapimanagement.Protocol{to.Ptr(armapimanagement.ProtocolHTTPS)},
+	}
+
+	if api.DisplayName != "" {
+		properties.DisplayName = to.Ptr(api.DisplayName)
+	}
+
+	if api.ServiceUrl != "" {
+		properties.ServiceURL = to.Ptr(api.ServiceUrl)
+	}
+
+	if api.SourceApiId != "" {
+		properties.SourceAPIID = to.Ptr(api.SourceApiId)
+	}
+
+	if api.VersionSetId != "" {
+		properties.APIVersion = to.Ptr(api.Version)
+		properties.APIVersionSetID = to.Ptr(api.VersionSetId)
+	}
+
+	poller, err := client.BeginCreateOrUpdate(
+		ctx,
+		resourceGroupName,
+		serviceName,
+		apiId,
+		armapimanagement.APICreateOrUpdateParameter{Properties: properties},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("starting import of api '%s': %w", apiId, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("importing api '%s': %w", apiId, err)
+	}
+
+	return nil
+}
+
+func (as *apiManagementService) ReleaseApiRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	serviceName string,
+	apiId string,
+	revision string,
+	notes string,
+) error {
+	credential, err := as.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	options := clientOptionsBuilder(as.httpClient, as.userAgent).BuildArmClientOptions()
+	client, err := armapimanagement.NewAPIReleaseClient(subscriptionId, credential, options)
+	if err != nil {
+		return fmt.Errorf("creating API Management release client: %w", err)
+	}
+
+	_, err = client.CreateOrUpdate(
+		ctx,
+		resourceGroupName,
+		serviceName,
+		apiId,
+		fmt.Sprintf("rev-%s", revision),
+		armapimanagement.APIReleaseContract{
+			Properties: &armapimanagement.APIReleaseContractProperties{
+				APIID: to.Ptr(fmt.Sprintf("/apis/%s;rev=%s", apiId, revision)),
+				Notes: to.Ptr(notes),
+			},
+		},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("releasing revision %s of api '%s': %w", revision, apiId, err)
+	}
+
+	return nil
+}
+
+func (as *apiManagementService) createApiClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armapimanagement.APIClient, error) {
+	credential, err := as.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := clientOptionsBuilder(as.httpClient, as.userAgent).BuildArmClientOptions()
+	client, err := armapimanagement.NewAPIClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating API Management client: %w", err)
+	}
+
+	return client, nil
+}
@@ -40,6 +40,7 @@ type AzCli interface {
 		subscriptionId string,
 		deploymentName string,
 	) (*armresources.DeploymentExtended, error)
+	ListSubscriptionDeployments(ctx context.Context, subscriptionId string) ([]*armresources.DeploymentExtended, error)
 	GetResourceGroupDeployment(
 		ctx context.Context,
 		subscriptionId string,
@@ -64,6 +65,23 @@ type AzCli interface {
 		vaultName string,
 		secretName string,
 	) (*AzCliKeyVaultSecret, error)
+	// SetKeyVaultSecret creates or updates the secret of the vault, returning the new version of the secret.
+	SetKeyVaultSecret(
+		ctx context.Context,
+		subscriptionId string,
+		vaultName string,
+		secretName string,
+		value string,
+	) (*AzCliKeyVaultSecret, error)
+	// CreateKeyVault creates a standard vault authorizing its data plane operations with Azure RBAC.
+	CreateKeyVault(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		vaultName string,
+		location string,
+		tenantId string,
+	) (*AzCliKeyVault, error)
 	GetAppConfig(
 		ctx context.Context, subscriptionId string, resourceGroupName string, configName string) (*AzCliAppConfig, error)
 	PurgeApim(ctx context.Context, subscriptionId string, apimName string, location string) error
@@ -78,6 +96,37 @@ type AzCli interface {
 		appName string,
 		deployZipFile io.Reader,
 	) (*string, error)
+	// UpdateAppServiceSiteConfig updates the settings of the site configuration of the App Service differing from
+	// config. It returns false when no setting differs.
+	UpdateAppServiceSiteConfig(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		config AzCliAppServiceSiteConfig,
+	) (bool, error)
+	// MissingPermissions returns the actions the signed-in principal isn't allowed to perform at the scope.
+	MissingPermissions(ctx context.Context, subscriptionId string, scope string, actions []string) ([]string, error)
+	// RunContainer runs the container group in Azure Container Instances until its container exits, streaming the
+	// logs of the container to logs, then deletes the container group. It returns the exit code of the container.
+	RunContainer(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		containerGroupName string,
+		group azsdk.ContainerGroup,
+		logs io.Writer,
+	) (int, error)
+	// BindAppServiceCustomDomain binds the custom domain to the App Service, or Function App, and secures it with a
+	// certificate managed by App Service when managedCertificate is true.
+	BindAppServiceCustomDomain(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		hostName string,
+		managedCertificate bool,
+	) error
 	DeployFunctionAppUsingZipFile(
 		ctx context.Context,
 		subscriptionID string,
@@ -85,17 +134,34 @@ type AzCli interface {
 		funcName string,
 		deployZipFile io.Reader,
 	) (*string, error)
+	// PublishFunctionAppUsingZipFile deploys the package of a function app hosted on the Flex Consumption plan with
+	// the one deploy API.
+	PublishFunctionAppUsingZipFile(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+		deployZipFile io.Reader,
+		remoteBuild bool,
+	) (*string, error)
 	GetFunctionAppProperties(
 		ctx context.Context,
 		subscriptionID string,
 		resourceGroup string,
 		funcName string,
 	) (*AzCliFunctionAppProperties, error)
+	GetFunctionAppPlanTier(
+		ctx context.Context,
+		subscriptionID string,
+		resourceGroup string,
+		funcName string,
+	) (string, error)
 	DeployToSubscription(
 		ctx context.Context, subscriptionId, deploymentName string,
 		armTemplate azure.RawArmTemplate,
 		parameters azure.ArmParameters,
-		location string) (
+		location string,
+		tags map[string]*string) (
 		*armresources.DeploymentExtended, error)
 	DeployToResourceGroup(
 		ctx context.Context,
@@ -118,6 +184,47 @@ type AzCli interface {
 		resourceGroupName string,
 		listOptions *ListResourceGroupResourcesOptions,
 	) ([]AzCliResource, error)
+	// PullBicepModule downloads a Bicep module from an Azure Container Registry, at a tag or a digest. The registry
+	// can belong to another subscription, and tenant, than the environment.
+	PullBicepModule(
+		ctx context.Context,
+		subscriptionId string,
+		loginServer string,
+		repository string,
+		reference string,
+	) (*azsdk.BicepModule, error)
+	// DeleteResource deletes a resource by id, with the latest API version of its resource type.
+	DeleteResource(ctx context.Context, subscriptionId string, resourceId string) error
+	// GetResourceProviderState returns the registration state of a resource provider, like Registered or NotRegistered.
+	GetResourceProviderState(ctx context.Context, subscriptionId string, namespace string) (string, error)
+	// RegisterResourceProvider starts the registration of a resource provider in the subscription.
+	RegisterResourceProvider(ctx context.Context, subscriptionId string, namespace string) error
+	// UpdateResourceTags merges the tags into the existing tags of a resource, or of a resource group.
+	UpdateResourceTags(ctx context.Context, subscriptionId string, resourceId string, tags map[string]string) error
+	// QueryResources runs an Azure Resource Graph query against the resources of the subscription. The query must
+	// project the id, name, type, location and tags of the resources.
+	QueryResources(ctx context.Context, subscriptionId string, query string) ([]AzCliResource, error)
+	// ListLocationUsages lists the quotas of a resource provider in a region, like the cores of the VM families of
+	// Microsoft.Compute.
+	ListLocationUsages(
+		ctx context.Context,
+		subscriptionId string,
+		providerNamespace string,
+		location string,
+	) ([]*azsdk.QuotaUsage, error)
+	// ListComputeSkus lists the sizes of virtual machines offered in a region.
+	ListComputeSkus(ctx context.Context, subscriptionId string, location string) ([]*azsdk.ComputeSku, error)
+	// ListAppServiceRegions lists the display names of the regions offering a pricing tier of App Service plans.
+	ListAppServiceRegions(ctx context.Context, subscriptionId string, tier string) ([]string, error)
+	// CreateServiceConnection creates or updates a Service Connector connection of a compute resource, and returns the
+	// configuration values set in the app settings of the compute resource for the connection.
+	CreateServiceConnection(
+		ctx context.Context,
+		subscriptionId string,
+		sourceResourceId string,
+		name string,
+		connection azsdk.ServiceConnection,
+	) ([]azsdk.ServiceConnectionConfiguration, error)
 	ListSubscriptionDeploymentOperations(
 		ctx context.Context,
 		subscriptionId string,
@@ -139,6 +246,15 @@ type AzCli interface {
 		applicationName string,
 		roleToAssign string,
 	) (json.RawMessage, error)
+	// EnsureRoleAssignment assigns a role, by name, to a principal on a scope, like the id of a resource. Assigning a
+	// role which is already assigned succeeds.
+	EnsureRoleAssignment(
+		ctx context.Context,
+		subscriptionId string,
+		scope string,
+		roleName string,
+		principalId string,
+	) error
 	GetAppServiceProperties(
 		ctx context.Context,
 		subscriptionId string,
@@ -213,15 +329,18 @@ type AzCliDeploymentOutput struct {
 }
 
 type AzCliResource struct {
-	Id       string `json:"id"`
-	Name     string `json:"name"`
-	Type     string `json:"type"`
-	Location string `json:"location"`
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Location string            `json:"location"`
+	Tags     map[string]string `json:"tags,omitempty"`
 }
 
 type AzCliResourceExtended struct {
 	AzCliResource
 	Kind string `json:"kind"`
+	// The principal id of the managed identity of the resource, empty when it has none
+	PrincipalId string `json:"principalId"`
 }
 
 type AzCliDeploymentResourceReference struct {
@@ -331,13 +450,11 @@ func (cli *azCli) UserAgent() string {
 }
 
 func (cli *azCli) createDefaultClientOptionsBuilder(ctx context.Context) *azsdk.ClientOptionsBuilder {
-	return azsdk.NewClientOptionsBuilder().
-		WithTransport(httputil.GetHttpClient(ctx)).
-		WithPerCallPolicy(azsdk.NewUserAgentPolicy(cli.UserAgent()))
+	return azsdk.DefaultClientOptionsBuilder(httputil.GetHttpClient(ctx), cli.UserAgent()).
+		WithPerCallPolicy(azsdk.NewTelemetryPolicy())
 }
 
 func clientOptionsBuilder(httpClient httputil.HttpClient, userAgent string) *azsdk.ClientOptionsBuilder {
-	return azsdk.NewClientOptionsBuilder().
-		WithTransport(httpClient).
-		WithPerCallPolicy(azsdk.NewUserAgentPolicy(userAgent))
+	return azsdk.DefaultClientOptionsBuilder(httpClient, userAgent).
+		WithPerCallPolicy(azsdk.NewTelemetryPolicy())
 }
@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RunContainer(t *testing.T) {
+	const groupPath = "/providers/Microsoft.ContainerInstance/containerGroups/azd-hook-postprovision"
+
+	interval := containerPollInterval
+	containerPollInterval = time.Millisecond
+	t.Cleanup(func() { containerPollInterval = interval })
+
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzCliFromMockContext(mockContext)
+
+	var request map[string]any
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, groupPath)
+	}).RespondFn(func(r *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &request))
+
+		return mocks.CreateEmptyHttpResponse(r, http.StatusCreated)
+	})
+
+	states := []string{azsdk.ContainerStateWaiting, azsdk.ContainerStateRunning, azsdk.ContainerStateTerminated}
+	polls := 0
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, groupPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		state := states[polls]
+		polls++
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"properties": map[string]any{
+				"provisioningState": "Succeeded",
+				"containers": []any{
+					map[string]any{
+						"properties": map[string]any{
+							"instanceView": map[string]any{
+								"currentState": map[string]any{"state": state, "exitCode": 3},
+							},
+						},
+					},
+				},
+			},
+		})
+	})
+
+	logs := []string{"connecting\n", "connecting\nschema updated\n"}
+	reads := 0
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, "/logs")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		content := logs[reads]
+		reads++
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"content": content})
+	})
+
+	deleted := false
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodDelete && strings.HasSuffix(request.URL.Path, groupPath)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		deleted = true
+		return mocks.CreateEmptyHttpResponse(request, http.StatusOK)
+	})
+
+	output := &bytes.Buffer{}
+	exitCode, err := azCli.RunContainer(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP_ID",
+		"azd-hook-postprovision",
+		azsdk.ContainerGroup{
+			Location:    "eastus2",
+			Image:       "mcr.microsoft.com/azure-cli",
+			Command:     []string{"bash", "-c", "echo schema updated"},
+			Environment: map[string]string{"AZURE_ENV_NAME": "dev"},
+			Cpu:         1,
+			MemoryInGB:  1.5,
+			IdentityId:  "IDENTITY_ID",
+			SubnetId:    "SUBNET_ID",
+		},
+		output,
+	)
+
+	require.NoError(t, err)
+	require.Equal(t, 3, exitCode)
+	require.Equal(t, "connecting\nschema updated\n", output.String())
+	require.True(t, deleted)
+
+	properties := request["properties"].(map[string]any)
+	require.Equal(t, "Never", properties["restartPolicy"])
+	require.Equal(t, []any{map[string]any{"id": "SUBNET_ID"}}, properties["subnetIds"])
+	require.Equal(t, map[string]any{
+		"type":                   "UserAssigned",
+		"userAssignedIdentities": map[string]any{"IDENTITY_ID": map[string]any{}},
+	}, request["identity"])
+
+	container := properties["containers"].([]any)[0].(map[string]any)["properties"].(map[string]any)
+	require.Equal(t, []any{
+		map[string]any{"name": "AZURE_ENV_NAME", "secureValue": "dev"},
+	}, container["environmentVariables"])
+}
@@ -81,6 +81,49 @@ func Test_GetFunctionAppProperties(t *testing.T) {
 	})
 }
 
+func Test_GetFunctionAppPlanTier(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzCliFromMockContext(mockContext)
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.Contains(request.URL.Path, "/providers/Microsoft.Web/sites/FUNC_APP_NAME")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappservice.WebAppsClientGetResponse{
+			Site: armappservice.Site{
+				Name: convert.RefOf("FUNC_APP_NAME"),
+				Properties: &armappservice.SiteProperties{
+					ServerFarmID: convert.RefOf(
+						"/subscriptions/SUBSCRIPTION_ID/resourceGroups/PLAN_GROUP/providers/Microsoft.Web/serverfarms/PLAN"),
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/resourceGroups/PLAN_GROUP/providers/Microsoft.Web/serverfarms/PLAN")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		response := armappservice.PlansClientGetResponse{
+			Plan: armappservice.Plan{
+				Name: convert.RefOf("PLAN"),
+				SKU: &armappservice.SKUDescription{
+					Name: convert.RefOf("FC1"),
+					Tier: convert.RefOf(FlexConsumptionTier),
+				},
+			},
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+	})
+
+	tier, err := azCli.GetFunctionAppPlanTier(*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP_ID", "FUNC_APP_NAME")
+	require.NoError(t, err)
+	require.Equal(t, FlexConsumptionTier, tier)
+}
+
 func Test_DeployFunctionAppUsingZipFile(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		ran := false
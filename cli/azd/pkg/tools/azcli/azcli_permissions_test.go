@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azcli
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PermissionsAllow(t *testing.T) {
+	contributor := []azsdk.Permission{{
+		Actions:    []string{"*"},
+		NotActions: []string{"Microsoft.Authorization/*/Delete", "Microsoft.Authorization/*/Write"},
+	}}
+
+	websiteContributor := []azsdk.Permission{{Actions: []string{"Microsoft.Web/sites/*", "*/read"}}}
+
+	tests := []struct {
+		name        string
+		permissions []azsdk.Permission
+		action      string
+		allowed     bool
+	}{
+		{"Wildcard", contributor, DeploymentsWriteAction, true},
+		{"NotAction", contributor, RoleAssignmentsWriteAction, false},
+		{"CaseInsensitive", websiteContributor, "microsoft.web/sites/publish/Action", true},
+		{"OtherNamespace", websiteContributor, DeploymentsWriteAction, false},
+		{"ReadOnly", websiteContributor, "Microsoft.Resources/deployments/read", true},
+		{"NoPermission", nil, DeploymentsWriteAction, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.allowed, permissionsAllow(test.permissions, test.action))
+		})
+	}
+}
+
+func Test_MissingPermissions(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azCli := newAzCliFromMockContext(mockContext)
+
+	// The permissions of the role assignments are returned in pages
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Authorization/permissions")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if request.URL.Query().Get("page") == "" {
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+				"value":    []any{map[string]any{"actions": []string{"*/read"}}},
+				"nextLink": "https://management.azure.com" + request.URL.Path + "?page=2",
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"value": []any{map[string]any{"actions": []string{"Microsoft.Resources/deployments/*"}}},
+		})
+	})
+
+	missing, err := azCli.MissingPermissions(
+		*mockContext.Context,
+		"SUBSCRIPTION_ID",
+		"/subscriptions/SUBSCRIPTION_ID",
+		[]string{DeploymentsWriteAction, RoleAssignmentsWriteAction},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{RoleAssignmentsWriteAction}, missing)
+}
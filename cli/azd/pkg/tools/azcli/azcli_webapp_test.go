@@ -0,0 +1,204 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UpdateAppServiceSiteConfig(t *testing.T) {
+	mockSiteConfig := func(mockContext *mocks.MockContext) {
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/sites/WEB_APP_NAME/config/web")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			response := armappservice.WebAppsClientGetConfigurationResponse{
+				SiteConfigResource: armappservice.SiteConfigResource{
+					Properties: &armappservice.SiteConfig{
+						LinuxFxVersion: convert.RefOf("PYTHON|3.11"),
+						AppCommandLine: convert.RefOf(""),
+						AlwaysOn:       convert.RefOf(false),
+						Cors: &armappservice.CorsSettings{
+							AllowedOrigins: []*string{
+								convert.RefOf("https://www.contoso.com"),
+								convert.RefOf("https://admin.contoso.com"),
+							},
+						},
+					},
+				},
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+		})
+	}
+
+	t.Run("UpdatesDifferingSettings", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+		mockSiteConfig(mockContext)
+
+		var patch armappservice.SiteConfigResource
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPatch &&
+				strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/sites/WEB_APP_NAME/config/web")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &patch))
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, patch)
+		})
+
+		updated, err := azCli.UpdateAppServiceSiteConfig(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"WEB_APP_NAME",
+			AzCliAppServiceSiteConfig{
+				LinuxFxVersion:  convert.RefOf("PYTHON|3.11"),
+				AppCommandLine:  convert.RefOf("gunicorn app:app"),
+				HealthCheckPath: convert.RefOf("/health"),
+				Cors: &AzCliCorsSettings{
+					AllowedOrigins: []string{"https://www.contoso.com"},
+				},
+			},
+		)
+		require.NoError(t, err)
+		require.True(t, updated)
+		// The runtime stack is unchanged, it isn't part of the update
+		require.Nil(t, patch.Properties.LinuxFxVersion)
+		require.Equal(t, "gunicorn app:app", *patch.Properties.AppCommandLine)
+		require.Equal(t, "/health", *patch.Properties.HealthCheckPath)
+		require.Nil(t, patch.Properties.AlwaysOn)
+		require.Equal(t, []*string{convert.RefOf("https://www.contoso.com")}, patch.Properties.Cors.AllowedOrigins)
+	})
+
+	t.Run("SkipsUnchangedSettings", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+		mockSiteConfig(mockContext)
+
+		updated, err := azCli.UpdateAppServiceSiteConfig(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"WEB_APP_NAME",
+			AzCliAppServiceSiteConfig{
+				LinuxFxVersion: convert.RefOf("PYTHON|3.11"),
+				AlwaysOn:       convert.RefOf(false),
+				Cors: &AzCliCorsSettings{
+					AllowedOrigins: []string{"https://admin.contoso.com", "https://www.contoso.com"},
+				},
+			},
+		)
+		require.NoError(t, err)
+		require.False(t, updated)
+	})
+}
+
+func Test_BindAppServiceCustomDomain(t *testing.T) {
+	const sitePath = "/providers/Microsoft.Web/sites/WEB_APP_NAME"
+	const bindingPath = sitePath + "/hostNameBindings/www.contoso.com"
+
+	mockSite := func(mockContext *mocks.MockContext) {
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, sitePath)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			response := armappservice.WebAppsClientGetResponse{
+				Site: armappservice.Site{
+					Location: convert.RefOf("eastus2"),
+					Name:     convert.RefOf("WEB_APP_NAME"),
+					Properties: &armappservice.SiteProperties{
+						DefaultHostName:            convert.RefOf("WEB_APP_NAME.azurewebsites.net"),
+						CustomDomainVerificationID: convert.RefOf("VERIFICATION_ID"),
+						ServerFarmID:               convert.RefOf("PLAN_ID"),
+					},
+				},
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+		})
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, bindingPath)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		})
+	}
+
+	t.Run("Unverified", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+		mockSite(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, bindingPath)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusBadRequest)
+		})
+
+		err := azCli.BindAppServiceCustomDomain(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP_ID", "WEB_APP_NAME", "www.contoso.com", true)
+
+		var verificationErr *DomainVerificationError
+		require.ErrorAs(t, err, &verificationErr)
+		require.Equal(t, []AzCliDnsRecord{
+			{Type: "CNAME", Name: "www.contoso.com", Value: "WEB_APP_NAME.azurewebsites.net"},
+			{Type: "TXT", Name: "asuid.www.contoso.com", Value: "VERIFICATION_ID"},
+		}, verificationErr.Records)
+	})
+
+	t.Run("ManagedCertificate", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+		mockSite(mockContext)
+
+		bindings := []armappservice.HostNameBinding{}
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut && strings.HasSuffix(request.URL.Path, bindingPath)
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			var binding armappservice.HostNameBinding
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &binding))
+			bindings = append(bindings, binding)
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, binding)
+		})
+
+		var certificate armappservice.Certificate
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodPut &&
+				strings.HasSuffix(request.URL.Path, "/providers/Microsoft.Web/certificates/www.contoso.com")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(body, &certificate))
+
+			response := certificate
+			response.Properties.Thumbprint = convert.RefOf("THUMBPRINT")
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+		})
+
+		err := azCli.BindAppServiceCustomDomain(
+			*mockContext.Context, "SUBSCRIPTION_ID", "RESOURCE_GROUP_ID", "WEB_APP_NAME", "www.contoso.com", true)
+		require.NoError(t, err)
+
+		require.Equal(t, "www.contoso.com", *certificate.Properties.CanonicalName)
+		require.Equal(t, "PLAN_ID", *certificate.Properties.ServerFarmID)
+		require.Len(t, bindings, 2)
+		require.Equal(t, armappservice.SSLStateSniEnabled, *bindings[1].Properties.SSLState)
+		require.Equal(t, "THUMBPRINT", *bindings[1].Properties.Thumbprint)
+	})
+}
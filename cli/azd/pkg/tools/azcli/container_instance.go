@@ -0,0 +1,94 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+// How often the state, and the logs, of a container run by RunContainer are polled
+var containerPollInterval = 5 * time.Second
+
+// RunContainer runs the container group until its container exits, streaming the logs of the container to logs, and
+// returns the exit code of the container. The container group is deleted once the container exits, or when ctx is
+// cancelled.
+func (cli *azCli) RunContainer(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	containerGroupName string,
+	group azsdk.ContainerGroup,
+	logs io.Writer,
+) (int, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return -1, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewContainerInstanceClient(credential, options)
+	if err != nil {
+		return -1, fmt.Errorf("creating ContainerInstance client: %w", err)
+	}
+
+	if err := client.CreateOrUpdate(ctx, subscriptionId, resourceGroup, containerGroupName, group); err != nil {
+		return -1, fmt.Errorf("failed creating container group %s: %w", containerGroupName, err)
+	}
+
+	defer func() {
+		// The container group is removed even when ctx is cancelled, to not leave the container group behind
+		deleteCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		if err := client.Delete(deleteCtx, subscriptionId, resourceGroup, containerGroupName); err != nil {
+			log.Printf("failed deleting container group %s: %v", containerGroupName, err)
+		}
+	}()
+
+	written := 0
+	streamLogs := func() {
+		content, err := client.GetLogs(ctx, subscriptionId, resourceGroup, containerGroupName)
+		if err != nil {
+			log.Printf("failed reading logs of container group %s: %v", containerGroupName, err)
+			return
+		}
+
+		// The logs are read from the start of the container, only the lines not yet written are written
+		if len(content) > written {
+			_, _ = io.WriteString(logs, content[written:])
+			written = len(content)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-time.After(containerPollInterval):
+		}
+
+		state, err := client.GetState(ctx, subscriptionId, resourceGroup, containerGroupName)
+		if err != nil {
+			return -1, fmt.Errorf("failed retrieving container group %s: %w", containerGroupName, err)
+		}
+
+		if strings.EqualFold(state.ProvisioningState, "Failed") {
+			return -1, fmt.Errorf("container group %s failed: %s", containerGroupName, state.Detail)
+		}
+
+		if state.State == azsdk.ContainerStateWaiting {
+			continue
+		}
+
+		streamLogs()
+
+		if state.State == azsdk.ContainerStateTerminated {
+			return state.ExitCode, nil
+		}
+	}
+}
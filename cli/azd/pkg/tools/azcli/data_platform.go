@@ -0,0 +1,311 @@
+package azcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const (
+	databricksResourceApiVersion = "2023-02-01"
+	synapseResourceApiVersion    = "2021-06-01"
+)
+
+// DataPlatformService deploys notebooks, jobs and pipelines to Azure Databricks and Synapse workspaces, through the
+// APIs of the workspaces authenticated with the current Azure principal.
+type DataPlatformService interface {
+	// Gets the url of a Databricks workspace, like https://adb-1234.5.azuredatabricks.net
+	GetDatabricksWorkspaceUrl(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		workspaceName string,
+	) (string, error)
+	// Imports a notebook into a Databricks workspace
+	ImportDatabricksNotebook(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		workspaceName string,
+		notebook *azsdk.DatabricksNotebook,
+	) error
+	// Creates a job of a Databricks workspace, or updates the job with the same name, and returns the id of the job
+	UpsertDatabricksJob(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		workspaceName string,
+		settings map[string]any,
+	) (int64, error)
+	// Gets the url of Synapse Studio for a Synapse workspace
+	GetSynapseWorkspaceUrl(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		workspaceName string,
+	) (string, error)
+	// Creates or updates an artifact of a Synapse workspace, like a notebook or a pipeline
+	PutSynapseArtifact(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		workspaceName string,
+		kind string,
+		name string,
+		properties json.RawMessage,
+	) error
+}
+
+type dataPlatformService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
+	userAgent          string
+}
+
+// Creates a new instance of the DataPlatformService
+func NewDataPlatformService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
+) DataPlatformService {
+	return &dataPlatformService{
+		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
+		userAgent:          azdinternal.MakeUserAgentString(""),
+	}
+}
+
+// The properties of workspaces read by the service
+type workspaceProperties struct {
+	// The url of Databricks workspaces
+	WorkspaceUrl string `json:"workspaceUrl"`
+	// The endpoints of Synapse workspaces
+	ConnectivityEndpoints map[string]string `json:"connectivityEndpoints"`
+}
+
+func (ds *dataPlatformService) GetDatabricksWorkspaceUrl(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+) (string, error) {
+	credential, err := ds.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	return ds.databricksWorkspaceUrl(ctx, credential, subscriptionId, resourceGroupName, workspaceName)
+}
+
+func (ds *dataPlatformService) ImportDatabricksNotebook(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+	notebook *azsdk.DatabricksNotebook,
+) error {
+	client, err := ds.createDatabricksClient(ctx, subscriptionId, resourceGroupName, workspaceName)
+	if err != nil {
+		return err
+	}
+
+	return client.ImportNotebook(ctx, notebook)
+}
+
+func (ds *dataPlatformService) UpsertDatabricksJob(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+	settings map[string]any,
+) (int64, error) {
+	client, err := ds.createDatabricksClient(ctx, subscriptionId, resourceGroupName, workspaceName)
+	if err != nil {
+		return 0, err
+	}
+
+	return client.UpsertJob(ctx, settings)
+}
+
+func (ds *dataPlatformService) GetSynapseWorkspaceUrl(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+) (string, error) {
+	credential, err := ds.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	return ds.synapseEndpoint(ctx, credential, subscriptionId, resourceGroupName, workspaceName, "web")
+}
+
+func (ds *dataPlatformService) PutSynapseArtifact(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+	kind string,
+	name string,
+	properties json.RawMessage,
+) error {
+	credential, err := ds.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := ds.synapseEndpoint(ctx, credential, subscriptionId, resourceGroupName, workspaceName, "dev")
+	if err != nil {
+		return err
+	}
+
+	client, err := azsdk.NewSynapseClient(
+		endpoint,
+		credential,
+		clientOptionsBuilder(ds.httpClient, ds.userAgent).BuildCoreClientOptions(),
+	)
+	if err != nil {
+		return fmt.Errorf("creating Synapse client: %w", err)
+	}
+
+	if err := client.PutArtifact(ctx, kind, name, properties); err != nil {
+		return fmt.Errorf("deploying %s '%s': %w", kind, name, err)
+	}
+
+	return nil
+}
+
+func (ds *dataPlatformService) createDatabricksClient(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+) (*azsdk.DatabricksClient, error) {
+	credential, err := ds.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceUrl, err := ds.databricksWorkspaceUrl(ctx, credential, subscriptionId, resourceGroupName, workspaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := azsdk.NewDatabricksClient(
+		workspaceUrl,
+		credential,
+		clientOptionsBuilder(ds.httpClient, ds.userAgent).BuildCoreClientOptions(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating Databricks client: %w", err)
+	}
+
+	return client, nil
+}
+
+func (ds *dataPlatformService) databricksWorkspaceUrl(
+	ctx context.Context,
+	credential azcore.TokenCredential,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+) (string, error) {
+	properties, err := ds.workspaceProperties(
+		ctx,
+		credential,
+		subscriptionId,
+		resourceGroupName,
+		"Microsoft.Databricks",
+		workspaceName,
+		databricksResourceApiVersion,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if properties.WorkspaceUrl == "" {
+		return "", fmt.Errorf("databricks workspace '%s' has no url", workspaceName)
+	}
+
+	return "https://" + properties.WorkspaceUrl, nil
+}
+
+// synapseEndpoint gets an endpoint of a Synapse workspace, like its development endpoint.
+func (ds *dataPlatformService) synapseEndpoint(
+	ctx context.Context,
+	credential azcore.TokenCredential,
+	subscriptionId string,
+	resourceGroupName string,
+	workspaceName string,
+	endpointName string,
+) (string, error) {
+	properties, err := ds.workspaceProperties(
+		ctx,
+		credential,
+		subscriptionId,
+		resourceGroupName,
+		"Microsoft.Synapse",
+		workspaceName,
+		synapseResourceApiVersion,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := properties.ConnectivityEndpoints[endpointName]
+	if endpoint == "" {
+		return "", fmt.Errorf("synapse workspace '%s' has no %s endpoint", workspaceName, endpointName)
+	}
+
+	return endpoint, nil
+}
+
+func (ds *dataPlatformService) workspaceProperties(
+	ctx context.Context,
+	credential azcore.TokenCredential,
+	subscriptionId string,
+	resourceGroupName string,
+	providerNamespace string,
+	workspaceName string,
+	apiVersion string,
+) (*workspaceProperties, error) {
+	options := clientOptionsBuilder(ds.httpClient, ds.userAgent).BuildArmClientOptions()
+	client, err := armresources.NewClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Resource client: %w", err)
+	}
+
+	workspace, err := client.Get(
+		ctx,
+		resourceGroupName,
+		providerNamespace,
+		"",
+		"workspaces",
+		workspaceName,
+		apiVersion,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting workspace '%s': %w", workspaceName, err)
+	}
+
+	// The properties are returned untyped by the generic resources client
+	propertiesJson, err := json.Marshal(workspace.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := &workspaceProperties{}
+	if err := json.Unmarshal(propertiesJson, properties); err != nil {
+		return nil, fmt.Errorf("reading properties of workspace '%s': %w", workspaceName, err)
+	}
+
+	return properties, nil
+}
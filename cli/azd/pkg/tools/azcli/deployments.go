@@ -13,6 +13,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/shutdown"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/internal"
 )
 
@@ -38,6 +39,29 @@ func (cli *azCli) GetSubscriptionDeployment(
 	return &deployment.DeploymentExtended, nil
 }
 
+// ListSubscriptionDeployments returns the deployments of the subscription, which are limited to 800 per subscription.
+func (cli *azCli) ListSubscriptionDeployments(
+	ctx context.Context,
+	subscriptionId string,
+) ([]*armresources.DeploymentExtended, error) {
+	deploymentClient, err := cli.createDeploymentsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("creating deployments client: %w", err)
+	}
+
+	var deployments []*armresources.DeploymentExtended
+	pager := deploymentClient.NewListAtSubscriptionScopePager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing deployments of subscription: %w", err)
+		}
+		deployments = append(deployments, page.Value...)
+	}
+
+	return deployments, nil
+}
+
 func (cli *azCli) GetResourceGroupDeployment(
 	ctx context.Context,
 	subscriptionId string,
@@ -85,6 +109,7 @@ func (cli *azCli) DeployToSubscription(
 	armTemplate azure.RawArmTemplate,
 	parameters azure.ArmParameters,
 	location string,
+	tags map[string]*string,
 ) (*armresources.DeploymentExtended, error) {
 	deploymentClient, err := cli.createDeploymentsClient(ctx, subscriptionId)
 	if err != nil {
@@ -100,11 +125,19 @@ func (cli *azCli) DeployToSubscription(
 				Mode:       to.Ptr(armresources.DeploymentModeIncremental),
 			},
 			Location: to.Ptr(location),
+			Tags:     tags,
 		}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("starting deployment to subscription: %w", err)
 	}
 
+	// Cancel the deployment in Azure when azd is cancelled, instead of leaving it running unattended
+	unregister := shutdown.Register(ctx, "cancel subscription deployment", func(ctx context.Context) error {
+		_, err := deploymentClient.CancelAtSubscriptionScope(ctx, deploymentName, nil)
+		return err
+	})
+	defer unregister()
+
 	// wait for deployment creation
 	deployResult, err := createFromTemplateOperation.PollUntilDone(ctx, nil)
 	if err != nil {
@@ -142,6 +175,13 @@ func (cli *azCli) DeployToResourceGroup(
 		return nil, fmt.Errorf("starting deployment to resource group: %w", err)
 	}
 
+	// Cancel the deployment in Azure when azd is cancelled, instead of leaving it running unattended
+	unregister := shutdown.Register(ctx, "cancel resource group deployment", func(ctx context.Context) error {
+		_, err := deploymentClient.Cancel(ctx, resourceGroup, deploymentName, nil)
+		return err
+	})
+	defer unregister()
+
 	// wait for deployment creation
 	deployResult, err := createFromTemplateOperation.PollUntilDone(ctx, nil)
 	if err != nil {
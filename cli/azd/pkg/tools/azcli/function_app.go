@@ -4,10 +4,16 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/profiling"
 )
 
+// The SKU tier of the App Service plans of the Flex Consumption hosting plan of Azure Functions
+const FlexConsumptionTier = "FlexConsumption"
+
 type AzCliFunctionAppProperties struct {
 	HostNames []string
 }
@@ -45,10 +51,103 @@ func (cli *azCli) DeployFunctionAppUsingZipFile(
 		return nil, err
 	}
 
+	endStage := profiling.TrackStage(fmt.Sprintf("zip upload %s", appName))
 	response, err := client.Deploy(ctx, appName, deployZipFile)
+	endStage()
 	if err != nil {
 		return nil, err
 	}
 
 	return convert.RefOf(response.StatusText), nil
 }
+
+// GetFunctionAppPlanTier returns the SKU tier of the App Service plan hosting the function app, like Dynamic or
+// FlexConsumption.
+func (cli *azCli) GetFunctionAppPlanTier(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+) (string, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	webApp, err := client.Get(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving function app properties: %w", err)
+	}
+
+	if webApp.Properties == nil || webApp.Properties.ServerFarmID == nil {
+		return "", fmt.Errorf("function app %s has no hosting plan", appName)
+	}
+
+	planResourceGroup, planName, err := planOfId(*webApp.Properties.ServerFarmID)
+	if err != nil {
+		return "", err
+	}
+
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	plansClient, err := armappservice.NewPlansClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", fmt.Errorf("creating Plans client: %w", err)
+	}
+
+	plan, err := plansClient.Get(ctx, planResourceGroup, planName, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving hosting plan of function app %s: %w", appName, err)
+	}
+
+	if plan.SKU == nil || plan.SKU.Tier == nil {
+		return "", nil
+	}
+
+	return *plan.SKU.Tier, nil
+}
+
+func (cli *azCli) PublishFunctionAppUsingZipFile(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	deployZipFile io.Reader,
+	remoteBuild bool,
+) (*string, error) {
+	client, err := cli.createZipDeployClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	endStage := profiling.TrackStage(fmt.Sprintf("zip upload %s", appName))
+	response, err := client.Publish(ctx, appName, deployZipFile, remoteBuild)
+	endStage()
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.RefOf(response.StatusText), nil
+}
+
+// planOfId returns the resource group and the name of the App Service plan of the id, like
+// /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Web/serverfarms/<plan>.
+func planOfId(planId string) (string, string, error) {
+	segments := strings.Split(strings.Trim(planId, "/"), "/")
+	resourceGroup := ""
+	for i := 0; i+1 < len(segments); i++ {
+		if strings.EqualFold(segments[i], "resourceGroups") {
+			resourceGroup = segments[i+1]
+		}
+	}
+
+	if resourceGroup == "" || len(segments) < 2 || !strings.EqualFold(segments[len(segments)-2], "serverfarms") {
+		return "", "", fmt.Errorf("'%s' isn't the id of an App Service plan", planId)
+	}
+
+	return resourceGroup, segments[len(segments)-1], nil
+}
@@ -0,0 +1,167 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const iotHubResourceApiVersion = "2021-07-02"
+
+// IotHubService manages the IoT Edge deployments of IoT hubs, which apply the modules of a deployment manifest to
+// the devices matching their target condition.
+type IotHubService interface {
+	// Lists the deployments, and other configurations, of the IoT hub
+	ListDeployments(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		hubName string,
+	) ([]*azsdk.IotHubConfiguration, error)
+	// Creates a deployment on the IoT hub
+	CreateDeployment(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		hubName string,
+		deployment *azsdk.IotHubConfiguration,
+	) error
+	// Deletes a deployment of the IoT hub, the devices keep their modules until another deployment targets them
+	DeleteDeployment(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		hubName string,
+		deploymentId string,
+	) error
+}
+
+type iotHubService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
+	userAgent          string
+}
+
+// Creates a new instance of the IotHubService
+func NewIotHubService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
+) IotHubService {
+	return &iotHubService{
+		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
+		userAgent:          azdinternal.MakeUserAgentString(""),
+	}
+}
+
+func (hs *iotHubService) ListDeployments(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	hubName string,
+) ([]*azsdk.IotHubConfiguration, error) {
+	client, err := hs.createIotHubClient(ctx, subscriptionId, resourceGroupName, hubName)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := client.Configurations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployments of IoT hub '%s': %w", hubName, err)
+	}
+
+	return deployments, nil
+}
+
+func (hs *iotHubService) CreateDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	hubName string,
+	deployment *azsdk.IotHubConfiguration,
+) error {
+	client, err := hs.createIotHubClient(ctx, subscriptionId, resourceGroupName, hubName)
+	if err != nil {
+		return err
+	}
+
+	if err := client.CreateConfiguration(ctx, deployment); err != nil {
+		return fmt.Errorf("creating deployment '%s': %w", deployment.Id, err)
+	}
+
+	return nil
+}
+
+func (hs *iotHubService) DeleteDeployment(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	hubName string,
+	deploymentId string,
+) error {
+	client, err := hs.createIotHubClient(ctx, subscriptionId, resourceGroupName, hubName)
+	if err != nil {
+		return err
+	}
+
+	if err := client.DeleteConfiguration(ctx, deploymentId); err != nil {
+		return fmt.Errorf("deleting deployment '%s': %w", deploymentId, err)
+	}
+
+	return nil
+}
+
+// createIotHubClient creates a client for the data plane of the hub, reading its host name from the hub resource.
+func (hs *iotHubService) createIotHubClient(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	hubName string,
+) (*azsdk.IotHubClient, error) {
+	credential, err := hs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := clientOptionsBuilder(hs.httpClient, hs.userAgent).BuildArmClientOptions()
+	resourcesClient, err := armresources.NewClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Resource client: %w", err)
+	}
+
+	hub, err := resourcesClient.Get(
+		ctx,
+		resourceGroupName,
+		"Microsoft.Devices",
+		"",
+		"IotHubs",
+		hubName,
+		iotHubResourceApiVersion,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("getting IoT hub '%s': %w", hubName, err)
+	}
+
+	properties, _ := hub.Properties.(map[string]any)
+	hostName, _ := properties["hostName"].(string)
+	if hostName == "" {
+		return nil, fmt.Errorf("IoT hub '%s' has no host name", hubName)
+	}
+
+	client, err := azsdk.NewIotHubClient(
+		hostName,
+		credential,
+		clientOptionsBuilder(hs.httpClient, hs.userAgent).BuildCoreClientOptions(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating IoT hub client: %w", err)
+	}
+
+	return client, nil
+}
@@ -91,6 +91,74 @@ func (cli *azCli) GetKeyVaultSecret(
 	}, nil
 }
 
+func (cli *azCli) SetKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	value string,
+) (*AzCliKeyVaultSecret, error) {
+	vaultUrl := vaultName
+	if !strings.Contains(strings.ToLower(vaultName), "https://") {
+		vaultUrl = fmt.Sprintf("https://%s.vault.azure.net", vaultName)
+	}
+
+	client, err := cli.createSecretsDataClient(ctx, subscriptionId, vaultUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.SetSecret(ctx, secretName, azsecrets.SetSecretParameters{Value: &value}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("setting key vault secret: %w", err)
+	}
+
+	return &AzCliKeyVaultSecret{
+		Id:   response.SecretBundle.ID.Version(),
+		Name: response.SecretBundle.ID.Name(),
+	}, nil
+}
+
+func (cli *azCli) CreateKeyVault(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	vaultName string,
+	location string,
+	tenantId string,
+) (*AzCliKeyVault, error) {
+	client, err := cli.createKeyVaultClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginCreateOrUpdate(ctx, resourceGroupName, vaultName, armkeyvault.VaultCreateOrUpdateParameters{
+		Location: &location,
+		Properties: &armkeyvault.VaultProperties{
+			TenantID: &tenantId,
+			SKU: &armkeyvault.SKU{
+				Family: convert.RefOf(armkeyvault.SKUFamilyA),
+				Name:   convert.RefOf(armkeyvault.SKUNameStandard),
+			},
+			EnableRbacAuthorization: convert.RefOf(true),
+		},
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("starting creating key vault: %w", err)
+	}
+
+	response, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating key vault: %w", err)
+	}
+
+	return &AzCliKeyVault{
+		Id:       *response.ID,
+		Name:     *response.Name,
+		Location: *response.Location,
+	}, nil
+}
+
 func (cli *azCli) PurgeKeyVault(ctx context.Context, subscriptionId string, vaultName string, location string) error {
 	client, err := cli.createKeyVaultClient(ctx, subscriptionId)
 	if err != nil {
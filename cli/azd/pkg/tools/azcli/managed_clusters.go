@@ -19,6 +19,13 @@ type ManagedClustersService interface {
 		resourceGroupName string,
 		resourceName string,
 	) (*armcontainerservice.CredentialResults, error)
+	// Gets the managed cluster of the resource group
+	Get(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		resourceName string,
+	) (*armcontainerservice.ManagedCluster, error)
 }
 
 type managedClustersService struct {
@@ -59,6 +66,26 @@ func (cs *managedClustersService) GetAdminCredentials(
 	return &credResult.CredentialResults, nil
 }
 
+// Gets the managed cluster of the resource group
+func (cs *managedClustersService) Get(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	resourceName string,
+) (*armcontainerservice.ManagedCluster, error) {
+	client, err := cs.createManagedClusterClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Get(ctx, resourceGroupName, resourceName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.ManagedCluster, nil
+}
+
 func (cs *managedClustersService) createManagedClusterClient(
 	ctx context.Context,
 	subscriptionId string,
@@ -0,0 +1,101 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	azdinternal "github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+// ManagedIdentitiesService provides actions on top of user-assigned managed identities
+type ManagedIdentitiesService interface {
+	// Gets the user-assigned managed identity of the resource group
+	Get(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		identityName string,
+	) (*azsdk.ManagedIdentity, error)
+	// Creates or updates a federated identity credential of the user-assigned managed identity
+	CreateOrUpdateFederatedCredential(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		identityName string,
+		credentialName string,
+		credential azsdk.FederatedCredential,
+	) error
+}
+
+type managedIdentitiesService struct {
+	credentialProvider account.SubscriptionCredentialProvider
+	httpClient         httputil.HttpClient
+	userAgent          string
+}
+
+// Creates a new instance of the ManagedIdentitiesService
+func NewManagedIdentitiesService(
+	credentialProvider account.SubscriptionCredentialProvider,
+	httpClient httputil.HttpClient,
+) ManagedIdentitiesService {
+	return &managedIdentitiesService{
+		credentialProvider: credentialProvider,
+		httpClient:         httpClient,
+		userAgent:          azdinternal.MakeUserAgentString(""),
+	}
+}
+
+// Gets the user-assigned managed identity of the resource group
+func (s *managedIdentitiesService) Get(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+) (*azsdk.ManagedIdentity, error) {
+	client, err := s.createManagedIdentityClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Get(ctx, subscriptionId, resourceGroupName, identityName)
+}
+
+// Creates or updates a federated identity credential of the user-assigned managed identity
+func (s *managedIdentitiesService) CreateOrUpdateFederatedCredential(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	identityName string,
+	credentialName string,
+	credential azsdk.FederatedCredential,
+) error {
+	client, err := s.createManagedIdentityClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	return client.CreateOrUpdateFederatedCredential(
+		ctx, subscriptionId, resourceGroupName, identityName, credentialName, credential)
+}
+
+func (s *managedIdentitiesService) createManagedIdentityClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*azsdk.ManagedIdentityClient, error) {
+	credential, err := s.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := clientOptionsBuilder(s.httpClient, s.userAgent).BuildArmClientOptions()
+
+	client, err := azsdk.NewManagedIdentityClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating managed identity client, %w", err)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,31 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+// PullBicepModule downloads a Bicep module from an Azure Container Registry, authenticating with the credential of the
+// subscription, which is the tenant of the subscription for registries of other tenants.
+func (cli *azCli) PullBicepModule(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	repository string,
+	reference string,
+) (*azsdk.BicepModule, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildCoreClientOptions()
+	client, err := azsdk.NewModuleRegistryClient(loginServer, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating module registry client: %w", err)
+	}
+
+	return client.Pull(ctx, repository, reference)
+}
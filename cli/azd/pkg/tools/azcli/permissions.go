@@ -0,0 +1,91 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+// The actions checked before an operation, which fails deep in its requests without them
+const (
+	// Creates the ARM deployments of the infrastructure
+	DeploymentsWriteAction = "Microsoft.Resources/deployments/write"
+	// Creates the role assignments declared by the infrastructure
+	RoleAssignmentsWriteAction = "Microsoft.Authorization/roleAssignments/write"
+)
+
+// MissingPermissionsError is returned when the signed-in principal isn't allowed to perform actions at a scope.
+type MissingPermissionsError struct {
+	Scope   string
+	Actions []string
+}
+
+func (e *MissingPermissionsError) Error() string {
+	return fmt.Sprintf(
+		"the signed-in account is missing the permission %s on %s. Ask an owner of the scope for a role assignment "+
+			"granting it, then run the command again",
+		strings.Join(e.Actions, ", "),
+		e.Scope,
+	)
+}
+
+// MissingPermissions returns the actions the signed-in principal isn't allowed to perform at the scope, like
+// /subscriptions/<id> or the id of a resource.
+func (cli *azCli) MissingPermissions(
+	ctx context.Context,
+	subscriptionId string,
+	scope string,
+	actions []string,
+) ([]string, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewPermissionsClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Permissions client: %w", err)
+	}
+
+	permissions, err := client.List(ctx, scope)
+	if err != nil {
+		return nil, fmt.Errorf("reading permissions at scope %s: %w", scope, err)
+	}
+
+	missing := []string{}
+	for _, action := range actions {
+		if !permissionsAllow(permissions, action) {
+			missing = append(missing, action)
+		}
+	}
+
+	return missing, nil
+}
+
+// permissionsAllow reports whether a permission allows the action without excluding it.
+func permissionsAllow(permissions []azsdk.Permission, action string) bool {
+	for _, permission := range permissions {
+		if matchesAnyAction(permission.Actions, action) && !matchesAnyAction(permission.NotActions, action) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesAnyAction reports whether the action matches one of the patterns, which may contain * wildcards. Actions are
+// case insensitive.
+func matchesAnyAction(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		expression := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if matched, err := regexp.MatchString(expression, action); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+func (cli *azCli) ListLocationUsages(
+	ctx context.Context,
+	subscriptionId string,
+	providerNamespace string,
+	location string,
+) ([]*azsdk.QuotaUsage, error) {
+	client, err := cli.createQuotaClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Usages(ctx, subscriptionId, providerNamespace, location)
+}
+
+func (cli *azCli) ListComputeSkus(
+	ctx context.Context,
+	subscriptionId string,
+	location string,
+) ([]*azsdk.ComputeSku, error) {
+	client, err := cli.createQuotaClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ComputeSkus(ctx, subscriptionId, location)
+}
+
+func (cli *azCli) ListAppServiceRegions(ctx context.Context, subscriptionId string, tier string) ([]string, error) {
+	client, err := cli.createQuotaClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.AppServiceRegions(ctx, subscriptionId, tier)
+}
+
+func (cli *azCli) createQuotaClient(ctx context.Context, subscriptionId string) (*azsdk.QuotaClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewQuotaClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Quota client: %w", err)
+	}
+
+	return client, nil
+}
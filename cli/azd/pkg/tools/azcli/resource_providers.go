@@ -0,0 +1,61 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// The registration state of a resource provider allowing to deploy its resources in the subscription
+const ResourceProviderRegistered = "Registered"
+
+// GetResourceProviderState returns the registration state of the resource provider in the subscription, like
+// Registered, NotRegistered or Registering.
+func (cli *azCli) GetResourceProviderState(ctx context.Context, subscriptionId string, namespace string) (string, error) {
+	client, err := cli.createProvidersClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	provider, err := client.Get(ctx, namespace, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting resource provider %s: %w", namespace, err)
+	}
+
+	return convert.ToValueWithDefault(provider.RegistrationState, ""), nil
+}
+
+// RegisterResourceProvider starts the registration of the resource provider in the subscription, which completes once
+// its state is Registered.
+func (cli *azCli) RegisterResourceProvider(ctx context.Context, subscriptionId string, namespace string) error {
+	client, err := cli.createProvidersClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Register(ctx, namespace, nil); err != nil {
+		return fmt.Errorf("registering resource provider %s: %w", namespace, err)
+	}
+
+	return nil
+}
+
+func (cli *azCli) createProvidersClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*armresources.ProvidersClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := armresources.NewProvidersClient(subscriptionId, credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Providers client: %w", err)
+	}
+
+	return client, nil
+}
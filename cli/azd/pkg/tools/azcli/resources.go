@@ -2,9 +2,13 @@ package azcli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 )
 
 func (cli *azCli) GetResource(
@@ -25,11 +29,35 @@ func (cli *azCli) GetResource(
 			Name:     *res.Name,
 			Type:     *res.Type,
 			Location: *res.Location,
+			Tags:     convertTags(res.Tags),
 		},
-		Kind: *res.Kind,
+		Kind:        convert.ToValueWithDefault(res.Kind, ""),
+		PrincipalId: identityPrincipalId(res.Identity),
 	}, nil
 }
 
+// identityPrincipalId returns the principal id of the system assigned identity of a resource, or of its user assigned
+// identity when it has a single one.
+func identityPrincipalId(identity *armresources.Identity) string {
+	if identity == nil {
+		return ""
+	}
+
+	if identity.PrincipalID != nil {
+		return *identity.PrincipalID
+	}
+
+	if len(identity.UserAssignedIdentities) == 1 {
+		for _, userAssigned := range identity.UserAssignedIdentities {
+			if userAssigned != nil && userAssigned.PrincipalID != nil {
+				return *userAssigned.PrincipalID
+			}
+		}
+	}
+
+	return ""
+}
+
 func (cli *azCli) ListResourceGroupResources(
 	ctx context.Context,
 	subscriptionId string,
@@ -62,6 +90,7 @@ func (cli *azCli) ListResourceGroupResources(
 				Name:     *resource.Name,
 				Type:     *resource.Type,
 				Location: *resource.Location,
+				Tags:     convertTags(resource.Tags),
 			})
 		}
 	}
@@ -69,6 +98,31 @@ func (cli *azCli) ListResourceGroupResources(
 	return resources, nil
 }
 
+func (cli *azCli) QueryResources(ctx context.Context, subscriptionId string, query string) ([]AzCliResource, error) {
+	client, err := cli.createResourceGraphClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := client.Resources(ctx, subscriptionId, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying resource graph: %w", err)
+	}
+
+	resources := make([]AzCliResource, 0, len(rows))
+	for _, row := range rows {
+		resources = append(resources, AzCliResource{
+			Id:       row.Id,
+			Name:     row.Name,
+			Type:     row.Type,
+			Location: row.Location,
+			Tags:     row.Tags,
+		})
+	}
+
+	return resources, nil
+}
+
 func (cli *azCli) ListResourceGroup(
 	ctx context.Context,
 	subscriptionId string,
@@ -110,6 +164,7 @@ func (cli *azCli) ListResourceGroup(
 				Name:     *group.Name,
 				Type:     *group.Type,
 				Location: *group.Location,
+				Tags:     convertTags(group.Tags),
 			})
 		}
 	}
@@ -136,6 +191,131 @@ func (cli *azCli) DeleteResourceGroup(ctx context.Context, subscriptionId string
 	return nil
 }
 
+// DeleteResource deletes a resource, with the latest stable API version of its resource type.
+func (cli *azCli) DeleteResource(ctx context.Context, subscriptionId string, resourceId string) error {
+	apiVersion, err := cli.latestApiVersion(ctx, subscriptionId, resourceId)
+	if err != nil {
+		return err
+	}
+
+	client, err := cli.createResourcesClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginDeleteByID(ctx, resourceId, apiVersion, nil)
+	if err != nil {
+		return fmt.Errorf("beginning resource deletion: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("deleting resource: %w", err)
+	}
+
+	return nil
+}
+
+// latestApiVersion returns the latest API version of the resource type of a resource, preferring stable versions.
+func (cli *azCli) latestApiVersion(ctx context.Context, subscriptionId string, resourceId string) (string, error) {
+	namespace, resourceType, err := resourceTypeOfId(resourceId)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := cli.createProvidersClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	provider, err := client.Get(ctx, namespace, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting resource provider %s: %w", namespace, err)
+	}
+
+	for _, providerType := range provider.ResourceTypes {
+		if providerType.ResourceType == nil || !strings.EqualFold(*providerType.ResourceType, resourceType) {
+			continue
+		}
+
+		preview := ""
+		// API versions are listed from the latest
+		for _, version := range providerType.APIVersions {
+			if version == nil {
+				continue
+			}
+
+			if !strings.Contains(*version, "preview") {
+				return *version, nil
+			}
+
+			if preview == "" {
+				preview = *version
+			}
+		}
+
+		if preview != "" {
+			return preview, nil
+		}
+	}
+
+	return "", fmt.Errorf("no API version found for resource type %s/%s", namespace, resourceType)
+}
+
+// resourceTypeOfId returns the provider namespace and the resource type of a resource id, like Microsoft.Web and
+// sites/slots for /subscriptions/<id>/resourceGroups/<group>/providers/Microsoft.Web/sites/<site>/slots/<slot>.
+func resourceTypeOfId(resourceId string) (string, string, error) {
+	index := strings.LastIndex(strings.ToLower(resourceId), "/providers/")
+	if index < 0 {
+		return "", "", fmt.Errorf("'%s' isn't the id of a resource of a provider", resourceId)
+	}
+
+	segments := strings.Split(strings.Trim(resourceId[index+len("/providers/"):], "/"), "/")
+	if len(segments) < 3 || len(segments)%2 == 0 {
+		return "", "", errors.New("invalid resource id: " + resourceId)
+	}
+
+	types := []string{}
+	for i := 1; i < len(segments); i += 2 {
+		types = append(types, segments[i])
+	}
+
+	return segments[0], strings.Join(types, "/"), nil
+}
+
+// UpdateResourceTags merges the tags into the existing tags of the resource.
+func (cli *azCli) UpdateResourceTags(
+	ctx context.Context,
+	subscriptionId string,
+	resourceId string,
+	tags map[string]string,
+) error {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := armresources.NewTagsClient(subscriptionId, credential, options)
+	if err != nil {
+		return fmt.Errorf("creating Tags client: %w", err)
+	}
+
+	properties := &armresources.Tags{Tags: map[string]*string{}}
+	for key, value := range tags {
+		properties.Tags[key] = convert.RefOf(value)
+	}
+
+	_, err = client.UpdateAtScope(ctx, resourceId, armresources.TagsPatchResource{
+		Operation:  convert.RefOf(armresources.TagsPatchOperationMerge),
+		Properties: properties,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("updating tags of resource: %w", err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) createResourcesClient(ctx context.Context, subscriptionId string) (*armresources.Client, error) {
 	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
@@ -151,6 +331,24 @@ func (cli *azCli) createResourcesClient(ctx context.Context, subscriptionId stri
 	return client, nil
 }
 
+func (cli *azCli) createResourceGraphClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*azsdk.ResourceGraphClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewResourceGraphClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Resource Graph client: %w", err)
+	}
+
+	return client, nil
+}
+
 func (cli *azCli) createResourceGroupClient(
 	ctx context.Context,
 	subscriptionId string,
@@ -168,3 +366,16 @@ func (cli *azCli) createResourceGroupClient(
 
 	return client, nil
 }
+
+func convertTags(tags map[string]*string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	converted := make(map[string]string, len(tags))
+	for name, value := range tags {
+		converted[name] = convert.ToValueWithDefault(value, "")
+	}
+
+	return converted
+}
@@ -0,0 +1,52 @@
+package azcli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
+)
+
+// CreateServiceConnection creates or updates a Service Connector connection of a compute resource, and returns the
+// configuration values Service Connector sets in the app settings of the compute resource for the connection.
+func (cli *azCli) CreateServiceConnection(
+	ctx context.Context,
+	subscriptionId string,
+	sourceResourceId string,
+	name string,
+	connection azsdk.ServiceConnection,
+) ([]azsdk.ServiceConnectionConfiguration, error) {
+	client, err := cli.createServiceConnectorClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.CreateOrUpdate(ctx, sourceResourceId, name, connection); err != nil {
+		return nil, fmt.Errorf("creating connection %s: %w", name, err)
+	}
+
+	configurations, err := client.ListConfigurations(ctx, sourceResourceId, name)
+	if err != nil {
+		return nil, fmt.Errorf("listing the configuration of connection %s: %w", name, err)
+	}
+
+	return configurations, nil
+}
+
+func (cli *azCli) createServiceConnectorClient(
+	ctx context.Context,
+	subscriptionId string,
+) (*azsdk.ServiceConnectorClient, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := azsdk.NewServiceConnectorClient(credential, options)
+	if err != nil {
+		return nil, fmt.Errorf("creating Service Connector client: %w", err)
+	}
+
+	return client, nil
+}
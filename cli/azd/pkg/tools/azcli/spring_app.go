@@ -34,7 +34,7 @@ type SpringService interface {
 		relativePath string,
 		deploymentName string,
 	) (*string, error)
-	// Upload jar artifact to ASA app Storage File
+	// Upload jar artifact to ASA app Storage File, reporting the uploaded bytes to progress when it isn't nil
 	UploadSpringArtifact(
 		ctx context.Context,
 		subscriptionId string,
@@ -42,6 +42,7 @@ type SpringService interface {
 		instanceName string,
 		appName string,
 		artifactPath string,
+		progress func(uploaded int64),
 	) (*string, error)
 	// Get Spring app deployment
 	GetSpringAppDeployment(
@@ -54,6 +55,9 @@ type SpringService interface {
 	) (*string, error)
 }
 
+// The number of attempts to upload each range of a Spring artifact
+const springUploadMaxTries = 5
+
 type springService struct {
 	credentialProvider account.SubscriptionCredentialProvider
 	httpClient         httputil.HttpClient
@@ -106,6 +110,7 @@ func (ss *springService) GetSpringAppProperties(
 func (ss *springService) UploadSpringArtifact(
 	ctx context.Context,
 	subscriptionId, resourceGroup, instanceName, appName, artifactPath string,
+	progress func(uploaded int64),
 ) (*string, error) {
 	file, err := os.Open(artifactPath)
 
@@ -131,14 +136,24 @@ func (ss *springService) UploadSpringArtifact(
 		return nil, fmt.Errorf("failed to parse storage upload url %s : %w", *storageInfo.UploadURL, err)
 	}
 
-	// Pass NewAnonymousCredential here, since the URL returned by Azure Spring Apps already contains a SAS token
-	fileURL := azfile.NewFileURL(*url, azfile.NewPipeline(azfile.NewAnonymousCredential(), azfile.PipelineOptions{}))
-	err = azfile.UploadFileToAzureFile(ctx, file, fileURL,
-		azfile.UploadToAzureFileOptions{
-			Metadata: azfile.Metadata{
-				"createdby": "AZD",
-			},
-		})
+	// Pass NewAnonymousCredential here, since the URL returned by Azure Spring Apps already contains a SAS token.
+	// The artifact is uploaded in ranges, each retried on its own, so an interrupted upload only sends the failed
+	// ranges again.
+	fileURL := azfile.NewFileURL(*url, azfile.NewPipeline(azfile.NewAnonymousCredential(), azfile.PipelineOptions{
+		Retry: azfile.RetryOptions{
+			MaxTries: springUploadMaxTries,
+		},
+	}))
+	options := azfile.UploadToAzureFileOptions{
+		Metadata: azfile.Metadata{
+			"createdby": "AZD",
+		},
+	}
+	if progress != nil {
+		options.Progress = progress
+	}
+
+	err = azfile.UploadFileToAzureFile(ctx, file, fileURL, options)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload artifact %s : %w", artifactPath, err)
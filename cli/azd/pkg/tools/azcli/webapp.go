@@ -8,6 +8,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/pkg/profiling"
 )
 
 type AzCliAppServiceProperties struct {
@@ -35,6 +36,137 @@ func (cli *azCli) GetAppServiceProperties(
 	}, nil
 }
 
+// AzCliAppServiceSiteConfig is the configuration of the site of an App Service. Nil values are left unchanged.
+type AzCliAppServiceSiteConfig struct {
+	// The runtime stack of a Linux App Service, like PYTHON|3.11.
+	LinuxFxVersion *string
+	// The command starting the application.
+	AppCommandLine *string
+	// The path probed by the health check.
+	HealthCheckPath *string
+	// Keeps the application loaded when idle.
+	AlwaysOn *bool
+	// The CORS policy of the App Service.
+	Cors *AzCliCorsSettings
+}
+
+// AzCliCorsSettings is the CORS policy of an App Service.
+type AzCliCorsSettings struct {
+	// The origins allowed to make cross-origin calls, like https://www.contoso.com, or * for all origins.
+	AllowedOrigins []string
+	// Allows the requests with credentials.
+	SupportCredentials bool
+}
+
+// UpdateAppServiceSiteConfig updates the settings of the site configuration of the App Service differing from config,
+// leaving the others unchanged. It returns false without updating the App Service when no setting differs.
+func (cli *azCli) UpdateAppServiceSiteConfig(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	config AzCliAppServiceSiteConfig,
+) (bool, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := client.GetConfiguration(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed retrieving webapp configuration: %w", err)
+	}
+
+	patch := siteConfigPatch(current.Properties, config)
+	if patch == nil {
+		return false, nil
+	}
+
+	_, err = client.UpdateConfiguration(ctx, resourceGroup, appName, armappservice.SiteConfigResource{
+		Properties: patch,
+	}, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed updating webapp configuration: %w", err)
+	}
+
+	return true, nil
+}
+
+// siteConfigPatch returns the site configuration with the settings of config differing from the current configuration,
+// or nil when none differs.
+func siteConfigPatch(current *armappservice.SiteConfig, config AzCliAppServiceSiteConfig) *armappservice.SiteConfig {
+	if current == nil {
+		current = &armappservice.SiteConfig{}
+	}
+
+	patch := &armappservice.SiteConfig{}
+	changed := false
+	patchString := func(value *string, currentValue *string, target **string) {
+		if value != nil && convert.ToValueWithDefault(currentValue, "") != *value {
+			*target = value
+			changed = true
+		}
+	}
+
+	patchString(config.LinuxFxVersion, current.LinuxFxVersion, &patch.LinuxFxVersion)
+	patchString(config.AppCommandLine, current.AppCommandLine, &patch.AppCommandLine)
+	patchString(config.HealthCheckPath, current.HealthCheckPath, &patch.HealthCheckPath)
+
+	if config.AlwaysOn != nil && convert.ToValueWithDefault(current.AlwaysOn, false) != *config.AlwaysOn {
+		patch.AlwaysOn = config.AlwaysOn
+		changed = true
+	}
+
+	if config.Cors != nil && !corsEqual(current.Cors, *config.Cors) {
+		allowedOrigins := []*string{}
+		for _, origin := range config.Cors.AllowedOrigins {
+			allowedOrigins = append(allowedOrigins, convert.RefOf(origin))
+		}
+
+		patch.Cors = &armappservice.CorsSettings{
+			AllowedOrigins:     allowedOrigins,
+			SupportCredentials: convert.RefOf(config.Cors.SupportCredentials),
+		}
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return patch
+}
+
+// corsEqual returns true when the current CORS policy allows the same origins, in any order, and credentials.
+func corsEqual(current *armappservice.CorsSettings, cors AzCliCorsSettings) bool {
+	if current == nil {
+		current = &armappservice.CorsSettings{}
+	}
+
+	if convert.ToValueWithDefault(current.SupportCredentials, false) != cors.SupportCredentials {
+		return false
+	}
+
+	origins := map[string]bool{}
+	for _, origin := range current.AllowedOrigins {
+		if origin != nil {
+			origins[*origin] = true
+		}
+	}
+
+	if len(origins) != len(cors.AllowedOrigins) {
+		return false
+	}
+
+	for _, origin := range cors.AllowedOrigins {
+		if !origins[origin] {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (cli *azCli) DeployAppServiceZip(
 	ctx context.Context,
 	subscriptionId string,
@@ -47,7 +179,9 @@ func (cli *azCli) DeployAppServiceZip(
 		return nil, err
 	}
 
+	endStage := profiling.TrackStage(fmt.Sprintf("zip upload %s", appName))
 	response, err := client.Deploy(ctx, appName, deployZipFile)
+	endStage()
 	if err != nil {
 		return nil, err
 	}
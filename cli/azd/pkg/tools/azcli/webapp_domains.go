@@ -0,0 +1,224 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// How often, and how long, the creation of a managed certificate is polled
+var (
+	managedCertificatePollInterval = 10 * time.Second
+	managedCertificateTimeout      = 10 * time.Minute
+)
+
+// AzCliDnsRecord is a DNS record of a custom domain.
+type AzCliDnsRecord struct {
+	Type  string
+	Name  string
+	Value string
+}
+
+// DomainVerificationError is returned when a custom domain can't be bound to an App Service because the DNS records
+// routing the domain to the App Service, and proving its ownership, don't exist yet.
+type DomainVerificationError struct {
+	HostName string
+	Records  []AzCliDnsRecord
+	Err      error
+}
+
+func (e *DomainVerificationError) Error() string {
+	records := []string{}
+	for _, record := range e.Records {
+		records = append(records, fmt.Sprintf("%s %s -> %s", record.Type, record.Name, record.Value))
+	}
+
+	return fmt.Sprintf(
+		"the custom domain %s couldn't be verified, create the DNS records below then deploy again:\n  %s",
+		e.HostName,
+		strings.Join(records, "\n  "),
+	)
+}
+
+func (e *DomainVerificationError) Unwrap() error {
+	return e.Err
+}
+
+// BindAppServiceCustomDomain binds the custom domain to the App Service, and secures it with a free certificate
+// managed by App Service when managedCertificate is true. Domains already bound, or already secured, are left
+// unchanged. A *DomainVerificationError lists the DNS records to create when the domain can't be verified.
+func (cli *azCli) BindAppServiceCustomDomain(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	hostName string,
+	managedCertificate bool,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	site, err := client.Get(ctx, resourceGroup, appName, nil)
+	if err != nil {
+		return fmt.Errorf("failed retrieving webapp properties: %w", err)
+	}
+
+	var binding armappservice.HostNameBinding
+	existing, err := client.GetHostNameBinding(ctx, resourceGroup, appName, hostName, nil)
+	if err == nil {
+		binding = existing.HostNameBinding
+	} else {
+		var responseErr *azcore.ResponseError
+		if !errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("failed retrieving binding of custom domain %s: %w", hostName, err)
+		}
+
+		created, err := client.CreateOrUpdateHostNameBinding(ctx, resourceGroup, appName, hostName,
+			armappservice.HostNameBinding{
+				Properties: &armappservice.HostNameBindingProperties{
+					SiteName:                    convert.RefOf(appName),
+					HostNameType:                convert.RefOf(armappservice.HostNameTypeVerified),
+					CustomHostNameDNSRecordType: convert.RefOf(armappservice.CustomHostNameDNSRecordTypeCName),
+				},
+			}, nil)
+		if err != nil {
+			if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusBadRequest {
+				return &DomainVerificationError{
+					HostName: hostName,
+					Records:  domainRecords(site.Site, hostName),
+					Err:      err,
+				}
+			}
+
+			return fmt.Errorf("failed binding custom domain %s: %w", hostName, err)
+		}
+
+		binding = created.HostNameBinding
+	}
+
+	if !managedCertificate || (binding.Properties != nil && binding.Properties.SSLState != nil &&
+		*binding.Properties.SSLState != armappservice.SSLStateDisabled) {
+		return nil
+	}
+
+	thumbprint, err := cli.createManagedCertificate(ctx, subscriptionId, resourceGroup, site.Site, hostName)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.CreateOrUpdateHostNameBinding(ctx, resourceGroup, appName, hostName,
+		armappservice.HostNameBinding{
+			Properties: &armappservice.HostNameBindingProperties{
+				SiteName:                    convert.RefOf(appName),
+				HostNameType:                convert.RefOf(armappservice.HostNameTypeVerified),
+				CustomHostNameDNSRecordType: convert.RefOf(armappservice.CustomHostNameDNSRecordTypeCName),
+				SSLState:                    convert.RefOf(armappservice.SSLStateSniEnabled),
+				Thumbprint:                  convert.RefOf(thumbprint),
+			},
+		}, nil)
+	if err != nil {
+		return fmt.Errorf("failed securing custom domain %s: %w", hostName, err)
+	}
+
+	return nil
+}
+
+// domainRecords returns the DNS records routing the custom domain to the site and proving its ownership.
+func domainRecords(site armappservice.Site, hostName string) []AzCliDnsRecord {
+	records := []AzCliDnsRecord{}
+	if site.Properties == nil {
+		return records
+	}
+
+	if site.Properties.DefaultHostName != nil {
+		records = append(records, AzCliDnsRecord{Type: "CNAME", Name: hostName, Value: *site.Properties.DefaultHostName})
+	}
+
+	if site.Properties.CustomDomainVerificationID != nil {
+		records = append(records, AzCliDnsRecord{
+			Type:  "TXT",
+			Name:  "asuid." + hostName,
+			Value: *site.Properties.CustomDomainVerificationID,
+		})
+	}
+
+	return records
+}
+
+// createManagedCertificate creates the free certificate managed by App Service for the custom domain of the site, and
+// returns its thumbprint once it's issued.
+func (cli *azCli) createManagedCertificate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	site armappservice.Site,
+	hostName string,
+) (string, error) {
+	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	options := cli.createDefaultClientOptionsBuilder(ctx).BuildArmClientOptions()
+	client, err := armappservice.NewCertificatesClient(subscriptionId, credential, options)
+	if err != nil {
+		return "", fmt.Errorf("creating Certificates client: %w", err)
+	}
+
+	var serverFarmId *string
+	if site.Properties != nil {
+		serverFarmId = site.Properties.ServerFarmID
+	}
+
+	certificate, err := client.CreateOrUpdate(ctx, resourceGroup, hostName, armappservice.Certificate{
+		Location: site.Location,
+		Properties: &armappservice.CertificateProperties{
+			CanonicalName: convert.RefOf(hostName),
+			ServerFarmID:  serverFarmId,
+		},
+	}, nil)
+	if err == nil && certificate.Properties != nil && certificate.Properties.Thumbprint != nil {
+		return *certificate.Properties.Thumbprint, nil
+	}
+
+	// The certificate is issued asynchronously, the request is accepted before the certificate exists
+	var responseErr *azcore.ResponseError
+	if err != nil && (!errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusAccepted) {
+		return "", fmt.Errorf("failed creating managed certificate for %s: %w", hostName, err)
+	}
+
+	deadline := time.Now().Add(managedCertificateTimeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("waiting for managed certificate for %s: %w", hostName, ctx.Err())
+		case <-time.After(managedCertificatePollInterval):
+		}
+
+		certificate, err := client.Get(ctx, resourceGroup, hostName, nil)
+		if err != nil && (!errors.As(err, &responseErr) || responseErr.StatusCode != http.StatusNotFound) {
+			return "", fmt.Errorf("failed retrieving managed certificate for %s: %w", hostName, err)
+		}
+
+		if err == nil && certificate.Properties != nil && certificate.Properties.Thumbprint != nil {
+			return *certificate.Properties.Thumbprint, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf(
+				"the managed certificate for %s isn't issued after %s, deploy again once it's issued",
+				hostName,
+				managedCertificateTimeout,
+			)
+		}
+	}
+}
@@ -23,6 +23,8 @@ type Docker interface {
 		platform string,
 		buildContext string,
 		name string,
+		buildArgs []string,
+		buildEnv []string,
 	) (string, error)
 	Tag(ctx context.Context, cwd string, imageName string, tag string) error
 	Push(ctx context.Context, cwd string, tag string) error
@@ -52,9 +54,10 @@ func (d *docker) Login(ctx context.Context, loginServer string, username string,
 }
 
 // Runs a Docker build for a given Dockerfile. If the platform is not specified (empty),
-// it defaults to amd64. If the build
-// is successful, the function
-// returns the image id of the built image.
+// it defaults to amd64. The build args are passed as --build-arg KEY=value. The build env
+// values, formatted as KEY=value, are passed as --build-arg KEY, docker reads their value
+// from its environment so they aren't written to the logs. If the build is successful,
+// the function returns the image id of the built image.
 func (d *docker) Build(
 	ctx context.Context,
 	cwd string,
@@ -62,6 +65,8 @@ func (d *docker) Build(
 	platform string,
 	buildContext string,
 	tagName string,
+	buildArgs []string,
+	buildEnv []string,
 ) (string, error) {
 	if strings.TrimSpace(platform) == "" {
 		platform = "amd64"
@@ -77,9 +82,21 @@ func (d *docker) Build(
 		args = append(args, "-t", tagName)
 	}
 
+	for _, buildArg := range buildArgs {
+		args = append(args, "--build-arg", buildArg)
+	}
+
+	for _, kv := range buildEnv {
+		name, _, _ := strings.Cut(kv, "=")
+		args = append(args, "--build-arg", name)
+	}
+
 	args = append(args, buildContext)
 
-	res, err := d.executeCommand(ctx, cwd, args...)
+	res, err := d.commandRunner.Run(ctx, exec.NewRunArgs("docker", args...).
+		WithCwd(cwd).
+		WithEnv(buildEnv).
+		WithEnrichError(true))
 	if err != nil {
 		return "", fmt.Errorf("building image: %s: %w", res.String(), err)
 	}
@@ -48,7 +48,7 @@ func Test_DockerBuild(t *testing.T) {
 			}, nil
 		})
 
-		result, err := docker.Build(context.Background(), cwd, dockerFile, platform, dockerContext, imageName)
+		result, err := docker.Build(context.Background(), cwd, dockerFile, platform, dockerContext, imageName, nil, nil)
 
 		require.Equal(t, true, ran)
 		require.Nil(t, err)
@@ -86,7 +86,7 @@ func Test_DockerBuild(t *testing.T) {
 			}, errors.New(customErrorMessage)
 		})
 
-		result, err := docker.Build(context.Background(), cwd, dockerFile, platform, dockerContext, imageName)
+		result, err := docker.Build(context.Background(), cwd, dockerFile, platform, dockerContext, imageName, nil, nil)
 
 		require.Equal(t, true, ran)
 		require.NotNil(t, err)
@@ -133,13 +133,46 @@ func Test_DockerBuildEmptyPlatform(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName)
+	result, err := docker.Build(context.Background(), cwd, dockerFile, "", dockerContext, imageName, nil, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
 	require.Equal(t, "Docker build output", result)
 }
 
+func Test_DockerBuildArgs(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	docker := NewDocker(mockContext.CommandRunner)
+
+	var buildArgs []string
+	var buildEnv []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker build")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		buildArgs = args.Args
+		buildEnv = args.Env
+		return exec.RunResult{Stdout: "imageId"}, nil
+	})
+
+	_, err := docker.Build(
+		context.Background(), ".", "./Dockerfile", "", ".", "IMAGE_NAME",
+		[]string{"VERSION=1.0", "REGION=eastus2"}, []string{"API_KEY=secret"})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"build",
+		"-q",
+		"-f", "./Dockerfile",
+		"--platform", "amd64",
+		"-t", "IMAGE_NAME",
+		"--build-arg", "VERSION=1.0",
+		"--build-arg", "REGION=eastus2",
+		"--build-arg", "API_KEY",
+		".",
+	}, buildArgs)
+	// The values of the build env are only passed through the environment of docker
+	require.Equal(t, []string{"API_KEY=secret"}, buildEnv)
+}
+
 func Test_DockerTag(t *testing.T) {
 	cwd := "."
 	imageName := "image-name"
@@ -19,7 +19,7 @@ type DotNetCli interface {
 	tools.ExternalTool
 	Restore(ctx context.Context, project string) error
 	Build(ctx context.Context, project string, configuration string, output string) error
-	Publish(ctx context.Context, project string, configuration string, output string) error
+	Publish(ctx context.Context, project string, configuration string, output string, options PublishOptions) error
 	InitializeSecret(ctx context.Context, project string) error
 	SetSecrets(ctx context.Context, secrets map[string]string, project string) error
 }
@@ -93,7 +93,27 @@ func (cli *dotNetCli) Build(ctx context.Context, project string, configuration s
 	return nil
 }
 
-func (cli *dotNetCli) Publish(ctx context.Context, project string, configuration string, output string) error {
+// PublishOptions are the options of dotnet publish, in addition to the build configuration and the output directory.
+type PublishOptions struct {
+	// The runtime identifier of the published application, like linux-x64
+	RuntimeIdentifier string
+	// Publishes the .NET runtime with the application. Left to the project when nil.
+	SelfContained *bool
+	// Compiles the assemblies ahead of time, in the ReadyToRun format
+	ReadyToRun bool
+	// Trims the assemblies not used by the application
+	Trimmed bool
+	// The publish profile of the project, the name of a .pubxml file of Properties/PublishProfiles
+	PublishProfile string
+}
+
+func (cli *dotNetCli) Publish(
+	ctx context.Context,
+	project string,
+	configuration string,
+	output string,
+	options PublishOptions,
+) error {
 	runArgs := exec.NewRunArgs("dotnet", "publish", project)
 	if configuration != "" {
 		runArgs = runArgs.AppendParams("-c", configuration)
@@ -103,6 +123,26 @@ func (cli *dotNetCli) Publish(ctx context.Context, project string, configuration
 		runArgs = runArgs.AppendParams("--output", output)
 	}
 
+	if options.RuntimeIdentifier != "" {
+		runArgs = runArgs.AppendParams("-r", options.RuntimeIdentifier)
+	}
+
+	if options.SelfContained != nil {
+		runArgs = runArgs.AppendParams(fmt.Sprintf("--self-contained=%t", *options.SelfContained))
+	}
+
+	if options.ReadyToRun {
+		runArgs = runArgs.AppendParams("-p:PublishReadyToRun=true")
+	}
+
+	if options.Trimmed {
+		runArgs = runArgs.AppendParams("-p:PublishTrimmed=true")
+	}
+
+	if options.PublishProfile != "" {
+		runArgs = runArgs.AppendParams(fmt.Sprintf("-p:PublishProfile=%s", options.PublishProfile))
+	}
+
 	res, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("dotnet publish on project '%s' failed: %s: %w", project, res.String(), err)
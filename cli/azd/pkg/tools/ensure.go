@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"log"
 	osexec "os/exec"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exitcode"
 )
 
 // missingToolErrors wraps a set of errors discovered when
@@ -31,24 +34,33 @@ func (m *missingToolErrors) Error() string {
 }
 
 // EnsureInstalled checks that all tools are installed, returning an
-// error if one or more tools are not.
+// error if one or more tools are not. The tools are checked concurrently,
+// each one once, and with a context from WithInstalledCheckCache the result
+// of each check is reused by the next calls.
 func EnsureInstalled(ctx context.Context, tools ...ExternalTool) error {
-	var allErrors []error
-	errorsEncountered := map[string]struct{}{}
+	tools = Unique(tools)
 
-	confirmedTools := make(map[string]struct{})
-	if fromCtx, ok := ctx.Value(installedCheckCacheKey).(map[string]struct{}); ok && fromCtx != nil {
-		confirmedTools = fromCtx
+	cache, ok := ctx.Value(installedCheckCacheKey).(*installedCheckCache)
+	if !ok || cache == nil {
+		cache = newInstalledCheckCache()
 	}
 
-	for _, tool := range tools {
-		_, ok := confirmedTools[tool.Name()]
-		if ok {
-			log.Printf("Skipping install check for '%s'. It was previously confirmed.", tool.Name())
-			continue
-		}
+	results := make([]error, len(tools))
+	var wg sync.WaitGroup
+	for i, tool := range tools {
+		wg.Add(1)
+		go func(i int, tool ExternalTool) {
+			defer wg.Done()
+			results[i] = cache.check(ctx, tool)
+		}(i, tool)
+	}
+	wg.Wait()
 
-		err := tool.CheckInstalled(ctx)
+	var allErrors []error
+	errorsEncountered := map[string]struct{}{}
+
+	for i, tool := range tools {
+		err := results[i]
 		var errSem *ErrSemver
 		if errors.As(err, &errSem) {
 			errorMsg := err.Error()
@@ -67,13 +79,10 @@ func EnsureInstalled(ctx context.Context, tools ...ExternalTool) error {
 				errorsEncountered[errorMsg] = struct{}{}
 			}
 		}
-
-		// Mark the current tool as confirmed
-		confirmedTools[tool.Name()] = struct{}{}
 	}
 
 	if len(allErrors) > 0 {
-		return &missingToolErrors{errs: allErrors}
+		return exitcode.New(exitcode.CategoryExternalTool, &missingToolErrors{errs: allErrors})
 	}
 
 	return nil
@@ -103,6 +112,44 @@ const (
 	installedCheckCacheKey confirmCacheKey = "checkCache"
 )
 
+// installedCheckCache holds the result of the install check of each tool, by tool name. Concurrent checks of the same
+// tool wait for the first one.
+type installedCheckCache struct {
+	mu      sync.Mutex
+	results map[string]*installedCheck
+}
+
+type installedCheck struct {
+	once sync.Once
+	err  error
+}
+
+func newInstalledCheckCache() *installedCheckCache {
+	return &installedCheckCache{results: map[string]*installedCheck{}}
+}
+
+func (c *installedCheckCache) check(ctx context.Context, tool ExternalTool) error {
+	c.mu.Lock()
+	result, has := c.results[tool.Name()]
+	if !has {
+		result = &installedCheck{}
+		c.results[tool.Name()] = result
+	}
+	c.mu.Unlock()
+
+	if has {
+		log.Printf("Skipping install check for '%s'. It was previously checked.", tool.Name())
+	}
+
+	result.once.Do(func() {
+		result.err = tool.CheckInstalled(ctx)
+	})
+
+	return result.err
+}
+
+// WithInstalledCheckCache returns a context caching the results of the install checks of EnsureInstalled, so each
+// tool is checked once while a command runs.
 func WithInstalledCheckCache(ctx context.Context) context.Context {
-	return context.WithValue(ctx, installedCheckCacheKey, make(map[string]struct{}))
+	return context.WithValue(ctx, installedCheckCacheKey, newInstalledCheckCache())
 }
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	osexec "os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -17,13 +18,31 @@ func Test_EnsureInstalledOnlyOnceWhenCached(t *testing.T) {
 	require.Equal(t, tool.installChecks, 1)
 }
 
+func Test_EnsureInstalledChecksEachToolOnce(t *testing.T) {
+	tool := TestTool{}
+	missing := TestTool{name: "Missing Tool", err: osexec.ErrNotFound}
+
+	err := EnsureInstalled(context.Background(), &tool, &missing, &tool, &missing)
+	require.ErrorContains(t, err, "Missing Tool is not installed")
+	require.Equal(t, 1, tool.installChecks)
+	require.Equal(t, 1, missing.installChecks)
+
+	// The failed check is cached as well
+	ctx := WithInstalledCheckCache(context.Background())
+	require.Error(t, EnsureInstalled(ctx, &missing))
+	require.Error(t, EnsureInstalled(ctx, &tool, &missing))
+	require.Equal(t, 2, missing.installChecks)
+}
+
 type TestTool struct {
+	name          string
+	err           error
 	installChecks int
 }
 
 func (t *TestTool) CheckInstalled(ctx context.Context) error {
 	t.installChecks++
-	return nil
+	return t.err
 }
 
 func (t *TestTool) InstallUrl() string {
@@ -31,5 +50,9 @@ func (t *TestTool) InstallUrl() string {
 }
 
 func (t *TestTool) Name() string {
+	if t.name != "" {
+		return t.name
+	}
+
 	return "Test Tool"
 }
@@ -8,11 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"runtime"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/blang/semver/v4"
@@ -22,6 +25,9 @@ type GitCli interface {
 	tools.ExternalTool
 	GetRemoteUrl(ctx context.Context, string, remoteName string) (string, error)
 	ShallowClone(ctx context.Context, repositoryPath string, branch string, target string) error
+	// SparseClone clones the latest commit of the branch or tag of the repository, checking out the files of the
+	// directory only. The blobs of the other files aren't downloaded.
+	SparseClone(ctx context.Context, repositoryPath string, branch string, directory string, target string) error
 	InitRepo(ctx context.Context, repositoryPath string) error
 	AddRemote(ctx context.Context, repositoryPath string, remoteName string, remoteUrl string) error
 	UpdateRemote(ctx context.Context, repositoryPath string, remoteName string, remoteUrl string) error
@@ -32,9 +38,17 @@ type GitCli interface {
 	IsUntrackedFile(ctx context.Context, repositoryPath string, filePath string) (bool, error)
 	SetCredentialStore(ctx context.Context, repositoryPath string) error
 	ListStagedFiles(ctx context.Context, repositoryPath string) (string, error)
+	// ListUntrackedFiles lists the untracked files of the repository which aren't ignored.
+	ListUntrackedFiles(ctx context.Context, repositoryPath string) ([]string, error)
+	// DiffHead returns the changes of the tracked files, staged or not, since the last commit, without context lines.
+	// All the staged files are changes when the repository has no commit yet.
+	DiffHead(ctx context.Context, repositoryPath string) (string, error)
 	AddFileExecPermission(ctx context.Context, repositoryPath string, file string) error
 	// make current repo to use gh-cli as credential helper.
 	SetGitHubAuthForRepo(ctx context.Context, repositoryPath, credential, ghPath string) error
+	// GetCredential returns the password stored by the configured credential helpers for the host, or an empty
+	// string when there is none. Git never prompts for the credential.
+	GetCredential(ctx context.Context, host string) (string, error)
 }
 
 type gitCli struct {
@@ -90,7 +104,7 @@ func (cli *gitCli) Name() string {
 }
 
 func (cli *gitCli) ShallowClone(ctx context.Context, repositoryPath string, branch string, target string) error {
-	args := []string{"clone", "--depth", "1", repositoryPath}
+	args := append(proxyArgs(repositoryPath), "clone", "--depth", "1", repositoryPath)
 	if branch != "" {
 		args = append(args, "--branch", branch)
 	}
@@ -105,6 +119,45 @@ func (cli *gitCli) ShallowClone(ctx context.Context, repositoryPath string, bran
 	return nil
 }
 
+func (cli *gitCli) SparseClone(
+	ctx context.Context, repositoryPath string, branch string, directory string, target string) error {
+	args := append(proxyArgs(repositoryPath), "clone", "--depth", "1", "--filter=blob:none", "--sparse", repositoryPath)
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	args = append(args, target)
+
+	res, err := cli.commandRunner.Run(ctx, newRunArgs(args...))
+	if err != nil {
+		return fmt.Errorf("failed to clone repository %s, %s: %w", repositoryPath, res.String(), err)
+	}
+
+	res, err = cli.commandRunner.Run(ctx, newRunArgs("-C", target, "sparse-checkout", "set", directory))
+	if err != nil {
+		return fmt.Errorf("failed to check out directory %s of repository %s, %s: %w",
+			directory, repositoryPath, res.String(), err)
+	}
+
+	return nil
+}
+
+// proxyArgs returns the arguments configuring git to clone the repository through the proxy of the HTTPS_PROXY,
+// HTTP_PROXY and NO_PROXY environment variables, the same proxy as the other requests of azd. No arguments are returned
+// when the repository isn't cloned over HTTP or no proxy applies, git then uses its own configuration.
+func proxyArgs(repositoryPath string) []string {
+	repositoryUrl, err := url.Parse(repositoryPath)
+	if err != nil || (repositoryUrl.Scheme != "https" && repositoryUrl.Scheme != "http") {
+		return []string{}
+	}
+
+	proxyUrl, err := http.ProxyFromEnvironment(&http.Request{URL: repositoryUrl})
+	if err != nil || proxyUrl == nil {
+		return []string{}
+	}
+
+	return []string{"-c", "http.proxy=" + proxyUrl.String()}
+}
+
 var noSuchRemoteRegex = regexp.MustCompile("(fatal|error): No such remote")
 var notGitRepositoryRegex = regexp.MustCompile("(fatal|error): not a git repository")
 var ErrNoSuchRemote = errors.New("no such remote")
@@ -227,6 +280,42 @@ func (cli *gitCli) ListStagedFiles(ctx context.Context, repositoryPath string) (
 	return res.Stdout, nil
 }
 
+func (cli *gitCli) ListUntrackedFiles(ctx context.Context, repositoryPath string) ([]string, error) {
+	runArgs := newRunArgs("-C", repositoryPath, "ls-files", "-z", "--others", "--exclude-standard")
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %s: %w", res.String(), err)
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(res.Stdout, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files, nil
+}
+
+func (cli *gitCli) DiffHead(ctx context.Context, repositoryPath string) (string, error) {
+	diffArgs := []string{"-C", repositoryPath, "diff", "--no-color", "--no-ext-diff", "--unified=0"}
+
+	// Without a commit, the staged files are compared to the empty tree
+	verifyArgs := newRunArgs("-C", repositoryPath, "rev-parse", "--verify", "--quiet", "HEAD")
+	if _, err := cli.commandRunner.Run(ctx, verifyArgs); err != nil {
+		diffArgs = append(diffArgs, "--cached")
+	} else {
+		diffArgs = append(diffArgs, "HEAD")
+	}
+
+	res, err := cli.commandRunner.Run(ctx, newRunArgs(diffArgs...))
+	if err != nil {
+		return "", fmt.Errorf("failed to diff changes: %s: %w", res.String(), err)
+	}
+
+	return res.Stdout, nil
+}
+
 func (cli *gitCli) AddFileExecPermission(ctx context.Context, repositoryPath string, file string) error {
 	runArgs := newRunArgs("-C", repositoryPath, "update-index", "--add", "--chmod=+x", file)
 	res, err := cli.commandRunner.Run(ctx, runArgs)
@@ -280,6 +369,27 @@ func (cli *gitCli) SetGitHubAuthForRepo(ctx context.Context, repositoryPath, cre
 	return nil
 }
 
+func (cli *gitCli) GetCredential(ctx context.Context, host string) (string, error) {
+	runArgs := newRunArgs("credential", "fill").
+		WithStdIn(strings.NewReader(fmt.Sprintf("protocol=https\nhost=%s\n\n", host)))
+	// Fail instead of prompting when no credential helper has a credential for the host
+	runArgs.Env = append(runArgs.Env, "GIT_TERMINAL_PROMPT=0")
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		log.Printf("no git credential found for %s: %v", host, err)
+		return "", nil
+	}
+
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		if password, has := strings.CutPrefix(line, "password="); has {
+			return strings.TrimSpace(password), nil
+		}
+	}
+
+	return "", nil
+}
+
 func setAuthCredentialHelper(
 	ctx context.Context, runner exec.CommandRunner, repositoryPath, credential, value, flag string) error {
 	runArgs := newRunArgs(
@@ -297,7 +407,7 @@ func setAuthCredentialHelper(
 func newRunArgs(args ...string) exec.RunArgs {
 
 	runArgs := exec.NewRunArgs("git", args...)
-	if os.Getenv("CODESPACES") == "true" {
+	if devcontainer.Detect(os.Getenv) == devcontainer.KindCodespaces {
 		// azd running git in codespaces should not use the Codespaces token.
 		// As azd needs bigger access across repos. And the token in codespaces is mono-repo by default
 		runArgs = runArgs.WithEnv([]string{"GITHUB_TOKEN=", "GH_TOKEN="})
@@ -6,6 +6,7 @@ package github
 import (
 	"archive/tar"
 	"archive/zip"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -14,16 +15,19 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry"
 	"github.com/azure/azure-dev/cli/azd/internal/telemetry/events"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/devcontainer"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -36,7 +40,21 @@ type GitHubCli interface {
 	GetAuthStatus(ctx context.Context, hostname string) (AuthStatus, error)
 	ListSecrets(ctx context.Context, repo string) error
 	SetSecret(ctx context.Context, repo string, name string, value string) error
+	// SetVariable sets a configuration variable of the repository, visible in the logs of the workflows unlike secrets.
+	SetVariable(ctx context.Context, repo string, name string, value string) error
+	// SetEnvironmentSecret sets a secret only available to the jobs of the workflows deploying to the environment.
+	SetEnvironmentSecret(ctx context.Context, repo string, environment string, name string, value string) error
+	// CreateOrUpdateEnvironment creates the deployment environment of the repository, or updates its protection rules
+	// when it exists.
+	CreateOrUpdateEnvironment(ctx context.Context, repo string, name string, protection EnvironmentProtection) error
+	// GetUserId returns the id of the user with the given login.
+	GetUserId(ctx context.Context, login string) (int, error)
+	// GetTeamId returns the id of the team of the organization with the given slug.
+	GetTeamId(ctx context.Context, org string, team string) (int, error)
 	Login(ctx context.Context, hostname string) error
+	// LoginWithToken logs in to the host with an existing token, like the GitHub credential forwarded to a
+	// dev container.
+	LoginWithToken(ctx context.Context, hostname string, token string) error
 	ListRepositories(ctx context.Context) ([]GhCliRepository, error)
 	ViewRepository(ctx context.Context, name string) (GhCliRepository, error)
 	CreatePrivateRepository(ctx context.Context, name string) error
@@ -49,6 +67,23 @@ func NewGitHubCli(ctx context.Context, console input.Console, commandRunner exec
 	return newGitHubCliImplementation(ctx, console, commandRunner, http.DefaultClient, downloadGh, extractGhCli)
 }
 
+// NewGitHubCliForHost is like NewGitHubCli, but the commands which don't name the host of a repository, like listing
+// and creating repositories or calling the API, target hostname, like a GitHub Enterprise Server instance.
+func NewGitHubCliForHost(
+	ctx context.Context,
+	console input.Console,
+	commandRunner exec.CommandRunner,
+	hostname string,
+) (GitHubCli, error) {
+	cli, err := NewGitHubCli(ctx, console, commandRunner)
+	if err != nil {
+		return nil, err
+	}
+
+	cli.(*ghCli).hostname = hostname
+	return cli, nil
+}
+
 // GitHubCliVersion is the minimum version of GitHub cli that we require (and the one we fetch when we fetch bicep on
 // behalf of a user).
 var GitHubCliVersion semver.Version = semver.MustParse("2.28.0")
@@ -140,6 +175,8 @@ var (
 type ghCli struct {
 	commandRunner exec.CommandRunner
 	path          string
+	// The host targeted by the commands, github.com when empty
+	hostname string
 }
 
 func (cli *ghCli) CheckInstalled(ctx context.Context) error {
@@ -213,6 +250,18 @@ func (cli *ghCli) Login(ctx context.Context, hostname string) error {
 	return nil
 }
 
+func (cli *ghCli) LoginWithToken(ctx context.Context, hostname string, token string) error {
+	runArgs := cli.newRunArgs("auth", "login", "--hostname", hostname, "--with-token").
+		WithStdIn(strings.NewReader(token))
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh auth login %s: %w", res.String(), err)
+	}
+
+	return nil
+}
+
 func (cli *ghCli) ListSecrets(ctx context.Context, repoSlug string) error {
 	runArgs := cli.newRunArgs("-R", repoSlug, "secret", "list")
 	res, err := cli.run(ctx, runArgs)
@@ -231,6 +280,104 @@ func (cli *ghCli) SetSecret(ctx context.Context, repoSlug string, name string, v
 	return nil
 }
 
+// SetVariable uses the REST API, `gh variable set` requires a newer version of the GitHub CLI than GitHubCliVersion.
+func (cli *ghCli) SetVariable(ctx context.Context, repoSlug string, name string, value string) error {
+	body, err := json.Marshal(map[string]string{"name": name, "value": value})
+	if err != nil {
+		return fmt.Errorf("marshalling variable %s: %w", name, err)
+	}
+
+	// Update the variable, and create it when it doesn't exist yet
+	variablesPath := "/repos/" + repoSlug + "/actions/variables"
+	runArgs := cli.newRunArgs(
+		"api", "--method", "PATCH", variablesPath+"/"+url.PathEscape(name), "--input", "-",
+	).WithStdIn(bytes.NewReader(body))
+	if _, err := cli.run(ctx, runArgs); err == nil {
+		return nil
+	}
+
+	runArgs = cli.newRunArgs("api", "--method", "POST", variablesPath, "--input", "-").WithStdIn(bytes.NewReader(body))
+	res, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("setting variable %s %s: %w", name, res.String(), err)
+	}
+	return nil
+}
+
+func (cli *ghCli) SetEnvironmentSecret(
+	ctx context.Context,
+	repoSlug string,
+	environment string,
+	name string,
+	value string,
+) error {
+	runArgs := cli.newRunArgs("-R", repoSlug, "secret", "set", name, "--env", environment, "--body", value)
+	res, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running gh secret set %s: %w", res.String(), err)
+	}
+	return nil
+}
+
+// EnvironmentReviewer is a user or a team required to approve the deployments to an environment.
+type EnvironmentReviewer struct {
+	// The type of the reviewer, User or Team
+	Type string `json:"type"`
+	Id   int    `json:"id"`
+}
+
+// EnvironmentProtection are the protection rules of a deployment environment. Rules which aren't set are left unchanged
+// when the environment exists.
+type EnvironmentProtection struct {
+	// The minutes to wait before the deployments to the environment start
+	WaitTimer int                   `json:"wait_timer,omitempty"`
+	Reviewers []EnvironmentReviewer `json:"reviewers,omitempty"`
+}
+
+func (cli *ghCli) CreateOrUpdateEnvironment(
+	ctx context.Context,
+	repoSlug string,
+	name string,
+	protection EnvironmentProtection,
+) error {
+	body, err := json.Marshal(protection)
+	if err != nil {
+		return fmt.Errorf("marshalling the protection rules of environment %s: %w", name, err)
+	}
+
+	runArgs := cli.newRunArgs(
+		"api", "--method", "PUT", "/repos/"+repoSlug+"/environments/"+url.PathEscape(name), "--input", "-",
+	).WithStdIn(bytes.NewReader(body))
+	res, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("creating environment %s %s: %w", name, res.String(), err)
+	}
+	return nil
+}
+
+func (cli *ghCli) GetUserId(ctx context.Context, login string) (int, error) {
+	return cli.getId(ctx, "/users/"+login)
+}
+
+func (cli *ghCli) GetTeamId(ctx context.Context, org string, team string) (int, error) {
+	return cli.getId(ctx, "/orgs/"+org+"/teams/"+team)
+}
+
+// getId returns the id of the object of the API at the given path, like a user or a team
+func (cli *ghCli) getId(ctx context.Context, path string) (int, error) {
+	runArgs := cli.newRunArgs("api", path, "--jq", ".id")
+	res, err := cli.run(ctx, runArgs)
+	if err != nil {
+		return 0, fmt.Errorf("failed running gh api %s %s: %w", path, res.String(), err)
+	}
+
+	id, err := strconv.Atoi(strings.TrimSpace(res.Stdout))
+	if err != nil {
+		return 0, fmt.Errorf("could not parse the id of %s from output %s: %w", path, res.Stdout, err)
+	}
+	return id, nil
+}
+
 // cGhCliVersionRegexp fetches the version number from the output of gh --version, which looks like this:
 //
 // gh version 2.6.0 (2022-03-15)
@@ -355,8 +502,19 @@ func (cli *ghCli) GitHubActionsExists(ctx context.Context, repoSlug string) (boo
 func (cli *ghCli) newRunArgs(args ...string) exec.RunArgs {
 
 	runArgs := exec.NewRunArgs(cli.path, args...)
-	if os.Getenv("CODESPACES") == "true" {
-		runArgs = runArgs.WithEnv([]string{"GITHUB_TOKEN=", "GH_TOKEN="})
+
+	var env []string
+	if devcontainer.Detect(os.Getenv) == devcontainer.KindCodespaces {
+		env = append(env, "GITHUB_TOKEN=", "GH_TOKEN=")
+	}
+
+	// gh targets GH_HOST instead of github.com for commands which don't name the host of a repository
+	if cli.hostname != "" && cli.hostname != GitHubHostName {
+		env = append(env, fmt.Sprintf("GH_HOST=%s", cli.hostname))
+	}
+
+	if len(env) > 0 {
+		runArgs = runArgs.WithEnv(env)
 	}
 
 	return runArgs
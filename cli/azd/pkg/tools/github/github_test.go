@@ -268,3 +268,36 @@ func createSampleTarGz(path, content, file string) (string, error) {
 
 	return filePath, nil
 }
+
+func TestCreateOrUpdateEnvironment(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	cli := &ghCli{path: "gh", commandRunner: mockContext.CommandRunner}
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "api /users/octocat")
+	}).Respond(exec.NewRunResult(0, "583231\n", ""))
+
+	var runArgs exec.RunArgs
+	var body []byte
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "--method PUT")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		body, _ = io.ReadAll(args.StdIn)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	id, err := cli.GetUserId(*mockContext.Context, "octocat")
+	require.NoError(t, err)
+	require.Equal(t, 583231, id)
+
+	err = cli.CreateOrUpdateEnvironment(*mockContext.Context, "Azure/azure-dev", "prod west", EnvironmentProtection{
+		WaitTimer: 30,
+		Reviewers: []EnvironmentReviewer{{Type: "User", Id: id}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"api", "--method", "PUT", "/repos/Azure/azure-dev/environments/prod%20west", "--input", "-",
+	}, runArgs.Args)
+	require.JSONEq(t, `{"wait_timer": 30, "reviewers": [{"type": "User", "id": 583231}]}`, string(body))
+}
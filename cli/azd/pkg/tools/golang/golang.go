@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+type GoCli interface {
+	tools.ExternalTool
+	// ModDownload downloads the modules required by the module of the project into the module cache
+	ModDownload(ctx context.Context, projectPath string) error
+	// Build compiles the package of the project into the output binary
+	Build(ctx context.Context, projectPath string, pkg string, output string, options BuildOptions) error
+}
+
+// BuildOptions are the options of go build.
+type BuildOptions struct {
+	// The target operating system, like linux. The operating system of the machine running azd when empty.
+	OS string
+	// The target architecture, like amd64. The architecture of the machine running azd when empty.
+	Arch string
+	// Disables cgo, so the binary is statically linked and runs on the host without the C libraries of the machine
+	// running azd.
+	DisableCgo bool
+	// Additional build flags, like -ldflags=-s -w
+	Flags []string
+}
+
+type goCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewGoCli(commandRunner exec.CommandRunner) GoCli {
+	return &goCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *goCli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 1,
+			Minor: 20,
+			Patch: 0},
+		UpdateCommand: "Visit https://go.dev/dl/ to upgrade",
+	}
+}
+
+func (cli *goCli) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("go")
+	if err != nil {
+		return err
+	}
+
+	goRes, err := tools.ExecuteCommand(ctx, cli.commandRunner, "go", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	goSemver, err := tools.ExtractVersion(goRes)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := cli.versionInfo()
+	if goSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+
+	return nil
+}
+
+func (cli *goCli) InstallUrl() string {
+	return "https://go.dev/doc/install"
+}
+
+func (cli *goCli) Name() string {
+	return "Go"
+}
+
+func (cli *goCli) ModDownload(ctx context.Context, projectPath string) error {
+	runArgs := exec.
+		NewRunArgs("go", "mod", "download").
+		WithCwd(projectPath)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("go mod download on project '%s' failed: %s: %w", projectPath, res.String(), err)
+	}
+
+	return nil
+}
+
+func (cli *goCli) Build(ctx context.Context, projectPath string, pkg string, output string, options BuildOptions) error {
+	args := []string{"build", "-o", output}
+	args = append(args, options.Flags...)
+	args = append(args, pkg)
+
+	env := []string{}
+	if options.OS != "" {
+		env = append(env, "GOOS="+options.OS)
+	}
+	if options.Arch != "" {
+		env = append(env, "GOARCH="+options.Arch)
+	}
+	if options.DisableCgo {
+		env = append(env, "CGO_ENABLED=0")
+	}
+
+	runArgs := exec.
+		NewRunArgs("go", args...).
+		WithCwd(projectPath).
+		WithEnv(env)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("go build on project '%s' failed: %s: %w", projectPath, res.String(), err)
+	}
+
+	return nil
+}
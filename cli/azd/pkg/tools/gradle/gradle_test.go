@@ -0,0 +1,129 @@
+package gradle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_getGradlePath(t *testing.T) {
+	rootPath := t.TempDir()
+	sourcePath := filepath.Join(rootPath, "src")
+	projectPath := filepath.Join(sourcePath, "api")
+	pathDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(projectPath, 0755))
+	ostest.Unsetenv(t, "PATH")
+
+	tests := []struct {
+		name        string
+		gradlewPath []string
+		gradlePath  []string
+		envVar      map[string]string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "GradlewProjectPath",
+			gradlewPath: []string{projectPath},
+			want:        filepath.Join(projectPath, gradlewWithExt()),
+		},
+		{
+			name:        "GradlewRootPath",
+			gradlewPath: []string{rootPath},
+			want:        filepath.Join(rootPath, gradlewWithExt()),
+		},
+		{
+			name:        "GradlewFirst",
+			gradlewPath: []string{sourcePath},
+			gradlePath:  []string{pathDir},
+			envVar:      map[string]string{"PATH": pathDir},
+			want:        filepath.Join(sourcePath, gradlewWithExt()),
+		},
+		{
+			name:       "Gradle",
+			gradlePath: []string{pathDir},
+			envVar:     map[string]string{"PATH": pathDir},
+			want:       filepath.Join(pathDir, gradleWithExt()),
+		},
+		{name: "NotFound", want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placeExecutable(t, gradlewWithExt(), tt.gradlewPath...)
+			placeExecutable(t, gradleWithExt(), tt.gradlePath...)
+			ostest.Setenvs(t, tt.envVar)
+
+			actual, err := getGradlePath(projectPath, rootPath)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
+func Test_extractVersion(t *testing.T) {
+	execMock := mockexec.NewMockCommandRunner().
+		When(func(a exec.RunArgs, command string) bool { return a.Args[0] == "--version" }).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(0, heredoc.Doc(`
+
+			------------------------------------------------------------
+			Gradle 8.4
+			------------------------------------------------------------
+
+			Build time:   2023-10-04 20:52:13 UTC
+			Kotlin:       1.9.10
+			JVM:          17.0.8 (Microsoft 17.0.8+7-LTS)
+			`), ""), nil
+		})
+
+	projectPath := t.TempDir()
+	placeExecutable(t, gradlewWithExt(), projectPath)
+
+	gradle := NewGradleCli(execMock).(*gradleCli)
+	gradle.SetPath(projectPath, projectPath)
+	ver, err := gradle.extractVersion(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "8.4", ver)
+}
+
+func placeExecutable(t *testing.T, name string, dirs ...string) {
+	for _, createPath := range dirs {
+		toCreate := filepath.Join(createPath, name)
+		ostest.Create(t, toCreate)
+
+		err := os.Chmod(toCreate, 0755)
+		require.NoError(t, err)
+	}
+}
+
+func gradleWithExt() string {
+	if runtime.GOOS == "windows" {
+		// For Windows, we want to test EXT resolution behavior
+		return "gradle.bat"
+	} else {
+		return "gradle"
+	}
+}
+
+func gradlewWithExt() string {
+	if runtime.GOOS == "windows" {
+		return "gradlew.bat"
+	} else {
+		return "gradlew"
+	}
+}
@@ -42,6 +42,8 @@ type KubectlCli interface {
 	Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error)
 	// Gets the deployment rollout status
 	RolloutStatus(ctx context.Context, deploymentName string, flags *KubeCliFlags) (*exec.RunResult, error)
+	// Forwards a local port to a port of the resource, like svc/api, until the context is cancelled
+	PortForward(ctx context.Context, resource string, localPort int, remotePort int, flags *KubeCliFlags) error
 }
 
 type OutputType string
@@ -253,6 +255,24 @@ func (cli *kubectlCli) RolloutStatus(
 	return &res, nil
 }
 
+func (cli *kubectlCli) PortForward(
+	ctx context.Context,
+	resource string,
+	localPort int,
+	remotePort int,
+	flags *KubeCliFlags,
+) error {
+	runArgs := exec.
+		NewRunArgs("kubectl", "port-forward", resource, fmt.Sprintf("%d:%d", localPort, remotePort)).
+		WithInteractive(true)
+
+	if _, err := cli.executeCommandWithArgs(ctx, runArgs, flags); err != nil {
+		return fmt.Errorf("port forwarding to %s: %w", resource, err)
+	}
+
+	return nil
+}
+
 // Executes a k8s CLI command from the specified arguments and flags
 func (cli *kubectlCli) Exec(ctx context.Context, flags *KubeCliFlags, args ...string) (exec.RunResult, error) {
 	runArgs := exec.
@@ -142,6 +142,16 @@ func Test_Command_Args(t *testing.T) {
 				return err
 			},
 		},
+		"port-forward": {
+			mockCommandPredicate: "kubectl port-forward",
+			expectedCmd:          "kubectl",
+			expectedArgs:         []string{"port-forward", "svc/api", "8080:80", "-n", "test-namespace"},
+			testFn: func() error {
+				return cli.PortForward(*mockContext.Context, "svc/api", 8080, 80, &KubeCliFlags{
+					Namespace: "test-namespace",
+				})
+			},
+		},
 		"exec": {
 			mockCommandPredicate: "kubectl get deployment",
 			expectedCmd:          "kubectl",
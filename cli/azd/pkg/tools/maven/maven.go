@@ -21,7 +21,9 @@ type MavenCli interface {
 	SetPath(projectPath string, rootProjectPath string)
 	ResolveDependencies(ctx context.Context, projectPath string) error
 	Compile(ctx context.Context, projectPath string) error
-	Package(ctx context.Context, projectPath string) error
+	Package(ctx context.Context, projectPath string, skipTests bool) error
+	// BuildOutputPath returns the directory of the archives built by Package
+	BuildOutputPath(projectPath string) string
 }
 
 type mavenCli struct {
@@ -188,14 +190,19 @@ func (cli *mavenCli) Compile(ctx context.Context, projectPath string) error {
 	return nil
 }
 
-func (cli *mavenCli) Package(ctx context.Context, projectPath string) error {
+func (cli *mavenCli) Package(ctx context.Context, projectPath string, skipTests bool) error {
 	mvnCmd, err := cli.mvnCmd()
 	if err != nil {
 		return err
 	}
 
-	// Maven's package phase includes tests by default. Skip it explicitly.
-	runArgs := exec.NewRunArgs(mvnCmd, "package", "-DskipTests").WithCwd(projectPath)
+	// Maven's package phase includes tests by default. Skip it explicitly when requested.
+	args := []string{"package"}
+	if skipTests {
+		args = append(args, "-DskipTests")
+	}
+
+	runArgs := exec.NewRunArgs(mvnCmd, args...).WithCwd(projectPath)
 	res, err := cli.commandRunner.Run(ctx, runArgs)
 	if err != nil {
 		return fmt.Errorf("mvn package on project '%s' failed: %s: %w", projectPath, res.String(), err)
@@ -218,6 +225,11 @@ func (cli *mavenCli) ResolveDependencies(ctx context.Context, projectPath string
 	return nil
 }
 
+// BuildOutputPath returns target, where the package phase of Maven writes the archives of the project.
+func (cli *mavenCli) BuildOutputPath(projectPath string) string {
+	return filepath.Join(projectPath, "target")
+}
+
 func NewMavenCli(commandRunner exec.CommandRunner) MavenCli {
 	return &mavenCli{
 		commandRunner: commandRunner,
@@ -5,7 +5,10 @@ package npm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -15,6 +18,8 @@ import (
 type NpmCli interface {
 	tools.ExternalTool
 	Install(ctx context.Context, project string) error
+	// InstallDependencies installs the dependencies of a project with the package manager of the options
+	InstallDependencies(ctx context.Context, projectPath string, options InstallOptions) error
 	RunScript(ctx context.Context, projectPath string, scriptName string, env []string) error
 	Prune(ctx context.Context, projectPath string, production bool) error
 }
@@ -83,6 +88,118 @@ func (cli *npmCli) Install(ctx context.Context, project string) error {
 	return nil
 }
 
+type PackageManagerKind string
+
+const (
+	PackageManagerNpm  PackageManagerKind = "npm"
+	PackageManagerYarn PackageManagerKind = "yarn"
+	PackageManagerPnpm PackageManagerKind = "pnpm"
+)
+
+// lockFiles maps the lockfile of each package manager to the package manager
+var lockFiles = []struct {
+	name    string
+	manager PackageManagerKind
+}{
+	{"package-lock.json", PackageManagerNpm},
+	{"npm-shrinkwrap.json", PackageManagerNpm},
+	{"yarn.lock", PackageManagerYarn},
+	{"pnpm-lock.yaml", PackageManagerPnpm},
+}
+
+// LockFile is the lockfile of a project, written by its package manager.
+type LockFile struct {
+	// The path of the lockfile
+	Path           string
+	PackageManager PackageManagerKind
+}
+
+// FindLockFile finds the lockfile of the project in the project directory, up to the root directory. The lockfile of a
+// workspace is in the root directory of the workspace, where the dependencies of the packages of the workspace are
+// hoisted. Returns nil when the project has no lockfile.
+func FindLockFile(projectPath string, rootPath string) (*LockFile, error) {
+	searchDir, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		for _, lockFile := range lockFiles {
+			lockFilePath := filepath.Join(searchDir, lockFile.name)
+			if _, err := os.Stat(lockFilePath); err == nil {
+				return &LockFile{Path: lockFilePath, PackageManager: lockFile.manager}, nil
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+
+		parentDir := filepath.Dir(searchDir)
+		// Past root, terminate search and return not found
+		if len(parentDir) < len(root) || parentDir == searchDir {
+			return nil, nil
+		}
+		searchDir = parentDir
+	}
+}
+
+// InstallOptions configures an install of the dependencies of a project.
+type InstallOptions struct {
+	// The package manager running the install, npm when empty
+	PackageManager PackageManagerKind
+	// Installs the exact versions of the lockfile, failing when the lockfile is out of date
+	Frozen bool
+	// Skips the development dependencies
+	Production bool
+}
+
+func (cli *npmCli) InstallDependencies(ctx context.Context, projectPath string, options InstallOptions) error {
+	var args []string
+	switch options.PackageManager {
+	case PackageManagerNpm, "":
+		args = []string{"npm", "install"}
+		if options.Frozen {
+			args = []string{"npm", "ci"}
+		}
+		if options.Production {
+			args = append(args, "--omit=dev")
+		}
+	case PackageManagerYarn:
+		args = []string{"yarn", "install", "--non-interactive"}
+		if options.Frozen {
+			args = append(args, "--frozen-lockfile")
+		}
+		if options.Production {
+			args = append(args, "--production")
+		}
+	case PackageManagerPnpm:
+		args = []string{"pnpm", "install"}
+		if options.Frozen {
+			args = append(args, "--frozen-lockfile")
+		}
+		if options.Production {
+			args = append(args, "--prod")
+		}
+	default:
+		return fmt.Errorf("unsupported package manager '%s'", options.PackageManager)
+	}
+
+	runArgs := exec.
+		NewRunArgs(args[0], args[1:]...).
+		WithCwd(projectPath)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to install project %s with %s, %s: %w", projectPath, args[0], res.String(), err)
+	}
+
+	return nil
+}
+
 func (cli *npmCli) RunScript(ctx context.Context, projectPath string, scriptName string, env []string) error {
 	runArgs := exec.
 		NewRunArgs("npm", "run", scriptName, "--if-present").
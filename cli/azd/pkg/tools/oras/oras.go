@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package oras
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+// Oras pushes OCI artifacts to container registries, with the credentials of the docker config.
+type Oras interface {
+	tools.ExternalTool
+	// Attaches a file to an image of a registry, as an artifact of the given type referring to the image
+	Attach(ctx context.Context, image string, artifactType string, filePath string) error
+}
+
+func NewOras(commandRunner exec.CommandRunner) Oras {
+	return &oras{
+		commandRunner: commandRunner,
+	}
+}
+
+type oras struct {
+	commandRunner exec.CommandRunner
+}
+
+func (o *oras) Attach(ctx context.Context, image string, artifactType string, filePath string) error {
+	// ORAS only pushes files by their path relative to the working directory
+	runArgs := exec.NewRunArgs(
+		"oras", "attach", "--artifact-type", artifactType, image, filepath.Base(filePath),
+	).WithCwd(filepath.Dir(filePath))
+
+	res, err := o.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("attaching %s to %s: %s: %w", filepath.Base(filePath), image, res.String(), err)
+	}
+
+	return nil
+}
+
+func (o *oras) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 1,
+			Minor: 0,
+			Patch: 0},
+		UpdateCommand: "Visit https://oras.land/docs/installation to upgrade",
+	}
+}
+
+func (o *oras) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("oras")
+	if err != nil {
+		return err
+	}
+	orasRes, err := tools.ExecuteCommand(ctx, o.commandRunner, "oras", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", o.Name(), err)
+	}
+	log.Printf("oras version: %s", orasRes)
+	orasSemver, err := tools.ExtractVersion(orasRes)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := o.versionInfo()
+	if orasSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: o.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+func (o *oras) InstallUrl() string {
+	return "https://oras.land/docs/installation"
+}
+
+func (o *oras) Name() string {
+	return "ORAS"
+}
@@ -5,11 +5,14 @@ package python
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
 	"path"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -68,6 +71,16 @@ func (cli *PythonCli) Name() string {
 }
 
 func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, environment, requirementFile string) error {
+	return cli.pipInstall(ctx, workingDir, environment, "-r", requirementFile)
+}
+
+// InstallProject installs the project of the working directory in editable mode, with the dependencies declared in its
+// pyproject.toml, for projects without a requirements file like Poetry projects.
+func (cli *PythonCli) InstallProject(ctx context.Context, workingDir, environment string) error {
+	return cli.pipInstall(ctx, workingDir, environment, "-e", ".")
+}
+
+func (cli *PythonCli) pipInstall(ctx context.Context, workingDir, environment string, installArgs ...string) error {
 	var res exec.RunResult
 	var err error
 
@@ -89,14 +102,14 @@ func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, envir
 		vEnvSetting := fmt.Sprintf("VIRTUAL_ENV=%s", path.Join(absWorkingDir, environment))
 
 		runArgs := exec.
-			NewRunArgs(pyString, "-m", "pip", "install", "-r", requirementFile).
+			NewRunArgs(pyString, append([]string{"-m", "pip", "install"}, installArgs...)...).
 			WithCwd(workingDir).
 			WithEnv([]string{vEnvSetting})
 
 		res, err = cli.commandRunner.Run(ctx, runArgs)
 	} else {
 		envActivation := ". " + path.Join(environment, "bin", "activate")
-		installCmd := fmt.Sprintf("%s -m pip install -r %s", pyString, requirementFile)
+		installCmd := fmt.Sprintf("%s -m pip install %s", pyString, strings.Join(installArgs, " "))
 		commands := []string{envActivation, installCmd}
 
 		runArgs := exec.NewRunArgs("").WithCwd(workingDir)
@@ -109,6 +122,80 @@ func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, envir
 	return nil
 }
 
+// TargetInstallOptions configures the installation of the dependencies of a project into a directory, deployed with
+// the project.
+type TargetInstallOptions struct {
+	// The requirements file listing the dependencies. When empty, the project is built with the build backend of its
+	// pyproject.toml, like Poetry or setuptools, and installed with its dependencies, without the optional ones.
+	RequirementsFile string
+	// The directory the packages are installed into
+	Target string
+	// The platform of the wheels installed, like manylinux2014_x86_64. Only binary packages are installed when set, so
+	// packages with native code aren't built for the platform of the machine running azd.
+	Platform string
+	// The Python version of the wheels installed, like 3.11. The version of the virtual environment when empty.
+	PythonVersion string
+}
+
+// InstallToTarget installs the dependencies of the project of the working directory into a target directory with the
+// pip of an isolated virtual environment, so the packages of the environment used for development don't leak into the
+// target. The virtual environment is created at venvPath if it doesn't exist.
+func (cli *PythonCli) InstallToTarget(
+	ctx context.Context,
+	workingDir string,
+	venvPath string,
+	options TargetInstallOptions,
+) error {
+	if _, err := os.Stat(venvPath); errors.Is(err, os.ErrNotExist) {
+		if err := cli.CreateVirtualEnv(ctx, filepath.Dir(venvPath), filepath.Base(venvPath)); err != nil {
+			return err
+		}
+	}
+
+	venvPython := filepath.Join(venvPath, "bin", "python")
+	if runtime.GOOS == "windows" {
+		venvPython = filepath.Join(venvPath, "Scripts", "python.exe")
+	}
+
+	installArgs := []string{"-m", "pip", "install", "--disable-pip-version-check", "--target", options.Target}
+	if options.Platform != "" {
+		installArgs = append(installArgs, "--platform", options.Platform, "--only-binary=:all:", "--implementation", "cp")
+	}
+	if options.PythonVersion != "" {
+		installArgs = append(installArgs, "--python-version", options.PythonVersion)
+	}
+
+	if options.RequirementsFile != "" {
+		installArgs = append(installArgs, "-r", options.RequirementsFile)
+	} else {
+		// Only binary packages can be installed for another platform, the project is built into a wheel first.
+		wheelDir := filepath.Join(venvPath, "wheels")
+		wheelArgs := exec.NewRunArgs(
+			venvPython, "-m", "pip", "wheel", "--disable-pip-version-check", "--no-deps", "--wheel-dir", wheelDir, ".",
+		).WithCwd(workingDir)
+		if res, err := cli.commandRunner.Run(ctx, wheelArgs); err != nil {
+			return fmt.Errorf("failed to build wheel of project '%s': %w (%s)", workingDir, err, res.String())
+		}
+
+		wheels, err := filepath.Glob(filepath.Join(wheelDir, "*.whl"))
+		if err != nil {
+			return err
+		}
+		if len(wheels) != 1 {
+			return fmt.Errorf("expected one wheel built for project '%s', found %d", workingDir, len(wheels))
+		}
+
+		installArgs = append(installArgs, wheels[0])
+	}
+
+	res, err := cli.commandRunner.Run(ctx, exec.NewRunArgs(venvPython, installArgs...).WithCwd(workingDir))
+	if err != nil {
+		return fmt.Errorf("failed to install requirements for project '%s': %w (%s)", workingDir, err, res.String())
+	}
+
+	return nil
+}
+
 func (cli *PythonCli) CreateVirtualEnv(ctx context.Context, workingDir, name string) error {
 	pyString, err := checkPath()
 	if err != nil {
@@ -0,0 +1,177 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package trivy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+// The severities of vulnerabilities, from the lowest to the highest
+var Severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// Vulnerability is a vulnerability of a package of a container image.
+type Vulnerability struct {
+	VulnerabilityID  string `json:"VulnerabilityID"`
+	PkgName          string `json:"PkgName"`
+	InstalledVersion string `json:"InstalledVersion"`
+	FixedVersion     string `json:"FixedVersion"`
+	Severity         string `json:"Severity"`
+	Title            string `json:"Title"`
+}
+
+// SbomFormat is the format of a software bill of materials.
+type SbomFormat string
+
+const (
+	SbomFormatCycloneDx SbomFormat = "cyclonedx"
+	SbomFormatSpdx      SbomFormat = "spdx-json"
+)
+
+type Trivy interface {
+	tools.ExternalTool
+	// Scans a local container image, and returns its vulnerabilities of the given severities
+	ScanImage(
+		ctx context.Context,
+		cwd string,
+		image string,
+		severities []string,
+		ignoreUnfixed bool,
+	) ([]Vulnerability, error)
+	// Writes the software bill of materials of a local container image to outputPath
+	GenerateImageSbom(ctx context.Context, cwd string, image string, format SbomFormat, outputPath string) error
+	// Writes the software bill of materials of a directory to outputPath, from the lock files and manifests of its
+	// packages, like package-lock.json, packages.lock.json, requirements.txt or go.mod
+	GenerateFsSbom(ctx context.Context, path string, format SbomFormat, outputPath string) error
+}
+
+func NewTrivy(commandRunner exec.CommandRunner) Trivy {
+	return &trivy{
+		commandRunner: commandRunner,
+	}
+}
+
+type trivy struct {
+	commandRunner exec.CommandRunner
+}
+
+// scanReport is the subset of the JSON report of `trivy image` read by azd.
+type scanReport struct {
+	Results []struct {
+		Target          string          `json:"Target"`
+		Vulnerabilities []Vulnerability `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+func (t *trivy) ScanImage(
+	ctx context.Context,
+	cwd string,
+	image string,
+	severities []string,
+	ignoreUnfixed bool,
+) ([]Vulnerability, error) {
+	runArgs := exec.NewRunArgs("trivy", "image", "--quiet", "--format", "json").WithCwd(cwd)
+	if len(severities) > 0 {
+		runArgs = runArgs.AppendParams("--severity", strings.Join(severities, ","))
+	}
+
+	if ignoreUnfixed {
+		runArgs = runArgs.AppendParams("--ignore-unfixed")
+	}
+
+	res, err := t.commandRunner.Run(ctx, runArgs.AppendParams(image))
+	if err != nil {
+		return nil, fmt.Errorf("scanning image %s: %s: %w", image, res.String(), err)
+	}
+
+	var report scanReport
+	if err := json.Unmarshal([]byte(res.Stdout), &report); err != nil {
+		return nil, fmt.Errorf("reading the scan report of image %s: %w", image, err)
+	}
+
+	var vulnerabilities []Vulnerability
+	for _, result := range report.Results {
+		vulnerabilities = append(vulnerabilities, result.Vulnerabilities...)
+	}
+
+	return vulnerabilities, nil
+}
+
+func (t *trivy) GenerateImageSbom(
+	ctx context.Context,
+	cwd string,
+	image string,
+	format SbomFormat,
+	outputPath string,
+) error {
+	runArgs := exec.NewRunArgs(
+		"trivy", "image", "--quiet", "--format", string(format), "--output", outputPath, image,
+	).WithCwd(cwd)
+
+	res, err := t.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("generating the SBOM of image %s: %s: %w", image, res.String(), err)
+	}
+
+	return nil
+}
+
+func (t *trivy) GenerateFsSbom(ctx context.Context, path string, format SbomFormat, outputPath string) error {
+	runArgs := exec.NewRunArgs(
+		"trivy", "fs", "--quiet", "--format", string(format), "--output", outputPath, path,
+	).WithCwd(path)
+
+	res, err := t.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("generating the SBOM of %s: %s: %w", path, res.String(), err)
+	}
+
+	return nil
+}
+
+func (t *trivy) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 0,
+			Minor: 35,
+			Patch: 0},
+		UpdateCommand: "Visit https://aquasecurity.github.io/trivy/latest/getting-started/installation to upgrade",
+	}
+}
+
+func (t *trivy) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("trivy")
+	if err != nil {
+		return err
+	}
+	trivyRes, err := tools.ExecuteCommand(ctx, t.commandRunner, "trivy", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", t.Name(), err)
+	}
+	log.Printf("trivy version: %s", trivyRes)
+	trivySemver, err := tools.ExtractVersion(trivyRes)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := t.versionInfo()
+	if trivySemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: t.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+func (t *trivy) InstallUrl() string {
+	return "https://aquasecurity.github.io/trivy/latest/getting-started/installation/"
+}
+
+func (t *trivy) Name() string {
+	return "Trivy"
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package trivy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ScanImage(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	trivyCli := NewTrivy(mockContext.CommandRunner)
+
+	var runArgs exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "trivy image")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		runArgs = args
+		return exec.NewRunResult(0, `{
+  "Results": [
+    {
+      "Target": "api:latest (debian 11.6)",
+      "Vulnerabilities": [
+        {
+          "VulnerabilityID": "CVE-2023-0286",
+          "PkgName": "libssl1.1",
+          "InstalledVersion": "1.1.1n-0+deb11u3",
+          "FixedVersion": "1.1.1n-0+deb11u4",
+          "Severity": "HIGH",
+          "Title": "openssl: X.400 address type confusion in X.509 GeneralName"
+        }
+      ]
+    },
+    {
+      "Target": "app/package-lock.json"
+    }
+  ]
+}`, ""), nil
+	})
+
+	vulnerabilities, err := trivyCli.ScanImage(
+		*mockContext.Context,
+		"./src/api",
+		"api:latest",
+		[]string{"HIGH", "CRITICAL"},
+		true,
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "./src/api", runArgs.Cwd)
+	require.Equal(t, []string{
+		"image", "--quiet", "--format", "json",
+		"--severity", "HIGH,CRITICAL",
+		"--ignore-unfixed",
+		"api:latest",
+	}, runArgs.Args)
+	require.Equal(t, []Vulnerability{
+		{
+			VulnerabilityID:  "CVE-2023-0286",
+			PkgName:          "libssl1.1",
+			InstalledVersion: "1.1.1n-0+deb11u3",
+			FixedVersion:     "1.1.1n-0+deb11u4",
+			Severity:         "HIGH",
+			Title:            "openssl: X.400 address type confusion in X.509 GeneralName",
+		},
+	}, vulnerabilities)
+}
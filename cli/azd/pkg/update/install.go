@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package update
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// InstallMethod is how the running azd binary was installed.
+type InstallMethod string
+
+const (
+	// InstallMethodScript is an install with the install-azd scripts or a binary copied by the user, azd can replace it.
+	InstallMethodScript InstallMethod = "script"
+	// InstallMethodHomebrew is an install with `brew install azd`.
+	InstallMethodHomebrew InstallMethod = "homebrew"
+	// InstallMethodMsi is an install with the Windows installer, directly or through winget or chocolatey.
+	InstallMethodMsi InstallMethod = "msi"
+	// InstallMethodLinuxPackage is an install with the deb or rpm package.
+	InstallMethodLinuxPackage InstallMethod = "linux-package"
+)
+
+// ErrManagedInstall is returned when azd was installed by a package manager, which must upgrade it instead of
+// `azd upgrade` so its view of the installed version stays correct.
+var ErrManagedInstall = errors.New("azd was installed by a package manager")
+
+// InstallInstructionsUrl documents how to install and upgrade azd for each install method.
+const InstallInstructionsUrl = "https://aka.ms/azd/install"
+
+// upgradeCommands are the commands upgrading azd for each managed install method, empty when the user has to download
+// the new package.
+var upgradeCommands = map[InstallMethod]string{
+	InstallMethodHomebrew:     "brew upgrade azd",
+	InstallMethodMsi:          "winget upgrade Microsoft.Azd",
+	InstallMethodLinuxPackage: "",
+}
+
+// DetectInstallMethod returns how the running azd binary was installed, from the location of the binary.
+func DetectInstallMethod() InstallMethod {
+	exePath, err := executablePath()
+	if err != nil {
+		return InstallMethodScript
+	}
+
+	return installMethod(exePath, runtime.GOOS)
+}
+
+func installMethod(exePath string, goos string) InstallMethod {
+	path := strings.ToLower(exePath)
+	if goos == "windows" {
+		path = strings.ReplaceAll(path, `\`, "/")
+	}
+
+	switch {
+	case strings.Contains(path, "/cellar/") || strings.Contains(path, "/homebrew/") ||
+		strings.Contains(path, "/linuxbrew/"):
+		return InstallMethodHomebrew
+	// The MSI, also used by the winget and chocolatey packages, installs to "Azure Dev CLI" under Program Files
+	case goos == "windows" && strings.Contains(path, "/azure dev cli/"):
+		return InstallMethodMsi
+	case goos == "linux" && strings.HasPrefix(path, "/opt/microsoft/azd/"):
+		return InstallMethodLinuxPackage
+	default:
+		return InstallMethodScript
+	}
+}
+
+// CanUpgrade returns nil when `azd upgrade` can replace the running binary. It returns ErrUpgradeNotSupported for
+// builds without a signing key, and ErrManagedInstall, naming the command to use instead, for package manager installs.
+func CanUpgrade() error {
+	if _, err := signingKey(); err != nil {
+		return err
+	}
+
+	return checkInstallMethod(DetectInstallMethod())
+}
+
+func checkInstallMethod(method InstallMethod) error {
+	command, has := upgradeCommands[method]
+	if !has {
+		return nil
+	}
+
+	if command == "" {
+		return fmt.Errorf(
+			"%w (%s), upgrade it following the instructions at %s", ErrManagedInstall, method, InstallInstructionsUrl)
+	}
+
+	return fmt.Errorf("%w (%s), upgrade it with: %s", ErrManagedInstall, method, command)
+}
+
+// UpgradeCommand returns the command upgrading the running azd binary: `azd upgrade` when it can, or else the command
+// of the package manager. It returns an empty string when there is no command, the user has to follow the install
+// instructions.
+func UpgradeCommand() string {
+	if _, err := signingKey(); err != nil {
+		return ""
+	}
+
+	if command, has := upgradeCommands[DetectInstallMethod()]; has {
+		return command
+	}
+
+	return "azd upgrade"
+}
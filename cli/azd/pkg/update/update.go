@@ -0,0 +1,292 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package update checks for new versions of azd on a release channel, and replaces the running azd binary with the
+// latest release of the channel.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/blang/semver/v4"
+)
+
+// Channel is a release channel of azd.
+type Channel string
+
+const (
+	// ChannelStable publishes the official releases of azd.
+	ChannelStable Channel = "stable"
+	// ChannelBeta publishes the release candidates of azd.
+	ChannelBeta Channel = "beta"
+	// ChannelDaily publishes a build of the main branch every day.
+	ChannelDaily Channel = "daily"
+)
+
+const (
+	// ChannelConfigPath is the user configuration setting selecting the release channel, ex:
+	// `azd config set updates.channel daily`.
+	ChannelConfigPath = "updates.channel"
+
+	// CheckIntervalConfigPath is the user configuration setting, in hours, of the frequency of the check for a new
+	// version. The "new version available" notice is disabled when set to 0.
+	CheckIntervalConfigPath = "updates.checkIntervalHours"
+
+	// DefaultCheckInterval is the frequency of the check for a new version when not configured.
+	DefaultCheckInterval = 24 * time.Hour
+
+	// updateCheckCacheFileName is the name of the file created in the azd configuration directory
+	// which is used to cache version information for the up to date check.
+	updateCheckCacheFileName = "update-check.json"
+)
+
+// The root of the release artifacts of each channel.
+const releaseBaseUrl = "https://azdrelease.azureedge.net/azd/standalone"
+
+type channelEndpoints struct {
+	// The URL of a text file containing the latest version of the channel.
+	versionUrl string
+	// The URL of the folder containing the release artifacts of the latest version of the channel.
+	artifactsUrl string
+}
+
+var channels = map[Channel]channelEndpoints{
+	ChannelStable: {
+		versionUrl:   "https://aka.ms/azure-dev/versions/cli/latest",
+		artifactsUrl: releaseBaseUrl + "/release/latest",
+	},
+	ChannelBeta: {
+		versionUrl:   releaseBaseUrl + "/release/beta/version.txt",
+		artifactsUrl: releaseBaseUrl + "/release/beta",
+	},
+	ChannelDaily: {
+		versionUrl:   releaseBaseUrl + "/daily/version.txt",
+		artifactsUrl: releaseBaseUrl + "/daily",
+	},
+}
+
+// ParseChannel parses the name of a release channel.
+func ParseChannel(value string) (Channel, error) {
+	channel := Channel(strings.ToLower(strings.TrimSpace(value)))
+	if _, has := channels[channel]; !has {
+		return "", fmt.Errorf(
+			"invalid release channel '%s', supported channels are: %s, %s, %s",
+			value, ChannelStable, ChannelBeta, ChannelDaily,
+		)
+	}
+
+	return channel, nil
+}
+
+// Config is the update configuration of the user.
+type Config struct {
+	Channel       Channel
+	CheckInterval time.Duration
+}
+
+// LoadConfig reads the update configuration from the user configuration. Values set with `azd config set` are stored
+// as strings.
+func LoadConfig(userConfig config.Config) (Config, error) {
+	result := Config{
+		Channel:       ChannelStable,
+		CheckInterval: DefaultCheckInterval,
+	}
+
+	if value, has := userConfig.Get(ChannelConfigPath); has {
+		channel, err := ParseChannel(fmt.Sprint(value))
+		if err != nil {
+			return result, fmt.Errorf("invalid value for '%s': %w", ChannelConfigPath, err)
+		}
+
+		result.Channel = channel
+	}
+
+	if value, has := userConfig.Get(CheckIntervalConfigPath); has {
+		hours, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+		if err != nil || hours < 0 {
+			return result, fmt.Errorf("invalid value '%v' for '%s', expected a number of hours", value, CheckIntervalConfigPath)
+		}
+
+		result.CheckInterval = time.Duration(hours * float64(time.Hour))
+	}
+
+	return result, nil
+}
+
+// Manager checks for new versions of azd and upgrades azd.
+type Manager struct {
+	httpClient httputil.HttpClient
+}
+
+// NewManager creates a Manager downloading the release information with httpClient.
+func NewManager(httpClient httputil.HttpClient) *Manager {
+	return &Manager{
+		httpClient: httpClient,
+	}
+}
+
+type updateCacheFile struct {
+	// The release channel of the cached version. Empty for caches written before channels were supported.
+	Channel Channel `json:"channel,omitempty"`
+	// The semver of the latest version of the CLI
+	Version string `json:"version"`
+	// A time at which this cached value expires, stored as an RFC3339 timestamp
+	ExpiresOn string `json:"expiresOn"`
+}
+
+// LatestVersion returns the latest version published on the release channel. When useCache is true, the version is
+// cached for the configured check interval, so the release channel is queried at most once per interval.
+func (m *Manager) LatestVersion(ctx context.Context, cfg Config, useCache bool) (semver.Version, error) {
+	cacheFilePath, err := cacheFilePath()
+	if err != nil {
+		return semver.Version{}, err
+	}
+
+	if useCache {
+		if cached, ok := readCache(cacheFilePath, cfg.Channel); ok {
+			return cached, nil
+		}
+	}
+
+	log.Printf("fetching latest version information for channel '%s'", cfg.Channel)
+	body, err := m.get(ctx, channels[cfg.Channel].versionUrl)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("fetching latest version: %w", err)
+	}
+
+	versionText := strings.TrimSpace(string(body))
+	version, err := semver.Parse(versionText)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("parsing latest version '%s': %w", versionText, err)
+	}
+
+	if cfg.CheckInterval > 0 {
+		writeCache(cacheFilePath, updateCacheFile{
+			Channel:   cfg.Channel,
+			Version:   versionText,
+			ExpiresOn: time.Now().UTC().Add(cfg.CheckInterval).Format(time.RFC3339),
+		})
+	}
+
+	return version, nil
+}
+
+// IsNewer returns true when latest is newer than the running version of azd. Dev builds are never considered out of
+// date.
+func IsNewer(latest semver.Version) bool {
+	if internal.IsDevVersion() {
+		return false
+	}
+
+	return latest.GT(internal.VersionInfo().Version)
+}
+
+func (m *Manager) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", internal.MakeUserAgentString(""))
+
+	res, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s failed with status %d", url, res.StatusCode)
+	}
+
+	return body, nil
+}
+
+func cacheFilePath() (string, error) {
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, updateCheckCacheFileName), nil
+}
+
+// readCache returns the cached latest version of the channel, when the cache exists and has not expired.
+func readCache(path string, channel Channel) (semver.Version, bool) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("error reading update cache file: %v, ignoring cache", err)
+		}
+		return semver.Version{}, false
+	}
+
+	var cache updateCacheFile
+	if err := json.Unmarshal(contents, &cache); err != nil {
+		log.Printf("could not unmarshal cache file: %v, ignoring cache", err)
+		return semver.Version{}, false
+	}
+
+	cachedChannel := cache.Channel
+	if cachedChannel == "" {
+		cachedChannel = ChannelStable
+	}
+
+	if cachedChannel != channel {
+		log.Printf("ignoring cached latest version of channel '%s'", cachedChannel)
+		return semver.Version{}, false
+	}
+
+	version, err := semver.Parse(cache.Version)
+	if err != nil {
+		log.Printf("failed to parse cached version '%s' as a semver: %v, ignoring cached value", cache.Version, err)
+		return semver.Version{}, false
+	}
+
+	expiresOn, err := time.Parse(time.RFC3339, cache.ExpiresOn)
+	if err != nil {
+		log.Printf("failed to parse cached version expiration time '%s': %v, ignoring cached value", cache.ExpiresOn, err)
+		return semver.Version{}, false
+	}
+
+	if !time.Now().UTC().Before(expiresOn) {
+		log.Printf("ignoring cached latest version, it is out of date")
+		return semver.Version{}, false
+	}
+
+	log.Printf("using cached latest version: %s (expires on: %s)", cache.Version, cache.ExpiresOn)
+	return version, true
+}
+
+func writeCache(path string, cache updateCacheFile) {
+	// The marshal call can not fail, so we ignore the error.
+	contents, _ := json.Marshal(cache)
+
+	if err := os.WriteFile(path, contents, osutil.PermissionFile); err != nil {
+		log.Printf("failed to write update cache file: %v", err)
+		return
+	}
+
+	log.Printf("updated cache file to version %s (expires on: %s)", cache.Version, cache.ExpiresOn)
+}
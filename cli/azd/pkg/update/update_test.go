@@ -0,0 +1,192 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadConfig(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		cfg, err := LoadConfig(config.NewConfig(nil))
+		require.NoError(t, err)
+		require.Equal(t, Config{Channel: ChannelStable, CheckInterval: DefaultCheckInterval}, cfg)
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		userConfig := config.NewConfig(nil)
+		require.NoError(t, userConfig.Set(ChannelConfigPath, "Daily"))
+		require.NoError(t, userConfig.Set(CheckIntervalConfigPath, "0.5"))
+
+		cfg, err := LoadConfig(userConfig)
+		require.NoError(t, err)
+		require.Equal(t, Config{Channel: ChannelDaily, CheckInterval: 30 * time.Minute}, cfg)
+	})
+
+	t.Run("InvalidChannel", func(t *testing.T) {
+		userConfig := config.NewConfig(nil)
+		require.NoError(t, userConfig.Set(ChannelConfigPath, "nightly"))
+
+		_, err := LoadConfig(userConfig)
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidInterval", func(t *testing.T) {
+		userConfig := config.NewConfig(nil)
+		require.NoError(t, userConfig.Set(CheckIntervalConfigPath, "-1"))
+
+		_, err := LoadConfig(userConfig)
+		require.Error(t, err)
+	})
+}
+
+func Test_Cache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), updateCheckCacheFileName)
+
+	_, ok := readCache(path, ChannelStable)
+	require.False(t, ok)
+
+	writeCache(path, updateCacheFile{
+		Channel:   ChannelBeta,
+		Version:   "1.2.0-beta.1",
+		ExpiresOn: time.Now().UTC().Add(time.Hour).Format(time.RFC3339),
+	})
+
+	version, ok := readCache(path, ChannelBeta)
+	require.True(t, ok)
+	require.Equal(t, semver.MustParse("1.2.0-beta.1"), version)
+
+	// The cached version of another channel is ignored
+	_, ok = readCache(path, ChannelStable)
+	require.False(t, ok)
+
+	writeCache(path, updateCacheFile{
+		Channel:   ChannelBeta,
+		Version:   "1.2.0-beta.1",
+		ExpiresOn: time.Now().UTC().Add(-time.Hour).Format(time.RFC3339),
+	})
+
+	_, ok = readCache(path, ChannelBeta)
+	require.False(t, ok)
+}
+
+func Test_VerifySignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	artifact := []byte("release")
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, artifact)) + "\n")
+
+	require.NoError(t, verifySignature(publicKey, artifact, signature))
+	require.Error(t, verifySignature(publicKey, []byte("tampered"), signature))
+	require.Error(t, verifySignature(publicKey, artifact, []byte("not a signature")))
+}
+
+func Test_SigningKey(t *testing.T) {
+	original := SigningPublicKey
+	t.Cleanup(func() { SigningPublicKey = original })
+
+	SigningPublicKey = ""
+	_, err := signingKey()
+	require.ErrorIs(t, err, ErrUpgradeNotSupported)
+
+	SigningPublicKey = base64.StdEncoding.EncodeToString([]byte("short"))
+	_, err = signingKey()
+	require.ErrorIs(t, err, ErrUpgradeNotSupported)
+}
+
+func Test_ExtractBinary(t *testing.T) {
+	var zipArchive bytes.Buffer
+	zipWriter := zip.NewWriter(&zipArchive)
+	file, err := zipWriter.Create("azd-windows-amd64.exe")
+	require.NoError(t, err)
+	_, err = file.Write([]byte("windows binary"))
+	require.NoError(t, err)
+	require.NoError(t, zipWriter.Close())
+
+	var tarArchive bytes.Buffer
+	gzWriter := gzip.NewWriter(&tarArchive)
+	tarWriter := tar.NewWriter(gzWriter)
+	contents := []byte("linux binary")
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+		Name:     "azd-linux-amd64",
+		Typeflag: tar.TypeReg,
+		Mode:     0755,
+		Size:     int64(len(contents)),
+	}))
+	_, err = tarWriter.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzWriter.Close())
+
+	var extracted bytes.Buffer
+	require.NoError(t, extractBinary(zipArchive.Bytes(), binaryName("windows", "amd64"), &extracted))
+	require.Equal(t, "windows binary", extracted.String())
+
+	extracted.Reset()
+	require.NoError(t, extractBinary(tarArchive.Bytes(), binaryName("linux", "amd64"), &extracted))
+	require.Equal(t, "linux binary", extracted.String())
+
+	require.Error(t, extractBinary(tarArchive.Bytes(), binaryName("linux", "arm64"), &extracted))
+}
+
+func Test_ArtifactName(t *testing.T) {
+	require.Equal(t, "azd-linux-amd64.tar.gz", artifactName("linux", "amd64"))
+	require.Equal(t, "azd-darwin-arm64.zip", artifactName("darwin", "arm64"))
+	require.Equal(t, "azd-windows-amd64.zip", artifactName("windows", "amd64"))
+}
+
+func Test_ReplaceExecutable(t *testing.T) {
+	dir := t.TempDir()
+	exePath := filepath.Join(dir, "azd")
+	newPath := filepath.Join(dir, "azd-new")
+	require.NoError(t, os.WriteFile(exePath, []byte("old"), 0600))
+	require.NoError(t, os.WriteFile(newPath, []byte("new"), 0600))
+
+	require.NoError(t, replaceExecutable(context.Background(), exePath, newPath))
+
+	contents, err := os.ReadFile(exePath)
+	require.NoError(t, err)
+	require.Equal(t, "new", string(contents))
+	require.NoFileExists(t, newPath)
+}
+
+func Test_InstallMethod(t *testing.T) {
+	tests := []struct {
+		exePath string
+		goos    string
+		want    InstallMethod
+	}{
+		{"/opt/homebrew/Cellar/azd/1.0.0/bin/azd", "darwin", InstallMethodHomebrew},
+		{"/home/linuxbrew/.linuxbrew/Cellar/azd/1.0.0/bin/azd", "linux", InstallMethodHomebrew},
+		{`C:\Program Files\Azure Dev CLI\azd.exe`, "windows", InstallMethodMsi},
+		{`C:\Users\me\AppData\Local\Programs\Azure Dev CLI\azd.exe`, "windows", InstallMethodMsi},
+		{"/opt/microsoft/azd/azd-linux-amd64", "linux", InstallMethodLinuxPackage},
+		{"/usr/local/bin/azd", "linux", InstallMethodScript},
+		{`C:\Users\me\.azd\bin\azd.exe`, "windows", InstallMethodScript},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.exePath, func(t *testing.T) {
+			require.Equal(t, tt.want, installMethod(tt.exePath, tt.goos))
+		})
+	}
+
+	require.NoError(t, checkInstallMethod(InstallMethodScript))
+	err := checkInstallMethod(InstallMethodHomebrew)
+	require.ErrorIs(t, err, ErrManagedInstall)
+	require.ErrorContains(t, err, "brew upgrade azd")
+}
@@ -0,0 +1,252 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// SigningPublicKey is the base64 encoded ed25519 public key verifying the signatures of the release artifacts.
+//
+// Official builds set this value using `-ldflags`, for example:
+//
+//	-ldflags="-X 'github.com/azure/azure-dev/cli/azd/pkg/update.SigningPublicKey=<base64 public key>'"
+//
+// Builds without a signing key, like dev builds, can't upgrade themselves.
+var SigningPublicKey = ""
+
+// ErrUpgradeNotSupported is returned when the running build of azd can't verify the release artifacts.
+var ErrUpgradeNotSupported = errors.New(
+	"this build of azd can not verify the signature of releases, install azd following the instructions at " +
+		InstallInstructionsUrl,
+)
+
+// signatureSuffix is appended to the URL of a release artifact to get the URL of its detached signature.
+const signatureSuffix = ".sig"
+
+// Upgrade replaces the running azd binary with the latest release of the channel. The release artifact is verified
+// against its signature before being extracted. progress is called with a description of each step.
+func (m *Manager) Upgrade(ctx context.Context, cfg Config, progress func(string)) error {
+	publicKey, err := signingKey()
+	if err != nil {
+		return err
+	}
+
+	exePath, err := executablePath()
+	if err != nil {
+		return err
+	}
+
+	// The previous binary is left behind on Windows, where a running executable can't be deleted.
+	removePreviousExecutable(exePath)
+
+	artifactUrl := fmt.Sprintf("%s/%s", channels[cfg.Channel].artifactsUrl, artifactName(runtime.GOOS, runtime.GOARCH))
+
+	progress("Downloading release")
+	artifact, err := m.get(ctx, artifactUrl)
+	if err != nil {
+		return fmt.Errorf("downloading release: %w", err)
+	}
+
+	signature, err := m.get(ctx, artifactUrl+signatureSuffix)
+	if err != nil {
+		return fmt.Errorf("downloading release signature: %w", err)
+	}
+
+	progress("Verifying signature")
+	if err := verifySignature(publicKey, artifact, signature); err != nil {
+		return err
+	}
+
+	progress("Installing")
+	// The new binary is extracted next to the current one, so the final rename doesn't cross file systems.
+	newExe, err := os.CreateTemp(filepath.Dir(exePath), ".azd-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	defer os.Remove(newExe.Name())
+
+	if err := extractBinary(artifact, binaryName(runtime.GOOS, runtime.GOARCH), newExe); err != nil {
+		newExe.Close()
+		return fmt.Errorf("extracting release: %w", err)
+	}
+
+	if err := newExe.Close(); err != nil {
+		return fmt.Errorf("writing new binary: %w", err)
+	}
+
+	if err := os.Chmod(newExe.Name(), osutil.PermissionExecutableFile); err != nil {
+		return fmt.Errorf("setting permissions of new binary: %w", err)
+	}
+
+	return replaceExecutable(ctx, exePath, newExe.Name())
+}
+
+// artifactName returns the name of the release artifact for the operating system and architecture.
+func artifactName(goos string, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" || goos == "darwin" {
+		ext = ".zip"
+	}
+
+	return fmt.Sprintf("azd-%s-%s%s", goos, goarch, ext)
+}
+
+// binaryName returns the name of the azd binary within the release artifact.
+func binaryName(goos string, goarch string) string {
+	name := fmt.Sprintf("azd-%s-%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+
+	return name
+}
+
+func signingKey() (ed25519.PublicKey, error) {
+	if SigningPublicKey == "" {
+		return nil, ErrUpgradeNotSupported
+	}
+
+	key, err := base64.StdEncoding.DecodeString(SigningPublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid release signing key: %w", ErrUpgradeNotSupported)
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// verifySignature verifies the base64 encoded detached ed25519 signature of the artifact.
+func verifySignature(publicKey ed25519.PublicKey, artifact []byte, signature []byte) error {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("decoding release signature: %w", err)
+	}
+
+	if !ed25519.Verify(publicKey, artifact, decoded) {
+		return errors.New("the signature of the release is not valid, the download may have been tampered with")
+	}
+
+	return nil
+}
+
+// extractBinary writes the file named name of the zip or tar.gz archive to dst.
+func extractBinary(archive []byte, name string, dst io.Writer) error {
+	if bytes.HasPrefix(archive, []byte("PK")) {
+		zipReader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return err
+		}
+
+		for _, file := range zipReader.File {
+			if file.FileInfo().IsDir() || filepath.Base(file.Name) != name {
+				continue
+			}
+
+			fileReader, err := file.Open()
+			if err != nil {
+				return err
+			}
+			defer fileReader.Close()
+
+			/* #nosec G110 - decompression bomb false positive, the archive is signed */
+			_, err = io.Copy(dst, fileReader)
+			return err
+		}
+
+		return fmt.Errorf("%s was not found within the zip file", name)
+	}
+
+	gzReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		fileHeader, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s was not found within the tar file", name)
+		}
+		if err != nil {
+			return err
+		}
+
+		// cspell: disable-next-line `Typeflag` is coming from *tar.Header
+		if fileHeader.Typeflag == tar.TypeReg && filepath.Base(fileHeader.Name) == name {
+			/* #nosec G110 - decompression bomb false positive, the archive is signed */
+			_, err = io.Copy(dst, tarReader)
+			return err
+		}
+	}
+}
+
+// executablePath returns the path of the running azd binary, following symbolic links created by package managers.
+func executablePath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding the azd executable: %w", err)
+	}
+
+	exePath, err = filepath.EvalSymlinks(exePath)
+	if err != nil {
+		return "", fmt.Errorf("resolving the azd executable: %w", err)
+	}
+
+	return exePath, nil
+}
+
+func previousExecutablePath(exePath string) string {
+	return exePath + ".old"
+}
+
+func removePreviousExecutable(exePath string) {
+	if err := os.Remove(previousExecutablePath(exePath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("failed to remove previous azd executable: %v", err)
+	}
+}
+
+// replaceExecutable atomically replaces the executable at exePath with newPath. Windows doesn't allow replacing a
+// running executable but allows renaming it, so the running executable is first moved aside, and restored when the
+// new executable can't be moved in place.
+func replaceExecutable(ctx context.Context, exePath string, newPath string) error {
+	if runtime.GOOS != "windows" {
+		if err := osutil.Rename(ctx, newPath, exePath); err != nil {
+			return fmt.Errorf("replacing %s: %w", exePath, err)
+		}
+
+		return nil
+	}
+
+	previousPath := previousExecutablePath(exePath)
+	if err := osutil.Rename(ctx, exePath, previousPath); err != nil {
+		return fmt.Errorf("moving %s: %w", exePath, err)
+	}
+
+	if err := osutil.Rename(ctx, newPath, exePath); err != nil {
+		if restoreErr := osutil.Rename(ctx, previousPath, exePath); restoreErr != nil {
+			log.Printf("failed to restore %s: %v", exePath, restoreErr)
+		}
+
+		return fmt.Errorf("replacing %s: %w", exePath, err)
+	}
+
+	return nil
+}
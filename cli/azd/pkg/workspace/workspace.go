@@ -0,0 +1,235 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package workspace provides a registry of the azd projects of the user, stored in the user configuration.
+//
+// Registered projects can be listed and switched to from anywhere on disk. The project containing the current working
+// directory always wins, the active project of the workspace is used when azd runs outside of a project. The active
+// project is then named to the user, who confirms commands changing Azure resources.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	projectsConfigPath = "workspace.projects"
+	activeConfigPath   = "workspace.active"
+)
+
+var (
+	ErrProjectNotFound = errors.New("project not found in the workspace")
+)
+
+// Project is a project registered in the workspace.
+type Project struct {
+	// The name of the project, unique in the workspace.
+	Name string
+	// The absolute path of the directory containing the azure.yaml file of the project.
+	Path string
+	// Whether the project is the active project of the workspace.
+	IsActive bool
+}
+
+// Manager manages the projects registered in the workspace of the user.
+type Manager struct {
+	configManager config.UserConfigManager
+}
+
+// NewManager creates a workspace manager storing the registered projects in the user configuration.
+func NewManager(configManager config.UserConfigManager) *Manager {
+	return &Manager{
+		configManager: configManager,
+	}
+}
+
+// List returns the registered projects, sorted by name.
+func (m *Manager) List() ([]*Project, error) {
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	activeName := activeProjectName(userConfig)
+
+	var projects []*Project
+	if raw, has := userConfig.Get(projectsConfigPath); has {
+		registered, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid value for '%s' in user configuration", projectsConfigPath)
+		}
+
+		for name, value := range registered {
+			path, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid path for project '%s' in user configuration", name)
+			}
+
+			projects = append(projects, &Project{
+				Name:     name,
+				Path:     path,
+				IsActive: name == activeName,
+			})
+		}
+	}
+
+	slices.SortFunc(projects, func(a, b *Project) bool {
+		return a.Name < b.Name
+	})
+
+	return projects, nil
+}
+
+// Get returns the registered project with the specified name, or ErrProjectNotFound.
+func (m *Manager) Get(name string) (*Project, error) {
+	projects, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		if project.Name == name {
+			return project, nil
+		}
+	}
+
+	return nil, fmt.Errorf("'%s': %w", name, ErrProjectNotFound)
+}
+
+// Add registers the project located in the directory projectDir with the specified name. Registering a project again
+// with the same name updates its path.
+func (m *Manager) Add(name string, projectDir string) (*Project, error) {
+	if name == "" || strings.Contains(name, ".") {
+		return nil, fmt.Errorf("project name '%s' is invalid, it must not be empty or contain '.'", name)
+	}
+
+	path, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving project path: %w", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(path, azdcontext.ProjectFileName)); err != nil {
+		return nil, fmt.Errorf("'%s' is not an azd project, %s not found: %w", path, azdcontext.ProjectFileName, err)
+	}
+
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := userConfig.Set(projectConfigPath(name), path); err != nil {
+		return nil, fmt.Errorf("registering project '%s': %w", name, err)
+	}
+
+	if err := m.configManager.Save(userConfig); err != nil {
+		return nil, err
+	}
+
+	return &Project{
+		Name:     name,
+		Path:     path,
+		IsActive: activeProjectName(userConfig) == name,
+	}, nil
+}
+
+// Remove unregisters the project with the specified name. The project files are left untouched.
+func (m *Manager) Remove(name string) error {
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := userConfig.Unset(projectConfigPath(name)); err != nil {
+		return fmt.Errorf("removing project '%s': %w", name, err)
+	}
+
+	if activeProjectName(userConfig) == name {
+		if err := userConfig.Unset(activeConfigPath); err != nil {
+			return fmt.Errorf("removing active project: %w", err)
+		}
+	}
+
+	return m.configManager.Save(userConfig)
+}
+
+// Switch sets the project with the specified name as the active project of the workspace.
+func (m *Manager) Switch(name string) (*Project, error) {
+	project, err := m.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := userConfig.Set(activeConfigPath, name); err != nil {
+		return nil, fmt.Errorf("setting active project: %w", err)
+	}
+
+	if err := m.configManager.Save(userConfig); err != nil {
+		return nil, err
+	}
+
+	project.IsActive = true
+	return project, nil
+}
+
+// Active returns the active project of the workspace, or nil when no project is active.
+func (m *Manager) Active() (*Project, error) {
+	userConfig, err := m.configManager.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	name := activeProjectName(userConfig)
+	if name == "" {
+		return nil, nil
+	}
+
+	return m.Get(name)
+}
+
+// ResolveAzdContext returns the context of the project containing the current working directory. When the current
+// working directory is not within a project, the context of the active project of the workspace is returned along with
+// the active project, so callers can tell the user which project was chosen.
+func (m *Manager) ResolveAzdContext() (*azdcontext.AzdContext, *Project, error) {
+	azdCtx, err := azdcontext.NewAzdContext()
+	if !errors.Is(err, azdcontext.ErrNoProject) {
+		return azdCtx, nil, err
+	}
+
+	active, activeErr := m.Active()
+	if activeErr != nil || active == nil {
+		return nil, nil, err
+	}
+
+	return azdcontext.NewAzdContextWithDirectory(active.Path), active, nil
+}
+
+func projectConfigPath(name string) string {
+	return fmt.Sprintf("%s.%s", projectsConfigPath, name)
+}
+
+func activeProjectName(userConfig config.Config) string {
+	if value, has := userConfig.Get(activeConfigPath); has {
+		if name, ok := value.(string); ok {
+			return name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,131 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/test/ostest"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Manager(t *testing.T) {
+	manager := NewManager(newMemoryConfigManager())
+	api := newProjectDir(t)
+	web := newProjectDir(t)
+
+	projects, err := manager.List()
+	require.NoError(t, err)
+	require.Empty(t, projects)
+
+	_, err = manager.Add("web", web)
+	require.NoError(t, err)
+	_, err = manager.Add("api", api)
+	require.NoError(t, err)
+
+	projects, err = manager.List()
+	require.NoError(t, err)
+	require.Equal(t, []*Project{
+		{Name: "api", Path: api},
+		{Name: "web", Path: web},
+	}, projects)
+
+	active, err := manager.Active()
+	require.NoError(t, err)
+	require.Nil(t, active)
+
+	_, err = manager.Switch("web")
+	require.NoError(t, err)
+
+	active, err = manager.Active()
+	require.NoError(t, err)
+	require.Equal(t, &Project{Name: "web", Path: web, IsActive: true}, active)
+
+	_, err = manager.Switch("other")
+	require.True(t, errors.Is(err, ErrProjectNotFound))
+
+	// Removing the active project leaves the workspace without an active project
+	require.NoError(t, manager.Remove("web"))
+
+	active, err = manager.Active()
+	require.NoError(t, err)
+	require.Nil(t, active)
+
+	projects, err = manager.List()
+	require.NoError(t, err)
+	require.Equal(t, []*Project{{Name: "api", Path: api}}, projects)
+}
+
+func Test_Manager_Add_Invalid(t *testing.T) {
+	manager := NewManager(newMemoryConfigManager())
+
+	t.Run("NotAProject", func(t *testing.T) {
+		_, err := manager.Add("api", t.TempDir())
+		require.Error(t, err)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		_, err := manager.Add("my.api", newProjectDir(t))
+		require.Error(t, err)
+	})
+}
+
+func Test_Manager_ResolveAzdContext(t *testing.T) {
+	manager := NewManager(newMemoryConfigManager())
+	api := newProjectDir(t)
+	ostest.Chdir(t, t.TempDir())
+
+	// Without an active project, running outside of a project fails
+	_, active, err := manager.ResolveAzdContext()
+	require.True(t, errors.Is(err, azdcontext.ErrNoProject))
+	require.Nil(t, active)
+
+	_, err = manager.Add("api", api)
+	require.NoError(t, err)
+	_, err = manager.Switch("api")
+	require.NoError(t, err)
+
+	azdCtx, active, err := manager.ResolveAzdContext()
+	require.NoError(t, err)
+	require.Equal(t, api, azdCtx.ProjectDirectory())
+	require.Equal(t, "api", active.Name)
+
+	// The project containing the current directory wins over the active project
+	web := newProjectDir(t)
+	ostest.Chdir(t, web)
+
+	azdCtx, active, err = manager.ResolveAzdContext()
+	require.NoError(t, err)
+	require.Equal(t, web, azdCtx.ProjectDirectory())
+	require.Nil(t, active)
+}
+
+func newProjectDir(t *testing.T) string {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, azdcontext.ProjectFileName), []byte("name: test\n"), 0600)
+	require.NoError(t, err)
+
+	return dir
+}
+
+func newMemoryConfigManager() config.UserConfigManager {
+	return &memoryConfigManager{
+		config: config.NewConfig(nil),
+	}
+}
+
+type memoryConfigManager struct {
+	config config.Config
+}
+
+func (m *memoryConfigManager) Load() (config.Config, error) {
+	return m.config, nil
+}
+
+func (m *memoryConfigManager) Save(cfg config.Config) error {
+	m.config = cfg
+	return nil
+}
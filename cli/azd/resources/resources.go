@@ -1,9 +1,24 @@
 package resources
 
-import _ "embed"
+import "embed"
 
 //go:embed templates.json
 var TemplatesJson []byte
 
 //go:embed alpha_features.yaml
 var AlphaFeatures []byte
+
+// GitHubWorkflow is the GitHub Actions workflow written, or merged into the existing one, by `azd pipeline config`.
+//
+//go:embed pipeline/github/azure-dev.yml
+var GitHubWorkflow []byte
+
+// GitHubPrWorkflow is the GitHub Actions workflow validating pull requests, written by `azd pipeline config`.
+//
+//go:embed pipeline/github/azure-dev-pr.yml
+var GitHubPrWorkflow []byte
+
+// Locales contains the message catalogs used to localize user facing messages, one YAML file per locale.
+//
+//go:embed locales
+var Locales embed.FS
@@ -25,7 +25,7 @@ func AddAzResourceListMock(
 	result []*armresources.GenericResourceExpanded,
 ) {
 	c.When(func(request *http.Request) bool {
-		isMatch := strings.Contains(request.URL.Path, "/resources")
+		isMatch := strings.Contains(request.URL.Path, "/resources") && !IsResourceGraphQuery(request)
 		if matchResourceGroupName != nil {
 			isMatch = isMatch &&
 				strings.Contains(request.URL.Path, fmt.Sprintf("/resourceGroups/%s/resources", *matchResourceGroupName))
@@ -51,6 +51,31 @@ func AddAzResourceListMock(
 			Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
 		}, nil
 	})
+
+	// Tagged service resources are found with a Resource Graph query
+	c.When(IsResourceGraphQuery).RespondFn(func(request *http.Request) (*http.Response, error) {
+		rows := []map[string]any{}
+		for _, resource := range result {
+			if resource.Tags["azd-service-name"] == nil {
+				continue
+			}
+
+			rows = append(rows, map[string]any{
+				"id":       resource.ID,
+				"name":     resource.Name,
+				"type":     resource.Type,
+				"location": resource.Location,
+				"tags":     resource.Tags,
+			})
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{"data": rows})
+	})
+}
+
+func IsResourceGraphQuery(request *http.Request) bool {
+	return request.Method == http.MethodPost &&
+		strings.Contains(request.URL.Path, "/providers/Microsoft.ResourceGraph/resources")
 }
 
 func AddResourceGroupListMock(c *mockhttp.MockHttpClient, subscriptionId string, results []*armresources.ResourceGroup) {
@@ -114,9 +114,15 @@ func (c *MockConsole) Confirm(ctx context.Context, options input.ConsoleOptions)
 }
 
 // Writes a single answer prompt to the console for the user to complete
+// The response is validated with the validation function of the options when set
 func (c *MockConsole) Prompt(ctx context.Context, options input.ConsoleOptions) (string, error) {
 	c.log = append(c.log, options.Message)
 	value, err := c.respond("Prompt", options)
+	if err == nil && options.Validate != nil {
+		if err := options.Validate(value.(string)); err != nil {
+			return "", err
+		}
+	}
 	return value.(string), err
 }
 
@@ -127,6 +133,16 @@ func (c *MockConsole) Select(ctx context.Context, options input.ConsoleOptions)
 	return value.(int), err
 }
 
+// Writes a multiple choice selection to the console for the user to choose any number of values
+func (c *MockConsole) MultiSelect(ctx context.Context, options input.ConsoleOptions) ([]string, error) {
+	c.log = append(c.log, options.Message)
+	value, err := c.respond("MultiSelect", options)
+	if value == nil {
+		return nil, err
+	}
+	return value.([]string), err
+}
+
 // Writes messages to the underlying writer
 func (c *MockConsole) Flush() {
 }
@@ -185,6 +201,18 @@ func (c *MockConsole) WhenSelect(predicate WhenPredicate) *MockConsoleExpression
 	return &expr
 }
 
+// Registers a multiple choice selection of any number of values expression for mocking in unit tests
+func (c *MockConsole) WhenMultiSelect(predicate WhenPredicate) *MockConsoleExpression {
+	expr := MockConsoleExpression{
+		command:     "MultiSelect",
+		console:     c,
+		predicateFn: predicate,
+	}
+
+	c.expressions = append(c.expressions, &expr)
+	return &expr
+}
+
 // MockConsoleExpression is an expression with options response or error
 type MockConsoleExpression struct {
 	command     string
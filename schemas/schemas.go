@@ -0,0 +1,18 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package schemas contains the JSON schemas of the files read by azd, which azd validates the files against.
+package schemas
+
+import (
+	"embed"
+	"path"
+)
+
+//go:embed v1.0/azure.yaml.json v1.1/azure.yaml.json
+var azureYaml embed.FS
+
+// AzureYaml returns the JSON schema of azure.yaml for a schema version, like 1.1.
+func AzureYaml(version string) ([]byte, error) {
+	return azureYaml.ReadFile(path.Join("v"+version, "azure.yaml.json"))
+}